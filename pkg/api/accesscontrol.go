@@ -25,6 +25,7 @@ var (
 	ScopeProvisionersDatasources   = ac.Scope("provisioners", "datasources")
 	ScopeProvisionersNotifications = ac.Scope("provisioners", "notifications")
 	ScopeProvisionersAlertRules    = ac.Scope("provisioners", "alerting")
+	ScopeProvisionersAccessControl = ac.Scope("provisioners", "accesscontrol")
 )
 
 // declareFixedRoles declares to the AccessControl service fixed roles and their
@@ -75,7 +76,7 @@ func (hs *HTTPServer) declareFixedRoles() error {
 		Role: ac.RoleDTO{
 			Name:        "fixed:datasources:reader",
 			DisplayName: "Data source reader",
-			Description: "Read and query all data sources.",
+			Description: "Read and query all data sources, and use them in alert rules.",
 			Group:       "Data sources",
 			Permissions: []ac.Permission{
 				{
@@ -86,6 +87,10 @@ func (hs *HTTPServer) declareFixedRoles() error {
 					Action: datasources.ActionQuery,
 					Scope:  datasources.ScopeAll,
 				},
+				{
+					Action: datasources.ActionAlert,
+					Scope:  datasources.ScopeAll,
+				},
 			},
 		},
 		Grants: []string{string(org.RoleAdmin)},
@@ -95,7 +100,7 @@ func (hs *HTTPServer) declareFixedRoles() error {
 		Role: ac.RoleDTO{
 			Name:        "fixed:datasources.builtin:reader",
 			DisplayName: "Built in data source reader",
-			Description: "Read and query Grafana's built in test data sources.",
+			Description: "Read and query Grafana's built in test data sources, and use them in alert rules.",
 			Group:       "Data sources",
 			Permissions: []ac.Permission{
 				{
@@ -106,14 +111,25 @@ func (hs *HTTPServer) declareFixedRoles() error {
 					Action: datasources.ActionQuery,
 					Scope:  fmt.Sprintf("%s%s", datasources.ScopePrefix, grafanads.DatasourceUID),
 				},
+				{
+					Action: datasources.ActionAlert,
+					Scope:  fmt.Sprintf("%s%s", datasources.ScopePrefix, grafanads.DatasourceUID),
+				},
 			},
 			Hidden: true,
 		},
 		Grants: []string{string(org.RoleViewer)},
 	}
 
-	// when running oss or enterprise without a license all users should be able to query data sources
-	if !hs.License.FeatureEnabled("dspermissions.enforcement") {
+	if hs.Cfg.DataSourcePermissionsEnforced {
+		// with enforced permissions only admins can use all data sources, others are granted
+		// query, explore and alert permissions per data source
+		datasourcesReaderRole.Role.Permissions = append(datasourcesReaderRole.Role.Permissions, ac.Permission{
+			Action: datasources.ActionExplore,
+			Scope:  datasources.ScopeAll,
+		})
+	} else if !hs.License.FeatureEnabled("dspermissions.enforcement") {
+		// when running oss or enterprise without a license all users should be able to query data sources
 		datasourcesReaderRole.Grants = []string{string(org.RoleViewer)}
 	}
 
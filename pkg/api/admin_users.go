@@ -13,12 +13,12 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/audit"
 	"github.com/grafana/grafana/pkg/services/auth"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
-	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -63,8 +63,12 @@ func (hs *HTTPServer) AdminCreateUser(c *contextmodel.ReqContext) response.Respo
 		}
 	}
 
-	if len(cmd.Password) < 4 {
-		return response.Error(400, "Password is missing or too short", nil)
+	if len(cmd.Password) == 0 {
+		return response.Error(400, "Password is missing", nil)
+	}
+
+	if err := hs.userService.ValidatePassword(cmd.Password); err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "Invalid password", err)
 	}
 
 	usr, err := hs.userService.Create(c.Req.Context(), &cmd)
@@ -77,7 +81,7 @@ func (hs *HTTPServer) AdminCreateUser(c *contextmodel.ReqContext) response.Respo
 			return response.Error(http.StatusPreconditionFailed, fmt.Sprintf("User with email '%s' or username '%s' already exists", form.Email, form.Login), err)
 		}
 
-		return response.Error(http.StatusInternalServerError, "failed to create user", err)
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to create user", err)
 	}
 
 	metrics.MApiAdminUserCreate.Inc()
@@ -116,29 +120,16 @@ func (hs *HTTPServer) AdminUpdateUserPassword(c *contextmodel.ReqContext) respon
 		return response.Error(http.StatusBadRequest, "id is invalid", err)
 	}
 
-	if len(form.Password) < 4 {
-		return response.Error(400, "New password too short", nil)
-	}
-
-	userQuery := user.GetUserByIDQuery{ID: userID}
-
-	usr, err := hs.userService.GetByID(c.Req.Context(), &userQuery)
-	if err != nil {
-		return response.Error(500, "Could not read user from database", err)
-	}
-
-	passwordHashed, err := util.EncodePassword(form.Password, usr.Salt)
-	if err != nil {
-		return response.Error(500, "Could not encode password", err)
-	}
-
 	cmd := user.ChangeUserPasswordCommand{
 		UserID:      userID,
-		NewPassword: passwordHashed,
+		NewPassword: form.Password,
 	}
 
 	if err := hs.userService.ChangePassword(c.Req.Context(), &cmd); err != nil {
-		return response.Error(500, "Failed to update user password", err)
+		if errors.Is(err, user.ErrUserNotFound) {
+			return response.Error(http.StatusNotFound, "User not found", err)
+		}
+		return response.ErrOrFallback(500, "Failed to update user password", err)
 	}
 
 	return response.Success("User password updated")
@@ -176,6 +167,12 @@ func (hs *HTTPServer) AdminUpdateUserPermissions(c *contextmodel.ReqContext) res
 		return response.Error(500, "Failed to update user permissions", err)
 	}
 
+	hs.auditService.Log(c.Req.Context(), audit.Event{
+		Action:   audit.ActionUserGrafanaAdminUpdate,
+		Resource: audit.Resource{Kind: audit.ResourceUser, ID: strconv.FormatInt(userID, 10)},
+		Details:  map[string]any{"isGrafanaAdmin": form.IsGrafanaAdmin},
+	})
+
 	return response.Success("User permissions updated")
 }
 
@@ -352,6 +349,49 @@ func (hs *HTTPServer) AdminEnableUser(c *contextmodel.ReqContext) response.Respo
 	return response.Success("User enabled")
 }
 
+// swagger:route POST /admin/users/{user_id}/unlock admin_users adminUnlockUser
+//
+// Unlock user.
+//
+// Unlocks a user locked out after too many failed login attempts and resets their failed login attempts.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `users:write` and scope `global.users:1` (userIDScope).
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) AdminUnlockUser(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	usr, err := hs.userService.GetByID(c.Req.Context(), &user.GetUserByIDQuery{ID: userID})
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return response.Error(http.StatusNotFound, user.ErrUserNotFound.Error(), nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to unlock user", err)
+	}
+
+	if err := hs.loginAttemptService.Unlock(c.Req.Context(), usr.Login); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to unlock user", err)
+	}
+
+	hs.auditService.Log(c.Req.Context(), audit.Event{
+		Action:   audit.ActionUserUnlock,
+		Resource: audit.Resource{Kind: audit.ResourceUser, ID: strconv.FormatInt(userID, 10), Name: usr.Login},
+	})
+
+	return response.Success("User unlocked")
+}
+
 // swagger:route POST /admin/users/{user_id}/logout admin_users adminLogoutUser
 //
 // Logout user revokes all auth tokens (devices) for the user. User of issued auth tokens (devices) will no longer be logged in and will be required to authenticate again upon next activity.
@@ -454,6 +494,13 @@ type AdminEnableUserParams struct {
 	UserID int64 `json:"user_id"`
 }
 
+// swagger:parameters adminUnlockUser
+type AdminUnlockUserParams struct {
+	// in:path
+	// required:true
+	UserID int64 `json:"user_id"`
+}
+
 // swagger:parameters adminDisableUser
 type AdminDisableUserParams struct {
 	// in:path
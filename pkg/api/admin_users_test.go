@@ -13,10 +13,12 @@ import (
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/db/dbtest"
+	"github.com/grafana/grafana/pkg/services/audit/audittest"
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/auth/authtest"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/login/logintest"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/services/user/usertest"
@@ -229,6 +231,31 @@ func TestAdminAPIEndpoint(t *testing.T) {
 			assert.Equal(t, "user already exists", respJSON.Get("error").MustString())
 		})
 	})
+
+	t.Run("When a server admin attempts to create a user with a password violating the password policy", func(t *testing.T) {
+		createCmd := dtos.AdminCreateUserForm{
+			Login:    testLogin,
+			Password: "pwd",
+		}
+		usrSvc := &usertest.FakeUserService{ExpectedPasswordError: user.ErrPasswordBanned.Errorf("banned")}
+		adminCreateUserScenario(t, "Should return a bad request error", "/api/admin/users", "/api/admin/users", createCmd, usrSvc, func(sc *scenarioContext) {
+			sc.fakeReqWithParams("POST", sc.url, map[string]string{}).exec()
+			assert.Equal(t, 400, sc.resp.Code)
+
+			respJSON, err := simplejson.NewJson(sc.resp.Body.Bytes())
+			require.NoError(t, err)
+			assert.Equal(t, "Password is too common, choose a different one", respJSON.Get("message").MustString())
+		})
+	})
+
+	t.Run("When a server admin unlocks a user", func(t *testing.T) {
+		adminUnlockUserScenario(t, "Should unlock the user", "/api/admin/users/42/unlock",
+			"/api/admin/users/:id/unlock", func(sc *scenarioContext, loginAttempts *loginattempttest.MockLoginAttemptService) {
+				sc.fakeReqWithParams("POST", sc.url, map[string]string{}).exec()
+				assert.Equal(t, 200, sc.resp.Code)
+				assert.True(t, loginAttempts.UnlockCalled)
+			})
+	})
 }
 
 func putAdminScenario(t *testing.T, desc string, url string, routePattern string, role org.RoleType,
@@ -239,6 +266,7 @@ func putAdminScenario(t *testing.T, desc string, url string, routePattern string
 			SQLStore:        sqlStore,
 			authInfoService: &logintest.AuthInfoServiceFake{},
 			userService:     userSvc,
+			auditService:    audittest.NewFakeService(),
 		}
 
 		sc := setupScenarioContext(t, url)
@@ -264,6 +292,7 @@ func adminLogoutUserScenario(t *testing.T, desc string, url string, routePattern
 		hs := HTTPServer{
 			AuthTokenService: authtest.NewFakeUserAuthTokenService(),
 			userService:      userService,
+			auditService:     audittest.NewFakeService(),
 		}
 
 		sc := setupScenarioContext(t, url)
@@ -372,6 +401,30 @@ func adminDisableUserScenario(t *testing.T, desc string, action string, url stri
 	})
 }
 
+func adminUnlockUserScenario(t *testing.T, desc string, url string, routePattern string, fn func(*scenarioContext, *loginattempttest.MockLoginAttemptService)) {
+	t.Run(fmt.Sprintf("%s %s", desc, url), func(t *testing.T) {
+		loginAttempts := &loginattempttest.MockLoginAttemptService{}
+		hs := HTTPServer{
+			SQLStore:            dbtest.NewFakeDB(),
+			userService:         &usertest.FakeUserService{ExpectedUser: &user.User{ID: 42, Login: "locked", Email: "locked@example.com"}},
+			loginAttemptService: loginAttempts,
+			auditService:        audittest.NewFakeService(),
+		}
+
+		sc := setupScenarioContext(t, url)
+		sc.sqlStore = hs.SQLStore
+		sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
+			sc.context = c
+			sc.context.UserID = testUserID
+			return hs.AdminUnlockUser(c)
+		})
+
+		sc.m.Post(routePattern, sc.defaultHandler)
+
+		fn(sc, loginAttempts)
+	})
+}
+
 func adminDeleteUserScenario(t *testing.T, desc string, url string, routePattern string, fn scenarioFunc) {
 	hs := HTTPServer{
 		SQLStore:    dbtest.NewFakeDB(),
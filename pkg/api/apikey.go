@@ -10,6 +10,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/components/apikeygen"
 	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/audit"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -90,6 +91,11 @@ func (hs *HTTPServer) DeleteAPIKey(c *contextmodel.ReqContext) response.Response
 		return response.Error(status, "Failed to delete API key", err)
 	}
 
+	hs.auditService.Log(c.Req.Context(), audit.Event{
+		Action:   audit.ActionAPIKeyDelete,
+		Resource: audit.Resource{Kind: audit.ResourceAPIKey, ID: strconv.FormatInt(id, 10)},
+	})
+
 	return response.Success("API key deleted")
 }
 
@@ -146,6 +152,12 @@ func (hs *HTTPServer) AddAPIKey(c *contextmodel.ReqContext) response.Response {
 		return response.Error(500, "Failed to add API Key", err)
 	}
 
+	hs.auditService.Log(c.Req.Context(), audit.Event{
+		Action:   audit.ActionAPIKeyCreate,
+		Resource: audit.Resource{Kind: audit.ResourceAPIKey, ID: strconv.FormatInt(key.ID, 10), Name: key.Name},
+		Details:  map[string]any{"role": cmd.Role, "secondsToLive": cmd.SecondsToLive},
+	})
+
 	result := &dtos.NewApiKeyResult{
 		ID:   key.ID,
 		Name: key.Name,
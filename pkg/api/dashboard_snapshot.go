@@ -436,6 +436,7 @@ func (hs *HTTPServer) SearchDashboardSnapshots(c *contextmodel.ReqContext) respo
 			UserID:      snapshot.UserID,
 			External:    snapshot.External,
 			ExternalURL: snapshot.ExternalURL,
+			ScheduleUID: snapshot.ScheduleUID,
 			Expires:     snapshot.Expires,
 			Created:     snapshot.Created,
 			Updated:     snapshot.Updated,
@@ -445,6 +446,52 @@ func (hs *HTTPServer) SearchDashboardSnapshots(c *contextmodel.ReqContext) respo
 	return response.JSON(http.StatusOK, dto)
 }
 
+// swagger:route POST /dashboards/uid/{uid}/snapshots snapshots takeDashboardSnapshot
+//
+// Create a snapshot of a saved dashboard on the server.
+//
+// The queries of the dashboard are run with the permissions of the signed in user and their results are stored in the snapshot.
+//
+// Responses:
+// 200: createDashboardSnapshotResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) TakeDashboardSnapshot(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	cmd := dashboardsnapshots.TakeDashboardSnapshotCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.DashboardUID = web.Params(c.Req)[":uid"]
+	cmd.OrgID = c.OrgID
+	cmd.SignedInUser = c.SignedInUser
+
+	if resp := hs.checkSnapshotDashboardAccess(c, cmd.DashboardUID, false); resp != nil {
+		return resp
+	}
+
+	snapshot, err := hs.dashboardsnapshotsService.TakeDashboardSnapshot(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create snapshot", err)
+	}
+
+	metrics.MApiDashboardSnapshotCreate.Inc()
+
+	return response.JSON(http.StatusOK, util.DynMap{
+		"key":       snapshot.Key,
+		"deleteKey": snapshot.DeleteKey,
+		"url":       setting.ToAbsUrl("dashboard/snapshot/" + snapshot.Key),
+		"deleteUrl": setting.ToAbsUrl("api/snapshots-delete/" + snapshot.DeleteKey),
+		"id":        snapshot.ID,
+	})
+}
+
 // swagger:parameters createDashboardSnapshot
 type CreateSnapshotParams struct {
 	// in:body
@@ -452,6 +499,16 @@ type CreateSnapshotParams struct {
 	Body dashboardsnapshots.CreateDashboardSnapshotCommand `json:"body"`
 }
 
+// swagger:parameters takeDashboardSnapshot
+type TakeDashboardSnapshotParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	// required:true
+	Body dashboardsnapshots.TakeDashboardSnapshotCommand `json:"body"`
+}
+
 // swagger:parameters searchDashboardSnapshots
 type GetSnapshotsParams struct {
 	// Search Query
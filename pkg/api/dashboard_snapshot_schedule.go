@@ -0,0 +1,318 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /dashboard/snapshot-schedules snapshots searchSnapshotSchedules
+//
+// List the snapshot schedules of the dashboards the user can view.
+//
+// Responses:
+// 200: searchSnapshotSchedulesResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) SearchSnapshotSchedules(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	schedules, err := hs.dashboardsnapshotsService.SearchSnapshotSchedules(c.Req.Context(), &dashboardsnapshots.GetSnapshotSchedulesQuery{
+		OrgID:        c.OrgID,
+		DashboardUID: c.Query("dashboardUid"),
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get snapshot schedules", err)
+	}
+
+	filtered := make([]*dashboardsnapshots.DashboardSnapshotSchedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		g, err := guardian.NewByUID(c.Req.Context(), schedule.DashboardUID, c.OrgID, c.SignedInUser)
+		if err != nil {
+			continue
+		}
+		if canView, err := g.CanView(); err == nil && canView {
+			filtered = append(filtered, schedule)
+		}
+	}
+
+	return response.JSON(http.StatusOK, filtered)
+}
+
+// swagger:route POST /dashboard/snapshot-schedules snapshots createSnapshotSchedule
+//
+// Create a schedule that takes snapshots of a dashboard.
+//
+// The snapshots are taken with the permissions of the user who creates the schedule.
+//
+// Responses:
+// 200: snapshotScheduleResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) CreateSnapshotSchedule(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	cmd := dashboardsnapshots.CreateSnapshotScheduleCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.OrgID
+	cmd.UserID = c.UserID
+
+	if resp := hs.checkSnapshotDashboardAccess(c, cmd.DashboardUID, true); resp != nil {
+		return resp
+	}
+
+	schedule, err := hs.dashboardsnapshotsService.CreateSnapshotSchedule(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create snapshot schedule", err)
+	}
+
+	return response.JSON(http.StatusOK, schedule)
+}
+
+// swagger:route GET /dashboard/snapshot-schedules/{uid} snapshots getSnapshotSchedule
+//
+// Get a snapshot schedule.
+//
+// Responses:
+// 200: snapshotScheduleResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetSnapshotSchedule(c *contextmodel.ReqContext) response.Response {
+	schedule, resp := hs.getSnapshotSchedule(c, false)
+	if resp != nil {
+		return resp
+	}
+
+	return response.JSON(http.StatusOK, schedule)
+}
+
+// swagger:route PUT /dashboard/snapshot-schedules/{uid} snapshots updateSnapshotSchedule
+//
+// Update a snapshot schedule.
+//
+// The snapshots are taken with the permissions of the user who updates the schedule.
+//
+// Responses:
+// 200: snapshotScheduleResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) UpdateSnapshotSchedule(c *contextmodel.ReqContext) response.Response {
+	existing, resp := hs.getSnapshotSchedule(c, true)
+	if resp != nil {
+		return resp
+	}
+
+	cmd := dashboardsnapshots.UpdateSnapshotScheduleCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.UID = existing.UID
+	cmd.OrgID = c.OrgID
+	cmd.UserID = c.UserID
+
+	schedule, err := hs.dashboardsnapshotsService.UpdateSnapshotSchedule(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update snapshot schedule", err)
+	}
+
+	return response.JSON(http.StatusOK, schedule)
+}
+
+// swagger:route DELETE /dashboard/snapshot-schedules/{uid} snapshots deleteSnapshotSchedule
+//
+// Delete a snapshot schedule.
+//
+// The snapshots taken by the schedule are kept until they expire.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) DeleteSnapshotSchedule(c *contextmodel.ReqContext) response.Response {
+	schedule, resp := hs.getSnapshotSchedule(c, true)
+	if resp != nil {
+		return resp
+	}
+
+	cmd := &dashboardsnapshots.DeleteSnapshotScheduleCommand{UID: schedule.UID, OrgID: c.OrgID}
+	if err := hs.dashboardsnapshotsService.DeleteSnapshotSchedule(c.Req.Context(), cmd); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete snapshot schedule", err)
+	}
+
+	return response.Success("Snapshot schedule deleted")
+}
+
+// swagger:route POST /dashboard/snapshot-schedules/{uid}/run snapshots runSnapshotSchedule
+//
+// Take a snapshot for the schedule now.
+//
+// The snapshot is taken with the permissions of the user who runs the schedule.
+//
+// Responses:
+// 200: createDashboardSnapshotResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) RunSnapshotSchedule(c *contextmodel.ReqContext) response.Response {
+	schedule, resp := hs.getSnapshotSchedule(c, true)
+	if resp != nil {
+		return resp
+	}
+
+	snapshot, err := hs.dashboardsnapshotsService.RunSnapshotSchedule(c.Req.Context(), schedule, c.SignedInUser)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create snapshot", err)
+	}
+
+	return response.JSON(http.StatusOK, util.DynMap{
+		"key":       snapshot.Key,
+		"deleteKey": snapshot.DeleteKey,
+		"url":       setting.ToAbsUrl("dashboard/snapshot/" + snapshot.Key),
+		"deleteUrl": setting.ToAbsUrl("api/snapshots-delete/" + snapshot.DeleteKey),
+		"id":        snapshot.ID,
+	})
+}
+
+// swagger:route GET /dashboard/snapshot-schedules/{uid}/snapshots snapshots getSnapshotScheduleSnapshots
+//
+// List the snapshots taken by a schedule.
+//
+// Responses:
+// 200: searchDashboardSnapshotsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetSnapshotScheduleSnapshots(c *contextmodel.ReqContext) response.Response {
+	schedule, resp := hs.getSnapshotSchedule(c, false)
+	if resp != nil {
+		return resp
+	}
+
+	limit := c.QueryInt("limit")
+	if limit == 0 {
+		limit = 1000
+	}
+
+	snapshots, err := hs.dashboardsnapshotsService.SearchDashboardSnapshots(c.Req.Context(), &dashboardsnapshots.GetDashboardSnapshotsQuery{
+		Limit:        limit,
+		OrgID:        c.OrgID,
+		ScheduleUID:  schedule.UID,
+		SignedInUser: c.SignedInUser,
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Search failed", err)
+	}
+
+	return response.JSON(http.StatusOK, snapshots)
+}
+
+// getSnapshotSchedule returns the schedule of the request if the user can view, or edit, its dashboard
+func (hs *HTTPServer) getSnapshotSchedule(c *contextmodel.ReqContext, edit bool) (*dashboardsnapshots.DashboardSnapshotSchedule, response.Response) {
+	if !hs.Cfg.SnapshotEnabled {
+		return nil, response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	schedule, err := hs.dashboardsnapshotsService.GetSnapshotSchedule(c.Req.Context(), &dashboardsnapshots.GetSnapshotScheduleQuery{
+		UID:   web.Params(c.Req)[":uid"],
+		OrgID: c.OrgID,
+	})
+	if err != nil {
+		return nil, response.ErrOrFallback(http.StatusInternalServerError, "Failed to get snapshot schedule", err)
+	}
+
+	if resp := hs.checkSnapshotDashboardAccess(c, schedule.DashboardUID, edit); resp != nil {
+		return nil, resp
+	}
+	return schedule, nil
+}
+
+// checkSnapshotDashboardAccess checks that the user can view the dashboard to snapshot it or read its schedules,
+// and edit it to manage its schedules
+func (hs *HTTPServer) checkSnapshotDashboardAccess(c *contextmodel.ReqContext, dashboardUID string, edit bool) response.Response {
+	g, err := guardian.NewByUID(c.Req.Context(), dashboardUID, c.OrgID, c.SignedInUser)
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return response.Error(http.StatusNotFound, "Dashboard not found", err)
+		}
+		return response.Err(err)
+	}
+
+	allowed, err := g.CanView()
+	if edit && err == nil && allowed {
+		allowed, err = g.CanEdit()
+	}
+	if err != nil || !allowed {
+		return dashboardGuardianResponse(err)
+	}
+	return nil
+}
+
+// swagger:parameters createSnapshotSchedule
+type CreateSnapshotScheduleParams struct {
+	// in:body
+	// required:true
+	Body dashboardsnapshots.CreateSnapshotScheduleCommand `json:"body"`
+}
+
+// swagger:parameters updateSnapshotSchedule
+type UpdateSnapshotScheduleParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	// required:true
+	Body dashboardsnapshots.UpdateSnapshotScheduleCommand `json:"body"`
+}
+
+// swagger:parameters getSnapshotSchedule deleteSnapshotSchedule runSnapshotSchedule getSnapshotScheduleSnapshots
+type SnapshotScheduleParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:parameters searchSnapshotSchedules
+type SearchSnapshotSchedulesParams struct {
+	// Only return the schedules of this dashboard
+	// in:query
+	DashboardUID string `json:"dashboardUid"`
+}
+
+// swagger:response snapshotScheduleResponse
+type SnapshotScheduleResponse struct {
+	// in:body
+	Body *dashboardsnapshots.DashboardSnapshotSchedule `json:"body"`
+}
+
+// swagger:response searchSnapshotSchedulesResponse
+type SearchSnapshotSchedulesResponse struct {
+	// in:body
+	Body []*dashboardsnapshots.DashboardSnapshotSchedule `json:"body"`
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/audit"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/datasources/permissions"
@@ -186,6 +187,7 @@ func (hs *HTTPServer) DeleteDataSourceById(c *contextmodel.ReqContext) response.
 	}
 
 	hs.Live.HandleDatasourceDelete(c.OrgID, ds.UID)
+	hs.auditDataSource(c.Req.Context(), audit.ActionDatasourceDelete, ds)
 
 	return response.Success("Data source deleted")
 }
@@ -265,6 +267,7 @@ func (hs *HTTPServer) DeleteDataSourceByUID(c *contextmodel.ReqContext) response
 	}
 
 	hs.Live.HandleDatasourceDelete(c.OrgID, ds.UID)
+	hs.auditDataSource(c.Req.Context(), audit.ActionDatasourceDelete, ds)
 
 	return response.JSON(http.StatusOK, util.DynMap{
 		"message": "Data source deleted",
@@ -315,6 +318,7 @@ func (hs *HTTPServer) DeleteDataSourceByName(c *contextmodel.ReqContext) respons
 	}
 
 	hs.Live.HandleDatasourceDelete(c.OrgID, dataSource.UID)
+	hs.auditDataSource(c.Req.Context(), audit.ActionDatasourceDelete, dataSource)
 
 	return response.JSON(http.StatusOK, util.DynMap{
 		"message": "Data source deleted",
@@ -405,6 +409,8 @@ func (hs *HTTPServer) AddDataSource(c *contextmodel.ReqContext) response.Respons
 		hs.accesscontrolService.ClearUserPermissionCache(c.SignedInUser)
 	}
 
+	hs.auditDataSource(c.Req.Context(), audit.ActionDatasourceCreate, dataSource)
+
 	ds := hs.convertModelToDtos(c.Req.Context(), dataSource)
 	return response.JSON(http.StatusOK, util.DynMap{
 		"message":    "Datasource added",
@@ -537,6 +543,7 @@ func (hs *HTTPServer) updateDataSourceByID(c *contextmodel.ReqContext, ds *datas
 	datasourceDTO := hs.convertModelToDtos(c.Req.Context(), dataSource)
 
 	hs.Live.HandleDatasourceUpdate(c.OrgID, datasourceDTO.UID)
+	hs.auditDataSource(c.Req.Context(), audit.ActionDatasourceUpdate, dataSource)
 
 	return response.JSON(http.StatusOK, util.DynMap{
 		"message":    "Datasource updated",
@@ -546,6 +553,16 @@ func (hs *HTTPServer) updateDataSourceByID(c *contextmodel.ReqContext, ds *datas
 	})
 }
 
+// auditDataSource records a change of a data source in the audit log, the secure JSON data is left out
+func (hs *HTTPServer) auditDataSource(ctx context.Context, action string, ds *datasources.DataSource) {
+	hs.auditService.Log(ctx, audit.Event{
+		OrgID:    ds.OrgID,
+		Action:   action,
+		Resource: audit.Resource{Kind: audit.ResourceDatasource, ID: ds.UID, Name: ds.Name},
+		Details:  map[string]any{"type": ds.Type, "url": ds.URL},
+	})
+}
+
 func (hs *HTTPServer) getRawDataSourceById(ctx context.Context, id int64, orgID int64) (*datasources.DataSource, error) {
 	query := datasources.GetDataSourceQuery{
 		ID:    id,
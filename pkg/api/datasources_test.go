@@ -19,6 +19,7 @@ import (
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/audit/audittest"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/datasources/permissions"
@@ -115,6 +116,7 @@ func TestAddDataSource_URLWithoutProtocol(t *testing.T) {
 		Cfg:                  setting.NewCfg(),
 		AccessControl:        acimpl.ProvideAccessControl(setting.NewCfg()),
 		accesscontrolService: actest.FakeService{},
+		auditService:         audittest.NewFakeService(),
 	}
 
 	sc := setupScenarioContext(t, "/api/datasources")
@@ -229,6 +231,7 @@ func TestUpdateDataSource_URLWithoutProtocol(t *testing.T) {
 		Cfg:                  setting.NewCfg(),
 		AccessControl:        acimpl.ProvideAccessControl(setting.NewCfg()),
 		accesscontrolService: actest.FakeService{},
+		auditService:         audittest.NewFakeService(),
 	}
 
 	sc := setupScenarioContext(t, "/api/datasources/1234")
@@ -26,6 +26,10 @@ type LoginCommand struct {
 	Remember bool   `json:"remember"`
 }
 
+type TwoFactorCodeCommand struct {
+	Code string `json:"code" binding:"Required"`
+}
+
 type CurrentUser struct {
 	IsSignedIn                 bool               `json:"isSignedIn"`
 	Id                         int64              `json:"id"`
@@ -16,12 +16,15 @@ import (
 	"github.com/grafana/grafana/pkg/login"
 	"github.com/grafana/grafana/pkg/middleware/cookies"
 	"github.com/grafana/grafana/pkg/services/auth"
+	authJWT "github.com/grafana/grafana/pkg/services/auth/jwt"
 	"github.com/grafana/grafana/pkg/services/authn"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	loginservice "github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
 	pref "github.com/grafana/grafana/pkg/services/preference"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/twofactor"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
@@ -201,6 +204,9 @@ func (hs *HTTPServer) LoginPost(c *contextmodel.ReqContext) response.Response {
 			if errors.As(err, &tokenErr) {
 				return response.Error(tokenErr.StatusCode, tokenErr.ExternalErr, tokenErr.InternalErr)
 			}
+			if errors.Is(err, twofactor.ErrCodeRequired) || errors.Is(err, twofactor.ErrEnrollmentRequired) {
+				return twoFactorChallengeResponse(errors.Is(err, twofactor.ErrEnrollmentRequired))
+			}
 			return response.Err(err)
 		}
 
@@ -215,8 +221,13 @@ func (hs *HTTPServer) LoginPost(c *contextmodel.ReqContext) response.Response {
 	authModule := ""
 	var usr *user.User
 	var resp *response.NormalResponse
+	challenged := false
 
 	defer func() {
+		// the login hooks run when the second factor is verified
+		if challenged {
+			return
+		}
 		err := resp.Err()
 		if err == nil && resp.ErrMessage() != "" {
 			err = errors.New(resp.ErrMessage())
@@ -252,6 +263,11 @@ func (hs *HTTPServer) LoginPost(c *contextmodel.ReqContext) response.Response {
 			return resp
 		}
 
+		if errors.Is(err, loginattempt.ErrAccountLocked) || errors.Is(err, user.ErrPasswordExpired) {
+			resp = response.Err(err)
+			return resp
+		}
+
 		if errors.Is(err, login.ErrNoAuthProvider) {
 			resp = response.Error(http.StatusInternalServerError, "No authorization providers enabled", err)
 			return resp
@@ -270,6 +286,21 @@ func (hs *HTTPServer) LoginPost(c *contextmodel.ReqContext) response.Response {
 
 	usr = authQuery.User
 
+	// users authenticated by an external system, such as LDAP, rely on that system for additional factors
+	if authModule == "grafana" {
+		challenge, err := hs.twoFactorService.StartLogin(c.Req.Context(), usr.ID)
+		if err != nil {
+			resp = response.Error(http.StatusInternalServerError, "Error while trying to authenticate user", err)
+			return resp
+		}
+		if challenge != nil {
+			challenged = true
+			twofactor.WriteChallengeCookie(c.Resp, challenge.Token)
+			resp = twoFactorChallengeResponse(challenge.Enroll)
+			return resp
+		}
+	}
+
 	err = hs.loginUserWithUser(usr, c)
 	if err != nil {
 		var createTokenErr *auth.CreateTokenErr
@@ -289,6 +320,144 @@ func (hs *HTTPServer) LoginPost(c *contextmodel.ReqContext) response.Response {
 	return resp
 }
 
+// LoginTwoFactorPost completes a password login challenged for a two-factor authentication code
+func (hs *HTTPServer) LoginTwoFactorPost(c *contextmodel.ReqContext) response.Response {
+	if hs.Features.IsEnabled(featuremgmt.FlagAuthnService) {
+		identity, err := hs.authnService.Login(c.Req.Context(), authn.ClientTwoFactor, &authn.Request{HTTPRequest: c.Req, Resp: c.Resp})
+		if err != nil {
+			tokenErr := &auth.CreateTokenErr{}
+			if errors.As(err, &tokenErr) {
+				return response.Error(tokenErr.StatusCode, tokenErr.ExternalErr, tokenErr.InternalErr)
+			}
+			return response.Err(err)
+		}
+
+		metrics.MApiLoginPost.Inc()
+		return authn.HandleLoginResponse(c.Req, c.Resp, hs.Cfg, identity, hs.ValidateRedirectTo)
+	}
+
+	cmd := dtos.TwoFactorCodeCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad login data", err)
+	}
+
+	if hs.Cfg.DisableLoginForm {
+		return response.Error(http.StatusUnauthorized, "Login is disabled", nil)
+	}
+
+	var usr *user.User
+	var resp *response.NormalResponse
+
+	defer func() {
+		hs.HooksService.RunLoginHook(&loginservice.LoginInfo{
+			AuthModule: "grafana",
+			User:       usr,
+			HTTPStatus: resp.Status(),
+			Error:      resp.Err(),
+		}, c)
+	}()
+
+	userID, err := hs.twoFactorService.CompleteLogin(c.Req.Context(), twoFactorChallengeToken(c), cmd.Code, c.RemoteAddr())
+	if err != nil {
+		if errors.Is(err, twofactor.ErrChallengeNotFound) {
+			twofactor.DeleteChallengeCookie(c.Resp)
+		}
+		resp = response.ErrOrFallback(http.StatusInternalServerError, "Error while trying to authenticate user", err)
+		return resp
+	}
+	twofactor.DeleteChallengeCookie(c.Resp)
+
+	usr, err = hs.userService.GetByID(c.Req.Context(), &user.GetUserByIDQuery{ID: userID})
+	if err != nil {
+		resp = response.Error(http.StatusInternalServerError, "Error while trying to authenticate user", err)
+		return resp
+	}
+
+	if err := hs.loginUserWithUser(usr, c); err != nil {
+		var createTokenErr *auth.CreateTokenErr
+		if errors.As(err, &createTokenErr) {
+			resp = response.Error(createTokenErr.StatusCode, createTokenErr.ExternalErr, createTokenErr.InternalErr)
+		} else {
+			resp = response.Error(http.StatusInternalServerError, "Error while signing in user", err)
+		}
+		return resp
+	}
+
+	metrics.MApiLoginPost.Inc()
+	resp = response.JSON(http.StatusOK, map[string]any{
+		"message":     "Logged in",
+		"redirectUrl": hs.GetRedirectURL(c),
+	})
+	return resp
+}
+
+// LoginTwoFactorEnroll returns the secret and the recovery codes of users that need to enroll to complete their login
+func (hs *HTTPServer) LoginTwoFactorEnroll(c *contextmodel.ReqContext) response.Response {
+	enrollment, err := hs.twoFactorService.EnrollLogin(c.Req.Context(), twoFactorChallengeToken(c))
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to enroll in two-factor authentication", err)
+	}
+	return response.JSON(http.StatusOK, enrollment)
+}
+
+func twoFactorChallengeToken(c *contextmodel.ReqContext) string {
+	cookie, err := c.Req.Cookie(twofactor.ChallengeCookieName)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+func twoFactorChallengeResponse(enroll bool) *response.NormalResponse {
+	return response.JSON(http.StatusOK, map[string]any{
+		"message":           "Two-factor authentication required",
+		"twoFactorRequired": true,
+		"twoFactorEnroll":   enroll,
+	})
+}
+
+// LoginJWT exchanges a valid JWT for a session, so that following requests
+// can be authenticated with the session cookie instead of the token.
+func (hs *HTTPServer) LoginJWT(c *contextmodel.ReqContext) response.Response {
+	if hs.Features.IsEnabled(featuremgmt.FlagAuthnService) {
+		identity, err := hs.authnService.Login(c.Req.Context(), authn.ClientJWT, &authn.Request{HTTPRequest: c.Req, Resp: c.Resp})
+		if err != nil {
+			tokenErr := &auth.CreateTokenErr{}
+			if errors.As(err, &tokenErr) {
+				return response.Error(tokenErr.StatusCode, tokenErr.ExternalErr, tokenErr.InternalErr)
+			}
+			return response.Err(err)
+		}
+
+		return authn.HandleLoginResponse(c.Req, c.Resp, hs.Cfg, identity, hs.ValidateRedirectTo)
+	}
+
+	// the context handler verifies the JWT before looking at the session cookie,
+	// so a signed in request carrying a JWT is authenticated by that token
+	token := strings.TrimPrefix(c.Req.Header.Get(hs.Cfg.JWTAuthHeaderName), "Bearer ")
+	if token == "" || !authJWT.HasSubClaim(token) || !c.IsSignedIn {
+		return response.Error(http.StatusUnauthorized, "Invalid JWT", nil)
+	}
+
+	usr, err := hs.userService.GetByID(c.Req.Context(), &user.GetUserByIDQuery{ID: c.UserID})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Error while signing in user", err)
+	}
+
+	if err := hs.loginUserWithUser(usr, c); err != nil {
+		var createTokenErr *auth.CreateTokenErr
+		if errors.As(err, &createTokenErr) {
+			return response.Error(createTokenErr.StatusCode, createTokenErr.ExternalErr, createTokenErr.InternalErr)
+		}
+		return response.Error(http.StatusInternalServerError, "Error while signing in user", err)
+	}
+
+	return response.JSON(http.StatusOK, map[string]any{
+		"message":     "Logged in",
+		"redirectUrl": hs.GetRedirectURL(c),
+	})
+}
+
 func (hs *HTTPServer) loginUserWithUser(user *user.User, c *contextmodel.ReqContext) error {
 	if user == nil {
 		return errors.New("could not login user")
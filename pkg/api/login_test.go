@@ -22,7 +22,10 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/login"
 	"github.com/grafana/grafana/pkg/login/social"
+	"github.com/grafana/grafana/pkg/models/usertoken"
 	"github.com/grafana/grafana/pkg/services/auth/authtest"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/authn/authntest"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/hooks"
@@ -32,8 +35,11 @@ import (
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/secrets/fakes"
 	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/twofactor"
+	"github.com/grafana/grafana/pkg/services/twofactor/twofactortest"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 func fakeSetIndexViewData(t *testing.T) {
@@ -605,6 +611,7 @@ func TestLoginPostRunLokingHook(t *testing.T) {
 		AuthTokenService: authtest.NewFakeUserAuthTokenService(),
 		Features:         featuremgmt.WithFeatures(),
 		HooksService:     hookService,
+		twoFactorService: &twofactortest.FakeService{},
 	}
 
 	sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
@@ -689,6 +696,125 @@ func TestLoginPostRunLokingHook(t *testing.T) {
 	}
 }
 
+func TestLoginPostTwoFactor(t *testing.T) {
+	testUser := &user.User{ID: 42}
+
+	testCases := []struct {
+		desc              string
+		authModule        string
+		challenge         *twofactor.LoginChallenge
+		expectedChallenge bool
+		expectedEnroll    bool
+	}{
+		{
+			desc:       "should log in Grafana user without two-factor authentication",
+			authModule: "grafana",
+		},
+		{
+			desc:              "should challenge Grafana user with two-factor authentication",
+			authModule:        "grafana",
+			challenge:         &twofactor.LoginChallenge{Token: "token"},
+			expectedChallenge: true,
+		},
+		{
+			desc:              "should challenge Grafana user required to enroll",
+			authModule:        "grafana",
+			challenge:         &twofactor.LoginChallenge{Token: "token", Enroll: true},
+			expectedChallenge: true,
+			expectedEnroll:    true,
+		},
+		{
+			desc:       "should not challenge LDAP user",
+			authModule: loginservice.LDAPAuthModule,
+			challenge:  &twofactor.LoginChallenge{Token: "token"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sc := setupScenarioContext(t, "/login")
+			hs := &HTTPServer{
+				log:              log.NewNopLogger(),
+				Cfg:              setting.NewCfg(),
+				HooksService:     &hooks.HooksService{},
+				License:          &licensing.OSSLicensingService{},
+				AuthTokenService: authtest.NewFakeUserAuthTokenService(),
+				Features:         featuremgmt.WithFeatures(),
+				authenticator:    &fakeAuthenticator{testUser, tc.authModule, nil},
+				twoFactorService: &twofactortest.FakeService{ExpectedChallenge: tc.challenge},
+			}
+			hs.Cfg.LoginCookieName = "grafana_session"
+
+			sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
+				c.Req.Header.Set("Content-Type", "application/json")
+				c.Req.Body = io.NopCloser(bytes.NewBufferString(`{"user":"admin","password":"admin"}`))
+				return hs.LoginPost(c)
+			})
+			sc.m.Post(sc.url, sc.defaultHandler)
+			sc.fakeReqNoAssertions("POST", sc.url).exec()
+			require.Equal(t, http.StatusOK, sc.resp.Code)
+
+			respJSON, err := simplejson.NewJson(sc.resp.Body.Bytes())
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedChallenge, respJSON.Get("twoFactorRequired").MustBool())
+			assert.Equal(t, tc.expectedEnroll, respJSON.Get("twoFactorEnroll").MustBool())
+
+			setCookie := strings.Join(sc.resp.Header()["Set-Cookie"], "\n")
+			if tc.expectedChallenge {
+				assert.Contains(t, setCookie, twofactor.ChallengeCookieName+"=token")
+				assert.NotContains(t, setCookie, hs.Cfg.LoginCookieName+"=")
+			} else {
+				assert.NotContains(t, setCookie, twofactor.ChallengeCookieName)
+			}
+		})
+	}
+}
+
+func TestLoginJWT(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		identity       *authn.Identity
+		err            error
+		expectedStatus int
+	}{
+		{
+			desc:           "should exchange a valid JWT for a session cookie",
+			identity:       &authn.Identity{ID: "user:42", SessionToken: &usertoken.UserToken{UnhashedToken: "session-token"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "should reject an invalid JWT",
+			err:            errutil.NewBase(errutil.StatusUnauthorized, "jwt.invalid").Errorf("failed to verify JWT"),
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			sc := setupScenarioContext(t, "/api/login/jwt")
+			hs := &HTTPServer{
+				log:          log.NewNopLogger(),
+				Cfg:          setting.NewCfg(),
+				Features:     featuremgmt.WithFeatures(featuremgmt.FlagAuthnService),
+				authnService: &authntest.FakeService{ExpectedIdentity: tc.identity, ExpectedErr: tc.err},
+			}
+			hs.Cfg.LoginCookieName = "grafana_session"
+
+			sc.defaultHandler = routing.Wrap(hs.LoginJWT)
+			sc.m.Post(sc.url, sc.defaultHandler)
+			sc.fakeReqNoAssertions("POST", sc.url).exec()
+			require.Equal(t, tc.expectedStatus, sc.resp.Code)
+
+			setCookie := strings.Join(sc.resp.Header()["Set-Cookie"], "\n")
+			if tc.expectedStatus == http.StatusOK {
+				assert.Contains(t, setCookie, hs.Cfg.LoginCookieName+"=session-token")
+			} else {
+				assert.NotContains(t, setCookie, hs.Cfg.LoginCookieName+"=")
+			}
+		})
+	}
+}
+
 type mockSocialService struct {
 	oAuthInfo       *social.OAuthInfo
 	oAuthInfos      map[string]*social.OAuthInfo
@@ -9,9 +9,13 @@ import (
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -37,7 +41,12 @@ func (hs *HTTPServer) handleQueryMetricsError(err error) *response.NormalRespons
 // DataSource query metrics with expressions.
 //
 // If you are running Grafana Enterprise and have Fine-grained access control enabled
-// you need to have a permission with action: `datasources:query`.
+// you need to have a permission with action: `datasources:query`. When data source permissions
+// are enforced, queries that are not saved in a dashboard you can read (`X-Dashboard-Uid` header)
+// also need a permission with action `datasources:explore`.
+//
+// When query caching is enabled, the `X-Cache` response header is set to `HIT`, `MISS` or `BYPASS`.
+// Send the `X-Cache-Skip: true` header to bypass the cache.
 //
 // Responses:
 // 200: queryMetricsWithExpressionsRespons
@@ -52,13 +61,55 @@ func (hs *HTTPServer) QueryMetricsV2(c *contextmodel.ReqContext) response.Respon
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 
-	resp, err := hs.queryDataService.QueryData(c.Req.Context(), c.SignedInUser, c.SkipCache, reqDTO)
+	ctx := query.WithUsage(c.Req.Context(), hs.queryUsage(c, reqDTO.Queries))
+	ctx = query.WithQueryCache(ctx, c.Req.Header.Get(query.HeaderCacheSkip) == "true")
+	resp, err := hs.queryDataService.QueryData(ctx, c.SignedInUser, c.SkipCache, reqDTO)
 	if err != nil {
 		return hs.handleQueryMetricsError(err)
 	}
+	if status := query.CacheStatusFromContext(ctx); status != "" {
+		c.Resp.Header().Set(query.HeaderCacheStatus, string(status))
+	}
 	return hs.toJsonStreamingResponse(resp)
 }
 
+// queryUsage returns query.UsageDashboard for requests sent from a dashboard
+// the user can read, and query.UsageExplore for any other request. The
+// dashboard header is set by the client, so the queries must also match the
+// targets saved in the panels of the dashboard.
+func (hs *HTTPServer) queryUsage(c *contextmodel.ReqContext, queries []*simplejson.Json) query.Usage {
+	dashboardUID := c.Req.Header.Get(query.HeaderDashboardUID)
+	if dashboardUID == "" {
+		return query.UsageExplore
+	}
+
+	ctx := c.Req.Context()
+	evaluator := ac.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(dashboardUID))
+	if ok, err := hs.AccessControl.Evaluate(ctx, c.SignedInUser, evaluator); err != nil || !ok {
+		return query.UsageExplore
+	}
+
+	dash, err := hs.DashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: dashboardUID, OrgID: c.OrgID})
+	if err != nil {
+		return query.UsageExplore
+	}
+
+	libraryPanel := func(uid string) (*simplejson.Json, error) {
+		element, err := hs.LibraryElementService.GetElement(ctx, c.SignedInUser, uid)
+		if err != nil {
+			return nil, err
+		}
+		return simplejson.NewJson(element.Model)
+	}
+	dataSources := func() ([]*datasources.DataSource, error) {
+		return hs.DataSourcesService.GetDataSources(ctx, &datasources.GetDataSourcesQuery{OrgID: c.OrgID})
+	}
+	if !query.MatchesDashboard(dash.Data, queries, libraryPanel, dataSources) {
+		return query.UsageExplore
+	}
+	return query.UsageDashboard
+}
+
 func (hs *HTTPServer) toJsonStreamingResponse(qdr *backend.QueryDataResponse) response.Response {
 	statusWhenError := http.StatusBadRequest
 	if hs.Features.IsEnabled(featuremgmt.FlagDatasourceQueryMultiStatus) {
@@ -265,13 +265,17 @@ func (hs *HTTPServer) CompleteInvite(c *contextmodel.ReqContext) response.Respon
 		SkipOrgSetup: true,
 	}
 
+	if err := hs.userService.ValidatePassword(cmd.Password); err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "Invalid password", err)
+	}
+
 	usr, err := hs.userService.Create(c.Req.Context(), &cmd)
 	if err != nil {
 		if errors.Is(err, user.ErrUserAlreadyExists) {
 			return response.Error(412, fmt.Sprintf("User with email '%s' or username '%s' already exists", completeInvite.Email, completeInvite.Username), err)
 		}
 
-		return response.Error(500, "failed to create user", err)
+		return response.ErrOrFallback(500, "failed to create user", err)
 	}
 
 	if err := hs.bus.Publish(c.Req.Context(), &events.SignUpCompleted{
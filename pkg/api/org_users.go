@@ -10,6 +10,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/audit"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/login"
@@ -96,6 +97,13 @@ func (hs *HTTPServer) addOrgUserHelper(c *contextmodel.ReqContext, cmd org.AddOr
 		return response.Error(500, "Could not add user to organization", err)
 	}
 
+	hs.auditService.Log(c.Req.Context(), audit.Event{
+		OrgID:    cmd.OrgID,
+		Action:   audit.ActionOrgUserAdd,
+		Resource: audit.Resource{Kind: audit.ResourceUser, ID: strconv.FormatInt(cmd.UserID, 10), Name: userToAdd.Login},
+		Details:  map[string]any{"role": cmd.Role},
+	})
+
 	return response.JSON(http.StatusOK, util.DynMap{
 		"message": "User added to organization",
 		"userId":  cmd.UserID,
@@ -412,6 +420,13 @@ func (hs *HTTPServer) updateOrgUserHelper(c *contextmodel.ReqContext, cmd org.Up
 		return response.Error(http.StatusInternalServerError, "Failed update org user", err)
 	}
 
+	hs.auditService.Log(c.Req.Context(), audit.Event{
+		OrgID:    cmd.OrgID,
+		Action:   audit.ActionOrgUserRoleUpdate,
+		Resource: audit.Resource{Kind: audit.ResourceUser, ID: strconv.FormatInt(cmd.UserID, 10)},
+		Details:  map[string]any{"role": cmd.Role},
+	})
+
 	if !hs.accesscontrolService.IsDisabled() {
 		hs.accesscontrolService.ClearUserPermissionCache(&user.SignedInUser{
 			UserID: cmd.UserID,
@@ -484,6 +499,13 @@ func (hs *HTTPServer) removeOrgUserHelper(ctx context.Context, cmd *org.RemoveOr
 		return response.Error(500, "Failed to remove user from organization", err)
 	}
 
+	hs.auditService.Log(ctx, audit.Event{
+		OrgID:    cmd.OrgID,
+		Action:   audit.ActionOrgUserRemove,
+		Resource: audit.Resource{Kind: audit.ResourceUser, ID: strconv.FormatInt(cmd.UserID, 10)},
+		Details:  map[string]any{"userDeleted": cmd.UserWasDeleted},
+	})
+
 	if cmd.UserWasDeleted {
 		// This should be called from appropriate service when moved
 		if err := hs.accesscontrolService.DeleteUserPermissions(ctx, accesscontrol.GlobalOrgID, cmd.UserID); err != nil {
@@ -11,7 +11,6 @@ import (
 	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/user"
-	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -82,20 +81,11 @@ func (hs *HTTPServer) ResetPassword(c *contextmodel.ReqContext) response.Respons
 		return response.Error(400, "Passwords do not match", nil)
 	}
 
-	password := user.Password(form.NewPassword)
-	if password.IsWeak() {
-		return response.Error(400, "New password is too short", nil)
-	}
-
 	cmd := user.ChangeUserPasswordCommand{}
 	cmd.UserID = userResult.ID
-	cmd.NewPassword, err = util.EncodePassword(form.NewPassword, userResult.Salt)
-	if err != nil {
-		return response.Error(500, "Failed to encode password", err)
-	}
-
+	cmd.NewPassword = form.NewPassword
 	if err := hs.userService.ChangePassword(c.Req.Context(), &cmd); err != nil {
-		return response.Error(500, "Failed to change user password", err)
+		return response.ErrOrFallback(500, "Failed to change user password", err)
 	}
 
 	if err := hs.loginAttemptService.Reset(c.Req.Context(), username); err != nil {
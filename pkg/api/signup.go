@@ -103,13 +103,17 @@ func (hs *HTTPServer) SignUpStep2(c *contextmodel.ReqContext) response.Response
 		createUserCmd.EmailVerified = true
 	}
 
+	if err := hs.userService.ValidatePassword(form.Password); err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "Invalid password", err)
+	}
+
 	usr, err := hs.userService.Create(c.Req.Context(), &createUserCmd)
 	if err != nil {
 		if errors.Is(err, user.ErrUserAlreadyExists) {
 			return response.Error(401, "User with same email address already exists", nil)
 		}
 
-		return response.Error(500, "Failed to create user", err)
+		return response.ErrOrFallback(500, "Failed to create user", err)
 	}
 
 	// publish signup event
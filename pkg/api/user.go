@@ -449,19 +449,9 @@ func (hs *HTTPServer) ChangeUserPassword(c *contextmodel.ReqContext) response.Re
 		return response.Error(401, "Invalid old password", nil)
 	}
 
-	password := user.Password(cmd.NewPassword)
-	if password.IsWeak() {
-		return response.Error(400, "New password is too short", nil)
-	}
-
 	cmd.UserID = c.UserID
-	cmd.NewPassword, err = util.EncodePassword(cmd.NewPassword, usr.Salt)
-	if err != nil {
-		return response.Error(500, "Failed to encode password", err)
-	}
-
 	if err := hs.userService.ChangePassword(c.Req.Context(), &cmd); err != nil {
-		return response.Error(500, "Failed to change user password", err)
+		return response.ErrOrFallback(500, "Failed to change user password", err)
 	}
 
 	return response.Success("User password changed")
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/ua-parser/uap-go/uaparser"
@@ -11,6 +12,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/network"
+	"github.com/grafana/grafana/pkg/services/audit"
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/authn"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
@@ -54,6 +56,57 @@ func (hs *HTTPServer) RevokeUserAuthToken(c *contextmodel.ReqContext) response.R
 	return hs.revokeUserAuthTokenInternal(c, c.UserID, cmd)
 }
 
+// swagger:route POST /user/auth-tokens/revoke-all signed_in_user revokeAllUserAuthTokens
+//
+// Revoke all auth tokens of the actual User.
+//
+// Signs the actual user out of all devices, including the current one. Sessions are stored in the database so this applies to every Grafana instance.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) RevokeAllUserAuthTokens(c *contextmodel.ReqContext) response.Response {
+	resp := hs.logoutUserFromAllDevicesInternal(c.Req.Context(), c.UserID)
+	if resp.Status() == http.StatusOK {
+		authn.DeleteSessionCookie(c.Resp, hs.Cfg)
+	}
+	return resp
+}
+
+// swagger:route GET /org/auth-tokens org getOrgUserAuthTokens
+//
+// Auth tokens of the users of the current organization.
+//
+// Return a list of the active auth tokens (devices) of all the users that are members of the current organization.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `users.authtoken:read` and scope `global.users:*`.
+//
+// Responses:
+// 200: getOrgUserAuthTokensResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetOrgUserAuthTokens(c *contextmodel.ReqContext) response.Response {
+	sessions, err := hs.AuthTokenService.GetOrgUserSessions(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get org user auth tokens", err)
+	}
+
+	parser := uaparser.NewFromSaved()
+	result := make([]*dtos.OrgUserToken, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, &dtos.OrgUserToken{
+			UserToken: userTokenDTO(parser, &session.UserToken, c.UserToken),
+			UserId:    session.UserId,
+			Login:     session.Login,
+			Email:     session.Email,
+		})
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
 func (hs *HTTPServer) RotateUserAuthTokenRedirect(c *contextmodel.ReqContext) response.Response {
 	if err := hs.rotateToken(c); err != nil {
 		hs.log.FromContext(c.Req.Context()).Debug("Failed to rotate token", "error", err)
@@ -135,6 +188,11 @@ func (hs *HTTPServer) logoutUserFromAllDevicesInternal(ctx context.Context, user
 		return response.Error(500, "Failed to logout user", err)
 	}
 
+	hs.auditService.Log(ctx, audit.Event{
+		Action:   audit.ActionUserLogout,
+		Resource: audit.Resource{Kind: audit.ResourceUser, ID: strconv.FormatInt(userID, 10)},
+	})
+
 	return response.JSON(http.StatusOK, util.DynMap{
 		"message": "User logged out",
 	})
@@ -160,56 +218,61 @@ func (hs *HTTPServer) getUserAuthTokensInternal(c *contextmodel.ReqContext, user
 		return response.Error(500, "Failed to get user auth tokens", err)
 	}
 
+	parser := uaparser.NewFromSaved()
 	result := []*dtos.UserToken{}
 	for _, token := range tokens {
-		isActive := false
-		if c.UserToken != nil && c.UserToken.Id == token.Id {
-			isActive = true
-		}
+		dto := userTokenDTO(parser, token, c.UserToken)
+		result = append(result, &dto)
+	}
 
-		parser := uaparser.NewFromSaved()
-		client := parser.Parse(token.UserAgent)
+	return response.JSON(http.StatusOK, result)
+}
 
-		osVersion := ""
-		if client.Os.Major != "" {
-			osVersion = client.Os.Major
+func userTokenDTO(parser *uaparser.Parser, token *auth.UserToken, current *auth.UserToken) dtos.UserToken {
+	isActive := false
+	if current != nil && current.Id == token.Id {
+		isActive = true
+	}
 
-			if client.Os.Minor != "" {
-				osVersion = osVersion + "." + client.Os.Minor
-			}
-		}
+	client := parser.Parse(token.UserAgent)
 
-		browserVersion := ""
-		if client.UserAgent.Major != "" {
-			browserVersion = client.UserAgent.Major
+	osVersion := ""
+	if client.Os.Major != "" {
+		osVersion = client.Os.Major
 
-			if client.UserAgent.Minor != "" {
-				browserVersion = browserVersion + "." + client.UserAgent.Minor
-			}
+		if client.Os.Minor != "" {
+			osVersion = osVersion + "." + client.Os.Minor
 		}
+	}
 
-		createdAt := time.Unix(token.CreatedAt, 0)
-		seenAt := time.Unix(token.SeenAt, 0)
+	browserVersion := ""
+	if client.UserAgent.Major != "" {
+		browserVersion = client.UserAgent.Major
 
-		if token.SeenAt == 0 {
-			seenAt = createdAt
+		if client.UserAgent.Minor != "" {
+			browserVersion = browserVersion + "." + client.UserAgent.Minor
 		}
+	}
 
-		result = append(result, &dtos.UserToken{
-			Id:                     token.Id,
-			IsActive:               isActive,
-			ClientIp:               token.ClientIp,
-			Device:                 client.Device.ToString(),
-			OperatingSystem:        client.Os.Family,
-			OperatingSystemVersion: osVersion,
-			Browser:                client.UserAgent.Family,
-			BrowserVersion:         browserVersion,
-			CreatedAt:              createdAt,
-			SeenAt:                 seenAt,
-		})
+	createdAt := time.Unix(token.CreatedAt, 0)
+	seenAt := time.Unix(token.SeenAt, 0)
+
+	if token.SeenAt == 0 {
+		seenAt = createdAt
 	}
 
-	return response.JSON(http.StatusOK, result)
+	return dtos.UserToken{
+		Id:                     token.Id,
+		IsActive:               isActive,
+		ClientIp:               token.ClientIp,
+		Device:                 client.Device.ToString(),
+		OperatingSystem:        client.Os.Family,
+		OperatingSystemVersion: osVersion,
+		Browser:                client.UserAgent.Family,
+		BrowserVersion:         browserVersion,
+		CreatedAt:              createdAt,
+		SeenAt:                 seenAt,
+	}
 }
 
 func (hs *HTTPServer) revokeUserAuthTokenInternal(c *contextmodel.ReqContext, userID int64, cmd auth.RevokeAuthTokenCmd) response.Response {
@@ -254,6 +317,12 @@ type RevokeUserAuthTokenParams struct {
 	Body auth.RevokeAuthTokenCmd `json:"body"`
 }
 
+// swagger:response getOrgUserAuthTokensResponse
+type GetOrgUserAuthTokensResponse struct {
+	// in:body
+	Body []*dtos.OrgUserToken `json:"body"`
+}
+
 // swagger:response getUserAuthTokensResponse
 type GetUserAuthTokensResponse struct {
 	// in:body
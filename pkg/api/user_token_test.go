@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/audit/audittest"
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/auth/authtest"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
@@ -93,6 +94,40 @@ func TestUserTokenAPIEndpoint(t *testing.T) {
 		}, mockUser)
 	})
 
+	t.Run("When gets auth tokens of the org users", func(t *testing.T) {
+		getOrgUserAuthTokensScenario(t, "Should be successful", func(sc *scenarioContext) {
+			sc.userAuthTokenService.GetOrgUserSessionsProvider = func(ctx context.Context, orgID int64) ([]*auth.UserSession, error) {
+				require.Equal(t, testOrgID, orgID)
+				return []*auth.UserSession{
+					{
+						UserToken: auth.UserToken{
+							Id:        3,
+							UserId:    5,
+							ClientIp:  "10.0.0.1",
+							UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 11_0 like Mac OS X) AppleWebKit/604.1.38 (KHTML, like Gecko) Version/11.0 Mobile/15A372 Safari/604.1",
+							CreatedAt: time.Now().Unix(),
+						},
+						Login: "viewer",
+						Email: "viewer@example.org",
+					},
+				}, nil
+			}
+			sc.fakeReqWithParams("GET", sc.url, map[string]string{}).exec()
+
+			assert.Equal(t, 200, sc.resp.Code)
+			result := sc.ToJSON()
+			require.Len(t, result.MustArray(), 1)
+
+			session := result.GetIndex(0)
+			assert.Equal(t, int64(3), session.Get("id").MustInt64())
+			assert.Equal(t, int64(5), session.Get("userId").MustInt64())
+			assert.Equal(t, "viewer", session.Get("login").MustString())
+			assert.Equal(t, "10.0.0.1", session.Get("clientIp").MustString())
+			assert.Equal(t, "iPhone", session.Get("device").MustString())
+			assert.False(t, session.Get("isActive").MustBool())
+		})
+	})
+
 	t.Run("When gets auth tokens for a user", func(t *testing.T) {
 		currentToken := &auth.UserToken{Id: 1}
 		mockUser := usertest.NewUserServiceFake()
@@ -296,11 +331,37 @@ func getUserAuthTokensScenario(t *testing.T, desc string, url string, routePatte
 	})
 }
 
+func getOrgUserAuthTokensScenario(t *testing.T, desc string, fn scenarioFunc) {
+	t.Run(desc, func(t *testing.T) {
+		fakeAuthTokenService := authtest.NewFakeUserAuthTokenService()
+
+		hs := HTTPServer{
+			AuthTokenService: fakeAuthTokenService,
+		}
+
+		sc := setupScenarioContext(t, "/")
+		sc.userAuthTokenService = fakeAuthTokenService
+		sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
+			sc.context = c
+			sc.context.UserID = testUserID
+			sc.context.OrgID = testOrgID
+			sc.context.IsGrafanaAdmin = true
+
+			return hs.GetOrgUserAuthTokens(c)
+		})
+
+		sc.m.Get("/", sc.defaultHandler)
+
+		fn(sc)
+	})
+}
+
 func logoutUserFromAllDevicesInternalScenario(t *testing.T, desc string, userId int64, fn scenarioFunc, userService user.Service) {
 	t.Run(desc, func(t *testing.T) {
 		hs := HTTPServer{
 			AuthTokenService: authtest.NewFakeUserAuthTokenService(),
 			userService:      userService,
+			auditService:     audittest.NewFakeService(),
 		}
 
 		sc := setupScenarioContext(t, "/")
@@ -0,0 +1,206 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/twofactor"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /user/2fa signed_in_user getUserTwoFactor
+//
+// Two-factor authentication status of the actual User.
+//
+// Responses:
+// 200: getUserTwoFactorResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetUserTwoFactor(c *contextmodel.ReqContext) response.Response {
+	status, err := hs.twoFactorService.GetStatus(c.Req.Context(), c.UserID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get two-factor authentication status", err)
+	}
+	return response.JSON(http.StatusOK, status)
+}
+
+// swagger:route POST /user/2fa/enroll signed_in_user enrollUserTwoFactor
+//
+// Start the two-factor authentication enrollment of the actual User.
+//
+// Returns the secret to add to an authenticator app and the recovery codes. The enrollment is only enabled once confirmed with a code from the app.
+//
+// Responses:
+// 200: enrollUserTwoFactorResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) EnrollUserTwoFactor(c *contextmodel.ReqContext) response.Response {
+	// users authenticated by an external system rely on that system for additional factors
+	isExternal, err := hs.isExternalUser(c.Req.Context(), c.UserID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to validate User", err)
+	}
+	if isExternal {
+		return response.Err(twofactor.ErrExternalUser.Errorf("user %d is managed by an external system", c.UserID))
+	}
+
+	enrollment, err := hs.twoFactorService.Enroll(c.Req.Context(), c.UserID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to enroll in two-factor authentication", err)
+	}
+	return response.JSON(http.StatusOK, enrollment)
+}
+
+// swagger:route POST /user/2fa/confirm signed_in_user confirmUserTwoFactor
+//
+// Enable two-factor authentication for the actual User with a code from the enrolled authenticator app.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) ConfirmUserTwoFactor(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.TwoFactorCodeCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	if err := hs.twoFactorService.ConfirmEnrollment(c.Req.Context(), c.UserID, cmd.Code); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to enable two-factor authentication", err)
+	}
+	return response.Success("Two-factor authentication enabled")
+}
+
+// swagger:route POST /user/2fa/recovery-codes signed_in_user regenerateUserTwoFactorRecoveryCodes
+//
+// Replace the recovery codes of the actual User.
+//
+// Requires a code from the authenticator app or a recovery code. Previous recovery codes can no longer be used.
+//
+// Responses:
+// 200: regenerateUserTwoFactorRecoveryCodesResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) RegenerateUserTwoFactorRecoveryCodes(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.TwoFactorCodeCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	if err := hs.twoFactorService.Verify(c.Req.Context(), c.UserID, cmd.Code); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to verify two-factor authentication code", err)
+	}
+
+	codes, err := hs.twoFactorService.RegenerateRecoveryCodes(c.Req.Context(), c.UserID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to generate recovery codes", err)
+	}
+	return response.JSON(http.StatusOK, map[string]any{"recoveryCodes": codes})
+}
+
+// swagger:route POST /user/2fa/disable signed_in_user disableUserTwoFactor
+//
+// Disable two-factor authentication for the actual User.
+//
+// Requires a code from the authenticator app or a recovery code. Users required to use two-factor authentication cannot disable it.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) DisableUserTwoFactor(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.TwoFactorCodeCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	status, err := hs.twoFactorService.GetStatus(c.Req.Context(), c.UserID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get two-factor authentication status", err)
+	}
+	if status.Required {
+		return response.Err(twofactor.ErrRequired.Errorf("user %d is required to use two-factor authentication", c.UserID))
+	}
+
+	if err := hs.twoFactorService.Verify(c.Req.Context(), c.UserID, cmd.Code); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to verify two-factor authentication code", err)
+	}
+
+	if err := hs.twoFactorService.Disable(c.Req.Context(), c.UserID); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to disable two-factor authentication", err)
+	}
+	return response.Success("Two-factor authentication disabled")
+}
+
+// swagger:route DELETE /admin/users/{user_id}/2fa admin_users adminResetUserTwoFactor
+//
+// Reset two-factor authentication for the user, for example when they lost their authenticator app and their recovery codes.
+// Users required to use two-factor authentication enroll again on their next login.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `users:write` and scope `global.users:*`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminResetUserTwoFactor(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	if err := hs.twoFactorService.Disable(c.Req.Context(), userID); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to reset two-factor authentication", err)
+	}
+	return response.Success("Two-factor authentication reset")
+}
+
+// swagger:response getUserTwoFactorResponse
+type GetUserTwoFactorResponse struct {
+	// in:body
+	Body twofactor.Status `json:"body"`
+}
+
+// swagger:response enrollUserTwoFactorResponse
+type EnrollUserTwoFactorResponse struct {
+	// in:body
+	Body twofactor.Enrollment `json:"body"`
+}
+
+// swagger:response regenerateUserTwoFactorRecoveryCodesResponse
+type RegenerateUserTwoFactorRecoveryCodesResponse struct {
+	// in:body
+	Body struct {
+		RecoveryCodes []string `json:"recoveryCodes"`
+	} `json:"body"`
+}
+
+// swagger:parameters confirmUserTwoFactor regenerateUserTwoFactorRecoveryCodes disableUserTwoFactor
+type TwoFactorCodeParams struct {
+	// in:body
+	// required:true
+	Body dtos.TwoFactorCodeCommand `json:"body"`
+}
+
+// swagger:parameters adminResetUserTwoFactor
+type AdminResetUserTwoFactorParams struct {
+	// in:path
+	// required:true
+	UserID int64 `json:"user_id"`
+}
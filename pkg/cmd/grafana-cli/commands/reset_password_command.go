@@ -11,8 +11,8 @@ import (
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
 	"github.com/grafana/grafana/pkg/server"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
 	"github.com/grafana/grafana/pkg/services/user"
-	"github.com/grafana/grafana/pkg/util"
 )
 
 const DefaultAdminUserId = 1
@@ -36,7 +36,7 @@ func resetPasswordCommand(c utils.CommandLine, runner server.Runner) error {
 		newPassword = c.Args().First()
 	}
 
-	err := resetPassword(adminId, newPassword, runner.UserService)
+	err := resetPassword(adminId, newPassword, runner.UserService, runner.LoginAttempts)
 	if err == nil {
 		logger.Infof("\n")
 		logger.Infof("Admin password changed successfully %s", color.GreenString("✔"))
@@ -44,12 +44,7 @@ func resetPasswordCommand(c utils.CommandLine, runner server.Runner) error {
 	return err
 }
 
-func resetPassword(adminId int64, newPassword string, userSvc user.Service) error {
-	password := user.Password(newPassword)
-	if password.IsWeak() {
-		return fmt.Errorf("new password is too short")
-	}
-
+func resetPassword(adminId int64, newPassword string, userSvc user.Service, loginAttempts loginattempt.Service) error {
 	userQuery := user.GetUserByIDQuery{ID: adminId}
 	usr, err := userSvc.GetByID(context.Background(), &userQuery)
 	if err != nil {
@@ -59,20 +54,20 @@ func resetPassword(adminId int64, newPassword string, userSvc user.Service) erro
 		return ErrMustBeAdmin
 	}
 
-	passwordHashed, err := util.EncodePassword(newPassword, usr.Salt)
-	if err != nil {
-		return err
-	}
-
 	cmd := user.ChangeUserPasswordCommand{
 		UserID:      adminId,
-		NewPassword: passwordHashed,
+		NewPassword: newPassword,
 	}
 
 	if err := userSvc.ChangePassword(context.Background(), &cmd); err != nil {
 		return fmt.Errorf("failed to update user password: %w", err)
 	}
 
+	// the admin may have been locked out, which is usually why the password is reset
+	if err := loginAttempts.Unlock(context.Background(), usr.Login); err != nil {
+		return fmt.Errorf("failed to unlock user: %w", err)
+	}
+
 	return nil
 }
 
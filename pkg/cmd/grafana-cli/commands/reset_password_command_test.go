@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/services/user/usertest"
 )
@@ -43,7 +44,7 @@ func TestResetPassword(t *testing.T) {
 			svc.ExpectedUser = &user.User{
 				IsAdmin: test.IsAdmin,
 			}
-			err := resetPassword(test.UserID, "s00pers3cure!", svc)
+			err := resetPassword(test.UserID, "s00pers3cure!", svc, loginattempttest.FakeLoginAttemptService{})
 			if test.ExpectErr != nil {
 				require.EqualError(t, err, test.ExpectErr.Error())
 			} else {
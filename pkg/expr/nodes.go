@@ -10,6 +10,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"gonum.org/v1/gonum/graph/simple"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/expr/classic"
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -180,6 +181,9 @@ func (s *Service) buildDSNode(dp *simple.DirectedGraph, rn *rawNode, req *Reques
 		request:    *req,
 		datasource: rn.DataSource,
 	}
+	if err := CheckQueryRestrictions(rn.DataSource, rn.RefID, rn.QueryType, simplejson.NewFromAny(rn.Query)); err != nil {
+		return nil, err
+	}
 
 	var floatIntervalMS float64
 	if rawIntervalMS, ok := rn.Query["intervalMs"]; ok {
@@ -205,6 +209,14 @@ func (s *Service) buildDSNode(dp *simple.DirectedGraph, rn *rawNode, req *Reques
 // already by in vars.
 func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s *Service) (r mathexp.Results, e error) {
 	logger := logger.FromContext(ctx).New("datasourceType", dn.datasource.Type, "queryRefId", dn.refID, "datasourceUid", dn.datasource.UID, "datasourceVersion", dn.datasource.Version)
+	// the query restrictions of the data source may have changed since the node was built
+	model, err := simplejson.NewJson(dn.query)
+	if err != nil {
+		model = simplejson.New()
+	}
+	if err := CheckQueryRestrictions(dn.datasource, dn.refID, dn.queryType, model); err != nil {
+		return mathexp.Results{}, err
+	}
 	dsInstanceSettings, err := adapters.ModelToInstanceSettings(dn.datasource, s.decryptSecureJsonDataFn(ctx))
 	if err != nil {
 		return mathexp.Results{}, fmt.Errorf("%v: %w", "failed to convert datasource instance settings", err)
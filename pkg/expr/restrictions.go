@@ -0,0 +1,51 @@
+package expr
+
+import (
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrQueryTypeNotAllowed = errutil.NewBase(errutil.StatusForbidden, "query.queryTypeNotAllowed").MustTemplate("query type {{ .Public.QueryType }} of query {{ .Public.RefId }} is not allowed", errutil.WithPublic("Query type '{{ .Public.QueryType }}' of query {{ .Public.RefId }} is not allowed for this data source"))
+	ErrQueryNotReadOnly    = errutil.NewBase(errutil.StatusBadRequest, "query.notReadOnly").MustTemplate("query {{ .Public.RefId }} was rejected: {{ .Error }}", errutil.WithPublic("Query {{ .Public.RefId }} was rejected: {{ .Public.Reason }}"))
+)
+
+// CheckQueryRestrictions verifies that a query is allowed by the query
+// restrictions of its data source. It is checked by the query service and by
+// the data source nodes of expressions, which alert rules are evaluated with.
+func CheckQueryRestrictions(ds *datasources.DataSource, refID string, queryType string, model *simplejson.Json) error {
+	if ds == nil {
+		return nil
+	}
+	restrictions := ds.QueryRestrictions()
+	if restrictions.IsEmpty() {
+		return nil
+	}
+
+	if len(restrictions.AllowedQueryTypes) > 0 && !isQueryTypeAllowed(restrictions.AllowedQueryTypes, queryType) {
+		return ErrQueryTypeNotAllowed.Build(errutil.TemplateData{
+			Public: map[string]interface{}{"RefId": refID, "QueryType": queryType},
+		})
+	}
+
+	if restrictions.ReadOnlySQL {
+		if err := sqleng.ValidateReadOnly(model.Get("rawSql").MustString()); err != nil {
+			return ErrQueryNotReadOnly.Build(errutil.TemplateData{
+				Public: map[string]interface{}{"RefId": refID, "Reason": err.Error()},
+				Error:  err,
+			})
+		}
+	}
+	return nil
+}
+
+func isQueryTypeAllowed(allowed []string, queryType string) bool {
+	for _, t := range allowed {
+		if t == queryType {
+			return true
+		}
+	}
+	return false
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	datafakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
 	"github.com/grafana/grafana/pkg/setting"
@@ -93,6 +94,46 @@ func TestService(t *testing.T) {
 	}
 }
 
+func TestServiceQueryRestrictions(t *testing.T) {
+	s := Service{
+		cfg:               setting.NewCfg(),
+		dataService:       &mockEndpoint{},
+		dataSourceService: &datafakes.FakeDataSourceService{},
+	}
+	ds := &datasources.DataSource{
+		OrgID:    1,
+		UID:      "mysql",
+		Type:     datasources.DS_MYSQL,
+		JsonData: simplejson.NewFromAny(map[string]interface{}{"readOnlySql": true, "allowedQueryTypes": []interface{}{"", "table"}}),
+	}
+	build := func(queryType string, model string) error {
+		_, err := s.BuildPipeline(&Request{Queries: []Query{{
+			RefID:      "A",
+			DataSource: ds,
+			QueryType:  queryType,
+			JSON:       json.RawMessage(model),
+			TimeRange:  AbsoluteTimeRange{},
+		}}})
+		return err
+	}
+
+	require.NoError(t, build("table", `{"rawSql": "SELECT 1"}`))
+	require.ErrorIs(t, build("table", `{"rawSql": "DELETE FROM users"}`), ErrQueryNotReadOnly)
+	require.ErrorIs(t, build("timeseries", `{"rawSql": "SELECT 1"}`), ErrQueryTypeNotAllowed)
+
+	// the data source can be restricted after the pipeline is built
+	pl, err := s.BuildPipeline(&Request{Queries: []Query{{
+		RefID:      "A",
+		DataSource: &datasources.DataSource{OrgID: 1, UID: "mysql", Type: datasources.DS_MYSQL},
+		JSON:       json.RawMessage(`{"rawSql": "DROP TABLE users"}`),
+		TimeRange:  AbsoluteTimeRange{},
+	}}})
+	require.NoError(t, err)
+	pl[0].(*DSNode).datasource = ds
+	_, err = pl[0].Execute(context.Background(), time.Now(), nil, &s)
+	require.ErrorIs(t, err, ErrQueryNotReadOnly)
+}
+
 func fp(f float64) *float64 {
 	return &f
 }
@@ -109,6 +109,9 @@ var (
 
 	// MPublicDashboardDatasourceQuerySuccess is a metric counter for successful queries labelled by datasource
 	MPublicDashboardDatasourceQuerySuccess *prometheus.CounterVec
+
+	// MPublicDashboardAccessLogDropped is a metric counter for public dashboard access log entries that were not saved
+	MPublicDashboardAccessLogDropped prometheus.Counter
 )
 
 // Timers
@@ -437,6 +440,12 @@ func init() {
 		Namespace: ExporterName,
 	})
 
+	MPublicDashboardAccessLogDropped = metricutil.NewCounterStartingAtZero(prometheus.CounterOpts{
+		Name:      "public_dashboard_access_log_dropped_total",
+		Help:      "counter for public dashboard access log entries dropped because too many entries were pending",
+		Namespace: ExporterName,
+	})
+
 	MPublicDashboardDatasourceQuerySuccess = metricutil.NewCounterVecStartingAtZero(prometheus.CounterOpts{
 		Name:      "public_dashboard_datasource_query_success",
 		Help:      "counter for queries to public dashboard datasources labelled by datasource type and success status success/failed",
@@ -705,5 +714,6 @@ func initMetricVars() {
 		MStatTotalPublicDashboards,
 		MPublicDashboardRequestCount,
 		MPublicDashboardDatasourceQuerySuccess,
+		MPublicDashboardAccessLogDropped,
 	)
 }
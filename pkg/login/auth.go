@@ -77,6 +77,7 @@ func (a *AuthenticatorService) AuthenticateUser(ctx context.Context, query *logi
 	if isGrafanaLoginEnabled && (err == nil || (!errors.Is(err, user.ErrUserNotFound) && !errors.Is(err, ErrInvalidCredentials) &&
 		!errors.Is(err, ErrUserDisabled))) {
 		query.AuthModule = "grafana"
+		a.resetLoginAttempts(ctx, query, err)
 		return err
 	}
 
@@ -84,6 +85,7 @@ func (a *AuthenticatorService) AuthenticateUser(ctx context.Context, query *logi
 	if ldapEnabled {
 		query.AuthModule = login.LDAPAuthModule
 		if ldapErr == nil || !errors.Is(ldapErr, ldap.ErrInvalidCredentials) {
+			a.resetLoginAttempts(ctx, query, ldapErr)
 			return ldapErr
 		}
 
@@ -107,6 +109,17 @@ func (a *AuthenticatorService) AuthenticateUser(ctx context.Context, query *logi
 	return err
 }
 
+// resetLoginAttempts clears the failed login attempts after a successful login
+// so that only consecutive failures count towards the account lockout.
+func (a *AuthenticatorService) resetLoginAttempts(ctx context.Context, query *login.LoginUserQuery, err error) {
+	if err != nil {
+		return
+	}
+	if err := a.loginAttemptService.Reset(ctx, query.Username); err != nil {
+		loginLogger.Error("Failed to reset login attempts", "err", err)
+	}
+}
+
 func validatePasswordSet(password string) error {
 	if len(password) == 0 {
 		return ErrPasswordEmpty
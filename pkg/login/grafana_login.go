@@ -24,18 +24,26 @@ var validatePassword = func(providedPassword string, userPassword string, userSa
 var loginUsingGrafanaDB = func(ctx context.Context, query *login.LoginUserQuery, userService user.Service) error {
 	userQuery := user.GetUserByLoginQuery{LoginOrEmail: query.Username}
 
-	user, err := userService.GetByLogin(ctx, &userQuery)
+	usr, err := userService.GetByLogin(ctx, &userQuery)
 	if err != nil {
 		return err
 	}
 
-	if user.IsDisabled {
+	if usr.IsDisabled {
 		return ErrUserDisabled
 	}
 
-	if err := validatePassword(query.Password, user.Password, user.Salt); err != nil {
+	if err := validatePassword(query.Password, usr.Password, usr.Salt); err != nil {
 		return err
 	}
-	query.User = user
+
+	expired, err := userService.IsPasswordExpired(ctx, usr)
+	if err != nil {
+		return err
+	}
+	if expired {
+		return user.ErrPasswordExpired.Errorf("password of user %d has expired", usr.ID)
+	}
+	query.User = usr
 	return nil
 }
@@ -46,6 +46,16 @@ func TestLoginUsingGrafanaDB(t *testing.T) {
 		assert.Equal(t, sc.loginUserQuery.Password, sc.loginUserQuery.User.Password)
 	})
 
+	grafanaLoginScenario(t, "When login with expired password", func(sc *grafanaLoginScenarioContext) {
+		sc.withValidCredentials()
+		sc.userService.ExpectedPasswordExpired = true
+		err := loginUsingGrafanaDB(context.Background(), sc.loginUserQuery, sc.userService)
+		require.ErrorIs(t, err, user.ErrPasswordExpired)
+
+		assert.True(t, sc.validatePasswordCalled)
+		assert.Nil(t, sc.loginUserQuery.User)
+	})
+
 	grafanaLoginScenario(t, "When login with disabled user", func(sc *grafanaLoginScenarioContext) {
 		sc.withDisabledUser()
 		err := loginUsingGrafanaDB(context.Background(), sc.loginUserQuery, sc.userService)
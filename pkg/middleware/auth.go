@@ -109,9 +109,16 @@ func CanAdminPlugins(cfg *setting.Cfg) func(c *contextmodel.ReqContext) {
 
 func RoleAuth(roles ...org.RoleType) web.Handler {
 	return func(c *contextmodel.ReqContext) {
+		orgRole := c.OrgRole
+		// scoped service account tokens are only limited by access control, so they can't
+		// use the role of the service account on routes that only check the role
+		if c.SignedInUser != nil && c.SignedInUser.TokenPermissions != nil {
+			orgRole = org.RoleViewer
+		}
+
 		ok := false
 		for _, role := range roles {
-			if role == c.OrgRole {
+			if role == orgRole {
 				ok = true
 				break
 			}
@@ -2,14 +2,19 @@ package middleware
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
 )
 
 func TestMiddlewareAuth(t *testing.T) {
@@ -112,6 +117,45 @@ func TestMiddlewareAuth(t *testing.T) {
 	})
 }
 
+func TestRoleAuth(t *testing.T) {
+	tests := []struct {
+		desc             string
+		role             org.RoleType
+		tokenPermissions map[string][]string
+		expectedCode     int
+	}{
+		{desc: "should allow editor", role: org.RoleEditor, expectedCode: http.StatusOK},
+		{desc: "should reject viewer", role: org.RoleViewer, expectedCode: http.StatusForbidden},
+		{
+			desc:             "should reject editor authenticated with a scoped token",
+			role:             org.RoleEditor,
+			tokenPermissions: map[string][]string{"dashboards:read": {"dashboards:*"}},
+			expectedCode:     http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c := &contextmodel.ReqContext{
+				Context: &web.Context{
+					Req:  httptest.NewRequest(http.MethodGet, "/api/secure", nil),
+					Resp: web.NewResponseWriter(http.MethodGet, recorder),
+				},
+				SignedInUser: &user.SignedInUser{OrgRole: tt.role, TokenPermissions: tt.tokenPermissions},
+				Logger:       log.New("test"),
+			}
+
+			ReqEditorRole.(func(*contextmodel.ReqContext))(c)
+			if !c.Resp.Written() {
+				c.Resp.WriteHeader(http.StatusOK)
+			}
+
+			assert.Equal(t, tt.expectedCode, recorder.Code)
+		})
+	}
+}
+
 func TestRemoveForceLoginparams(t *testing.T) {
 	tcs := []struct {
 		inp string
@@ -44,6 +44,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/org/orgtest"
 	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/twofactor/twofactortest"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/services/user/usertest"
 	"github.com/grafana/grafana/pkg/setting"
@@ -960,7 +961,7 @@ func getContextHandler(t *testing.T, cfg *setting.Cfg, mockSQLStore *dbtest.Fake
 		loginService, apiKeyService, authenticator, userService, orgService,
 		oauthTokenService,
 		featuremgmt.WithFeatures(featuremgmt.FlagAccessTokenExpirationCheck),
-		&authntest.FakeService{}, &anontest.FakeAnonymousSessionService{})
+		&authntest.FakeService{}, &anontest.FakeAnonymousSessionService{}, &twofactortest.FakeService{})
 }
 
 type fakeRenderService struct {
@@ -48,6 +48,8 @@ func corePlugins(rt *thema.Runtime) []pfs.ParsedPlugin {
 		parsePluginOrPanic("public/app/plugins/datasource/phlare", "phlare", rt),
 		parsePluginOrPanic("public/app/plugins/datasource/postgres", "postgres", rt),
 		parsePluginOrPanic("public/app/plugins/datasource/prometheus", "prometheus", rt),
+		parsePluginOrPanic("public/app/plugins/datasource/sqlite", "sqlite", rt),
+		parsePluginOrPanic("public/app/plugins/datasource/genericsql", "genericsql", rt),
 		parsePluginOrPanic("public/app/plugins/datasource/tempo", "tempo", rt),
 		parsePluginOrPanic("public/app/plugins/datasource/testdata", "testdata", rt),
 		parsePluginOrPanic("public/app/plugins/datasource/zipkin", "zipkin", rt),
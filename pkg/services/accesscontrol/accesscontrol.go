@@ -42,6 +42,29 @@ type Service interface {
 	IsDisabled() bool
 }
 
+// RoleService manages custom roles, sets of permissions stored in the database that can be
+// assigned to users, service accounts and teams
+type RoleService interface {
+	// CreateRole creates a custom role in the organization, or a global one
+	CreateRole(ctx context.Context, cmd CreateRoleCommand) (*RoleDTO, error)
+	// UpdateRole replaces the attributes and permissions of a custom role
+	UpdateRole(ctx context.Context, cmd UpdateRoleCommand) (*RoleDTO, error)
+	// DeleteRole removes a custom role with its permissions and assignments
+	DeleteRole(ctx context.Context, cmd DeleteRoleCommand) error
+	// GetRole returns a custom role of the organization, or a global one, with its permissions
+	GetRole(ctx context.Context, query GetRoleQuery) (*RoleDTO, error)
+	// SearchRoles returns the custom roles available in the organization with their permissions
+	SearchRoles(ctx context.Context, query SearchRolesQuery) ([]*RoleDTO, error)
+	// GetUserRoles returns the custom roles assigned to a user in the organization
+	GetUserRoles(ctx context.Context, orgID, userID int64) ([]*RoleDTO, error)
+	// GetTeamRoles returns the custom roles assigned to a team
+	GetTeamRoles(ctx context.Context, orgID, teamID int64) ([]*RoleDTO, error)
+	// AddRoleAssignment assigns a custom role to a user or a team
+	AddRoleAssignment(ctx context.Context, cmd RoleAssignmentCommand) error
+	// RemoveRoleAssignment removes a custom role from a user or a team
+	RemoveRoleAssignment(ctx context.Context, cmd RoleAssignmentCommand) error
+}
+
 type RoleRegistry interface {
 	// RegisterFixedRoles registers all roles declared to AccessControl
 	RegisterFixedRoles(ctx context.Context) error
@@ -256,6 +279,33 @@ func Reduce(ps []Permission) map[string][]string {
 	return reduced
 }
 
+// RestrictPermissions returns the part of the permissions covered by the allowed scopes, grouped by action.
+// An empty allowed scope allows the action on every scope, a scope narrower than a permission replaces it.
+func RestrictPermissions(permissions []Permission, allowed map[string][]string) []Permission {
+	restricted := make([]Permission, 0, len(permissions))
+	seen := make(map[Permission]bool)
+	add := func(action, scope string) {
+		p := Permission{Action: action, Scope: scope}
+		if !seen[p] {
+			seen[p] = true
+			restricted = append(restricted, p)
+		}
+	}
+
+	for _, p := range permissions {
+		for _, scope := range allowed[p.Action] {
+			switch {
+			case scope == "" || p.Scope == "" || match(scope, p.Scope):
+				add(p.Action, p.Scope)
+			case match(p.Scope, scope):
+				add(p.Action, scope)
+			}
+		}
+	}
+
+	return restricted
+}
+
 func ValidateScope(scope string) bool {
 	prefix, last := scope[:len(scope)-1], scope[len(scope)-1]
 	// verify that last char is either ':' or '/' if last character of scope is '*'
@@ -287,6 +337,10 @@ func IsDisabled(cfg *setting.Cfg) bool {
 // GetOrgRoles returns legacy org roles for a user
 func GetOrgRoles(user *user.SignedInUser) []string {
 	roles := []string{string(user.OrgRole)}
+	// scoped tokens are restricted from the permissions of the role of the service account
+	if user.TokenPermissions != nil && user.TokenOrgRole != "" {
+		roles = []string{string(user.TokenOrgRole)}
+	}
 
 	if user.IsGrafanaAdmin {
 		roles = append(roles, RoleGrafanaAdmin)
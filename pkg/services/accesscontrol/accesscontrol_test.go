@@ -125,3 +125,61 @@ func TestReduce(t *testing.T) {
 		})
 	}
 }
+
+func TestRestrictPermissions(t *testing.T) {
+	tests := []struct {
+		name    string
+		ps      []Permission
+		allowed map[string][]string
+		want    []Permission
+	}{
+		{
+			name: "drop actions that are not allowed",
+			ps: []Permission{
+				{Action: "dashboards:read", Scope: "dashboards:*"},
+				{Action: "dashboards:write", Scope: "dashboards:*"},
+				{Action: "users:create"},
+			},
+			allowed: map[string][]string{"dashboards:read": {""}, "users:create": {""}},
+			want: []Permission{
+				{Action: "dashboards:read", Scope: "dashboards:*"},
+				{Action: "users:create"},
+			},
+		},
+		{
+			name: "narrow wildcard scopes to the allowed scope",
+			ps: []Permission{
+				{Action: "dashboards:read", Scope: "dashboards:*"},
+				{Action: "dashboards:read", Scope: "folders:*"},
+			},
+			allowed: map[string][]string{"dashboards:read": {"folders:uid:ops"}},
+			want: []Permission{
+				{Action: "dashboards:read", Scope: "folders:uid:ops"},
+			},
+		},
+		{
+			name: "keep the scopes covered by an allowed wildcard",
+			ps: []Permission{
+				{Action: "dashboards:read", Scope: "dashboards:uid:1"},
+				{Action: "dashboards:read", Scope: "folders:uid:ops"},
+			},
+			allowed: map[string][]string{"dashboards:read": {"dashboards:*"}},
+			want: []Permission{
+				{Action: "dashboards:read", Scope: "dashboards:uid:1"},
+			},
+		},
+		{
+			name: "not grant scopes outside of the permissions",
+			ps: []Permission{
+				{Action: "dashboards:read", Scope: "folders:uid:ops"},
+			},
+			allowed: map[string][]string{"dashboards:read": {"folders:uid:dev"}},
+			want:    []Permission{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ElementsMatch(t, tt.want, RestrictPermissions(tt.ps, tt.allowed))
+		})
+	}
+}
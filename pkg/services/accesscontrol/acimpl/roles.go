@@ -0,0 +1,191 @@
+package acimpl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	maxRoleNameLength = 190
+	maxRoleUIDLength  = 40
+)
+
+var _ accesscontrol.RoleService = &Service{}
+
+// CreateRole creates a custom role, global roles are stored in the global organization
+func (s *Service) CreateRole(ctx context.Context, cmd accesscontrol.CreateRoleCommand) (*accesscontrol.RoleDTO, error) {
+	uid := cmd.UID
+	if uid == "" {
+		uid = util.GenerateShortUID()
+	}
+
+	orgID := cmd.OrgID
+	if cmd.Global {
+		orgID = accesscontrol.GlobalOrgID
+	}
+
+	now := time.Now()
+	role := &accesscontrol.RoleDTO{
+		OrgID:       orgID,
+		UID:         uid,
+		Version:     1,
+		Name:        cmd.Name,
+		DisplayName: cmd.DisplayName,
+		Description: cmd.Description,
+		Group:       cmd.Group,
+		Hidden:      cmd.Hidden,
+		Permissions: cmd.Permissions,
+		Created:     now,
+		Updated:     now,
+	}
+	if err := validateCustomRole(role); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.CreateRole(ctx, role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// UpdateRole replaces the attributes and permissions of a custom role and increments its version
+func (s *Service) UpdateRole(ctx context.Context, cmd accesscontrol.UpdateRoleCommand) (*accesscontrol.RoleDTO, error) {
+	role, err := s.store.GetRole(ctx, cmd.OrgID, cmd.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	previousVersion := role.Version
+	role.Version = previousVersion + 1
+	if cmd.Version != 0 {
+		if cmd.Version <= previousVersion {
+			return nil, accesscontrol.ErrRoleVersionMismatch.Errorf("UpdateRole: role %s is at version %d, expected a greater version than %d", role.UID, previousVersion, cmd.Version)
+		}
+		role.Version = cmd.Version
+	}
+	role.Name = cmd.Name
+	role.DisplayName = cmd.DisplayName
+	role.Description = cmd.Description
+	role.Group = cmd.Group
+	role.Hidden = cmd.Hidden
+	role.Permissions = cmd.Permissions
+	role.Updated = time.Now()
+	if err := validateCustomRole(role); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.UpdateRole(ctx, role, previousVersion); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (s *Service) DeleteRole(ctx context.Context, cmd accesscontrol.DeleteRoleCommand) error {
+	role, err := s.store.GetRole(ctx, cmd.OrgID, cmd.UID)
+	if err != nil {
+		return err
+	}
+	return s.store.DeleteRole(ctx, role.ID)
+}
+
+func (s *Service) GetRole(ctx context.Context, query accesscontrol.GetRoleQuery) (*accesscontrol.RoleDTO, error) {
+	return s.store.GetRole(ctx, query.OrgID, query.UID)
+}
+
+func (s *Service) SearchRoles(ctx context.Context, query accesscontrol.SearchRolesQuery) ([]*accesscontrol.RoleDTO, error) {
+	return s.store.SearchRoles(ctx, query)
+}
+
+func (s *Service) GetUserRoles(ctx context.Context, orgID, userID int64) ([]*accesscontrol.RoleDTO, error) {
+	return s.store.GetUserRoles(ctx, orgID, userID)
+}
+
+func (s *Service) GetTeamRoles(ctx context.Context, orgID, teamID int64) ([]*accesscontrol.RoleDTO, error) {
+	return s.store.GetTeamRoles(ctx, orgID, teamID)
+}
+
+// AddRoleAssignment assigns a custom role to a user, or service account, or a team of the organization
+func (s *Service) AddRoleAssignment(ctx context.Context, cmd accesscontrol.RoleAssignmentCommand) error {
+	role, err := s.store.GetRole(ctx, cmd.OrgID, cmd.RoleUID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case cmd.UserID > 0:
+		if err := s.store.AddUserRole(ctx, cmd.OrgID, cmd.UserID, role.ID); err != nil {
+			return err
+		}
+		s.clearAssigneePermissionCache(cmd.OrgID, cmd.UserID)
+		return nil
+	case cmd.TeamID > 0:
+		// team members permissions are refreshed when their cache entries expire
+		return s.store.AddTeamRole(ctx, cmd.OrgID, cmd.TeamID, role.ID)
+	default:
+		return accesscontrol.ErrRoleAssignmentTarget.Errorf("AddRoleAssignment: no user or team to assign role %s to", cmd.RoleUID)
+	}
+}
+
+// RemoveRoleAssignment removes a custom role from a user, or service account, or a team of the organization
+func (s *Service) RemoveRoleAssignment(ctx context.Context, cmd accesscontrol.RoleAssignmentCommand) error {
+	role, err := s.store.GetRole(ctx, cmd.OrgID, cmd.RoleUID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case cmd.UserID > 0:
+		if err := s.store.RemoveUserRole(ctx, cmd.OrgID, cmd.UserID, role.ID); err != nil {
+			return err
+		}
+		s.clearAssigneePermissionCache(cmd.OrgID, cmd.UserID)
+		return nil
+	case cmd.TeamID > 0:
+		return s.store.RemoveTeamRole(ctx, cmd.OrgID, cmd.TeamID, role.ID)
+	default:
+		return accesscontrol.ErrRoleAssignmentTarget.Errorf("RemoveRoleAssignment: no user or team to remove role %s from", cmd.RoleUID)
+	}
+}
+
+// clearAssigneePermissionCache removes the cached permissions of a user or a service account
+func (s *Service) clearAssigneePermissionCache(orgID, userID int64) {
+	s.ClearUserPermissionCache(&user.SignedInUser{OrgID: orgID, UserID: userID})
+	s.ClearUserPermissionCache(&user.SignedInUser{OrgID: orgID, UserID: userID, IsServiceAccount: true})
+}
+
+// validateCustomRole checks the name, uid and permissions of a custom role and removes duplicated permissions
+func validateCustomRole(role *accesscontrol.RoleDTO) error {
+	if !role.IsCustom() || len(role.Name) == len(accesscontrol.CustomRolePrefix) {
+		return accesscontrol.ErrRoleInvalid.Errorf("role name %q should be prefixed with %q", role.Name, accesscontrol.CustomRolePrefix)
+	}
+	if len(role.Name) > maxRoleNameLength || len(role.DisplayName) > maxRoleNameLength || len(role.Group) > maxRoleNameLength {
+		return accesscontrol.ErrRoleInvalid.Errorf("role name, display name and group should not be longer than %d characters", maxRoleNameLength)
+	}
+	if !util.IsValidShortUID(role.UID) || len(role.UID) > maxRoleUIDLength {
+		return accesscontrol.ErrRoleInvalid.Errorf("role uid %q is invalid", role.UID)
+	}
+
+	seen := make(map[accesscontrol.Permission]bool, len(role.Permissions))
+	permissions := make([]accesscontrol.Permission, 0, len(role.Permissions))
+	for _, p := range role.Permissions {
+		p = p.OSSPermission()
+		if p.Action == "" || len(p.Action) > maxRoleNameLength || strings.ContainsAny(p.Action, "*?") {
+			return accesscontrol.ErrRoleInvalid.Errorf("permission action %q is invalid", p.Action)
+		}
+		if p.Scope != "" && (len(p.Scope) > maxRoleNameLength || !accesscontrol.ValidateScope(p.Scope)) {
+			return accesscontrol.ErrRoleInvalid.Errorf("permission scope %q is invalid", p.Scope)
+		}
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		permissions = append(permissions, p)
+	}
+	role.Permissions = permissions
+	return nil
+}
@@ -0,0 +1,121 @@
+package acimpl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+func TestService_CreateRole(t *testing.T) {
+	ctx := context.Background()
+	ac := setupTestEnv(t)
+
+	t.Run("should create role with generated uid and without duplicated permissions", func(t *testing.T) {
+		role, err := ac.CreateRole(ctx, accesscontrol.CreateRoleCommand{
+			OrgID: 1,
+			Name:  "custom:annotations:writer",
+			Permissions: []accesscontrol.Permission{
+				{Action: accesscontrol.ActionAnnotationsWrite, Scope: "folders:uid:x"},
+				{Action: accesscontrol.ActionAnnotationsWrite, Scope: "folders:uid:x"},
+				{Action: accesscontrol.ActionAnnotationsCreate, Scope: "folders:uid:x"},
+			},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, role.UID)
+		require.Equal(t, int64(1), role.Version)
+		require.Len(t, role.Permissions, 2)
+
+		stored, err := ac.GetRole(ctx, accesscontrol.GetRoleQuery{OrgID: 1, UID: role.UID})
+		require.NoError(t, err)
+		require.Len(t, stored.Permissions, 2)
+	})
+
+	t.Run("should create global role", func(t *testing.T) {
+		role, err := ac.CreateRole(ctx, accesscontrol.CreateRoleCommand{
+			OrgID:       1,
+			Global:      true,
+			UID:         "stats-reader",
+			Name:        "custom:stats:reader",
+			Permissions: []accesscontrol.Permission{{Action: accesscontrol.ActionServerStatsRead}},
+		})
+		require.NoError(t, err)
+		require.True(t, role.Global())
+	})
+
+	tests := []struct {
+		desc string
+		cmd  accesscontrol.CreateRoleCommand
+	}{
+		{desc: "should fail without custom prefix", cmd: accesscontrol.CreateRoleCommand{Name: "fixed:annotations:writer"}},
+		{desc: "should fail with only the custom prefix", cmd: accesscontrol.CreateRoleCommand{Name: "custom:"}},
+		{desc: "should fail with invalid uid", cmd: accesscontrol.CreateRoleCommand{Name: "custom:a", UID: "not a uid"}},
+		{desc: "should fail without action", cmd: accesscontrol.CreateRoleCommand{Name: "custom:a", Permissions: []accesscontrol.Permission{{Scope: "folders:*"}}}},
+		{desc: "should fail with wildcard action", cmd: accesscontrol.CreateRoleCommand{Name: "custom:a", Permissions: []accesscontrol.Permission{{Action: "folders:*"}}}},
+		{desc: "should fail with invalid scope", cmd: accesscontrol.CreateRoleCommand{Name: "custom:a", Permissions: []accesscontrol.Permission{{Action: "folders:read", Scope: "folders:uid*"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			tt.cmd.OrgID = 1
+			_, err := ac.CreateRole(ctx, tt.cmd)
+			require.ErrorIs(t, err, accesscontrol.ErrRoleInvalid)
+		})
+	}
+}
+
+func TestService_UpdateRole(t *testing.T) {
+	ctx := context.Background()
+	ac := setupTestEnv(t)
+
+	role, err := ac.CreateRole(ctx, accesscontrol.CreateRoleCommand{
+		OrgID:       1,
+		UID:         "writer",
+		Name:        "custom:annotations:writer",
+		Permissions: []accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsWrite, Scope: "folders:uid:x"}},
+	})
+	require.NoError(t, err)
+
+	t.Run("should fail when the version is not incremented", func(t *testing.T) {
+		_, err := ac.UpdateRole(ctx, accesscontrol.UpdateRoleCommand{OrgID: 1, UID: role.UID, Version: 1, Name: role.Name})
+		require.ErrorIs(t, err, accesscontrol.ErrRoleVersionMismatch)
+	})
+
+	t.Run("should replace permissions and increment version", func(t *testing.T) {
+		updated, err := ac.UpdateRole(ctx, accesscontrol.UpdateRoleCommand{
+			OrgID:       1,
+			UID:         role.UID,
+			Version:     2,
+			Name:        role.Name,
+			DisplayName: "Annotation writer",
+			Permissions: []accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsWrite, Scope: "folders:uid:y"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), updated.Version)
+
+		stored, err := ac.GetRole(ctx, accesscontrol.GetRoleQuery{OrgID: 1, UID: role.UID})
+		require.NoError(t, err)
+		require.Equal(t, "Annotation writer", stored.DisplayName)
+		require.Equal(t, "folders:uid:y", stored.Permissions[0].Scope)
+	})
+
+	t.Run("should fail for unknown role", func(t *testing.T) {
+		_, err := ac.UpdateRole(ctx, accesscontrol.UpdateRoleCommand{OrgID: 2, UID: role.UID, Name: role.Name})
+		require.ErrorIs(t, err, accesscontrol.ErrRoleNotFound)
+	})
+}
+
+func TestService_AddRoleAssignment(t *testing.T) {
+	ctx := context.Background()
+	ac := setupTestEnv(t)
+
+	_, err := ac.CreateRole(ctx, accesscontrol.CreateRoleCommand{OrgID: 1, UID: "writer", Name: "custom:writer"})
+	require.NoError(t, err)
+
+	err = ac.AddRoleAssignment(ctx, accesscontrol.RoleAssignmentCommand{OrgID: 1, RoleUID: "writer"})
+	require.ErrorIs(t, err, accesscontrol.ErrRoleAssignmentTarget)
+
+	err = ac.AddRoleAssignment(ctx, accesscontrol.RoleAssignmentCommand{OrgID: 1, RoleUID: "unknown", UserID: 1})
+	require.ErrorIs(t, err, accesscontrol.ErrRoleNotFound)
+}
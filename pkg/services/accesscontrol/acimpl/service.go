@@ -35,7 +35,7 @@ func ProvideService(cfg *setting.Cfg, store db.DB, routeRegister routing.RouteRe
 	service := ProvideOSSService(cfg, database.ProvideService(store), cache, features)
 
 	if !accesscontrol.IsDisabled(cfg) {
-		api.NewAccessControlAPI(routeRegister, accessControl, service, features).RegisterAPIEndpoints()
+		api.NewAccessControlAPI(routeRegister, accessControl, service, service, features).RegisterAPIEndpoints()
 		if err := accesscontrol.DeclareFixedRoles(service); err != nil {
 			return nil, err
 		}
@@ -62,6 +62,17 @@ type store interface {
 	SearchUsersPermissions(ctx context.Context, orgID int64, options accesscontrol.SearchOptions) (map[int64][]accesscontrol.Permission, error)
 	GetUsersBasicRoles(ctx context.Context, userFilter []int64, orgID int64) (map[int64][]string, error)
 	DeleteUserPermissions(ctx context.Context, orgID, userID int64) error
+	CreateRole(ctx context.Context, role *accesscontrol.RoleDTO) error
+	UpdateRole(ctx context.Context, role *accesscontrol.RoleDTO, previousVersion int64) error
+	DeleteRole(ctx context.Context, roleID int64) error
+	GetRole(ctx context.Context, orgID int64, uid string) (*accesscontrol.RoleDTO, error)
+	SearchRoles(ctx context.Context, query accesscontrol.SearchRolesQuery) ([]*accesscontrol.RoleDTO, error)
+	GetUserRoles(ctx context.Context, orgID, userID int64) ([]*accesscontrol.RoleDTO, error)
+	GetTeamRoles(ctx context.Context, orgID, teamID int64) ([]*accesscontrol.RoleDTO, error)
+	AddUserRole(ctx context.Context, orgID, userID, roleID int64) error
+	RemoveUserRole(ctx context.Context, orgID, userID, roleID int64) error
+	AddTeamRole(ctx context.Context, orgID, teamID, roleID int64) error
+	RemoveTeamRole(ctx context.Context, orgID, teamID, roleID int64) error
 }
 
 // Service is the service implementing role based access control.
@@ -91,11 +102,25 @@ func (s *Service) GetUserPermissions(ctx context.Context, user *user.SignedInUse
 	timer := prometheus.NewTimer(metrics.MAccessPermissionsSummary)
 	defer timer.ObserveDuration()
 
+	var (
+		permissions []accesscontrol.Permission
+		err         error
+	)
 	if !s.cfg.RBACPermissionCache || !user.HasUniqueId() {
-		return s.getUserPermissions(ctx, user, options)
+		permissions, err = s.getUserPermissions(ctx, user, options)
+	} else {
+		permissions, err = s.getCachedUserPermissions(ctx, user, options)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return s.getCachedUserPermissions(ctx, user, options)
+	// scoped service account tokens only get the part of the permissions they are restricted to,
+	// the cache keeps the permissions of the service account
+	if user.TokenPermissions != nil {
+		return accesscontrol.RestrictPermissions(permissions, user.TokenPermissions), nil
+	}
+	return permissions, nil
 }
 
 func (s *Service) getUserPermissions(ctx context.Context, user *user.SignedInUser, options accesscontrol.Options) ([]accesscontrol.Permission, error) {
@@ -107,11 +132,11 @@ func (s *Service) getUserPermissions(ctx context.Context, user *user.SignedInUse
 	}
 
 	dbPermissions, err := s.store.GetUserPermissions(ctx, accesscontrol.GetUserPermissionsQuery{
-		OrgID:      user.OrgID,
-		UserID:     user.UserID,
-		Roles:      accesscontrol.GetOrgRoles(user),
-		TeamIDs:    user.Teams,
-		RolePrefix: accesscontrol.ManagedRolePrefix,
+		OrgID:        user.OrgID,
+		UserID:       user.UserID,
+		Roles:        accesscontrol.GetOrgRoles(user),
+		TeamIDs:      user.Teams,
+		RolePrefixes: []string{accesscontrol.ManagedRolePrefix, accesscontrol.CustomRolePrefix},
 	})
 	if err != nil {
 		return nil, err
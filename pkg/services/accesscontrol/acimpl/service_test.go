@@ -740,3 +740,26 @@ func TestPermissionCacheKey(t *testing.T) {
 		})
 	}
 }
+
+func TestService_GetUserPermissionsScopedToken(t *testing.T) {
+	ac := setupTestEnv(t)
+	ac.registrations.Append(accesscontrol.RoleRegistration{
+		Role: accesscontrol.RoleDTO{
+			Name: "fixed:test:test",
+			Permissions: []accesscontrol.Permission{
+				{Action: "dashboards:read", Scope: "dashboards:*"},
+				{Action: "dashboards:write", Scope: "dashboards:*"},
+			},
+		},
+		Grants: []string{string(roletype.RoleEditor)},
+	})
+	require.NoError(t, ac.RegisterFixedRoles(context.Background()))
+
+	serviceAccount := &user.SignedInUser{UserID: 2, OrgID: 1, OrgRole: roletype.RoleEditor, IsServiceAccount: true}
+	serviceAccount.RestrictToToken(map[string][]string{"dashboards:write": {"dashboards:uid:ops"}})
+	require.Equal(t, roletype.RoleViewer, serviceAccount.OrgRole)
+
+	permissions, err := ac.GetUserPermissions(context.Background(), serviceAccount, accesscontrol.Options{})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []accesscontrol.Permission{{Action: "dashboards:write", Scope: "dashboards:uid:ops"}}, permissions)
+}
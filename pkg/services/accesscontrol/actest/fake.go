@@ -76,6 +76,8 @@ type FakeStore struct {
 	ExpectedUserPermissions  []accesscontrol.Permission
 	ExpectedUsersPermissions map[int64][]accesscontrol.Permission
 	ExpectedUsersRoles       map[int64][]string
+	ExpectedRole             *accesscontrol.RoleDTO
+	ExpectedRoles            []*accesscontrol.RoleDTO
 	ExpectedErr              error
 }
 
@@ -95,6 +97,94 @@ func (f FakeStore) DeleteUserPermissions(ctx context.Context, orgID, userID int6
 	return f.ExpectedErr
 }
 
+func (f FakeStore) CreateRole(ctx context.Context, role *accesscontrol.RoleDTO) error {
+	return f.ExpectedErr
+}
+
+func (f FakeStore) UpdateRole(ctx context.Context, role *accesscontrol.RoleDTO, previousVersion int64) error {
+	return f.ExpectedErr
+}
+
+func (f FakeStore) DeleteRole(ctx context.Context, roleID int64) error {
+	return f.ExpectedErr
+}
+
+func (f FakeStore) GetRole(ctx context.Context, orgID int64, uid string) (*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRole, f.ExpectedErr
+}
+
+func (f FakeStore) SearchRoles(ctx context.Context, query accesscontrol.SearchRolesQuery) ([]*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRoles, f.ExpectedErr
+}
+
+func (f FakeStore) GetUserRoles(ctx context.Context, orgID, userID int64) ([]*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRoles, f.ExpectedErr
+}
+
+func (f FakeStore) GetTeamRoles(ctx context.Context, orgID, teamID int64) ([]*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRoles, f.ExpectedErr
+}
+
+func (f FakeStore) AddUserRole(ctx context.Context, orgID, userID, roleID int64) error {
+	return f.ExpectedErr
+}
+
+func (f FakeStore) RemoveUserRole(ctx context.Context, orgID, userID, roleID int64) error {
+	return f.ExpectedErr
+}
+
+func (f FakeStore) AddTeamRole(ctx context.Context, orgID, teamID, roleID int64) error {
+	return f.ExpectedErr
+}
+
+func (f FakeStore) RemoveTeamRole(ctx context.Context, orgID, teamID, roleID int64) error {
+	return f.ExpectedErr
+}
+
+var _ accesscontrol.RoleService = new(FakeRoleService)
+
+type FakeRoleService struct {
+	ExpectedErr   error
+	ExpectedRole  *accesscontrol.RoleDTO
+	ExpectedRoles []*accesscontrol.RoleDTO
+}
+
+func (f FakeRoleService) CreateRole(ctx context.Context, cmd accesscontrol.CreateRoleCommand) (*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRole, f.ExpectedErr
+}
+
+func (f FakeRoleService) UpdateRole(ctx context.Context, cmd accesscontrol.UpdateRoleCommand) (*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRole, f.ExpectedErr
+}
+
+func (f FakeRoleService) DeleteRole(ctx context.Context, cmd accesscontrol.DeleteRoleCommand) error {
+	return f.ExpectedErr
+}
+
+func (f FakeRoleService) GetRole(ctx context.Context, query accesscontrol.GetRoleQuery) (*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRole, f.ExpectedErr
+}
+
+func (f FakeRoleService) SearchRoles(ctx context.Context, query accesscontrol.SearchRolesQuery) ([]*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRoles, f.ExpectedErr
+}
+
+func (f FakeRoleService) GetUserRoles(ctx context.Context, orgID, userID int64) ([]*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRoles, f.ExpectedErr
+}
+
+func (f FakeRoleService) GetTeamRoles(ctx context.Context, orgID, teamID int64) ([]*accesscontrol.RoleDTO, error) {
+	return f.ExpectedRoles, f.ExpectedErr
+}
+
+func (f FakeRoleService) AddRoleAssignment(ctx context.Context, cmd accesscontrol.RoleAssignmentCommand) error {
+	return f.ExpectedErr
+}
+
+func (f FakeRoleService) RemoveRoleAssignment(ctx context.Context, cmd accesscontrol.RoleAssignmentCommand) error {
+	return f.ExpectedErr
+}
+
 var _ accesscontrol.PermissionsService = new(FakePermissionsService)
 
 type FakePermissionsService struct {
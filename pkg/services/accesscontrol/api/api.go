@@ -14,10 +14,11 @@ import (
 )
 
 func NewAccessControlAPI(router routing.RouteRegister, accesscontrol ac.AccessControl, service ac.Service,
-	features *featuremgmt.FeatureManager) *AccessControlAPI {
+	roleService ac.RoleService, features *featuremgmt.FeatureManager) *AccessControlAPI {
 	return &AccessControlAPI{
 		RouteRegister: router,
 		Service:       service,
+		RoleService:   roleService,
 		AccessControl: accesscontrol,
 		features:      features,
 	}
@@ -25,6 +26,7 @@ func NewAccessControlAPI(router routing.RouteRegister, accesscontrol ac.AccessCo
 
 type AccessControlAPI struct {
 	Service       ac.Service
+	RoleService   ac.RoleService
 	AccessControl ac.AccessControl
 	RouteRegister routing.RouteRegister
 	features      *featuremgmt.FeatureManager
@@ -44,6 +46,33 @@ func (api *AccessControlAPI) RegisterAPIEndpoints() {
 				ac.EvalPermission(ac.ActionUsersPermissionsRead, userIDScope)), routing.Wrap(api.searchUserPermissions))
 		}
 	})
+	// Custom roles
+	api.RouteRegister.Group("/api/access-control", func(rr routing.RouteRegister) {
+		rr.Get("/roles", authorize(middleware.ReqSignedIn, ac.EvalPermission(ac.ActionRolesRead)), routing.Wrap(api.searchRoles))
+		rr.Post("/roles", authorize(middleware.ReqSignedIn, ac.EvalPermission(ac.ActionRolesWrite)), routing.Wrap(api.createRole))
+		rr.Get("/roles/:roleUID", authorize(middleware.ReqSignedIn,
+			ac.EvalPermission(ac.ActionRolesRead, ac.ScopeRolesUID)), routing.Wrap(api.getRole))
+		rr.Put("/roles/:roleUID", authorize(middleware.ReqSignedIn,
+			ac.EvalPermission(ac.ActionRolesWrite, ac.ScopeRolesUID)), routing.Wrap(api.updateRole))
+		rr.Delete("/roles/:roleUID", authorize(middleware.ReqSignedIn,
+			ac.EvalPermission(ac.ActionRolesDelete, ac.ScopeRolesUID)), routing.Wrap(api.deleteRole))
+
+		// Service accounts are assigned roles with their user ID
+		userIDScope := ac.Scope("users", "id", ac.Parameter(":userId"))
+		rr.Get("/users/:userId/roles", authorize(middleware.ReqSignedIn,
+			ac.EvalPermission(ac.ActionUsersRolesRead, userIDScope)), routing.Wrap(api.getUserRoles))
+		rr.Post("/users/:userId/roles", authorize(middleware.ReqSignedIn,
+			ac.EvalPermission(ac.ActionUsersRolesAdd, userIDScope)), routing.Wrap(api.addUserRole))
+		rr.Delete("/users/:userId/roles/:roleUID", authorize(middleware.ReqSignedIn,
+			ac.EvalPermission(ac.ActionUsersRolesRemove, userIDScope)), routing.Wrap(api.removeUserRole))
+
+		rr.Get("/teams/:teamId/roles", authorize(middleware.ReqSignedIn,
+			ac.EvalPermission(ac.ActionTeamsRolesRead, ac.ScopeTeamsID)), routing.Wrap(api.getTeamRoles))
+		rr.Post("/teams/:teamId/roles", authorize(middleware.ReqSignedIn,
+			ac.EvalPermission(ac.ActionTeamsRolesAdd, ac.ScopeTeamsID)), routing.Wrap(api.addTeamRole))
+		rr.Delete("/teams/:teamId/roles/:roleUID", authorize(middleware.ReqSignedIn,
+			ac.EvalPermission(ac.ActionTeamsRolesRemove, ac.ScopeTeamsID)), routing.Wrap(api.removeTeamRole))
+	})
 }
 
 // GET /api/access-control/user/actions
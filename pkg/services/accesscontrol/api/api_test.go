@@ -1,8 +1,10 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -40,7 +42,7 @@ func TestAPI_getUserActions(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
 			acSvc := actest.FakeService{ExpectedPermissions: tt.permissions}
-			api := NewAccessControlAPI(routing.NewRouteRegister(), actest.FakeAccessControl{}, acSvc, featuremgmt.WithFeatures())
+			api := NewAccessControlAPI(routing.NewRouteRegister(), actest.FakeAccessControl{}, acSvc, actest.FakeRoleService{}, featuremgmt.WithFeatures())
 			api.RegisterAPIEndpoints()
 
 			server := webtest.NewServer(t, api.RouteRegister)
@@ -93,7 +95,7 @@ func TestAPI_getUserPermissions(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
 			acSvc := actest.FakeService{ExpectedPermissions: tt.permissions}
-			api := NewAccessControlAPI(routing.NewRouteRegister(), actest.FakeAccessControl{}, acSvc, featuremgmt.WithFeatures())
+			api := NewAccessControlAPI(routing.NewRouteRegister(), actest.FakeAccessControl{}, acSvc, actest.FakeRoleService{}, featuremgmt.WithFeatures())
 			api.RegisterAPIEndpoints()
 
 			server := webtest.NewServer(t, api.RouteRegister)
@@ -118,3 +120,78 @@ func TestAPI_getUserPermissions(t *testing.T) {
 		})
 	}
 }
+
+// userPermissionsAccessControl evaluates against the permissions of the signed in user
+type userPermissionsAccessControl struct {
+	actest.FakeAccessControl
+}
+
+func (a userPermissionsAccessControl) Evaluate(ctx context.Context, user *user.SignedInUser, evaluator ac.Evaluator) (bool, error) {
+	return evaluator.Evaluate(user.Permissions[user.OrgID]), nil
+}
+
+func TestAPI_createRole(t *testing.T) {
+	type testCase struct {
+		desc           string
+		body           string
+		permissions    map[string][]string
+		isGrafanaAdmin bool
+		expectedCode   int
+	}
+
+	roleWriter := map[string][]string{ac.ActionRolesWrite: {ac.ScopeRolesAll}}
+	tests := []testCase{
+		{
+			desc:         "should create role with permissions the user has",
+			body:         `{"name": "custom:annotations:writer", "permissions": [{"action": "annotations:write", "scope": "folders:uid:x"}]}`,
+			permissions:  map[string][]string{ac.ActionRolesWrite: {ac.ScopeRolesAll}, ac.ActionAnnotationsWrite: {"folders:*"}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should not create role with permissions the user does not have",
+			body:         `{"name": "custom:annotations:writer", "permissions": [{"action": "annotations:write", "scope": "folders:uid:x"}]}`,
+			permissions:  map[string][]string{ac.ActionRolesWrite: {ac.ScopeRolesAll}, ac.ActionAnnotationsWrite: {"folders:uid:y"}},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "should not create global role when user is not Grafana admin",
+			body:         `{"name": "custom:annotations:writer", "global": true}`,
+			permissions:  roleWriter,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:           "should create global role when user is Grafana admin",
+			body:           `{"name": "custom:annotations:writer", "global": true}`,
+			permissions:    roleWriter,
+			isGrafanaAdmin: true,
+			expectedCode:   http.StatusOK,
+		},
+		{
+			desc:         "should not create role without roles:write",
+			body:         `{"name": "custom:annotations:writer"}`,
+			permissions:  map[string][]string{},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			roleSvc := actest.FakeRoleService{ExpectedRole: &ac.RoleDTO{UID: "role", Name: "custom:annotations:writer"}}
+			api := NewAccessControlAPI(routing.NewRouteRegister(), userPermissionsAccessControl{}, actest.FakeService{}, roleSvc, featuremgmt.WithFeatures())
+			api.RegisterAPIEndpoints()
+
+			server := webtest.NewServer(t, api.RouteRegister)
+			req := server.NewPostRequest("/api/access-control/roles", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{
+				OrgID:          1,
+				IsGrafanaAdmin: tt.isGrafanaAdmin,
+				Permissions:    map[int64]map[string][]string{1: tt.permissions},
+			})
+			res, err := server.Send(req)
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+			require.Equal(t, tt.expectedCode, res.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+type addRoleAssignmentCommand struct {
+	RoleUID string `json:"roleUid"`
+}
+
+// GET /api/access-control/roles
+func (api *AccessControlAPI) searchRoles(c *contextmodel.ReqContext) response.Response {
+	roles, err := api.RoleService.SearchRoles(c.Req.Context(), ac.SearchRolesQuery{
+		OrgID:         c.OrgID,
+		ExcludeGlobal: c.QueryBool("excludeGlobal"),
+		Query:         c.Query("query"),
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to search roles", err)
+	}
+
+	return response.JSON(http.StatusOK, roles)
+}
+
+// POST /api/access-control/roles
+func (api *AccessControlAPI) createRole(c *contextmodel.ReqContext) response.Response {
+	cmd := ac.CreateRoleCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.OrgID
+
+	if cmd.Global && !c.SignedInUser.IsGrafanaAdmin {
+		return response.Error(http.StatusForbidden, "Only Grafana administrators can create global roles", nil)
+	}
+	if resp := api.checkCanDelegate(c, cmd.Permissions); resp != nil {
+		return resp
+	}
+
+	role, err := api.RoleService.CreateRole(c.Req.Context(), cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create role", err)
+	}
+
+	return response.JSON(http.StatusOK, role)
+}
+
+// GET /api/access-control/roles/:roleUID
+func (api *AccessControlAPI) getRole(c *contextmodel.ReqContext) response.Response {
+	role, err := api.RoleService.GetRole(c.Req.Context(), ac.GetRoleQuery{OrgID: c.OrgID, UID: web.Params(c.Req)[":roleUID"]})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get role", err)
+	}
+
+	return response.JSON(http.StatusOK, role)
+}
+
+// PUT /api/access-control/roles/:roleUID
+func (api *AccessControlAPI) updateRole(c *contextmodel.ReqContext) response.Response {
+	cmd := ac.UpdateRoleCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.OrgID
+	cmd.UID = web.Params(c.Req)[":roleUID"]
+
+	if _, resp := api.getModifiableRole(c, cmd.UID); resp != nil {
+		return resp
+	}
+	if resp := api.checkCanDelegate(c, cmd.Permissions); resp != nil {
+		return resp
+	}
+
+	role, err := api.RoleService.UpdateRole(c.Req.Context(), cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update role", err)
+	}
+
+	return response.JSON(http.StatusOK, role)
+}
+
+// DELETE /api/access-control/roles/:roleUID
+func (api *AccessControlAPI) deleteRole(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":roleUID"]
+	if _, resp := api.getModifiableRole(c, uid); resp != nil {
+		return resp
+	}
+
+	if err := api.RoleService.DeleteRole(c.Req.Context(), ac.DeleteRoleCommand{OrgID: c.OrgID, UID: uid}); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete role", err)
+	}
+
+	return response.Success("Role deleted")
+}
+
+// GET /api/access-control/users/:userId/roles
+func (api *AccessControlAPI) getUserRoles(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":userId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "user ID is invalid", err)
+	}
+
+	roles, err := api.RoleService.GetUserRoles(c.Req.Context(), c.OrgID, userID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get user roles", err)
+	}
+
+	return response.JSON(http.StatusOK, roles)
+}
+
+// POST /api/access-control/users/:userId/roles
+func (api *AccessControlAPI) addUserRole(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":userId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "user ID is invalid", err)
+	}
+
+	cmd := addRoleAssignmentCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	return api.changeRoleAssignment(c, ac.RoleAssignmentCommand{OrgID: c.OrgID, RoleUID: cmd.RoleUID, UserID: userID}, true)
+}
+
+// DELETE /api/access-control/users/:userId/roles/:roleUID
+func (api *AccessControlAPI) removeUserRole(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":userId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "user ID is invalid", err)
+	}
+
+	return api.changeRoleAssignment(c, ac.RoleAssignmentCommand{OrgID: c.OrgID, RoleUID: web.Params(c.Req)[":roleUID"], UserID: userID}, false)
+}
+
+// GET /api/access-control/teams/:teamId/roles
+func (api *AccessControlAPI) getTeamRoles(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "team ID is invalid", err)
+	}
+
+	roles, err := api.RoleService.GetTeamRoles(c.Req.Context(), c.OrgID, teamID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get team roles", err)
+	}
+
+	return response.JSON(http.StatusOK, roles)
+}
+
+// POST /api/access-control/teams/:teamId/roles
+func (api *AccessControlAPI) addTeamRole(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "team ID is invalid", err)
+	}
+
+	cmd := addRoleAssignmentCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	return api.changeRoleAssignment(c, ac.RoleAssignmentCommand{OrgID: c.OrgID, RoleUID: cmd.RoleUID, TeamID: teamID}, true)
+}
+
+// DELETE /api/access-control/teams/:teamId/roles/:roleUID
+func (api *AccessControlAPI) removeTeamRole(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "team ID is invalid", err)
+	}
+
+	return api.changeRoleAssignment(c, ac.RoleAssignmentCommand{OrgID: c.OrgID, RoleUID: web.Params(c.Req)[":roleUID"], TeamID: teamID}, false)
+}
+
+// changeRoleAssignment adds or removes a role assignment when the signed in user holds all the permissions of the role
+func (api *AccessControlAPI) changeRoleAssignment(c *contextmodel.ReqContext, cmd ac.RoleAssignmentCommand, add bool) response.Response {
+	role, err := api.RoleService.GetRole(c.Req.Context(), ac.GetRoleQuery{OrgID: cmd.OrgID, UID: cmd.RoleUID})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get role", err)
+	}
+	if resp := api.checkCanDelegate(c, role.Permissions); resp != nil {
+		return resp
+	}
+
+	if add {
+		if err := api.RoleService.AddRoleAssignment(c.Req.Context(), cmd); err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "Failed to add role", err)
+		}
+		return response.Success("Role added")
+	}
+
+	if err := api.RoleService.RemoveRoleAssignment(c.Req.Context(), cmd); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to remove role", err)
+	}
+	return response.Success("Role removed")
+}
+
+// getModifiableRole returns the role when the signed in user can change it: global roles can only be
+// changed by Grafana administrators and users can only change roles they could have created
+func (api *AccessControlAPI) getModifiableRole(c *contextmodel.ReqContext, uid string) (*ac.RoleDTO, response.Response) {
+	role, err := api.RoleService.GetRole(c.Req.Context(), ac.GetRoleQuery{OrgID: c.OrgID, UID: uid})
+	if err != nil {
+		return nil, response.ErrOrFallback(http.StatusInternalServerError, "Failed to get role", err)
+	}
+	if role.Global() && !c.SignedInUser.IsGrafanaAdmin {
+		return nil, response.Error(http.StatusForbidden, "Only Grafana administrators can change global roles", nil)
+	}
+	if resp := api.checkCanDelegate(c, role.Permissions); resp != nil {
+		return nil, resp
+	}
+	return role, nil
+}
+
+// checkCanDelegate prevents users from granting permissions they do not have
+func (api *AccessControlAPI) checkCanDelegate(c *contextmodel.ReqContext, permissions []ac.Permission) response.Response {
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	evaluators := make([]ac.Evaluator, 0, len(permissions))
+	for _, p := range permissions {
+		if p.Scope == "" {
+			evaluators = append(evaluators, ac.EvalPermission(p.Action))
+		} else {
+			evaluators = append(evaluators, ac.EvalPermission(p.Action, p.Scope))
+		}
+	}
+
+	hasAccess, err := api.AccessControl.Evaluate(c.Req.Context(), c.SignedInUser, ac.EvalAll(evaluators...))
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to evaluate permissions", err)
+	}
+	if !hasAccess {
+		return response.Error(http.StatusForbidden, "Cannot grant permissions you do not have", nil)
+	}
+	return nil
+}
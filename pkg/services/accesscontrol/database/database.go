@@ -35,9 +35,11 @@ func (s *AccessControlStore) GetUserPermissions(ctx context.Context, query acces
 			INNER JOIN role ON role.id = permission.role_id
 		` + filter
 
-		if query.RolePrefix != "" {
-			q += " WHERE role.name LIKE ?"
-			params = append(params, query.RolePrefix+"%")
+		if len(query.RolePrefixes) > 0 {
+			q += " WHERE (role.name LIKE ?" + strings.Repeat(" OR role.name LIKE ?", len(query.RolePrefixes)-1) + ")"
+			for _, prefix := range query.RolePrefixes {
+				params = append(params, prefix+"%")
+			}
 		}
 
 		if err := sess.SQL(q, params...).Find(&result); err != nil {
@@ -0,0 +1,308 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+// CreateRole stores a custom role and its permissions
+func (s *AccessControlStore) CreateRole(ctx context.Context, role *accesscontrol.RoleDTO) error {
+	return s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if err := checkRoleUnique(sess, role); err != nil {
+			return err
+		}
+
+		r := role.Role()
+		if _, err := sess.Insert(&r); err != nil {
+			return err
+		}
+		role.ID = r.ID
+
+		return insertRolePermissions(sess, role.ID, role.Permissions)
+	})
+}
+
+// UpdateRole replaces the attributes and permissions of a custom role, the update only succeeds
+// when the stored role is still at previousVersion
+func (s *AccessControlStore) UpdateRole(ctx context.Context, role *accesscontrol.RoleDTO, previousVersion int64) error {
+	return s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if err := checkRoleUnique(sess, role); err != nil {
+			return err
+		}
+
+		r := role.Role()
+		affected, err := sess.ID(role.ID).Where("version = ?", previousVersion).
+			Cols("name", "display_name", "description", "group_name", "hidden", "version", "updated").
+			Update(&r)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return accesscontrol.ErrRoleVersionMismatch.Errorf("UpdateRole: role %s changed since version %d", role.UID, previousVersion)
+		}
+
+		if _, err := sess.Exec("DELETE FROM permission WHERE role_id = ?", role.ID); err != nil {
+			return err
+		}
+		return insertRolePermissions(sess, role.ID, role.Permissions)
+	})
+}
+
+// DeleteRole removes a role with its permissions and its assignments
+func (s *AccessControlStore) DeleteRole(ctx context.Context, roleID int64) error {
+	return s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for _, table := range []string{"permission", "user_role", "team_role", "builtin_role"} {
+			if _, err := sess.Exec("DELETE FROM "+table+" WHERE role_id = ?", roleID); err != nil {
+				return err
+			}
+		}
+		_, err := sess.Exec("DELETE FROM role WHERE id = ?", roleID)
+		return err
+	})
+}
+
+// GetRole returns a custom role of the organization, or a global custom role, by uid
+func (s *AccessControlStore) GetRole(ctx context.Context, orgID int64, uid string) (*accesscontrol.RoleDTO, error) {
+	var role *accesscontrol.RoleDTO
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		rows := make([]accesscontrol.Role, 0)
+		if err := sess.
+			Where("uid = ? AND (org_id = ? OR org_id = ?) AND name LIKE ?", uid, orgID, accesscontrol.GlobalOrgID, accesscontrol.CustomRolePrefix+"%").
+			Find(&rows); err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return accesscontrol.ErrRoleNotFound.Errorf("GetRole: role not found by uid: %s", uid)
+		}
+
+		roles, err := withPermissions(sess, rows[:1])
+		if err != nil {
+			return err
+		}
+		role = roles[0]
+		return nil
+	})
+	return role, err
+}
+
+// SearchRoles returns the custom roles of the organization, and the global custom roles unless excluded
+func (s *AccessControlStore) SearchRoles(ctx context.Context, query accesscontrol.SearchRolesQuery) ([]*accesscontrol.RoleDTO, error) {
+	var roles []*accesscontrol.RoleDTO
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("name LIKE ?", accesscontrol.CustomRolePrefix+"%")
+		if query.ExcludeGlobal {
+			q = q.And("org_id = ?", query.OrgID)
+		} else {
+			q = q.And("(org_id = ? OR org_id = ?)", query.OrgID, accesscontrol.GlobalOrgID)
+		}
+		if query.Query != "" {
+			like := "%" + query.Query + "%"
+			q = q.And("(name "+s.sql.GetDialect().LikeStr()+" ? OR display_name "+s.sql.GetDialect().LikeStr()+" ?)", like, like)
+		}
+		rows := make([]accesscontrol.Role, 0)
+		if err := q.Asc("name").Find(&rows); err != nil {
+			return err
+		}
+
+		var err error
+		roles, err = withPermissions(sess, rows)
+		return err
+	})
+	return roles, err
+}
+
+// GetUserRoles returns the custom roles assigned to the user in the organization
+func (s *AccessControlStore) GetUserRoles(ctx context.Context, orgID, userID int64) ([]*accesscontrol.RoleDTO, error) {
+	var roles []*accesscontrol.RoleDTO
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		rows := make([]accesscontrol.Role, 0)
+		if err := sess.Table("role").
+			Join("INNER", "user_role", "user_role.role_id = role.id").
+			Where("user_role.user_id = ? AND (user_role.org_id = ? OR user_role.org_id = ?) AND role.name LIKE ?",
+				userID, orgID, accesscontrol.GlobalOrgID, accesscontrol.CustomRolePrefix+"%").
+			Asc("role.name").
+			Cols("role.*").
+			Find(&rows); err != nil {
+			return err
+		}
+
+		var err error
+		roles, err = withPermissions(sess, rows)
+		return err
+	})
+	return roles, err
+}
+
+// GetTeamRoles returns the custom roles assigned to the team
+func (s *AccessControlStore) GetTeamRoles(ctx context.Context, orgID, teamID int64) ([]*accesscontrol.RoleDTO, error) {
+	var roles []*accesscontrol.RoleDTO
+	err := s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		rows := make([]accesscontrol.Role, 0)
+		if err := sess.Table("role").
+			Join("INNER", "team_role", "team_role.role_id = role.id").
+			Where("team_role.team_id = ? AND team_role.org_id = ? AND role.name LIKE ?",
+				teamID, orgID, accesscontrol.CustomRolePrefix+"%").
+			Asc("role.name").
+			Cols("role.*").
+			Find(&rows); err != nil {
+			return err
+		}
+
+		var err error
+		roles, err = withPermissions(sess, rows)
+		return err
+	})
+	return roles, err
+}
+
+// AddUserRole assigns a role to a user, or service account, of the organization
+func (s *AccessControlStore) AddUserRole(ctx context.Context, orgID, userID, roleID int64) error {
+	return s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if res, err := sess.Query("SELECT 1 FROM org_user WHERE org_id = ? AND user_id = ?", orgID, userID); err != nil {
+			return err
+		} else if len(res) == 0 {
+			return accesscontrol.ErrRoleAssignmentTarget.Errorf("AddUserRole: user %d not found in org %d", userID, orgID)
+		}
+
+		if res, err := sess.Query("SELECT 1 FROM user_role WHERE org_id = ? AND user_id = ? AND role_id = ?", orgID, userID, roleID); err != nil {
+			return err
+		} else if len(res) > 0 {
+			return accesscontrol.ErrRoleAlreadyAssigned.Errorf("AddUserRole: role %d already assigned to user %d", roleID, userID)
+		}
+
+		_, err := sess.Insert(&accesscontrol.UserRole{OrgID: orgID, UserID: userID, RoleID: roleID, Created: time.Now()})
+		return err
+	})
+}
+
+// RemoveUserRole removes a role from a user of the organization
+func (s *AccessControlStore) RemoveUserRole(ctx context.Context, orgID, userID, roleID int64) error {
+	return s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM user_role WHERE org_id = ? AND user_id = ? AND role_id = ?", orgID, userID, roleID)
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err != nil {
+			return err
+		} else if affected == 0 {
+			return accesscontrol.ErrRoleNotFound.Errorf("RemoveUserRole: role %d not assigned to user %d", roleID, userID)
+		}
+		return nil
+	})
+}
+
+// AddTeamRole assigns a role to a team of the organization
+func (s *AccessControlStore) AddTeamRole(ctx context.Context, orgID, teamID, roleID int64) error {
+	return s.sql.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if res, err := sess.Query("SELECT 1 FROM team WHERE org_id = ? AND id = ?", orgID, teamID); err != nil {
+			return err
+		} else if len(res) == 0 {
+			return accesscontrol.ErrRoleAssignmentTarget.Errorf("AddTeamRole: team %d not found in org %d", teamID, orgID)
+		}
+
+		if res, err := sess.Query("SELECT 1 FROM team_role WHERE org_id = ? AND team_id = ? AND role_id = ?", orgID, teamID, roleID); err != nil {
+			return err
+		} else if len(res) > 0 {
+			return accesscontrol.ErrRoleAlreadyAssigned.Errorf("AddTeamRole: role %d already assigned to team %d", roleID, teamID)
+		}
+
+		_, err := sess.Insert(&accesscontrol.TeamRole{OrgID: orgID, TeamID: teamID, RoleID: roleID, Created: time.Now()})
+		return err
+	})
+}
+
+// RemoveTeamRole removes a role from a team of the organization
+func (s *AccessControlStore) RemoveTeamRole(ctx context.Context, orgID, teamID, roleID int64) error {
+	return s.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM team_role WHERE org_id = ? AND team_id = ? AND role_id = ?", orgID, teamID, roleID)
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err != nil {
+			return err
+		} else if affected == 0 {
+			return accesscontrol.ErrRoleNotFound.Errorf("RemoveTeamRole: role %d not assigned to team %d", roleID, teamID)
+		}
+		return nil
+	})
+}
+
+// checkRoleUnique checks that no other role uses the uid of the role in its organization or globally,
+// and that no other role of its organization uses its name
+func checkRoleUnique(sess *db.Session, role *accesscontrol.RoleDTO) error {
+	existing := make([]accesscontrol.Role, 0)
+	q := sess.Table("role").Where("id <> ?", role.ID)
+	if role.Global() {
+		q = q.And("(uid = ? OR (org_id = ? AND name = ?))", role.UID, role.OrgID, role.Name)
+	} else {
+		q = q.And("((uid = ? AND (org_id = ? OR org_id = ?)) OR (org_id = ? AND name = ?))",
+			role.UID, role.OrgID, accesscontrol.GlobalOrgID, role.OrgID, role.Name)
+	}
+	if err := q.Find(&existing); err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return accesscontrol.ErrRoleAlreadyExists.Errorf("role with uid %s or name %s already exists", role.UID, role.Name)
+	}
+	return nil
+}
+
+func insertRolePermissions(sess *db.Session, roleID int64, permissions []accesscontrol.Permission) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	toInsert := make([]accesscontrol.Permission, 0, len(permissions))
+	for _, p := range permissions {
+		toInsert = append(toInsert, accesscontrol.Permission{RoleID: roleID, Action: p.Action, Scope: p.Scope, Created: now, Updated: now})
+	}
+	_, err := sess.InsertMulti(&toInsert)
+	return err
+}
+
+// withPermissions converts the roles to DTOs holding their permissions
+func withPermissions(sess *db.Session, rows []accesscontrol.Role) ([]*accesscontrol.RoleDTO, error) {
+	roles := make([]*accesscontrol.RoleDTO, 0, len(rows))
+	if len(rows) == 0 {
+		return roles, nil
+	}
+
+	byID := make(map[int64]*accesscontrol.RoleDTO, len(rows))
+	params := make([]interface{}, 0, len(rows))
+	for _, r := range rows {
+		role := &accesscontrol.RoleDTO{
+			ID:          r.ID,
+			OrgID:       r.OrgID,
+			Version:     r.Version,
+			UID:         r.UID,
+			Name:        r.Name,
+			DisplayName: r.DisplayName,
+			Description: r.Description,
+			Group:       r.Group,
+			Hidden:      r.Hidden,
+			Permissions: make([]accesscontrol.Permission, 0),
+			Created:     r.Created,
+			Updated:     r.Updated,
+		}
+		roles = append(roles, role)
+		byID[role.ID] = role
+		params = append(params, role.ID)
+	}
+
+	permissions := make([]accesscontrol.Permission, 0)
+	if err := sess.SQL("SELECT * FROM permission WHERE role_id IN (?"+strings.Repeat(",?", len(params)-1)+") ORDER BY action, scope", params...).
+		Find(&permissions); err != nil {
+		return nil, err
+	}
+	for _, p := range permissions {
+		if role, ok := byID[p.RoleID]; ok {
+			role.Permissions = append(role.Permissions, p)
+		}
+	}
+	return roles, nil
+}
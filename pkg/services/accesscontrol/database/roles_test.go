@@ -0,0 +1,157 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+func TestIntegrationAccessControlStore_CustomRoles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	store, _, userSvc, teamSvc, _ := setupTestEnv(t)
+	user, team := createUserAndTeam(t, userSvc, teamSvc, 1)
+
+	newRole := func(orgID int64, uid, name string, permissions ...accesscontrol.Permission) *accesscontrol.RoleDTO {
+		return &accesscontrol.RoleDTO{
+			OrgID:       orgID,
+			UID:         uid,
+			Name:        name,
+			Version:     1,
+			Permissions: permissions,
+			Created:     time.Now(),
+			Updated:     time.Now(),
+		}
+	}
+
+	writer := newRole(1, "annotation-writer", "custom:annotations:writer",
+		accesscontrol.Permission{Action: accesscontrol.ActionAnnotationsWrite, Scope: "folders:uid:x"},
+		accesscontrol.Permission{Action: accesscontrol.ActionAnnotationsCreate, Scope: "folders:uid:x"},
+	)
+	require.NoError(t, store.CreateRole(ctx, writer))
+	require.NotZero(t, writer.ID)
+
+	global := newRole(accesscontrol.GlobalOrgID, "stats-reader", "custom:stats:reader",
+		accesscontrol.Permission{Action: accesscontrol.ActionServerStatsRead},
+	)
+	require.NoError(t, store.CreateRole(ctx, global))
+
+	t.Run("uid and name should be unique", func(t *testing.T) {
+		err := store.CreateRole(ctx, newRole(1, "annotation-writer", "custom:other"))
+		require.ErrorIs(t, err, accesscontrol.ErrRoleAlreadyExists)
+		err = store.CreateRole(ctx, newRole(1, "stats-reader", "custom:other"))
+		require.ErrorIs(t, err, accesscontrol.ErrRoleAlreadyExists)
+		err = store.CreateRole(ctx, newRole(1, "other", "custom:annotations:writer"))
+		require.ErrorIs(t, err, accesscontrol.ErrRoleAlreadyExists)
+		require.NoError(t, store.CreateRole(ctx, newRole(2, "other", "custom:annotations:writer")))
+	})
+
+	t.Run("should get roles of the organization and global roles", func(t *testing.T) {
+		role, err := store.GetRole(ctx, 1, "annotation-writer")
+		require.NoError(t, err)
+		require.Equal(t, "custom:annotations:writer", role.Name)
+		require.Len(t, role.Permissions, 2)
+
+		role, err = store.GetRole(ctx, 2, "stats-reader")
+		require.NoError(t, err)
+		require.True(t, role.Global())
+
+		_, err = store.GetRole(ctx, 2, "annotation-writer")
+		require.ErrorIs(t, err, accesscontrol.ErrRoleNotFound)
+
+		roles, err := store.SearchRoles(ctx, accesscontrol.SearchRolesQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, roles, 2)
+
+		roles, err = store.SearchRoles(ctx, accesscontrol.SearchRolesQuery{OrgID: 1, ExcludeGlobal: true})
+		require.NoError(t, err)
+		require.Len(t, roles, 1)
+
+		roles, err = store.SearchRoles(ctx, accesscontrol.SearchRolesQuery{OrgID: 1, Query: "stats"})
+		require.NoError(t, err)
+		require.Len(t, roles, 1)
+		require.Equal(t, "stats-reader", roles[0].UID)
+	})
+
+	t.Run("should only update the expected version", func(t *testing.T) {
+		writer.Version = 2
+		writer.Description = "Write annotations in folder x"
+		writer.Permissions = []accesscontrol.Permission{{Action: accesscontrol.ActionAnnotationsWrite, Scope: "folders:uid:x"}}
+
+		err := store.UpdateRole(ctx, writer, 5)
+		require.ErrorIs(t, err, accesscontrol.ErrRoleVersionMismatch)
+
+		require.NoError(t, store.UpdateRole(ctx, writer, 1))
+		role, err := store.GetRole(ctx, 1, "annotation-writer")
+		require.NoError(t, err)
+		require.Equal(t, int64(2), role.Version)
+		require.Equal(t, "Write annotations in folder x", role.Description)
+		require.Len(t, role.Permissions, 1)
+	})
+
+	t.Run("should assign roles to users and teams", func(t *testing.T) {
+		require.NoError(t, store.AddUserRole(ctx, 1, user.ID, global.ID))
+		require.ErrorIs(t, store.AddUserRole(ctx, 1, user.ID, global.ID), accesscontrol.ErrRoleAlreadyAssigned)
+		require.ErrorIs(t, store.AddUserRole(ctx, 2, user.ID, global.ID), accesscontrol.ErrRoleAssignmentTarget)
+		require.NoError(t, store.AddTeamRole(ctx, 1, team.ID, writer.ID))
+		require.ErrorIs(t, store.AddTeamRole(ctx, 2, team.ID, writer.ID), accesscontrol.ErrRoleAssignmentTarget)
+
+		roles, err := store.GetUserRoles(ctx, 1, user.ID)
+		require.NoError(t, err)
+		require.Len(t, roles, 1)
+		require.Equal(t, "stats-reader", roles[0].UID)
+
+		roles, err = store.GetTeamRoles(ctx, 1, team.ID)
+		require.NoError(t, err)
+		require.Len(t, roles, 1)
+		require.Equal(t, "annotation-writer", roles[0].UID)
+
+		permissions, err := store.GetUserPermissions(ctx, accesscontrol.GetUserPermissionsQuery{
+			OrgID:        1,
+			UserID:       user.ID,
+			TeamIDs:      []int64{team.ID},
+			RolePrefixes: []string{accesscontrol.ManagedRolePrefix, accesscontrol.CustomRolePrefix},
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []accesscontrol.Permission{
+			{Action: accesscontrol.ActionServerStatsRead, Scope: ""},
+			{Action: accesscontrol.ActionAnnotationsWrite, Scope: "folders:uid:x"},
+		}, permissions)
+
+		permissions, err = store.GetUserPermissions(ctx, accesscontrol.GetUserPermissionsQuery{
+			OrgID:        1,
+			UserID:       user.ID,
+			TeamIDs:      []int64{team.ID},
+			RolePrefixes: []string{accesscontrol.ManagedRolePrefix},
+		})
+		require.NoError(t, err)
+		require.Empty(t, permissions)
+	})
+
+	t.Run("should remove assignments", func(t *testing.T) {
+		require.NoError(t, store.RemoveUserRole(ctx, 1, user.ID, global.ID))
+		require.ErrorIs(t, store.RemoveUserRole(ctx, 1, user.ID, global.ID), accesscontrol.ErrRoleNotFound)
+
+		roles, err := store.GetUserRoles(ctx, 1, user.ID)
+		require.NoError(t, err)
+		require.Empty(t, roles)
+	})
+
+	t.Run("should delete roles with their assignments", func(t *testing.T) {
+		require.NoError(t, store.DeleteRole(ctx, writer.ID))
+
+		_, err := store.GetRole(ctx, 1, "annotation-writer")
+		require.ErrorIs(t, err, accesscontrol.ErrRoleNotFound)
+
+		roles, err := store.GetTeamRoles(ctx, 1, team.ID)
+		require.NoError(t, err)
+		require.Empty(t, roles)
+	})
+}
@@ -3,6 +3,8 @@ package accesscontrol
 import (
 	"errors"
 	"fmt"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 var (
@@ -13,6 +15,15 @@ var (
 	ErrPluginIDRequired       = errors.New("plugin ID is required")
 )
 
+var (
+	ErrRoleNotFound         = errutil.NewBase(errutil.StatusNotFound, "accesscontrol.roleNotFound", errutil.WithPublicMessage("role not found"))
+	ErrRoleAlreadyExists    = errutil.NewBase(errutil.StatusBadRequest, "accesscontrol.roleAlreadyExists", errutil.WithPublicMessage("a role with the same uid or name already exists"))
+	ErrRoleVersionMismatch  = errutil.NewBase(errutil.StatusBadRequest, "accesscontrol.roleVersionMismatch", errutil.WithPublicMessage("the role has been changed by someone else"))
+	ErrRoleInvalid          = errutil.NewBase(errutil.StatusBadRequest, "accesscontrol.roleInvalid")
+	ErrRoleAlreadyAssigned  = errutil.NewBase(errutil.StatusBadRequest, "accesscontrol.roleAlreadyAssigned", errutil.WithPublicMessage("role is already assigned"))
+	ErrRoleAssignmentTarget = errutil.NewBase(errutil.StatusNotFound, "accesscontrol.roleAssignmentTargetNotFound", errutil.WithPublicMessage("user or team not found in organization"))
+)
+
 type ErrorInvalidRole struct{}
 
 func (e *ErrorInvalidRole) Error() string {
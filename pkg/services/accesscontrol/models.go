@@ -48,6 +48,10 @@ func (r *Role) IsBasic() bool {
 	return strings.HasPrefix(r.Name, BasicRolePrefix) || strings.HasPrefix(r.UID, BasicRoleUIDPrefix)
 }
 
+func (r *Role) IsCustom() bool {
+	return strings.HasPrefix(r.Name, CustomRolePrefix)
+}
+
 func (r Role) MarshalJSON() ([]byte, error) {
 	type Alias Role
 
@@ -129,6 +133,10 @@ func (r *RoleDTO) IsBasic() bool {
 	return strings.HasPrefix(r.Name, BasicRolePrefix) || strings.HasPrefix(r.UID, BasicRoleUIDPrefix)
 }
 
+func (r *RoleDTO) IsCustom() bool {
+	return strings.HasPrefix(r.Name, CustomRolePrefix)
+}
+
 func (r RoleDTO) MarshalJSON() ([]byte, error) {
 	type Alias RoleDTO
 
@@ -188,11 +196,65 @@ func (p Permission) OSSPermission() Permission {
 }
 
 type GetUserPermissionsQuery struct {
-	OrgID      int64
-	UserID     int64
-	Roles      []string
-	TeamIDs    []int64
-	RolePrefix string
+	OrgID        int64
+	UserID       int64
+	Roles        []string
+	TeamIDs      []int64
+	RolePrefixes []string
+}
+
+// CreateRoleCommand creates a custom role, a global role is available in all organizations
+type CreateRoleCommand struct {
+	OrgID       int64        `json:"-"`
+	Global      bool         `json:"global"`
+	UID         string       `json:"uid"`
+	Name        string       `json:"name"`
+	DisplayName string       `json:"displayName"`
+	Description string       `json:"description"`
+	Group       string       `json:"group"`
+	Hidden      bool         `json:"hidden"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// UpdateRoleCommand replaces the attributes and permissions of a custom role. When Version is set
+// it has to be greater than the stored version, otherwise the version is incremented.
+type UpdateRoleCommand struct {
+	OrgID       int64        `json:"-"`
+	UID         string       `json:"-"`
+	Version     int64        `json:"version"`
+	Name        string       `json:"name"`
+	DisplayName string       `json:"displayName"`
+	Description string       `json:"description"`
+	Group       string       `json:"group"`
+	Hidden      bool         `json:"hidden"`
+	Permissions []Permission `json:"permissions"`
+}
+
+type DeleteRoleCommand struct {
+	OrgID int64
+	UID   string
+}
+
+// GetRoleQuery finds a custom role of the organization or a global custom role
+type GetRoleQuery struct {
+	OrgID int64
+	UID   string
+}
+
+// SearchRolesQuery lists the custom roles of the organization and, unless excluded, the global custom roles
+type SearchRolesQuery struct {
+	OrgID         int64
+	ExcludeGlobal bool
+	Query         string
+}
+
+// RoleAssignmentCommand adds a custom role to, or removes it from, a user or a team of the organization.
+// Service accounts are assigned roles as users.
+type RoleAssignmentCommand struct {
+	OrgID   int64
+	RoleUID string
+	UserID  int64
+	TeamID  int64
 }
 
 // ResourcePermission is structure that holds all actions that either a team / user / builtin-role
@@ -251,6 +313,7 @@ const (
 	ManagedRolePrefix  = "managed:"
 	BasicRolePrefix    = "basic:"
 	PluginRolePrefix   = "plugins:"
+	CustomRolePrefix   = "custom:"
 	BasicRoleUIDPrefix = "basic_"
 	RoleGrafanaAdmin   = "Grafana Admin"
 
@@ -262,6 +325,22 @@ const (
 	ActionAPIKeyCreate = "apikeys:create"
 	ActionAPIKeyDelete = "apikeys:delete"
 
+	// Roles actions
+	ActionRolesRead   = "roles:read"
+	ActionRolesWrite  = "roles:write"
+	ActionRolesDelete = "roles:delete"
+
+	// Role assignment actions
+	ActionUsersRolesRead   = "users.roles:read"
+	ActionUsersRolesAdd    = "users.roles:add"
+	ActionUsersRolesRemove = "users.roles:remove"
+	ActionTeamsRolesRead   = "teams.roles:read"
+	ActionTeamsRolesAdd    = "teams.roles:add"
+	ActionTeamsRolesRemove = "teams.roles:remove"
+
+	// Roles scope
+	ScopeRolesAll = "roles:*"
+
 	// Users actions
 	ActionUsersRead  = "users:read"
 	ActionUsersWrite = "users:write"
@@ -383,6 +462,9 @@ var (
 	// Team scope
 	ScopeTeamsID = Scope("teams", "id", Parameter(":teamId"))
 
+	// Role scope
+	ScopeRolesUID = Scope("roles", "uid", Parameter(":roleUID"))
+
 	// Annotation scopes
 	ScopeAnnotationsRoot             = "annotations"
 	ScopeAnnotationsProvider         = NewScopeProvider(ScopeAnnotationsRoot)
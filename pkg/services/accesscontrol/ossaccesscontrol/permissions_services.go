@@ -10,7 +10,9 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/resourcepermissions"
+	"github.com/grafana/grafana/pkg/services/audit"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/licensing"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
@@ -42,7 +44,7 @@ var (
 func ProvideTeamPermissions(
 	cfg *setting.Cfg, router routing.RouteRegister, sql db.DB,
 	ac accesscontrol.AccessControl, license licensing.Licensing, service accesscontrol.Service,
-	teamService team.Service, userService user.Service,
+	teamService team.Service, userService user.Service, auditService audit.Service,
 ) (*TeamPermissionsService, error) {
 	options := resourcepermissions.Options{
 		Resource:          "teams",
@@ -98,7 +100,7 @@ func ProvideTeamPermissions(
 		},
 	}
 
-	srv, err := resourcepermissions.New(options, cfg, router, license, ac, service, sql, teamService, userService)
+	srv, err := resourcepermissions.New(options, cfg, router, license, ac, service, sql, teamService, userService, auditService)
 	if err != nil {
 		return nil, err
 	}
@@ -116,7 +118,7 @@ var DashboardAdminActions = append(DashboardEditActions, []string{dashboards.Act
 func ProvideDashboardPermissions(
 	cfg *setting.Cfg, router routing.RouteRegister, sql db.DB, ac accesscontrol.AccessControl,
 	license licensing.Licensing, dashboardStore dashboards.Store, folderService folder.Service, service accesscontrol.Service,
-	teamService team.Service, userService user.Service,
+	teamService team.Service, userService user.Service, auditService audit.Service,
 ) (*DashboardPermissionsService, error) {
 	getDashboard := func(ctx context.Context, orgID int64, resourceID string) (*dashboards.Dashboard, error) {
 		query := &dashboards.GetDashboardQuery{UID: resourceID, OrgID: orgID}
@@ -178,7 +180,7 @@ func ProvideDashboardPermissions(
 		RoleGroup:      "Dashboards",
 	}
 
-	srv, err := resourcepermissions.New(options, cfg, router, license, ac, service, sql, teamService, userService)
+	srv, err := resourcepermissions.New(options, cfg, router, license, ac, service, sql, teamService, userService, auditService)
 	if err != nil {
 		return nil, err
 	}
@@ -203,7 +205,7 @@ var FolderAdminActions = append(FolderEditActions, []string{dashboards.ActionFol
 func ProvideFolderPermissions(
 	cfg *setting.Cfg, router routing.RouteRegister, sql db.DB, accesscontrol accesscontrol.AccessControl,
 	license licensing.Licensing, dashboardStore dashboards.Store, folderService folder.Service, service accesscontrol.Service,
-	teamService team.Service, userService user.Service,
+	teamService team.Service, userService user.Service, auditService audit.Service,
 ) (*FolderPermissionsService, error) {
 	options := resourcepermissions.Options{
 		Resource:          "folders",
@@ -238,43 +240,67 @@ func ProvideFolderPermissions(
 		WriterRoleName: "Folder permission writer",
 		RoleGroup:      "Folders",
 	}
-	srv, err := resourcepermissions.New(options, cfg, router, license, accesscontrol, service, sql, teamService, userService)
+	srv, err := resourcepermissions.New(options, cfg, router, license, accesscontrol, service, sql, teamService, userService, auditService)
 	if err != nil {
 		return nil, err
 	}
 	return &FolderPermissionsService{srv}, nil
 }
 
-func ProvideDatasourcePermissionsService() *DatasourcePermissionsService {
-	return &DatasourcePermissionsService{}
-}
-
-var _ accesscontrol.DatasourcePermissionsService = new(DatasourcePermissionsService)
-
-type DatasourcePermissionsService struct{}
-
-func (e DatasourcePermissionsService) GetPermissions(ctx context.Context, user *user.SignedInUser, resourceID string) ([]accesscontrol.ResourcePermission, error) {
-	return nil, nil
-}
-
-func (e DatasourcePermissionsService) SetUserPermission(ctx context.Context, orgID int64, user accesscontrol.User, resourceID, permission string) (*accesscontrol.ResourcePermission, error) {
-	return nil, nil
-}
-
-func (e DatasourcePermissionsService) SetTeamPermission(ctx context.Context, orgID, teamID int64, resourceID, permission string) (*accesscontrol.ResourcePermission, error) {
-	return nil, nil
+type DatasourcePermissionsService struct {
+	*resourcepermissions.Service
 }
 
-func (e DatasourcePermissionsService) SetBuiltInRolePermission(ctx context.Context, orgID int64, builtInRole string, resourceID string, permission string) (*accesscontrol.ResourcePermission, error) {
-	return nil, nil
-}
+var (
+	DatasourceQueryActions   = []string{datasources.ActionRead, datasources.ActionQuery}
+	DatasourceExploreActions = append(DatasourceQueryActions, datasources.ActionExplore)
+	DatasourceAlertActions   = append(DatasourceExploreActions, datasources.ActionAlert)
+	DatasourceEditActions    = append(DatasourceAlertActions, []string{datasources.ActionWrite, datasources.ActionDelete}...)
+	DatasourceAdminActions   = append(DatasourceEditActions, []string{datasources.ActionPermissionsRead, datasources.ActionPermissionsWrite}...)
+)
 
-func (e DatasourcePermissionsService) SetPermissions(ctx context.Context, orgID int64, resourceID string, commands ...accesscontrol.SetResourcePermissionCommand) ([]accesscontrol.ResourcePermission, error) {
-	return nil, nil
-}
+func ProvideDatasourcePermissionsService(
+	cfg *setting.Cfg, router routing.RouteRegister, sql db.DB, ac accesscontrol.AccessControl,
+	license licensing.Licensing, service accesscontrol.Service,
+	teamService team.Service, userService user.Service, auditService audit.Service,
+) (*DatasourcePermissionsService, error) {
+	options := resourcepermissions.Options{
+		Resource:          datasources.ScopeRoot,
+		ResourceAttribute: "uid",
+		ResourceValidator: func(ctx context.Context, orgID int64, resourceID string) error {
+			return sql.WithDbSession(ctx, func(sess *db.Session) error {
+				exists, err := sess.Table("data_source").Where("org_id = ? AND uid = ?", orgID, resourceID).Exist()
+				if err != nil {
+					return err
+				}
+				if !exists {
+					return datasources.ErrDataSourceNotFound
+				}
+				return nil
+			})
+		},
+		Assignments: resourcepermissions.Assignments{
+			Users:        true,
+			Teams:        true,
+			BuiltInRoles: true,
+		},
+		PermissionsToActions: map[string][]string{
+			"Query":   DatasourceQueryActions,
+			"Explore": DatasourceExploreActions,
+			"Alert":   DatasourceAlertActions,
+			"Edit":    DatasourceEditActions,
+			"Admin":   DatasourceAdminActions,
+		},
+		ReaderRoleName: "Data source permission reader",
+		WriterRoleName: "Data source permission writer",
+		RoleGroup:      "Data sources",
+	}
 
-func (e DatasourcePermissionsService) MapActions(permission accesscontrol.ResourcePermission) string {
-	return ""
+	srv, err := resourcepermissions.New(options, cfg, router, license, ac, service, sql, teamService, userService, auditService)
+	if err != nil {
+		return nil, err
+	}
+	return &DatasourcePermissionsService{srv}, nil
 }
 
 var (
@@ -298,7 +324,7 @@ type ServiceAccountPermissionsService struct {
 func ProvideServiceAccountPermissions(
 	cfg *setting.Cfg, router routing.RouteRegister, sql db.DB, ac accesscontrol.AccessControl,
 	license licensing.Licensing, serviceAccountRetrieverService *retriever.Service, service accesscontrol.Service,
-	teamService team.Service, userService user.Service,
+	teamService team.Service, userService user.Service, auditService audit.Service,
 ) (*ServiceAccountPermissionsService, error) {
 	options := resourcepermissions.Options{
 		Resource:          "serviceaccounts",
@@ -325,7 +351,7 @@ func ProvideServiceAccountPermissions(
 		RoleGroup:      "Service accounts",
 	}
 
-	srv, err := resourcepermissions.New(options, cfg, router, license, ac, service, sql, teamService, userService)
+	srv, err := resourcepermissions.New(options, cfg, router, license, ac, service, sql, teamService, userService, auditService)
 	if err != nil {
 		return nil, err
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/audit"
 	"github.com/grafana/grafana/pkg/services/licensing"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/team"
@@ -51,7 +52,7 @@ type Store interface {
 func New(
 	options Options, cfg *setting.Cfg, router routing.RouteRegister, license licensing.Licensing,
 	ac accesscontrol.AccessControl, service accesscontrol.Service, sqlStore db.DB,
-	teamService team.Service, userService user.Service,
+	teamService team.Service, userService user.Service, auditService audit.Service,
 ) (*Service, error) {
 	permissions := make([]string, 0, len(options.PermissionsToActions))
 	actionSet := make(map[string]struct{})
@@ -73,17 +74,18 @@ func New(
 	}
 
 	s := &Service{
-		ac:          ac,
-		cfg:         cfg,
-		store:       NewStore(sqlStore),
-		options:     options,
-		license:     license,
-		permissions: permissions,
-		actions:     actions,
-		sqlStore:    sqlStore,
-		service:     service,
-		teamService: teamService,
-		userService: userService,
+		ac:           ac,
+		cfg:          cfg,
+		store:        NewStore(sqlStore),
+		options:      options,
+		license:      license,
+		permissions:  permissions,
+		actions:      actions,
+		sqlStore:     sqlStore,
+		service:      service,
+		teamService:  teamService,
+		userService:  userService,
+		auditService: auditService,
 	}
 
 	s.api = newApi(ac, router, s)
@@ -106,12 +108,13 @@ type Service struct {
 	api     *api
 	license licensing.Licensing
 
-	options     Options
-	permissions []string
-	actions     []string
-	sqlStore    db.DB
-	teamService team.Service
-	userService user.Service
+	options      Options
+	permissions  []string
+	actions      []string
+	sqlStore     db.DB
+	teamService  team.Service
+	userService  user.Service
+	auditService audit.Service
 }
 
 func (s *Service) GetPermissions(ctx context.Context, user *user.SignedInUser, resourceID string) ([]accesscontrol.ResourcePermission, error) {
@@ -150,13 +153,19 @@ func (s *Service) SetUserPermission(ctx context.Context, orgID int64, user acces
 		return nil, err
 	}
 
-	return s.store.SetUserResourcePermission(ctx, orgID, user, SetResourcePermissionCommand{
+	result, err := s.store.SetUserResourcePermission(ctx, orgID, user, SetResourcePermissionCommand{
 		Actions:           actions,
 		Permission:        permission,
 		Resource:          s.options.Resource,
 		ResourceID:        resourceID,
 		ResourceAttribute: s.options.ResourceAttribute,
 	}, s.options.OnSetUser)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditPermissions(ctx, orgID, resourceID, accesscontrol.SetResourcePermissionCommand{UserID: user.ID, Permission: permission})
+	return result, nil
 }
 
 func (s *Service) SetTeamPermission(ctx context.Context, orgID, teamID int64, resourceID, permission string) (*accesscontrol.ResourcePermission, error) {
@@ -173,13 +182,19 @@ func (s *Service) SetTeamPermission(ctx context.Context, orgID, teamID int64, re
 		return nil, err
 	}
 
-	return s.store.SetTeamResourcePermission(ctx, orgID, teamID, SetResourcePermissionCommand{
+	result, err := s.store.SetTeamResourcePermission(ctx, orgID, teamID, SetResourcePermissionCommand{
 		Actions:           actions,
 		Permission:        permission,
 		Resource:          s.options.Resource,
 		ResourceID:        resourceID,
 		ResourceAttribute: s.options.ResourceAttribute,
 	}, s.options.OnSetTeam)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditPermissions(ctx, orgID, resourceID, accesscontrol.SetResourcePermissionCommand{TeamID: teamID, Permission: permission})
+	return result, nil
 }
 
 func (s *Service) SetBuiltInRolePermission(ctx context.Context, orgID int64, builtInRole, resourceID, permission string) (*accesscontrol.ResourcePermission, error) {
@@ -196,13 +211,19 @@ func (s *Service) SetBuiltInRolePermission(ctx context.Context, orgID int64, bui
 		return nil, err
 	}
 
-	return s.store.SetBuiltInResourcePermission(ctx, orgID, builtInRole, SetResourcePermissionCommand{
+	result, err := s.store.SetBuiltInResourcePermission(ctx, orgID, builtInRole, SetResourcePermissionCommand{
 		Actions:           actions,
 		Permission:        permission,
 		Resource:          s.options.Resource,
 		ResourceID:        resourceID,
 		ResourceAttribute: s.options.ResourceAttribute,
 	}, s.options.OnSetBuiltInRole)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditPermissions(ctx, orgID, resourceID, accesscontrol.SetResourcePermissionCommand{BuiltinRole: builtInRole, Permission: permission})
+	return result, nil
 }
 
 func (s *Service) SetPermissions(
@@ -248,11 +269,27 @@ func (s *Service) SetPermissions(
 		})
 	}
 
-	return s.store.SetResourcePermissions(ctx, orgID, dbCommands, ResourceHooks{
+	permissions, err := s.store.SetResourcePermissions(ctx, orgID, dbCommands, ResourceHooks{
 		User:        s.options.OnSetUser,
 		Team:        s.options.OnSetTeam,
 		BuiltInRole: s.options.OnSetBuiltInRole,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditPermissions(ctx, orgID, resourceID, commands...)
+	return permissions, nil
+}
+
+// auditPermissions records the permissions set on a resource, an empty permission removes the access
+func (s *Service) auditPermissions(ctx context.Context, orgID int64, resourceID string, commands ...accesscontrol.SetResourcePermissionCommand) {
+	s.auditService.Log(ctx, audit.Event{
+		OrgID:    orgID,
+		Action:   audit.ActionPermissionsUpdate,
+		Resource: audit.Resource{Kind: s.options.Resource, ID: resourceID},
+		Details:  map[string]any{"permissions": commands},
+	})
 }
 
 func (s *Service) MapActions(permission accesscontrol.ResourcePermission) string {
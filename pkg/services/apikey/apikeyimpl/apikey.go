@@ -63,10 +63,16 @@ func (s *Service) DeleteApiKey(ctx context.Context, cmd *apikey.DeleteCommand) e
 	return s.store.DeleteApiKey(ctx, cmd)
 }
 func (s *Service) AddAPIKey(ctx context.Context, cmd *apikey.AddCommand) (res *apikey.APIKey, err error) {
+	if err := cmd.Permissions.Validate(); err != nil {
+		return nil, err
+	}
+	if err := cmd.AllowedIPs.Validate(); err != nil {
+		return nil, err
+	}
 	return s.store.AddAPIKey(ctx, cmd)
 }
-func (s *Service) UpdateAPIKeyLastUsedDate(ctx context.Context, tokenID int64) error {
-	return s.store.UpdateAPIKeyLastUsedDate(ctx, tokenID)
+func (s *Service) UpdateAPIKeyLastUsedDate(ctx context.Context, tokenID int64, ip string) error {
+	return s.store.UpdateAPIKeyLastUsedDate(ctx, tokenID, ip)
 }
 
 // IsDisabled returns true if the apikey service is disabled for the given org.
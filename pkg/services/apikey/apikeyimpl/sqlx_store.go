@@ -113,10 +113,12 @@ func (ss *sqlxStore) AddAPIKey(ctx context.Context, cmd *apikey.AddCommand) (*ap
 		Expires:          expires,
 		ServiceAccountId: nil,
 		IsRevoked:        &isRevoked,
+		Permissions:      cmd.Permissions,
+		AllowedIPs:       cmd.AllowedIPs,
 	}
 
 	t.ID, err = ss.sess.ExecWithReturningId(ctx,
-		`INSERT INTO api_key (org_id, name, role, "key", created, updated, expires, service_account_id, is_revoked) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, t.OrgID, t.Name, t.Role, t.Key, t.Created, t.Updated, t.Expires, t.ServiceAccountId, t.IsRevoked)
+		`INSERT INTO api_key (org_id, name, role, "key", created, updated, expires, service_account_id, is_revoked, permissions, allowed_ips) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, t.OrgID, t.Name, t.Role, t.Key, t.Created, t.Updated, t.Expires, t.ServiceAccountId, t.IsRevoked, t.Permissions, t.AllowedIPs)
 	return &t, err
 }
 
@@ -147,9 +149,13 @@ func (ss *sqlxStore) GetAPIKeyByHash(ctx context.Context, hash string) (*apikey.
 	return &key, err
 }
 
-func (ss *sqlxStore) UpdateAPIKeyLastUsedDate(ctx context.Context, tokenID int64) error {
+func (ss *sqlxStore) UpdateAPIKeyLastUsedDate(ctx context.Context, tokenID int64, ip string) error {
 	now := timeNow()
-	_, err := ss.sess.Exec(ctx, `UPDATE api_key SET last_used_at=? WHERE id=?`, &now, tokenID)
+	if ip == "" {
+		_, err := ss.sess.Exec(ctx, `UPDATE api_key SET last_used_at=? WHERE id=?`, &now, tokenID)
+		return err
+	}
+	_, err := ss.sess.Exec(ctx, `UPDATE api_key SET last_used_at=?, last_used_ip=? WHERE id=?`, &now, ip, tokenID)
 	return err
 }
 
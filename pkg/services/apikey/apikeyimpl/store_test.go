@@ -128,13 +128,35 @@ func testIntegrationApiKeyDataAccess(t *testing.T, fn getStore) {
 
 			assert.Nil(t, key.LastUsedAt)
 
-			err = ss.UpdateAPIKeyLastUsedDate(context.Background(), key.ID)
+			err = ss.UpdateAPIKeyLastUsedDate(context.Background(), key.ID, "10.0.0.1")
 			require.NoError(t, err)
 
 			query := apikey.GetByNameQuery{KeyName: "last-update-at", OrgID: 1}
 			key, err = ss.GetApiKeyByName(context.Background(), &query)
 			assert.Nil(t, err)
 			assert.NotNil(t, key.LastUsedAt)
+			require.NotNil(t, key.LastUsedIP)
+			assert.Equal(t, "10.0.0.1", *key.LastUsedIP)
+		})
+
+		t.Run("Add a key with restrictions", func(t *testing.T) {
+			cmd := apikey.AddCommand{
+				OrgID: 1, Name: "restricted", Key: "restricted",
+				Permissions: apikey.Permissions{{Action: "dashboards:read", Scope: "folders:uid:ops"}},
+				AllowedIPs:  apikey.AllowedIPs{"10.0.0.0/8"},
+			}
+			_, err := ss.AddAPIKey(context.Background(), &cmd)
+			require.NoError(t, err)
+
+			key, err := ss.GetApiKeyByName(context.Background(), &apikey.GetByNameQuery{KeyName: "restricted", OrgID: 1})
+			require.NoError(t, err)
+			assert.Equal(t, cmd.Permissions, key.Permissions)
+			assert.Equal(t, cmd.AllowedIPs, key.AllowedIPs)
+
+			key, err = ss.GetApiKeyByName(context.Background(), &apikey.GetByNameQuery{KeyName: "last-update-at", OrgID: 1})
+			require.NoError(t, err)
+			assert.Empty(t, key.Permissions)
+			assert.Empty(t, key.AllowedIPs)
 		})
 
 		t.Run("Add a key with negative lifespan", func(t *testing.T) {
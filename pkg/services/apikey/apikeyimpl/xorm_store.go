@@ -129,6 +129,8 @@ func (ss *sqlStore) AddAPIKey(ctx context.Context, cmd *apikey.AddCommand) (res
 			Expires:          expires,
 			ServiceAccountId: cmd.ServiceAccountID,
 			IsRevoked:        &isRevoked,
+			Permissions:      cmd.Permissions,
+			AllowedIPs:       cmd.AllowedIPs,
 		}
 
 		if _, err := sess.Insert(&t); err != nil {
@@ -188,10 +190,16 @@ func (ss *sqlStore) GetAPIKeyByHash(ctx context.Context, hash string) (*apikey.A
 	return &key, err
 }
 
-func (ss *sqlStore) UpdateAPIKeyLastUsedDate(ctx context.Context, tokenID int64) error {
+func (ss *sqlStore) UpdateAPIKeyLastUsedDate(ctx context.Context, tokenID int64, ip string) error {
 	now := timeNow()
 	return ss.db.WithDbSession(ctx, func(sess *db.Session) error {
-		if _, err := sess.Table("api_key").ID(tokenID).Cols("last_used_at").Update(&apikey.APIKey{LastUsedAt: &now}); err != nil {
+		update := &apikey.APIKey{LastUsedAt: &now}
+		cols := []string{"last_used_at"}
+		if ip != "" {
+			update.LastUsedIP = &ip
+			cols = append(cols, "last_used_ip")
+		}
+		if _, err := sess.Table("api_key").ID(tokenID).Cols(cols...).Update(update); err != nil {
 			return err
 		}
 
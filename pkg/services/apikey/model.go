@@ -1,19 +1,28 @@
 package apikey
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
 	"time"
 
+	"github.com/grafana/grafana/pkg/infra/network"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
 var (
-	ErrNotFound          = errors.New("API key not found")
-	ErrInvalid           = errors.New("invalid API key")
-	ErrInvalidExpiration = errors.New("negative value for SecondsToLive")
-	ErrDuplicate         = errors.New("API key, organization ID and name must be unique")
+	ErrNotFound           = errors.New("API key not found")
+	ErrInvalid            = errors.New("invalid API key")
+	ErrInvalidExpiration  = errors.New("negative value for SecondsToLive")
+	ErrDuplicate          = errors.New("API key, organization ID and name must be unique")
+	ErrInvalidPermissions = errors.New("invalid token permissions")
+	ErrInvalidAllowedIPs  = errors.New("invalid token allowed IPs")
 )
 
 type APIKey struct {
@@ -28,10 +37,184 @@ type APIKey struct {
 	Expires          *int64       `db:"expires"`
 	ServiceAccountId *int64       `db:"service_account_id"`
 	IsRevoked        *bool        `xorm:"is_revoked" db:"is_revoked"`
+	// Permissions restricts a service account token to a subset of the permissions of its service account.
+	// The token has all of them when empty.
+	Permissions Permissions `xorm:"permissions" db:"permissions"`
+	// AllowedIPs restricts the addresses a service account token can be used from.
+	// The token can be used from any address when empty.
+	AllowedIPs AllowedIPs `xorm:"allowed_ips" db:"allowed_ips"`
+	LastUsedIP *string    `xorm:"last_used_ip" db:"last_used_ip"`
 }
 
 func (k APIKey) TableName() string { return "api_key" }
 
+// Permission is an action, optionally limited to a scope, a service account token can use
+type Permission struct {
+	Action string `json:"action"`
+	Scope  string `json:"scope,omitempty"`
+}
+
+// Permissions is stored as a JSON list, NULL or empty when the token is not restricted
+type Permissions []Permission
+
+// ScopesByAction groups the scopes of the permissions by action, it returns nil when there are no permissions
+func (p Permissions) ScopesByAction() map[string][]string {
+	if len(p) == 0 {
+		return nil
+	}
+	m := make(map[string][]string, len(p))
+	for _, permission := range p {
+		m[permission.Action] = append(m[permission.Action], permission.Scope)
+	}
+	return m
+}
+
+func (p Permissions) Validate() error {
+	for _, permission := range p {
+		if permission.Action == "" {
+			return fmt.Errorf("%w: permission action is required", ErrInvalidPermissions)
+		}
+	}
+	return nil
+}
+
+func (p *Permissions) FromDB(data []byte) error {
+	return unmarshalList(data, p)
+}
+
+func (p Permissions) ToDB() ([]byte, error) {
+	return marshalList(len(p), p)
+}
+
+func (p *Permissions) Scan(src interface{}) error {
+	return scanList(src, p)
+}
+
+func (p Permissions) Value() (driver.Value, error) {
+	return valueList(len(p), p)
+}
+
+// AllowedIPs lists IP addresses and CIDR ranges, it is stored as a JSON list
+type AllowedIPs []string
+
+// Contains returns true if the IP address is allowed, any address is allowed when the list is empty
+func (a AllowedIPs) Contains(ip net.IP) bool {
+	if len(a) == 0 {
+		return true
+	}
+	if ip == nil {
+		return false
+	}
+	for _, allowed := range a {
+		if strings.Contains(allowed, "/") {
+			if _, ipNet, err := net.ParseCIDR(allowed); err == nil && ipNet.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if allowedIP := net.ParseIP(allowed); allowedIP != nil && allowedIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the address a token is used from. The X-Forwarded-For and X-Real-IP headers are only
+// read when the request comes from a trusted proxy, otherwise clients could claim to use an allowed address.
+func ClientIP(req *http.Request, trustedProxies AllowedIPs) net.IP {
+	ip, err := network.GetIPFromAddress(req.RemoteAddr)
+	if err != nil || len(trustedProxies) == 0 || !trustedProxies.Contains(ip) {
+		return ip
+	}
+
+	// the addresses are appended by each proxy, the client is the last one that is not a trusted proxy
+	if forwarded := req.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		addresses := strings.Split(strings.Join(forwarded, ","), ",")
+		for i := len(addresses) - 1; i >= 0; i-- {
+			forwardedIP, err := network.GetIPFromAddress(strings.TrimSpace(addresses[i]))
+			if err != nil {
+				break
+			}
+			ip = forwardedIP
+			if !trustedProxies.Contains(ip) {
+				break
+			}
+		}
+		return ip
+	}
+
+	if realIP, err := network.GetIPFromAddress(req.Header.Get("X-Real-IP")); err == nil {
+		return realIP
+	}
+	return ip
+}
+
+func (a AllowedIPs) Validate() error {
+	for _, allowed := range a {
+		if strings.Contains(allowed, "/") {
+			if _, _, err := net.ParseCIDR(allowed); err != nil {
+				return fmt.Errorf("%w: %s is not a valid CIDR range", ErrInvalidAllowedIPs, allowed)
+			}
+			continue
+		}
+		if net.ParseIP(allowed) == nil {
+			return fmt.Errorf("%w: %s is not a valid IP address", ErrInvalidAllowedIPs, allowed)
+		}
+	}
+	return nil
+}
+
+func (a *AllowedIPs) FromDB(data []byte) error {
+	return unmarshalList(data, a)
+}
+
+func (a AllowedIPs) ToDB() ([]byte, error) {
+	return marshalList(len(a), a)
+}
+
+func (a *AllowedIPs) Scan(src interface{}) error {
+	return scanList(src, a)
+}
+
+func (a AllowedIPs) Value() (driver.Value, error) {
+	return valueList(len(a), a)
+}
+
+func unmarshalList(data []byte, v interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+func marshalList(length int, v interface{}) ([]byte, error) {
+	if length == 0 {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+func scanList(src interface{}, v interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return unmarshalList(data, v)
+	case string:
+		return unmarshalList([]byte(data), v)
+	default:
+		return fmt.Errorf("unsupported type %T", src)
+	}
+}
+
+func valueList(length int, v interface{}) (driver.Value, error) {
+	data, err := marshalList(length, v)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
 // swagger:model
 type AddCommand struct {
 	Name             string       `json:"name" binding:"Required"`
@@ -40,6 +223,8 @@ type AddCommand struct {
 	Key              string       `json:"-"`
 	SecondsToLive    int64        `json:"secondsToLive"`
 	ServiceAccountID *int64       `json:"-"`
+	Permissions      Permissions  `json:"-"`
+	AllowedIPs       AllowedIPs   `json:"-"`
 }
 
 type DeleteCommand struct {
@@ -0,0 +1,16 @@
+package audit
+
+import (
+	"context"
+)
+
+type Service interface {
+	// Log writes an event to the configured sinks. The actor and the IP address of the event are
+	// taken from the request of the context when they are not set. Failing sinks are logged,
+	// they never fail the audited action.
+	Log(ctx context.Context, event Event)
+	// Search returns the events recorded in the database, newest first.
+	Search(ctx context.Context, query *SearchQuery) (*SearchResult, error)
+	// DeleteExpired deletes the events recorded in the database that are older than the configured maximum age.
+	DeleteExpired(ctx context.Context) (int64, error)
+}
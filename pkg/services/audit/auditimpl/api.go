@@ -0,0 +1,49 @@
+package auditimpl
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/audit"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
+
+func (s *Service) registerAPIEndpoints(router routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	router.Group("/api/admin/audit-logs", func(auditRoute routing.RouteRegister) {
+		auditRoute.Get("/", authorize(middleware.ReqGrafanaAdmin, ac.EvalPermission(ActionRead)), routing.Wrap(s.searchHandler))
+	}, middleware.ReqSignedIn)
+}
+
+// searchHandler returns the events recorded in the database. The from and to
+// parameters are epoch milliseconds.
+func (s *Service) searchHandler(c *contextmodel.ReqContext) response.Response {
+	query := &audit.SearchQuery{
+		OrgID:        c.QueryInt64("orgId"),
+		ActorID:      c.QueryInt64("userId"),
+		ActorLogin:   c.Query("login"),
+		Action:       c.Query("action"),
+		ResourceKind: c.Query("resourceKind"),
+		ResourceID:   c.Query("resourceId"),
+		Result:       c.Query("result"),
+		Page:         c.QueryInt("page"),
+		Limit:        c.QueryInt("perpage"),
+	}
+	if from := c.QueryInt64("from"); from > 0 {
+		query.From = time.UnixMilli(from)
+	}
+	if to := c.QueryInt64("to"); to > 0 {
+		query.To = time.UnixMilli(to)
+	}
+
+	result, err := s.Search(c.Req.Context(), query)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to search the audit log", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,24 @@
+package auditimpl
+
+import (
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+const ActionRead = "auditlogs:read"
+
+var readerRole = ac.RoleDTO{
+	Name:        "fixed:auditlogs:reader",
+	DisplayName: "Audit log reader",
+	Description: "Search the audit log of all organizations",
+	Group:       "Audit log",
+	Permissions: []ac.Permission{
+		{Action: ActionRead},
+	},
+}
+
+func declareFixedRoles(service ac.Service) error {
+	return service.DeclareFixedRoles(ac.RoleRegistration{
+		Role:   readerRole,
+		Grants: []string{ac.RoleGrafanaAdmin},
+	})
+}
@@ -0,0 +1,221 @@
+package auditimpl
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/audit"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/contexthandler/ctxkey"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/hooks"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/twofactor"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	defaultPerPage = 100
+	maxPerPage     = 1000
+)
+
+var (
+	errDisabled = errutil.NewBase(errutil.StatusNotFound, "audit.disabled", errutil.WithPublicMessage("The audit log is disabled"))
+	errNoSink   = errutil.NewBase(errutil.StatusNotFound, "audit.no-database-sink", errutil.WithPublicMessage("The audit log is not recorded in the database"))
+)
+
+var _ audit.Service = new(Service)
+
+func ProvideService(
+	cfg *setting.Cfg, db db.DB, routeRegister routing.RouteRegister, accessControl ac.AccessControl,
+	accesscontrolService ac.Service, hooksService *hooks.HooksService, authnService authn.Service,
+) (*Service, error) {
+	s := &Service{
+		cfg:           cfg,
+		store:         &xormStore{db: db},
+		accessControl: accessControl,
+		log:           log.New("audit"),
+		now:           time.Now,
+	}
+
+	if !cfg.Audit.Enabled {
+		return s, nil
+	}
+
+	for _, name := range cfg.Audit.Sinks {
+		sink, err := newSink(cfg, s.store, name)
+		if err != nil {
+			return nil, err
+		}
+		s.sinks = append(s.sinks, sink)
+	}
+
+	if !accessControl.IsDisabled() {
+		if err := declareFixedRoles(accesscontrolService); err != nil {
+			return nil, err
+		}
+	}
+
+	hooksService.AddLoginHook(s.loginHook)
+	authnService.RegisterPostLoginHook(s.postLoginHook, 200)
+	s.registerAPIEndpoints(routeRegister)
+
+	return s, nil
+}
+
+// Service writes audit events to the sinks configured in the [audit] section
+type Service struct {
+	cfg           *setting.Cfg
+	store         store
+	sinks         []sink
+	accessControl ac.AccessControl
+	log           log.Logger
+	now           func() time.Time
+}
+
+func (s *Service) Log(ctx context.Context, event audit.Event) {
+	if !s.cfg.Audit.Enabled {
+		return
+	}
+
+	if event.Timestamp.IsZero() {
+		event.Timestamp = s.now()
+	}
+	if event.Result == "" {
+		event.Result = audit.ResultSuccess
+	}
+	if reqCtx, ok := ctxkey.Get(ctx).(*contextmodel.ReqContext); ok && reqCtx != nil {
+		if event.Actor == (audit.Actor{}) && reqCtx.SignedInUser != nil {
+			event.Actor = actorFromSignedInUser(reqCtx)
+		}
+		if event.OrgID == 0 {
+			event.OrgID = reqCtx.OrgID
+		}
+		if event.IPAddress == "" && reqCtx.Req != nil {
+			event.IPAddress = web.RemoteAddr(reqCtx.Req)
+		}
+	}
+
+	for _, sink := range s.sinks {
+		if err := sink.Write(ctx, &event); err != nil {
+			s.log.FromContext(ctx).Error("Failed to write audit event", "sink", sink.Name(), "action", event.Action, "error", err)
+		}
+	}
+}
+
+func (s *Service) Search(ctx context.Context, query *audit.SearchQuery) (*audit.SearchResult, error) {
+	if !s.cfg.Audit.Enabled {
+		return nil, errDisabled.Errorf("audit log is disabled")
+	}
+	if !s.hasDatabaseSink() {
+		return nil, errNoSink.Errorf("the database audit sink is not configured")
+	}
+
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultPerPage
+	}
+	if query.Limit > maxPerPage {
+		query.Limit = maxPerPage
+	}
+	return s.store.Search(ctx, query)
+}
+
+func (s *Service) DeleteExpired(ctx context.Context) (int64, error) {
+	if !s.hasDatabaseSink() || s.cfg.Audit.MaxAge <= 0 {
+		return 0, nil
+	}
+	return s.store.DeleteOlderThan(ctx, s.now().Add(-s.cfg.Audit.MaxAge))
+}
+
+func (s *Service) hasDatabaseSink() bool {
+	for _, sink := range s.sinks {
+		if sink.Name() == audit.SinkDatabase {
+			return true
+		}
+	}
+	return false
+}
+
+// loginHook records the logins handled by the login API and the OAuth callbacks
+func (s *Service) loginHook(info *login.LoginInfo, c *contextmodel.ReqContext) {
+	event := audit.Event{
+		Action:   audit.ActionLogin,
+		Actor:    audit.Actor{Kind: audit.ActorUser, Login: info.LoginUsername},
+		Resource: audit.Resource{Kind: audit.ResourceUser},
+		Details:  map[string]any{"authModule": info.AuthModule},
+	}
+	if info.User != nil {
+		event.Actor.ID = info.User.ID
+		event.Actor.Login = info.User.Login
+	} else if info.ExternalUser.Login != "" {
+		event.Actor.Login = info.ExternalUser.Login
+	}
+	event.Resource.ID = event.Actor.Login
+
+	if info.Error != nil {
+		event.Result = audit.ResultFailure
+		event.Details["error"] = info.Error.Error()
+	}
+
+	s.Log(c.Req.Context(), event)
+}
+
+// postLoginHook records the logins handled by the authn service
+func (s *Service) postLoginHook(ctx context.Context, identity *authn.Identity, r *authn.Request, err error) {
+	// the password was valid but the login is only complete once the second factor is verified
+	if errors.Is(err, twofactor.ErrCodeRequired) || errors.Is(err, twofactor.ErrEnrollmentRequired) {
+		return
+	}
+
+	event := audit.Event{
+		Action:   audit.ActionLogin,
+		Actor:    audit.Actor{Kind: audit.ActorUser, Login: r.GetMeta(authn.MetaKeyUsername)},
+		Resource: audit.Resource{Kind: audit.ResourceUser},
+		Details:  map[string]any{"authModule": r.GetMeta(authn.MetaKeyAuthModule)},
+	}
+	if identity != nil {
+		_, id := identity.NamespacedID()
+		event.Actor.ID = id
+		event.Actor.Login = identity.Login
+		event.OrgID = identity.OrgID
+		if identity.AuthModule != "" {
+			event.Details["authModule"] = identity.AuthModule
+		}
+	}
+	event.Resource.ID = event.Actor.Login
+	if r.HTTPRequest != nil {
+		event.IPAddress = web.RemoteAddr(r.HTTPRequest)
+	}
+
+	if err != nil {
+		event.Result = audit.ResultFailure
+		event.Details["error"] = err.Error()
+	}
+
+	s.Log(ctx, event)
+}
+
+func actorFromSignedInUser(c *contextmodel.ReqContext) audit.Actor {
+	u := c.SignedInUser
+	switch {
+	case u.IsServiceAccount:
+		return audit.Actor{Kind: audit.ActorServiceAccount, ID: u.UserID, Login: u.Login}
+	case u.ApiKeyID > 0:
+		return audit.Actor{Kind: audit.ActorAPIKey, ID: u.ApiKeyID}
+	case u.IsAnonymous:
+		return audit.Actor{Kind: audit.ActorAnonymous}
+	case u.UserID > 0:
+		return audit.Actor{Kind: audit.ActorUser, ID: u.UserID, Login: u.Login}
+	}
+	return audit.Actor{}
+}
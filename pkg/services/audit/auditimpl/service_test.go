@@ -0,0 +1,153 @@
+package auditimpl
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/audit"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/contexthandler/ctxkey"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/twofactor"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestService_Log(t *testing.T) {
+	t.Run("should take the actor of service accounts from the request", func(t *testing.T) {
+		s, sink := setupFakeSinkService()
+		s.Log(requestContext(&user.SignedInUser{UserID: 5, OrgID: 1, Login: "sa-scim", IsServiceAccount: true}), audit.Event{Action: audit.ActionAPIKeyCreate})
+
+		require.Len(t, sink.events, 1)
+		assert.Equal(t, audit.Actor{Kind: audit.ActorServiceAccount, ID: 5, Login: "sa-scim"}, sink.events[0].Actor)
+	})
+
+	t.Run("should not write events when disabled", func(t *testing.T) {
+		s, sink := setupFakeSinkService()
+		s.cfg.Audit.Enabled = false
+		s.Log(context.Background(), audit.Event{Action: audit.ActionAPIKeyCreate})
+		assert.Empty(t, sink.events)
+	})
+}
+
+func TestService_Search(t *testing.T) {
+	t.Run("should cap the page size", func(t *testing.T) {
+		s, _ := setupFakeSinkService()
+		store := &fakeStore{}
+		s.store = store
+		s.sinks = []sink{&databaseSink{store: store}}
+
+		_, err := s.Search(context.Background(), &audit.SearchQuery{Limit: 5000})
+		require.NoError(t, err)
+		assert.Equal(t, 1, store.query.Page)
+		assert.Equal(t, maxPerPage, store.query.Limit)
+	})
+
+	t.Run("should fail without the database sink", func(t *testing.T) {
+		s, _ := setupFakeSinkService()
+		_, err := s.Search(context.Background(), &audit.SearchQuery{})
+		assert.ErrorIs(t, err, errNoSink)
+	})
+}
+
+func TestService_DeleteExpired(t *testing.T) {
+	s, _ := setupFakeSinkService()
+	s.cfg.Audit.MaxAge = 24 * time.Hour
+	now := time.Date(2023, 11, 14, 22, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	store := &fakeStore{deleted: 3}
+	s.store = store
+	s.sinks = []sink{&databaseSink{store: store}}
+
+	deleted, err := s.DeleteExpired(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	assert.Equal(t, now.Add(-24*time.Hour), store.olderThan)
+}
+
+func TestService_PostLoginHook(t *testing.T) {
+	newRequest := func() *authn.Request {
+		r := &authn.Request{HTTPRequest: &http.Request{RemoteAddr: "10.0.0.2:51234", Header: http.Header{}}}
+		r.SetMeta(authn.MetaKeyUsername, "jdoe")
+		return r
+	}
+
+	t.Run("should record failed logins with the username", func(t *testing.T) {
+		s, sink := setupFakeSinkService()
+		s.postLoginHook(context.Background(), nil, newRequest(), authn.ErrTokenNeedsRotation.Errorf("invalid"))
+
+		require.Len(t, sink.events, 1)
+		event := sink.events[0]
+		assert.Equal(t, audit.ActionLogin, event.Action)
+		assert.Equal(t, audit.ResultFailure, event.Result)
+		assert.Equal(t, "jdoe", event.Actor.Login)
+		assert.Equal(t, "10.0.0.2", event.IPAddress)
+	})
+
+	t.Run("should record successful logins", func(t *testing.T) {
+		s, sink := setupFakeSinkService()
+		identity := &authn.Identity{ID: authn.NamespacedID(authn.NamespaceUser, 7), OrgID: 1, Login: "jdoe"}
+		s.postLoginHook(context.Background(), identity, newRequest(), nil)
+
+		require.Len(t, sink.events, 1)
+		assert.Equal(t, audit.ResultSuccess, sink.events[0].Result)
+		assert.Equal(t, audit.Actor{Kind: audit.ActorUser, ID: 7, Login: "jdoe"}, sink.events[0].Actor)
+	})
+
+	t.Run("should wait for the second factor", func(t *testing.T) {
+		s, sink := setupFakeSinkService()
+		s.postLoginHook(context.Background(), nil, newRequest(), twofactor.ErrCodeRequired.Errorf("code required"))
+		assert.Empty(t, sink.events)
+	})
+}
+
+type fakeSink struct {
+	events []audit.Event
+}
+
+func (s *fakeSink) Name() string { return "fake" }
+
+func (s *fakeSink) Write(ctx context.Context, event *audit.Event) error {
+	s.events = append(s.events, *event)
+	return nil
+}
+
+type fakeStore struct {
+	query     *audit.SearchQuery
+	olderThan time.Time
+	deleted   int64
+}
+
+func (s *fakeStore) Insert(ctx context.Context, event *audit.Event) error { return nil }
+
+func (s *fakeStore) Search(ctx context.Context, query *audit.SearchQuery) (*audit.SearchResult, error) {
+	s.query = query
+	return &audit.SearchResult{}, nil
+}
+
+func (s *fakeStore) DeleteOlderThan(ctx context.Context, olderThan time.Time) (int64, error) {
+	s.olderThan = olderThan
+	return s.deleted, nil
+}
+
+func setupFakeSinkService() (*Service, *fakeSink) {
+	cfg := setting.NewCfg()
+	cfg.Audit.Enabled = true
+	fake := &fakeSink{}
+	return &Service{cfg: cfg, sinks: []sink{fake}, log: log.NewNopLogger(), now: time.Now}, fake
+}
+
+func requestContext(signedInUser *user.SignedInUser) context.Context {
+	req := &http.Request{RemoteAddr: "10.0.0.1:51234", Header: http.Header{}}
+	return ctxkey.Set(context.Background(), &contextmodel.ReqContext{
+		Context:      &web.Context{Req: req},
+		SignedInUser: signedInUser,
+	})
+}
@@ -0,0 +1,83 @@
+package auditimpl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/services/audit"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// sink is a destination audit events are written to
+type sink interface {
+	Name() string
+	Write(ctx context.Context, event *audit.Event) error
+}
+
+func newSink(cfg *setting.Cfg, store store, name string) (sink, error) {
+	switch name {
+	case audit.SinkDatabase:
+		return &databaseSink{store: store}, nil
+	case audit.SinkFile:
+		path := cfg.Audit.FilePath
+		if path == "" {
+			path = filepath.Join(cfg.LogsPath, "audit.log")
+		}
+		return newFileSink(path)
+	case audit.SinkSyslog:
+		return newSyslogSink(cfg.Audit.SyslogNetwork, cfg.Audit.SyslogAddress, cfg.Audit.SyslogFacility, cfg.Audit.SyslogTag)
+	default:
+		return nil, fmt.Errorf("unknown audit sink %q", name)
+	}
+}
+
+// databaseSink records events in the audit_log table, they can be searched through the API
+type databaseSink struct {
+	store store
+}
+
+func (s *databaseSink) Name() string {
+	return audit.SinkDatabase
+}
+
+func (s *databaseSink) Write(ctx context.Context, event *audit.Event) error {
+	return s.store.Insert(ctx, event)
+}
+
+// fileSink appends events to a file, one JSON object per line
+type fileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func newFileSink(path string) (*fileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, err
+	}
+	// nolint:gosec
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log file: %w", err)
+	}
+	return &fileSink{file: file}, nil
+}
+
+func (s *fileSink) Name() string {
+	return audit.SinkFile
+}
+
+func (s *fileSink) Write(ctx context.Context, event *audit.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
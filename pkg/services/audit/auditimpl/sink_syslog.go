@@ -0,0 +1,61 @@
+//go:build !windows && !nacl && !plan9
+// +build !windows,!nacl,!plan9
+
+package auditimpl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+
+	"github.com/grafana/grafana/pkg/services/audit"
+)
+
+var facilities = map[string]syslog.Priority{
+	"user":   syslog.LOG_USER,
+	"daemon": syslog.LOG_DAEMON,
+	"auth":   syslog.LOG_AUTH,
+	"local0": syslog.LOG_LOCAL0,
+	"local1": syslog.LOG_LOCAL1,
+	"local2": syslog.LOG_LOCAL2,
+	"local3": syslog.LOG_LOCAL3,
+	"local4": syslog.LOG_LOCAL4,
+	"local5": syslog.LOG_LOCAL5,
+	"local6": syslog.LOG_LOCAL6,
+	"local7": syslog.LOG_LOCAL7,
+}
+
+// syslogSink sends events as JSON messages to a syslog server
+type syslogSink struct {
+	writer *syslog.Writer
+}
+
+func newSyslogSink(network, address, facility, tag string) (sink, error) {
+	priority, ok := facilities[facility]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility %q", facility)
+	}
+
+	writer, err := syslog.Dial(network, address, priority|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+	return &syslogSink{writer: writer}, nil
+}
+
+func (s *syslogSink) Name() string {
+	return audit.SinkSyslog
+}
+
+func (s *syslogSink) Write(ctx context.Context, event *audit.Event) error {
+	msg, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if event.Result == audit.ResultFailure {
+		return s.writer.Warning(string(msg))
+	}
+	return s.writer.Info(string(msg))
+}
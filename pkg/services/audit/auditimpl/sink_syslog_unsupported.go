@@ -0,0 +1,12 @@
+//go:build windows || nacl || plan9
+// +build windows nacl plan9
+
+package auditimpl
+
+import (
+	"errors"
+)
+
+func newSyslogSink(network, address, facility, tag string) (sink, error) {
+	return nil, errors.New("the syslog audit sink is not supported on this platform")
+}
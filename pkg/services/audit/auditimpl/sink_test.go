@@ -0,0 +1,34 @@
+package auditimpl
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/audit"
+)
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "audit.log")
+	sink, err := newFileSink(path)
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Write(context.Background(), &audit.Event{Action: audit.ActionDatasourceUpdate, Actor: audit.Actor{Login: "admin"}}))
+	require.NoError(t, sink.Write(context.Background(), &audit.Event{Action: audit.ActionOrgUserRoleUpdate}))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+
+	var event audit.Event
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &event))
+	assert.Equal(t, audit.ActionDatasourceUpdate, event.Action)
+	assert.Equal(t, "admin", event.Actor.Login)
+}
@@ -0,0 +1,165 @@
+package auditimpl
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/audit"
+)
+
+type auditLog struct {
+	ID           int64     `xorm:"pk autoincr 'id'"`
+	OrgID        int64     `xorm:"org_id"`
+	Created      time.Time `xorm:"'created'"`
+	ActorKind    string    `xorm:"actor_kind"`
+	ActorID      int64     `xorm:"actor_id"`
+	ActorLogin   string    `xorm:"actor_login"`
+	IPAddress    string    `xorm:"ip_address"`
+	Action       string    `xorm:"action"`
+	ResourceKind string    `xorm:"resource_kind"`
+	ResourceID   string    `xorm:"resource_id"`
+	ResourceName string    `xorm:"resource_name"`
+	Result       string    `xorm:"result"`
+	Details      string    `xorm:"details"`
+}
+
+func (auditLog) TableName() string { return "audit_log" }
+
+// utcTimestamp formats the time like the created column is written, in UTC, so it can be compared with it
+func utcTimestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+type store interface {
+	Insert(ctx context.Context, event *audit.Event) error
+	Search(ctx context.Context, query *audit.SearchQuery) (*audit.SearchResult, error)
+	DeleteOlderThan(ctx context.Context, olderThan time.Time) (int64, error)
+}
+
+type xormStore struct {
+	db db.DB
+}
+
+func (s *xormStore) Insert(ctx context.Context, event *audit.Event) error {
+	entry := &auditLog{
+		OrgID:        event.OrgID,
+		Created:      event.Timestamp,
+		ActorKind:    event.Actor.Kind,
+		ActorID:      event.Actor.ID,
+		ActorLogin:   event.Actor.Login,
+		IPAddress:    event.IPAddress,
+		Action:       event.Action,
+		ResourceKind: event.Resource.Kind,
+		ResourceID:   event.Resource.ID,
+		ResourceName: event.Resource.Name,
+		Result:       event.Result,
+	}
+	if len(event.Details) > 0 {
+		details, err := json.Marshal(event.Details)
+		if err != nil {
+			return err
+		}
+		entry.Details = string(details)
+	}
+
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(entry); err != nil {
+			return err
+		}
+		event.ID = entry.ID
+		return nil
+	})
+}
+
+func (s *xormStore) Search(ctx context.Context, query *audit.SearchQuery) (*audit.SearchResult, error) {
+	result := &audit.SearchResult{Events: make([]*audit.Event, 0), Page: query.Page, PerPage: query.Limit}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		filter := func() *db.Session {
+			sess.Table("audit_log")
+			if query.OrgID != 0 {
+				sess.Where("org_id = ?", query.OrgID)
+			}
+			if query.ActorID != 0 {
+				sess.And("actor_id = ?", query.ActorID)
+			}
+			if query.ActorLogin != "" {
+				sess.And("actor_login = ?", query.ActorLogin)
+			}
+			if query.Action != "" {
+				sess.And("action = ?", query.Action)
+			}
+			if query.ResourceKind != "" {
+				sess.And("resource_kind = ?", query.ResourceKind)
+			}
+			if query.ResourceID != "" {
+				sess.And("resource_id = ?", query.ResourceID)
+			}
+			if query.Result != "" {
+				sess.And("result = ?", query.Result)
+			}
+			if !query.From.IsZero() {
+				sess.And("created >= ?", utcTimestamp(query.From))
+			}
+			if !query.To.IsZero() {
+				sess.And("created <= ?", utcTimestamp(query.To))
+			}
+			return sess
+		}
+
+		count, err := filter().Count()
+		if err != nil {
+			return err
+		}
+		result.TotalCount = count
+
+		entries := make([]*auditLog, 0)
+		if err := filter().OrderBy("created DESC, id DESC").Limit(query.Limit, (query.Page-1)*query.Limit).Find(&entries); err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			event, err := entry.toEvent()
+			if err != nil {
+				return err
+			}
+			result.Events = append(result.Events, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *xormStore) DeleteOlderThan(ctx context.Context, olderThan time.Time) (int64, error) {
+	var affectedRows int64
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		affectedRows, err = sess.Where("created < ?", utcTimestamp(olderThan)).Delete(&auditLog{})
+		return err
+	})
+
+	return affectedRows, err
+}
+
+func (e *auditLog) toEvent() (*audit.Event, error) {
+	event := &audit.Event{
+		ID:        e.ID,
+		Timestamp: e.Created,
+		OrgID:     e.OrgID,
+		Actor:     audit.Actor{Kind: e.ActorKind, ID: e.ActorID, Login: e.ActorLogin},
+		IPAddress: e.IPAddress,
+		Action:    e.Action,
+		Resource:  audit.Resource{Kind: e.ResourceKind, ID: e.ResourceID, Name: e.ResourceName},
+		Result:    e.Result,
+	}
+	if e.Details != "" {
+		if err := json.Unmarshal([]byte(e.Details), &event.Details); err != nil {
+			return nil, err
+		}
+	}
+	return event, nil
+}
@@ -0,0 +1,98 @@
+package auditimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/audit"
+)
+
+func TestIntegrationAuditLogStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	store := &xormStore{db: db.InitTestDB(t)}
+	created := time.Date(2023, 11, 14, 22, 0, 0, 0, time.UTC)
+
+	datasourceUpdate := &audit.Event{
+		Timestamp: created,
+		OrgID:     1,
+		Actor:     audit.Actor{Kind: audit.ActorUser, ID: 2, Login: "admin"},
+		IPAddress: "10.0.0.1",
+		Action:    audit.ActionDatasourceUpdate,
+		Resource:  audit.Resource{Kind: audit.ResourceDatasource, ID: "prom", Name: "Prometheus"},
+		Result:    audit.ResultSuccess,
+		Details:   map[string]any{"type": "prometheus"},
+	}
+	require.NoError(t, store.Insert(ctx, datasourceUpdate))
+	assert.NotZero(t, datasourceUpdate.ID)
+
+	require.NoError(t, store.Insert(ctx, &audit.Event{
+		Timestamp: created.Add(time.Hour),
+		OrgID:     2,
+		Actor:     audit.Actor{Kind: audit.ActorUser, ID: 2, Login: "admin"},
+		Action:    audit.ActionOrgUserRoleUpdate,
+		Resource:  audit.Resource{Kind: audit.ResourceUser, ID: "3"},
+		Result:    audit.ResultSuccess,
+	}))
+
+	t.Run("Should return events newest first", func(t *testing.T) {
+		result, err := store.Search(ctx, &audit.SearchQuery{Page: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), result.TotalCount)
+		require.Len(t, result.Events, 2)
+		assert.Equal(t, audit.ActionOrgUserRoleUpdate, result.Events[0].Action)
+
+		event := result.Events[1]
+		assert.Equal(t, int64(1), event.OrgID)
+		assert.Equal(t, audit.Actor{Kind: audit.ActorUser, ID: 2, Login: "admin"}, event.Actor)
+		assert.Equal(t, "10.0.0.1", event.IPAddress)
+		assert.Equal(t, audit.Resource{Kind: audit.ResourceDatasource, ID: "prom", Name: "Prometheus"}, event.Resource)
+		assert.Equal(t, map[string]any{"type": "prometheus"}, event.Details)
+	})
+
+	t.Run("Should filter events", func(t *testing.T) {
+		result, err := store.Search(ctx, &audit.SearchQuery{OrgID: 1, ResourceKind: audit.ResourceDatasource, Page: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, result.Events, 1)
+		assert.Equal(t, "prom", result.Events[0].Resource.ID)
+
+		result, err = store.Search(ctx, &audit.SearchQuery{ActorID: 3, Page: 1, Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, result.Events)
+	})
+
+	t.Run("Should filter events by time in UTC", func(t *testing.T) {
+		zone := time.FixedZone("UTC-5", -5*60*60)
+		result, err := store.Search(ctx, &audit.SearchQuery{From: created.Add(30 * time.Minute).In(zone), To: created.Add(2 * time.Hour).In(zone), Page: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, result.Events, 1)
+		assert.Equal(t, audit.ActionOrgUserRoleUpdate, result.Events[0].Action)
+	})
+
+	t.Run("Should paginate events", func(t *testing.T) {
+		result, err := store.Search(ctx, &audit.SearchQuery{Page: 2, Limit: 1})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), result.TotalCount)
+		require.Len(t, result.Events, 1)
+		assert.Equal(t, audit.ActionDatasourceUpdate, result.Events[0].Action)
+	})
+
+	t.Run("Should delete events older than the given time", func(t *testing.T) {
+		deleted, err := store.DeleteOlderThan(ctx, created.Add(30*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		result, err := store.Search(ctx, &audit.SearchQuery{Page: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, result.Events, 1)
+		assert.Equal(t, audit.ActionOrgUserRoleUpdate, result.Events[0].Action)
+	})
+}
@@ -0,0 +1,44 @@
+package audittest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/services/audit"
+)
+
+var _ audit.Service = new(FakeService)
+
+type FakeService struct {
+	ExpectedSearchResult *audit.SearchResult
+	ExpectedDeleted      int64
+	ExpectedErr          error
+
+	mu     sync.Mutex
+	events []audit.Event
+}
+
+func NewFakeService() *FakeService {
+	return &FakeService{}
+}
+
+func (f *FakeService) Log(ctx context.Context, event audit.Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event)
+}
+
+// Events returns the events logged so far
+func (f *FakeService) Events() []audit.Event {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]audit.Event(nil), f.events...)
+}
+
+func (f *FakeService) Search(ctx context.Context, query *audit.SearchQuery) (*audit.SearchResult, error) {
+	return f.ExpectedSearchResult, f.ExpectedErr
+}
+
+func (f *FakeService) DeleteExpired(ctx context.Context) (int64, error) {
+	return f.ExpectedDeleted, f.ExpectedErr
+}
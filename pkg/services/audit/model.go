@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"time"
+)
+
+// Actions of the audit events
+const (
+	ActionLogin                     = "login"
+	ActionPermissionsUpdate         = "permissions.update"
+	ActionOrgUserAdd                = "org.users.add"
+	ActionOrgUserRoleUpdate         = "org.users.role.update"
+	ActionOrgUserRemove             = "org.users.remove"
+	ActionUserGrafanaAdminUpdate    = "users.grafana-admin.update"
+	ActionUserLogout                = "users.logout"
+	ActionUserUnlock                = "users.unlock"
+	ActionDatasourceCreate          = "datasources.create"
+	ActionDatasourceUpdate          = "datasources.update"
+	ActionDatasourceDelete          = "datasources.delete"
+	ActionAPIKeyCreate              = "api-keys.create"
+	ActionAPIKeyDelete              = "api-keys.delete"
+	ActionServiceAccountTokenCreate = "serviceaccounts.tokens.create"
+	ActionServiceAccountTokenDelete = "serviceaccounts.tokens.delete"
+)
+
+// Results of the audit events
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Kinds of actors
+const (
+	ActorUser           = "user"
+	ActorServiceAccount = "service-account"
+	ActorAPIKey         = "api-key"
+	ActorAnonymous      = "anonymous"
+)
+
+// Kinds of resources, resource permissions use the resource of the managed permissions such as dashboards or folders
+const (
+	ResourceUser                = "users"
+	ResourceDatasource          = "datasources"
+	ResourceAPIKey              = "api-keys"
+	ResourceServiceAccountToken = "serviceaccounts.tokens"
+)
+
+// Sinks events can be written to
+const (
+	SinkDatabase = "database"
+	SinkFile     = "file"
+	SinkSyslog   = "syslog"
+)
+
+// Event is a record of who did what on which resource
+type Event struct {
+	ID        int64     `json:"id,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	OrgID     int64     `json:"orgId"`
+	Actor     Actor     `json:"actor"`
+	IPAddress string    `json:"ipAddress,omitempty"`
+	Action    string    `json:"action"`
+	Resource  Resource  `json:"resource"`
+	Result    string    `json:"result"`
+	// Details holds the attributes specific to the action, such as the new role or the permissions set
+	Details map[string]any `json:"details,omitempty"`
+}
+
+type Actor struct {
+	Kind  string `json:"kind,omitempty"`
+	ID    int64  `json:"id,omitempty"`
+	Login string `json:"login,omitempty"`
+}
+
+type Resource struct {
+	Kind string `json:"kind,omitempty"`
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type SearchQuery struct {
+	// OrgID restricts the events to an organization, events of all organizations are returned when it is zero
+	OrgID        int64
+	ActorID      int64
+	ActorLogin   string
+	Action       string
+	ResourceKind string
+	ResourceID   string
+	Result       string
+	From         time.Time
+	To           time.Time
+	Page         int
+	Limit        int
+}
+
+type SearchResult struct {
+	TotalCount int64    `json:"totalCount"`
+	Events     []*Event `json:"events"`
+	Page       int      `json:"page"`
+	PerPage    int      `json:"perPage"`
+}
@@ -54,6 +54,13 @@ func (e *TokenExpiredError) Error() string {
 	return fmt.Sprintf("%s: user token expired", ErrInvalidSessionToken)
 }
 
+// UserSession is an active user auth token together with the user it belongs to
+type UserSession struct {
+	UserToken
+	Login string
+	Email string
+}
+
 type RevokeAuthTokenCmd struct {
 	AuthTokenId int64 `json:"authTokenId"`
 }
@@ -77,6 +84,8 @@ type UserTokenService interface {
 	GetUserToken(ctx context.Context, userId, userTokenId int64) (*UserToken, error)
 	GetUserTokens(ctx context.Context, userId int64) ([]*UserToken, error)
 	GetUserRevokedTokens(ctx context.Context, userId int64) ([]*UserToken, error)
+	// GetOrgUserSessions returns the active sessions of all the users that are members of the org
+	GetOrgUserSessions(ctx context.Context, orgID int64) ([]*UserSession, error)
 }
 
 type UserTokenBackgroundService interface {
@@ -90,9 +90,11 @@ func (s *UserAuthTokenService) CreateToken(ctx context.Context, user *user.User,
 		AuthTokenSeen: false,
 	}
 
-	err = s.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
-		_, err = dbSession.Insert(&userAuthToken)
-		return err
+	err = s.sqlStore.WithTransactionalDbSession(ctx, func(dbSession *db.Session) error {
+		if _, err := dbSession.Insert(&userAuthToken); err != nil {
+			return err
+		}
+		return s.revokeExceedingSessions(ctx, dbSession, user.ID)
 	})
 
 	if err != nil {
@@ -136,8 +138,9 @@ func (s *UserAuthTokenService) LookupToken(ctx context.Context, unhashedToken st
 	if model.RevokedAt > 0 {
 		ctxLogger.Debug("user token has been revoked", "user ID", model.UserId, "token ID", model.Id)
 		return nil, &auth.TokenRevokedError{
-			UserID:  model.UserId,
-			TokenID: model.Id,
+			UserID:                model.UserId,
+			TokenID:               model.Id,
+			MaxConcurrentSessions: int64(s.cfg.LoginMaxConcurrentSessions),
 		}
 	}
 
@@ -149,6 +152,18 @@ func (s *UserAuthTokenService) LookupToken(ctx context.Context, unhashedToken st
 		}
 	}
 
+	idle, err := s.exceedsRoleIdleTimeout(ctx, &model)
+	if err != nil {
+		return nil, err
+	}
+	if idle {
+		ctxLogger.Debug("user token has exceeded the idle timeout of the user role", "user ID", model.UserId, "token ID", model.Id)
+		return nil, &auth.TokenExpiredError{
+			UserID:  model.UserId,
+			TokenID: model.Id,
+		}
+	}
+
 	// Current incoming token is the previous auth token in the DB and the auth_token_seen is true
 	if model.AuthToken != hashedToken && model.PrevAuthToken == hashedToken && model.AuthTokenSeen {
 		model.AuthTokenSeen = false
@@ -552,6 +567,111 @@ func (s *UserAuthTokenService) GetUserRevokedTokens(ctx context.Context, userId
 	return result, err
 }
 
+func (s *UserAuthTokenService) GetOrgUserSessions(ctx context.Context, orgID int64) ([]*auth.UserSession, error) {
+	result := []*auth.UserSession{}
+	err := s.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
+		var rows []*orgUserAuthToken
+		err := dbSession.Table("user_auth_token").
+			Select("user_auth_token.*, u.login, u.email").
+			Join("INNER", "org_user", "org_user.user_id = user_auth_token.user_id").
+			Join("INNER", s.sqlStore.GetDialect().Quote("user")+" AS u", "u.id = user_auth_token.user_id").
+			Where("org_user.org_id = ? AND user_auth_token.created_at > ? AND user_auth_token.rotated_at > ? AND user_auth_token.revoked_at = 0",
+				orgID,
+				s.createdAfterParam(),
+				s.rotatedAfterParam()).
+			Desc("user_auth_token.seen_at").
+			Find(&rows)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			session := auth.UserSession{Login: row.Login, Email: row.Email}
+			if err := row.Token.toUserToken(&session.UserToken); err != nil {
+				return err
+			}
+			result = append(result, &session)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// revokeExceedingSessions soft revokes the oldest sessions of the user
+// when they have more than the configured maximum of concurrent sessions.
+func (s *UserAuthTokenService) revokeExceedingSessions(ctx context.Context, dbSession *db.Session, userID int64) error {
+	limit := s.cfg.LoginMaxConcurrentSessions
+	if limit <= 0 {
+		return nil
+	}
+
+	var ids []int64
+	err := dbSession.Table("user_auth_token").Cols("id").
+		Where("user_id = ? AND created_at > ? AND rotated_at > ? AND revoked_at = 0",
+			userID,
+			s.createdAfterParam(),
+			s.rotatedAfterParam()).
+		Desc("created_at", "id").
+		Find(&ids)
+	if err != nil {
+		return err
+	}
+
+	if len(ids) <= limit {
+		return nil
+	}
+
+	exceeding := ids[limit:]
+	affected, err := dbSession.Table("user_auth_token").In("id", exceeding).
+		Update(map[string]any{"revoked_at": getTime().Unix()})
+	if err != nil {
+		return err
+	}
+
+	s.log.FromContext(ctx).Debug("revoked sessions exceeding the concurrent sessions limit", "userId", userID, "limit", limit, "count", affected)
+	return nil
+}
+
+// exceedsRoleIdleTimeout checks the token against the idle timeout configured
+// for the role the user has in their current org, if any.
+func (s *UserAuthTokenService) exceedsRoleIdleTimeout(ctx context.Context, model *userAuthToken) (bool, error) {
+	if len(s.cfg.LoginMaxInactiveLifetimeByRole) == 0 {
+		return false, nil
+	}
+
+	// avoid looking up the role of users that have been active recently enough for any role
+	var shortest time.Duration
+	for _, d := range s.cfg.LoginMaxInactiveLifetimeByRole {
+		if shortest == 0 || d < shortest {
+			shortest = d
+		}
+	}
+	if model.RotatedAt > getTime().Add(-shortest).Unix() {
+		return false, nil
+	}
+
+	var role string
+	err := s.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
+		userTable := s.sqlStore.GetDialect().Quote("user")
+		_, err := dbSession.SQL(`SELECT org_user.role FROM org_user
+			INNER JOIN `+userTable+` ON `+userTable+`.id = org_user.user_id AND `+userTable+`.org_id = org_user.org_id
+			WHERE org_user.user_id = ?`, model.UserId).Get(&role)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	timeout, ok := s.cfg.LoginMaxInactiveLifetimeByRole[role]
+	if !ok {
+		return false, nil
+	}
+
+	return model.RotatedAt <= getTime().Add(-timeout).Unix(), nil
+}
+
 func (s *UserAuthTokenService) reportActiveTokenCount(ctx context.Context, _ *quota.ScopeParameters) (*quota.Map, error) {
 	var count int64
 	var err error
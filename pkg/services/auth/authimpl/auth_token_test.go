@@ -525,6 +525,83 @@ func TestUserAuthToken(t *testing.T) {
 		})
 	})
 
+	t.Run("revokes the oldest sessions when exceeding the concurrent sessions limit", func(t *testing.T) {
+		ctx := createTestContext(t)
+		ctx.tokenService.cfg.LoginMaxConcurrentSessions = 2
+
+		tokens := make([]*auth.UserToken, 0, 3)
+		for i := 0; i < 3; i++ {
+			getTime = func() time.Time { return now.Add(time.Duration(i) * time.Minute) }
+			token, err := ctx.tokenService.CreateToken(context.Background(), user, net.ParseIP("192.168.10.11"), "some user agent")
+			require.NoError(t, err)
+			tokens = append(tokens, token)
+		}
+
+		_, err := ctx.tokenService.LookupToken(context.Background(), tokens[0].UnhashedToken)
+		var revokedErr *auth.TokenRevokedError
+		require.ErrorAs(t, err, &revokedErr)
+		require.Equal(t, int64(2), revokedErr.MaxConcurrentSessions)
+
+		for _, token := range tokens[1:] {
+			_, err := ctx.tokenService.LookupToken(context.Background(), token.UnhashedToken)
+			require.NoError(t, err)
+		}
+
+		active, err := ctx.tokenService.GetUserTokens(context.Background(), user.ID)
+		require.NoError(t, err)
+		require.Len(t, active, 2)
+	})
+
+	t.Run("expires tokens after the idle timeout of the user role", func(t *testing.T) {
+		ctx := createTestContext(t)
+		ctx.tokenService.cfg.LoginMaxInactiveLifetimeByRole = map[string]time.Duration{"Viewer": time.Hour}
+		getTime = func() time.Time { return now }
+
+		viewer := ctx.createOrgUser(t, "viewer", 1, "Viewer")
+		editor := ctx.createOrgUser(t, "editor", 1, "Editor")
+
+		viewerToken, err := ctx.tokenService.CreateToken(context.Background(), viewer, nil, "")
+		require.NoError(t, err)
+		editorToken, err := ctx.tokenService.CreateToken(context.Background(), editor, nil, "")
+		require.NoError(t, err)
+
+		getTime = func() time.Time { return now.Add(59 * time.Minute) }
+		_, err = ctx.tokenService.LookupToken(context.Background(), viewerToken.UnhashedToken)
+		require.NoError(t, err)
+
+		getTime = func() time.Time { return now.Add(time.Hour) }
+		_, err = ctx.tokenService.LookupToken(context.Background(), viewerToken.UnhashedToken)
+		require.IsType(t, &auth.TokenExpiredError{}, err)
+
+		_, err = ctx.tokenService.LookupToken(context.Background(), editorToken.UnhashedToken)
+		require.NoError(t, err)
+	})
+
+	t.Run("lists the active sessions of the org users", func(t *testing.T) {
+		ctx := createTestContext(t)
+		getTime = func() time.Time { return now }
+
+		member := ctx.createOrgUser(t, "member", 1, "Viewer")
+		other := ctx.createOrgUser(t, "other", 2, "Viewer")
+
+		memberToken, err := ctx.tokenService.CreateToken(context.Background(), member, net.ParseIP("10.0.0.1"), "some user agent")
+		require.NoError(t, err)
+		revoked, err := ctx.tokenService.CreateToken(context.Background(), member, net.ParseIP("10.0.0.2"), "some user agent")
+		require.NoError(t, err)
+		require.NoError(t, ctx.tokenService.RevokeToken(context.Background(), revoked, true))
+		_, err = ctx.tokenService.CreateToken(context.Background(), other, net.ParseIP("10.0.0.3"), "some user agent")
+		require.NoError(t, err)
+
+		sessions, err := ctx.tokenService.GetOrgUserSessions(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, memberToken.Id, sessions[0].Id)
+		assert.Equal(t, member.ID, sessions[0].UserId)
+		assert.Equal(t, "10.0.0.1", sessions[0].ClientIp)
+		assert.Equal(t, "member", sessions[0].Login)
+		assert.Equal(t, "member@example.org", sessions[0].Email)
+	})
+
 	t.Run("When populating userAuthToken from UserToken should copy all properties", func(t *testing.T) {
 		ut := auth.UserToken{
 			Id:            1,
@@ -638,6 +715,21 @@ func (c *testContext) getAuthTokenByID(id int64) (*userAuthToken, error) {
 	return res, err
 }
 
+func (c *testContext) createOrgUser(t *testing.T, login string, orgID int64, role string) *user.User {
+	t.Helper()
+	u := &user.User{Login: login, Email: login + "@example.org", OrgID: orgID, Created: getTime(), Updated: getTime()}
+	err := c.sqlstore.WithDbSession(context.Background(), func(sess *db.Session) error {
+		if _, err := sess.Insert(u); err != nil {
+			return err
+		}
+		_, err := sess.Exec("INSERT INTO org_user (org_id, user_id, role, created, updated) VALUES (?, ?, ?, ?, ?)",
+			orgID, u.ID, role, getTime(), getTime())
+		return err
+	})
+	require.NoError(t, err)
+	return u
+}
+
 func (c *testContext) markAuthTokenAsSeen(id int64) (bool, error) {
 	hasRowsAffected := false
 	err := c.sqlstore.WithDbSession(context.Background(), func(sess *db.Session) error {
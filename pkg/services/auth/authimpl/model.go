@@ -22,6 +22,13 @@ type userAuthToken struct {
 	UnhashedToken string `xorm:"-"`
 }
 
+// orgUserAuthToken is a user auth token joined with the user it belongs to
+type orgUserAuthToken struct {
+	Token userAuthToken `xorm:"extends"`
+	Login string
+	Email string
+}
+
 func userAuthTokenFromUserToken(ut *auth.UserToken) (*userAuthToken, error) {
 	var uat userAuthToken
 	err := uat.fromUserToken(ut)
@@ -25,6 +25,7 @@ type FakeUserAuthTokenService struct {
 	GetUserTokensProvider        func(ctx context.Context, userId int64) ([]*auth.UserToken, error)
 	GetUserRevokedTokensProvider func(ctx context.Context, userId int64) ([]*auth.UserToken, error)
 	BatchRevokedTokenProvider    func(ctx context.Context, userIds []int64) error
+	GetOrgUserSessionsProvider   func(ctx context.Context, orgID int64) ([]*auth.UserSession, error)
 }
 
 func NewFakeUserAuthTokenService() *FakeUserAuthTokenService {
@@ -62,6 +63,9 @@ func NewFakeUserAuthTokenService() *FakeUserAuthTokenService {
 		GetUserTokensProvider: func(ctx context.Context, userId int64) ([]*auth.UserToken, error) {
 			return nil, nil
 		},
+		GetOrgUserSessionsProvider: func(ctx context.Context, orgID int64) ([]*auth.UserSession, error) {
+			return nil, nil
+		},
 	}
 }
 
@@ -112,6 +116,10 @@ func (s *FakeUserAuthTokenService) GetUserRevokedTokens(ctx context.Context, use
 	return s.GetUserRevokedTokensProvider(context.Background(), userId)
 }
 
+func (s *FakeUserAuthTokenService) GetOrgUserSessions(ctx context.Context, orgID int64) ([]*auth.UserSession, error) {
+	return s.GetOrgUserSessionsProvider(context.Background(), orgID)
+}
+
 func (s *FakeUserAuthTokenService) BatchRevokeAllUserTokens(ctx context.Context, userIds []int64) error {
 	return s.BatchRevokedTokenProvider(ctx, userIds)
 }
@@ -37,13 +37,27 @@ func (s *AuthService) init() error {
 		return nil
 	}
 
-	if err := s.initClaimExpectations(); err != nil {
+	if _, err := ParseOrgMapping(s.Cfg.JWTAuthOrgMapping); err != nil {
 		return err
 	}
-	if err := s.initKeySet(); err != nil {
+	if err := s.initIssuers(); err != nil {
 		return err
 	}
 
+	// the [auth.jwt] key set is optional when trusted issuers are configured
+	if len(s.issuers) > 0 && !isKeySetConfigured(s.Cfg.JWTAuthKeyFile, s.Cfg.JWTAuthJWKSetFile, s.Cfg.JWTAuthJWKSetURL) {
+		return nil
+	}
+
+	if err := s.verifier.initClaimExpectations(s.Cfg.JWTAuthExpectClaims); err != nil {
+		return err
+	}
+	keySet, err := s.newKeySet(s.Cfg.JWTAuthKeyFile, s.Cfg.JWTAuthJWKSetFile, s.Cfg.JWTAuthJWKSetURL, s.Cfg.JWTAuthCacheTTL)
+	if err != nil {
+		return err
+	}
+	s.keySet = keySet
+
 	return nil
 }
 
@@ -51,8 +65,15 @@ type AuthService struct {
 	Cfg         *setting.Cfg
 	RemoteCache *remotecache.RemoteCache
 
+	// verifier is used for tokens which are not issued by one of the trusted issuers
+	verifier
+	issuers map[string]*verifier
+	log     log.Logger
+}
+
+// verifier verifies tokens with a key set and validates their claims.
+type verifier struct {
 	keySet           keySet
-	log              log.Logger
 	expect           map[string]interface{}
 	expectRegistered jwt.Expected
 }
@@ -74,7 +95,12 @@ func (s *AuthService) Verify(ctx context.Context, strToken string) (JWTClaims, e
 		return nil, err
 	}
 
-	keys, err := s.keySet.Key(ctx, token.Headers[0].KeyID)
+	v, err := s.verifierFor(token)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := v.keySet.Key(ctx, token.Headers[0].KeyID)
 	if err != nil {
 		return nil, err
 	}
@@ -96,13 +122,34 @@ func (s *AuthService) Verify(ctx context.Context, strToken string) (JWTClaims, e
 
 	s.log.Debug("Validating JSON Web Token claims")
 
-	if err = s.validateClaims(claims); err != nil {
+	if err = v.validateClaims(claims); err != nil {
 		return nil, err
 	}
 
 	return claims, nil
 }
 
+// verifierFor returns the verifier of the trusted issuer named in the "iss" claim
+// of the token, falling back to the [auth.jwt] key set. The claim is read without
+// verification, the token signature is checked with the key set of the issuer.
+func (s *AuthService) verifierFor(token *jwt.JSONWebToken) (*verifier, error) {
+	if len(s.issuers) > 0 {
+		var claims jwt.Claims
+		if err := token.UnsafeClaimsWithoutVerification(&claims); err != nil {
+			return nil, err
+		}
+		if v, ok := s.issuers[claims.Issuer]; ok {
+			return v, nil
+		}
+	}
+
+	if s.keySet == nil {
+		return nil, ErrUntrustedIssuer
+	}
+
+	return &s.verifier, nil
+}
+
 // HasSubClaim checks if the provided JWT token contains a non-empty "sub" claim.
 // Returns true if it contains, otherwise returns false.
 func HasSubClaim(jwtToken string) bool {
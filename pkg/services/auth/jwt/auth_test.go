@@ -2,6 +2,7 @@ package jwt
 
 import (
 	"context"
+	"crypto/rsa"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
@@ -344,6 +345,60 @@ func jwkCachingScenario(t *testing.T, desc string, fn cachingScenarioFunc, cbs .
 	})
 }
 
+func TestVerifyWithTrustedIssuers(t *testing.T) {
+	configureIssuers := func(t *testing.T, cfg *setting.Cfg) {
+		cfg.JWTAuthIssuers = []setting.JWTIssuerSettings{
+			{Name: "portal", Issuer: "https://portal.example.com", KeyFile: writePKIXPublicKeyFile(t, rsaKeys[1]), ExpectClaims: "{}"},
+			{Name: "partner", Issuer: "https://partner.example.com", KeyFile: writePKIXPublicKeyFile(t, rsaKeys[2]), ExpectClaims: `{"aud": "grafana"}`},
+		}
+	}
+
+	scenario(t, "verifies tokens with the key set of their issuer", func(t *testing.T, sc scenarioContext) {
+		_, err := sc.authJWTSvc.Verify(sc.ctx, sign(t, rsaKeys[1], jwt.Claims{Subject: subject, Issuer: "https://portal.example.com"}))
+		require.NoError(t, err)
+
+		_, err = sc.authJWTSvc.Verify(sc.ctx, sign(t, rsaKeys[2], jwt.Claims{Subject: subject, Issuer: "https://partner.example.com", Audience: jwt.Audience{"grafana"}}))
+		require.NoError(t, err)
+	}, configureIssuers)
+
+	scenario(t, "rejects a token signed with the key of another issuer", func(t *testing.T, sc scenarioContext) {
+		_, err := sc.authJWTSvc.Verify(sc.ctx, sign(t, rsaKeys[1], jwt.Claims{Subject: subject, Issuer: "https://partner.example.com", Audience: jwt.Audience{"grafana"}}))
+		require.Error(t, err)
+	}, configureIssuers)
+
+	scenario(t, "validates the claim expectations of the issuer", func(t *testing.T, sc scenarioContext) {
+		_, err := sc.authJWTSvc.Verify(sc.ctx, sign(t, rsaKeys[2], jwt.Claims{Subject: subject, Issuer: "https://partner.example.com", Audience: jwt.Audience{"other"}}))
+		require.Error(t, err)
+	}, configureIssuers)
+
+	scenario(t, "rejects a token from an unknown issuer", func(t *testing.T, sc scenarioContext) {
+		_, err := sc.authJWTSvc.Verify(sc.ctx, sign(t, rsaKeys[1], jwt.Claims{Subject: subject, Issuer: "https://unknown.example.com"}))
+		require.ErrorIs(t, err, ErrUntrustedIssuer)
+	}, configureIssuers)
+
+	scenario(t, "falls back to the default key set for unknown issuers", func(t *testing.T, sc scenarioContext) {
+		_, err := sc.authJWTSvc.Verify(sc.ctx, sign(t, rsaKeys[0], jwt.Claims{Subject: subject, Issuer: "https://unknown.example.com"}))
+		require.NoError(t, err)
+
+		_, err = sc.authJWTSvc.Verify(sc.ctx, sign(t, rsaKeys[1], jwt.Claims{Subject: subject, Issuer: "https://portal.example.com"}))
+		require.NoError(t, err)
+	}, configureIssuers, configurePKIXPublicKeyFile)
+
+	t.Run("requires the issuer claim value", func(t *testing.T) {
+		_, err := initAuthService(t, func(t *testing.T, cfg *setting.Cfg) {
+			cfg.JWTAuthIssuers = []setting.JWTIssuerSettings{{Name: "portal", KeyFile: writePKIXPublicKeyFile(t, rsaKeys[1]), ExpectClaims: "{}"}}
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("requires a key set for every issuer", func(t *testing.T) {
+		_, err := initAuthService(t, func(t *testing.T, cfg *setting.Cfg) {
+			cfg.JWTAuthIssuers = []setting.JWTIssuerSettings{{Name: "portal", Issuer: "https://portal.example.com", ExpectClaims: "{}"}}
+		})
+		require.ErrorIs(t, err, ErrKeySetIsNotConfigured)
+	})
+}
+
 func TestBase64Paddings(t *testing.T) {
 	key := rsaKeys[0]
 
@@ -401,6 +456,12 @@ func scenarioRunner(fn scenarioFunc, cbs ...configureFunc) func(t *testing.T) {
 func configurePKIXPublicKeyFile(t *testing.T, cfg *setting.Cfg) {
 	t.Helper()
 
+	cfg.JWTAuthKeyFile = writePKIXPublicKeyFile(t, rsaKeys[0])
+}
+
+func writePKIXPublicKeyFile(t *testing.T, key *rsa.PrivateKey) string {
+	t.Helper()
+
 	file, err := os.CreateTemp(os.TempDir(), "public-key-*.pem")
 	require.NoError(t, err)
 	t.Cleanup(func() {
@@ -409,7 +470,7 @@ func configurePKIXPublicKeyFile(t *testing.T, cfg *setting.Cfg) {
 		}
 	})
 
-	blockBytes, err := x509.MarshalPKIXPublicKey(rsaKeys[0].Public())
+	blockBytes, err := x509.MarshalPKIXPublicKey(key.Public())
 	require.NoError(t, err)
 
 	require.NoError(t, pem.Encode(file, &pem.Block{
@@ -418,5 +479,5 @@ func configurePKIXPublicKeyFile(t *testing.T, cfg *setting.Cfg) {
 	}))
 	require.NoError(t, file.Close())
 
-	cfg.JWTAuthKeyFile = file.Name()
+	return file.Name()
 }
@@ -0,0 +1,43 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrUntrustedIssuer = errors.New("token is not issued by a trusted issuer")
+
+// initIssuers initializes a verifier for every [auth.jwt.issuer.<name>] section.
+// Tokens are matched to an issuer by their "iss" claim.
+func (s *AuthService) initIssuers() error {
+	s.issuers = make(map[string]*verifier, len(s.Cfg.JWTAuthIssuers))
+
+	for _, issuer := range s.Cfg.JWTAuthIssuers {
+		if issuer.Issuer == "" {
+			return fmt.Errorf("jwt issuer %q: issuer is required", issuer.Name)
+		}
+		if _, ok := s.issuers[issuer.Issuer]; ok {
+			return fmt.Errorf("jwt issuer %q: issuer %q is configured more than once", issuer.Name, issuer.Issuer)
+		}
+		if _, err := ParseOrgMapping(issuer.OrgMapping); err != nil {
+			return fmt.Errorf("jwt issuer %q: %w", issuer.Name, err)
+		}
+
+		v := &verifier{}
+		if err := v.initClaimExpectations(issuer.ExpectClaims); err != nil {
+			return fmt.Errorf("jwt issuer %q: %w", issuer.Name, err)
+		}
+		// the issuer is selected by the unverified "iss" claim, the verified claim must match it
+		v.expectRegistered.Issuer = issuer.Issuer
+
+		keySet, err := s.newKeySet(issuer.KeyFile, issuer.JWKSetFile, issuer.JWKSetURL, issuer.CacheTTL)
+		if err != nil {
+			return fmt.Errorf("jwt issuer %q: %w", issuer.Name, err)
+		}
+		v.keySet = keySet
+
+		s.issuers[issuer.Issuer] = v
+	}
+
+	return nil
+}
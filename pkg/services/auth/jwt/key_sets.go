@@ -42,15 +42,19 @@ type keySetHTTP struct {
 	cacheExpiration time.Duration
 }
 
-func (s *AuthService) checkKeySetConfiguration() error {
+func isKeySetConfigured(keyFile, jwkSetFile, jwkSetURL string) bool {
+	return keyFile != "" || jwkSetFile != "" || jwkSetURL != ""
+}
+
+func checkKeySetConfiguration(keyFile, jwkSetFile, jwkSetURL string) error {
 	var count int
-	if s.Cfg.JWTAuthKeyFile != "" {
+	if keyFile != "" {
 		count++
 	}
-	if s.Cfg.JWTAuthJWKSetFile != "" {
+	if jwkSetFile != "" {
 		count++
 	}
-	if s.Cfg.JWTAuthJWKSetURL != "" {
+	if jwkSetURL != "" {
 		count++
 	}
 
@@ -65,102 +69,102 @@ func (s *AuthService) checkKeySetConfiguration() error {
 	return nil
 }
 
-func (s *AuthService) initKeySet() error {
-	if err := s.checkKeySetConfiguration(); err != nil {
-		return err
+func (s *AuthService) newKeySet(keyFile, jwkSetFile, jwkSetURL string, cacheTTL time.Duration) (keySet, error) {
+	if err := checkKeySetConfiguration(keyFile, jwkSetFile, jwkSetURL); err != nil {
+		return nil, err
 	}
 
-	if keyFilePath := s.Cfg.JWTAuthKeyFile; keyFilePath != "" {
+	if keyFile != "" {
 		// nolint:gosec
 		// We can ignore the gosec G304 warning on this one because `fileName` comes from grafana configuration file
-		file, err := os.Open(keyFilePath)
+		file, err := os.Open(keyFile)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		defer func() {
 			if err := file.Close(); err != nil {
-				s.log.Warn("Failed to close file", "path", keyFilePath, "err", err)
+				s.log.Warn("Failed to close file", "path", keyFile, "err", err)
 			}
 		}()
 
 		data, err := io.ReadAll(file)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		block, _ := pem.Decode(data)
 		if block == nil {
-			return ErrFailedToParsePemFile
+			return nil, ErrFailedToParsePemFile
 		}
 
 		var key interface{}
 		switch block.Type {
 		case "PUBLIC KEY":
 			if key, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
-				return err
+				return nil, err
 			}
 		case "PRIVATE KEY":
 			if key, err = x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
-				return err
+				return nil, err
 			}
 		case "RSA PUBLIC KEY":
 			if key, err = x509.ParsePKCS1PublicKey(block.Bytes); err != nil {
-				return err
+				return nil, err
 			}
 		case "RSA PRIVATE KEY":
 			if key, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
-				return err
+				return nil, err
 			}
 		case "EC PRIVATE KEY":
 			if key, err = x509.ParseECPrivateKey(block.Bytes); err != nil {
-				return err
+				return nil, err
 			}
 		default:
-			return fmt.Errorf("unknown pem block type %q", block.Type)
+			return nil, fmt.Errorf("unknown pem block type %q", block.Type)
 		}
 
-		s.keySet = keySetJWKS{
+		return keySetJWKS{
 			jose.JSONWebKeySet{
 				Keys: []jose.JSONWebKey{{Key: key}},
 			},
-		}
-	} else if keyFilePath := s.Cfg.JWTAuthJWKSetFile; keyFilePath != "" {
+		}, nil
+	} else if jwkSetFile != "" {
 		// nolint:gosec
 		// We can ignore the gosec G304 warning on this one because `fileName` comes from grafana configuration file
-		file, err := os.Open(keyFilePath)
+		file, err := os.Open(jwkSetFile)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		defer func() {
 			if err := file.Close(); err != nil {
-				s.log.Warn("Failed to close file", "path", keyFilePath, "err", err)
+				s.log.Warn("Failed to close file", "path", jwkSetFile, "err", err)
 			}
 		}()
 
 		var jwks jose.JSONWebKeySet
 		if err := json.NewDecoder(file).Decode(&jwks); err != nil {
-			return err
+			return nil, err
 		}
 
-		s.keySet = keySetJWKS{jwks}
-	} else if urlStr := s.Cfg.JWTAuthJWKSetURL; urlStr != "" {
-		urlParsed, err := url.Parse(urlStr)
+		return keySetJWKS{jwks}, nil
+	} else if jwkSetURL != "" {
+		urlParsed, err := url.Parse(jwkSetURL)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if urlParsed.Scheme != "https" {
-			return ErrJWTSetURLMustHaveHTTPSScheme
+			return nil, ErrJWTSetURLMustHaveHTTPSScheme
 		}
-		s.keySet = &keySetHTTP{
-			url:             urlStr,
+		return &keySetHTTP{
+			url:             jwkSetURL,
 			log:             s.log,
 			client:          &http.Client{},
-			cacheKey:        fmt.Sprintf("auth-jwt:jwk-%s", urlStr),
-			cacheExpiration: s.Cfg.JWTAuthCacheTTL,
+			cacheKey:        fmt.Sprintf("auth-jwt:jwk-%s", jwkSetURL),
+			cacheExpiration: cacheTTL,
 			cache:           s.RemoteCache,
-		}
+		}, nil
 	}
 
-	return nil
+	return nil, ErrKeySetIsNotConfigured
 }
 
 func (ks keySetJWKS) Key(ctx context.Context, keyID string) ([]jose.JSONWebKey, error) {
@@ -0,0 +1,159 @@
+package jwt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jmespath/go-jmespath"
+
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// orgMappingWildcard matches every token, whatever the value of its org claim.
+const orgMappingWildcard = "*"
+
+// OrgMapping maps a value of the org claim to a Grafana organization.
+type OrgMapping struct {
+	Value string
+	OrgID int64
+	// Role is optional, the role from the role claim is used when it is empty.
+	Role org.RoleType
+}
+
+// ParseOrgMapping parses org_mapping entries in the <value>:<org id>[:<role>] format.
+func ParseOrgMapping(entries []string) ([]OrgMapping, error) {
+	mappings := make([]OrgMapping, 0, len(entries))
+	for _, entry := range entries {
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid org mapping %q: expected <value>:<org id>[:<role>]", entry)
+		}
+
+		orgID, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || orgID <= 0 {
+			return nil, fmt.Errorf("invalid org mapping %q: org id must be a positive number", entry)
+		}
+
+		mapping := OrgMapping{Value: parts[0], OrgID: orgID}
+		if len(parts) == 3 {
+			mapping.Role = org.RoleType(parts[2])
+			if !mapping.Role.IsValid() {
+				return nil, fmt.Errorf("invalid org mapping %q: unknown role %q", entry, parts[2])
+			}
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings, nil
+}
+
+// ClaimMapping is how the role and org claims of a token map to Grafana org roles.
+type ClaimMapping struct {
+	RoleAttributePath string
+	OrgAttributePath  string
+	OrgMapping        []OrgMapping
+}
+
+// ClaimMappingFor returns the claim mapping of the trusted issuer named in the "iss"
+// claim. Settings the issuer does not override are taken from [auth.jwt].
+func ClaimMappingFor(cfg *setting.Cfg, claims JWTClaims) ClaimMapping {
+	m := ClaimMapping{
+		RoleAttributePath: cfg.JWTAuthRoleAttributePath,
+		OrgAttributePath:  cfg.JWTAuthOrgAttributePath,
+	}
+	// org mappings are validated when the service is initialized
+	m.OrgMapping, _ = ParseOrgMapping(cfg.JWTAuthOrgMapping)
+
+	iss, _ := claims["iss"].(string)
+	if iss == "" {
+		return m
+	}
+
+	for _, issuer := range cfg.JWTAuthIssuers {
+		if issuer.Issuer != iss {
+			continue
+		}
+		if issuer.RoleAttributePath != "" {
+			m.RoleAttributePath = issuer.RoleAttributePath
+		}
+		if issuer.OrgAttributePath != "" {
+			m.OrgAttributePath = issuer.OrgAttributePath
+		}
+		if len(issuer.OrgMapping) > 0 {
+			m.OrgMapping, _ = ParseOrgMapping(issuer.OrgMapping)
+		}
+		break
+	}
+
+	return m
+}
+
+// OrgRoles returns the org roles mapped from the org claim of the token. role is
+// the role from the role claim, used for mappings without a role, defaulting to
+// Viewer. If a token maps to the same org more than once, the highest role wins.
+// It returns nil when no org mapping is configured.
+func (m ClaimMapping) OrgRoles(claims JWTClaims, role org.RoleType) map[int64]org.RoleType {
+	if len(m.OrgMapping) == 0 {
+		return nil
+	}
+
+	if !role.IsValid() {
+		role = org.RoleViewer
+	}
+
+	values := map[string]struct{}{orgMappingWildcard: {}}
+	if m.OrgAttributePath != "" {
+		for _, v := range orgClaimValues(m.OrgAttributePath, claims) {
+			values[v] = struct{}{}
+		}
+	}
+
+	orgRoles := map[int64]org.RoleType{}
+	for _, mapping := range m.OrgMapping {
+		if _, ok := values[mapping.Value]; !ok {
+			continue
+		}
+
+		mapped := mapping.Role
+		if mapped == "" {
+			mapped = role
+		}
+		if current, ok := orgRoles[mapping.OrgID]; ok && current.Includes(mapped) {
+			continue
+		}
+		orgRoles[mapping.OrgID] = mapped
+	}
+
+	return orgRoles
+}
+
+// orgClaimValues returns the values found at the attribute path, which can point
+// to a string, a number or a list of those.
+func orgClaimValues(attributePath string, claims JWTClaims) []string {
+	val, err := jmespath.Search(attributePath, map[string]interface{}(claims))
+	if err != nil {
+		return nil
+	}
+
+	var items []interface{}
+	switch v := val.(type) {
+	case []interface{}:
+		items = v
+	default:
+		items = []interface{}{v}
+	}
+
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		switch v := item.(type) {
+		case string:
+			values = append(values, v)
+		case float64:
+			values = append(values, strconv.FormatFloat(v, 'f', -1, 64))
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,77 @@
+package jwt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestParseOrgMapping(t *testing.T) {
+	mappings, err := ParseOrgMapping([]string{"engineering:2:Editor", "*:1"})
+	require.NoError(t, err)
+	assert.Equal(t, []OrgMapping{
+		{Value: "engineering", OrgID: 2, Role: org.RoleEditor},
+		{Value: "*", OrgID: 1},
+	}, mappings)
+
+	for _, entry := range []string{"engineering", "engineering:two", "engineering:0", "engineering:2:Owner", ":2", "a:b:c:d"} {
+		_, err := ParseOrgMapping([]string{entry})
+		assert.Error(t, err, entry)
+	}
+}
+
+func TestClaimMappingFor(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.JWTAuthRoleAttributePath = "role"
+	cfg.JWTAuthOrgAttributePath = "orgs"
+	cfg.JWTAuthOrgMapping = []string{"*:1"}
+	cfg.JWTAuthIssuers = []setting.JWTIssuerSettings{
+		{Issuer: "https://portal.example.com", OrgAttributePath: "tenants", OrgMapping: []string{"acme:3:Admin"}},
+	}
+
+	m := ClaimMappingFor(cfg, JWTClaims{"iss": "https://other.example.com"})
+	assert.Equal(t, ClaimMapping{RoleAttributePath: "role", OrgAttributePath: "orgs", OrgMapping: []OrgMapping{{Value: "*", OrgID: 1}}}, m)
+
+	m = ClaimMappingFor(cfg, JWTClaims{"iss": "https://portal.example.com"})
+	assert.Equal(t, ClaimMapping{RoleAttributePath: "role", OrgAttributePath: "tenants", OrgMapping: []OrgMapping{{Value: "acme", OrgID: 3, Role: org.RoleAdmin}}}, m)
+}
+
+func TestClaimMapping_OrgRoles(t *testing.T) {
+	m := ClaimMapping{
+		OrgAttributePath: "orgs[*].name",
+		OrgMapping: []OrgMapping{
+			{Value: "engineering", OrgID: 2, Role: org.RoleEditor},
+			{Value: "ops", OrgID: 2, Role: org.RoleAdmin},
+			{Value: "support", OrgID: 3},
+			{Value: "*", OrgID: 1, Role: org.RoleViewer},
+		},
+	}
+
+	t.Run("maps org claim values and keeps the highest role", func(t *testing.T) {
+		claims := JWTClaims{"orgs": []interface{}{
+			map[string]interface{}{"name": "engineering"},
+			map[string]interface{}{"name": "ops"},
+			map[string]interface{}{"name": "support"},
+		}}
+		assert.Equal(t, map[int64]org.RoleType{1: org.RoleViewer, 2: org.RoleAdmin, 3: org.RoleEditor}, m.OrgRoles(claims, org.RoleEditor))
+	})
+
+	t.Run("uses Viewer for mappings without role when the role claim is missing", func(t *testing.T) {
+		claims := JWTClaims{"orgs": []interface{}{map[string]interface{}{"name": "support"}}}
+		assert.Equal(t, map[int64]org.RoleType{1: org.RoleViewer, 3: org.RoleViewer}, m.OrgRoles(claims, ""))
+	})
+
+	t.Run("matches numeric and single values", func(t *testing.T) {
+		m := ClaimMapping{OrgAttributePath: "tenant", OrgMapping: []OrgMapping{{Value: "42", OrgID: 4, Role: org.RoleEditor}}}
+		assert.Equal(t, map[int64]org.RoleType{4: org.RoleEditor}, m.OrgRoles(JWTClaims{"tenant": float64(42)}, ""))
+		assert.Empty(t, m.OrgRoles(JWTClaims{"tenant": "43"}, ""))
+	})
+
+	t.Run("returns nil without org mapping", func(t *testing.T) {
+		assert.Nil(t, ClaimMapping{OrgAttributePath: "orgs"}.OrgRoles(JWTClaims{"orgs": "engineering"}, org.RoleAdmin))
+	})
+}
@@ -9,8 +9,8 @@ import (
 	"github.com/go-jose/go-jose/v3/jwt"
 )
 
-func (s *AuthService) initClaimExpectations() error {
-	if err := json.Unmarshal([]byte(s.Cfg.JWTAuthExpectClaims), &s.expect); err != nil {
+func (s *verifier) initClaimExpectations(expectClaims string) error {
+	if err := json.Unmarshal([]byte(expectClaims), &s.expect); err != nil {
 		return err
 	}
 
@@ -52,7 +52,7 @@ func (s *AuthService) initClaimExpectations() error {
 	return nil
 }
 
-func (s *AuthService) validateClaims(claims JWTClaims) error {
+func (s *verifier) validateClaims(claims JWTClaims) error {
 	var registeredClaims jwt.Claims
 	for key, value := range claims {
 		switch key {
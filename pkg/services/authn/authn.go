@@ -31,6 +31,7 @@ const (
 	ClientForm      = "auth.client.form"
 	ClientProxy     = "auth.client.proxy"
 	ClientSAML      = "auth.client.saml"
+	ClientTwoFactor = "auth.client.two-factor"
 )
 
 const (
@@ -225,6 +226,12 @@ type Identity struct {
 	ClientParams ClientParams
 	// Permissions is the list of permissions the entity has.
 	Permissions map[int64]map[string][]string
+	// TokenPermissions restricts the permissions of a service account authenticated
+	// with a scoped token, grouped by action. Nil when the token is not restricted.
+	TokenPermissions map[string][]string
+	// TokenOrgRole is the role of a service account authenticated with a scoped token,
+	// which the permissions restricted by the token are computed from.
+	TokenOrgRole org.RoleType
 }
 
 // Role returns the role of the identity in the active organization.
@@ -278,6 +285,8 @@ func (i *Identity) SignedInUser() *user.SignedInUser {
 		LastSeenAt:         i.LastSeenAt,
 		Teams:              i.Teams,
 		Permissions:        i.Permissions,
+		TokenPermissions:   i.TokenPermissions,
+		TokenOrgRole:       i.TokenOrgRole,
 	}
 
 	namespace, id := i.NamespacedID()
@@ -311,23 +320,25 @@ func (i *Identity) ExternalUserInfo() login.ExternalUserInfo {
 // IdentityFromSignedInUser creates an identity from a SignedInUser.
 func IdentityFromSignedInUser(id string, usr *user.SignedInUser, params ClientParams) *Identity {
 	return &Identity{
-		ID:             id,
-		OrgID:          usr.OrgID,
-		OrgName:        usr.OrgName,
-		OrgRoles:       map[int64]org.RoleType{usr.OrgID: usr.OrgRole},
-		Login:          usr.Login,
-		Name:           usr.Name,
-		Email:          usr.Email,
-		OrgCount:       usr.OrgCount,
-		IsGrafanaAdmin: &usr.IsGrafanaAdmin,
-		IsDisabled:     usr.IsDisabled,
-		HelpFlags1:     usr.HelpFlags1,
-		LastSeenAt:     usr.LastSeenAt,
-		Teams:          usr.Teams,
-		ClientParams:   params,
-		Permissions:    usr.Permissions,
-		AuthModule:     usr.ExternalAuthModule,
-		AuthID:         usr.ExternalAuthID,
+		ID:               id,
+		OrgID:            usr.OrgID,
+		OrgName:          usr.OrgName,
+		OrgRoles:         map[int64]org.RoleType{usr.OrgID: usr.OrgRole},
+		Login:            usr.Login,
+		Name:             usr.Name,
+		Email:            usr.Email,
+		OrgCount:         usr.OrgCount,
+		IsGrafanaAdmin:   &usr.IsGrafanaAdmin,
+		IsDisabled:       usr.IsDisabled,
+		HelpFlags1:       usr.HelpFlags1,
+		LastSeenAt:       usr.LastSeenAt,
+		Teams:            usr.Teams,
+		ClientParams:     params,
+		Permissions:      usr.Permissions,
+		TokenPermissions: usr.TokenPermissions,
+		TokenOrgRole:     usr.TokenOrgRole,
+		AuthModule:       usr.ExternalAuthModule,
+		AuthID:           usr.ExternalAuthID,
 	}
 }
 
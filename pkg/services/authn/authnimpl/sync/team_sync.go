@@ -0,0 +1,38 @@
+package sync
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+)
+
+func ProvideTeamSync(teamSyncService teamsync.Service) *TeamSync {
+	return &TeamSync{teamSyncService, log.New("team.sync")}
+}
+
+type TeamSync struct {
+	teamSyncService teamsync.Service
+	log             log.Logger
+}
+
+// SyncTeamsHook adds the identity to the teams mapped to its external groups and removes it from the ones it left
+func (s *TeamSync) SyncTeamsHook(ctx context.Context, id *authn.Identity, _ *authn.Request) error {
+	if !id.ClientParams.SyncTeams {
+		return nil
+	}
+
+	namespace, userID := id.NamespacedID()
+	if namespace != authn.NamespaceUser || userID <= 0 {
+		s.log.FromContext(ctx).Warn("Failed to sync teams, invalid namespace for identity", "id", id.ID, "namespace", namespace)
+		return nil
+	}
+
+	s.log.FromContext(ctx).Debug("Syncing teams", "id", id.ID, "groups", id.Groups)
+	if err := s.teamSyncService.SyncUserTeams(ctx, userID, id.Groups); err != nil {
+		s.log.FromContext(ctx).Error("Failed to sync teams", "id", id.ID, "error", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/teamsync/teamsynctest"
+)
+
+func TestTeamSync_SyncTeamsHook(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity *authn.Identity
+		expected map[int64][]string
+	}{
+		{
+			name: "should sync the groups of users",
+			identity: &authn.Identity{
+				ID:           authn.NamespacedID(authn.NamespaceUser, 2),
+				Groups:       []string{"devs", "ops"},
+				ClientParams: authn.ClientParams{SyncTeams: true},
+			},
+			expected: map[int64][]string{2: {"devs", "ops"}},
+		},
+		{
+			name: "should not sync when the client does not sync teams",
+			identity: &authn.Identity{
+				ID:     authn.NamespacedID(authn.NamespaceUser, 2),
+				Groups: []string{"devs"},
+			},
+			expected: map[int64][]string{},
+		},
+		{
+			name: "should not sync service accounts",
+			identity: &authn.Identity{
+				ID:           authn.NamespacedID(authn.NamespaceServiceAccount, 3),
+				Groups:       []string{"devs"},
+				ClientParams: authn.ClientParams{SyncTeams: true},
+			},
+			expected: map[int64][]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := teamsynctest.NewFakeService()
+			s := ProvideTeamSync(fake)
+			require.NoError(t, s.SyncTeamsHook(context.Background(), tt.identity, nil))
+			assert.Equal(t, tt.expected, fake.SyncedGroups)
+		})
+	}
+}
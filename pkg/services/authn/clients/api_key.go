@@ -3,6 +3,7 @@ package clients
 import (
 	"context"
 	"errors"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,31 +14,38 @@ import (
 	"github.com/grafana/grafana/pkg/services/authn"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 var (
-	errAPIKeyInvalid = errutil.NewBase(errutil.StatusUnauthorized, "api-key.invalid", errutil.WithPublicMessage("Invalid API key"))
-	errAPIKeyExpired = errutil.NewBase(errutil.StatusUnauthorized, "api-key.expired", errutil.WithPublicMessage("Expired API key"))
-	errAPIKeyRevoked = errutil.NewBase(errutil.StatusUnauthorized, "api-key.revoked", errutil.WithPublicMessage("Revoked API key"))
+	errAPIKeyInvalid  = errutil.NewBase(errutil.StatusUnauthorized, "api-key.invalid", errutil.WithPublicMessage("Invalid API key"))
+	errAPIKeyExpired  = errutil.NewBase(errutil.StatusUnauthorized, "api-key.expired", errutil.WithPublicMessage("Expired API key"))
+	errAPIKeyRevoked  = errutil.NewBase(errutil.StatusUnauthorized, "api-key.revoked", errutil.WithPublicMessage("Revoked API key"))
+	errAPIKeyIPDenied = errutil.NewBase(errutil.StatusForbidden, "api-key.ip-denied", errutil.WithPublicMessage("API key can not be used from this IP address"))
 )
 
+// metaKeyAPIKeyID is the request metadata used to record the usage of the key once the request is authenticated
+const metaKeyAPIKeyID = "apiKeyID"
+
 var _ authn.HookClient = new(APIKey)
 var _ authn.ContextAwareClient = new(APIKey)
 
-func ProvideAPIKey(apiKeyService apikey.Service, userService user.Service) *APIKey {
+func ProvideAPIKey(cfg *setting.Cfg, apiKeyService apikey.Service, userService user.Service) *APIKey {
 	return &APIKey{
-		log:           log.New(authn.ClientAPIKey),
-		userService:   userService,
-		apiKeyService: apiKeyService,
+		log:            log.New(authn.ClientAPIKey),
+		userService:    userService,
+		apiKeyService:  apiKeyService,
+		trustedProxies: apikey.AllowedIPs(cfg.SATokenTrustedProxies),
 	}
 }
 
 type APIKey struct {
-	log           log.Logger
-	userService   user.Service
-	apiKeyService apikey.Service
+	log            log.Logger
+	userService    user.Service
+	apiKeyService  apikey.Service
+	trustedProxies apikey.AllowedIPs
 }
 
 func (s *APIKey) Name() string {
@@ -61,6 +69,15 @@ func (s *APIKey) Authenticate(ctx context.Context, r *authn.Request) (*authn.Ide
 		return nil, errAPIKeyRevoked.Errorf("Api key is revoked")
 	}
 
+	if len(apiKey.AllowedIPs) > 0 {
+		ip := apikey.ClientIP(r.HTTPRequest, s.trustedProxies)
+		if !apiKey.AllowedIPs.Contains(ip) {
+			return nil, errAPIKeyIPDenied.Errorf("API key %d can not be used from %s", apiKey.ID, ip)
+		}
+	}
+
+	r.SetMeta(metaKeyAPIKeyID, strconv.FormatInt(apiKey.ID, 10))
+
 	// if the api key don't belong to a service account construct the identity and return it
 	if apiKey.ServiceAccountId == nil || *apiKey.ServiceAccountId < 1 {
 		return &authn.Identity{
@@ -80,6 +97,8 @@ func (s *APIKey) Authenticate(ctx context.Context, r *authn.Request) (*authn.Ide
 		return nil, err
 	}
 
+	// scoped tokens only get the part of the service account permissions they are restricted to
+	usr.RestrictToToken(apiKey.Permissions.ScopesByAction())
 	return authn.IdentityFromSignedInUser(authn.NamespacedID(authn.NamespaceServiceAccount, usr.UserID), usr, authn.ClientParams{SyncPermissions: true}), nil
 }
 
@@ -145,21 +164,26 @@ func (s *APIKey) Priority() uint {
 }
 
 func (s *APIKey) Hook(ctx context.Context, identity *authn.Identity, r *authn.Request) error {
-	namespace, id := identity.NamespacedID()
-	if namespace != authn.NamespaceAPIKey {
+	apiKeyID, err := strconv.ParseInt(r.GetMeta(metaKeyAPIKeyID), 10, 64)
+	if err != nil {
 		return nil
 	}
 
-	go func(apikeyID int64) {
+	var lastUsedIP string
+	if ip := apikey.ClientIP(r.HTTPRequest, s.trustedProxies); ip != nil {
+		lastUsedIP = ip.String()
+	}
+
+	go func(apikeyID int64, ip string) {
 		defer func() {
 			if err := recover(); err != nil {
 				s.log.Error("panic during user last seen sync", "err", err)
 			}
 		}()
-		if err := s.apiKeyService.UpdateAPIKeyLastUsedDate(context.Background(), apikeyID); err != nil {
+		if err := s.apiKeyService.UpdateAPIKeyLastUsedDate(context.Background(), apikeyID, ip); err != nil {
 			s.log.Warn("failed to update last use date for api key", "id", apikeyID)
 		}
-	}(id)
+	}(apiKeyID, lastUsedIP)
 
 	return nil
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 var (
@@ -90,6 +91,68 @@ func TestAPIKey_Authenticate(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "should restrict the permissions and the role of a scoped service account token",
+			req: &authn.Request{HTTPRequest: &http.Request{
+				RemoteAddr: "10.1.2.3:52144",
+				Header: map[string][]string{
+					"Authorization": {"Bearer " + secret},
+				},
+			}},
+			expectedKey: &apikey.APIKey{
+				ID:               1,
+				OrgID:            1,
+				Key:              hash,
+				ServiceAccountId: intPtr(1),
+				Permissions:      apikey.Permissions{{Action: "dashboards:read", Scope: "folders:uid:ops"}},
+				AllowedIPs:       apikey.AllowedIPs{"10.0.0.0/8"},
+			},
+			expectedUser: &user.SignedInUser{
+				UserID:           1,
+				OrgID:            1,
+				IsServiceAccount: true,
+				OrgRole:          org.RoleAdmin,
+			},
+			expectedIdentity: &authn.Identity{
+				ID:               "service-account:1",
+				OrgID:            1,
+				OrgRoles:         map[int64]org.RoleType{1: org.RoleViewer},
+				IsGrafanaAdmin:   boolPtr(false),
+				TokenPermissions: map[string][]string{"dashboards:read": {"folders:uid:ops"}},
+				TokenOrgRole:     org.RoleAdmin,
+				ClientParams: authn.ClientParams{
+					SyncPermissions: true,
+				},
+			},
+		},
+		{
+			desc: "should fail for api key used from an address that is not allowed",
+			req: &authn.Request{HTTPRequest: &http.Request{
+				RemoteAddr: "192.168.1.10:52144",
+				Header:     map[string][]string{"Authorization": {"Bearer " + secret}},
+			}},
+			expectedKey: &apikey.APIKey{
+				Key:        hash,
+				AllowedIPs: apikey.AllowedIPs{"10.0.0.0/8"},
+			},
+			expectedErr: errAPIKeyIPDenied,
+		},
+		{
+			desc: "should fail for api key with an allowed address in a forwarded header from an untrusted client",
+			req: &authn.Request{HTTPRequest: &http.Request{
+				RemoteAddr: "192.168.1.10:52144",
+				Header: map[string][]string{
+					"Authorization":   {"Bearer " + secret},
+					"X-Forwarded-For": {"10.1.2.3"},
+					"X-Real-Ip":       {"10.1.2.3"},
+				},
+			}},
+			expectedKey: &apikey.APIKey{
+				Key:        hash,
+				AllowedIPs: apikey.AllowedIPs{"10.0.0.0/8"},
+			},
+			expectedErr: errAPIKeyIPDenied,
+		},
 		{
 			desc: "should fail for expired api key",
 			req:  &authn.Request{HTTPRequest: &http.Request{Header: map[string][]string{"Authorization": {"Bearer " + secret}}}},
@@ -112,7 +175,7 @@ func TestAPIKey_Authenticate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
-			c := ProvideAPIKey(&apikeytest.Service{
+			c := ProvideAPIKey(setting.NewCfg(), &apikeytest.Service{
 				ExpectedAPIKey: tt.expectedKey,
 			}, &usertest.FakeUserService{
 				ExpectedSignedInUser: tt.expectedUser,
@@ -181,12 +244,38 @@ func TestAPIKey_Test(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
-			c := ProvideAPIKey(&apikeytest.Service{}, usertest.NewUserServiceFake())
+			c := ProvideAPIKey(setting.NewCfg(), &apikeytest.Service{}, usertest.NewUserServiceFake())
 			assert.Equal(t, tt.expected, c.Test(context.Background(), tt.req))
 		})
 	}
 }
 
+func TestAPIKey_AuthenticateBehindTrustedProxy(t *testing.T) {
+	secret, hash := genApiKey(false)
+	cfg := setting.NewCfg()
+	cfg.SATokenTrustedProxies = []string{"172.16.0.0/12"}
+	c := ProvideAPIKey(cfg, &apikeytest.Service{
+		ExpectedAPIKey: &apikey.APIKey{ID: 1, OrgID: 1, Key: hash, Role: org.RoleViewer, AllowedIPs: apikey.AllowedIPs{"10.0.0.0/8"}},
+	}, usertest.NewUserServiceFake())
+
+	newRequest := func(forwardedFor string) *authn.Request {
+		return &authn.Request{HTTPRequest: &http.Request{
+			RemoteAddr: "172.16.0.2:52144",
+			Header: map[string][]string{
+				"Authorization":   {"Bearer " + secret},
+				"X-Forwarded-For": {forwardedFor},
+			},
+		}}
+	}
+
+	_, err := c.Authenticate(context.Background(), newRequest("10.1.2.3, 172.16.0.3"))
+	assert.NoError(t, err)
+
+	// only the address added by the trusted proxy is used, the client can prepend any address
+	_, err = c.Authenticate(context.Background(), newRequest("10.1.2.3, 192.168.1.10"))
+	assert.ErrorIs(t, err, errAPIKeyIPDenied)
+}
+
 func intPtr(n int64) *int64 {
 	return &n
 }
@@ -4,21 +4,24 @@ import (
 	"context"
 
 	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/twofactor"
 	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 var (
 	errDecodingBasicAuthHeader = errutil.NewBase(errutil.StatusBadRequest, "basic-auth.invalid-header", errutil.WithPublicMessage("Invalid Basic Auth Header"))
+	errBasicAuthTwoFactor      = errutil.NewBase(errutil.StatusUnauthorized, "basic-auth.two-factor", errutil.WithPublicMessage("Basic auth is not available for users with two-factor authentication, use a service account token instead"))
 )
 
 var _ authn.ContextAwareClient = new(Basic)
 
-func ProvideBasic(client authn.PasswordClient) *Basic {
-	return &Basic{client}
+func ProvideBasic(client authn.PasswordClient, twoFactor twofactor.Service) *Basic {
+	return &Basic{client, twoFactor}
 }
 
 type Basic struct {
-	client authn.PasswordClient
+	client    authn.PasswordClient
+	twoFactor twofactor.Service
 }
 
 func (c *Basic) String() string {
@@ -35,7 +38,20 @@ func (c *Basic) Authenticate(ctx context.Context, r *authn.Request) (*authn.Iden
 		return nil, errDecodingBasicAuthHeader.Errorf("failed to decode basic auth header")
 	}
 
-	return c.client.AuthenticatePassword(ctx, r, username, password)
+	identity, err := c.client.AuthenticatePassword(ctx, r, username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	// basic auth has no way to ask for a second factor
+	enabled, err := usesTwoFactor(ctx, c.twoFactor, r, identity)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, errBasicAuthTwoFactor.Errorf("user %s uses two-factor authentication", identity.ID)
+	}
+	return identity, nil
 }
 
 func (c *Basic) Test(ctx context.Context, r *authn.Request) bool {
@@ -9,6 +9,8 @@ import (
 
 	"github.com/grafana/grafana/pkg/services/authn"
 	"github.com/grafana/grafana/pkg/services/authn/authntest"
+	"github.com/grafana/grafana/pkg/services/twofactor"
+	"github.com/grafana/grafana/pkg/services/twofactor/twofactortest"
 )
 
 func TestBasic_Authenticate(t *testing.T) {
@@ -16,6 +18,7 @@ func TestBasic_Authenticate(t *testing.T) {
 		desc             string
 		req              *authn.Request
 		client           authn.PasswordClient
+		twoFactor        *twofactortest.FakeService
 		expectedErr      error
 		expectedIdentity *authn.Identity
 	}
@@ -32,11 +35,29 @@ func TestBasic_Authenticate(t *testing.T) {
 			req:         &authn.Request{HTTPRequest: &http.Request{Header: map[string][]string{authorizationHeaderName: {}}}},
 			expectedErr: errDecodingBasicAuthHeader,
 		},
+		{
+			desc:        "should fail when user managed by grafana uses two-factor authentication",
+			req:         grafanaRequest(&http.Request{Header: map[string][]string{authorizationHeaderName: {encodeBasicAuth("user", "password")}}}),
+			client:      authntest.FakePasswordClient{ExpectedIdentity: &authn.Identity{ID: "user:1"}},
+			twoFactor:   &twofactortest.FakeService{ExpectedStatus: &twofactor.Status{Enabled: true}},
+			expectedErr: errBasicAuthTwoFactor,
+		},
+		{
+			desc:             "should success when user authenticated by another system uses two-factor authentication",
+			req:              &authn.Request{HTTPRequest: &http.Request{Header: map[string][]string{authorizationHeaderName: {encodeBasicAuth("user", "password")}}}},
+			client:           authntest.FakePasswordClient{ExpectedIdentity: &authn.Identity{ID: "user:1"}},
+			twoFactor:        &twofactortest.FakeService{ExpectedStatus: &twofactor.Status{Enabled: true}},
+			expectedIdentity: &authn.Identity{ID: "user:1"},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
-			c := ProvideBasic(tt.client)
+			twoFactor := tt.twoFactor
+			if twoFactor == nil {
+				twoFactor = &twofactortest.FakeService{}
+			}
+			c := ProvideBasic(tt.client, twoFactor)
 
 			identity, err := c.Authenticate(context.Background(), tt.req)
 			if tt.expectedErr != nil {
@@ -89,7 +110,7 @@ func TestBasic_Test(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
-			c := ProvideBasic(authntest.FakePasswordClient{})
+			c := ProvideBasic(authntest.FakePasswordClient{}, &twofactortest.FakeService{})
 			assert.Equal(t, tt.expected, c.Test(context.Background(), tt.req))
 		})
 	}
@@ -11,12 +11,16 @@ import (
 
 	"github.com/grafana/grafana/pkg/services/authn"
 	"github.com/grafana/grafana/pkg/services/authn/authntest"
+	"github.com/grafana/grafana/pkg/services/twofactor"
+	"github.com/grafana/grafana/pkg/services/twofactor/twofactortest"
 )
 
 func TestForm_Authenticate(t *testing.T) {
 	type testCase struct {
 		desc        string
 		req         *authn.Request
+		client      authn.PasswordClient
+		twoFactor   *twofactortest.FakeService
 		expectedErr error
 	}
 
@@ -36,11 +40,39 @@ func TestForm_Authenticate(t *testing.T) {
 			}},
 			expectedErr: errBadForm,
 		},
+		{
+			desc: "should return challenge when user managed by grafana uses two-factor authentication",
+			req: grafanaRequest(&http.Request{
+				Header: map[string][]string{"Content-Type": {"application/json"}},
+				Body:   io.NopCloser(strings.NewReader(`{"user": "test", "password": "test"}`)),
+			}),
+			client:      authntest.FakePasswordClient{ExpectedIdentity: &authn.Identity{ID: "user:1"}},
+			twoFactor:   &twofactortest.FakeService{ExpectedChallenge: &twofactor.LoginChallenge{Token: "token"}},
+			expectedErr: twofactor.ErrCodeRequired,
+		},
+		{
+			desc: "should return enrollment challenge when user managed by grafana is required to use two-factor authentication",
+			req: grafanaRequest(&http.Request{
+				Header: map[string][]string{"Content-Type": {"application/json"}},
+				Body:   io.NopCloser(strings.NewReader(`{"user": "test", "password": "test"}`)),
+			}),
+			client:      authntest.FakePasswordClient{ExpectedIdentity: &authn.Identity{ID: "user:1"}},
+			twoFactor:   &twofactortest.FakeService{ExpectedChallenge: &twofactor.LoginChallenge{Token: "token", Enroll: true}},
+			expectedErr: twofactor.ErrEnrollmentRequired,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
-			c := ProvideForm(&authntest.FakePasswordClient{})
+			client := tt.client
+			if client == nil {
+				client = &authntest.FakePasswordClient{}
+			}
+			twoFactor := tt.twoFactor
+			if twoFactor == nil {
+				twoFactor = &twofactortest.FakeService{}
+			}
+			c := ProvideForm(client, twoFactor)
 			_, err := c.Authenticate(context.Background(), tt.req)
 			assert.ErrorIs(t, err, tt.expectedErr)
 		})
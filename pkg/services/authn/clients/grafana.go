@@ -103,6 +103,14 @@ func (c *Grafana) AuthenticatePassword(ctx context.Context, r *authn.Request, us
 		return nil, errInvalidPassword.Errorf("invalid password")
 	}
 
+	expired, err := c.userService.IsPasswordExpired(ctx, usr)
+	if err != nil {
+		return nil, err
+	}
+	if expired {
+		return nil, user.ErrPasswordExpired.Errorf("password of user %d has expired", usr.ID)
+	}
+
 	signedInUser, err := c.userService.GetSignedInUserWithCacheCtx(ctx, &user.GetSignedInUserQuery{OrgID: r.OrgID, UserID: usr.ID})
 	if err != nil {
 		return nil, err
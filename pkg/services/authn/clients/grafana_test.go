@@ -130,6 +130,7 @@ func TestGrafana_AuthenticatePassword(t *testing.T) {
 		username             string
 		password             string
 		findUser             bool
+		passwordExpired      bool
 		expectedErr          error
 		expectedIdentity     *authn.Identity
 		expectedSignedInUser *user.SignedInUser
@@ -162,14 +163,23 @@ func TestGrafana_AuthenticatePassword(t *testing.T) {
 			password:    "password",
 			expectedErr: errIdentityNotFound,
 		},
+		{
+			desc:            "should fail if password has expired",
+			username:        "user",
+			password:        "password",
+			findUser:        true,
+			passwordExpired: true,
+			expectedErr:     user.ErrPasswordExpired,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
 			hashed, _ := util.EncodePassword("password", "salt")
 			userService := &usertest.FakeUserService{
-				ExpectedSignedInUser: tt.expectedSignedInUser,
-				ExpectedUser:         &user.User{Password: hashed, Salt: "salt"},
+				ExpectedSignedInUser:    tt.expectedSignedInUser,
+				ExpectedUser:            &user.User{Password: hashed, Salt: "salt"},
+				ExpectedPasswordExpired: tt.passwordExpired,
 			}
 
 			if !tt.findUser {
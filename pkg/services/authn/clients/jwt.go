@@ -90,12 +90,16 @@ func (s *JWT) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identi
 		id.Name = name
 	}
 
+	mapping := authJWT.ClaimMappingFor(s.cfg, claims)
+
+	var role org.RoleType
 	orgRoles, isGrafanaAdmin, err := getRoles(s.cfg, func() (org.RoleType, *bool, error) {
 		if s.cfg.JWTAuthSkipOrgRoleSync {
 			return "", nil, nil
 		}
 
-		role, grafanaAdmin := s.extractRoleAndAdmin(claims)
+		var grafanaAdmin bool
+		role, grafanaAdmin = extractRoleAndAdmin(mapping.RoleAttributePath, claims)
 		if s.cfg.JWTAuthRoleAttributeStrict && !role.IsValid() {
 			return "", nil, errJWTInvalidRole.Errorf("invalid role claim in JWT: %s", role)
 		}
@@ -111,6 +115,12 @@ func (s *JWT) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identi
 		return nil, err
 	}
 
+	if !s.cfg.JWTAuthSkipOrgRoleSync {
+		if mapped := mapping.OrgRoles(claims, role); len(mapped) > 0 {
+			orgRoles = mapped
+		}
+	}
+
 	id.OrgRoles = orgRoles
 	id.IsGrafanaAdmin = isGrafanaAdmin
 
@@ -170,12 +180,12 @@ func (s *JWT) Priority() uint {
 
 const roleGrafanaAdmin = "GrafanaAdmin"
 
-func (s *JWT) extractRoleAndAdmin(claims map[string]interface{}) (org.RoleType, bool) {
-	if s.cfg.JWTAuthRoleAttributePath == "" {
+func extractRoleAndAdmin(roleAttributePath string, claims map[string]interface{}) (org.RoleType, bool) {
+	if roleAttributePath == "" {
 		return "", false
 	}
 
-	role, err := searchClaimsForStringAttr(s.cfg.JWTAuthRoleAttributePath, claims)
+	role, err := searchClaimsForStringAttr(roleAttributePath, claims)
 	if err != nil || role == "" {
 		return "", false
 	}
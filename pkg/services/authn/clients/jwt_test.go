@@ -88,6 +88,42 @@ func TestAuthenticateJWT(t *testing.T) {
 	assert.EqualValues(t, wantID, id, fmt.Sprintf("%+v", id))
 }
 
+func TestAuthenticateJWTOrgMapping(t *testing.T) {
+	jwtService := &jwt.FakeJWTService{
+		VerifyProvider: func(context.Context, string) (jwt.JWTClaims, error) {
+			return jwt.JWTClaims{
+				"sub":     "1234567890",
+				"iss":     "https://portal.example.com",
+				"email":   "eai.doe@cor.po",
+				"role":    "Editor",
+				"tenants": []interface{}{"acme", "initech"},
+			}, nil
+		},
+	}
+
+	cfg := &setting.Cfg{
+		JWTAuthEnabled:           true,
+		JWTAuthHeaderName:        "X-Forwarded-User",
+		JWTAuthEmailClaim:        "email",
+		JWTAuthRoleAttributePath: "roles",
+		JWTAuthOrgAttributePath:  "orgs",
+		JWTAuthOrgMapping:        []string{"*:1:Viewer"},
+		JWTAuthIssuers: []setting.JWTIssuerSettings{{
+			Issuer:            "https://portal.example.com",
+			RoleAttributePath: "role",
+			OrgAttributePath:  "tenants",
+			OrgMapping:        []string{"acme:2", "initech:3:Admin", "globex:4:Admin"},
+		}},
+	}
+
+	id, err := ProvideJWT(jwtService, cfg).Authenticate(context.Background(), &authn.Request{
+		OrgID:       1,
+		HTTPRequest: &http.Request{Header: map[string][]string{"X-Forwarded-User": {"sample-token"}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]roletype.RoleType{2: roletype.RoleEditor, 3: roletype.RoleAdmin}, id.OrgRoles)
+}
+
 func TestJWTClaimConfig(t *testing.T) {
 	jwtService := &jwt.FakeJWTService{
 		VerifyProvider: func(context.Context, string) (jwt.JWTClaims, error) {
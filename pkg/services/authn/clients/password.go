@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/authn"
 	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util/errutil"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -57,9 +58,18 @@ func (c *Password) AuthenticatePassword(ctx context.Context, r *authn.Request, u
 			continue
 		}
 
+		// only consecutive failures count towards the account lockout
+		if err := c.loginAttempts.Reset(ctx, username); err != nil {
+			c.log.FromContext(ctx).Error("Failed to reset login attempts", "error", err)
+		}
+
 		return identity, nil
 	}
 
+	if errors.Is(clientErrs, user.ErrPasswordExpired) {
+		return nil, user.ErrPasswordExpired.Errorf("failed to authenticate identity: %w", clientErrs)
+	}
+
 	if errors.Is(clientErrs, errInvalidPassword) {
 		_ = c.loginAttempts.Add(ctx, username, web.RemoteAddr(r.HTTPRequest))
 	}
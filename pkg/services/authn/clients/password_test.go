@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/authn"
 	"github.com/grafana/grafana/pkg/services/authn/authntest"
 	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
+	"github.com/grafana/grafana/pkg/services/user"
 )
 
 func TestPassword_AuthenticatePassword(t *testing.T) {
@@ -63,6 +64,14 @@ func TestPassword_AuthenticatePassword(t *testing.T) {
 			clients:     []authn.PasswordClient{authntest.FakePasswordClient{ExpectedErr: errIdentityNotFound}, authntest.FakePasswordClient{ExpectedErr: errIdentityNotFound}},
 			expectedErr: errPasswordAuthFailed,
 		},
+		{
+			desc:        "should fail with expired password error when the password has expired",
+			username:    "test",
+			password:    "test",
+			req:         &authn.Request{},
+			clients:     []authn.PasswordClient{authntest.FakePasswordClient{ExpectedErr: user.ErrPasswordExpired.Errorf("expired")}, authntest.FakePasswordClient{ExpectedErr: errIdentityNotFound}},
+			expectedErr: user.ErrPasswordExpired,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,127 @@
+package clients
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/infra/network"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/twofactor"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+var (
+	errBadTwoFactorForm = errutil.NewBase(errutil.StatusBadRequest, "two-factor-auth.invalid", errutil.WithPublicMessage("bad two-factor authentication data"))
+)
+
+var _ authn.Client = new(TwoFactor)
+
+func ProvideTwoFactor(twoFactor twofactor.Service, userService user.Service) *TwoFactor {
+	return &TwoFactor{twoFactor, userService}
+}
+
+// TwoFactor completes password logins that were challenged for a two-factor authentication code
+type TwoFactor struct {
+	twoFactor   twofactor.Service
+	userService user.Service
+}
+
+type twoFactorForm struct {
+	Code string `json:"code" binding:"Required"`
+}
+
+func (c *TwoFactor) Name() string {
+	return authn.ClientTwoFactor
+}
+
+func (c *TwoFactor) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identity, error) {
+	form := twoFactorForm{}
+	if err := web.Bind(r.HTTPRequest, &form); err != nil {
+		return nil, errBadTwoFactorForm.Errorf("failed to parse request: %w", err)
+	}
+
+	var token string
+	if cookie, err := r.HTTPRequest.Cookie(twofactor.ChallengeCookieName); err == nil {
+		token = cookie.Value
+	}
+
+	var ipAddress string
+	if ip, err := network.GetIPFromAddress(web.RemoteAddr(r.HTTPRequest)); err == nil {
+		ipAddress = ip.String()
+	}
+
+	userID, err := c.twoFactor.CompleteLogin(ctx, token, form.Code, ipAddress)
+	if err != nil {
+		if errors.Is(err, twofactor.ErrChallengeNotFound) && r.Resp != nil {
+			twofactor.DeleteChallengeCookie(r.Resp)
+		}
+		return nil, err
+	}
+	if r.Resp != nil {
+		twofactor.DeleteChallengeCookie(r.Resp)
+	}
+
+	r.SetMeta(authn.MetaKeyAuthModule, "grafana")
+
+	signedInUser, err := c.userService.GetSignedInUserWithCacheCtx(ctx, &user.GetSignedInUserQuery{OrgID: r.OrgID, UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	return authn.IdentityFromSignedInUser(authn.NamespacedID(authn.NamespaceUser, signedInUser.UserID), signedInUser, authn.ClientParams{SyncPermissions: true}), nil
+}
+
+// challengeTwoFactor returns an error carrying a login challenge when a user that authenticated
+// with a password managed by Grafana needs a second factor
+func challengeTwoFactor(ctx context.Context, twoFactor twofactor.Service, r *authn.Request, identity *authn.Identity) error {
+	userID, ok := grafanaUserID(r, identity)
+	if !ok {
+		return nil
+	}
+
+	challenge, err := twoFactor.StartLogin(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if challenge == nil {
+		return nil
+	}
+
+	if r.Resp != nil {
+		twofactor.WriteChallengeCookie(r.Resp, challenge.Token)
+	}
+	if challenge.Enroll {
+		return twofactor.ErrEnrollmentRequired.Errorf("user %d must enroll in two-factor authentication", userID)
+	}
+	return twofactor.ErrCodeRequired.Errorf("user %d must enter a two-factor authentication code", userID)
+}
+
+// usesTwoFactor returns true when a user that authenticated with a password managed by Grafana
+// uses, or is required to use, a second factor
+func usesTwoFactor(ctx context.Context, twoFactor twofactor.Service, r *authn.Request, identity *authn.Identity) (bool, error) {
+	userID, ok := grafanaUserID(r, identity)
+	if !ok {
+		return false, nil
+	}
+
+	status, err := twoFactor.GetStatus(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return status.Enabled || status.Required, nil
+}
+
+func grafanaUserID(r *authn.Request, identity *authn.Identity) (int64, bool) {
+	// users authenticated by an external system, such as LDAP, rely on that system for additional factors
+	if r.GetMeta(authn.MetaKeyAuthModule) != "grafana" {
+		return 0, false
+	}
+
+	namespace, id := identity.NamespacedID()
+	if namespace != authn.NamespaceUser || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
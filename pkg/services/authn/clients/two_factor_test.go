@@ -0,0 +1,83 @@
+package clients
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/twofactor"
+	"github.com/grafana/grafana/pkg/services/twofactor/twofactortest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestTwoFactor_Authenticate(t *testing.T) {
+	type testCase struct {
+		desc             string
+		body             string
+		twoFactor        *twofactortest.FakeService
+		expectedErr      error
+		expectedIdentity bool
+	}
+
+	tests := []testCase{
+		{
+			desc:             "should success when the code is valid",
+			body:             `{"code": "123456"}`,
+			twoFactor:        &twofactortest.FakeService{ExpectedUserID: 1},
+			expectedIdentity: true,
+		},
+		{
+			desc:        "should return error for bad request",
+			body:        `{}`,
+			twoFactor:   &twofactortest.FakeService{ExpectedUserID: 1},
+			expectedErr: errBadTwoFactorForm,
+		},
+		{
+			desc:        "should return error when the code is invalid",
+			body:        `{"code": "123456"}`,
+			twoFactor:   &twofactortest.FakeService{ExpectedErr: twofactor.ErrInvalidCode.Errorf("invalid")},
+			expectedErr: twofactor.ErrInvalidCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			req := &http.Request{
+				Header: map[string][]string{"Content-Type": {"application/json"}},
+				Body:   io.NopCloser(strings.NewReader(tt.body)),
+			}
+			req.AddCookie(&http.Cookie{Name: twofactor.ChallengeCookieName, Value: "token"})
+
+			c := ProvideTwoFactor(tt.twoFactor, &usertest.FakeUserService{
+				ExpectedSignedInUser: &user.SignedInUser{UserID: 1, OrgID: 1},
+			})
+			identity, err := c.Authenticate(context.Background(), &authn.Request{
+				HTTPRequest: req,
+				Resp:        web.NewResponseWriter(http.MethodPost, httptest.NewRecorder()),
+			})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, identity)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "user:1", identity.ID)
+		})
+	}
+}
+
+func grafanaRequest(req *http.Request) *authn.Request {
+	r := &authn.Request{HTTPRequest: req}
+	r.SetMeta(authn.MetaKeyAuthModule, "grafana")
+	return r
+}
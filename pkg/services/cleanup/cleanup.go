@@ -17,9 +17,11 @@ import (
 	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/audit"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	"github.com/grafana/grafana/pkg/services/queryhistory"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	tempuser "github.com/grafana/grafana/pkg/services/temp_user"
@@ -29,7 +31,8 @@ import (
 func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockService,
 	shortURLService shorturls.Service, sqlstore db.DB, queryHistoryService queryhistory.Service,
 	dashboardVersionService dashver.Service, dashSnapSvc dashboardsnapshots.Service, deleteExpiredImageService *image.DeleteExpiredService,
-	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner) *CleanUpService {
+	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner,
+	publicDashboardService publicdashboards.Service, auditService audit.Service) *CleanUpService {
 	s := &CleanUpService{
 		Cfg:                       cfg,
 		ServerLockService:         serverLockService,
@@ -43,6 +46,8 @@ func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockSe
 		tempUserService:           tempUserService,
 		tracer:                    tracer,
 		annotationCleaner:         annotationCleaner,
+		publicDashboardService:    publicDashboardService,
+		auditService:              auditService,
 	}
 	return s
 }
@@ -58,8 +63,10 @@ type CleanUpService struct {
 	dashboardVersionService   dashver.Service
 	dashboardSnapshotService  dashboardsnapshots.Service
 	deleteExpiredImageService *image.DeleteExpiredService
+	publicDashboardService    publicdashboards.Service
 	tempUserService           tempuser.Service
 	annotationCleaner         annotations.Cleaner
+	auditService              audit.Service
 }
 
 type cleanUpJob struct {
@@ -102,6 +109,8 @@ func (srv *CleanUpService) clean(ctx context.Context) {
 		{"expire old user invites", srv.expireOldUserInvites},
 		{"delete stale short URLs", srv.deleteStaleShortURLs},
 		{"delete stale query history", srv.deleteStaleQueryHistory},
+		{"delete expired public dashboard access log", srv.deleteExpiredPublicDashboardAccessLog},
+		{"delete expired audit log", srv.deleteExpiredAuditLog},
 	}
 
 	logger := srv.log.FromContext(ctx)
@@ -279,3 +288,23 @@ func (srv *CleanUpService) deleteStaleQueryHistory(ctx context.Context) {
 		logger.Debug("Enforced row limit for query_history_star", "rows affected", rowsCount)
 	}
 }
+
+func (srv *CleanUpService) deleteExpiredPublicDashboardAccessLog(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	rowsAffected, err := srv.publicDashboardService.DeleteExpiredAccessLogs(ctx)
+	if err != nil {
+		logger.Error("Problem deleting expired public dashboard access log", "error", err.Error())
+	} else {
+		logger.Debug("Deleted expired public dashboard access log", "rows affected", rowsAffected)
+	}
+}
+
+func (srv *CleanUpService) deleteExpiredAuditLog(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	rowsAffected, err := srv.auditService.DeleteExpired(ctx)
+	if err != nil {
+		logger.Error("Problem deleting expired audit log", "error", err.Error())
+	} else {
+		logger.Debug("Deleted expired audit log", "rows affected", rowsAffected)
+	}
+}
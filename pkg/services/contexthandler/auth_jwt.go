@@ -89,7 +89,8 @@ func (h *ContextHandler) initContextWithJWT(ctx *contextmodel.ReqContext, orgId
 	var role roletype.RoleType
 	var grafanaAdmin bool
 	if !h.Cfg.JWTAuthSkipOrgRoleSync {
-		role, grafanaAdmin = h.extractJWTRoleAndAdmin(claims)
+		mapping := authJWT.ClaimMappingFor(h.Cfg, claims)
+		role, grafanaAdmin = extractJWTRoleAndAdmin(mapping.RoleAttributePath, claims)
 		if h.Cfg.JWTAuthRoleAttributeStrict && !role.IsValid() {
 			ctx.Logger.Debug("Extracted Role is invalid")
 			ctx.JsonApiErr(http.StatusForbidden, InvalidRole, nil)
@@ -112,6 +113,9 @@ func (h *ContextHandler) initContextWithJWT(ctx *contextmodel.ReqContext, orgId
 				extUser.IsGrafanaAdmin = &grafanaAdmin
 			}
 		}
+		if mapped := mapping.OrgRoles(claims, role); len(mapped) > 0 {
+			extUser.OrgRoles = mapped
+		}
 	}
 
 	if query.Login == "" && query.Email == "" {
@@ -162,12 +166,12 @@ func (h *ContextHandler) initContextWithJWT(ctx *contextmodel.ReqContext, orgId
 
 const roleGrafanaAdmin = "GrafanaAdmin"
 
-func (h *ContextHandler) extractJWTRoleAndAdmin(claims map[string]interface{}) (org.RoleType, bool) {
-	if h.Cfg.JWTAuthRoleAttributePath == "" {
+func extractJWTRoleAndAdmin(roleAttributePath string, claims map[string]interface{}) (org.RoleType, bool) {
+	if roleAttributePath == "" {
 		return "", false
 	}
 
-	role, err := searchClaimsForStringAttr(h.Cfg.JWTAuthRoleAttributePath, claims)
+	role, err := searchClaimsForStringAttr(roleAttributePath, claims)
 	if err != nil || role == "" {
 		return "", false
 	}
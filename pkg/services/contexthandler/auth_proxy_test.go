@@ -27,6 +27,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/org/orgtest"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/services/twofactor/twofactortest"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/services/user/usertest"
 	"github.com/grafana/grafana/pkg/setting"
@@ -119,7 +120,7 @@ func getContextHandler(t *testing.T) *ContextHandler {
 	return ProvideService(cfg, userAuthTokenSvc, authJWTSvc, remoteCacheSvc,
 		renderSvc, sqlStore, tracer, authProxy, loginService, nil, authenticator,
 		&userService, orgService, nil, featuremgmt.WithFeatures(),
-		&authntest.FakeService{}, &anontest.FakeAnonymousSessionService{})
+		&authntest.FakeService{}, &anontest.FakeAnonymousSessionService{}, &twofactortest.FakeService{})
 }
 
 type fakeAuthenticator struct{}
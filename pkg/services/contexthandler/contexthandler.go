@@ -37,6 +37,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/oauthtoken"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/twofactor"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
@@ -54,7 +55,7 @@ func ProvideService(cfg *setting.Cfg, tokenService auth.UserTokenService, jwtSer
 	tracer tracing.Tracer, authProxy *authproxy.AuthProxy, loginService login.Service,
 	apiKeyService apikey.Service, authenticator loginpkg.Authenticator, userService user.Service,
 	orgService org.Service, oauthTokenService oauthtoken.OAuthTokenService, features *featuremgmt.FeatureManager,
-	authnService authn.Service, anonSessionService anonymous.Service,
+	authnService authn.Service, anonSessionService anonymous.Service, twoFactorService twofactor.Service,
 ) *ContextHandler {
 	return &ContextHandler{
 		Cfg:                cfg,
@@ -74,6 +75,7 @@ func ProvideService(cfg *setting.Cfg, tokenService auth.UserTokenService, jwtSer
 		features:           features,
 		authnService:       authnService,
 		anonSessionService: anonSessionService,
+		twoFactorService:   twoFactorService,
 		singleflight:       new(singleflight.Group),
 	}
 }
@@ -98,6 +100,7 @@ type ContextHandler struct {
 	authnService       authn.Service
 	singleflight       *singleflight.Group
 	anonSessionService anonymous.Service
+	twoFactorService   twofactor.Service
 	// GetTime returns the current time.
 	// Stubbable by tests.
 	GetTime func() time.Time
@@ -391,17 +394,28 @@ func (h *ContextHandler) initContextWithAPIKey(reqContext *contextmodel.ReqConte
 		return true
 	}
 
+	ip := apikey.ClientIP(reqContext.Req, apikey.AllowedIPs(h.Cfg.SATokenTrustedProxies))
+	if !apiKey.AllowedIPs.Contains(ip) {
+		reqContext.JsonApiErr(http.StatusForbidden, "API key can not be used from this IP address", nil)
+		return true
+	}
+
+	var lastUsedIP string
+	if ip != nil {
+		lastUsedIP = ip.String()
+	}
+
 	// non-blocking update api_key last used date
-	go func(id int64) {
+	go func(id int64, ip string) {
 		defer func() {
 			if err := recover(); err != nil {
 				reqContext.Logger.Error("api key authentication panic", "err", err)
 			}
 		}()
-		if err := h.apiKeyService.UpdateAPIKeyLastUsedDate(context.Background(), id); err != nil {
+		if err := h.apiKeyService.UpdateAPIKeyLastUsedDate(context.Background(), id, ip); err != nil {
 			reqContext.Logger.Warn("failed to update last use date for api key", "id", id)
 		}
-	}(apiKey.ID)
+	}(apiKey.ID, lastUsedIP)
 
 	if apiKey.ServiceAccountId == nil || *apiKey.ServiceAccountId < 1 { //There is no service account attached to the apikey
 		// Use the old APIkey method.  This provides backwards compatibility.
@@ -436,6 +450,9 @@ func (h *ContextHandler) initContextWithAPIKey(reqContext *contextmodel.ReqConte
 		return true
 	}
 
+	// scoped tokens only get the part of the service account permissions they are restricted to
+	querySignedInUserResult.RestrictToToken(apiKey.Permissions.ScopesByAction())
+
 	reqContext.IsSignedIn = true
 	reqContext.SignedInUser = querySignedInUserResult
 
@@ -482,6 +499,19 @@ func (h *ContextHandler) initContextWithBasicAuth(reqContext *contextmodel.ReqCo
 
 	usr := authQuery.User
 
+	// basic auth has no way to ask for a second factor
+	if authQuery.AuthModule == "grafana" {
+		status, err := h.twoFactorService.GetStatus(reqContext.Req.Context(), usr.ID)
+		if err != nil {
+			reqContext.JsonApiErr(500, "Failed to get two-factor authentication status", err)
+			return true
+		}
+		if status.Enabled || status.Required {
+			reqContext.JsonApiErr(401, "Basic auth is not available for users with two-factor authentication, use a service account token instead", nil)
+			return true
+		}
+	}
+
 	query := user.GetSignedInUserQuery{UserID: usr.ID, OrgID: orgID}
 	queryResult, err := h.userService.GetSignedInUserWithCacheCtx(reqContext.Req.Context(), &query)
 	if err != nil {
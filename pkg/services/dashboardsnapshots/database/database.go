@@ -63,6 +63,7 @@ func (d *DashboardSnapshotStore) CreateDashboardSnapshot(ctx context.Context, cm
 			External:           cmd.External,
 			ExternalURL:        cmd.ExternalURL,
 			ExternalDeleteURL:  cmd.ExternalDeleteURL,
+			ScheduleUID:        cmd.ScheduleUID,
 			Dashboard:          simplejson.New(),
 			DashboardEncrypted: cmd.DashboardEncrypted,
 			Expires:            expires,
@@ -124,6 +125,10 @@ func (d *DashboardSnapshotStore) SearchDashboardSnapshots(ctx context.Context, q
 			sess.Where("name LIKE ?", query.Name)
 		}
 
+		if query.ScheduleUID != "" {
+			sess.Where("schedule_uid = ?", query.ScheduleUID)
+		}
+
 		// admins can see all snapshots, everyone else can only see their own snapshots
 		switch {
 		case query.SignedInUser.OrgRole == org.RoleAdmin:
@@ -144,3 +149,107 @@ func (d *DashboardSnapshotStore) SearchDashboardSnapshots(ctx context.Context, q
 	}
 	return queryResult, nil
 }
+
+func (d *DashboardSnapshotStore) CreateSnapshotSchedule(ctx context.Context, schedule *dashboardsnapshots.DashboardSnapshotSchedule) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.UseBool("enabled").Insert(schedule)
+		return err
+	})
+}
+
+func (d *DashboardSnapshotStore) UpdateSnapshotSchedule(ctx context.Context, schedule *dashboardsnapshots.DashboardSnapshotSchedule) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Where("org_id = ? AND uid = ?", schedule.OrgID, schedule.UID).
+			Cols("name", "schedule", "time_from", "time_to", "retention", "enabled", "user_id", "updated").
+			Update(schedule)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return dashboardsnapshots.ErrScheduleNotFound.Errorf("snapshot schedule not found")
+		}
+		return nil
+	})
+}
+
+func (d *DashboardSnapshotStore) DeleteSnapshotSchedule(ctx context.Context, cmd *dashboardsnapshots.DeleteSnapshotScheduleCommand) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Where("org_id = ? AND uid = ?", cmd.OrgID, cmd.UID).Delete(&dashboardsnapshots.DashboardSnapshotSchedule{})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return dashboardsnapshots.ErrScheduleNotFound.Errorf("snapshot schedule not found")
+		}
+		return nil
+	})
+}
+
+func (d *DashboardSnapshotStore) GetSnapshotSchedule(ctx context.Context, query *dashboardsnapshots.GetSnapshotScheduleQuery) (*dashboardsnapshots.DashboardSnapshotSchedule, error) {
+	var queryResult *dashboardsnapshots.DashboardSnapshotSchedule
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		schedule := dashboardsnapshots.DashboardSnapshotSchedule{}
+		has, err := sess.Where("org_id = ? AND uid = ?", query.OrgID, query.UID).Get(&schedule)
+		if err != nil {
+			return err
+		} else if !has {
+			return dashboardsnapshots.ErrScheduleNotFound.Errorf("snapshot schedule not found")
+		}
+
+		queryResult = &schedule
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return queryResult, nil
+}
+
+func (d *DashboardSnapshotStore) SearchSnapshotSchedules(ctx context.Context, query *dashboardsnapshots.GetSnapshotSchedulesQuery) ([]*dashboardsnapshots.DashboardSnapshotSchedule, error) {
+	schedules := make([]*dashboardsnapshots.DashboardSnapshotSchedule, 0)
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		if query.OrgID != 0 {
+			sess.Where("org_id = ?", query.OrgID)
+		}
+		if query.DashboardUID != "" {
+			sess.Where("dashboard_uid = ?", query.DashboardUID)
+		}
+		if query.OnlyEnabled {
+			sess.Where("enabled = ?", true)
+		}
+		return sess.Asc("id").Find(&schedules)
+	})
+	return schedules, err
+}
+
+func (d *DashboardSnapshotStore) ClaimSnapshotScheduleRun(ctx context.Context, schedule *dashboardsnapshots.DashboardSnapshotSchedule, runAt time.Time) (bool, error) {
+	var claimed bool
+	err := d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE dashboard_snapshot_schedule SET version = ?, last_run = ? WHERE id = ? AND version = ?",
+			schedule.Version+1, runAt, schedule.ID, schedule.Version)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		claimed = affected == 1
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	if claimed {
+		schedule.Version++
+		schedule.LastRun = &runAt
+	}
+	return claimed, nil
+}
+
+func (d *DashboardSnapshotStore) SetSnapshotScheduleError(ctx context.Context, id int64, lastError string) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("UPDATE dashboard_snapshot_schedule SET last_error = ? WHERE id = ?", lastError, id)
+		return err
+	})
+}
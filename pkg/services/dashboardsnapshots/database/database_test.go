@@ -217,3 +217,107 @@ func createTestSnapshot(t *testing.T, dashStore *DashboardSnapshotStore, key str
 
 	return result
 }
+
+func TestIntegrationSnapshotSchedules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlstore := db.InitTestDB(t)
+	dashStore := ProvideStore(sqlstore, setting.NewCfg())
+	ctx := context.Background()
+
+	now := time.Now()
+	schedule := &dashboardsnapshots.DashboardSnapshotSchedule{
+		UID:          "schedule",
+		OrgID:        1,
+		DashboardUID: "dash",
+		UserID:       1000,
+		Name:         "Daily",
+		Schedule:     "0 9 * * *",
+		Retention:    3600,
+		Enabled:      true,
+		Created:      now,
+		Updated:      now,
+	}
+	require.NoError(t, dashStore.CreateSnapshotSchedule(ctx, schedule))
+	require.NoError(t, dashStore.CreateSnapshotSchedule(ctx, &dashboardsnapshots.DashboardSnapshotSchedule{
+		UID: "disabled", OrgID: 2, DashboardUID: "other", Schedule: "@daily", Created: now, Updated: now,
+	}))
+
+	t.Run("Should get schedule by uid in the org", func(t *testing.T) {
+		result, err := dashStore.GetSnapshotSchedule(ctx, &dashboardsnapshots.GetSnapshotScheduleQuery{UID: "schedule", OrgID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, "Daily", result.Name)
+		assert.Nil(t, result.LastRun)
+
+		_, err = dashStore.GetSnapshotSchedule(ctx, &dashboardsnapshots.GetSnapshotScheduleQuery{UID: "schedule", OrgID: 2})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrScheduleNotFound)
+	})
+
+	t.Run("Should search schedules", func(t *testing.T) {
+		result, err := dashStore.SearchSnapshotSchedules(ctx, &dashboardsnapshots.GetSnapshotSchedulesQuery{OnlyEnabled: true})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "schedule", result[0].UID)
+
+		result, err = dashStore.SearchSnapshotSchedules(ctx, &dashboardsnapshots.GetSnapshotSchedulesQuery{OrgID: 2})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "disabled", result[0].UID)
+	})
+
+	t.Run("Should claim a run once", func(t *testing.T) {
+		first, err := dashStore.GetSnapshotSchedule(ctx, &dashboardsnapshots.GetSnapshotScheduleQuery{UID: "schedule", OrgID: 1})
+		require.NoError(t, err)
+		second, err := dashStore.GetSnapshotSchedule(ctx, &dashboardsnapshots.GetSnapshotScheduleQuery{UID: "schedule", OrgID: 1})
+		require.NoError(t, err)
+
+		claimed, err := dashStore.ClaimSnapshotScheduleRun(ctx, first, now)
+		require.NoError(t, err)
+		assert.True(t, claimed)
+		assert.NotNil(t, first.LastRun)
+
+		claimed, err = dashStore.ClaimSnapshotScheduleRun(ctx, second, now)
+		require.NoError(t, err)
+		assert.False(t, claimed)
+
+		require.NoError(t, dashStore.SetSnapshotScheduleError(ctx, first.ID, "failed"))
+		result, err := dashStore.GetSnapshotSchedule(ctx, &dashboardsnapshots.GetSnapshotScheduleQuery{UID: "schedule", OrgID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, "failed", result.LastError)
+		assert.NotNil(t, result.LastRun)
+	})
+
+	t.Run("Should update and delete schedule", func(t *testing.T) {
+		schedule.Enabled = false
+		schedule.Schedule = "@hourly"
+		require.NoError(t, dashStore.UpdateSnapshotSchedule(ctx, schedule))
+
+		result, err := dashStore.GetSnapshotSchedule(ctx, &dashboardsnapshots.GetSnapshotScheduleQuery{UID: "schedule", OrgID: 1})
+		require.NoError(t, err)
+		assert.False(t, result.Enabled)
+		assert.Equal(t, "@hourly", result.Schedule)
+
+		require.NoError(t, dashStore.DeleteSnapshotSchedule(ctx, &dashboardsnapshots.DeleteSnapshotScheduleCommand{UID: "schedule", OrgID: 1}))
+		err = dashStore.DeleteSnapshotSchedule(ctx, &dashboardsnapshots.DeleteSnapshotScheduleCommand{UID: "schedule", OrgID: 1})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrScheduleNotFound)
+	})
+
+	t.Run("Should search snapshots of a schedule", func(t *testing.T) {
+		_, err := dashStore.CreateDashboardSnapshot(ctx, &dashboardsnapshots.CreateDashboardSnapshotCommand{
+			Key: "scheduled", DeleteKey: "deletescheduled", OrgID: 1, UserID: 1000, ScheduleUID: "schedule",
+		})
+		require.NoError(t, err)
+		createTestSnapshot(t, dashStore, "manual", 3600)
+
+		result, err := dashStore.SearchDashboardSnapshots(ctx, &dashboardsnapshots.GetDashboardSnapshotsQuery{
+			OrgID:        1,
+			ScheduleUID:  "schedule",
+			SignedInUser: &user.SignedInUser{OrgRole: org.RoleAdmin},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "scheduled", result[0].Key)
+		assert.Equal(t, "schedule", result[0].ScheduleUID)
+	})
+}
@@ -5,3 +5,11 @@ import (
 )
 
 var ErrBaseNotFound = errutil.NewBase(errutil.StatusNotFound, "dashboardsnapshots.not-found", errutil.WithPublicMessage("Snapshot not found"))
+
+var (
+	ErrScheduleNotFound  = errutil.NewBase(errutil.StatusNotFound, "dashboardsnapshots.schedule-not-found", errutil.WithPublicMessage("Snapshot schedule not found"))
+	ErrInvalidSchedule   = errutil.NewBase(errutil.StatusBadRequest, "dashboardsnapshots.invalid-schedule", errutil.WithPublicMessage("Invalid cron expression"))
+	ErrInvalidTimeRange  = errutil.NewBase(errutil.StatusBadRequest, "dashboardsnapshots.invalid-time-range", errutil.WithPublicMessage("Invalid time range"))
+	ErrInvalidRetention  = errutil.NewBase(errutil.StatusBadRequest, "dashboardsnapshots.invalid-retention", errutil.WithPublicMessage("Retention must not be negative"))
+	ErrDashboardNotFound = errutil.NewBase(errutil.StatusNotFound, "dashboardsnapshots.dashboard-not-found", errutil.WithPublicMessage("Dashboard not found"))
+)
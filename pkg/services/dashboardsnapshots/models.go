@@ -18,6 +18,7 @@ type DashboardSnapshot struct {
 	External          bool
 	ExternalURL       string `xorm:"external_url"`
 	ExternalDeleteURL string `xorm:"external_delete_url"`
+	ScheduleUID       string `xorm:"schedule_uid"`
 
 	Expires time.Time
 	Created time.Time
@@ -36,6 +37,7 @@ type DashboardSnapshotDTO struct {
 	UserID      int64  `json:"userId" xorm:"user_id"`
 	External    bool   `json:"external"`
 	ExternalURL string `json:"externalUrl" xorm:"external_url"`
+	ScheduleUID string `json:"scheduleUid,omitempty" xorm:"schedule_uid"`
 
 	Expires time.Time `json:"expires"`
 	Created time.Time `json:"created"`
@@ -73,12 +75,35 @@ type CreateDashboardSnapshotCommand struct {
 	// required:false
 	DeleteKey string `json:"deleteKey"`
 
-	OrgID  int64 `json:"-"`
-	UserID int64 `json:"-"`
+	OrgID       int64  `json:"-"`
+	UserID      int64  `json:"-"`
+	ScheduleUID string `json:"-"`
 
 	DashboardEncrypted []byte `json:"-"`
 }
 
+// TakeDashboardSnapshotCommand creates a snapshot on the server by running the queries of a saved dashboard
+type TakeDashboardSnapshotCommand struct {
+	// Snapshot name, defaults to the dashboard title
+	// required:false
+	Name string `json:"name"`
+	// When the snapshot should expire in seconds. Default is never to expire.
+	// required:false
+	// default:0
+	Expires int64 `json:"expires"`
+	// Start of the time range, defaults to the time range of the dashboard
+	// required:false
+	From string `json:"from"`
+	// End of the time range, defaults to the time range of the dashboard
+	// required:false
+	To string `json:"to"`
+
+	DashboardUID string             `json:"-"`
+	OrgID        int64              `json:"-"`
+	ScheduleUID  string             `json:"-"`
+	SignedInUser *user.SignedInUser `json:"-"`
+}
+
 type DeleteDashboardSnapshotCommand struct {
 	DeleteKey string `json:"-"`
 }
@@ -98,5 +123,88 @@ type GetDashboardSnapshotsQuery struct {
 	Name         string
 	Limit        int
 	OrgID        int64
+	ScheduleUID  string
 	SignedInUser *user.SignedInUser
 }
+
+// DashboardSnapshotSchedule takes snapshots of a dashboard on a cron schedule
+type DashboardSnapshotSchedule struct {
+	ID           int64  `json:"id" xorm:"pk autoincr 'id'"`
+	UID          string `json:"uid" xorm:"uid"`
+	OrgID        int64  `json:"orgId" xorm:"org_id"`
+	DashboardUID string `json:"dashboardUid" xorm:"dashboard_uid"`
+	// The queries run with the permissions of this user, who created or last updated the schedule
+	UserID   int64  `json:"userId" xorm:"user_id"`
+	Name     string `json:"name"`
+	Schedule string `json:"schedule"`
+	TimeFrom string `json:"timeFrom"`
+	TimeTo   string `json:"timeTo"`
+	// Expiration of the snapshots in seconds, 0 keeps them forever
+	Retention int64      `json:"retention"`
+	Enabled   bool       `json:"enabled"`
+	LastRun   *time.Time `json:"lastRun"`
+	LastError string     `json:"lastError"`
+	// Version is incremented every run, it lets a single instance claim a run
+	Version int64 `json:"-"`
+
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// swagger:model
+type CreateSnapshotScheduleCommand struct {
+	// required:true
+	DashboardUID string `json:"dashboardUid" binding:"Required"`
+	// Name of the snapshots, defaults to the dashboard title
+	// required:false
+	Name string `json:"name"`
+	// Cron expression, for example `0 9 * * *` for every day at 09:00. A time zone can be set with the `CRON_TZ=` prefix.
+	// required:true
+	Schedule string `json:"schedule" binding:"Required"`
+	// Time range of the snapshots, defaults to the time range of the dashboard
+	// required:false
+	TimeFrom string `json:"timeFrom"`
+	// required:false
+	TimeTo string `json:"timeTo"`
+	// How long the snapshots are kept in seconds. Default is to keep them forever.
+	// required:false
+	// default:0
+	Retention int64 `json:"retention"`
+	// required:false
+	// default:true
+	Enabled *bool `json:"enabled"`
+
+	OrgID  int64 `json:"-"`
+	UserID int64 `json:"-"`
+}
+
+// swagger:model
+type UpdateSnapshotScheduleCommand struct {
+	Name      string `json:"name"`
+	Schedule  string `json:"schedule" binding:"Required"`
+	TimeFrom  string `json:"timeFrom"`
+	TimeTo    string `json:"timeTo"`
+	Retention int64  `json:"retention"`
+	Enabled   bool   `json:"enabled"`
+
+	UID    string `json:"-"`
+	OrgID  int64  `json:"-"`
+	UserID int64  `json:"-"`
+}
+
+type DeleteSnapshotScheduleCommand struct {
+	UID   string
+	OrgID int64
+}
+
+type GetSnapshotScheduleQuery struct {
+	UID   string
+	OrgID int64
+}
+
+type GetSnapshotSchedulesQuery struct {
+	OrgID        int64
+	DashboardUID string
+	// OnlyEnabled returns the enabled schedules of all organizations when OrgID is 0
+	OnlyEnabled bool
+}
@@ -2,6 +2,8 @@ package dashboardsnapshots
 
 import (
 	"context"
+
+	"github.com/grafana/grafana/pkg/services/user"
 )
 
 //go:generate mockery --name Service --structname MockService --inpackage --filename service_mock.go
@@ -11,4 +13,12 @@ type Service interface {
 	DeleteExpiredSnapshots(context.Context, *DeleteExpiredSnapshotsCommand) error
 	GetDashboardSnapshot(context.Context, *GetDashboardSnapshotQuery) (*DashboardSnapshot, error)
 	SearchDashboardSnapshots(context.Context, *GetDashboardSnapshotsQuery) (DashboardSnapshotsList, error)
+	TakeDashboardSnapshot(context.Context, *TakeDashboardSnapshotCommand) (*DashboardSnapshot, error)
+
+	CreateSnapshotSchedule(context.Context, *CreateSnapshotScheduleCommand) (*DashboardSnapshotSchedule, error)
+	UpdateSnapshotSchedule(context.Context, *UpdateSnapshotScheduleCommand) (*DashboardSnapshotSchedule, error)
+	DeleteSnapshotSchedule(context.Context, *DeleteSnapshotScheduleCommand) error
+	GetSnapshotSchedule(context.Context, *GetSnapshotScheduleQuery) (*DashboardSnapshotSchedule, error)
+	SearchSnapshotSchedules(context.Context, *GetSnapshotSchedulesQuery) ([]*DashboardSnapshotSchedule, error)
+	RunSnapshotSchedule(context.Context, *DashboardSnapshotSchedule, *user.SignedInUser) (*DashboardSnapshot, error)
 }
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const maxScheduleNameLength = 255
+
+func (s *ServiceImpl) CreateSnapshotSchedule(ctx context.Context, cmd *dashboardsnapshots.CreateSnapshotScheduleCommand) (*dashboardsnapshots.DashboardSnapshotSchedule, error) {
+	if err := validateSchedule(cmd.Schedule, cmd.TimeFrom, cmd.TimeTo, cmd.Retention); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: cmd.DashboardUID, OrgID: cmd.OrgID}); err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return nil, dashboardsnapshots.ErrDashboardNotFound.Errorf("CreateSnapshotSchedule: dashboard not found by uid: %s", cmd.DashboardUID)
+		}
+		return nil, err
+	}
+
+	enabled := true
+	if cmd.Enabled != nil {
+		enabled = *cmd.Enabled
+	}
+
+	now := time.Now()
+	schedule := &dashboardsnapshots.DashboardSnapshotSchedule{
+		UID:          util.GenerateShortUID(),
+		OrgID:        cmd.OrgID,
+		DashboardUID: cmd.DashboardUID,
+		UserID:       cmd.UserID,
+		Name:         truncateName(cmd.Name),
+		Schedule:     cmd.Schedule,
+		TimeFrom:     cmd.TimeFrom,
+		TimeTo:       cmd.TimeTo,
+		Retention:    cmd.Retention,
+		Enabled:      enabled,
+		Created:      now,
+		Updated:      now,
+	}
+	if err := s.store.CreateSnapshotSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *ServiceImpl) UpdateSnapshotSchedule(ctx context.Context, cmd *dashboardsnapshots.UpdateSnapshotScheduleCommand) (*dashboardsnapshots.DashboardSnapshotSchedule, error) {
+	if err := validateSchedule(cmd.Schedule, cmd.TimeFrom, cmd.TimeTo, cmd.Retention); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.store.GetSnapshotSchedule(ctx, &dashboardsnapshots.GetSnapshotScheduleQuery{UID: cmd.UID, OrgID: cmd.OrgID})
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.Name = truncateName(cmd.Name)
+	schedule.Schedule = cmd.Schedule
+	schedule.TimeFrom = cmd.TimeFrom
+	schedule.TimeTo = cmd.TimeTo
+	schedule.Retention = cmd.Retention
+	schedule.Enabled = cmd.Enabled
+	// the schedule runs with the permissions of the user who updates it, so editors cannot run queries they do not have access to
+	schedule.UserID = cmd.UserID
+	schedule.Updated = time.Now()
+	if err := s.store.UpdateSnapshotSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *ServiceImpl) DeleteSnapshotSchedule(ctx context.Context, cmd *dashboardsnapshots.DeleteSnapshotScheduleCommand) error {
+	return s.store.DeleteSnapshotSchedule(ctx, cmd)
+}
+
+func (s *ServiceImpl) GetSnapshotSchedule(ctx context.Context, query *dashboardsnapshots.GetSnapshotScheduleQuery) (*dashboardsnapshots.DashboardSnapshotSchedule, error) {
+	return s.store.GetSnapshotSchedule(ctx, query)
+}
+
+func (s *ServiceImpl) SearchSnapshotSchedules(ctx context.Context, query *dashboardsnapshots.GetSnapshotSchedulesQuery) ([]*dashboardsnapshots.DashboardSnapshotSchedule, error) {
+	return s.store.SearchSnapshotSchedules(ctx, query)
+}
+
+// RunSnapshotSchedule takes a snapshot for the schedule now, with the permissions of the given user
+func (s *ServiceImpl) RunSnapshotSchedule(ctx context.Context, schedule *dashboardsnapshots.DashboardSnapshotSchedule, u *user.SignedInUser) (*dashboardsnapshots.DashboardSnapshot, error) {
+	return s.TakeDashboardSnapshot(ctx, &dashboardsnapshots.TakeDashboardSnapshotCommand{
+		Name:         schedule.Name,
+		Expires:      schedule.Retention,
+		From:         schedule.TimeFrom,
+		To:           schedule.TimeTo,
+		DashboardUID: schedule.DashboardUID,
+		OrgID:        schedule.OrgID,
+		ScheduleUID:  schedule.UID,
+		SignedInUser: u,
+	})
+}
+
+// Run takes the snapshots of the schedules that are due
+func (s *ServiceImpl) Run(ctx context.Context) error {
+	if s.cfg == nil || !s.cfg.SnapshotEnabled {
+		return nil
+	}
+
+	ticker := time.NewTicker(s.scheduleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			s.runDueSchedules(ctx, now)
+		}
+	}
+}
+
+func (s *ServiceImpl) runDueSchedules(ctx context.Context, now time.Time) {
+	schedules, err := s.store.SearchSnapshotSchedules(ctx, &dashboardsnapshots.GetSnapshotSchedulesQuery{OnlyEnabled: true})
+	if err != nil {
+		s.log.Error("Failed to find snapshot schedules", "error", err)
+		return
+	}
+
+	for _, schedule := range schedules {
+		if !isScheduleDue(schedule, now) {
+			continue
+		}
+
+		// several instances can run the schedules, only the one that claims the run takes the snapshot
+		claimed, err := s.store.ClaimSnapshotScheduleRun(ctx, schedule, now)
+		if err != nil {
+			s.log.Error("Failed to claim snapshot schedule run", "scheduleUid", schedule.UID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		lastError := ""
+		snapshot, err := s.runScheduleAsOwner(ctx, schedule)
+		if err != nil {
+			s.log.Error("Failed to take scheduled snapshot", "scheduleUid", schedule.UID, "dashboardUid", schedule.DashboardUID, "error", err)
+			lastError = err.Error()
+		} else {
+			s.log.Info("Scheduled snapshot taken", "scheduleUid", schedule.UID, "dashboardUid", schedule.DashboardUID, "snapshotKey", snapshot.Key)
+		}
+
+		if err := s.store.SetSnapshotScheduleError(ctx, schedule.ID, lastError); err != nil {
+			s.log.Error("Failed to save snapshot schedule result", "scheduleUid", schedule.UID, "error", err)
+		}
+	}
+}
+
+// runScheduleAsOwner takes a snapshot for the schedule with the permissions of the user who created or last updated it
+func (s *ServiceImpl) runScheduleAsOwner(ctx context.Context, schedule *dashboardsnapshots.DashboardSnapshotSchedule) (*dashboardsnapshots.DashboardSnapshot, error) {
+	u, err := s.getScheduleUser(ctx, schedule)
+	if err != nil {
+		return nil, err
+	}
+	return s.RunSnapshotSchedule(ctx, schedule, u)
+}
+
+// isScheduleDue reports whether the schedule has a run between its last run, or creation, and now
+func isScheduleDue(schedule *dashboardsnapshots.DashboardSnapshotSchedule, now time.Time) bool {
+	sched, err := cron.ParseStandard(schedule.Schedule)
+	if err != nil {
+		return false
+	}
+
+	last := schedule.Created
+	if schedule.LastRun != nil {
+		last = *schedule.LastRun
+	}
+	return !sched.Next(last).After(now)
+}
+
+// getScheduleUser returns the user who created or last updated the schedule with their permissions in the organization
+func (s *ServiceImpl) getScheduleUser(ctx context.Context, schedule *dashboardsnapshots.DashboardSnapshotSchedule) (*user.SignedInUser, error) {
+	u, err := s.userService.GetSignedInUser(ctx, &user.GetSignedInUserQuery{UserID: schedule.UserID, OrgID: schedule.OrgID})
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := s.accessControl.GetUserPermissions(ctx, u, accesscontrol.Options{ReloadCache: false})
+	if err != nil {
+		return nil, err
+	}
+	if u.Permissions == nil {
+		u.Permissions = make(map[int64]map[string][]string)
+	}
+	u.Permissions[u.OrgID] = accesscontrol.GroupScopesByAction(permissions)
+	return u, nil
+}
+
+func validateSchedule(schedule string, from string, to string, retention int64) error {
+	if _, err := cron.ParseStandard(schedule); err != nil {
+		return dashboardsnapshots.ErrInvalidSchedule.Errorf("invalid cron expression %s: %w", schedule, err)
+	}
+	if retention < 0 {
+		return dashboardsnapshots.ErrInvalidRetention.Errorf("invalid retention %d", retention)
+	}
+	if from == "" && to == "" {
+		return nil
+	}
+	_, err := parseTimeRange(from, to)
+	return err
+}
+
+func truncateName(name string) string {
+	if len(name) > maxScheduleNameLength {
+		return name[:maxScheduleNameLength]
+	}
+	return name
+}
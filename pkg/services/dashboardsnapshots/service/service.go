@@ -2,24 +2,50 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 type ServiceImpl struct {
-	store          dashboardsnapshots.Store
-	secretsService secrets.Service
+	cfg               *setting.Cfg
+	store             dashboardsnapshots.Store
+	secretsService    secrets.Service
+	dashboardService  dashboards.DashboardService
+	queryService      query.Service
+	dataSourceService datasources.DataSourceService
+	userService       user.Service
+	accessControl     accesscontrol.Service
+	log               log.Logger
+	// scheduleInterval is how often the snapshot schedules are checked
+	scheduleInterval time.Duration
 }
 
 // ServiceImpl implements the dashboardsnapshots Service interface
 var _ dashboardsnapshots.Service = (*ServiceImpl)(nil)
 
-func ProvideService(store dashboardsnapshots.Store, secretsService secrets.Service) *ServiceImpl {
+func ProvideService(cfg *setting.Cfg, store dashboardsnapshots.Store, secretsService secrets.Service, dashboardService dashboards.DashboardService,
+	queryService query.Service, dataSourceService datasources.DataSourceService, userService user.Service, accessControl accesscontrol.Service) *ServiceImpl {
 	s := &ServiceImpl{
-		store:          store,
-		secretsService: secretsService,
+		cfg:               cfg,
+		store:             store,
+		secretsService:    secretsService,
+		dashboardService:  dashboardService,
+		queryService:      queryService,
+		dataSourceService: dataSourceService,
+		userService:       userService,
+		accessControl:     accessControl,
+		log:               log.New("dashboardsnapshots"),
+		scheduleInterval:  time.Minute,
 	}
 
 	return s
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/query/templatevars"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	defaultMaxDataPoints = 1000
+	mixedDatasourceUID   = "-- Mixed --"
+)
+
+// TakeDashboardSnapshot runs the queries of a saved dashboard and stores the results as a snapshot,
+// it is the server side version of sharing a snapshot from the browser
+func (s *ServiceImpl) TakeDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.TakeDashboardSnapshotCommand) (*dashboardsnapshots.DashboardSnapshot, error) {
+	dashboard, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: cmd.DashboardUID, OrgID: cmd.OrgID})
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return nil, dashboardsnapshots.ErrDashboardNotFound.Errorf("TakeDashboardSnapshot: dashboard not found by uid: %s", cmd.DashboardUID)
+		}
+		return nil, err
+	}
+
+	from, to := cmd.From, cmd.To
+	if from == "" || to == "" {
+		from = dashboard.Data.GetPath("time", "from").MustString("now-6h")
+		to = dashboard.Data.GetPath("time", "to").MustString("now")
+	}
+	timeRange, err := parseTimeRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	name := cmd.Name
+	if name == "" {
+		name = dashboard.Title
+	}
+
+	snapshotDashboard, err := s.buildSnapshotDashboard(ctx, cmd.SignedInUser, dashboard, name, timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := util.GetRandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	deleteKey, err := util.GetRandomString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.CreateDashboardSnapshot(ctx, &dashboardsnapshots.CreateDashboardSnapshotCommand{
+		Dashboard:   snapshotDashboard,
+		Name:        name,
+		Expires:     cmd.Expires,
+		Key:         key,
+		DeleteKey:   deleteKey,
+		OrgID:       cmd.OrgID,
+		UserID:      cmd.SignedInUser.UserID,
+		ScheduleUID: cmd.ScheduleUID,
+	})
+}
+
+// parseTimeRange returns the time range if both ends are valid and from is before to
+func parseTimeRange(from string, to string) (legacydata.DataTimeRange, error) {
+	timeRange := legacydata.NewDataTimeRange(from, to)
+	fromTime, err := timeRange.ParseFrom()
+	if err != nil {
+		return timeRange, dashboardsnapshots.ErrInvalidTimeRange.Errorf("invalid time range from %s: %w", from, err)
+	}
+	toTime, err := timeRange.ParseTo()
+	if err != nil {
+		return timeRange, dashboardsnapshots.ErrInvalidTimeRange.Errorf("invalid time range to %s: %w", to, err)
+	}
+	if !fromTime.Before(toTime) {
+		return timeRange, dashboardsnapshots.ErrInvalidTimeRange.Errorf("invalid time range %s to %s", from, to)
+	}
+	return timeRange, nil
+}
+
+// buildSnapshotDashboard returns a copy of the dashboard with the data of the panels and without the queries,
+// like the snapshots created by the frontend
+func (s *ServiceImpl) buildSnapshotDashboard(ctx context.Context, u *user.SignedInUser, dashboard *dashboards.Dashboard, name string, timeRange legacydata.DataTimeRange) (*simplejson.Json, error) {
+	raw, err := dashboard.Data.Encode()
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := simplejson.NewJson(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := make(map[string][]string)
+	for varName, variable := range templatevars.FromDashboard(snapshot) {
+		variables[varName] = variable.Values()
+	}
+
+	snapshot.Set("title", name)
+	snapshot.Set("links", []interface{}{})
+	snapshot.Set("time", map[string]interface{}{
+		"from": timeRange.GetFromAsTimeUTC().Format(time.RFC3339),
+		"to":   timeRange.GetToAsTimeUTC().Format(time.RFC3339),
+	})
+	snapshot.Set("snapshot", map[string]interface{}{
+		"timestamp":   time.Now().UTC().Format(time.RFC3339),
+		"originalUrl": fmt.Sprintf("/d/%s", dashboard.UID),
+	})
+
+	for _, panelObj := range getPanels(snapshot) {
+		panel := simplejson.NewFromAny(panelObj)
+		frames, err := s.queryPanel(ctx, u, panel, variables, timeRange)
+		if err != nil {
+			// the panel is empty in the snapshot, the other panels are still useful
+			s.log.Warn("Failed to query panel for snapshot", "dashboardUid", dashboard.UID, "panelId", panel.Get("id").MustInt64(), "error", err)
+		}
+
+		panel.Set("snapshotData", frames)
+		panel.Set("targets", []interface{}{})
+		panel.Set("links", []interface{}{})
+		panel.Set("datasource", nil)
+	}
+
+	scrubAnnotations(snapshot)
+	scrubTemplateVariables(snapshot)
+
+	return snapshot, nil
+}
+
+// getPanels returns the panels of the dashboard including the panels of collapsed rows
+func getPanels(dashboard *simplejson.Json) []interface{} {
+	var panels []interface{}
+	for _, panelObj := range dashboard.Get("panels").MustArray() {
+		panel := simplejson.NewFromAny(panelObj)
+		if panel.Get("type").MustString() == "row" {
+			panels = append(panels, panel.Get("panels").MustArray()...)
+			continue
+		}
+		panels = append(panels, panelObj)
+	}
+	return panels
+}
+
+func (s *ServiceImpl) queryPanel(ctx context.Context, u *user.SignedInUser, panel *simplejson.Json, variables map[string][]string, timeRange legacydata.DataTimeRange) ([]interface{}, error) {
+	frames := make([]interface{}, 0)
+	if len(panel.Get("targets").MustArray()) == 0 {
+		return frames, nil
+	}
+
+	panelDatasource, err := s.getPanelDatasource(ctx, u, panel)
+	if err != nil {
+		return frames, err
+	}
+
+	maxDataPoints := panel.Get("maxDataPoints").MustInt64(defaultMaxDataPoints)
+	intervalMs := (timeRange.GetToAsMsEpoch() - timeRange.GetFromAsMsEpoch()) / maxDataPoints
+	if intervalMs < 1 {
+		intervalMs = 1
+	}
+
+	queries := make([]*simplejson.Json, 0)
+	for _, queryObj := range panel.Get("targets").MustArray() {
+		query := simplejson.NewFromAny(queryObj)
+		if query.Get("hide").MustBool() {
+			continue
+		}
+		if _, ok := query.CheckGet("datasource"); !ok || query.GetPath("datasource", "uid").MustString() == mixedDatasourceUID {
+			query.Set("datasource", panelDatasource)
+		}
+		templatevars.InterpolateQuery(query, variables)
+		query.Set("intervalMs", intervalMs)
+		query.Set("maxDataPoints", maxDataPoints)
+		queries = append(queries, query)
+	}
+	if len(queries) == 0 {
+		return frames, nil
+	}
+
+	res, err := s.queryService.QueryData(ctx, u, false, dtos.MetricRequest{
+		From:    strconv.FormatInt(timeRange.GetFromAsMsEpoch(), 10),
+		To:      strconv.FormatInt(timeRange.GetToAsMsEpoch(), 10),
+		Queries: queries,
+	})
+	if err != nil {
+		return frames, err
+	}
+
+	// keep the order of the queries in the panel
+	for _, query := range queries {
+		refID := query.Get("refId").MustString("A")
+		response, ok := res.Responses[refID]
+		if !ok {
+			continue
+		}
+		if response.Error != nil {
+			err = response.Error
+		}
+		for _, frame := range response.Frames {
+			frames = append(frames, frameToSnapshotData(frame, refID))
+		}
+	}
+	return frames, err
+}
+
+// getPanelDatasource returns the datasource reference of the panel, panels without one use the default datasource
+func (s *ServiceImpl) getPanelDatasource(ctx context.Context, u *user.SignedInUser, panel *simplejson.Json) (interface{}, error) {
+	if ds, ok := panel.CheckGet("datasource"); ok && ds.Interface() != nil {
+		if uid := ds.Get("uid").MustString(); uid != "" && uid != mixedDatasourceUID {
+			return ds.Interface(), nil
+		}
+		// before 8.3 panels referenced the datasource by name
+		if name := ds.MustString(); name != "" {
+			ds, err := s.dataSourceService.GetDataSource(ctx, &datasources.GetDataSourceQuery{Name: name, OrgID: u.OrgID})
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"uid": ds.UID, "type": ds.Type}, nil
+		}
+	}
+
+	ds, err := s.dataSourceService.GetDefaultDataSource(ctx, &datasources.GetDefaultDataSourceQuery{OrgID: u.OrgID, User: u})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"uid": ds.UID, "type": ds.Type}, nil
+}
+
+// frameToSnapshotData converts a frame to the DataFrameDTO format of the frontend snapshots
+func frameToSnapshotData(frame *data.Frame, refID string) map[string]interface{} {
+	fields := make([]interface{}, 0, len(frame.Fields))
+	for _, field := range frame.Fields {
+		values := make([]interface{}, field.Len())
+		for i := range values {
+			v, ok := field.ConcreteAt(i)
+			if !ok {
+				continue
+			}
+			if t, ok := v.(time.Time); ok {
+				v = t.UnixMilli()
+			}
+			values[i] = v
+		}
+
+		dto := map[string]interface{}{
+			"name":   field.Name,
+			"type":   frontendFieldType(field.Type()),
+			"values": values,
+		}
+		if field.Config != nil {
+			dto["config"] = field.Config
+		} else {
+			dto["config"] = map[string]interface{}{}
+		}
+		if len(field.Labels) > 0 {
+			dto["labels"] = field.Labels
+		}
+		fields = append(fields, dto)
+	}
+
+	dto := map[string]interface{}{
+		"name":   frame.Name,
+		"refId":  refID,
+		"fields": fields,
+	}
+	if frame.Meta != nil {
+		meta := *frame.Meta
+		// snapshots are shared, don't leak the queries
+		meta.ExecutedQueryString = ""
+		dto["meta"] = meta
+	}
+	return dto
+}
+
+func frontendFieldType(t data.FieldType) string {
+	switch {
+	case t.Time():
+		return "time"
+	case t.Numeric():
+		return "number"
+	case t == data.FieldTypeString || t == data.FieldTypeNullableString:
+		return "string"
+	case t == data.FieldTypeBool || t == data.FieldTypeNullableBool:
+		return "boolean"
+	default:
+		return "other"
+	}
+}
+
+// scrubAnnotations keeps the enabled annotations without their queries
+func scrubAnnotations(dashboard *simplejson.Json) {
+	annotations := make([]interface{}, 0)
+	for _, obj := range dashboard.GetPath("annotations", "list").MustArray() {
+		annotation := simplejson.NewFromAny(obj)
+		if !annotation.Get("enable").MustBool() {
+			continue
+		}
+		scrubbed := map[string]interface{}{"snapshotData": []interface{}{}}
+		for _, key := range []string{"name", "enable", "iconColor", "type", "builtIn", "hide"} {
+			if v, ok := annotation.CheckGet(key); ok {
+				scrubbed[key] = v.Interface()
+			}
+		}
+		annotations = append(annotations, scrubbed)
+	}
+	dashboard.SetPath([]string{"annotations", "list"}, annotations)
+}
+
+// scrubTemplateVariables removes the variable queries and keeps the current values as the only option
+func scrubTemplateVariables(dashboard *simplejson.Json) {
+	for _, obj := range dashboard.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(obj)
+		variable.Set("query", "")
+		variable.Set("refresh", 0)
+		if current, ok := variable.CheckGet("current"); ok && current.Interface() != nil {
+			variable.Set("options", []interface{}{current.Interface()})
+		} else {
+			variable.Set("options", []interface{}{})
+		}
+	}
+}
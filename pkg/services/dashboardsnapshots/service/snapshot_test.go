@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	dashsnapdb "github.com/grafana/grafana/pkg/services/dashboardsnapshots/database"
+	datasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const dashboardToSnapshot = `
+{
+  "uid": "dash",
+  "title": "Dashboard",
+  "time": { "from": "now-1h", "to": "now" },
+  "links": [{ "title": "link" }],
+  "templating": {
+    "list": [
+      { "name": "job", "type": "query", "query": "label_values(job)", "current": { "text": "api", "value": "api" }, "options": [] }
+    ]
+  },
+  "annotations": {
+    "list": [
+      { "name": "Annotations & Alerts", "builtIn": 1, "enable": true, "type": "dashboard" },
+      { "name": "Deploys", "enable": false, "datasource": { "uid": "ds" } }
+    ]
+  },
+  "panels": [
+    {
+      "id": 1,
+      "type": "timeseries",
+      "datasource": { "uid": "ds", "type": "prometheus" },
+      "targets": [
+        { "refId": "A", "expr": "up{job=\"$job\"}" },
+        { "refId": "B", "expr": "hidden", "hide": true }
+      ]
+    },
+    {
+      "id": 2,
+      "type": "row",
+      "collapsed": true,
+      "panels": [{ "id": 3, "type": "text" }]
+    }
+  ]
+}`
+
+func TestTakeDashboardSnapshot(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	dsStore := dashsnapdb.ProvideStore(sqlStore, setting.NewCfg())
+	secretsService := secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+
+	dashboardData, err := simplejson.NewJson([]byte(dashboardToSnapshot))
+	require.NoError(t, err)
+
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboard", mock.Anything, &dashboards.GetDashboardQuery{UID: "dash", OrgID: 1}).
+		Return(&dashboards.Dashboard{UID: "dash", OrgID: 1, Title: "Dashboard", Data: dashboardData}, nil)
+	dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(nil, dashboards.ErrDashboardNotFound)
+
+	frame := data.NewFrame("up",
+		data.NewField("time", nil, []time.Time{time.UnixMilli(1000)}),
+		data.NewField("value", data.Labels{"job": "api"}, []*float64{nil}),
+	)
+	queryService := query.NewFakeQueryService(t)
+	queryService.On("QueryData", mock.Anything, mock.Anything, false, mock.MatchedBy(func(req dtos.MetricRequest) bool {
+		return len(req.Queries) == 1 && req.Queries[0].Get("expr").MustString() == `up{job="api"}`
+	})).Return(&backend.QueryDataResponse{Responses: backend.Responses{"A": {Frames: data.Frames{frame}}}}, nil).Once()
+
+	s := ProvideService(setting.NewCfg(), dsStore, secretsService, dashboardService, queryService, &datasources.FakeDataSourceService{}, nil, nil)
+	signedInUser := &user.SignedInUser{UserID: 10, OrgID: 1}
+
+	t.Run("runs the queries and stores the results", func(t *testing.T) {
+		snapshot, err := s.TakeDashboardSnapshot(context.Background(), &dashboardsnapshots.TakeDashboardSnapshotCommand{
+			DashboardUID: "dash",
+			OrgID:        1,
+			Expires:      3600,
+			ScheduleUID:  "schedule",
+			SignedInUser: signedInUser,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, snapshot.Key)
+		require.Equal(t, "Dashboard", snapshot.Name)
+		require.Equal(t, "schedule", snapshot.ScheduleUID)
+		require.Equal(t, int64(10), snapshot.UserID)
+
+		stored, err := s.GetDashboardSnapshot(context.Background(), &dashboardsnapshots.GetDashboardSnapshotQuery{Key: snapshot.Key})
+		require.NoError(t, err)
+		dash := stored.Dashboard
+
+		require.Equal(t, "/d/dash", dash.GetPath("snapshot", "originalUrl").MustString())
+		require.Empty(t, dash.Get("links").MustArray())
+		_, err = time.Parse(time.RFC3339, dash.GetPath("time", "from").MustString())
+		require.NoError(t, err)
+
+		panel := dash.Get("panels").GetIndex(0)
+		require.Empty(t, panel.Get("targets").MustArray())
+		require.Nil(t, panel.Get("datasource").Interface())
+		snapshotData := panel.Get("snapshotData").GetIndex(0)
+		require.Equal(t, "A", snapshotData.Get("refId").MustString())
+		require.Equal(t, "time", snapshotData.Get("fields").GetIndex(0).Get("type").MustString())
+		require.Equal(t, int64(1000), snapshotData.Get("fields").GetIndex(0).Get("values").GetIndex(0).MustInt64())
+		require.Equal(t, "number", snapshotData.Get("fields").GetIndex(1).Get("type").MustString())
+		require.Equal(t, []interface{}{nil}, snapshotData.Get("fields").GetIndex(1).Get("values").MustArray())
+		require.Equal(t, "api", snapshotData.Get("fields").GetIndex(1).GetPath("labels", "job").MustString())
+
+		textPanel := dash.Get("panels").GetIndex(1).Get("panels").GetIndex(0)
+		require.Empty(t, textPanel.Get("snapshotData").MustArray())
+
+		variable := dash.GetPath("templating", "list").GetIndex(0)
+		require.Equal(t, "", variable.Get("query").MustString())
+		require.Len(t, variable.Get("options").MustArray(), 1)
+
+		annotations := dash.GetPath("annotations", "list").MustArray()
+		require.Len(t, annotations, 1)
+	})
+
+	t.Run("returns error when dashboard does not exist", func(t *testing.T) {
+		_, err := s.TakeDashboardSnapshot(context.Background(), &dashboardsnapshots.TakeDashboardSnapshotCommand{
+			DashboardUID: "missing",
+			OrgID:        1,
+			SignedInUser: signedInUser,
+		})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrDashboardNotFound)
+	})
+
+	t.Run("returns error when time range is invalid", func(t *testing.T) {
+		_, err := s.TakeDashboardSnapshot(context.Background(), &dashboardsnapshots.TakeDashboardSnapshotCommand{
+			DashboardUID: "dash",
+			OrgID:        1,
+			From:         "now",
+			To:           "now-1h",
+			SignedInUser: signedInUser,
+		})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrInvalidTimeRange)
+	})
+}
+
+func TestSnapshotScheduleUsers(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	dsStore := dashsnapdb.ProvideStore(sqlStore, setting.NewCfg())
+	secretsService := secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+
+	dashboardData, err := simplejson.NewJson([]byte(dashboardToSnapshot))
+	require.NoError(t, err)
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboard", mock.Anything, &dashboards.GetDashboardQuery{UID: "dash", OrgID: 1}).
+		Return(&dashboards.Dashboard{UID: "dash", OrgID: 1, Title: "Dashboard", Data: dashboardData}, nil)
+
+	// the queries must run as the editor, not as the user who created the schedule
+	queryService := query.NewFakeQueryService(t)
+	queryService.On("QueryData", mock.Anything, mock.MatchedBy(func(u *user.SignedInUser) bool { return u.UserID == 20 }), false, mock.Anything).
+		Return(&backend.QueryDataResponse{Responses: backend.Responses{}}, nil).Once()
+
+	s := ProvideService(setting.NewCfg(), dsStore, secretsService, dashboardService, queryService, &datasources.FakeDataSourceService{}, nil, nil)
+	owner := &user.SignedInUser{UserID: 10, OrgID: 1}
+	editor := &user.SignedInUser{UserID: 20, OrgID: 1}
+
+	schedule, err := s.CreateSnapshotSchedule(context.Background(), &dashboardsnapshots.CreateSnapshotScheduleCommand{
+		DashboardUID: "dash",
+		Schedule:     "@daily",
+		OrgID:        1,
+		UserID:       owner.UserID,
+	})
+	require.NoError(t, err)
+
+	t.Run("updating a schedule makes it run with the permissions of the editor", func(t *testing.T) {
+		updated, err := s.UpdateSnapshotSchedule(context.Background(), &dashboardsnapshots.UpdateSnapshotScheduleCommand{
+			UID:      schedule.UID,
+			OrgID:    1,
+			UserID:   editor.UserID,
+			Schedule: "@hourly",
+			Enabled:  true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, editor.UserID, updated.UserID)
+
+		stored, err := s.GetSnapshotSchedule(context.Background(), &dashboardsnapshots.GetSnapshotScheduleQuery{UID: schedule.UID, OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, editor.UserID, stored.UserID)
+	})
+
+	t.Run("running a schedule takes the snapshot with the permissions of the caller", func(t *testing.T) {
+		snapshot, err := s.RunSnapshotSchedule(context.Background(), schedule, editor)
+		require.NoError(t, err)
+		require.Equal(t, editor.UserID, snapshot.UserID)
+		require.Equal(t, schedule.UID, snapshot.ScheduleUID)
+	})
+}
+
+func TestIsScheduleDue(t *testing.T) {
+	created := time.Date(2023, 5, 1, 8, 0, 0, 0, time.UTC)
+	schedule := &dashboardsnapshots.DashboardSnapshotSchedule{Schedule: "0 9 * * *", Created: created}
+
+	require.False(t, isScheduleDue(schedule, created.Add(59*time.Minute)))
+	require.True(t, isScheduleDue(schedule, created.Add(time.Hour)))
+
+	lastRun := created.Add(time.Hour)
+	schedule.LastRun = &lastRun
+	require.False(t, isScheduleDue(schedule, created.Add(2*time.Hour)))
+	require.True(t, isScheduleDue(schedule, created.Add(25*time.Hour)))
+
+	schedule.Schedule = "not a cron expression"
+	require.False(t, isScheduleDue(schedule, created.Add(25*time.Hour)))
+}
+
+func TestValidateSchedule(t *testing.T) {
+	require.NoError(t, validateSchedule("0 9 * * *", "", "", 0))
+	require.NoError(t, validateSchedule("CRON_TZ=Europe/Stockholm 0 9 * * 1-5", "now-24h", "now", 86400))
+	require.NoError(t, validateSchedule("@daily", "now-1d/d", "now-1d/d", 0))
+	require.ErrorIs(t, validateSchedule("every day", "", "", 0), dashboardsnapshots.ErrInvalidSchedule)
+	require.ErrorIs(t, validateSchedule("@daily", "", "", -1), dashboardsnapshots.ErrInvalidRetention)
+	require.ErrorIs(t, validateSchedule("@daily", "now-1h", "", 0), dashboardsnapshots.ErrInvalidTimeRange)
+	require.ErrorIs(t, validateSchedule("@daily", "yesterday", "now", 0), dashboardsnapshots.ErrInvalidTimeRange)
+}
@@ -6,6 +6,8 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	user "github.com/grafana/grafana/pkg/services/user"
 )
 
 // MockService is an autogenerated mock type for the Service type
@@ -36,6 +38,29 @@ func (_m *MockService) CreateDashboardSnapshot(_a0 context.Context, _a1 *CreateD
 	return r0, r1
 }
 
+// CreateSnapshotSchedule provides a mock function with given fields: _a0, _a1
+func (_m *MockService) CreateSnapshotSchedule(_a0 context.Context, _a1 *CreateSnapshotScheduleCommand) (*DashboardSnapshotSchedule, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *DashboardSnapshotSchedule
+	if rf, ok := ret.Get(0).(func(context.Context, *CreateSnapshotScheduleCommand) *DashboardSnapshotSchedule); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DashboardSnapshotSchedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *CreateSnapshotScheduleCommand) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteDashboardSnapshot provides a mock function with given fields: _a0, _a1
 func (_m *MockService) DeleteDashboardSnapshot(_a0 context.Context, _a1 *DeleteDashboardSnapshotCommand) error {
 	ret := _m.Called(_a0, _a1)
@@ -64,6 +89,20 @@ func (_m *MockService) DeleteExpiredSnapshots(_a0 context.Context, _a1 *DeleteEx
 	return r0
 }
 
+// DeleteSnapshotSchedule provides a mock function with given fields: _a0, _a1
+func (_m *MockService) DeleteSnapshotSchedule(_a0 context.Context, _a1 *DeleteSnapshotScheduleCommand) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *DeleteSnapshotScheduleCommand) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetDashboardSnapshot provides a mock function with given fields: _a0, _a1
 func (_m *MockService) GetDashboardSnapshot(_a0 context.Context, _a1 *GetDashboardSnapshotQuery) (*DashboardSnapshot, error) {
 	ret := _m.Called(_a0, _a1)
@@ -87,6 +126,52 @@ func (_m *MockService) GetDashboardSnapshot(_a0 context.Context, _a1 *GetDashboa
 	return r0, r1
 }
 
+// GetSnapshotSchedule provides a mock function with given fields: _a0, _a1
+func (_m *MockService) GetSnapshotSchedule(_a0 context.Context, _a1 *GetSnapshotScheduleQuery) (*DashboardSnapshotSchedule, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *DashboardSnapshotSchedule
+	if rf, ok := ret.Get(0).(func(context.Context, *GetSnapshotScheduleQuery) *DashboardSnapshotSchedule); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DashboardSnapshotSchedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *GetSnapshotScheduleQuery) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RunSnapshotSchedule provides a mock function with given fields: _a0, _a1, _a2
+func (_m *MockService) RunSnapshotSchedule(_a0 context.Context, _a1 *DashboardSnapshotSchedule, _a2 *user.SignedInUser) (*DashboardSnapshot, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 *DashboardSnapshot
+	if rf, ok := ret.Get(0).(func(context.Context, *DashboardSnapshotSchedule, *user.SignedInUser) *DashboardSnapshot); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DashboardSnapshot)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *DashboardSnapshotSchedule, *user.SignedInUser) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SearchDashboardSnapshots provides a mock function with given fields: _a0, _a1
 func (_m *MockService) SearchDashboardSnapshots(_a0 context.Context, _a1 *GetDashboardSnapshotsQuery) (DashboardSnapshotsList, error) {
 	ret := _m.Called(_a0, _a1)
@@ -110,6 +195,75 @@ func (_m *MockService) SearchDashboardSnapshots(_a0 context.Context, _a1 *GetDas
 	return r0, r1
 }
 
+// SearchSnapshotSchedules provides a mock function with given fields: _a0, _a1
+func (_m *MockService) SearchSnapshotSchedules(_a0 context.Context, _a1 *GetSnapshotSchedulesQuery) ([]*DashboardSnapshotSchedule, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []*DashboardSnapshotSchedule
+	if rf, ok := ret.Get(0).(func(context.Context, *GetSnapshotSchedulesQuery) []*DashboardSnapshotSchedule); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*DashboardSnapshotSchedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *GetSnapshotSchedulesQuery) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TakeDashboardSnapshot provides a mock function with given fields: _a0, _a1
+func (_m *MockService) TakeDashboardSnapshot(_a0 context.Context, _a1 *TakeDashboardSnapshotCommand) (*DashboardSnapshot, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *DashboardSnapshot
+	if rf, ok := ret.Get(0).(func(context.Context, *TakeDashboardSnapshotCommand) *DashboardSnapshot); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DashboardSnapshot)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *TakeDashboardSnapshotCommand) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateSnapshotSchedule provides a mock function with given fields: _a0, _a1
+func (_m *MockService) UpdateSnapshotSchedule(_a0 context.Context, _a1 *UpdateSnapshotScheduleCommand) (*DashboardSnapshotSchedule, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *DashboardSnapshotSchedule
+	if rf, ok := ret.Get(0).(func(context.Context, *UpdateSnapshotScheduleCommand) *DashboardSnapshotSchedule); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DashboardSnapshotSchedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *UpdateSnapshotScheduleCommand) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewMockService interface {
 	mock.TestingT
 	Cleanup(func())
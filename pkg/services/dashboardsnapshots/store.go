@@ -2,6 +2,7 @@ package dashboardsnapshots
 
 import (
 	"context"
+	"time"
 )
 
 type Store interface {
@@ -10,4 +11,13 @@ type Store interface {
 	DeleteExpiredSnapshots(context.Context, *DeleteExpiredSnapshotsCommand) error
 	GetDashboardSnapshot(context.Context, *GetDashboardSnapshotQuery) (*DashboardSnapshot, error)
 	SearchDashboardSnapshots(context.Context, *GetDashboardSnapshotsQuery) (DashboardSnapshotsList, error)
+
+	CreateSnapshotSchedule(context.Context, *DashboardSnapshotSchedule) error
+	UpdateSnapshotSchedule(context.Context, *DashboardSnapshotSchedule) error
+	DeleteSnapshotSchedule(context.Context, *DeleteSnapshotScheduleCommand) error
+	GetSnapshotSchedule(context.Context, *GetSnapshotScheduleQuery) (*DashboardSnapshotSchedule, error)
+	SearchSnapshotSchedules(context.Context, *GetSnapshotSchedulesQuery) ([]*DashboardSnapshotSchedule, error)
+	// ClaimSnapshotScheduleRun marks the schedule as run at runAt, it returns false if another instance claimed the run first
+	ClaimSnapshotScheduleRun(ctx context.Context, schedule *DashboardSnapshotSchedule, runAt time.Time) (bool, error)
+	SetSnapshotScheduleError(ctx context.Context, id int64, lastError string) error
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/plugins"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/oauthtoken"
@@ -78,6 +79,9 @@ func (p *DataSourceProxyService) ProxyDatasourceRequestWithUID(c *contextmodel.R
 		toAPIError(c, err)
 		return
 	}
+	if !p.checkDatasourceAccess(c, ds) {
+		return
+	}
 	p.proxyDatasourceRequest(c, ds)
 }
 
@@ -89,6 +93,9 @@ func (p *DataSourceProxyService) ProxyDatasourceRequestWithID(c *contextmodel.Re
 		toAPIError(c, err)
 		return
 	}
+	if !p.checkDatasourceAccess(c, ds) {
+		return
+	}
 	p.proxyDatasourceRequest(c, ds)
 }
 
@@ -104,6 +111,26 @@ func toAPIError(c *contextmodel.ReqContext, err error) {
 	c.JsonApiErr(http.StatusInternalServerError, "Unable to load datasource meta data", err)
 }
 
+// checkDatasourceAccess verifies that the user can query the data source and
+// that the data source can be used through the proxy. Requests to the proxy
+// are not tied to a dashboard and cannot be validated against the query
+// restrictions of the data source, so restricted data sources are rejected.
+func (p *DataSourceProxyService) checkDatasourceAccess(c *contextmodel.ReqContext, ds *datasources.DataSource) bool {
+	if !ac.IsDisabled(p.Cfg) {
+		evaluator := ac.EvalPermission(datasources.ActionQuery, datasources.ScopeProvider.GetResourceScopeUID(ds.UID))
+		if !evaluator.Evaluate(c.SignedInUser.Permissions[c.OrgID]) {
+			c.JsonApiErr(http.StatusForbidden, "Access denied to datasource", datasources.ErrDataSourceAccessDenied)
+			return false
+		}
+	}
+
+	if !ds.QueryRestrictions().IsEmpty() {
+		c.JsonApiErr(http.StatusForbidden, "Data source has query restrictions and cannot be used through the data source proxy", nil)
+		return false
+	}
+	return true
+}
+
 func (p *DataSourceProxyService) proxyDatasourceRequest(c *contextmodel.ReqContext, ds *datasources.DataSource) {
 	err := p.PluginRequestValidator.Validate(ds.URL, c.Req)
 	if err != nil {
@@ -8,6 +8,8 @@ const (
 
 	ActionRead             = "datasources:read"
 	ActionQuery            = "datasources:query"
+	ActionExplore          = accesscontrol.ActionDatasourcesExplore
+	ActionAlert            = "datasources:alert"
 	ActionCreate           = "datasources:create"
 	ActionWrite            = "datasources:write"
 	ActionDelete           = "datasources:delete"
@@ -23,6 +23,8 @@ const (
 	DS_MYSQL          = "mysql"
 	DS_POSTGRES       = "postgres"
 	DS_MSSQL          = "mssql"
+	DS_SQLITE         = "sqlite"
+	DS_GENERIC_SQL    = "genericsql"
 	DS_ACCESS_DIRECT  = "direct"
 	DS_ACCESS_PROXY   = "proxy"
 	DS_ES_OPEN_DISTRO = "grafana-es-open-distro-datasource"
@@ -73,6 +75,43 @@ func (ds DataSource) AllowedCookies() []string {
 	return []string{}
 }
 
+// QueryRestrictions limits the queries that can be sent to a data source.
+type QueryRestrictions struct {
+	// AllowedQueryTypes are the only query types that can be used. All query types are allowed if it is empty.
+	AllowedQueryTypes []string
+	// ReadOnlySQL only allows SQL queries that do not modify data.
+	ReadOnlySQL bool
+}
+
+// IsEmpty returns true if no restriction is configured.
+func (r QueryRestrictions) IsEmpty() bool {
+	return len(r.AllowedQueryTypes) == 0 && !r.ReadOnlySQL
+}
+
+// QueryRestrictions returns the query restrictions configured in the data
+// source's jsonData with the allowedQueryTypes and readOnlySql keys.
+func (ds DataSource) QueryRestrictions() QueryRestrictions {
+	r := QueryRestrictions{}
+	if ds.JsonData == nil {
+		return r
+	}
+
+	r.AllowedQueryTypes = ds.JsonData.Get("allowedQueryTypes").MustStringArray()
+	if ds.IsSQL() {
+		r.ReadOnlySQL = ds.JsonData.Get("readOnlySql").MustBool(false)
+	}
+	return r
+}
+
+// IsSQL returns true for the SQL data sources that support read-only query validation.
+func (ds DataSource) IsSQL() bool {
+	switch ds.Type {
+	case DS_MYSQL, DS_POSTGRES, DS_MSSQL, DS_SQLITE, DS_GENERIC_SQL:
+		return true
+	}
+	return false
+}
+
 // Specific error type for grpc secrets management so that we can show more detailed plugin errors to users
 type ErrDatasourceSecretsPluginUserFriendly struct {
 	Err string
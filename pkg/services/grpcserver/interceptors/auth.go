@@ -2,14 +2,17 @@ package interceptors
 
 import (
 	"context"
+	"net"
 	"strings"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
 	"github.com/grafana/grafana/pkg/components/satokengen"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/network"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/apikey"
 	grpccontext "github.com/grafana/grafana/pkg/services/grpcserver/context"
@@ -98,6 +101,16 @@ func (a *authenticator) getSignedInUser(ctx context.Context, token string) (*use
 		return nil, status.Error(codes.Unauthenticated, "api key does not have a service account")
 	}
 
+	if len(apikey.AllowedIPs) > 0 {
+		var ip net.IP
+		if p, ok := peer.FromContext(ctx); ok {
+			ip, _ = network.GetIPFromAddress(p.Addr.String())
+		}
+		if !apikey.AllowedIPs.Contains(ip) {
+			return nil, status.Error(codes.PermissionDenied, "api key can not be used from this address")
+		}
+	}
+
 	querySignedInUser := user.GetSignedInUserQuery{UserID: *apikey.ServiceAccountId, OrgID: apikey.OrgID}
 	signedInUser, err := a.UserService.GetSignedInUserWithCacheCtx(ctx, &querySignedInUser)
 	if err != nil {
@@ -117,6 +130,8 @@ func (a *authenticator) getSignedInUser(ctx context.Context, token string) (*use
 		return nil, status.Error(codes.PermissionDenied, "service account is disabled")
 	}
 
+	signedInUser.RestrictToToken(apikey.Permissions.ScopesByAction())
+
 	if signedInUser.Permissions == nil {
 		signedInUser.Permissions = make(map[int64]map[string][]string)
 	}
@@ -559,7 +559,9 @@ func (g *GrafanaLive) handleOnRPC(client *centrifuge.Client, e centrifuge.RPCEve
 	if err != nil {
 		return centrifuge.RPCReply{}, centrifuge.ErrorBadRequest
 	}
-	resp, err := g.queryDataService.QueryData(client.Context(), user, false, req)
+	// queries over Live are not tied to a dashboard, they are authorized like Explore queries
+	ctx := query.WithUsage(client.Context(), query.UsageExplore)
+	resp, err := g.queryDataService.QueryData(ctx, user, false, req)
 	if err != nil {
 		logger.Error("Error query data", "user", client.UserID(), "client", client.ID(), "method", e.Method, "error", err)
 		if errors.Is(err, datasources.ErrDataSourceAccessDenied) {
@@ -569,6 +571,9 @@ func (g *GrafanaLive) handleOnRPC(client *centrifuge.Client, e centrifuge.RPCEve
 		if errors.As(err, &gfErr) && gfErr.Reason.Status() == errutil.StatusBadRequest {
 			return centrifuge.RPCReply{}, &centrifuge.Error{Code: uint32(http.StatusBadRequest), Message: http.StatusText(http.StatusBadRequest)}
 		}
+		if errors.As(err, &gfErr) && gfErr.Reason.Status() == errutil.StatusForbidden {
+			return centrifuge.RPCReply{}, &centrifuge.Error{Code: uint32(http.StatusForbidden), Message: http.StatusText(http.StatusForbidden)}
+		}
 		return centrifuge.RPCReply{}, centrifuge.ErrorInternal
 	}
 	data, err := jsonStd.Marshal(resp)
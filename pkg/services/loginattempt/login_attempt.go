@@ -2,16 +2,24 @@ package loginattempt
 
 import (
 	"context"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
+var ErrAccountLocked = errutil.NewBase(errutil.StatusUnauthorized, "login-attempt.locked",
+	errutil.WithPublicMessage("Account is locked after too many failed login attempts, contact your administrator"))
+
 type Service interface {
 	// Add adds a new login attempt record for provided username
 	Add(ctx context.Context, username, IPAddress string) error
 	// Validate checks if username has to many login attempts inside a window.
 	// Will return true if provided username do not have too many attempts.
+	// Returns ErrAccountLocked if account lockout is enabled and the username is locked.
 	Validate(ctx context.Context, username string) (bool, error)
 	// Reset resets all login attempts attached to username
 	Reset(ctx context.Context, username string) error
+	// Unlock removes the lockout and resets all login attempts attached to username
+	Unlock(ctx context.Context, username string) error
 }
 
 type LoginAttempt struct {
@@ -20,3 +28,9 @@ type LoginAttempt struct {
 	IpAddress string
 	Created   int64
 }
+
+type LoginLockout struct {
+	Id       int64
+	Username string
+	LockedAt int64
+}
@@ -2,33 +2,41 @@ package loginattemptimpl
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
 const (
 	maxInvalidLoginAttempts int64 = 5
 	loginAttemptsWindow           = time.Minute * 5
+	// lockoutAttemptsWindow is the window failed login attempts are counted in
+	// when account lockout is enabled.
+	lockoutAttemptsWindow = time.Hour * 24
 )
 
-func ProvideService(db db.DB, cfg *setting.Cfg, lock *serverlock.ServerLockService) *Service {
+func ProvideService(db db.DB, cfg *setting.Cfg, lock *serverlock.ServerLockService, userService user.Service) *Service {
 	return &Service{
 		&xormStore{db: db, now: time.Now},
 		cfg,
 		lock,
 		log.New("login_attempt"),
+		userService,
 	}
 }
 
 type Service struct {
-	store  store
-	cfg    *setting.Cfg
-	lock   *serverlock.ServerLockService
-	logger log.Logger
+	store       store
+	cfg         *setting.Cfg
+	lock        *serverlock.ServerLockService
+	logger      log.Logger
+	userService user.Service
 }
 
 func (s *Service) Run(ctx context.Context) error {
@@ -53,15 +61,45 @@ func (s *Service) Add(ctx context.Context, username, IPAddress string) error {
 		return nil
 	}
 
+	username = s.attemptsKey(ctx, username)
+
 	_, err := s.store.CreateLoginAttempt(ctx, CreateLoginAttemptCommand{
 		Username:  username,
 		IpAddress: IPAddress,
 	})
-	return err
+	if err != nil || !s.lockoutEnabled() {
+		return err
+	}
+
+	count, err := s.store.GetUserLoginAttemptCount(ctx, GetUserLoginAttemptCountQuery{
+		Username: username,
+		Since:    time.Now().Add(-lockoutAttemptsWindow),
+	})
+	if err != nil {
+		return err
+	}
+
+	if count >= int64(s.cfg.LoginLockoutThreshold) {
+		s.logger.Warn("Locking account after too many failed login attempts", "username", username, "attempts", count)
+		return s.store.CreateLoginLockout(ctx, CreateLoginLockoutCommand{Username: username})
+	}
+
+	return nil
 }
 
 func (s *Service) Reset(ctx context.Context, username string) error {
-	return s.store.DeleteLoginAttempts(ctx, DeleteLoginAttemptsCommand{username})
+	return s.store.DeleteLoginAttempts(ctx, DeleteLoginAttemptsCommand{s.attemptsKey(ctx, username)})
+}
+
+func (s *Service) Unlock(ctx context.Context, username string) error {
+	return s.unlock(ctx, s.attemptsKey(ctx, username))
+}
+
+func (s *Service) unlock(ctx context.Context, key string) error {
+	if err := s.store.DeleteLoginLockout(ctx, DeleteLoginLockoutCommand{Username: key}); err != nil {
+		return err
+	}
+	return s.store.DeleteLoginAttempts(ctx, DeleteLoginAttemptsCommand{key})
 }
 
 func (s *Service) Validate(ctx context.Context, username string) (bool, error) {
@@ -69,6 +107,12 @@ func (s *Service) Validate(ctx context.Context, username string) (bool, error) {
 		return true, nil
 	}
 
+	username = s.attemptsKey(ctx, username)
+
+	if s.lockoutEnabled() {
+		return s.validateLockout(ctx, username)
+	}
+
 	loginAttemptCountQuery := GetUserLoginAttemptCountQuery{
 		Username: username,
 		Since:    time.Now().Add(-loginAttemptsWindow),
@@ -86,10 +130,55 @@ func (s *Service) Validate(ctx context.Context, username string) (bool, error) {
 	return true, nil
 }
 
+// attemptsKey returns the username the login attempts are stored with: the
+// lower-cased login of the user the username resolves to, so that signing in
+// with the email or another casing of the login counts against the same
+// account, or the lower-cased username when it matches no user.
+func (s *Service) attemptsKey(ctx context.Context, username string) string {
+	if s.userService != nil {
+		usr, err := s.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: username})
+		if err == nil {
+			return strings.ToLower(usr.Login)
+		}
+	}
+	return strings.ToLower(username)
+}
+
+// lockoutEnabled returns true if failed login attempts lock the account
+// instead of throttling it.
+func (s *Service) lockoutEnabled() bool {
+	return s.cfg.LoginLockoutThreshold > 0
+}
+
+func (s *Service) validateLockout(ctx context.Context, username string) (bool, error) {
+	lockout, err := s.store.GetLoginLockout(ctx, GetLoginLockoutQuery{Username: username})
+	if err != nil {
+		return false, err
+	}
+	if lockout == nil {
+		return true, nil
+	}
+
+	lockedAt := time.Unix(lockout.LockedAt, 0)
+	if s.cfg.LoginLockoutDuration > 0 && time.Now().After(lockedAt.Add(s.cfg.LoginLockoutDuration)) {
+		if err := s.unlock(ctx, username); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	return false, loginattempt.ErrAccountLocked.Errorf("account %s locked since %s", username, lockedAt.Format(time.RFC3339))
+}
+
 func (s *Service) cleanup(ctx context.Context) {
+	olderThan := time.Minute * -10
+	if s.lockoutEnabled() {
+		olderThan = -lockoutAttemptsWindow
+	}
+
 	err := s.lock.LockAndExecute(ctx, "delete old login attempts", time.Minute*10, func(context.Context) {
 		cmd := DeleteOldLoginAttemptsCommand{
-			OlderThan: time.Now().Add(time.Minute * -10),
+			OlderThan: time.Now().Add(olderThan),
 		}
 		if deletedLogs, err := s.store.DeleteOldLoginAttempts(ctx, cmd); err != nil {
 			s.logger.Error("Problem deleting expired login attempts", "error", err.Error())
@@ -3,10 +3,15 @@ package loginattemptimpl
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -65,7 +70,7 @@ func TestService_Validate(t *testing.T) {
 			cfg := setting.NewCfg()
 			cfg.DisableBruteForceLoginProtection = tt.disabled
 			service := &Service{
-				store: fakeStore{
+				store: &fakeStore{
 					ExpectedCount: tt.loginAttempts,
 					ExpectedErr:   tt.expectedErr,
 				},
@@ -79,19 +84,94 @@ func TestService_Validate(t *testing.T) {
 	}
 }
 
+func TestService_Lockout(t *testing.T) {
+	newService := func(store *fakeStore, duration time.Duration) *Service {
+		cfg := setting.NewCfg()
+		cfg.LoginLockoutThreshold = 3
+		cfg.LoginLockoutDuration = duration
+		return &Service{store: store, cfg: cfg, logger: log.NewNopLogger()}
+	}
+
+	t.Run("should lock the account when the threshold is reached", func(t *testing.T) {
+		store := &fakeStore{ExpectedCount: 3}
+		require.NoError(t, newService(store, 0).Add(context.Background(), "test", "127.0.0.1"))
+		assert.True(t, store.LockoutCreated)
+	})
+
+	t.Run("should not lock the account below the threshold", func(t *testing.T) {
+		store := &fakeStore{ExpectedCount: 2}
+		require.NoError(t, newService(store, 0).Add(context.Background(), "test", "127.0.0.1"))
+		assert.False(t, store.LockoutCreated)
+	})
+
+	t.Run("should ignore failed attempts without a lockout", func(t *testing.T) {
+		ok, err := newService(&fakeStore{ExpectedCount: 10}, 0).Validate(context.Background(), "test")
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("should return ErrAccountLocked for a locked account", func(t *testing.T) {
+		store := &fakeStore{ExpectedLockout: &loginattempt.LoginLockout{Username: "test", LockedAt: time.Now().Add(-time.Hour).Unix()}}
+		ok, err := newService(store, 0).Validate(context.Background(), "test")
+		assert.ErrorIs(t, err, loginattempt.ErrAccountLocked)
+		assert.False(t, ok)
+	})
+
+	t.Run("should unlock the account once the lockout duration passed", func(t *testing.T) {
+		store := &fakeStore{ExpectedLockout: &loginattempt.LoginLockout{Username: "test", LockedAt: time.Now().Add(-time.Hour).Unix()}}
+		ok, err := newService(store, 30*time.Minute).Validate(context.Background(), "test")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.True(t, store.LockoutDeleted)
+	})
+}
+
+func TestService_AttemptsKey(t *testing.T) {
+	t.Run("should count the attempts of the login, the email and any casing against the user", func(t *testing.T) {
+		userService := usertest.NewUserServiceFake()
+		userService.ExpectedUser = &user.User{ID: 1, Login: "Admin", Email: "admin@example.org"}
+		store := &fakeStore{}
+		service := &Service{store: store, cfg: setting.NewCfg(), logger: log.NewNopLogger(), userService: userService}
+
+		for _, username := range []string{"admin", "ADMIN", "admin@example.org"} {
+			require.NoError(t, service.Add(context.Background(), username, "127.0.0.1"))
+			_, err := service.Validate(context.Background(), username)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, []string{"admin", "admin", "admin", "admin", "admin", "admin"}, store.Usernames)
+	})
+
+	t.Run("should lower-case usernames matching no user", func(t *testing.T) {
+		userService := usertest.NewUserServiceFake()
+		userService.ExpectedError = user.ErrUserNotFound
+		store := &fakeStore{}
+		service := &Service{store: store, cfg: setting.NewCfg(), logger: log.NewNopLogger(), userService: userService}
+
+		require.NoError(t, service.Add(context.Background(), "Unknown", "127.0.0.1"))
+		assert.Equal(t, []string{"unknown"}, store.Usernames)
+	})
+}
+
 var _ store = new(fakeStore)
 
 type fakeStore struct {
 	ExpectedErr         error
 	ExpectedCount       int64
 	ExpectedDeletedRows int64
+	ExpectedLockout     *loginattempt.LoginLockout
+
+	LockoutCreated bool
+	LockoutDeleted bool
+	Usernames      []string
 }
 
-func (f fakeStore) GetUserLoginAttemptCount(ctx context.Context, query GetUserLoginAttemptCountQuery) (int64, error) {
+func (f *fakeStore) GetUserLoginAttemptCount(ctx context.Context, query GetUserLoginAttemptCountQuery) (int64, error) {
+	f.Usernames = append(f.Usernames, query.Username)
 	return f.ExpectedCount, f.ExpectedErr
 }
 
-func (f fakeStore) CreateLoginAttempt(ctx context.Context, command CreateLoginAttemptCommand) (loginattempt.LoginAttempt, error) {
+func (f *fakeStore) CreateLoginAttempt(ctx context.Context, command CreateLoginAttemptCommand) (loginattempt.LoginAttempt, error) {
+	f.Usernames = append(f.Usernames, command.Username)
 	return loginattempt.LoginAttempt{}, f.ExpectedErr
 }
 
@@ -102,3 +182,17 @@ func (f fakeStore) DeleteOldLoginAttempts(ctx context.Context, command DeleteOld
 func (f fakeStore) DeleteLoginAttempts(ctx context.Context, cmd DeleteLoginAttemptsCommand) error {
 	return f.ExpectedErr
 }
+
+func (f fakeStore) GetLoginLockout(ctx context.Context, query GetLoginLockoutQuery) (*loginattempt.LoginLockout, error) {
+	return f.ExpectedLockout, f.ExpectedErr
+}
+
+func (f *fakeStore) CreateLoginLockout(ctx context.Context, cmd CreateLoginLockoutCommand) error {
+	f.LockoutCreated = true
+	return f.ExpectedErr
+}
+
+func (f *fakeStore) DeleteLoginLockout(ctx context.Context, cmd DeleteLoginLockoutCommand) error {
+	f.LockoutDeleted = true
+	return f.ExpectedErr
+}
@@ -21,3 +21,15 @@ type DeleteOldLoginAttemptsCommand struct {
 type DeleteLoginAttemptsCommand struct {
 	Username string
 }
+
+type GetLoginLockoutQuery struct {
+	Username string
+}
+
+type CreateLoginLockoutCommand struct {
+	Username string
+}
+
+type DeleteLoginLockoutCommand struct {
+	Username string
+}
@@ -18,6 +18,9 @@ type store interface {
 	DeleteOldLoginAttempts(ctx context.Context, cmd DeleteOldLoginAttemptsCommand) (int64, error)
 	DeleteLoginAttempts(ctx context.Context, cmd DeleteLoginAttemptsCommand) error
 	GetUserLoginAttemptCount(ctx context.Context, query GetUserLoginAttemptCountQuery) (int64, error)
+	GetLoginLockout(ctx context.Context, query GetLoginLockoutQuery) (*loginattempt.LoginLockout, error)
+	CreateLoginLockout(ctx context.Context, cmd CreateLoginLockoutCommand) error
+	DeleteLoginLockout(ctx context.Context, cmd DeleteLoginLockoutCommand) error
 }
 
 func (xs *xormStore) CreateLoginAttempt(ctx context.Context, cmd CreateLoginAttemptCommand) (result loginattempt.LoginAttempt, err error) {
@@ -82,3 +85,39 @@ func (xs *xormStore) GetUserLoginAttemptCount(ctx context.Context, query GetUser
 
 	return total, err
 }
+
+// GetLoginLockout returns the lockout of the username, or nil if it is not locked.
+func (xs *xormStore) GetLoginLockout(ctx context.Context, query GetLoginLockoutQuery) (*loginattempt.LoginLockout, error) {
+	var lockout *loginattempt.LoginLockout
+	err := xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var result loginattempt.LoginLockout
+		has, err := sess.Where("username = ?", query.Username).Get(&result)
+		if err != nil {
+			return err
+		}
+		if has {
+			lockout = &result
+		}
+		return nil
+	})
+	return lockout, err
+}
+
+func (xs *xormStore) CreateLoginLockout(ctx context.Context, cmd CreateLoginLockoutCommand) error {
+	return xs.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("username = ?", cmd.Username).Exist(&loginattempt.LoginLockout{})
+		if err != nil || has {
+			return err
+		}
+
+		_, err = sess.Insert(&loginattempt.LoginLockout{Username: cmd.Username, LockedAt: xs.now().Unix()})
+		return err
+	})
+}
+
+func (xs *xormStore) DeleteLoginLockout(ctx context.Context, cmd DeleteLoginLockoutCommand) error {
+	return xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM login_lockout WHERE username = ?", cmd.Username)
+		return err
+	})
+}
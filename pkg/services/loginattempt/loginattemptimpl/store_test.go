@@ -143,3 +143,34 @@ func TestIntegrationLoginAttemptsDelete(t *testing.T) {
 		require.Equal(t, test.DeletedRows, deletedRows, test.Name)
 	}
 }
+
+func TestIntegrationLoginLockout(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	lockedAt := time.Date(2017, 10, 22, 8, 0, 0, 0, time.Local)
+	s := &xormStore{
+		db:  db.InitTestDB(t),
+		now: func() time.Time { return lockedAt },
+	}
+	ctx := context.Background()
+
+	lockout, err := s.GetLoginLockout(ctx, GetLoginLockoutQuery{Username: "user"})
+	require.NoError(t, err)
+	require.Nil(t, lockout)
+
+	require.NoError(t, s.CreateLoginLockout(ctx, CreateLoginLockoutCommand{Username: "user"}))
+	// locking an already locked account keeps the original lock time
+	s.now = func() time.Time { return lockedAt.Add(time.Hour) }
+	require.NoError(t, s.CreateLoginLockout(ctx, CreateLoginLockoutCommand{Username: "user"}))
+
+	lockout, err = s.GetLoginLockout(ctx, GetLoginLockoutQuery{Username: "user"})
+	require.NoError(t, err)
+	require.NotNil(t, lockout)
+	require.Equal(t, lockedAt.Unix(), lockout.LockedAt)
+
+	require.NoError(t, s.DeleteLoginLockout(ctx, DeleteLoginLockoutCommand{Username: "user"}))
+	lockout, err = s.GetLoginLockout(ctx, GetLoginLockoutQuery{Username: "user"})
+	require.NoError(t, err)
+	require.Nil(t, lockout)
+}
@@ -12,6 +12,7 @@ type MockLoginAttemptService struct {
 	AddCalled      bool
 	ResetCalled    bool
 	ValidateCalled bool
+	UnlockCalled   bool
 
 	ExpectedValid bool
 	ExpectedErr   error
@@ -31,3 +32,8 @@ func (f *MockLoginAttemptService) Validate(ctx context.Context, username string)
 	f.ValidateCalled = true
 	return f.ExpectedValid, f.ExpectedErr
 }
+
+func (f *MockLoginAttemptService) Unlock(ctx context.Context, username string) error {
+	f.UnlockCalled = true
+	return f.ExpectedErr
+}
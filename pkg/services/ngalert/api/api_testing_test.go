@@ -55,7 +55,27 @@ func TestRouteTestGrafanaRuleConfig(t *testing.T) {
 			require.Equal(t, http.StatusUnauthorized, response.Status())
 		})
 
-		t.Run("should return 200 if user can query all data sources", func(t *testing.T) {
+		t.Run("should return 401 if user cannot use a data source in alert rules", func(t *testing.T) {
+			data1 := models.GenerateAlertQuery()
+
+			ac := acMock.New().WithPermissions([]accesscontrol.Permission{
+				{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID(data1.DatasourceUID)},
+			})
+			evaluator := &eval_mocks.ConditionEvaluatorMock{}
+			srv := createTestingApiSrv(nil, ac, eval_mocks.NewEvaluatorFactory(evaluator))
+
+			response := srv.RouteTestGrafanaRuleConfig(rc, definitions.TestRulePayload{
+				GrafanaManagedCondition: &definitions.EvalAlertConditionCommand{
+					Condition: data1.RefID,
+					Data:      ApiAlertQueriesFromAlertQueries([]models.AlertQuery{data1}),
+				},
+			})
+
+			require.Equal(t, http.StatusUnauthorized, response.Status())
+			evaluator.AssertNotCalled(t, "Evaluate", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should return 200 if user can query all data sources and use them in alert rules", func(t *testing.T) {
 			data1 := models.GenerateAlertQuery()
 			data2 := models.GenerateAlertQuery()
 
@@ -64,6 +84,8 @@ func TestRouteTestGrafanaRuleConfig(t *testing.T) {
 			ac := acMock.New().WithPermissions([]accesscontrol.Permission{
 				{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID(data1.DatasourceUID)},
 				{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID(data2.DatasourceUID)},
+				{Action: datasources.ActionAlert, Scope: datasources.ScopeProvider.GetResourceScopeUID(data1.DatasourceUID)},
+				{Action: datasources.ActionAlert, Scope: datasources.ScopeProvider.GetResourceScopeUID(data2.DatasourceUID)},
 			})
 
 			ds := &fakes.FakeCacheService{DataSources: []*datasources.DataSource{
@@ -181,7 +203,24 @@ func TestRouteEvalQueries(t *testing.T) {
 			require.Equal(t, http.StatusUnauthorized, response.Status())
 		})
 
-		t.Run("should return 200 if user can query all data sources", func(t *testing.T) {
+		t.Run("should return 401 if user cannot use a data source in alert rules", func(t *testing.T) {
+			data1 := models.GenerateAlertQuery()
+
+			ac := acMock.New().WithPermissions([]accesscontrol.Permission{
+				{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID(data1.DatasourceUID)},
+			})
+			evaluator := &eval_mocks.ConditionEvaluatorMock{}
+			srv := createTestingApiSrv(nil, ac, eval_mocks.NewEvaluatorFactory(evaluator))
+
+			response := srv.RouteEvalQueries(rc, definitions.EvalQueriesPayload{
+				Data: ApiAlertQueriesFromAlertQueries([]models.AlertQuery{data1}),
+			})
+
+			require.Equal(t, http.StatusUnauthorized, response.Status())
+			evaluator.AssertNotCalled(t, "EvaluateRaw", mock.Anything, mock.Anything)
+		})
+
+		t.Run("should return 200 if user can query all data sources and use them in alert rules", func(t *testing.T) {
 			data1 := models.GenerateAlertQuery()
 			data2 := models.GenerateAlertQuery()
 
@@ -190,6 +229,8 @@ func TestRouteEvalQueries(t *testing.T) {
 			ac := acMock.New().WithPermissions([]accesscontrol.Permission{
 				{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID(data1.DatasourceUID)},
 				{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID(data2.DatasourceUID)},
+				{Action: datasources.ActionAlert, Scope: datasources.ScopeProvider.GetResourceScopeUID(data1.DatasourceUID)},
+				{Action: datasources.ActionAlert, Scope: datasources.ScopeProvider.GetResourceScopeUID(data2.DatasourceUID)},
 			})
 
 			ds := &fakes.FakeCacheService{DataSources: []*datasources.DataSource{
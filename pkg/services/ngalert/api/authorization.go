@@ -255,6 +255,19 @@ func authorizeDatasourceAccessForRule(rule *ngmodels.AlertRule, evaluator func(e
 	return true
 }
 
+// authorizeDatasourceAlertingForRule checks that user is allowed to use all data sources declared by the rule in alert rules
+func authorizeDatasourceAlertingForRule(rule *ngmodels.AlertRule, evaluator func(evaluator ac.Evaluator) bool) bool {
+	for _, query := range rule.Data {
+		if query.QueryType == expr.DatasourceType || query.DatasourceUID == expr.DatasourceUID || query.DatasourceUID == expr.OldDatasourceUID {
+			continue
+		}
+		if !evaluator(ac.EvalPermission(datasources.ActionAlert, datasources.ScopeProvider.GetResourceScopeUID(query.DatasourceUID))) {
+			return false
+		}
+	}
+	return true
+}
+
 // authorizeAccessToRuleGroup checks all rules against authorizeDatasourceAccessForRule and exits on the first negative result
 func authorizeAccessToRuleGroup(rules []*ngmodels.AlertRule, evaluator func(evaluator ac.Evaluator) bool) bool {
 	for _, rule := range rules {
@@ -306,6 +319,9 @@ func authorizeRuleChanges(change *store.GroupDelta, evaluator func(evaluator ac.
 			if !dsAllowed {
 				return fmt.Errorf("%w to create a new alert rule '%s' because the user does not have read permissions for one or many datasources the rule uses", ErrAuthorization, rule.Title)
 			}
+			if !authorizeDatasourceAlertingForRule(rule, evaluator) {
+				return fmt.Errorf("%w to create a new alert rule '%s' because the user is not allowed to use one or many datasources the rule uses in alert rules", ErrAuthorization, rule.Title)
+			}
 		}
 	}
 
@@ -314,6 +330,9 @@ func authorizeRuleChanges(change *store.GroupDelta, evaluator func(evaluator ac.
 		if !dsAllowed {
 			return fmt.Errorf("%w to update alert rule '%s' (UID: %s) because the user does not have read permissions for one or many datasources the rule uses", ErrAuthorization, rule.Existing.Title, rule.Existing.UID)
 		}
+		if !authorizeDatasourceAlertingForRule(rule.New, evaluator) {
+			return fmt.Errorf("%w to update alert rule '%s' (UID: %s) because the user is not allowed to use one or many datasources the rule uses in alert rules", ErrAuthorization, rule.Existing.Title, rule.Existing.UID)
+		}
 
 		// Check if the rule is moved from one folder to the current. If yes, then the user must have the authorization to delete rules from the source folder and add rules to the target folder.
 		if rule.Existing.NamespaceUID != rule.New.NamespaceUID {
@@ -142,7 +142,7 @@ func TestAuthorizeRuleChanges(t *testing.T) {
 		permissions func(c *store.GroupDelta) map[string][]string
 	}{
 		{
-			name: "if there are rules to add it should check create action and query for datasource",
+			name: "if there are rules to add it should check create action and query and alert for datasource",
 			changes: func() *store.GroupDelta {
 				return &store.GroupDelta{
 					GroupKey: groupKey,
@@ -163,6 +163,7 @@ func TestAuthorizeRuleChanges(t *testing.T) {
 						namespaceIdScope,
 					},
 					datasources.ActionQuery: scopes,
+					datasources.ActionAlert: scopes,
 				}
 			},
 		},
@@ -226,6 +227,9 @@ func TestAuthorizeRuleChanges(t *testing.T) {
 						namespaceIdScope,
 					},
 					datasources.ActionQuery: scopes,
+					datasources.ActionAlert: getDatasourceScopesForRules(mapUpdates(c.Update, func(update store.RuleDelta) *models.AlertRule {
+						return update.New
+					})),
 				}
 			},
 		},
@@ -283,6 +287,9 @@ func TestAuthorizeRuleChanges(t *testing.T) {
 						dashboards.ScopeFoldersProvider.GetResourceScopeUID(c.GroupKey.NamespaceUID),
 					},
 					datasources.ActionQuery: dsScopes,
+					datasources.ActionAlert: getDatasourceScopesForRules(mapUpdates(c.Update, func(update store.RuleDelta) *models.AlertRule {
+						return update.New
+					})),
 				}
 			},
 		},
@@ -355,6 +362,9 @@ func TestAuthorizeRuleChanges(t *testing.T) {
 						dashboards.ScopeFoldersProvider.GetResourceScopeUID(c.GroupKey.NamespaceUID),
 					},
 					datasources.ActionQuery: dsScopes,
+					datasources.ActionAlert: getDatasourceScopesForRules(mapUpdates(c.Update, func(update store.RuleDelta) *models.AlertRule {
+						return update.New
+					})),
 				}
 			},
 		},
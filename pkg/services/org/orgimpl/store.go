@@ -794,6 +794,9 @@ func (ss *sqlStore) userDeletions() []string {
 		"DELETE FROM user_auth WHERE user_id = ?",
 		"DELETE FROM user_auth_token WHERE user_id = ?",
 		"DELETE FROM quota WHERE user_id = ?",
+		"DELETE FROM user_two_factor WHERE user_id = ?",
+		"DELETE FROM user_two_factor_recovery_code WHERE user_id = ?",
+		"DELETE FROM user_password_history WHERE user_id = ?",
 	}
 	return deletes
 }
@@ -0,0 +1,240 @@
+package accesscontrol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// Provision scans a directory for provisioning config files
+// and provisions the custom roles, role assignments and team groups in those files.
+func Provision(ctx context.Context, configDirectory string, roleService accesscontrol.RoleService, userService user.Service, teamService team.Service, serviceAccountsService serviceaccounts.Service, teamSyncService teamsync.Service) error {
+	logger := log.New("provisioning.accesscontrol")
+	p := RoleProvisioner{
+		log:                    logger,
+		cfgProvider:            &configReader{log: logger},
+		roleService:            roleService,
+		userService:            userService,
+		teamService:            teamService,
+		serviceAccountsService: serviceAccountsService,
+		teamSyncService:        teamSyncService,
+	}
+	return p.applyChanges(ctx, configDirectory)
+}
+
+// RoleProvisioner is responsible for provisioning custom roles and their
+// assignments based on configuration read by the `configReader`
+type RoleProvisioner struct {
+	log                    log.Logger
+	cfgProvider            *configReader
+	roleService            accesscontrol.RoleService
+	userService            user.Service
+	teamService            team.Service
+	serviceAccountsService serviceaccounts.Service
+	teamSyncService        teamsync.Service
+}
+
+func (p *RoleProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := p.cfgProvider.readConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	// roles of every file are provisioned before assignments so that files can refer to each other's roles
+	for _, cfg := range configs {
+		for _, role := range cfg.Roles {
+			if err := p.applyRole(ctx, role); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, cfg := range configs {
+		if err := p.applyAssignments(ctx, cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *RoleProvisioner) applyRole(ctx context.Context, cfg *roleFromConfig) error {
+	existing, err := p.findRole(ctx, &roleRefFromConfig{OrgID: cfg.OrgID, Global: cfg.Global, UID: cfg.UID, Name: cfg.Name})
+	if err != nil && !errors.Is(err, accesscontrol.ErrRoleNotFound) {
+		return err
+	}
+
+	if cfg.Absent {
+		if existing == nil {
+			return nil
+		}
+		p.log.Info("Deleting role from configuration", "name", existing.Name, "uid", existing.UID)
+		return p.roleService.DeleteRole(ctx, accesscontrol.DeleteRoleCommand{OrgID: existing.OrgID, UID: existing.UID})
+	}
+
+	if existing == nil {
+		p.log.Info("Inserting role from configuration", "name", cfg.Name, "uid", cfg.UID)
+		_, err := p.roleService.CreateRole(ctx, accesscontrol.CreateRoleCommand{
+			OrgID:       cfg.OrgID,
+			Global:      cfg.Global,
+			UID:         cfg.UID,
+			Name:        cfg.Name,
+			DisplayName: cfg.DisplayName,
+			Description: cfg.Description,
+			Group:       cfg.Group,
+			Hidden:      cfg.Hidden,
+			Permissions: cfg.Permissions,
+		})
+		return err
+	}
+
+	if !roleChanged(existing, cfg) {
+		return nil
+	}
+
+	p.log.Info("Updating role from configuration", "name", cfg.Name, "uid", existing.UID)
+	_, err = p.roleService.UpdateRole(ctx, accesscontrol.UpdateRoleCommand{
+		OrgID:       cfg.OrgID,
+		UID:         existing.UID,
+		Name:        cfg.Name,
+		DisplayName: cfg.DisplayName,
+		Description: cfg.Description,
+		Group:       cfg.Group,
+		Hidden:      cfg.Hidden,
+		Permissions: cfg.Permissions,
+	})
+	return err
+}
+
+func (p *RoleProvisioner) applyAssignments(ctx context.Context, cfg *configs) error {
+	for _, t := range cfg.Teams {
+		teamID, err := p.findTeam(ctx, t.OrgID, t.Name)
+		if err != nil {
+			return err
+		}
+		if err := p.assign(ctx, t, accesscontrol.RoleAssignmentCommand{TeamID: teamID}); err != nil {
+			return err
+		}
+		if t.Groups != nil {
+			if err := p.teamSyncService.SetGroups(ctx, &teamsync.SetTeamGroupsCommand{OrgID: t.OrgID, TeamID: teamID, GroupIDs: t.Groups}); err != nil {
+				return fmt.Errorf("failed to set groups of team %s: %w", t.Name, err)
+			}
+		}
+	}
+
+	for _, u := range cfg.Users {
+		usr, err := p.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: u.Name})
+		if err != nil {
+			return fmt.Errorf("failed to find user %s: %w", u.Name, err)
+		}
+		if err := p.assign(ctx, u, accesscontrol.RoleAssignmentCommand{UserID: usr.ID}); err != nil {
+			return err
+		}
+	}
+
+	for _, sa := range cfg.ServiceAccounts {
+		saID, err := p.serviceAccountsService.RetrieveServiceAccountIdByName(ctx, sa.OrgID, sa.Name)
+		if err != nil {
+			return fmt.Errorf("failed to find service account %s: %w", sa.Name, err)
+		}
+		if err := p.assign(ctx, sa, accesscontrol.RoleAssignmentCommand{UserID: saID}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// assign adds and removes the roles listed in the configuration, assignments already in place are left untouched
+func (p *RoleProvisioner) assign(ctx context.Context, cfg *assignmentFromConfig, cmd accesscontrol.RoleAssignmentCommand) error {
+	cmd.OrgID = cfg.OrgID
+	for _, ref := range cfg.Roles {
+		role, err := p.findRole(ctx, ref)
+		if err != nil {
+			if ref.Absent && errors.Is(err, accesscontrol.ErrRoleNotFound) {
+				continue
+			}
+			return fmt.Errorf("failed to find role %s%s for %s: %w", ref.UID, ref.Name, cfg.Name, err)
+		}
+		cmd.RoleUID = role.UID
+
+		if ref.Absent {
+			err = p.roleService.RemoveRoleAssignment(ctx, cmd)
+			if errors.Is(err, accesscontrol.ErrRoleNotFound) {
+				err = nil
+			}
+		} else {
+			err = p.roleService.AddRoleAssignment(ctx, cmd)
+			if errors.Is(err, accesscontrol.ErrRoleAlreadyAssigned) {
+				err = nil
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to change assignment of role %s for %s: %w", role.Name, cfg.Name, err)
+		}
+	}
+	return nil
+}
+
+// findRole looks up a role by uid or, when no uid is configured, by name
+func (p *RoleProvisioner) findRole(ctx context.Context, ref *roleRefFromConfig) (*accesscontrol.RoleDTO, error) {
+	if ref.UID != "" {
+		return p.roleService.GetRole(ctx, accesscontrol.GetRoleQuery{OrgID: ref.OrgID, UID: ref.UID})
+	}
+
+	roles, err := p.roleService.SearchRoles(ctx, accesscontrol.SearchRolesQuery{OrgID: ref.OrgID, Query: ref.Name})
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		if role.Name == ref.Name && role.Global() == ref.Global {
+			return role, nil
+		}
+	}
+	return nil, accesscontrol.ErrRoleNotFound.Errorf("role %s not found", ref.Name)
+}
+
+func (p *RoleProvisioner) findTeam(ctx context.Context, orgID int64, name string) (int64, error) {
+	result, err := p.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{
+		OrgID:        orgID,
+		Name:         name,
+		Limit:        1,
+		UserIDFilter: team.FilterIgnoreUser,
+		SignedInUser: &user.SignedInUser{
+			OrgID:       orgID,
+			Permissions: map[int64]map[string][]string{orgID: {accesscontrol.ActionTeamsRead: {accesscontrol.ScopeTeamsAll}}},
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find team %s: %w", name, err)
+	}
+	if len(result.Teams) == 0 {
+		return 0, fmt.Errorf("team %s not found in organization %d", name, orgID)
+	}
+	return result.Teams[0].ID, nil
+}
+
+func roleChanged(existing *accesscontrol.RoleDTO, cfg *roleFromConfig) bool {
+	if existing.Name != cfg.Name || existing.DisplayName != cfg.DisplayName || existing.Description != cfg.Description ||
+		existing.Group != cfg.Group || existing.Hidden != cfg.Hidden {
+		return true
+	}
+
+	current := make(map[accesscontrol.Permission]bool, len(existing.Permissions))
+	for _, p := range existing.Permissions {
+		current[p.OSSPermission()] = true
+	}
+	wanted := make(map[accesscontrol.Permission]bool, len(cfg.Permissions))
+	for _, p := range cfg.Permissions {
+		wanted[p.OSSPermission()] = true
+	}
+	return !reflect.DeepEqual(current, wanted)
+}
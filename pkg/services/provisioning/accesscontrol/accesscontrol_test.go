@@ -0,0 +1,185 @@
+package accesscontrol
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/teamsync/teamsynctest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+)
+
+func TestProvision(t *testing.T) {
+	ctx := context.Background()
+
+	roles := newFakeRoleService()
+	roles.roles["legacy"] = &accesscontrol.RoleDTO{OrgID: 1, UID: "legacy", Name: "custom:legacy"}
+	roles.assignments["user-1-legacy"] = true
+
+	teamSync := teamsynctest.NewFakeService()
+	provision := func() error {
+		return Provision(ctx, rolesConfig, roles,
+			&usertest.FakeUserService{ExpectedUser: &user.User{ID: 1, Login: "admin"}},
+			&fakeTeamService{teams: map[string]int64{"editors": 2, "viewers": 4}},
+			&fakeServiceAccountsService{ids: map[string]int64{"ci": 3}},
+			teamSync,
+		)
+	}
+
+	require.NoError(t, provision())
+
+	require.Len(t, roles.roles, 2)
+	require.NotContains(t, roles.roles, "legacy")
+	require.Len(t, roles.roles["annotations-writer"].Permissions, 2)
+	require.Equal(t, map[string]bool{
+		"team-2-annotations-writer":                             true,
+		"user-3-annotations-writer":                             true,
+		"user-1-" + roles.findByName("custom:stats:reader").UID: true,
+	}, roles.assignments)
+	require.Equal(t, map[int64][]string{2: {"cn=editors,ou=groups,dc=grafana,dc=org", "platform/editors"}}, teamSync.SetGroupsByTeam)
+
+	t.Run("should not update unchanged roles", func(t *testing.T) {
+		require.NoError(t, provision())
+		require.Equal(t, 0, roles.updates)
+	})
+
+	t.Run("should update changed roles", func(t *testing.T) {
+		roles.roles["annotations-writer"].Permissions = roles.roles["annotations-writer"].Permissions[:1]
+		require.NoError(t, provision())
+		require.Equal(t, 1, roles.updates)
+		require.Len(t, roles.roles["annotations-writer"].Permissions, 2)
+	})
+
+	t.Run("should fail for unknown team", func(t *testing.T) {
+		err := Provision(ctx, rolesConfig, roles, usertest.NewUserServiceFake(), &fakeTeamService{}, &fakeServiceAccountsService{}, teamSync)
+		require.ErrorContains(t, err, "team editors not found")
+	})
+}
+
+type fakeRoleService struct {
+	roles       map[string]*accesscontrol.RoleDTO
+	assignments map[string]bool
+	updates     int
+}
+
+var _ accesscontrol.RoleService = &fakeRoleService{}
+
+func newFakeRoleService() *fakeRoleService {
+	return &fakeRoleService{roles: map[string]*accesscontrol.RoleDTO{}, assignments: map[string]bool{}}
+}
+
+func (f *fakeRoleService) CreateRole(ctx context.Context, cmd accesscontrol.CreateRoleCommand) (*accesscontrol.RoleDTO, error) {
+	if cmd.UID == "" {
+		cmd.UID = strings.ReplaceAll(cmd.Name, ":", "-")
+	}
+	orgID := cmd.OrgID
+	if cmd.Global {
+		orgID = accesscontrol.GlobalOrgID
+	}
+	role := &accesscontrol.RoleDTO{OrgID: orgID, UID: cmd.UID, Name: cmd.Name, DisplayName: cmd.DisplayName, Description: cmd.Description, Permissions: cmd.Permissions}
+	f.roles[cmd.UID] = role
+	return role, nil
+}
+
+func (f *fakeRoleService) UpdateRole(ctx context.Context, cmd accesscontrol.UpdateRoleCommand) (*accesscontrol.RoleDTO, error) {
+	f.updates++
+	role := f.roles[cmd.UID]
+	role.Name, role.DisplayName, role.Description, role.Permissions = cmd.Name, cmd.DisplayName, cmd.Description, cmd.Permissions
+	return role, nil
+}
+
+func (f *fakeRoleService) DeleteRole(ctx context.Context, cmd accesscontrol.DeleteRoleCommand) error {
+	delete(f.roles, cmd.UID)
+	for key := range f.assignments {
+		if strings.HasSuffix(key, "-"+cmd.UID) {
+			delete(f.assignments, key)
+		}
+	}
+	return nil
+}
+
+func (f *fakeRoleService) GetRole(ctx context.Context, query accesscontrol.GetRoleQuery) (*accesscontrol.RoleDTO, error) {
+	role, ok := f.roles[query.UID]
+	if !ok {
+		return nil, accesscontrol.ErrRoleNotFound.Errorf("not found")
+	}
+	return role, nil
+}
+
+func (f *fakeRoleService) SearchRoles(ctx context.Context, query accesscontrol.SearchRolesQuery) ([]*accesscontrol.RoleDTO, error) {
+	var roles []*accesscontrol.RoleDTO
+	for _, role := range f.roles {
+		if strings.Contains(role.Name, query.Query) {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+func (f *fakeRoleService) GetUserRoles(ctx context.Context, orgID, userID int64) ([]*accesscontrol.RoleDTO, error) {
+	return nil, nil
+}
+
+func (f *fakeRoleService) GetTeamRoles(ctx context.Context, orgID, teamID int64) ([]*accesscontrol.RoleDTO, error) {
+	return nil, nil
+}
+
+func (f *fakeRoleService) AddRoleAssignment(ctx context.Context, cmd accesscontrol.RoleAssignmentCommand) error {
+	key := assignmentKey(cmd)
+	if f.assignments[key] {
+		return accesscontrol.ErrRoleAlreadyAssigned.Errorf("already assigned")
+	}
+	f.assignments[key] = true
+	return nil
+}
+
+func (f *fakeRoleService) RemoveRoleAssignment(ctx context.Context, cmd accesscontrol.RoleAssignmentCommand) error {
+	delete(f.assignments, assignmentKey(cmd))
+	return nil
+}
+
+func (f *fakeRoleService) findByName(name string) *accesscontrol.RoleDTO {
+	for _, role := range f.roles {
+		if role.Name == name {
+			return role
+		}
+	}
+	return nil
+}
+
+func assignmentKey(cmd accesscontrol.RoleAssignmentCommand) string {
+	if cmd.TeamID != 0 {
+		return fmt.Sprintf("team-%d-%s", cmd.TeamID, cmd.RoleUID)
+	}
+	return fmt.Sprintf("user-%d-%s", cmd.UserID, cmd.RoleUID)
+}
+
+type fakeTeamService struct {
+	teamtest.FakeService
+	teams map[string]int64
+}
+
+func (f *fakeTeamService) SearchTeams(ctx context.Context, query *team.SearchTeamsQuery) (team.SearchTeamQueryResult, error) {
+	result := team.SearchTeamQueryResult{}
+	if id, ok := f.teams[query.Name]; ok {
+		result.Teams = append(result.Teams, &team.TeamDTO{ID: id, OrgID: query.OrgID, Name: query.Name})
+	}
+	return result, nil
+}
+
+type fakeServiceAccountsService struct {
+	serviceaccounts.Service
+	ids map[string]int64
+}
+
+func (f *fakeServiceAccountsService) RetrieveServiceAccountIdByName(ctx context.Context, orgID int64, name string) (int64, error) {
+	return f.ids[name], nil
+}
@@ -0,0 +1,133 @@
+package accesscontrol
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const supportedAPIVersion = 2
+
+type configReader struct {
+	log log.Logger
+}
+
+func (cr *configReader) readConfig(path string) ([]*configs, error) {
+	var cfgs []*configs
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("can't read access control provisioning files from directory", "path", path, "error", err)
+		return cfgs, nil
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+
+		cfg, err := cr.parseConfig(filepath.Join(path, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name(), err)
+		}
+		if cfg != nil {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+
+	for _, cfg := range cfgs {
+		if err := validateConfig(cfg); err != nil {
+			return nil, err
+		}
+		applyDefaultOrgIDs(cfg)
+	}
+
+	return cfgs, nil
+}
+
+func (cr *configReader) parseConfig(path string) (*configs, error) {
+	filename, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiVersion *configVersion
+	if err := yaml.Unmarshal(yamlFile, &apiVersion); err != nil {
+		return nil, err
+	}
+
+	// files with only comments, like the sample file, have nothing to provision
+	if apiVersion == nil {
+		return nil, nil
+	}
+	if apiVersion.APIVersion != supportedAPIVersion {
+		return nil, fmt.Errorf("unsupported apiVersion %d, expected %d", apiVersion.APIVersion, supportedAPIVersion)
+	}
+
+	var v2 *configsV2
+	if err := yaml.Unmarshal(yamlFile, &v2); err != nil {
+		return nil, err
+	}
+	return v2.mapToAccessControlFromConfig(), nil
+}
+
+func validateConfig(cfg *configs) error {
+	for i, role := range cfg.Roles {
+		if role.Name == "" && (role.UID == "" || !role.Absent) {
+			return fmt.Errorf("role item %d in configuration doesn't contain required field name", i+1)
+		}
+	}
+
+	kinds := map[string][]*assignmentFromConfig{"team": cfg.Teams, "user": cfg.Users, "service account": cfg.ServiceAccounts}
+	for kind, assignments := range kinds {
+		for i, a := range assignments {
+			if a.Name == "" {
+				return fmt.Errorf("%s item %d in configuration doesn't contain a name or login", kind, i+1)
+			}
+			for _, role := range a.Roles {
+				if role.UID == "" && role.Name == "" {
+					return fmt.Errorf("role of %s %s in configuration doesn't contain a uid or a name", kind, a.Name)
+				}
+			}
+			for _, group := range a.Groups {
+				if group == "" {
+					return fmt.Errorf("%s %s in configuration contains an empty group", kind, a.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// applyDefaultOrgIDs places roles and assignments without organization in the main organization
+func applyDefaultOrgIDs(cfg *configs) {
+	for _, role := range cfg.Roles {
+		if role.OrgID < 1 {
+			role.OrgID = 1
+		}
+	}
+	for _, assignments := range [][]*assignmentFromConfig{cfg.Teams, cfg.Users, cfg.ServiceAccounts} {
+		for _, a := range assignments {
+			if a.OrgID < 1 {
+				a.OrgID = 1
+			}
+			for _, role := range a.Roles {
+				if role.OrgID < 1 {
+					role.OrgID = a.OrgID
+				}
+			}
+		}
+	}
+}
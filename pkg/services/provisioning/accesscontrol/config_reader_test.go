@@ -0,0 +1,72 @@
+package accesscontrol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+const (
+	rolesConfig        = "testdata/roles"
+	brokenYaml         = "testdata/broken-yaml"
+	unsupportedVersion = "testdata/unsupported-version"
+	noName             = "testdata/no-name"
+	emptyFolder        = "testdata/empty"
+)
+
+func TestConfigReader(t *testing.T) {
+	reader := &configReader{log: log.New("test logger")}
+
+	t.Run("Broken yaml should return error", func(t *testing.T) {
+		_, err := reader.readConfig(brokenYaml)
+		require.Error(t, err)
+	})
+
+	t.Run("Unsupported version should return error", func(t *testing.T) {
+		_, err := reader.readConfig(unsupportedVersion)
+		require.ErrorContains(t, err, "unsupported apiVersion 1")
+	})
+
+	t.Run("Role without name should return error", func(t *testing.T) {
+		_, err := reader.readConfig(noName)
+		require.ErrorContains(t, err, "role item 1 in configuration doesn't contain required field name")
+	})
+
+	t.Run("Empty folder should return no config", func(t *testing.T) {
+		cfgs, err := reader.readConfig(emptyFolder)
+		require.NoError(t, err)
+		require.Empty(t, cfgs)
+	})
+
+	t.Run("Can read correct properties", func(t *testing.T) {
+		cfgs, err := reader.readConfig(rolesConfig)
+		require.NoError(t, err)
+		require.Len(t, cfgs, 1)
+
+		cfg := cfgs[0]
+		require.Len(t, cfg.Roles, 3)
+		writer := cfg.Roles[0]
+		require.Equal(t, int64(1), writer.OrgID)
+		require.Equal(t, "annotations-writer", writer.UID)
+		require.Equal(t, "Annotation writer", writer.DisplayName)
+		require.Equal(t, []accesscontrol.Permission{
+			{Action: "annotations:write", Scope: "folders:uid:x"},
+			{Action: "annotations:create", Scope: "folders:uid:x"},
+		}, writer.Permissions)
+		require.True(t, cfg.Roles[1].Global)
+		require.True(t, cfg.Roles[2].Absent)
+
+		require.Len(t, cfg.Teams, 2)
+		require.Equal(t, "editors", cfg.Teams[0].Name)
+		require.Equal(t, int64(1), cfg.Teams[0].Roles[0].OrgID)
+		require.Equal(t, []string{"cn=editors,ou=groups,dc=grafana,dc=org", "platform/editors"}, cfg.Teams[0].Groups)
+		require.Nil(t, cfg.Teams[1].Groups)
+		require.Len(t, cfg.Users, 1)
+		require.Equal(t, "admin", cfg.Users[0].Name)
+		require.True(t, cfg.Users[0].Roles[1].Absent)
+		require.Len(t, cfg.ServiceAccounts, 1)
+	})
+}
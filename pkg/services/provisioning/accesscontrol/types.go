@@ -0,0 +1,170 @@
+package accesscontrol
+
+import (
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+const stateAbsent = "absent"
+
+// configVersion is used to figure out which API version a config uses.
+type configVersion struct {
+	APIVersion int64 `json:"apiVersion" yaml:"apiVersion"`
+}
+
+// configs is a normalized data object for access control config data. Any config version should be mappable
+// to this type.
+type configs struct {
+	Roles           []*roleFromConfig
+	Teams           []*assignmentFromConfig
+	Users           []*assignmentFromConfig
+	ServiceAccounts []*assignmentFromConfig
+}
+
+type roleFromConfig struct {
+	OrgID       int64
+	Global      bool
+	UID         string
+	Name        string
+	DisplayName string
+	Description string
+	Group       string
+	Hidden      bool
+	Permissions []accesscontrol.Permission
+	Absent      bool
+}
+
+// assignmentFromConfig lists the roles to assign to, or to remove from, a team, a user or a service account.
+// The assignee is found by team name, user login or service account name.
+type assignmentFromConfig struct {
+	OrgID int64
+	Name  string
+	Roles []*roleRefFromConfig
+	// Groups replaces the external groups synced to a team, they are left untouched when nil
+	Groups []string
+}
+
+type roleRefFromConfig struct {
+	OrgID  int64
+	Global bool
+	UID    string
+	Name   string
+	Absent bool
+}
+
+// configsV2 is a mapping for the version 2 configs, the first supported version
+type configsV2 struct {
+	configVersion
+
+	Roles           []*roleFromConfigV2       `json:"roles" yaml:"roles"`
+	Teams           []*teamAssignmentConfigV2 `json:"teams" yaml:"teams"`
+	Users           []*userAssignmentConfigV2 `json:"users" yaml:"users"`
+	ServiceAccounts []*assignmentFromConfigV2 `json:"serviceAccounts" yaml:"serviceAccounts"`
+}
+
+type roleFromConfigV2 struct {
+	OrgID       values.Int64Value         `json:"orgId" yaml:"orgId"`
+	Global      values.BoolValue          `json:"global" yaml:"global"`
+	UID         values.StringValue        `json:"uid" yaml:"uid"`
+	Name        values.StringValue        `json:"name" yaml:"name"`
+	DisplayName values.StringValue        `json:"displayName" yaml:"displayName"`
+	Description values.StringValue        `json:"description" yaml:"description"`
+	Group       values.StringValue        `json:"group" yaml:"group"`
+	Hidden      values.BoolValue          `json:"hidden" yaml:"hidden"`
+	Permissions []*permissionFromConfigV2 `json:"permissions" yaml:"permissions"`
+	State       values.StringValue        `json:"state" yaml:"state"`
+}
+
+type permissionFromConfigV2 struct {
+	Action values.StringValue `json:"action" yaml:"action"`
+	Scope  values.StringValue `json:"scope" yaml:"scope"`
+}
+
+type assignmentFromConfigV2 struct {
+	OrgID values.Int64Value      `json:"orgId" yaml:"orgId"`
+	Name  values.StringValue     `json:"name" yaml:"name"`
+	Roles []*roleRefFromConfigV2 `json:"roles" yaml:"roles"`
+}
+
+type teamAssignmentConfigV2 struct {
+	OrgID  values.Int64Value      `json:"orgId" yaml:"orgId"`
+	Name   values.StringValue     `json:"name" yaml:"name"`
+	Roles  []*roleRefFromConfigV2 `json:"roles" yaml:"roles"`
+	Groups []values.StringValue   `json:"groups" yaml:"groups"`
+}
+
+type userAssignmentConfigV2 struct {
+	OrgID values.Int64Value      `json:"orgId" yaml:"orgId"`
+	Login values.StringValue     `json:"login" yaml:"login"`
+	Roles []*roleRefFromConfigV2 `json:"roles" yaml:"roles"`
+}
+
+type roleRefFromConfigV2 struct {
+	OrgID  values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Global values.BoolValue   `json:"global" yaml:"global"`
+	UID    values.StringValue `json:"uid" yaml:"uid"`
+	Name   values.StringValue `json:"name" yaml:"name"`
+	State  values.StringValue `json:"state" yaml:"state"`
+}
+
+// mapToAccessControlFromConfig maps config syntax to a normalized configs object. Every version
+// of the config syntax should have this function.
+func (cfg *configsV2) mapToAccessControlFromConfig() *configs {
+	r := &configs{}
+	if cfg == nil {
+		return r
+	}
+
+	for _, role := range cfg.Roles {
+		permissions := make([]accesscontrol.Permission, 0, len(role.Permissions))
+		for _, p := range role.Permissions {
+			permissions = append(permissions, accesscontrol.Permission{Action: p.Action.Value(), Scope: p.Scope.Value()})
+		}
+
+		r.Roles = append(r.Roles, &roleFromConfig{
+			OrgID:       role.OrgID.Value(),
+			Global:      role.Global.Value(),
+			UID:         role.UID.Value(),
+			Name:        role.Name.Value(),
+			DisplayName: role.DisplayName.Value(),
+			Description: role.Description.Value(),
+			Group:       role.Group.Value(),
+			Hidden:      role.Hidden.Value(),
+			Permissions: permissions,
+			Absent:      role.State.Value() == stateAbsent,
+		})
+	}
+
+	for _, t := range cfg.Teams {
+		a := mapAssignment(t.OrgID.Value(), t.Name.Value(), t.Roles)
+		if t.Groups != nil {
+			a.Groups = make([]string, 0, len(t.Groups))
+			for _, g := range t.Groups {
+				a.Groups = append(a.Groups, g.Value())
+			}
+		}
+		r.Teams = append(r.Teams, a)
+	}
+	for _, u := range cfg.Users {
+		r.Users = append(r.Users, mapAssignment(u.OrgID.Value(), u.Login.Value(), u.Roles))
+	}
+	for _, sa := range cfg.ServiceAccounts {
+		r.ServiceAccounts = append(r.ServiceAccounts, mapAssignment(sa.OrgID.Value(), sa.Name.Value(), sa.Roles))
+	}
+
+	return r
+}
+
+func mapAssignment(orgID int64, name string, roles []*roleRefFromConfigV2) *assignmentFromConfig {
+	a := &assignmentFromConfig{OrgID: orgID, Name: name}
+	for _, role := range roles {
+		a.Roles = append(a.Roles, &roleRefFromConfig{
+			OrgID:  role.OrgID.Value(),
+			Global: role.Global.Value(),
+			UID:    role.UID.Value(),
+			Name:   role.Name.Value(),
+			Absent: role.State.Value() == stateAbsent,
+		})
+	}
+	return a
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/api/response"
@@ -18,12 +19,20 @@ import (
 	"github.com/grafana/grafana/pkg/web"
 )
 
+// access log entries that can be saved at the same time, more entries are dropped and counted
+// in the public_dashboard_access_log_dropped_total metric
+const maxPendingAccessLogs = 100
+
+const accessLogTimeout = 10 * time.Second
+
 type Api struct {
 	PublicDashboardService publicdashboards.Service
 	RouteRegister          routing.RouteRegister
 	AccessControl          accesscontrol.AccessControl
 	Features               *featuremgmt.FeatureManager
 	Log                    log.Logger
+
+	pendingAccessLogs chan struct{}
 }
 
 func ProvideApi(
@@ -38,6 +47,7 @@ func ProvideApi(
 		AccessControl:          ac,
 		Features:               features,
 		Log:                    log.New("publicdashboards.api"),
+		pendingAccessLogs:      make(chan struct{}, maxPendingAccessLogs),
 	}
 
 	// attach api if PublicDashboards feature flag is enabled
@@ -85,6 +95,26 @@ func (api *Api) RegisterAPIEndpoints() {
 	api.RouteRegister.Delete("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid",
 		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
 		routing.Wrap(api.DeletePublicDashboard))
+
+	// List named tokens of a public dashboard
+	api.RouteRegister.Get("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/tokens",
+		auth(middleware.ReqSignedIn, accesscontrol.EvalPermission(dashboards.ActionDashboardsRead, uidScope)),
+		routing.Wrap(api.ListPublicDashboardTokens))
+
+	// Create a named token
+	api.RouteRegister.Post("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/tokens",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.CreatePublicDashboardToken))
+
+	// Revoke a named token
+	api.RouteRegister.Delete("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/tokens/:tokenUid",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.RevokePublicDashboardToken))
+
+	// Access log of a public dashboard
+	api.RouteRegister.Get("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/access-log",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.GetPublicDashboardAccessLog))
 }
 
 // ListPublicDashboards Gets list of public dashboards by orgId
@@ -1,11 +1,15 @@
 package api
 
 import (
+	"context"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/metrics"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
@@ -30,25 +34,29 @@ func (api *Api) ViewPublicDashboard(c *contextmodel.ReqContext) response.Respons
 	}
 
 	meta := dtos.DashboardMeta{
-		Slug:                       dash.Slug,
-		Type:                       dashboards.DashTypeDB,
-		CanStar:                    false,
-		CanSave:                    false,
-		CanEdit:                    false,
-		CanAdmin:                   false,
-		CanDelete:                  false,
-		Created:                    dash.Created,
-		Updated:                    dash.Updated,
-		Version:                    dash.Version,
-		IsFolder:                   false,
-		FolderId:                   dash.FolderID,
-		PublicDashboardAccessToken: pubdash.AccessToken,
+		Slug:      dash.Slug,
+		Type:      dashboards.DashTypeDB,
+		CanStar:   false,
+		CanSave:   false,
+		CanEdit:   false,
+		CanAdmin:  false,
+		CanDelete: false,
+		Created:   dash.Created,
+		Updated:   dash.Updated,
+		Version:   dash.Version,
+		IsFolder:  false,
+		FolderId:  dash.FolderID,
+		// named tokens are returned as is, so the main access token is not shared with their viewers
+		PublicDashboardAccessToken: accessToken,
 		PublicDashboardEnabled:     pubdash.IsEnabled,
 	}
 	dash.Data.Get("timepicker").Set("hidden", !pubdash.TimeSelectionEnabled)
+	sanitizeTemplateVariables(dash.Data, pubdash.VariableSettings)
 
 	dto := dtos.DashboardFullWithMeta{Meta: meta, Dashboard: dash.Data}
 
+	api.recordAccess(c, accessToken, AccessActionView, 0)
+
 	return response.JSON(http.StatusOK, dto)
 }
 
@@ -75,6 +83,8 @@ func (api *Api) QueryPublicDashboard(c *contextmodel.ReqContext) response.Respon
 		return response.Err(err)
 	}
 
+	api.recordAccess(c, accessToken, AccessActionQuery, panelId)
+
 	return toJsonStreamingResponse(api.Features, resp)
 }
 
@@ -96,5 +106,86 @@ func (api *Api) GetAnnotations(c *contextmodel.ReqContext) response.Response {
 		return response.Err(err)
 	}
 
+	api.recordAccess(c, accessToken, AccessActionAnnotations, 0)
+
 	return response.JSON(http.StatusOK, annotations)
 }
+
+// recordAccess adds the request to the access log. The entry is saved in the background so viewers don't wait for it,
+// and dropped when too many entries are pending, which is counted in the public_dashboard_access_log_dropped_total metric. Errors are logged so they don't prevent viewing the dashboard.
+func (api *Api) recordAccess(c *contextmodel.ReqContext, accessToken string, action string, panelId int64) {
+	cmd := RecordAccessCommand{
+		AccessToken: accessToken,
+		Action:      action,
+		PanelId:     panelId,
+		IP:          c.RemoteAddr(),
+	}
+
+	select {
+	case api.pendingAccessLogs <- struct{}{}:
+	default:
+		metrics.MPublicDashboardAccessLogDropped.Inc()
+		api.Log.Warn("Too many pending public dashboard access log entries, dropping entry", "action", action)
+		return
+	}
+
+	go func() {
+		defer func() { <-api.pendingAccessLogs }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), accessLogTimeout)
+		defer cancel()
+		if err := api.PublicDashboardService.RecordAccess(ctx, cmd); err != nil {
+			api.Log.Error("Failed to record public dashboard access", "action", action, "error", err)
+		}
+	}()
+}
+
+// sanitizeTemplateVariables turns the dashboard variables into custom variables, so the viewer can only pick
+// the allowed values and no variable query is sent to a datasource. Variables that are not allowed are hidden.
+func sanitizeTemplateVariables(dashboard *simplejson.Json, settings *VariableSettings) {
+	for _, obj := range dashboard.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(obj)
+		name := variable.Get("name").MustString()
+
+		switch variable.Get("type").MustString() {
+		case "constant", "interval":
+			continue
+		case "adhoc":
+			variable.Set("hide", 2)
+			continue
+		}
+
+		var values []string
+		if settings.IsAllowed(name) {
+			values = settings.Allowed[name]
+		} else {
+			// the saved value is the only one that can be used
+			variable.Set("hide", 2)
+			current := variable.GetPath("current", "value")
+			if value, err := current.String(); err == nil {
+				values = []string{value}
+			} else {
+				values = current.MustStringArray()
+			}
+		}
+
+		query := make([]string, 0, len(values))
+		includeAll := false
+		for _, value := range values {
+			if value == "$__all" {
+				includeAll = true
+				continue
+			}
+			// commas separate values in custom variables
+			query = append(query, strings.ReplaceAll(value, ",", "\\,"))
+		}
+
+		variable.Set("type", "custom")
+		variable.Set("query", strings.Join(query, ","))
+		variable.Set("includeAll", includeAll)
+		variable.Set("options", []interface{}{})
+		variable.Del("datasource")
+		variable.Del("definition")
+		variable.Del("regex")
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -17,8 +19,10 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/metrics"
 	acmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
 	"github.com/grafana/grafana/pkg/services/annotations/annotationstest"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	dashboardStore "github.com/grafana/grafana/pkg/services/dashboards/database"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -34,6 +38,7 @@ import (
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util/errutil"
 	"github.com/grafana/grafana/pkg/web"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -85,6 +90,7 @@ func TestAPIViewPublicDashboard(t *testing.T) {
 			service := publicdashboards.NewFakePublicDashboardService(t)
 			service.On("FindEnabledPublicDashboardAndDashboardByAccessToken", mock.Anything, mock.AnythingOfType("string")).
 				Return(&PublicDashboard{Uid: "pubdashuid"}, test.DashboardResult, test.Err).Maybe()
+			service.On("RecordAccess", mock.Anything, mock.Anything).Return(nil).Maybe()
 
 			cfg := setting.NewCfg()
 			cfg.RBACEnabled = false
@@ -133,6 +139,71 @@ func TestAPIViewPublicDashboard(t *testing.T) {
 }
 
 // `/public/dashboards/:uid/query“ endpoint test
+func TestSanitizeTemplateVariables(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(`{
+		"templating": {
+			"list": [
+				{"name": "env", "type": "query", "query": "label_values(env)", "datasource": {"uid": "prom"}, "current": {"value": "prod"}},
+				{"name": "host", "type": "query", "query": "label_values(host)", "current": {"value": ["a", "b"]}},
+				{"name": "step", "type": "interval", "query": "1m,5m", "current": {"value": "1m"}}
+			]
+		}
+	}`))
+	require.NoError(t, err)
+
+	sanitizeTemplateVariables(dashboard, &VariableSettings{Allowed: map[string][]string{"env": {"prod", "dev,test", "$__all"}}})
+
+	env := dashboard.GetPath("templating", "list").GetIndex(0)
+	assert.Equal(t, "custom", env.Get("type").MustString())
+	assert.Equal(t, `prod,dev\,test`, env.Get("query").MustString())
+	assert.True(t, env.Get("includeAll").MustBool())
+	assert.Equal(t, 0, env.Get("hide").MustInt())
+	_, ok := env.CheckGet("datasource")
+	assert.False(t, ok)
+
+	host := dashboard.GetPath("templating", "list").GetIndex(1)
+	assert.Equal(t, "custom", host.Get("type").MustString())
+	assert.Equal(t, "a,b", host.Get("query").MustString())
+	assert.Equal(t, 2, host.Get("hide").MustInt())
+
+	step := dashboard.GetPath("templating", "list").GetIndex(2)
+	assert.Equal(t, "interval", step.Get("type").MustString())
+}
+
+func TestRecordAccess(t *testing.T) {
+	t.Run("saves the entry in the background", func(t *testing.T) {
+		recorded := make(chan RecordAccessCommand, 1)
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("RecordAccess", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			recorded <- args.Get(1).(RecordAccessCommand)
+		}).Return(nil)
+		api := &Api{PublicDashboardService: service, Log: log.NewNopLogger(), pendingAccessLogs: make(chan struct{}, 1)}
+
+		req := httptest.NewRequest(http.MethodGet, "/api/public/dashboards/token", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		api.recordAccess(&contextmodel.ReqContext{Context: &web.Context{Req: req}}, "token", AccessActionQuery, 2)
+
+		select {
+		case cmd := <-recorded:
+			assert.Equal(t, RecordAccessCommand{AccessToken: "token", Action: AccessActionQuery, PanelId: 2, IP: "10.0.0.1"}, cmd)
+		case <-time.After(time.Second):
+			t.Fatal("access was not recorded")
+		}
+	})
+
+	t.Run("drops the entry when too many are pending", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		api := &Api{PublicDashboardService: service, Log: log.NewNopLogger(), pendingAccessLogs: make(chan struct{}, 1)}
+		api.pendingAccessLogs <- struct{}{}
+
+		dropped := testutil.ToFloat64(metrics.MPublicDashboardAccessLogDropped)
+		req := httptest.NewRequest(http.MethodGet, "/api/public/dashboards/token", nil)
+		api.recordAccess(&contextmodel.ReqContext{Context: &web.Context{Req: req}}, "token", AccessActionView, 0)
+		service.AssertNotCalled(t, "RecordAccess", mock.Anything, mock.Anything)
+		assert.Equal(t, dropped+1, testutil.ToFloat64(metrics.MPublicDashboardAccessLogDropped))
+	})
+}
+
 func TestAPIQueryPublicDashboard(t *testing.T) {
 	mockedResponse := &backend.QueryDataResponse{
 		Responses: map[string]backend.DataResponse{
@@ -188,6 +259,7 @@ func TestAPIQueryPublicDashboard(t *testing.T) {
 
 	setup := func(enabled bool) (*web.Mux, *publicdashboards.FakePublicDashboardService) {
 		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("RecordAccess", mock.Anything, mock.Anything).Return(nil).Maybe()
 		cfg := setting.NewCfg()
 		cfg.RBACEnabled = false
 
@@ -416,6 +488,7 @@ func TestAPIGetAnnotations(t *testing.T) {
 			cfg := setting.NewCfg()
 			cfg.RBACEnabled = false
 			service := publicdashboards.NewFakePublicDashboardService(t)
+			service.On("RecordAccess", mock.Anything, mock.Anything).Return(nil).Maybe()
 
 			if test.ExpectedServiceCalled {
 				service.On("FindAnnotations", mock.Anything, mock.Anything, mock.AnythingOfType("string")).
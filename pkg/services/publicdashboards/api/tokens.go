@@ -0,0 +1,102 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// ListPublicDashboardTokens Gets the named tokens of a public dashboard
+// GET /api/dashboards/uid/:dashboardUid/public-dashboards/:uid/tokens
+func (api *Api) ListPublicDashboardTokens(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	if !validation.IsValidShortUID(uid) {
+		return response.Err(ErrInvalidUid.Errorf("ListPublicDashboardTokens: invalid Uid %s", uid))
+	}
+
+	tokens, err := api.PublicDashboardService.FindTokens(c.Req.Context(), c.OrgID, web.Params(c.Req)[":dashboardUid"], uid)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, tokens)
+}
+
+// CreatePublicDashboardToken Creates a named token for a public dashboard
+// POST /api/dashboards/uid/:dashboardUid/public-dashboards/:uid/tokens
+func (api *Api) CreatePublicDashboardToken(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	if !validation.IsValidShortUID(uid) {
+		return response.Err(ErrInvalidUid.Errorf("CreatePublicDashboardToken: invalid Uid %s", uid))
+	}
+
+	dto := &CreatePublicDashboardTokenDTO{}
+	if err := web.Bind(c.Req, dto); err != nil {
+		return response.Err(ErrBadRequest.Errorf("CreatePublicDashboardToken: bad request data %v", err))
+	}
+	dto.DashboardUid = web.Params(c.Req)[":dashboardUid"]
+	dto.PublicDashboardUid = uid
+
+	token, err := api.PublicDashboardService.CreateToken(c.Req.Context(), c.SignedInUser, dto)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, token)
+}
+
+// RevokePublicDashboardToken Revokes a named token of a public dashboard
+// DELETE /api/dashboards/uid/:dashboardUid/public-dashboards/:uid/tokens/:tokenUid
+func (api *Api) RevokePublicDashboardToken(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	if !validation.IsValidShortUID(uid) {
+		return response.Err(ErrInvalidUid.Errorf("RevokePublicDashboardToken: invalid Uid %s", uid))
+	}
+
+	tokenUid := web.Params(c.Req)[":tokenUid"]
+	if !validation.IsValidShortUID(tokenUid) {
+		return response.Err(ErrInvalidUid.Errorf("RevokePublicDashboardToken: invalid token Uid %s", tokenUid))
+	}
+
+	if err := api.PublicDashboardService.RevokeToken(c.Req.Context(), c.OrgID, web.Params(c.Req)[":dashboardUid"], uid, tokenUid); err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, nil)
+}
+
+// GetPublicDashboardAccessLog Gets the access log of a public dashboard, newest entries first
+// GET /api/dashboards/uid/:dashboardUid/public-dashboards/:uid/access-log
+func (api *Api) GetPublicDashboardAccessLog(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	if !validation.IsValidShortUID(uid) {
+		return response.Err(ErrInvalidUid.Errorf("GetPublicDashboardAccessLog: invalid Uid %s", uid))
+	}
+
+	query := AccessLogQuery{
+		OrgId:              c.OrgID,
+		DashboardUid:       web.Params(c.Req)[":dashboardUid"],
+		PublicDashboardUid: uid,
+		TokenUid:           c.Query("tokenUid"),
+		Limit:              c.QueryInt("limit"),
+	}
+	// from and to are epoch milliseconds
+	if from := c.QueryInt64("from"); from > 0 {
+		query.From = time.UnixMilli(from)
+	}
+	if to := c.QueryInt64("to"); to > 0 {
+		query.To = time.UnixMilli(to)
+	}
+
+	entries, err := api.PublicDashboardService.FindAccessLogs(c.Req.Context(), query)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, entries)
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -21,6 +22,14 @@ type PublicDashboardStoreImpl struct {
 
 var LogPrefix = "publicdashboards.store"
 
+// timestamps are saved in UTC, so they are compared with formatted UTC times like in the other queries
+func utcTimestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// selects the public dashboard of a token that is not revoked or expired
+const validTokenSQL = "SELECT public_dashboard_uid FROM dashboard_public_token WHERE access_token=? AND revoked=? AND (expires_at IS NULL OR expires_at > ?)"
+
 // Gives us a compile time error if our database does not adhere to contract of
 // the interface
 var _ publicdashboards.Store = (*PublicDashboardStoreImpl)(nil)
@@ -109,10 +118,10 @@ func (d *PublicDashboardStoreImpl) FindByAccessToken(ctx context.Context, access
 	}
 
 	var found bool
-	publicDashboard := &PublicDashboard{AccessToken: accessToken}
+	publicDashboard := &PublicDashboard{}
 	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
 		var err error
-		found, err = sess.Get(publicDashboard)
+		found, err = sess.Where("access_token=? OR uid IN ("+validTokenSQL+")", accessToken, accessToken, false, utcTimestamp(time.Now())).Get(publicDashboard)
 		return err
 	})
 
@@ -177,9 +186,9 @@ func (d *PublicDashboardStoreImpl) ExistsEnabledByDashboardUid(ctx context.Conte
 func (d *PublicDashboardStoreImpl) ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error) {
 	hasPublicDashboard := false
 	err := d.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
-		sql := "SELECT COUNT(*) FROM dashboard_public WHERE access_token=? AND is_enabled=true"
+		sql := "SELECT COUNT(*) FROM dashboard_public WHERE (access_token=? OR uid IN (" + validTokenSQL + ")) AND is_enabled=true"
 
-		result, err := dbSession.SQL(sql, accessToken).Count()
+		result, err := dbSession.SQL(sql, accessToken, accessToken, false, utcTimestamp(time.Now())).Count()
 		if err != nil {
 			return err
 		}
@@ -195,9 +204,9 @@ func (d *PublicDashboardStoreImpl) ExistsEnabledByAccessToken(ctx context.Contex
 func (d *PublicDashboardStoreImpl) GetOrgIdByAccessToken(ctx context.Context, accessToken string) (int64, error) {
 	var orgId int64
 	err := d.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
-		sql := "SELECT org_id FROM dashboard_public WHERE access_token=?"
+		sql := "SELECT org_id FROM dashboard_public WHERE access_token=? OR uid IN (" + validTokenSQL + ")"
 
-		_, err := dbSession.SQL(sql, accessToken).Get(&orgId)
+		_, err := dbSession.SQL(sql, accessToken, accessToken, false, utcTimestamp(time.Now())).Get(&orgId)
 		if err != nil {
 			return err
 		}
@@ -233,14 +242,21 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 			return err
 		}
 
-		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, share = ?, time_settings = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
+		variableSettingsJSON, err := json.Marshal(cmd.PublicDashboard.VariableSettings)
+		if err != nil {
+			return err
+		}
+
+		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, share = ?, time_settings = ?, max_time_range = ?, variable_settings = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
 			cmd.PublicDashboard.IsEnabled,
 			cmd.PublicDashboard.AnnotationsEnabled,
 			cmd.PublicDashboard.TimeSelectionEnabled,
 			cmd.PublicDashboard.Share,
 			string(timeSettingsJSON),
+			cmd.PublicDashboard.MaxTimeRange,
+			string(variableSettingsJSON),
 			cmd.PublicDashboard.UpdatedBy,
-			cmd.PublicDashboard.UpdatedAt.UTC().Format("2006-01-02 15:04:05"),
+			utcTimestamp(cmd.PublicDashboard.UpdatedAt),
 			cmd.PublicDashboard.Uid)
 
 		if err != nil {
@@ -255,14 +271,18 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 	return affectedRows, err
 }
 
-// Deletes a public dashboard
+// Deletes a public dashboard and its tokens. The access log is kept until it is removed by the cleanup service
 func (d *PublicDashboardStoreImpl) Delete(ctx context.Context, uid string) (int64, error) {
 	dashboard := &PublicDashboard{Uid: uid}
 	var affectedRows int64
-	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+	err := d.sqlStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
 		var err error
 		affectedRows, err = sess.Delete(dashboard)
+		if err != nil {
+			return err
+		}
 
+		_, err = sess.Exec("DELETE FROM dashboard_public_token WHERE public_dashboard_uid=?", uid)
 		return err
 	})
 
@@ -285,3 +305,112 @@ func (d *PublicDashboardStoreImpl) FindByDashboardFolder(ctx context.Context, da
 
 	return pubdashes, nil
 }
+
+// FindTokens returns the tokens of a public dashboard, including revoked and expired tokens
+func (d *PublicDashboardStoreImpl) FindTokens(ctx context.Context, orgId int64, publicDashboardUid string) ([]*PublicDashboardToken, error) {
+	tokens := make([]*PublicDashboardToken, 0)
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id=? AND public_dashboard_uid=?", orgId, publicDashboardUid).OrderBy("created_at DESC").Find(&tokens)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// FindTokenByAccessToken returns a token by access token or nil if not found
+func (d *PublicDashboardStoreImpl) FindTokenByAccessToken(ctx context.Context, accessToken string) (*PublicDashboardToken, error) {
+	if accessToken == "" {
+		return nil, nil
+	}
+
+	var found bool
+	token := &PublicDashboardToken{AccessToken: accessToken}
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		found, err = sess.Get(token)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return token, nil
+}
+
+// CreateToken saves a new token for a public dashboard
+func (d *PublicDashboardStoreImpl) CreateToken(ctx context.Context, token *PublicDashboardToken) error {
+	return d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.UseBool("revoked").Insert(token)
+		return err
+	})
+}
+
+// RevokeToken marks a token as revoked, revoked tokens are kept so they can be found in the access log
+func (d *PublicDashboardStoreImpl) RevokeToken(ctx context.Context, orgId int64, publicDashboardUid string, uid string) (int64, error) {
+	var affectedRows int64
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		sqlResult, err := sess.Exec("UPDATE dashboard_public_token SET revoked=?, updated_at=? WHERE org_id=? AND public_dashboard_uid=? AND uid=?",
+			true, utcTimestamp(time.Now()), orgId, publicDashboardUid, uid)
+		if err != nil {
+			return err
+		}
+
+		affectedRows, err = sqlResult.RowsAffected()
+		return err
+	})
+
+	return affectedRows, err
+}
+
+// CreateAccessLog adds an entry to the access log
+func (d *PublicDashboardStoreImpl) CreateAccessLog(ctx context.Context, entry *PublicDashboardAccessLog) error {
+	return d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(entry)
+		return err
+	})
+}
+
+// FindAccessLogs returns the access log of a public dashboard, newest entries first
+func (d *PublicDashboardStoreImpl) FindAccessLogs(ctx context.Context, query AccessLogQuery) ([]*PublicDashboardAccessLog, error) {
+	entries := make([]*PublicDashboardAccessLog, 0)
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Where("org_id=? AND public_dashboard_uid=?", query.OrgId, query.PublicDashboardUid)
+		if query.TokenUid != "" {
+			sess.And("token_uid=?", query.TokenUid)
+		}
+		if !query.From.IsZero() {
+			sess.And("created>=?", utcTimestamp(query.From))
+		}
+		if !query.To.IsZero() {
+			sess.And("created<=?", utcTimestamp(query.To))
+		}
+		if query.Limit > 0 {
+			sess.Limit(query.Limit)
+		}
+		return sess.OrderBy("created DESC, id DESC").Find(&entries)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// DeleteAccessLogsOlderThan removes access log entries created before the given time
+func (d *PublicDashboardStoreImpl) DeleteAccessLogsOlderThan(ctx context.Context, olderThan time.Time) (int64, error) {
+	var affectedRows int64
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		affectedRows, err = sess.Where("created < ?", utcTimestamp(olderThan)).Delete(&PublicDashboardAccessLog{})
+		return err
+	})
+
+	return affectedRows, err
+}
@@ -275,13 +275,14 @@ func TestIntegrationFindByDashboardUid(t *testing.T) {
 		setup()
 		cmd := SavePublicDashboardCommand{
 			PublicDashboard: PublicDashboard{
-				IsEnabled:    true,
-				Uid:          "pubdash-uid",
-				DashboardUid: savedDashboard.UID,
-				OrgId:        savedDashboard.OrgID,
-				TimeSettings: DefaultTimeSettings,
-				CreatedAt:    DefaultTime,
-				CreatedBy:    7,
+				IsEnabled:        true,
+				Uid:              "pubdash-uid",
+				DashboardUid:     savedDashboard.UID,
+				OrgId:            savedDashboard.OrgID,
+				TimeSettings:     DefaultTimeSettings,
+				VariableSettings: &VariableSettings{},
+				CreatedAt:        DefaultTime,
+				CreatedBy:        7,
 			},
 		}
 
@@ -342,14 +343,15 @@ func TestIntegrationFindByAccessToken(t *testing.T) {
 		setup()
 		cmd := SavePublicDashboardCommand{
 			PublicDashboard: PublicDashboard{
-				IsEnabled:    true,
-				Uid:          "pubdash-uid",
-				DashboardUid: savedDashboard.UID,
-				OrgId:        savedDashboard.OrgID,
-				TimeSettings: DefaultTimeSettings,
-				CreatedAt:    DefaultTime,
-				CreatedBy:    7,
-				AccessToken:  "thisisavalidaccesstoken",
+				IsEnabled:        true,
+				Uid:              "pubdash-uid",
+				DashboardUid:     savedDashboard.UID,
+				OrgId:            savedDashboard.OrgID,
+				TimeSettings:     DefaultTimeSettings,
+				VariableSettings: &VariableSettings{},
+				CreatedAt:        DefaultTime,
+				CreatedBy:        7,
+				AccessToken:      "thisisavalidaccesstoken",
 			},
 		}
 
@@ -671,6 +673,120 @@ func TestIntegrationDelete(t *testing.T) {
 	})
 }
 
+func TestIntegrationPublicDashboardTokens(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore, cfg := db.InitTestDBwithCfg(t)
+	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, cfg), quotatest.New(false, nil))
+	require.NoError(t, err)
+	publicdashboardStore := ProvideStore(sqlStore)
+	savedDashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true)
+	savedPublicDashboard := insertPublicDashboard(t, publicdashboardStore, savedDashboard.UID, savedDashboard.OrgID, true)
+
+	ctx := context.Background()
+	expired := time.Now().Add(-time.Hour)
+	valid := insertPublicDashboardToken(t, publicdashboardStore, savedPublicDashboard, "valid", nil)
+	expiredToken := insertPublicDashboardToken(t, publicdashboardStore, savedPublicDashboard, "expired", &expired)
+	revoked := insertPublicDashboardToken(t, publicdashboardStore, savedPublicDashboard, "revoked", nil)
+
+	affectedRows, err := publicdashboardStore.RevokeToken(ctx, savedPublicDashboard.OrgId, savedPublicDashboard.Uid, revoked.Uid)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, affectedRows)
+
+	t.Run("finds all tokens of the public dashboard", func(t *testing.T) {
+		tokens, err := publicdashboardStore.FindTokens(ctx, savedPublicDashboard.OrgId, savedPublicDashboard.Uid)
+		require.NoError(t, err)
+		assert.Len(t, tokens, 3)
+
+		token, err := publicdashboardStore.FindTokenByAccessToken(ctx, revoked.AccessToken)
+		require.NoError(t, err)
+		assert.True(t, token.Revoked)
+	})
+
+	t.Run("valid token can be used as access token", func(t *testing.T) {
+		pubdash, err := publicdashboardStore.FindByAccessToken(ctx, valid.AccessToken)
+		require.NoError(t, err)
+		require.NotNil(t, pubdash)
+		assert.Equal(t, savedPublicDashboard.Uid, pubdash.Uid)
+
+		exists, err := publicdashboardStore.ExistsEnabledByAccessToken(ctx, valid.AccessToken)
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		orgId, err := publicdashboardStore.GetOrgIdByAccessToken(ctx, valid.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, savedPublicDashboard.OrgId, orgId)
+	})
+
+	t.Run("expired and revoked tokens can't be used", func(t *testing.T) {
+		for _, token := range []*PublicDashboardToken{expiredToken, revoked} {
+			pubdash, err := publicdashboardStore.FindByAccessToken(ctx, token.AccessToken)
+			require.NoError(t, err)
+			assert.Nil(t, pubdash)
+
+			exists, err := publicdashboardStore.ExistsEnabledByAccessToken(ctx, token.AccessToken)
+			require.NoError(t, err)
+			assert.False(t, exists)
+		}
+	})
+
+	t.Run("tokens are deleted with the public dashboard", func(t *testing.T) {
+		_, err := publicdashboardStore.Delete(ctx, savedPublicDashboard.Uid)
+		require.NoError(t, err)
+
+		tokens, err := publicdashboardStore.FindTokens(ctx, savedPublicDashboard.OrgId, savedPublicDashboard.Uid)
+		require.NoError(t, err)
+		assert.Len(t, tokens, 0)
+	})
+}
+
+func TestIntegrationAccessLog(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore, _ := db.InitTestDBwithCfg(t)
+	publicdashboardStore := ProvideStore(sqlStore)
+	ctx := context.Background()
+
+	now := DefaultTime
+	for i, entry := range []*PublicDashboardAccessLog{
+		{OrgId: 1, PublicDashboardUid: "pubdash", Action: AccessActionView, IP: "10.0.0.1", Created: now.Add(-48 * time.Hour)},
+		{OrgId: 1, PublicDashboardUid: "pubdash", TokenUid: "token", Action: AccessActionQuery, PanelId: 2, IP: "10.0.0.2", Created: now.Add(-time.Hour)},
+		{OrgId: 1, PublicDashboardUid: "pubdash", Action: AccessActionQuery, PanelId: 3, IP: "10.0.0.1", Created: now},
+		{OrgId: 2, PublicDashboardUid: "other", Action: AccessActionView, IP: "10.0.0.3", Created: now},
+	} {
+		err := publicdashboardStore.CreateAccessLog(ctx, entry)
+		require.NoError(t, err, i)
+	}
+
+	entries, err := publicdashboardStore.FindAccessLogs(ctx, AccessLogQuery{OrgId: 1, PublicDashboardUid: "pubdash"})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, int64(3), entries[0].PanelId)
+	assert.Equal(t, "10.0.0.2", entries[1].IP)
+
+	entries, err = publicdashboardStore.FindAccessLogs(ctx, AccessLogQuery{OrgId: 1, PublicDashboardUid: "pubdash", TokenUid: "token"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	entries, err = publicdashboardStore.FindAccessLogs(ctx, AccessLogQuery{OrgId: 1, PublicDashboardUid: "pubdash", From: now.Add(-2 * time.Hour), Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(3), entries[0].PanelId)
+
+	// the time range is compared in UTC, whatever the location of the query
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	entries, err = publicdashboardStore.FindAccessLogs(ctx, AccessLogQuery{OrgId: 1, PublicDashboardUid: "pubdash", From: now.Add(-2 * time.Hour).In(zone), To: now.Add(-time.Minute).In(zone)})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(2), entries[0].PanelId)
+
+	deleted, err := publicdashboardStore.DeleteAccessLogsOlderThan(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, deleted)
+}
+
 func TestGetDashboardByFolder(t *testing.T) {
 	t.Run("returns nil when dashboard is not a folder", func(t *testing.T) {
 		sqlStore, _ := db.InitTestDBwithCfg(t)
@@ -763,3 +879,24 @@ func insertPublicDashboard(t *testing.T, publicdashboardStore *PublicDashboardSt
 
 	return pubdash
 }
+
+func insertPublicDashboardToken(t *testing.T, publicdashboardStore *PublicDashboardStoreImpl, pubdash *PublicDashboard, name string, expiresAt *time.Time) *PublicDashboardToken {
+	accessToken, err := service.GenerateAccessToken()
+	require.NoError(t, err)
+
+	token := &PublicDashboardToken{
+		Uid:                util.GenerateShortUID(),
+		PublicDashboardUid: pubdash.Uid,
+		OrgId:              pubdash.OrgId,
+		Name:               name,
+		AccessToken:        accessToken,
+		ExpiresAt:          expiresAt,
+		CreatedBy:          1,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
+	}
+	err = publicdashboardStore.CreateToken(context.Background(), token)
+	require.NoError(t, err)
+
+	return token
+}
@@ -8,6 +8,7 @@ var (
 	ErrPublicDashboardNotFound = errutil.NewBase(errutil.StatusNotFound, "publicdashboards.notFound", errutil.WithPublicMessage("Public dashboard not found"))
 	ErrDashboardNotFound       = errutil.NewBase(errutil.StatusNotFound, "publicdashboards.dashboardNotFound", errutil.WithPublicMessage("Dashboard not found"))
 	ErrPanelNotFound           = errutil.NewBase(errutil.StatusNotFound, "publicdashboards.panelNotFound", errutil.WithPublicMessage("Public dashboard panel not found"))
+	ErrTokenNotFound           = errutil.NewBase(errutil.StatusNotFound, "publicdashboards.tokenNotFound", errutil.WithPublicMessage("Public dashboard token not found"))
 
 	ErrBadRequest                          = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.badRequest")
 	ErrPanelQueriesNotFound                = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.panelQueriesNotFound", errutil.WithPublicMessage("Failed to extract queries from panel"))
@@ -20,6 +21,11 @@ var (
 	ErrInvalidMaxDataPoints                = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.maxDataPoints", errutil.WithPublicMessage("maxDataPoints should be greater than 0"))
 	ErrInvalidTimeRange                    = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidTimeRange", errutil.WithPublicMessage("Invalid time range"))
 	ErrInvalidShareType                    = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidShareType", errutil.WithPublicMessage("Invalid share type"))
+	ErrInvalidMaxTimeRange                 = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidMaxTimeRange", errutil.WithPublicMessage("Invalid max time range"))
+	ErrTimeRangeExceedsMax                 = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.timeRangeExceedsMax", errutil.WithPublicMessage("Time range exceeds the max time range of the public dashboard"))
+	ErrInvalidTokenName                    = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidTokenName", errutil.WithPublicMessage("Token name is required"))
+	ErrInvalidTokenExpiration              = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidTokenExpiration", errutil.WithPublicMessage("Token expiration must be in the future"))
+	ErrInvalidTemplateVariable             = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidTemplateVariable", errutil.WithPublicMessage("Invalid template variable"))
 
 	ErrPublicDashboardNotEnabled = errutil.NewBase(errutil.StatusForbidden, "publicdashboards.notEnabled", errutil.WithPublicMessage("Public dashboard paused"))
 )
@@ -40,20 +40,22 @@ var (
 type ShareType string
 
 type PublicDashboard struct {
-	Uid                  string        `json:"uid" xorm:"pk uid"`
-	DashboardUid         string        `json:"dashboardUid" xorm:"dashboard_uid"`
-	OrgId                int64         `json:"-" xorm:"org_id"` // Don't ever marshal orgId to Json
-	TimeSettings         *TimeSettings `json:"timeSettings" xorm:"time_settings"`
-	IsEnabled            bool          `json:"isEnabled" xorm:"is_enabled"`
-	AccessToken          string        `json:"accessToken" xorm:"access_token"`
-	AnnotationsEnabled   bool          `json:"annotationsEnabled" xorm:"annotations_enabled"`
-	TimeSelectionEnabled bool          `json:"timeSelectionEnabled" xorm:"time_selection_enabled"`
-	Share                ShareType     `json:"share" xorm:"share"`
-	Recipients           []EmailDTO    `json:"recipients,omitempty" xorm:"-"`
-	CreatedBy            int64         `json:"createdBy" xorm:"created_by"`
-	UpdatedBy            int64         `json:"updatedBy" xorm:"updated_by"`
-	CreatedAt            time.Time     `json:"createdAt" xorm:"created_at"`
-	UpdatedAt            time.Time     `json:"updatedAt" xorm:"updated_at"`
+	Uid                  string            `json:"uid" xorm:"pk uid"`
+	DashboardUid         string            `json:"dashboardUid" xorm:"dashboard_uid"`
+	OrgId                int64             `json:"-" xorm:"org_id"` // Don't ever marshal orgId to Json
+	TimeSettings         *TimeSettings     `json:"timeSettings" xorm:"time_settings"`
+	IsEnabled            bool              `json:"isEnabled" xorm:"is_enabled"`
+	AccessToken          string            `json:"accessToken" xorm:"access_token"`
+	AnnotationsEnabled   bool              `json:"annotationsEnabled" xorm:"annotations_enabled"`
+	TimeSelectionEnabled bool              `json:"timeSelectionEnabled" xorm:"time_selection_enabled"`
+	Share                ShareType         `json:"share" xorm:"share"`
+	MaxTimeRange         string            `json:"maxTimeRange,omitempty" xorm:"max_time_range"`
+	VariableSettings     *VariableSettings `json:"variableSettings,omitempty" xorm:"variable_settings"`
+	Recipients           []EmailDTO        `json:"recipients,omitempty" xorm:"-"`
+	CreatedBy            int64             `json:"createdBy" xorm:"created_by"`
+	UpdatedBy            int64             `json:"updatedBy" xorm:"updated_by"`
+	CreatedAt            time.Time         `json:"createdAt" xorm:"created_at"`
+	UpdatedAt            time.Time         `json:"updatedAt" xorm:"updated_at"`
 }
 
 type EmailDTO struct {
@@ -87,6 +89,53 @@ func (pd PublicDashboard) TableName() string {
 	return "dashboard_public"
 }
 
+// PublicDashboardToken is a named link to a public dashboard, it can expire and be revoked without
+// changing the access token of the public dashboard
+type PublicDashboardToken struct {
+	Id                 int64      `json:"-" xorm:"pk autoincr 'id'"`
+	Uid                string     `json:"uid" xorm:"uid"`
+	PublicDashboardUid string     `json:"publicDashboardUid" xorm:"public_dashboard_uid"`
+	OrgId              int64      `json:"-" xorm:"org_id"`
+	Name               string     `json:"name" xorm:"name"`
+	AccessToken        string     `json:"accessToken" xorm:"access_token"`
+	ExpiresAt          *time.Time `json:"expiresAt,omitempty" xorm:"expires_at"`
+	Revoked            bool       `json:"revoked" xorm:"revoked"`
+	CreatedBy          int64      `json:"createdBy" xorm:"created_by"`
+	CreatedAt          time.Time  `json:"createdAt" xorm:"created_at"`
+	UpdatedAt          time.Time  `json:"updatedAt" xorm:"updated_at"`
+}
+
+func (t PublicDashboardToken) TableName() string {
+	return "dashboard_public_token"
+}
+
+// IsExpired checks if the token can no longer be used to access the public dashboard
+func (t PublicDashboardToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !t.ExpiresAt.After(now)
+}
+
+// PublicDashboardAccessLog is an entry in the access log of public dashboards
+type PublicDashboardAccessLog struct {
+	Id                 int64     `json:"id" xorm:"pk autoincr 'id'"`
+	OrgId              int64     `json:"-" xorm:"org_id"`
+	PublicDashboardUid string    `json:"publicDashboardUid" xorm:"public_dashboard_uid"`
+	TokenUid           string    `json:"tokenUid,omitempty" xorm:"token_uid"` // empty when the public dashboard access token was used
+	Action             string    `json:"action" xorm:"action"`
+	PanelId            int64     `json:"panelId,omitempty" xorm:"panel_id"`
+	IP                 string    `json:"ip" xorm:"ip"`
+	Created            time.Time `json:"created" xorm:"'created'"`
+}
+
+func (l PublicDashboardAccessLog) TableName() string {
+	return "dashboard_public_access_log"
+}
+
+const (
+	AccessActionView        = "view"
+	AccessActionQuery       = "query"
+	AccessActionAnnotations = "annotations"
+)
+
 type PublicDashboardListResponse struct {
 	Uid          string `json:"uid" xorm:"uid"`
 	AccessToken  string `json:"accessToken" xorm:"access_token"`
@@ -108,6 +157,42 @@ func (ts *TimeSettings) ToDB() ([]byte, error) {
 	return json.Marshal(ts)
 }
 
+// VariableSettings holds the template variables a viewer can change and the values they can select.
+// Variables that are not listed always use the value saved in the dashboard.
+type VariableSettings struct {
+	Allowed map[string][]string `json:"allowed,omitempty"`
+}
+
+func (vs *VariableSettings) FromDB(data []byte) error {
+	return json.Unmarshal(data, vs)
+}
+
+func (vs *VariableSettings) ToDB() ([]byte, error) {
+	return json.Marshal(vs)
+}
+
+// IsAllowed checks if the variable can be changed by viewers
+func (vs *VariableSettings) IsAllowed(name string) bool {
+	if vs == nil {
+		return false
+	}
+	_, ok := vs.Allowed[name]
+	return ok
+}
+
+// IsValueAllowed checks if viewers can select the value for the variable
+func (vs *VariableSettings) IsValueAllowed(name string, value string) bool {
+	if vs == nil {
+		return false
+	}
+	for _, v := range vs.Allowed[name] {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // BuildTimeSettings build time settings object using selected values if enabled and are valid or dashboard default values
 func (pd PublicDashboard) BuildTimeSettings(dashboard *dashboards.Dashboard, reqDTO PublicDashboardQueryDTO) TimeSettings {
 	from := dashboard.Data.GetPath("time", "from").MustString()
@@ -140,6 +225,7 @@ type PublicDashboardQueryDTO struct {
 	MaxDataPoints   int64
 	QueryCachingTTL int64
 	TimeRange       TimeSettings
+	Variables       map[string][]string
 }
 
 type AnnotationsQueryDTO struct {
@@ -147,10 +233,34 @@ type AnnotationsQueryDTO struct {
 	To   int64
 }
 
+type CreatePublicDashboardTokenDTO struct {
+	DashboardUid       string     `json:"-"`
+	PublicDashboardUid string     `json:"-"`
+	Name               string     `json:"name"`
+	ExpiresAt          *time.Time `json:"expiresAt,omitempty"`
+}
+
+type AccessLogQuery struct {
+	OrgId              int64
+	DashboardUid       string
+	PublicDashboardUid string
+	TokenUid           string
+	From               time.Time
+	To                 time.Time
+	Limit              int
+}
+
 //
 // COMMANDS
 //
 
+type RecordAccessCommand struct {
+	AccessToken string
+	Action      string
+	PanelId     int64
+	IP          string
+}
+
 type SavePublicDashboardCommand struct {
 	PublicDashboard PublicDashboard
 }
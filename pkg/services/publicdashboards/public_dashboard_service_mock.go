@@ -46,6 +46,29 @@ func (_m *FakePublicDashboardService) Create(ctx context.Context, u *user.Signed
 	return r0, r1
 }
 
+// CreateToken provides a mock function with given fields: ctx, u, dto
+func (_m *FakePublicDashboardService) CreateToken(ctx context.Context, u *user.SignedInUser, dto *models.CreatePublicDashboardTokenDTO) (*models.PublicDashboardToken, error) {
+	ret := _m.Called(ctx, u, dto)
+
+	var r0 *models.PublicDashboardToken
+	if rf, ok := ret.Get(0).(func(context.Context, *user.SignedInUser, *models.CreatePublicDashboardTokenDTO) *models.PublicDashboardToken); ok {
+		r0 = rf(ctx, u, dto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PublicDashboardToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *user.SignedInUser, *models.CreatePublicDashboardTokenDTO) error); ok {
+		r1 = rf(ctx, u, dto)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ctx, uid
 func (_m *FakePublicDashboardService) Delete(ctx context.Context, uid string) error {
 	ret := _m.Called(ctx, uid)
@@ -74,6 +97,27 @@ func (_m *FakePublicDashboardService) DeleteByDashboard(ctx context.Context, das
 	return r0
 }
 
+// DeleteExpiredAccessLogs provides a mock function with given fields: ctx
+func (_m *FakePublicDashboardService) DeleteExpiredAccessLogs(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ExistsEnabledByAccessToken provides a mock function with given fields: ctx, accessToken
 func (_m *FakePublicDashboardService) ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error) {
 	ret := _m.Called(ctx, accessToken)
@@ -139,6 +183,29 @@ func (_m *FakePublicDashboardService) Find(ctx context.Context, uid string) (*mo
 	return r0, r1
 }
 
+// FindAccessLogs provides a mock function with given fields: ctx, query
+func (_m *FakePublicDashboardService) FindAccessLogs(ctx context.Context, query models.AccessLogQuery) ([]*models.PublicDashboardAccessLog, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*models.PublicDashboardAccessLog
+	if rf, ok := ret.Get(0).(func(context.Context, models.AccessLogQuery) []*models.PublicDashboardAccessLog); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.PublicDashboardAccessLog)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, models.AccessLogQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindAll provides a mock function with given fields: ctx, u, orgId
 func (_m *FakePublicDashboardService) FindAll(ctx context.Context, u *user.SignedInUser, orgId int64) ([]models.PublicDashboardListResponse, error) {
 	ret := _m.Called(ctx, u, orgId)
@@ -318,6 +385,29 @@ func (_m *FakePublicDashboardService) FindPublicDashboardAndDashboardByAccessTok
 	return r0, r1, r2
 }
 
+// FindTokens provides a mock function with given fields: ctx, orgId, dashboardUid, publicDashboardUid
+func (_m *FakePublicDashboardService) FindTokens(ctx context.Context, orgId int64, dashboardUid string, publicDashboardUid string) ([]*models.PublicDashboardToken, error) {
+	ret := _m.Called(ctx, orgId, dashboardUid, publicDashboardUid)
+
+	var r0 []*models.PublicDashboardToken
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) []*models.PublicDashboardToken); ok {
+		r0 = rf(ctx, orgId, dashboardUid, publicDashboardUid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.PublicDashboardToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, orgId, dashboardUid, publicDashboardUid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMetricRequest provides a mock function with given fields: ctx, dashboard, publicDashboard, panelId, reqDTO
 func (_m *FakePublicDashboardService) GetMetricRequest(ctx context.Context, dashboard *dashboards.Dashboard, publicDashboard *models.PublicDashboard, panelId int64, reqDTO models.PublicDashboardQueryDTO) (dtos.MetricRequest, error) {
 	ret := _m.Called(ctx, dashboard, publicDashboard, panelId, reqDTO)
@@ -425,6 +515,34 @@ func (_m *FakePublicDashboardService) NewPublicDashboardUid(ctx context.Context)
 	return r0, r1
 }
 
+// RecordAccess provides a mock function with given fields: ctx, cmd
+func (_m *FakePublicDashboardService) RecordAccess(ctx context.Context, cmd models.RecordAccessCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.RecordAccessCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeToken provides a mock function with given fields: ctx, orgId, dashboardUid, publicDashboardUid, uid
+func (_m *FakePublicDashboardService) RevokeToken(ctx context.Context, orgId int64, dashboardUid string, publicDashboardUid string, uid string) error {
+	ret := _m.Called(ctx, orgId, dashboardUid, publicDashboardUid, uid)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, string) error); ok {
+		r0 = rf(ctx, orgId, dashboardUid, publicDashboardUid, uid)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, u, dto
 func (_m *FakePublicDashboardService) Update(ctx context.Context, u *user.SignedInUser, dto *models.SavePublicDashboardDTO) (*models.PublicDashboard, error) {
 	ret := _m.Called(ctx, u, dto)
@@ -9,6 +9,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+
+	time "time"
 )
 
 // FakePublicDashboardStore is an autogenerated mock type for the Store type
@@ -37,6 +39,34 @@ func (_m *FakePublicDashboardStore) Create(ctx context.Context, cmd models.SaveP
 	return r0, r1
 }
 
+// CreateAccessLog provides a mock function with given fields: ctx, entry
+func (_m *FakePublicDashboardStore) CreateAccessLog(ctx context.Context, entry *models.PublicDashboardAccessLog) error {
+	ret := _m.Called(ctx, entry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PublicDashboardAccessLog) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateToken provides a mock function with given fields: ctx, token
+func (_m *FakePublicDashboardStore) CreateToken(ctx context.Context, token *models.PublicDashboardToken) error {
+	ret := _m.Called(ctx, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PublicDashboardToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: ctx, uid
 func (_m *FakePublicDashboardStore) Delete(ctx context.Context, uid string) (int64, error) {
 	ret := _m.Called(ctx, uid)
@@ -58,6 +88,27 @@ func (_m *FakePublicDashboardStore) Delete(ctx context.Context, uid string) (int
 	return r0, r1
 }
 
+// DeleteAccessLogsOlderThan provides a mock function with given fields: ctx, olderThan
+func (_m *FakePublicDashboardStore) DeleteAccessLogsOlderThan(ctx context.Context, olderThan time.Time) (int64, error) {
+	ret := _m.Called(ctx, olderThan)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, olderThan)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, olderThan)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ExistsEnabledByAccessToken provides a mock function with given fields: ctx, accessToken
 func (_m *FakePublicDashboardStore) ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error) {
 	ret := _m.Called(ctx, accessToken)
@@ -123,6 +174,29 @@ func (_m *FakePublicDashboardStore) Find(ctx context.Context, uid string) (*mode
 	return r0, r1
 }
 
+// FindAccessLogs provides a mock function with given fields: ctx, query
+func (_m *FakePublicDashboardStore) FindAccessLogs(ctx context.Context, query models.AccessLogQuery) ([]*models.PublicDashboardAccessLog, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*models.PublicDashboardAccessLog
+	if rf, ok := ret.Get(0).(func(context.Context, models.AccessLogQuery) []*models.PublicDashboardAccessLog); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.PublicDashboardAccessLog)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, models.AccessLogQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindAll provides a mock function with given fields: ctx, orgId
 func (_m *FakePublicDashboardStore) FindAll(ctx context.Context, orgId int64) ([]models.PublicDashboardListResponse, error) {
 	ret := _m.Called(ctx, orgId)
@@ -238,6 +312,52 @@ func (_m *FakePublicDashboardStore) FindDashboard(ctx context.Context, orgId int
 	return r0, r1
 }
 
+// FindTokenByAccessToken provides a mock function with given fields: ctx, accessToken
+func (_m *FakePublicDashboardStore) FindTokenByAccessToken(ctx context.Context, accessToken string) (*models.PublicDashboardToken, error) {
+	ret := _m.Called(ctx, accessToken)
+
+	var r0 *models.PublicDashboardToken
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.PublicDashboardToken); ok {
+		r0 = rf(ctx, accessToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PublicDashboardToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accessToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindTokens provides a mock function with given fields: ctx, orgId, publicDashboardUid
+func (_m *FakePublicDashboardStore) FindTokens(ctx context.Context, orgId int64, publicDashboardUid string) ([]*models.PublicDashboardToken, error) {
+	ret := _m.Called(ctx, orgId, publicDashboardUid)
+
+	var r0 []*models.PublicDashboardToken
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) []*models.PublicDashboardToken); ok {
+		r0 = rf(ctx, orgId, publicDashboardUid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.PublicDashboardToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, orgId, publicDashboardUid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetOrgIdByAccessToken provides a mock function with given fields: ctx, accessToken
 func (_m *FakePublicDashboardStore) GetOrgIdByAccessToken(ctx context.Context, accessToken string) (int64, error) {
 	ret := _m.Called(ctx, accessToken)
@@ -259,6 +379,27 @@ func (_m *FakePublicDashboardStore) GetOrgIdByAccessToken(ctx context.Context, a
 	return r0, r1
 }
 
+// RevokeToken provides a mock function with given fields: ctx, orgId, publicDashboardUid, uid
+func (_m *FakePublicDashboardStore) RevokeToken(ctx context.Context, orgId int64, publicDashboardUid string, uid string) (int64, error) {
+	ret := _m.Called(ctx, orgId, publicDashboardUid, uid)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) int64); ok {
+		r0 = rf(ctx, orgId, publicDashboardUid, uid)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, orgId, publicDashboardUid, uid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, cmd
 func (_m *FakePublicDashboardStore) Update(ctx context.Context, cmd models.SavePublicDashboardCommand) (int64, error) {
 	ret := _m.Called(ctx, cmd)
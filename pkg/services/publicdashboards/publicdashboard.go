@@ -2,6 +2,7 @@ package publicdashboards
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/api/dtos"
@@ -36,6 +37,14 @@ type Service interface {
 
 	ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error)
 	ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error)
+
+	FindTokens(ctx context.Context, orgId int64, dashboardUid string, publicDashboardUid string) ([]*PublicDashboardToken, error)
+	CreateToken(ctx context.Context, u *user.SignedInUser, dto *CreatePublicDashboardTokenDTO) (*PublicDashboardToken, error)
+	RevokeToken(ctx context.Context, orgId int64, dashboardUid string, publicDashboardUid string, uid string) error
+
+	RecordAccess(ctx context.Context, cmd RecordAccessCommand) error
+	FindAccessLogs(ctx context.Context, query AccessLogQuery) ([]*PublicDashboardAccessLog, error)
+	DeleteExpiredAccessLogs(ctx context.Context) (int64, error)
 }
 
 // ServiceWrapper these methods have different behavior between OSS and Enterprise. The latter would call the OSS service first
@@ -61,4 +70,13 @@ type Store interface {
 	FindByDashboardFolder(ctx context.Context, dashboard *dashboards.Dashboard) ([]*PublicDashboard, error)
 	ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error)
 	ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error)
+
+	FindTokens(ctx context.Context, orgId int64, publicDashboardUid string) ([]*PublicDashboardToken, error)
+	FindTokenByAccessToken(ctx context.Context, accessToken string) (*PublicDashboardToken, error)
+	CreateToken(ctx context.Context, token *PublicDashboardToken) error
+	RevokeToken(ctx context.Context, orgId int64, publicDashboardUid string, uid string) (int64, error)
+
+	CreateAccessLog(ctx context.Context, entry *PublicDashboardAccessLog) error
+	FindAccessLogs(ctx context.Context, query AccessLogQuery) ([]*PublicDashboardAccessLog, error)
+	DeleteAccessLogsOlderThan(ctx context.Context, olderThan time.Time) (int64, error)
 }
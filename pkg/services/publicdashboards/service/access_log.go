@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+const maxAccessLogLimit = 1000
+
+// RecordAccess adds an entry to the access log for the public dashboard of the access token.
+// Nothing is recorded when the access log is disabled or the access token is unknown.
+func (pd *PublicDashboardServiceImpl) RecordAccess(ctx context.Context, cmd RecordAccessCommand) error {
+	if pd.cfg == nil || !pd.cfg.PublicDashboards.AccessLogEnabled {
+		return nil
+	}
+
+	entry := &PublicDashboardAccessLog{
+		Action:  cmd.Action,
+		PanelId: cmd.PanelId,
+		IP:      cmd.IP,
+		Created: time.Now(),
+	}
+
+	token, err := pd.store.FindTokenByAccessToken(ctx, cmd.AccessToken)
+	if err != nil {
+		return ErrInternalServerError.Errorf("RecordAccess: failed to find token: %w", err)
+	}
+
+	if token != nil {
+		entry.OrgId = token.OrgId
+		entry.PublicDashboardUid = token.PublicDashboardUid
+		entry.TokenUid = token.Uid
+	} else {
+		pubdash, err := pd.store.FindByAccessToken(ctx, cmd.AccessToken)
+		if err != nil {
+			return ErrInternalServerError.Errorf("RecordAccess: failed to find public dashboard: %w", err)
+		}
+		if pubdash == nil {
+			return nil
+		}
+		entry.OrgId = pubdash.OrgId
+		entry.PublicDashboardUid = pubdash.Uid
+	}
+
+	if err := pd.store.CreateAccessLog(ctx, entry); err != nil {
+		return ErrInternalServerError.Errorf("RecordAccess: failed to save access log: %w", err)
+	}
+	return nil
+}
+
+// FindAccessLogs returns the access log of a public dashboard
+func (pd *PublicDashboardServiceImpl) FindAccessLogs(ctx context.Context, query AccessLogQuery) ([]*PublicDashboardAccessLog, error) {
+	if _, err := pd.findForDashboard(ctx, query.OrgId, query.DashboardUid, query.PublicDashboardUid); err != nil {
+		return nil, err
+	}
+
+	if query.Limit <= 0 || query.Limit > maxAccessLogLimit {
+		query.Limit = maxAccessLogLimit
+	}
+
+	entries, err := pd.store.FindAccessLogs(ctx, query)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("FindAccessLogs: failed to find access log: %w", err)
+	}
+	return entries, nil
+}
+
+// DeleteExpiredAccessLogs removes the entries older than the configured retention, it is called by the cleanup service
+func (pd *PublicDashboardServiceImpl) DeleteExpiredAccessLogs(ctx context.Context) (int64, error) {
+	if pd.cfg == nil || pd.cfg.PublicDashboards.AccessLogMaxAge <= 0 {
+		return 0, nil
+	}
+
+	return pd.store.DeleteAccessLogsOlderThan(ctx, time.Now().Add(-pd.cfg.PublicDashboards.AccessLogMaxAge))
+}
@@ -166,6 +166,10 @@ func (pd *PublicDashboardServiceImpl) Create(ctx context.Context, u *user.Signed
 		dto.PublicDashboard.TimeSettings = &TimeSettings{}
 	}
 
+	if dto.PublicDashboard.VariableSettings == nil {
+		dto.PublicDashboard.VariableSettings = &VariableSettings{}
+	}
+
 	if dto.PublicDashboard.Share == "" {
 		dto.PublicDashboard.Share = PublicShareType
 	}
@@ -190,6 +194,8 @@ func (pd *PublicDashboardServiceImpl) Create(ctx context.Context, u *user.Signed
 			TimeSelectionEnabled: dto.PublicDashboard.TimeSelectionEnabled,
 			TimeSettings:         dto.PublicDashboard.TimeSettings,
 			Share:                dto.PublicDashboard.Share,
+			MaxTimeRange:         dto.PublicDashboard.MaxTimeRange,
+			VariableSettings:     dto.PublicDashboard.VariableSettings,
 			CreatedBy:            dto.UserId,
 			CreatedAt:            time.Now(),
 			AccessToken:          accessToken,
@@ -245,6 +251,10 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 		dto.PublicDashboard.TimeSettings = &TimeSettings{}
 	}
 
+	if dto.PublicDashboard.VariableSettings == nil {
+		dto.PublicDashboard.VariableSettings = &VariableSettings{}
+	}
+
 	if dto.PublicDashboard.Share == "" {
 		dto.PublicDashboard.Share = existingPubdash.Share
 	}
@@ -258,6 +268,8 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 			TimeSelectionEnabled: dto.PublicDashboard.TimeSelectionEnabled,
 			TimeSettings:         dto.PublicDashboard.TimeSettings,
 			Share:                dto.PublicDashboard.Share,
+			MaxTimeRange:         dto.PublicDashboard.MaxTimeRange,
+			VariableSettings:     dto.PublicDashboard.VariableSettings,
 			UpdatedBy:            dto.UserId,
 			UpdatedAt:            time.Now(),
 		},
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const maxTokenNameLength = 190
+
+// FindTokens returns the named tokens of a public dashboard
+func (pd *PublicDashboardServiceImpl) FindTokens(ctx context.Context, orgId int64, dashboardUid string, publicDashboardUid string) ([]*PublicDashboardToken, error) {
+	if _, err := pd.findForDashboard(ctx, orgId, dashboardUid, publicDashboardUid); err != nil {
+		return nil, err
+	}
+
+	tokens, err := pd.store.FindTokens(ctx, orgId, publicDashboardUid)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("FindTokens: failed to find tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// CreateToken adds a named token to a public dashboard. The token can be used like the public dashboard
+// access token until it expires or is revoked
+func (pd *PublicDashboardServiceImpl) CreateToken(ctx context.Context, u *user.SignedInUser, dto *CreatePublicDashboardTokenDTO) (*PublicDashboardToken, error) {
+	if dto.Name == "" || len(dto.Name) > maxTokenNameLength {
+		return nil, ErrInvalidTokenName.Errorf("CreateToken: invalid token name")
+	}
+
+	now := time.Now()
+	if dto.ExpiresAt != nil && !dto.ExpiresAt.After(now) {
+		return nil, ErrInvalidTokenExpiration.Errorf("CreateToken: token expiration is in the past")
+	}
+
+	if _, err := pd.findForDashboard(ctx, u.OrgID, dto.DashboardUid, dto.PublicDashboardUid); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := pd.NewPublicDashboardAccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token := &PublicDashboardToken{
+		Uid:                util.GenerateShortUID(),
+		PublicDashboardUid: dto.PublicDashboardUid,
+		OrgId:              u.OrgID,
+		Name:               dto.Name,
+		AccessToken:        accessToken,
+		ExpiresAt:          dto.ExpiresAt,
+		CreatedBy:          u.UserID,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if err := pd.store.CreateToken(ctx, token); err != nil {
+		return nil, ErrInternalServerError.Errorf("CreateToken: failed to create token: %w", err)
+	}
+
+	pd.log.Info("Public dashboard token created", "publicDashboardUid", dto.PublicDashboardUid, "tokenUid", token.Uid, "user", u.Login)
+	return token, nil
+}
+
+// RevokeToken disables a named token, other tokens of the public dashboard keep working
+func (pd *PublicDashboardServiceImpl) RevokeToken(ctx context.Context, orgId int64, dashboardUid string, publicDashboardUid string, uid string) error {
+	if _, err := pd.findForDashboard(ctx, orgId, dashboardUid, publicDashboardUid); err != nil {
+		return err
+	}
+
+	affectedRows, err := pd.store.RevokeToken(ctx, orgId, publicDashboardUid, uid)
+	if err != nil {
+		return ErrInternalServerError.Errorf("RevokeToken: failed to revoke token: %w", err)
+	}
+	if affectedRows == 0 {
+		return ErrTokenNotFound.Errorf("RevokeToken: token not found by uid: %s", uid)
+	}
+
+	pd.log.Info("Public dashboard token revoked", "publicDashboardUid", publicDashboardUid, "tokenUid", uid)
+	return nil
+}
+
+// findForDashboard returns the public dashboard by uid if it belongs to the dashboard. The permissions
+// of the requests are checked on the dashboard, so they don't apply to public dashboards of other dashboards
+func (pd *PublicDashboardServiceImpl) findForDashboard(ctx context.Context, orgId int64, dashboardUid string, uid string) (*PublicDashboard, error) {
+	pubdash, err := pd.store.Find(ctx, uid)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("failed to find public dashboard by uid: %s: %w", uid, err)
+	}
+	if pubdash == nil || pubdash.OrgId != orgId || pubdash.DashboardUid != dashboardUid {
+		return nil, ErrPublicDashboardNotFound.Errorf("public dashboard not found by uid: %s", uid)
+	}
+	return pubdash, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestCreateToken(t *testing.T) {
+	pubdash := &PublicDashboard{Uid: "pubdash", DashboardUid: "dash", OrgId: 1}
+	user := *SignedInUser
+	user.OrgID = 1
+
+	setup := func() (*PublicDashboardServiceImpl, *FakePublicDashboardStore) {
+		fakeStore := &FakePublicDashboardStore{}
+		fakeStore.On("Find", mock.Anything, "pubdash").Return(pubdash, nil)
+		fakeStore.On("Find", mock.Anything, mock.Anything).Return(nil, nil)
+		fakeStore.On("FindByAccessToken", mock.Anything, mock.Anything).Return(nil, nil)
+		return &PublicDashboardServiceImpl{log: log.New("test.logger"), store: fakeStore}, fakeStore
+	}
+
+	t.Run("creates a token for the public dashboard", func(t *testing.T) {
+		service, fakeStore := setup()
+		fakeStore.On("CreateToken", mock.Anything, mock.AnythingOfType("*models.PublicDashboardToken")).Return(nil)
+
+		expiresAt := time.Now().Add(time.Hour)
+		token, err := service.CreateToken(context.Background(), &user, &CreatePublicDashboardTokenDTO{
+			DashboardUid:       "dash",
+			PublicDashboardUid: "pubdash",
+			Name:               "partners",
+			ExpiresAt:          &expiresAt,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "partners", token.Name)
+		assert.Equal(t, "pubdash", token.PublicDashboardUid)
+		assert.NotEmpty(t, token.Uid)
+		assert.NotEmpty(t, token.AccessToken)
+		assert.False(t, token.IsExpired(time.Now()))
+		assert.True(t, token.IsExpired(expiresAt))
+	})
+
+	t.Run("returns error when name is empty", func(t *testing.T) {
+		service, _ := setup()
+		_, err := service.CreateToken(context.Background(), &user, &CreatePublicDashboardTokenDTO{DashboardUid: "dash", PublicDashboardUid: "pubdash"})
+		require.ErrorIs(t, err, ErrInvalidTokenName)
+	})
+
+	t.Run("returns error when expiration is in the past", func(t *testing.T) {
+		service, _ := setup()
+		expiresAt := time.Now().Add(-time.Hour)
+		_, err := service.CreateToken(context.Background(), &user, &CreatePublicDashboardTokenDTO{
+			DashboardUid:       "dash",
+			PublicDashboardUid: "pubdash",
+			Name:               "partners",
+			ExpiresAt:          &expiresAt,
+		})
+		require.ErrorIs(t, err, ErrInvalidTokenExpiration)
+	})
+
+	t.Run("returns error when public dashboard belongs to another dashboard", func(t *testing.T) {
+		service, _ := setup()
+		_, err := service.CreateToken(context.Background(), &user, &CreatePublicDashboardTokenDTO{DashboardUid: "other", PublicDashboardUid: "pubdash", Name: "partners"})
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+	})
+}
+
+func TestRevokeToken(t *testing.T) {
+	fakeStore := &FakePublicDashboardStore{}
+	fakeStore.On("Find", mock.Anything, "pubdash").Return(&PublicDashboard{Uid: "pubdash", DashboardUid: "dash", OrgId: 1}, nil)
+	fakeStore.On("RevokeToken", mock.Anything, int64(1), "pubdash", "token").Return(int64(1), nil)
+	fakeStore.On("RevokeToken", mock.Anything, int64(1), "pubdash", mock.Anything).Return(int64(0), nil)
+	service := &PublicDashboardServiceImpl{log: log.New("test.logger"), store: fakeStore}
+
+	err := service.RevokeToken(context.Background(), 1, "dash", "pubdash", "token")
+	require.NoError(t, err)
+
+	err = service.RevokeToken(context.Background(), 1, "dash", "pubdash", "missing")
+	require.ErrorIs(t, err, ErrTokenNotFound)
+}
+
+func TestRecordAccess(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.PublicDashboards.AccessLogEnabled = true
+
+	t.Run("records the token used", func(t *testing.T) {
+		fakeStore := &FakePublicDashboardStore{}
+		fakeStore.On("FindTokenByAccessToken", mock.Anything, "named").Return(&PublicDashboardToken{Uid: "token", PublicDashboardUid: "pubdash", OrgId: 1}, nil)
+		fakeStore.On("CreateAccessLog", mock.Anything, mock.MatchedBy(func(entry *PublicDashboardAccessLog) bool {
+			return entry.TokenUid == "token" && entry.PublicDashboardUid == "pubdash" && entry.OrgId == 1 &&
+				entry.Action == AccessActionQuery && entry.PanelId == 2 && entry.IP == "10.0.0.1"
+		})).Return(nil)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), cfg: cfg, store: fakeStore}
+
+		err := service.RecordAccess(context.Background(), RecordAccessCommand{AccessToken: "named", Action: AccessActionQuery, PanelId: 2, IP: "10.0.0.1"})
+		require.NoError(t, err)
+		fakeStore.AssertExpectations(t)
+	})
+
+	t.Run("records the public dashboard access token", func(t *testing.T) {
+		fakeStore := &FakePublicDashboardStore{}
+		fakeStore.On("FindTokenByAccessToken", mock.Anything, "main").Return(nil, nil)
+		fakeStore.On("FindByAccessToken", mock.Anything, "main").Return(&PublicDashboard{Uid: "pubdash", OrgId: 1}, nil)
+		fakeStore.On("CreateAccessLog", mock.Anything, mock.MatchedBy(func(entry *PublicDashboardAccessLog) bool {
+			return entry.TokenUid == "" && entry.PublicDashboardUid == "pubdash" && entry.Action == AccessActionView
+		})).Return(nil)
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), cfg: cfg, store: fakeStore}
+
+		err := service.RecordAccess(context.Background(), RecordAccessCommand{AccessToken: "main", Action: AccessActionView})
+		require.NoError(t, err)
+		fakeStore.AssertExpectations(t)
+	})
+
+	t.Run("nothing is recorded when the access log is disabled", func(t *testing.T) {
+		fakeStore := &FakePublicDashboardStore{}
+		service := &PublicDashboardServiceImpl{log: log.New("test.logger"), cfg: setting.NewCfg(), store: fakeStore}
+
+		err := service.RecordAccess(context.Background(), RecordAccessCommand{AccessToken: "main", Action: AccessActionView})
+		require.NoError(t, err)
+		fakeStore.AssertNotCalled(t, "CreateAccessLog", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/query/templatevars"
+)
+
+// resolveTemplateVariables returns the values of the dashboard variables for a query. Values sent by the viewer
+// are only used when the public dashboard allows them, otherwise the request fails.
+func resolveTemplateVariables(dashboard *simplejson.Json, publicDashboard *models.PublicDashboard, requested map[string][]string) (map[string][]string, error) {
+	variables := templatevars.FromDashboard(dashboard)
+	settings := publicDashboard.VariableSettings
+
+	for name, values := range requested {
+		variable, ok := variables[name]
+		if !ok {
+			return nil, models.ErrInvalidTemplateVariable.Errorf("resolveTemplateVariables: unknown template variable %s", name)
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		for _, value := range values {
+			// the saved value is always accepted so viewers can send the state of all variables
+			if templatevars.Contains(variable.Current, value) {
+				continue
+			}
+			if !settings.IsValueAllowed(name, value) {
+				return nil, models.ErrInvalidTemplateVariable.Errorf("resolveTemplateVariables: value not allowed for template variable %s", name)
+			}
+		}
+		variable.Current = values
+	}
+
+	resolved := make(map[string][]string, len(variables))
+	for name, variable := range variables {
+		resolved[name] = expandAllValue(variable, settings)
+	}
+	return resolved, nil
+}
+
+// expandAllValue replaces `All` with the custom all value or the values that can be selected
+func expandAllValue(variable *templatevars.Variable, settings *models.VariableSettings) []string {
+	if !templatevars.Contains(variable.Current, templatevars.AllValue) || variable.AllValue != "" || !settings.IsAllowed(variable.Name) {
+		return variable.Values()
+	}
+
+	options := make([]string, 0)
+	for _, value := range settings.Allowed[variable.Name] {
+		if value != templatevars.AllValue {
+			options = append(options, value)
+		}
+	}
+	return options
+}
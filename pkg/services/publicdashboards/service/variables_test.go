@@ -0,0 +1,85 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+const dashboardWithTemplateVariables = `
+{
+  "templating": {
+    "list": [
+      {
+        "name": "env",
+        "type": "custom",
+        "current": { "text": "prod", "value": "prod" },
+        "options": [
+          { "text": "prod", "value": "prod" },
+          { "text": "dev", "value": "dev" },
+          { "text": "test", "value": "test" }
+        ]
+      },
+      {
+        "name": "host",
+        "type": "query",
+        "multi": true,
+        "includeAll": true,
+        "current": { "text": ["All"], "value": ["$__all"] },
+        "options": [
+          { "text": "All", "value": "$__all" },
+          { "text": "a", "value": "a" },
+          { "text": "b", "value": "b" },
+          { "text": "c", "value": "c" }
+        ]
+      }
+    ]
+  }
+}`
+
+func TestResolveTemplateVariables(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+
+	pubdash := &PublicDashboard{
+		VariableSettings: &VariableSettings{
+			Allowed: map[string][]string{"host": {"a", "b"}},
+		},
+	}
+
+	t.Run("uses dashboard values when no variables are sent", func(t *testing.T) {
+		variables, err := resolveTemplateVariables(dashboard, pubdash, nil)
+		require.NoError(t, err)
+		require.Equal(t, map[string][]string{"env": {"prod"}, "host": {"a", "b"}}, variables)
+	})
+
+	t.Run("uses allowed values", func(t *testing.T) {
+		variables, err := resolveTemplateVariables(dashboard, pubdash, map[string][]string{"env": {"prod"}, "host": {"b"}})
+		require.NoError(t, err)
+		require.Equal(t, map[string][]string{"env": {"prod"}, "host": {"b"}}, variables)
+	})
+
+	t.Run("returns error when value is not allowed", func(t *testing.T) {
+		_, err := resolveTemplateVariables(dashboard, pubdash, map[string][]string{"host": {"c"}})
+		require.ErrorIs(t, err, ErrInvalidTemplateVariable)
+	})
+
+	t.Run("returns error when variable is not allowed", func(t *testing.T) {
+		_, err := resolveTemplateVariables(dashboard, pubdash, map[string][]string{"env": {"dev"}})
+		require.ErrorIs(t, err, ErrInvalidTemplateVariable)
+	})
+
+	t.Run("returns error when variable is not in the dashboard", func(t *testing.T) {
+		_, err := resolveTemplateVariables(dashboard, pubdash, map[string][]string{"other": {"x"}})
+		require.ErrorIs(t, err, ErrInvalidTemplateVariable)
+	})
+
+	t.Run("all is expanded to dashboard options when variable is not allowed", func(t *testing.T) {
+		variables, err := resolveTemplateVariables(dashboard, &PublicDashboard{}, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b", "c"}, variables["host"])
+	})
+}
@@ -2,6 +2,7 @@ package validation
 
 import (
 	"github.com/google/uuid"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/tsdb/legacydata"
 	"github.com/grafana/grafana/pkg/util"
@@ -13,6 +14,21 @@ func ValidatePublicDashboard(dto *SavePublicDashboardDTO) error {
 		return ErrInvalidShareType.Errorf("ValidateSavePublicDashboard: invalid share type")
 	}
 
+	if dto.PublicDashboard.MaxTimeRange != "" {
+		maxTimeRange, err := gtime.ParseDuration(dto.PublicDashboard.MaxTimeRange)
+		if err != nil || maxTimeRange <= 0 {
+			return ErrInvalidMaxTimeRange.Errorf("ValidateSavePublicDashboard: invalid max time range %q", dto.PublicDashboard.MaxTimeRange)
+		}
+	}
+
+	if dto.PublicDashboard.VariableSettings != nil {
+		for name := range dto.PublicDashboard.VariableSettings.Allowed {
+			if name == "" {
+				return ErrInvalidTemplateVariable.Errorf("ValidateSavePublicDashboard: template variable name is empty")
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -28,14 +44,24 @@ func ValidateQueryPublicDashboardRequest(req PublicDashboardQueryDTO, pd *Public
 	if pd.TimeSelectionEnabled {
 		timeRange := legacydata.NewDataTimeRange(req.TimeRange.From, req.TimeRange.To)
 
-		_, err := timeRange.ParseFrom()
+		from, err := timeRange.ParseFrom()
 		if err != nil {
 			return ErrInvalidTimeRange.Errorf("ValidateQueryPublicDashboardRequest: time range from is invalid")
 		}
-		_, err = timeRange.ParseTo()
+		to, err := timeRange.ParseTo()
 		if err != nil {
 			return ErrInvalidTimeRange.Errorf("ValidateQueryPublicDashboardRequest: time range to is invalid")
 		}
+
+		if pd.MaxTimeRange != "" {
+			maxTimeRange, err := gtime.ParseDuration(pd.MaxTimeRange)
+			if err != nil {
+				return ErrInvalidMaxTimeRange.Errorf("ValidateQueryPublicDashboardRequest: invalid max time range %q", pd.MaxTimeRange)
+			}
+			if to.Sub(from) > maxTimeRange {
+				return ErrTimeRangeExceedsMax.Errorf("ValidateQueryPublicDashboardRequest: time range exceeds max time range %s", pd.MaxTimeRange)
+			}
+		}
 	}
 
 	return nil
@@ -22,6 +22,29 @@ func TestValidatePublicDashboard(t *testing.T) {
 		err := ValidatePublicDashboard(dto)
 		require.Error(t, err)
 	})
+
+	t.Run("Returns no error when max time range is valid", func(t *testing.T) {
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", OrgId: 1, UserId: 1, PublicDashboard: &PublicDashboard{MaxTimeRange: "7d"}}
+
+		err := ValidatePublicDashboard(dto)
+		require.NoError(t, err)
+	})
+
+	t.Run("Returns error when max time range is invalid", func(t *testing.T) {
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", OrgId: 1, UserId: 1, PublicDashboard: &PublicDashboard{MaxTimeRange: "invalid"}}
+
+		err := ValidatePublicDashboard(dto)
+		require.Error(t, err)
+	})
+
+	t.Run("Returns error when allowed template variable has no name", func(t *testing.T) {
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", OrgId: 1, UserId: 1, PublicDashboard: &PublicDashboard{
+			VariableSettings: &VariableSettings{Allowed: map[string][]string{"": {"a"}}},
+		}}
+
+		err := ValidatePublicDashboard(dto)
+		require.Error(t, err)
+	})
 }
 
 func TestValidateQueryPublicDashboardRequest(t *testing.T) {
@@ -129,6 +152,38 @@ func TestValidateQueryPublicDashboardRequest(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Returns no error when time range is within max time range",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					TimeRange: TimeSettings{
+						From: "now-6h",
+						To:   "now",
+					},
+				},
+				pd: &PublicDashboard{
+					TimeSelectionEnabled: true,
+					MaxTimeRange:         "1d",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Returns validation error when time range exceeds max time range",
+			args: args{
+				req: PublicDashboardQueryDTO{
+					TimeRange: TimeSettings{
+						From: "now-7d",
+						To:   "now",
+					},
+				},
+				pd: &PublicDashboard{
+					TimeSelectionEnabled: true,
+					MaxTimeRange:         "1d",
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
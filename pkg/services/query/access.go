@@ -0,0 +1,63 @@
+package query
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/expr"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/tsdb/grafanads"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// Usage is where a data source query comes from. It decides which data source
+// permissions are required to run it.
+type Usage string
+
+const (
+	// UsageDashboard is a query from a dashboard the user can read.
+	UsageDashboard Usage = "dashboard"
+	// UsageExplore is any other query sent by a user, e.g. from Explore.
+	UsageExplore Usage = "explore"
+)
+
+type usageKey struct{}
+
+// WithUsage sets the usage of the queries run with the returned context. Data
+// source permissions are only checked for queries with a usage, other callers
+// of the query service are expected to authorize the queries themselves.
+func WithUsage(ctx context.Context, usage Usage) context.Context {
+	return context.WithValue(ctx, usageKey{}, usage)
+}
+
+func usageFromContext(ctx context.Context) Usage {
+	usage, _ := ctx.Value(usageKey{}).(Usage)
+	return usage
+}
+
+// checkDatasourceAccess verifies that the user has the permissions needed to
+// query the data source for the usage of the request.
+func (s *ServiceImpl) checkDatasourceAccess(ctx context.Context, user *user.SignedInUser, ds *datasources.DataSource) error {
+	usage := usageFromContext(ctx)
+	if usage == "" || user == nil || ac.IsDisabled(s.cfg) {
+		return nil
+	}
+	// expressions and the built-in Grafana data source do not access external data
+	if expr.IsDataSource(ds.UID) || ds.UID == grafanads.DatasourceUID {
+		return nil
+	}
+
+	scope := datasources.ScopeProvider.GetResourceScopeUID(ds.UID)
+	public := map[string]interface{}{"DatasourceUID": ds.UID}
+	permissions := user.Permissions[user.OrgID]
+
+	if !ac.EvalPermission(datasources.ActionQuery, scope).Evaluate(permissions) {
+		return ErrQueryAccessDenied.Build(errutil.TemplateData{Public: public})
+	}
+	if usage == UsageExplore && s.cfg.DataSourcePermissionsEnforced &&
+		!ac.EvalPermission(datasources.ActionExplore, scope).Evaluate(permissions) {
+		return ErrExploreAccessDenied.Build(errutil.TemplateData{Public: public})
+	}
+	return nil
+}
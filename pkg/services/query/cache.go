@@ -0,0 +1,248 @@
+package query
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+const (
+	// HeaderCacheStatus is set on query responses to HIT, MISS or BYPASS when the query cache was used
+	HeaderCacheStatus = "X-Cache"
+	// HeaderCacheSkip can be set to "true" on query requests to bypass the query cache
+	HeaderCacheSkip = "X-Cache-Skip"
+)
+
+// CacheStatus is the query cache status of a request.
+type CacheStatus string
+
+const (
+	CacheStatusHit    CacheStatus = "HIT"
+	CacheStatusMiss   CacheStatus = "MISS"
+	CacheStatusBypass CacheStatus = "BYPASS"
+)
+
+// cacheStatusPriority decides the status of a request querying several data
+// sources, a single miss means that the response was not served from the cache.
+var cacheStatusPriority = map[CacheStatus]int{
+	CacheStatusHit:    1,
+	CacheStatusBypass: 2,
+	CacheStatusMiss:   3,
+}
+
+const queryCacheKeyPrefix = "query-cache:"
+
+type queryCacheKey struct{}
+
+type queryCacheState struct {
+	skip bool
+
+	mu     sync.Mutex
+	status CacheStatus
+}
+
+func (s *queryCacheState) record(status CacheStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cacheStatusPriority[status] > cacheStatusPriority[s.status] {
+		s.status = status
+	}
+}
+
+// WithQueryCache enables the query result cache for the queries run with the
+// returned context. Queries are never cached for other callers of the query
+// service, e.g. alerting, which must always evaluate fresh data. If skip is
+// true, the cache is bypassed but the cache status is still recorded.
+func WithQueryCache(ctx context.Context, skip bool) context.Context {
+	return context.WithValue(ctx, queryCacheKey{}, &queryCacheState{skip: skip})
+}
+
+// CacheStatusFromContext returns the query cache status of the queries run with
+// a context returned by WithQueryCache, or an empty status if the cache was not
+// used.
+func CacheStatusFromContext(ctx context.Context) CacheStatus {
+	state, ok := ctx.Value(queryCacheKey{}).(*queryCacheState)
+	if !ok {
+		return ""
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return state.status
+}
+
+func queryCacheFromContext(ctx context.Context) *queryCacheState {
+	state, _ := ctx.Value(queryCacheKey{}).(*queryCacheState)
+	return state
+}
+
+// cacheTTL returns how long the query results of the data source can be
+// cached, or 0 if they must not be cached. The default TTL can be overridden
+// with the queryCacheTTL field of the data source JSON data, where "0" disables
+// caching for the data source.
+func (s *ServiceImpl) cacheTTL(ds *datasources.DataSource) time.Duration {
+	if !s.cfg.QueryCaching.Enabled || s.remoteCache == nil {
+		return 0
+	}
+	// responses depend on the identity of the user when it is forwarded to the data source
+	if s.forwardsIdentity(ds) {
+		return 0
+	}
+	if ds.JsonData == nil {
+		return s.cfg.QueryCaching.TTL
+	}
+	if v := ds.JsonData.Get("queryCacheTTL").MustString(); v != "" {
+		ttl, err := gtime.ParseDuration(v)
+		if err != nil {
+			s.log.Warn("Invalid query cache TTL, query caching is disabled for the data source", "datasource", ds.UID, "ttl", v, "error", err)
+			return 0
+		}
+		return ttl
+	}
+	return s.cfg.QueryCaching.TTL
+}
+
+// queryDataCached returns the cached response of the queries if there is one
+// and otherwise runs the queries with queryFn, caching their response.
+func (s *ServiceImpl) queryDataCached(ctx context.Context, skipCache bool, ds *datasources.DataSource, queries []parsedQuery, queryFn func() (*backend.QueryDataResponse, error)) (*backend.QueryDataResponse, error) {
+	state := queryCacheFromContext(ctx)
+	if state == nil {
+		return queryFn()
+	}
+
+	ttl := s.cacheTTL(ds)
+	if ttl <= 0 || skipCache || state.skip {
+		state.record(CacheStatusBypass)
+		return queryFn()
+	}
+
+	// a time range shorter than the TTL would share its cache entry with
+	// ranges that barely overlap it
+	if shorterThan(queries, ttl) {
+		state.record(CacheStatusBypass)
+		return queryFn()
+	}
+
+	key, err := queryCacheKeyFor(ds, queries, ttl)
+	if err != nil {
+		s.log.Warn("Failed to build query cache key", "datasource", ds.UID, "error", err)
+		state.record(CacheStatusBypass)
+		return queryFn()
+	}
+
+	if value, err := s.remoteCache.Get(ctx, key); err == nil {
+		resp := &backend.QueryDataResponse{}
+		if err := json.Unmarshal(value, resp); err == nil {
+			state.record(CacheStatusHit)
+			return resp, nil
+		}
+		s.log.Warn("Failed to decode cached query response", "datasource", ds.UID, "error", err)
+	} else if !errors.Is(err, remotecache.ErrCacheItemNotFound) {
+		s.log.Warn("Failed to read query cache", "datasource", ds.UID, "error", err)
+	}
+
+	state.record(CacheStatusMiss)
+	resp, err := queryFn()
+	if err != nil || !isCacheable(resp) {
+		return resp, err
+	}
+
+	value, err := json.Marshal(resp)
+	if err != nil {
+		s.log.Warn("Failed to encode query response for the query cache", "datasource", ds.UID, "error", err)
+		return resp, nil
+	}
+	if len(value) > s.cfg.QueryCaching.MaxValueSize {
+		s.log.Debug("Query response is too large to be cached", "datasource", ds.UID, "size", len(value))
+		return resp, nil
+	}
+	if err := s.remoteCache.Set(ctx, key, value, ttl); err != nil {
+		s.log.Warn("Failed to write query cache", "datasource", ds.UID, "error", err)
+	}
+	return resp, nil
+}
+
+// isCacheable returns false for responses containing errors, they should be
+// retried on the next request.
+func isCacheable(resp *backend.QueryDataResponse) bool {
+	if resp == nil {
+		return false
+	}
+	for _, r := range resp.Responses {
+		if r.Error != nil || r.Status >= backend.StatusBadRequest {
+			return false
+		}
+	}
+	return true
+}
+
+// queryCacheKeyFor returns the cache key of the queries. The start of the time
+// ranges is aligned to the TTL so that requests with relative time ranges, e.g.
+// the same dashboard opened by many users, share a cache entry until it
+// expires, while their exact span keeps ranges of different lengths apart. The
+// data source update time is included so that editing the data source
+// invalidates its cached responses.
+func queryCacheKeyFor(ds *datasources.DataSource, queries []parsedQuery, ttl time.Duration) (string, error) {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%d\x00%s\x00%d\x00", ds.OrgID, ds.UID, ds.Updated.UnixNano())
+	for _, pq := range queries {
+		normalized, err := normalizeQuery(pq.query.JSON)
+		if err != nil {
+			return "", err
+		}
+		_, _ = fmt.Fprintf(h, "%s\x00%s\x00%d\x00%d\x00",
+			pq.query.RefID, normalized,
+			pq.query.TimeRange.From.Truncate(ttl).UnixMilli(),
+			pq.query.TimeRange.Duration().Milliseconds(),
+		)
+	}
+	return queryCacheKeyPrefix + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func shorterThan(queries []parsedQuery, ttl time.Duration) bool {
+	for _, pq := range queries {
+		if pq.query.TimeRange.Duration() < ttl {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardsIdentity returns true if the identity of the user running the
+// queries is sent to the data source, with the X-Grafana-User header, the team
+// HTTP headers, the cookies of the user or their OAuth token. The responses of
+// the data source can then differ between users.
+func (s *ServiceImpl) forwardsIdentity(ds *datasources.DataSource) bool {
+	if s.cfg.SendUserHeader {
+		return true
+	}
+	if ds.JsonData == nil {
+		return false
+	}
+	return ds.JsonData.Get("oauthPassThru").MustBool() ||
+		len(ds.JsonData.Get("keepCookies").MustArray()) > 0 ||
+		ds.JsonData.Get("teamHttpHeaders").Interface() != nil
+}
+
+// normalizeQuery removes the fields of the query model that do not change its
+// result and sorts its keys.
+func normalizeQuery(model []byte) ([]byte, error) {
+	m := map[string]interface{}{}
+	if err := json.Unmarshal(model, &m); err != nil {
+		return nil, err
+	}
+	delete(m, "datasource")
+	delete(m, "datasourceId")
+	delete(m, "requestId")
+	return json.Marshal(m)
+}
@@ -0,0 +1,182 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// queryDataCoalesced runs queryFn, sharing its response with the identical
+// queries to the data source that are already in flight when request
+// coalescing is enabled.
+func (s *ServiceImpl) queryDataCoalesced(ctx context.Context, ds *datasources.DataSource, queries []parsedQuery, queryFn func() (*backend.QueryDataResponse, error)) (*backend.QueryDataResponse, error) {
+	if !s.cfg.QueryConcurrency.CoalesceRequests || s.forwardsIdentity(ds) {
+		return queryFn()
+	}
+
+	// without a TTL the time ranges are not aligned, only identical queries share a key
+	key, err := queryCacheKeyFor(ds, queries, 0)
+	if err != nil {
+		s.log.Warn("Failed to build query key, the query is not coalesced", "datasource", ds.UID, "error", err)
+		return queryFn()
+	}
+
+	ch := s.inFlight.DoChan(key, func() (interface{}, error) {
+		return queryFn()
+	})
+	select {
+	case res := <-ch:
+		// the request running the shared query was canceled, this one has to
+		// run it again
+		if res.Shared && ctx.Err() == nil && (errors.Is(res.Err, context.Canceled) || errors.Is(res.Err, context.DeadlineExceeded)) {
+			return queryFn()
+		}
+		resp, _ := res.Val.(*backend.QueryDataResponse)
+		if !res.Shared || res.Err != nil {
+			return resp, res.Err
+		}
+		// every caller gets its own copy, the response can be modified afterwards
+		return copyQueryDataResponse(resp)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// copyQueryDataResponse returns a deep copy of resp, encoded the same way as
+// the cached responses.
+func copyQueryDataResponse(resp *backend.QueryDataResponse) (*backend.QueryDataResponse, error) {
+	if resp == nil {
+		return nil, nil
+	}
+	value, err := json.Marshal(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy the coalesced query response: %w", err)
+	}
+	cp := &backend.QueryDataResponse{}
+	if err := json.Unmarshal(value, cp); err != nil {
+		return nil, fmt.Errorf("failed to copy the coalesced query response: %w", err)
+	}
+	return cp, nil
+}
+
+// queryDataLimited runs queryFn once the concurrency limits of the user and of
+// the data source allow it. Queries over a limit wait in a queue until the
+// queue timeout and then fail with ErrTooManyQueries.
+func (s *ServiceImpl) queryDataLimited(ctx context.Context, user *user.SignedInUser, ds *datasources.DataSource, queryFn func() (*backend.QueryDataResponse, error)) (*backend.QueryDataResponse, error) {
+	// the user slot is always acquired first so that requests never wait for
+	// each other's slots
+	if limit := s.cfg.QueryConcurrency.MaxConcurrentQueriesPerUser; limit > 0 && user != nil && user.UserID > 0 {
+		release, err := s.acquireQuerySlot(ctx, fmt.Sprintf("user:%d:%d", user.OrgID, user.UserID), limit, "user")
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	if limit := s.datasourceConcurrencyLimit(ds); limit > 0 {
+		release, err := s.acquireQuerySlot(ctx, fmt.Sprintf("datasource:%d:%s", ds.OrgID, ds.UID), limit, "data source")
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	return queryFn()
+}
+
+// datasourceConcurrencyLimit returns the maximum number of concurrent requests
+// to the data source. The default limit can be overridden with the
+// maxConcurrentQueries field of the data source JSON data.
+func (s *ServiceImpl) datasourceConcurrencyLimit(ds *datasources.DataSource) int {
+	if ds.JsonData != nil {
+		if limit := ds.JsonData.Get("maxConcurrentQueries").MustInt(0); limit > 0 {
+			return limit
+		}
+	}
+	return s.cfg.QueryConcurrency.MaxConcurrentQueriesPerDatasource
+}
+
+func (s *ServiceImpl) acquireQuerySlot(ctx context.Context, key string, limit int, kind string) (func(), error) {
+	queueCtx, cancel := context.WithTimeout(ctx, s.cfg.QueryConcurrency.QueueTimeout)
+	defer cancel()
+
+	release, ok := s.limiter.acquire(queueCtx, key, limit)
+	if !ok {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		s.log.Warn("Too many concurrent queries", "limit", kind, "key", key, "maxConcurrentQueries", limit)
+		return nil, ErrTooManyQueries.Build(errutil.TemplateData{Public: map[string]interface{}{"Limit": kind}})
+	}
+	return release, nil
+}
+
+// concurrencyLimiter limits the number of concurrent holders of a key.
+type concurrencyLimiter struct {
+	mu    sync.Mutex
+	slots map[string]*limiterSlots
+}
+
+type limiterSlots struct {
+	ch chan struct{}
+	// refs is the number of holders and waiters, the slots are removed when
+	// there are none left
+	refs int
+}
+
+func newConcurrencyLimiter() *concurrencyLimiter {
+	return &concurrencyLimiter{slots: map[string]*limiterSlots{}}
+}
+
+// acquire waits until there are less than limit holders of the key or the
+// context is done. It returns a function to release the slot, or false if no
+// slot was acquired.
+func (l *concurrencyLimiter) acquire(ctx context.Context, key string, limit int) (func(), bool) {
+	// the limit of a data source can change while its queries are running
+	key = fmt.Sprintf("%s:%d", key, limit)
+
+	l.mu.Lock()
+	slots, ok := l.slots[key]
+	if !ok {
+		slots = &limiterSlots{ch: make(chan struct{}, limit)}
+		l.slots[key] = slots
+	}
+	slots.refs++
+	l.mu.Unlock()
+
+	select {
+	case slots.ch <- struct{}{}:
+	default:
+		select {
+		case slots.ch <- struct{}{}:
+		case <-ctx.Done():
+			l.unref(key, slots)
+			return nil, false
+		}
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-slots.ch
+			l.unref(key, slots)
+		})
+	}, true
+}
+
+func (l *concurrencyLimiter) unref(key string, slots *limiterSlots) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	slots.refs--
+	if slots.refs == 0 {
+		delete(l.slots, key)
+	}
+}
@@ -0,0 +1,323 @@
+package query
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+// variableRegex matches the template variable syntaxes the frontend replaces
+// before sending a query: $var, ${var:format} and [[var]].
+var variableRegex = regexp.MustCompile(`\$\w+|\$\{[^}]+\}|\[\[[^\]]+\]\]`)
+
+// variableNameRegex matches a value made of a single template variable and
+// captures its name.
+var variableNameRegex = regexp.MustCompile(`^(?:\$(\w+)|\$\{(\w+)(?::[^}]*)?\}|\[\[(\w+)(?::[^\]]*)?\]\])$`)
+
+// variableValuePattern matches the value of a template variable in a query. It
+// is limited to a single token so a variable cannot add clauses to a query.
+const variableValuePattern = "[^\\s'\"`;()]*"
+
+// ignoredTargetKeys are the keys of a saved panel target that are not part of
+// the query itself.
+var ignoredTargetKeys = map[string]bool{
+	"refId":      true,
+	"datasource": true,
+	"hide":       true,
+	"key":        true,
+}
+
+// LibraryPanelFunc returns the model of the library panel with the given UID.
+type LibraryPanelFunc func(uid string) (*simplejson.Json, error)
+
+// DataSourcesFunc returns the data sources of the organization of the
+// dashboard, which the data source variables of the dashboard can take.
+type DataSourcesFunc func() ([]*datasources.DataSource, error)
+
+// MatchesDashboard returns true if every query matches a target saved in a
+// panel of the dashboard and is sent to a data source the target can use.
+// Template variables in the saved targets match a single token, as the
+// frontend replaces them before sending the queries, while the macros of the
+// data sources, such as $__timeFilter, must be sent as they are saved.
+func MatchesDashboard(dashboard *simplejson.Json, queries []*simplejson.Json, libraryPanel LibraryPanelFunc, dataSources DataSourcesFunc) bool {
+	if dashboard == nil || len(queries) == 0 {
+		return false
+	}
+
+	resolver := &datasourceResolver{variables: dashboardVariables(dashboard), dataSources: dataSources}
+	targets := dashboardTargets(dashboard, libraryPanel)
+	for _, q := range queries {
+		matched := false
+		for _, t := range targets {
+			if t.matches(q, resolver) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+type dashboardTarget struct {
+	datasourceRef string
+	target        map[string]interface{}
+}
+
+func dashboardTargets(dashboard *simplejson.Json, libraryPanel LibraryPanelFunc) []dashboardTarget {
+	panels := dashboard.Get("panels").MustArray()
+	// rows of dashboards with schema version < 16
+	for _, row := range dashboard.Get("rows").MustArray() {
+		panels = append(panels, simplejson.NewFromAny(row).Get("panels").MustArray()...)
+	}
+
+	var targets []dashboardTarget
+	for len(panels) > 0 {
+		panel := simplejson.NewFromAny(panels[0])
+		panels = panels[1:]
+
+		// collapsed rows keep their panels
+		panels = append(panels, panel.Get("panels").MustArray()...)
+
+		if uid := panel.GetPath("libraryPanel", "uid").MustString(); uid != "" && libraryPanel != nil {
+			if model, err := libraryPanel(uid); err == nil && model != nil {
+				panel = model
+			}
+		}
+
+		panelDatasourceRef := datasourceRef(panel.Get("datasource"))
+		for _, t := range panel.Get("targets").MustArray() {
+			target, ok := t.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ref := datasourceRef(simplejson.NewFromAny(target).Get("datasource"))
+			if ref == "" {
+				ref = panelDatasourceRef
+			}
+			targets = append(targets, dashboardTarget{datasourceRef: ref, target: target})
+		}
+	}
+	return targets
+}
+
+// datasourceRef returns the UID of a data source reference, or its name for
+// dashboards saved before data sources were referenced by UID.
+func datasourceRef(ds *simplejson.Json) string {
+	if name, err := ds.String(); err == nil {
+		return name
+	}
+	return ds.Get("uid").MustString()
+}
+
+func (t dashboardTarget) matches(q *simplejson.Json, resolver *datasourceResolver) bool {
+	if q.Get("refId").MustString("A") != simplejson.NewFromAny(t.target).Get("refId").MustString("A") {
+		return false
+	}
+	if !resolver.uids(t.datasourceRef)[q.GetPath("datasource", "uid").MustString()] {
+		return false
+	}
+
+	for key, saved := range t.target {
+		if ignoredTargetKeys[key] {
+			continue
+		}
+		value, ok := q.CheckGet(key)
+		if !ok || !matchesValue(saved, value.Interface()) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesValue compares a saved value with the value of a query, where
+// template variables in saved strings match a single token.
+func matchesValue(saved, value interface{}) bool {
+	switch s := saved.(type) {
+	case string:
+		v, ok := value.(string)
+		return ok && variablePattern(s).MatchString(v)
+	case map[string]interface{}:
+		v, ok := value.(map[string]interface{})
+		if !ok || len(s) != len(v) {
+			return false
+		}
+		for key := range s {
+			if !matchesValue(s[key], v[key]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		v, ok := value.([]interface{})
+		if !ok || len(s) != len(v) {
+			return false
+		}
+		for i := range s {
+			if !matchesValue(s[i], v[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		v, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		sf, err1 := s.Float64()
+		vf, err2 := v.Float64()
+		return err1 == nil && err2 == nil && sf == vf
+	default:
+		return reflect.DeepEqual(saved, value)
+	}
+}
+
+func variablePattern(saved string) *regexp.Regexp {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	last := 0
+	for _, loc := range variableRegex.FindAllStringIndex(saved, -1) {
+		// the macros of the data sources are replaced by their backend
+		if strings.HasPrefix(saved[loc[0]:loc[1]], "$__") {
+			continue
+		}
+		pattern.WriteString(regexp.QuoteMeta(saved[last:loc[0]]))
+		pattern.WriteString(variableValuePattern)
+		last = loc[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(saved[last:]))
+	pattern.WriteString("$")
+	return regexp.MustCompile(pattern.String())
+}
+
+func dashboardVariables(dashboard *simplejson.Json) map[string]*simplejson.Json {
+	variables := map[string]*simplejson.Json{}
+	for _, v := range dashboard.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(v)
+		variables[variable.Get("name").MustString()] = variable
+	}
+	return variables
+}
+
+// datasourceResolver resolves the data source references of the targets to the
+// UIDs of the data sources they can use.
+type datasourceResolver struct {
+	variables   map[string]*simplejson.Json
+	dataSources DataSourcesFunc
+	loaded      bool
+	all         []*datasources.DataSource
+}
+
+func (r *datasourceResolver) load() []*datasources.DataSource {
+	if !r.loaded && r.dataSources != nil {
+		// without data sources only the references by UID can be resolved
+		r.all, _ = r.dataSources()
+	}
+	r.loaded = true
+	return r.all
+}
+
+func (r *datasourceResolver) uids(ref string) map[string]bool {
+	uids := map[string]bool{}
+	all := r.load()
+
+	// targets without a data source use the default one
+	if ref == "" {
+		for _, ds := range all {
+			if ds.IsDefault {
+				uids[ds.UID] = true
+			}
+		}
+		return uids
+	}
+
+	if !variableRegex.MatchString(ref) {
+		uids[ref] = true
+		for _, ds := range all {
+			if ds.Name == ref {
+				uids[ds.UID] = true
+			}
+		}
+		return uids
+	}
+
+	match := variableNameRegex.FindStringSubmatch(ref)
+	if match == nil {
+		return uids
+	}
+	variable, ok := r.variables[match[1]+match[2]+match[3]]
+	if !ok {
+		return uids
+	}
+
+	// data source variables can take the data sources of their plugin type
+	// whose name matches their regex
+	if variable.Get("type").MustString() == "datasource" {
+		nameRegex, err := variableRegexFilter(variable.Get("regex").MustString())
+		if err != nil {
+			return uids
+		}
+		pluginType := variable.Get("query").MustString()
+		for _, ds := range all {
+			if ds.Type == pluginType && (nameRegex == nil || nameRegex.MatchString(ds.Name)) {
+				uids[ds.UID] = true
+			}
+		}
+		return uids
+	}
+
+	// other variables can take their saved options
+	for _, value := range variableValues(variable) {
+		if !variableRegex.MatchString(value) {
+			for uid := range r.uids(value) {
+				uids[uid] = true
+			}
+		}
+	}
+	return uids
+}
+
+// variableRegexFilter compiles the regex of a variable, which the frontend
+// accepts with or without slashes, such as /^prod/.
+func variableRegexFilter(regex string) (*regexp.Regexp, error) {
+	if regex == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(regex, "/") {
+		if end := strings.LastIndex(regex, "/"); end > 0 {
+			flags := regex[end+1:]
+			regex = regex[1:end]
+			if strings.Contains(flags, "i") {
+				regex = "(?i)" + regex
+			}
+		}
+	}
+	return regexp.Compile(regex)
+}
+
+func variableValues(variable *simplejson.Json) []string {
+	var values []string
+	add := func(value interface{}) {
+		switch v := value.(type) {
+		case string:
+			values = append(values, v)
+		case []interface{}:
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					values = append(values, s)
+				}
+			}
+		}
+	}
+	add(variable.GetPath("current", "value").Interface())
+	for _, option := range variable.Get("options").MustArray() {
+		add(simplejson.NewFromAny(option).Get("value").Interface())
+	}
+	return values
+}
@@ -0,0 +1,164 @@
+package query
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+func TestMatchesDashboard(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(`{
+		"panels": [
+			{
+				"datasource": {"type": "prometheus", "uid": "prom"},
+				"targets": [{"refId": "A", "expr": "rate(http_requests_total{job=\"$job\"}[$__rate_interval])", "datasource": {"uid": "prom"}}]
+			},
+			{
+				"type": "row",
+				"collapsed": true,
+				"panels": [{
+					"datasource": {"uid": "${ds}"},
+					"targets": [
+						{"refId": "B", "rawSql": "SELECT 1", "format": "table", "intervalFactor": 1},
+						{"refId": "D", "rawSql": "SELECT time, value FROM metrics WHERE $__timeFilter(time) AND host = '$host'"}
+					]
+				}]
+			},
+			{"libraryPanel": {"uid": "lib"}},
+			{"targets": [{"refId": "E", "expr": "up"}]}
+		],
+		"templating": {
+			"list": [
+				{"name": "ds", "type": "datasource", "query": "mysql", "regex": "/^app-/"},
+				{"name": "host", "type": "custom", "current": {"value": "a"}, "options": [{"value": "a"}, {"value": "b"}]}
+			]
+		}
+	}`))
+	require.NoError(t, err)
+
+	libraryPanel := func(uid string) (*simplejson.Json, error) {
+		if uid != "lib" {
+			return nil, errors.New("not found")
+		}
+		return simplejson.NewJson([]byte(`{"datasource": {"uid": "loki"}, "targets": [{"refId": "C", "expr": "{app=\"grafana\"}"}]}`))
+	}
+
+	dataSources := func() ([]*datasources.DataSource, error) {
+		return []*datasources.DataSource{
+			{UID: "prom", Name: "Prometheus", Type: datasources.DS_PROMETHEUS, IsDefault: true},
+			{UID: "mysql", Name: "app-mysql", Type: datasources.DS_MYSQL},
+			{UID: "prod-db", Name: "prod-db", Type: datasources.DS_MYSQL},
+			{UID: "postgres", Name: "app-postgres", Type: datasources.DS_POSTGRES},
+		}, nil
+	}
+
+	newQueries := func(t *testing.T, queries ...string) []*simplejson.Json {
+		t.Helper()
+		result := make([]*simplejson.Json, 0, len(queries))
+		for _, q := range queries {
+			j, err := simplejson.NewJson([]byte(q))
+			require.NoError(t, err)
+			result = append(result, j)
+		}
+		return result
+	}
+
+	tests := []struct {
+		desc     string
+		queries  []string
+		expected bool
+	}{
+		{
+			desc: "should match a query with interpolated variables",
+			queries: []string{
+				`{"refId": "A", "datasource": {"uid": "prom"}, "expr": "rate(http_requests_total{job=\"api\"}[$__rate_interval])", "intervalMs": 15000}`,
+			},
+			expected: true,
+		},
+		{
+			desc: "should match a query with macros of the data source",
+			queries: []string{
+				`{"refId": "D", "datasource": {"uid": "mysql"}, "rawSql": "SELECT time, value FROM metrics WHERE $__timeFilter(time) AND host = 'b'"}`,
+			},
+			expected: true,
+		},
+		{
+			desc: "should match a query of a target without data source sent to the default data source",
+			queries: []string{
+				`{"refId": "E", "datasource": {"uid": "prom"}, "expr": "up"}`,
+			},
+			expected: true,
+		},
+		{
+			desc: "should not match a query replacing a macro",
+			queries: []string{
+				`{"refId": "D", "datasource": {"uid": "mysql"}, "rawSql": "SELECT time, value FROM metrics WHERE 1=1 UNION SELECT now(), password FROM users WHERE 1 IN (time) AND host = 'b'"}`,
+			},
+		},
+		{
+			desc: "should not match a query with a variable replaced by more than a token",
+			queries: []string{
+				`{"refId": "A", "datasource": {"uid": "prom"}, "expr": "rate(http_requests_total{job=\"api\"} or vector(1) or up{job=\"\"}[$__rate_interval])"}`,
+			},
+		},
+		{
+			desc: "should not match a query sent to a data source the data source variable cannot take",
+			queries: []string{
+				`{"refId": "B", "datasource": {"uid": "prod-db"}, "rawSql": "SELECT 1", "format": "table", "intervalFactor": 1}`,
+			},
+		},
+		{
+			desc: "should not match a query of a target without data source sent to another data source",
+			queries: []string{
+				`{"refId": "E", "datasource": {"uid": "loki"}, "expr": "up"}`,
+			},
+		},
+		{
+			desc: "should match queries of collapsed rows and library panels",
+			queries: []string{
+				`{"refId": "B", "datasource": {"uid": "mysql"}, "rawSql": "SELECT 1", "format": "table", "intervalFactor": 1.0}`,
+				`{"refId": "C", "datasource": {"uid": "loki"}, "expr": "{app=\"grafana\"}"}`,
+			},
+			expected: true,
+		},
+		{
+			desc: "should not match a query that is not saved in the dashboard",
+			queries: []string{
+				`{"refId": "B", "datasource": {"uid": "mysql"}, "rawSql": "SELECT password FROM user", "format": "table", "intervalFactor": 1}`,
+			},
+		},
+		{
+			desc: "should not match a saved query sent to another data source",
+			queries: []string{
+				`{"refId": "C", "datasource": {"uid": "prom"}, "expr": "{app=\"grafana\"}"}`,
+			},
+		},
+		{
+			desc: "should not match if one of the queries is not saved",
+			queries: []string{
+				`{"refId": "C", "datasource": {"uid": "loki"}, "expr": "{app=\"grafana\"}"}`,
+				`{"refId": "D", "datasource": {"uid": "loki"}, "expr": "{app=\"other\"}"}`,
+			},
+		},
+		{
+			desc: "should not match a query missing a saved field",
+			queries: []string{
+				`{"refId": "B", "datasource": {"uid": "mysql"}, "rawSql": "SELECT 1", "intervalFactor": 1}`,
+			},
+		},
+		{
+			desc: "should not match without queries",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MatchesDashboard(dashboard, newQueries(t, tt.queries...), libraryPanel, dataSources))
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package query
 
 import (
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
@@ -10,4 +11,9 @@ var (
 	ErrMissingDataSourceInfo = errutil.NewBase(errutil.StatusBadRequest, "query.missingDataSourceInfo").MustTemplate("query missing datasource info: {{ .Public.RefId }}", errutil.WithPublic("Query {{ .Public.RefId }} is missing datasource information"))
 	ErrQueryParamMismatch    = errutil.NewBase(errutil.StatusBadRequest, "query.headerMismatch", errutil.WithPublicMessage("The request headers point to a different plugin than is defined in the request body")).Errorf("plugin header/body mismatch")
 	ErrDuplicateRefId        = errutil.NewBase(errutil.StatusBadRequest, "query.duplicateRefId", errutil.WithPublicMessage("Multiple queries using the same RefId is not allowed ")).Errorf("multiple queries using the same RefId is not allowed")
+	ErrQueryAccessDenied     = errutil.NewBase(errutil.StatusForbidden, "query.accessDenied").MustTemplate("user is not allowed to query data source {{ .Public.DatasourceUID }}", errutil.WithPublic("Access denied to data source {{ .Public.DatasourceUID }}"))
+	ErrExploreAccessDenied   = errutil.NewBase(errutil.StatusForbidden, "query.exploreAccessDenied").MustTemplate("user is not allowed to use data source {{ .Public.DatasourceUID }} in Explore", errutil.WithPublic("Data source {{ .Public.DatasourceUID }} can only be queried from dashboards"))
+	ErrQueryTypeNotAllowed   = expr.ErrQueryTypeNotAllowed
+	ErrQueryNotReadOnly      = expr.ErrQueryNotReadOnly
+	ErrTooManyQueries        = errutil.NewBase(errutil.StatusTooManyRequests, "query.tooManyQueries").MustTemplate("too many concurrent queries for {{ .Public.Limit }}", errutil.WithPublic("Too many concurrent queries for this {{ .Public.Limit }}, try again later"))
 )
@@ -7,11 +7,13 @@ import (
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/adapters"
@@ -39,6 +41,7 @@ func ProvideService(
 	pluginRequestValidator validations.PluginRequestValidator,
 	dataSourceService datasources.DataSourceService,
 	pluginClient plugins.Client,
+	remoteCache remotecache.CacheStorage,
 ) *ServiceImpl {
 	g := &ServiceImpl{
 		cfg:                    cfg,
@@ -47,6 +50,8 @@ func ProvideService(
 		pluginRequestValidator: pluginRequestValidator,
 		dataSourceService:      dataSourceService,
 		pluginClient:           pluginClient,
+		remoteCache:            remoteCache,
+		limiter:                newConcurrencyLimiter(),
 		log:                    log.New("query_data"),
 	}
 	g.log.Info("Query Service initialization")
@@ -69,6 +74,9 @@ type ServiceImpl struct {
 	pluginRequestValidator validations.PluginRequestValidator
 	dataSourceService      datasources.DataSourceService
 	pluginClient           plugins.Client
+	remoteCache            remotecache.CacheStorage
+	inFlight               singleflight.Group
+	limiter                *concurrencyLimiter
 	log                    log.Logger
 }
 
@@ -92,7 +100,7 @@ func (s *ServiceImpl) QueryData(ctx context.Context, user *user.SignedInUser, sk
 	}
 	// If there is only one datasource, query it and return
 	if len(parsedReq.parsedQueries) == 1 {
-		return s.handleQuerySingleDatasource(ctx, user, parsedReq)
+		return s.handleQuerySingleDatasource(ctx, user, skipCache, parsedReq)
 	}
 	// If there are multiple datasources, handle their queries concurrently and return the aggregate result
 	return s.executeConcurrentQueries(ctx, user, skipCache, reqDTO, parsedReq.parsedQueries)
@@ -213,7 +221,7 @@ func (s *ServiceImpl) handleExpressions(ctx context.Context, user *user.SignedIn
 }
 
 // handleQuerySingleDatasource handles one or more queries to a single datasource
-func (s *ServiceImpl) handleQuerySingleDatasource(ctx context.Context, user *user.SignedInUser, parsedReq *parsedRequest) (*backend.QueryDataResponse, error) {
+func (s *ServiceImpl) handleQuerySingleDatasource(ctx context.Context, user *user.SignedInUser, skipCache bool, parsedReq *parsedRequest) (*backend.QueryDataResponse, error) {
 	queries := parsedReq.getFlattenedQueries()
 	ds := queries[0].datasource
 	if err := s.pluginRequestValidator.Validate(ds.URL, nil); err != nil {
@@ -247,7 +255,13 @@ func (s *ServiceImpl) handleQuerySingleDatasource(ctx context.Context, user *use
 		req.Queries = append(req.Queries, q.query)
 	}
 
-	return s.pluginClient.QueryData(ctx, req)
+	return s.queryDataCached(ctx, skipCache, ds, queries, func() (*backend.QueryDataResponse, error) {
+		return s.queryDataCoalesced(ctx, ds, queries, func() (*backend.QueryDataResponse, error) {
+			return s.queryDataLimited(ctx, user, ds, func() (*backend.QueryDataResponse, error) {
+				return s.pluginClient.QueryData(ctx, req)
+			})
+		})
+	})
 }
 
 // parseRequest parses a request into parsed queries grouped by datasource uid
@@ -273,6 +287,9 @@ func (s *ServiceImpl) parseMetricRequest(ctx context.Context, user *user.SignedI
 		if ds == nil {
 			return nil, ErrInvalidDatasourceID
 		}
+		if err := s.checkDatasourceAccess(ctx, user, ds); err != nil {
+			return nil, err
+		}
 
 		datasourcesByUid[ds.UID] = ds
 		if expr.IsDataSource(ds.UID) {
@@ -292,7 +309,7 @@ func (s *ServiceImpl) parseMetricRequest(ctx context.Context, user *user.SignedI
 			return nil, err
 		}
 
-		req.parsedQueries[ds.UID] = append(req.parsedQueries[ds.UID], parsedQuery{
+		pq := parsedQuery{
 			datasource: ds,
 			query: backend.DataQuery{
 				TimeRange: backend.TimeRange{
@@ -306,7 +323,12 @@ func (s *ServiceImpl) parseMetricRequest(ctx context.Context, user *user.SignedI
 				JSON:          modelJSON,
 			},
 			rawQuery: query,
-		})
+		}
+		if err := expr.CheckQueryRestrictions(ds, pq.query.RefID, pq.query.QueryType, query); err != nil {
+			return nil, err
+		}
+
+		req.parsedQueries[ds.UID] = append(req.parsedQueries[ds.UID], pq)
 	}
 
 	return req, req.validateRequest(ctx)
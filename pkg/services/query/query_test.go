@@ -6,9 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -17,6 +21,7 @@ import (
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
 	"github.com/grafana/grafana/pkg/models/roletype"
 	"github.com/grafana/grafana/pkg/plugins"
 	acmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
@@ -429,6 +434,380 @@ func TestQueryDataMultipleSources(t *testing.T) {
 	})
 }
 
+func TestQueryDataAccessControl(t *testing.T) {
+	queryDS1 := `{"datasource": {"type": "mysql", "uid": "ds1"}, "refId": "A"}`
+	permissions := map[int64]map[string][]string{1: {
+		datasources.ActionQuery:   {datasources.ScopeProvider.GetResourceScopeUID("ds1")},
+		datasources.ActionExplore: {datasources.ScopeProvider.GetResourceScopeUID("ds2")},
+	}}
+
+	t.Run("does not check permissions without a usage", func(t *testing.T) {
+		tc := setup(t)
+		_, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, true, metricRequestWithQueries(t, queryDS1))
+		require.NoError(t, err)
+	})
+
+	t.Run("requires query permission on the data source", func(t *testing.T) {
+		tc := setup(t)
+		tc.signedInUser.Permissions = permissions
+		ctx := WithUsage(context.Background(), UsageDashboard)
+
+		_, err := tc.queryService.QueryData(ctx, tc.signedInUser, true, metricRequestWithQueries(t, queryDS1))
+		require.NoError(t, err)
+
+		_, err = tc.queryService.QueryData(ctx, tc.signedInUser, true, metricRequestWithQueries(t, `{"datasource": {"type": "mysql", "uid": "ds2"}, "refId": "A"}`))
+		require.ErrorIs(t, err, ErrQueryAccessDenied)
+	})
+
+	t.Run("does not check permissions for expressions and the built-in data source", func(t *testing.T) {
+		tc := setup(t)
+		tc.signedInUser.Permissions = permissions
+		ctx := WithUsage(context.Background(), UsageExplore)
+
+		_, err := tc.queryService.QueryData(ctx, tc.signedInUser, true, metricRequestWithQueries(t, `{"datasource": {"type": "datasource", "uid": "grafana"}, "refId": "A"}`))
+		require.NoError(t, err)
+	})
+
+	t.Run("requires explore permission for explore queries when permissions are enforced", func(t *testing.T) {
+		tc := setup(t)
+		tc.signedInUser.Permissions = permissions
+		ctx := WithUsage(context.Background(), UsageExplore)
+
+		_, err := tc.queryService.QueryData(ctx, tc.signedInUser, true, metricRequestWithQueries(t, queryDS1))
+		require.NoError(t, err)
+
+		tc.queryService.cfg.DataSourcePermissionsEnforced = true
+		_, err = tc.queryService.QueryData(ctx, tc.signedInUser, true, metricRequestWithQueries(t, queryDS1))
+		require.ErrorIs(t, err, ErrExploreAccessDenied)
+
+		_, err = tc.queryService.QueryData(WithUsage(context.Background(), UsageDashboard), tc.signedInUser, true, metricRequestWithQueries(t, queryDS1))
+		require.NoError(t, err)
+	})
+}
+
+func TestQueryDataRestrictions(t *testing.T) {
+	setupRestricted := func(t *testing.T, dsType string, jsonData string) *testContext {
+		t.Helper()
+		tc := setup(t)
+		data, err := simplejson.NewJson([]byte(jsonData))
+		require.NoError(t, err)
+		tc.dataSourceCache.ds = &datasources.DataSource{UID: "ds1", Type: dsType, JsonData: data}
+		return tc
+	}
+
+	t.Run("only allows the configured query types", func(t *testing.T) {
+		tc := setupRestricted(t, datasources.DS_LOKI, `{"allowedQueryTypes": ["range", "instant"]}`)
+
+		_, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, true, metricRequestWithQueries(t, `{"datasource": {"uid": "ds1"}, "queryType": "range", "refId": "A"}`))
+		require.NoError(t, err)
+
+		_, err = tc.queryService.QueryData(context.Background(), tc.signedInUser, true, metricRequestWithQueries(t, `{"datasource": {"uid": "ds1"}, "queryType": "stream", "refId": "A"}`))
+		require.ErrorIs(t, err, ErrQueryTypeNotAllowed)
+
+		_, err = tc.queryService.QueryData(context.Background(), tc.signedInUser, true, metricRequestWithQueries(t, `{"datasource": {"uid": "ds1"}, "refId": "A"}`))
+		require.ErrorIs(t, err, ErrQueryTypeNotAllowed)
+	})
+
+	t.Run("only allows read-only queries for SQL data sources", func(t *testing.T) {
+		tc := setupRestricted(t, datasources.DS_POSTGRES, `{"readOnlySql": true}`)
+
+		_, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, true, metricRequestWithQueries(t, `{"datasource": {"uid": "ds1"}, "rawSql": "SELECT 1", "refId": "A"}`))
+		require.NoError(t, err)
+
+		_, err = tc.queryService.QueryData(context.Background(), tc.signedInUser, true, metricRequestWithQueries(t, `{"datasource": {"uid": "ds1"}, "rawSql": "DELETE FROM metrics", "refId": "A"}`))
+		require.ErrorIs(t, err, ErrQueryNotReadOnly)
+	})
+
+	t.Run("ignores read-only validation for other data sources", func(t *testing.T) {
+		tc := setupRestricted(t, datasources.DS_PROMETHEUS, `{"readOnlySql": true}`)
+
+		_, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, true, metricRequestWithQueries(t, `{"datasource": {"uid": "ds1"}, "rawSql": "DELETE FROM metrics", "refId": "A"}`))
+		require.NoError(t, err)
+	})
+}
+
+func TestQueryDataCache(t *testing.T) {
+	setupCache := func(t *testing.T, jsonData string) *testContext {
+		t.Helper()
+		tc := setup(t)
+		tc.queryService.cfg.QueryCaching = setting.QueryCachingSettings{Enabled: true, TTL: time.Minute, MaxValueSize: 1024 * 1024}
+		data, err := simplejson.NewJson([]byte(jsonData))
+		require.NoError(t, err)
+		tc.dataSourceCache.ds = &datasources.DataSource{UID: "ds1", OrgID: 1, Type: datasources.DS_PROMETHEUS, JsonData: data}
+		return tc
+	}
+	request := func(t *testing.T, from, to string, rawQueries ...string) dtos.MetricRequest {
+		t.Helper()
+		reqDTO := metricRequestWithQueries(t, rawQueries...)
+		reqDTO.From, reqDTO.To = from, to
+		return reqDTO
+	}
+	queryA := `{"datasource": {"uid": "ds1"}, "expr": "up", "refId": "A"}`
+
+	t.Run("serves repeated queries from the cache", func(t *testing.T) {
+		tc := setupCache(t, `{}`)
+
+		ctx := WithQueryCache(context.Background(), false)
+		_, err := tc.queryService.QueryData(ctx, tc.signedInUser, false, request(t, "1600000000000", "1600003600000", queryA))
+		require.NoError(t, err)
+		require.Equal(t, CacheStatusMiss, CacheStatusFromContext(ctx))
+
+		// the time range is aligned to the TTL and the data source reference is ignored
+		ctx = WithQueryCache(context.Background(), false)
+		_, err = tc.queryService.QueryData(ctx, tc.signedInUser, false, request(t, "1600000005000", "1600003605000",
+			`{"datasource": {"type": "prometheus", "uid": "ds1"}, "refId": "A", "expr": "up"}`))
+		require.NoError(t, err)
+		require.Equal(t, CacheStatusHit, CacheStatusFromContext(ctx))
+		require.Equal(t, 1, tc.pluginContext.calls)
+
+		ctx = WithQueryCache(context.Background(), false)
+		_, err = tc.queryService.QueryData(ctx, tc.signedInUser, false, request(t, "1600000000000", "1600003600000",
+			`{"datasource": {"uid": "ds1"}, "expr": "down", "refId": "A"}`))
+		require.NoError(t, err)
+		require.Equal(t, CacheStatusMiss, CacheStatusFromContext(ctx))
+		require.Equal(t, 2, tc.pluginContext.calls)
+	})
+
+	t.Run("bypasses the cache when requested", func(t *testing.T) {
+		tc := setupCache(t, `{}`)
+
+		for i := 0; i < 2; i++ {
+			ctx := WithQueryCache(context.Background(), true)
+			_, err := tc.queryService.QueryData(ctx, tc.signedInUser, false, request(t, "1600000000000", "1600003600000", queryA))
+			require.NoError(t, err)
+			require.Equal(t, CacheStatusBypass, CacheStatusFromContext(ctx))
+		}
+		require.Equal(t, 2, tc.pluginContext.calls)
+	})
+
+	t.Run("does not cache queries without a cache context", func(t *testing.T) {
+		tc := setupCache(t, `{}`)
+
+		for i := 0; i < 2; i++ {
+			_, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, false, request(t, "1600000000000", "1600003600000", queryA))
+			require.NoError(t, err)
+		}
+		require.Equal(t, 2, tc.pluginContext.calls)
+	})
+
+	t.Run("uses the TTL of the data source", func(t *testing.T) {
+		tc := setupCache(t, `{"queryCacheTTL": "0"}`)
+		require.Equal(t, time.Duration(0), tc.queryService.cacheTTL(tc.dataSourceCache.ds))
+
+		ctx := WithQueryCache(context.Background(), false)
+		_, err := tc.queryService.QueryData(ctx, tc.signedInUser, false, request(t, "1600000000000", "1600003600000", queryA))
+		require.NoError(t, err)
+		require.Equal(t, CacheStatusBypass, CacheStatusFromContext(ctx))
+
+		tc = setupCache(t, `{"queryCacheTTL": "5m"}`)
+		require.Equal(t, 5*time.Minute, tc.queryService.cacheTTL(tc.dataSourceCache.ds))
+
+		tc = setupCache(t, `{"queryCacheTTL": "5m", "oauthPassThru": true}`)
+		require.Equal(t, time.Duration(0), tc.queryService.cacheTTL(tc.dataSourceCache.ds))
+	})
+
+	t.Run("does not share cache entries between short time ranges", func(t *testing.T) {
+		tc := setupCache(t, `{"queryCacheTTL": "1h"}`)
+
+		// 12:30-12:35 and 12:40-12:45 start in the same hour
+		for _, r := range [][2]string{{"1600000200000", "1600000500000"}, {"1600000800000", "1600001100000"}} {
+			ctx := WithQueryCache(context.Background(), false)
+			_, err := tc.queryService.QueryData(ctx, tc.signedInUser, false, request(t, r[0], r[1], queryA))
+			require.NoError(t, err)
+			require.Equal(t, CacheStatusBypass, CacheStatusFromContext(ctx))
+		}
+		require.Equal(t, 2, tc.pluginContext.calls)
+	})
+
+	t.Run("keys time ranges by their aligned start and their span", func(t *testing.T) {
+		ds := &datasources.DataSource{UID: "ds1", OrgID: 1}
+		key := func(from, to int64) string {
+			t.Helper()
+			k, err := queryCacheKeyFor(ds, []parsedQuery{{query: backend.DataQuery{
+				RefID:     "A",
+				JSON:      []byte(`{"expr": "up"}`),
+				TimeRange: backend.TimeRange{From: time.UnixMilli(from), To: time.UnixMilli(to)},
+			}}}, time.Hour)
+			require.NoError(t, err)
+			return k
+		}
+
+		require.Equal(t, key(1600000200000, 1600003800000), key(1600000800000, 1600004400000))
+		require.NotEqual(t, key(1600000200000, 1600003800000), key(1600000200000, 1600005600000))
+		require.NotEqual(t, key(1600000200000, 1600003800000), key(1600000800000, 1600005000000))
+	})
+
+	t.Run("does not cache data sources that depend on the identity of the user", func(t *testing.T) {
+		for _, jsonData := range []string{
+			`{"oauthPassThru": true}`,
+			`{"keepCookies": ["session"]}`,
+			`{"teamHttpHeaders": {"1": [{"header": "X-Prom-Label-Policy", "value": "team=\"a\""}]}}`,
+		} {
+			tc := setupCache(t, jsonData)
+			require.Equal(t, time.Duration(0), tc.queryService.cacheTTL(tc.dataSourceCache.ds), jsonData)
+		}
+
+		tc := setupCache(t, `{}`)
+		tc.queryService.cfg.SendUserHeader = true
+
+		for i := 0; i < 2; i++ {
+			ctx := WithQueryCache(context.Background(), false)
+			_, err := tc.queryService.QueryData(ctx, tc.signedInUser, false, request(t, "1600000000000", "1600003600000", queryA))
+			require.NoError(t, err)
+			require.Equal(t, CacheStatusBypass, CacheStatusFromContext(ctx))
+		}
+		require.Equal(t, 2, tc.pluginContext.calls)
+	})
+
+	t.Run("does not cache failed queries", func(t *testing.T) {
+		tc := setupCache(t, `{}`)
+
+		for i := 0; i < 2; i++ {
+			ctx := WithQueryCache(context.Background(), false)
+			_, err := tc.queryService.QueryData(ctx, tc.signedInUser, false, request(t, "1600000000000", "1600003600000",
+				`{"datasource": {"uid": "ds1"}, "queryType": "FAIL", "refId": "A"}`))
+			require.Error(t, err)
+			require.Equal(t, CacheStatusMiss, CacheStatusFromContext(ctx))
+		}
+	})
+}
+
+func TestQueryDataConcurrency(t *testing.T) {
+	setupBlocking := func(t *testing.T, settings setting.QueryConcurrencySettings, jsonData string) (*testContext, *blockingPluginClient) {
+		t.Helper()
+		tc := setup(t)
+		tc.queryService.cfg.QueryConcurrency = settings
+		data, err := simplejson.NewJson([]byte(jsonData))
+		require.NoError(t, err)
+		tc.dataSourceCache.ds = &datasources.DataSource{UID: "ds1", OrgID: 1, Type: datasources.DS_MYSQL, JsonData: data}
+		client := &blockingPluginClient{started: make(chan struct{}, 10), release: make(chan struct{})}
+		tc.queryService.pluginClient = client
+		return tc, client
+	}
+	reqDTO := func(t *testing.T, rawQuery string) dtos.MetricRequest {
+		t.Helper()
+		r := metricRequestWithQueries(t, rawQuery)
+		r.From, r.To = "1600000000000", "1600003600000"
+		return r
+	}
+	queryA := `{"datasource": {"uid": "ds1"}, "rawSql": "SELECT 1", "refId": "A"}`
+
+	t.Run("coalesces identical concurrent queries", func(t *testing.T) {
+		tc, client := setupBlocking(t, setting.QueryConcurrencySettings{CoalesceRequests: true}, `{}`)
+
+		var wg sync.WaitGroup
+		results := make(chan error, 2)
+		run := func() {
+			defer wg.Done()
+			_, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, false, reqDTO(t, queryA))
+			results <- err
+		}
+		wg.Add(2)
+		go run()
+		<-client.started
+		go run()
+		// give the second request time to join the one in flight
+		time.Sleep(50 * time.Millisecond)
+		close(client.release)
+		wg.Wait()
+		close(results)
+
+		for err := range results {
+			require.NoError(t, err)
+		}
+		require.Equal(t, int32(1), client.calls.Load())
+	})
+
+	t.Run("gives every caller of a coalesced query its own response", func(t *testing.T) {
+		tc, client := setupBlocking(t, setting.QueryConcurrencySettings{CoalesceRequests: true}, `{}`)
+
+		const callers = 5
+		var wg sync.WaitGroup
+		responses := make(chan *backend.QueryDataResponse, callers)
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				resp, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, false, reqDTO(t, queryA))
+				if !assert.NoError(t, err) {
+					return
+				}
+				// every caller modifies its response, which must not race with the others
+				resp.Responses["A"].Frames[0].Name = fmt.Sprintf("caller-%d", i)
+				resp.Responses["B"] = backend.DataResponse{}
+				responses <- resp
+			}(i)
+		}
+		<-client.started
+		// give the other requests time to join the one in flight
+		time.Sleep(50 * time.Millisecond)
+		close(client.release)
+		wg.Wait()
+		close(responses)
+
+		names := map[string]bool{}
+		for resp := range responses {
+			names[resp.Responses["A"].Frames[0].Name] = true
+			require.Len(t, resp.Responses, 2)
+		}
+		require.Len(t, names, callers)
+		require.Equal(t, int32(1), client.calls.Load())
+	})
+
+	t.Run("does not coalesce when disabled", func(t *testing.T) {
+		tc, client := setupBlocking(t, setting.QueryConcurrencySettings{}, `{}`)
+		close(client.release)
+
+		for i := 0; i < 2; i++ {
+			_, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, false, reqDTO(t, queryA))
+			require.NoError(t, err)
+		}
+		require.Equal(t, int32(2), client.calls.Load())
+	})
+
+	t.Run("rejects queries over the data source limit after the queue timeout", func(t *testing.T) {
+		tc, client := setupBlocking(t, setting.QueryConcurrencySettings{QueueTimeout: 50 * time.Millisecond}, `{"maxConcurrentQueries": 1}`)
+
+		done := make(chan error)
+		go func() {
+			_, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, false, reqDTO(t, queryA))
+			done <- err
+		}()
+		<-client.started
+
+		_, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, false, reqDTO(t, `{"datasource": {"uid": "ds1"}, "rawSql": "SELECT 2", "refId": "A"}`))
+		require.ErrorIs(t, err, ErrTooManyQueries)
+
+		close(client.release)
+		require.NoError(t, <-done)
+		require.Empty(t, tc.queryService.limiter.slots)
+	})
+
+	t.Run("queues queries over the user limit", func(t *testing.T) {
+		tc, client := setupBlocking(t, setting.QueryConcurrencySettings{MaxConcurrentQueriesPerUser: 1, QueueTimeout: time.Minute}, `{}`)
+		tc.signedInUser.UserID = 1
+
+		done := make(chan error, 2)
+		for _, q := range []string{queryA, `{"datasource": {"uid": "ds1"}, "rawSql": "SELECT 2", "refId": "A"}`} {
+			go func(q string) {
+				_, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, false, reqDTO(t, q))
+				done <- err
+			}(q)
+		}
+		<-client.started
+		select {
+		case <-client.started:
+			t.Fatal("the second query should wait for the first one")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(client.release)
+		require.NoError(t, <-done)
+		require.NoError(t, <-done)
+		require.Equal(t, int32(2), client.calls.Load())
+	})
+}
+
 func setup(t *testing.T) *testContext {
 	t.Helper()
 	pc := &fakePluginClient{}
@@ -447,7 +826,7 @@ func setup(t *testing.T) *testContext {
 		SimulatePluginFailure: false,
 	}
 	exprService := expr.ProvideService(&setting.Cfg{ExpressionsEnabled: true}, pc, fakeDatasourceService)
-	queryService := ProvideService(setting.NewCfg(), dc, exprService, rv, ds, pc) // provider belonging to this package
+	queryService := ProvideService(setting.NewCfg(), dc, exprService, rv, ds, pc, remotecache.NewFakeStore(t)) // provider belonging to this package
 	return &testContext{
 		pluginContext:          pc,
 		secretStore:            ss,
@@ -501,18 +880,39 @@ func (c *fakeDataSourceCache) GetDatasource(ctx context.Context, datasourceID in
 }
 
 func (c *fakeDataSourceCache) GetDatasourceByUID(ctx context.Context, datasourceUID string, user *user.SignedInUser, skipCache bool) (*datasources.DataSource, error) {
+	if c.ds != nil && c.ds.UID == datasourceUID {
+		return c.ds, nil
+	}
 	return &datasources.DataSource{
 		UID: datasourceUID,
 	}, nil
 }
 
+type blockingPluginClient struct {
+	plugins.Client
+	calls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c *blockingPluginClient) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	c.calls.Add(1)
+	c.started <- struct{}{}
+	<-c.release
+	return &backend.QueryDataResponse{Responses: backend.Responses{
+		"A": backend.DataResponse{Frames: data.Frames{data.NewFrame("A", data.NewField("value", nil, []int64{1}))}},
+	}}, nil
+}
+
 type fakePluginClient struct {
 	plugins.Client
-	req *backend.QueryDataRequest
+	req   *backend.QueryDataRequest
+	calls int
 }
 
 func (c *fakePluginClient) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	c.req = req
+	c.calls++
 
 	// If an expression query ends up getting directly queried, we want it to return an error in our test.
 	if req.PluginContext.PluginID == expr.DatasourceUID {
@@ -0,0 +1,200 @@
+package templatevars
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// AllValue is the value of the `All` option of multi value variables
+const AllValue = "$__all"
+
+// same syntax as the frontend template service: $var, [[var:format]] and ${var.field:format}
+var variableRegex = regexp.MustCompile(`\$(\w+)|\[\[(\w+?)(?::(\w+))?\]\]|\$\{(\w+)(?:\.([^:^\}]+))?(?::([^\}]+))?\}`)
+
+// format used for multi value variables when the query does not set one, matches the frontend datasources
+var defaultFormats = map[string]string{
+	"prometheus": "regex",
+	"loki":       "regex",
+	"mysql":      "sqlstring",
+	"postgres":   "sqlstring",
+	"mssql":      "sqlstring",
+	"sqlite":     "sqlstring",
+	"genericsql": "sqlstring",
+}
+
+// datasources that put regex values in string literals, where the backslashes need to be escaped again
+var stringLiteralRegexTypes = map[string]bool{
+	"prometheus": true,
+	"loki":       true,
+}
+
+// Variable is a variable from the dashboard templating list
+type Variable struct {
+	Name     string
+	Current  []string
+	Options  []string
+	AllValue string
+}
+
+// FromDashboard returns the template variables of a dashboard by name
+func FromDashboard(dashboard *simplejson.Json) map[string]*Variable {
+	variables := make(map[string]*Variable)
+	for _, obj := range dashboard.GetPath("templating", "list").MustArray() {
+		v := simplejson.NewFromAny(obj)
+		name := v.Get("name").MustString()
+		if name == "" {
+			continue
+		}
+
+		variable := &Variable{
+			Name:     name,
+			Current:  getStringValues(v.GetPath("current", "value")),
+			AllValue: v.Get("allValue").MustString(),
+		}
+		for _, option := range v.Get("options").MustArray() {
+			value := simplejson.NewFromAny(option).Get("value").MustString()
+			if value != "" && value != AllValue {
+				variable.Options = append(variable.Options, value)
+			}
+		}
+		variables[name] = variable
+	}
+	return variables
+}
+
+// Values returns the current values of the variable, `All` is replaced with the custom all value or the options
+func (v *Variable) Values() []string {
+	if !Contains(v.Current, AllValue) {
+		return v.Current
+	}
+	if v.AllValue != "" {
+		return []string{v.AllValue}
+	}
+	return v.Options
+}
+
+// current values are saved as a string or as an array of strings for multi value variables
+func getStringValues(js *simplejson.Json) []string {
+	if s, err := js.String(); err == nil {
+		return []string{s}
+	}
+	return js.MustStringArray()
+}
+
+// Contains reports whether value is one of values
+func Contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// InterpolateQuery replaces dashboard variables in all the string fields of a query
+func InterpolateQuery(query *simplejson.Json, variables map[string][]string) {
+	if len(variables) == 0 {
+		return
+	}
+
+	dsType := query.GetPath("datasource", "type").MustString()
+	for key, value := range query.MustMap() {
+		if key == "datasource" || key == "refId" {
+			continue
+		}
+		query.Set(key, interpolateValue(value, variables, dsType))
+	}
+}
+
+func interpolateValue(value interface{}, variables map[string][]string, dsType string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return interpolateString(v, variables, dsType)
+	case []interface{}:
+		for i := range v {
+			v[i] = interpolateValue(v[i], variables, dsType)
+		}
+		return v
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = interpolateValue(v[k], variables, dsType)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+func interpolateString(target string, variables map[string][]string, dsType string) string {
+	if !strings.Contains(target, "$") && !strings.Contains(target, "[[") {
+		return target
+	}
+
+	return variableRegex.ReplaceAllStringFunc(target, func(match string) string {
+		groups := variableRegex.FindStringSubmatch(match)
+		name, format := groups[1]+groups[2]+groups[4], groups[3]+groups[6]
+
+		values, ok := variables[name]
+		if !ok {
+			// not a dashboard variable, for example a global variable like $__interval
+			return match
+		}
+		return FormatValues(values, format, dsType)
+	})
+}
+
+// FormatValues formats the values of a variable like the frontend template service
+func FormatValues(values []string, format string, dsType string) string {
+	if format == "" {
+		if len(values) == 1 {
+			return values[0]
+		}
+		format = defaultFormats[dsType]
+	}
+
+	switch format {
+	case "csv", "raw", "text":
+		return strings.Join(values, ",")
+	case "pipe":
+		return strings.Join(values, "|")
+	case "regex":
+		escaped := make([]string, len(values))
+		for i, v := range values {
+			escaped[i] = regexp.QuoteMeta(v)
+			if stringLiteralRegexTypes[dsType] {
+				escaped[i] = strings.ReplaceAll(escaped[i], `\`, `\\`)
+			}
+		}
+		if len(escaped) == 1 {
+			return escaped[0]
+		}
+		return "(" + strings.Join(escaped, "|") + ")"
+	case "singlequote":
+		return quoteValues(values, "'", `\'`)
+	case "doublequote":
+		return quoteValues(values, `"`, `\"`)
+	case "sqlstring":
+		return quoteValues(values, "'", "''")
+	case "percentencode":
+		return url.QueryEscape(strings.Join(values, ","))
+	case "glob", "":
+		if len(values) == 1 {
+			return values[0]
+		}
+		return fmt.Sprintf("{%s}", strings.Join(values, ","))
+	default:
+		return strings.Join(values, ",")
+	}
+}
+
+func quoteValues(values []string, quote string, escaped string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = quote + strings.ReplaceAll(v, quote, escaped) + quote
+	}
+	return strings.Join(quoted, ",")
+}
@@ -0,0 +1,62 @@
+package templatevars
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestFromDashboard(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(`{
+	  "templating": {
+	    "list": [
+	      { "name": "env", "current": { "value": "prod" }, "options": [{ "value": "prod" }, { "value": "dev" }] },
+	      { "name": "host", "allValue": ".*", "current": { "value": ["$__all"] }, "options": [{ "value": "$__all" }, { "value": "a" }] },
+	      { "name": "region", "current": { "value": ["$__all"] }, "options": [{ "value": "$__all" }, { "value": "eu" }, { "value": "us" }] }
+	    ]
+	  }
+	}`))
+	require.NoError(t, err)
+
+	variables := FromDashboard(dashboard)
+	require.Len(t, variables, 3)
+	require.Equal(t, []string{"prod"}, variables["env"].Values())
+	require.Equal(t, []string{"prod", "dev"}, variables["env"].Options)
+	require.Equal(t, []string{".*"}, variables["host"].Values())
+	require.Equal(t, []string{"eu", "us"}, variables["region"].Values())
+}
+
+func TestInterpolateQuery(t *testing.T) {
+	variables := map[string][]string{"env": {"prod"}, "host": {"a", "b.c"}}
+
+	query := simplejson.NewFromAny(map[string]interface{}{
+		"refId":      "$env",
+		"datasource": map[string]interface{}{"type": "prometheus", "uid": "$env"},
+		"expr":       `up{env="$env", host=~"${host}"} [$__interval]`,
+		"nested":     map[string]interface{}{"list": []interface{}{"[[env]]", "${host:csv}", 1}},
+		"rawSql":     "SELECT * FROM t WHERE host IN (${host:sqlstring}) AND env = '${env:raw}'",
+	})
+
+	InterpolateQuery(query, variables)
+
+	require.Equal(t, "$env", query.Get("refId").MustString())
+	require.Equal(t, "$env", query.GetPath("datasource", "uid").MustString())
+	require.Equal(t, `up{env="prod", host=~"(a|b\\.c)"} [$__interval]`, query.Get("expr").MustString())
+	require.Equal(t, []interface{}{"prod", "a,b.c", 1}, query.GetPath("nested", "list").MustArray())
+	require.Equal(t, "SELECT * FROM t WHERE host IN ('a','b.c') AND env = 'prod'", query.Get("rawSql").MustString())
+}
+
+func TestFormatVariableValues(t *testing.T) {
+	values := []string{"a", "b'c"}
+
+	require.Equal(t, "{a,b'c}", FormatValues(values, "", "graphite"))
+	require.Equal(t, "a|b'c", FormatValues(values, "pipe", ""))
+	require.Equal(t, `'a','b\'c'`, FormatValues(values, "singlequote", ""))
+	require.Equal(t, `"a","b'c"`, FormatValues(values, "doublequote", ""))
+	require.Equal(t, "'a','b''c'", FormatValues(values, "", "mysql"))
+	require.Equal(t, "a", FormatValues([]string{"a"}, "", "prometheus"))
+	require.Equal(t, `(a\\.b|c)`, FormatValues([]string{"a.b", "c"}, "regex", "loki"))
+	require.Equal(t, `(a\.b|c)`, FormatValues([]string{"a.b", "c"}, "regex", "graphite"))
+}
@@ -0,0 +1,286 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const rootURL = "/api/scim/v2"
+
+func (s *Service) registerAPIEndpoints(router routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+	reqGrafanaAdmin := middleware.ReqGrafanaAdmin
+
+	router.Group(rootURL, func(scimRoute routing.RouteRegister) {
+		scimRoute.Get("/ServiceProviderConfig", routing.Wrap(s.getServiceProviderConfig))
+		scimRoute.Get("/ResourceTypes", routing.Wrap(s.getResourceTypes))
+
+		scimRoute.Get("/Users", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionUsersRead)), routing.Wrap(s.listUsers))
+		scimRoute.Post("/Users", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionUsersWrite)), routing.Wrap(s.createUser))
+		scimRoute.Get("/Users/:id", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionUsersRead)), routing.Wrap(s.getUser))
+		scimRoute.Put("/Users/:id", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionUsersWrite)), routing.Wrap(s.replaceUser))
+		scimRoute.Patch("/Users/:id", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionUsersWrite)), routing.Wrap(s.patchUser))
+		scimRoute.Delete("/Users/:id", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionUsersWrite)), routing.Wrap(s.deleteUser))
+
+		scimRoute.Get("/Groups", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionGroupsRead)), routing.Wrap(s.listGroups))
+		scimRoute.Post("/Groups", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionGroupsWrite)), routing.Wrap(s.createGroup))
+		scimRoute.Get("/Groups/:id", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionGroupsRead)), routing.Wrap(s.getGroup))
+		scimRoute.Put("/Groups/:id", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionGroupsWrite)), routing.Wrap(s.replaceGroup))
+		scimRoute.Patch("/Groups/:id", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionGroupsWrite)), routing.Wrap(s.patchGroup))
+		scimRoute.Delete("/Groups/:id", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionGroupsWrite)), routing.Wrap(s.deleteGroup))
+	}, middleware.ReqSignedIn)
+}
+
+func (s *Service) listUsers(c *contextmodel.ReqContext) response.Response {
+	query, err := listQuery(c, attrID, attrUserName, attrExternalID, attrEmail)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	list, err := s.ListUsers(c.Req.Context(), query)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimJSON(http.StatusOK, list)
+}
+
+func (s *Service) getUser(c *contextmodel.ReqContext) response.Response {
+	u, err := s.GetUser(c.Req.Context(), c.OrgID, web.Params(c.Req)[":id"])
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimJSON(http.StatusOK, u)
+}
+
+func (s *Service) createUser(c *contextmodel.ReqContext) response.Response {
+	u := User{}
+	if err := bind(c, &u); err != nil {
+		return s.errorResponse(err)
+	}
+	created, err := s.CreateUser(c.Req.Context(), c.OrgID, &u)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimJSON(http.StatusCreated, created).SetHeader("Location", created.Meta.Location)
+}
+
+func (s *Service) replaceUser(c *contextmodel.ReqContext) response.Response {
+	u := User{}
+	if err := bind(c, &u); err != nil {
+		return s.errorResponse(err)
+	}
+	updated, err := s.ReplaceUser(c.Req.Context(), c.OrgID, web.Params(c.Req)[":id"], &u)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimJSON(http.StatusOK, updated)
+}
+
+func (s *Service) patchUser(c *contextmodel.ReqContext) response.Response {
+	patch := PatchRequest{}
+	if err := bind(c, &patch); err != nil {
+		return s.errorResponse(err)
+	}
+	updated, err := s.PatchUser(c.Req.Context(), c.OrgID, web.Params(c.Req)[":id"], patch.Operations)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimJSON(http.StatusOK, updated)
+}
+
+func (s *Service) deleteUser(c *contextmodel.ReqContext) response.Response {
+	if err := s.DeleteUser(c.Req.Context(), c.OrgID, web.Params(c.Req)[":id"]); err != nil {
+		return s.errorResponse(err)
+	}
+	return response.Empty(http.StatusNoContent)
+}
+
+func (s *Service) listGroups(c *contextmodel.ReqContext) response.Response {
+	query, err := listQuery(c, attrID, attrDisplayName)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	list, err := s.ListGroups(c.Req.Context(), query)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimJSON(http.StatusOK, list)
+}
+
+func (s *Service) getGroup(c *contextmodel.ReqContext) response.Response {
+	g, err := s.GetGroup(c.Req.Context(), c.OrgID, web.Params(c.Req)[":id"])
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimJSON(http.StatusOK, g)
+}
+
+func (s *Service) createGroup(c *contextmodel.ReqContext) response.Response {
+	g := Group{}
+	if err := bind(c, &g); err != nil {
+		return s.errorResponse(err)
+	}
+	created, err := s.CreateGroup(c.Req.Context(), c.OrgID, &g)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimJSON(http.StatusCreated, created).SetHeader("Location", created.Meta.Location)
+}
+
+func (s *Service) replaceGroup(c *contextmodel.ReqContext) response.Response {
+	g := Group{}
+	if err := bind(c, &g); err != nil {
+		return s.errorResponse(err)
+	}
+	updated, err := s.ReplaceGroup(c.Req.Context(), c.OrgID, web.Params(c.Req)[":id"], &g)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimJSON(http.StatusOK, updated)
+}
+
+func (s *Service) patchGroup(c *contextmodel.ReqContext) response.Response {
+	patch := PatchRequest{}
+	if err := bind(c, &patch); err != nil {
+		return s.errorResponse(err)
+	}
+	updated, err := s.PatchGroup(c.Req.Context(), c.OrgID, web.Params(c.Req)[":id"], patch.Operations)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimJSON(http.StatusOK, updated)
+}
+
+func (s *Service) deleteGroup(c *contextmodel.ReqContext) response.Response {
+	if err := s.DeleteGroup(c.Req.Context(), c.OrgID, web.Params(c.Req)[":id"]); err != nil {
+		return s.errorResponse(err)
+	}
+	return response.Empty(http.StatusNoContent)
+}
+
+func (s *Service) getServiceProviderConfig(c *contextmodel.ReqContext) response.Response {
+	return scimJSON(http.StatusOK, ServiceProviderConfig{
+		Schemas:        []string{SchemaServiceProviderConfig},
+		Patch:          supported{Supported: true},
+		Bulk:           bulkSupport{Supported: false},
+		Filter:         filterSupport{Supported: true, MaxResults: MaxCount},
+		ChangePassword: supported{Supported: false},
+		Sort:           supported{Supported: false},
+		ETag:           supported{Supported: false},
+		AuthenticationSchemes: []authenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "Service account token",
+			Description: "Authentication with a Grafana service account token sent as a bearer token",
+			Primary:     true,
+		}},
+		Meta: Meta{ResourceType: "ServiceProviderConfig", Location: s.location("ServiceProviderConfig", "")},
+	})
+}
+
+func (s *Service) getResourceTypes(c *contextmodel.ReqContext) response.Response {
+	resourceTypes := []interface{}{
+		ResourceType{
+			Schemas:     []string{SchemaResourceType},
+			ID:          "User",
+			Name:        "User",
+			Endpoint:    "/Users",
+			Description: "Grafana users of the organization",
+			Schema:      SchemaUser,
+			Meta:        Meta{ResourceType: "ResourceType", Location: s.location("ResourceTypes", "User")},
+		},
+		ResourceType{
+			Schemas:     []string{SchemaResourceType},
+			ID:          "Group",
+			Name:        "Group",
+			Endpoint:    "/Groups",
+			Description: "Teams of the organization",
+			Schema:      SchemaGroup,
+			Meta:        Meta{ResourceType: "ResourceType", Location: s.location("ResourceTypes", "Group")},
+		},
+	}
+	return scimJSON(http.StatusOK, ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: int64(len(resourceTypes)),
+		StartIndex:   1,
+		ItemsPerPage: len(resourceTypes),
+		Resources:    resourceTypes,
+	})
+}
+
+func (s *Service) errorResponse(err error) response.Response {
+	var reqErr requestError
+	if errors.As(err, &reqErr) {
+		return scimError(reqErr.status, reqErr.scimType, reqErr.detail)
+	}
+	s.log.Error("SCIM request failed", "error", err)
+	return scimError(http.StatusInternalServerError, "", "Failed to process the SCIM request")
+}
+
+func scimError(status int, scimType, detail string) *response.NormalResponse {
+	return scimJSON(status, Error{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+func scimJSON(status int, body interface{}) *response.NormalResponse {
+	return response.JSON(status, body).SetHeader("Content-Type", ContentType)
+}
+
+// bind decodes the request body, which identity providers send as application/scim+json
+func bind(c *contextmodel.ReqContext, v interface{}) error {
+	mediaType, _, err := mime.ParseMediaType(c.Req.Header.Get("Content-Type"))
+	if err != nil || (mediaType != ContentType && mediaType != "application/json") {
+		return badRequest(errorTypeInvalidSyntax, "unsupported content type %q", c.Req.Header.Get("Content-Type"))
+	}
+	defer func() { _ = c.Req.Body.Close() }()
+	if err := json.NewDecoder(c.Req.Body).Decode(v); err != nil {
+		return badRequest(errorTypeInvalidSyntax, "invalid request body: %s", err)
+	}
+	return nil
+}
+
+func listQuery(c *contextmodel.ReqContext, filterAttributes ...string) (ListQuery, error) {
+	query := ListQuery{
+		OrgID:      c.OrgID,
+		StartIndex: c.QueryInt("startIndex"),
+		Count:      DefaultCount,
+	}
+	if query.StartIndex < 1 {
+		query.StartIndex = 1
+	}
+	if c.Query("count") != "" {
+		query.Count = c.QueryInt("count")
+		if query.Count < 0 {
+			query.Count = 0
+		}
+		if query.Count > MaxCount {
+			query.Count = MaxCount
+		}
+	}
+
+	filter, err := ParseFilter(c.Query("filter"), filterAttributes...)
+	if err != nil {
+		return query, err
+	}
+	query.Filter = filter
+
+	for _, attribute := range strings.Split(c.Query("excludedAttributes"), ",") {
+		if normalizeAttribute(strings.TrimSpace(attribute)) == "members" {
+			query.ExcludeMembers = true
+		}
+	}
+	return query, nil
+}
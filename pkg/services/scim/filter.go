@@ -0,0 +1,78 @@
+package scim
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const (
+	attrID          = "id"
+	attrUserName    = "username"
+	attrExternalID  = "externalid"
+	attrEmail       = "emails.value"
+	attrDisplayName = "displayname"
+)
+
+// Filter is an equality filter on a single attribute, the form identity providers
+// use to look up a user or a group before creating it.
+type Filter struct {
+	// Attribute is the lower case attribute path, such as "username" or "emails.value"
+	Attribute string
+	Value     string
+}
+
+// ParseFilter parses filter expressions of the form `attribute eq "value"`.
+// Other operators and logical expressions are rejected as invalid filters.
+func ParseFilter(expr string, attributes ...string) (*Filter, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, nil
+	}
+
+	parts := strings.SplitN(expr, " ", 3)
+	if len(parts) != 3 {
+		return nil, badRequest(errorTypeInvalidFilter, "invalid filter %q", expr)
+	}
+	if !strings.EqualFold(parts[1], "eq") {
+		return nil, badRequest(errorTypeInvalidFilter, "unsupported filter operator %q, only eq is supported", parts[1])
+	}
+
+	attribute := normalizeAttribute(parts[0])
+	if !contains(attributes, attribute) {
+		return nil, badRequest(errorTypeInvalidFilter, "filtering on attribute %q is not supported", parts[0])
+	}
+
+	raw := strings.TrimSpace(parts[2])
+	var value string
+	if strings.HasPrefix(raw, `"`) {
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return nil, badRequest(errorTypeInvalidFilter, "invalid filter value %s, only a single comparison is supported", raw)
+		}
+	} else {
+		if strings.ContainsAny(raw, " ()[]") {
+			return nil, badRequest(errorTypeInvalidFilter, "invalid filter value %s, only a single comparison is supported", raw)
+		}
+		value = raw
+	}
+
+	return &Filter{Attribute: attribute, Value: value}, nil
+}
+
+// normalizeAttribute lower cases an attribute path and removes the schema prefix of fully
+// qualified attributes, mapping multi-valued emails to the value sub-attribute.
+func normalizeAttribute(attribute string) string {
+	attribute = strings.ToLower(stripSchema(attribute))
+	if attribute == "emails" {
+		return attrEmail
+	}
+	return attribute
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package scim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	attributes := []string{attrID, attrUserName, attrExternalID, attrEmail}
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected *Filter
+		wantErr  bool
+	}{
+		{name: "empty filter", expr: "", expected: nil},
+		{name: "userName", expr: `userName eq "jdoe"`, expected: &Filter{Attribute: attrUserName, Value: "jdoe"}},
+		{name: "case insensitive attribute and operator", expr: `USERNAME EQ "jdoe"`, expected: &Filter{Attribute: attrUserName, Value: "jdoe"}},
+		{name: "value with spaces and escapes", expr: `externalId eq "a \"b\" c"`, expected: &Filter{Attribute: attrExternalID, Value: `a "b" c`}},
+		{name: "emails", expr: `emails eq "jdoe@example.org"`, expected: &Filter{Attribute: attrEmail, Value: "jdoe@example.org"}},
+		{name: "emails value", expr: `emails.value eq "jdoe@example.org"`, expected: &Filter{Attribute: attrEmail, Value: "jdoe@example.org"}},
+		{name: "fully qualified attribute", expr: `urn:ietf:params:scim:schemas:core:2.0:User:userName eq "jdoe"`, expected: &Filter{Attribute: attrUserName, Value: "jdoe"}},
+		{name: "unquoted value", expr: `id eq 42`, expected: &Filter{Attribute: attrID, Value: "42"}},
+		{name: "unsupported operator", expr: `userName co "jdoe"`, wantErr: true},
+		{name: "unsupported attribute", expr: `title eq "engineer"`, wantErr: true},
+		{name: "logical expression", expr: `userName eq "jdoe" and active eq true`, wantErr: true},
+		{name: "missing value", expr: `userName eq`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := ParseFilter(tt.expr, attributes...)
+			if tt.wantErr {
+				var reqErr requestError
+				require.ErrorAs(t, err, &reqErr)
+				assert.Equal(t, errorTypeInvalidFilter, reqErr.scimType)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, filter)
+		})
+	}
+}
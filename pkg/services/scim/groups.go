@@ -0,0 +1,323 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/team"
+)
+
+// memberPermission is the name of the team permission of members that are not team admins
+const memberPermission = "Member"
+
+func (s *Service) GetGroup(ctx context.Context, orgID int64, id string) (*Group, error) {
+	t, err := s.getTeam(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toGroup(ctx, t, true)
+}
+
+func (s *Service) ListGroups(ctx context.Context, query ListQuery) (*ListResponse, error) {
+	if query.Filter != nil {
+		teams, err := s.findTeams(ctx, query.OrgID, query.Filter)
+		if err != nil {
+			return nil, err
+		}
+		return s.groupList(ctx, query, int64(len(teams)), filterPage(teams, query))
+	}
+
+	count := query.Count
+	if count == 0 {
+		count = 1
+	}
+	page, limit, skip := paginate(query.StartIndex, count)
+	result, err := s.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{
+		OrgID:        query.OrgID,
+		Page:         page,
+		Limit:        limit,
+		UserIDFilter: team.FilterIgnoreUser,
+		SignedInUser: queryUser(query.OrgID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	teams := result.Teams
+	if skip < len(teams) {
+		teams = teams[skip:]
+	} else {
+		teams = nil
+	}
+	if query.Count == 0 {
+		teams = nil
+	}
+	return s.groupList(ctx, query, result.TotalCount, teams)
+}
+
+func (s *Service) CreateGroup(ctx context.Context, orgID int64, g *Group) (*Group, error) {
+	if err := validateGroup(g); err != nil {
+		return nil, err
+	}
+	memberIDs, err := s.memberIDs(ctx, orgID, g.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := s.teamService.CreateTeam(g.DisplayName, "", orgID)
+	if err != nil {
+		if errors.Is(err, team.ErrTeamNameTaken) {
+			return nil, conflict("a group with the displayName %q already exists", g.DisplayName)
+		}
+		return nil, err
+	}
+
+	if err := s.setMembers(ctx, orgID, t.ID, nil, memberIDs); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Provisioned team", "orgID", orgID, "teamID", t.ID, "name", t.Name)
+	return s.GetGroup(ctx, orgID, strconv.FormatInt(t.ID, 10))
+}
+
+func (s *Service) ReplaceGroup(ctx context.Context, orgID int64, id string, g *Group) (*Group, error) {
+	t, err := s.getTeam(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.toGroup(ctx, t, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.updateGroup(ctx, t, current, g); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, orgID, id)
+}
+
+func (s *Service) PatchGroup(ctx context.Context, orgID int64, id string, operations []PatchOperation) (*Group, error) {
+	t, err := s.getTeam(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.toGroup(ctx, t, true)
+	if err != nil {
+		return nil, err
+	}
+
+	patched := *current
+	patched.Members = append([]Reference{}, current.Members...)
+	if err := applyGroupPatch(&patched, operations); err != nil {
+		return nil, err
+	}
+
+	if err := s.updateGroup(ctx, t, current, &patched); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, orgID, id)
+}
+
+func (s *Service) DeleteGroup(ctx context.Context, orgID int64, id string) error {
+	t, err := s.getTeam(ctx, orgID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.teamService.DeleteTeam(ctx, &team.DeleteTeamCommand{OrgID: orgID, ID: t.ID}); err != nil {
+		return err
+	}
+	s.log.Info("Deprovisioned team", "orgID", orgID, "teamID", t.ID, "name", t.Name)
+	return nil
+}
+
+func (s *Service) updateGroup(ctx context.Context, t *team.TeamDTO, current, g *Group) error {
+	if err := validateGroup(g); err != nil {
+		return err
+	}
+	memberIDs, err := s.memberIDs(ctx, t.OrgID, g.Members)
+	if err != nil {
+		return err
+	}
+
+	if g.DisplayName != current.DisplayName {
+		if err := s.teamService.UpdateTeam(ctx, &team.UpdateTeamCommand{
+			ID:    t.ID,
+			Name:  g.DisplayName,
+			Email: t.Email,
+			OrgID: t.OrgID,
+		}); err != nil {
+			if errors.Is(err, team.ErrTeamNameTaken) {
+				return conflict("a group with the displayName %q already exists", g.DisplayName)
+			}
+			return err
+		}
+	}
+
+	currentIDs := make([]int64, 0, len(current.Members))
+	for _, member := range current.Members {
+		userID, err := strconv.ParseInt(member.Value, 10, 64)
+		if err != nil {
+			return err
+		}
+		currentIDs = append(currentIDs, userID)
+	}
+	return s.setMembers(ctx, t.OrgID, t.ID, currentIDs, memberIDs)
+}
+
+// setMembers adds and removes team members so that the team members are the given users.
+// Members keep their team permission, so that team admins are not downgraded.
+func (s *Service) setMembers(ctx context.Context, orgID, teamID int64, current, members []int64) error {
+	teamIDString := strconv.FormatInt(teamID, 10)
+
+	for _, userID := range members {
+		if containsID(current, userID) {
+			continue
+		}
+		if _, err := s.teamPermissionsService.SetUserPermission(ctx, orgID, ac.User{ID: userID}, teamIDString, memberPermission); err != nil {
+			return err
+		}
+		s.log.Debug("Added team member", "teamID", teamID, "userID", userID)
+	}
+
+	for _, userID := range current {
+		if containsID(members, userID) {
+			continue
+		}
+		if _, err := s.teamPermissionsService.SetUserPermission(ctx, orgID, ac.User{ID: userID}, teamIDString, ""); err != nil {
+			if errors.Is(err, team.ErrLastTeamAdmin) {
+				return badRequest(errorTypeMutability, "cannot remove the last admin of the team")
+			}
+			return err
+		}
+		s.log.Debug("Removed team member", "teamID", teamID, "userID", userID)
+	}
+	return nil
+}
+
+// memberIDs returns the user IDs of the members, which must be users of the organization
+func (s *Service) memberIDs(ctx context.Context, orgID int64, members []Reference) ([]int64, error) {
+	ids := make([]int64, 0, len(members))
+	for _, member := range members {
+		orgUser, err := s.getOrgUser(ctx, orgID, member.Value)
+		if err != nil {
+			var reqErr requestError
+			if errors.As(err, &reqErr) {
+				return nil, badRequest(errorTypeInvalidValue, "member %q is not a user of the organization", member.Value)
+			}
+			return nil, err
+		}
+		if !containsID(ids, orgUser.UserID) {
+			ids = append(ids, orgUser.UserID)
+		}
+	}
+	return ids, nil
+}
+
+func (s *Service) getTeam(ctx context.Context, orgID int64, id string) (*team.TeamDTO, error) {
+	teamID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, notFound("group %s not found", id)
+	}
+
+	t, err := s.teamService.GetTeamByID(ctx, &team.GetTeamByIDQuery{
+		OrgID:        orgID,
+		ID:           teamID,
+		SignedInUser: queryUser(orgID),
+	})
+	if err != nil {
+		if errors.Is(err, team.ErrTeamNotFound) {
+			return nil, notFound("group %s not found", id)
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+// findTeams returns the teams of the organization matching the filter
+func (s *Service) findTeams(ctx context.Context, orgID int64, filter *Filter) ([]*team.TeamDTO, error) {
+	switch filter.Attribute {
+	case attrID:
+		t, err := s.getTeam(ctx, orgID, filter.Value)
+		if err != nil {
+			var reqErr requestError
+			if errors.As(err, &reqErr) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return []*team.TeamDTO{t}, nil
+	case attrDisplayName:
+		result, err := s.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{
+			OrgID:        orgID,
+			Name:         filter.Value,
+			UserIDFilter: team.FilterIgnoreUser,
+			SignedInUser: queryUser(orgID),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result.Teams, nil
+	}
+	return nil, badRequest(errorTypeInvalidFilter, "filtering groups on %q is not supported", filter.Attribute)
+}
+
+func (s *Service) toGroup(ctx context.Context, t *team.TeamDTO, withMembers bool) (*Group, error) {
+	id := strconv.FormatInt(t.ID, 10)
+	g := &Group{
+		Schemas:     []string{SchemaGroup},
+		ID:          id,
+		DisplayName: t.Name,
+		Meta: &Meta{
+			ResourceType: "Group",
+			Location:     s.location("Groups", id),
+		},
+	}
+	if !withMembers {
+		return g, nil
+	}
+
+	members, err := s.teamService.GetTeamMembers(ctx, &team.GetTeamMembersQuery{
+		OrgID:        t.OrgID,
+		TeamID:       t.ID,
+		SignedInUser: queryUser(t.OrgID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		userID := strconv.FormatInt(member.UserID, 10)
+		g.Members = append(g.Members, Reference{Value: userID, Ref: s.location("Users", userID), Display: member.Login})
+	}
+	return g, nil
+}
+
+func (s *Service) groupList(ctx context.Context, query ListQuery, total int64, teams []*team.TeamDTO) (*ListResponse, error) {
+	list := newListResponse(query, total)
+	for _, t := range teams {
+		g, err := s.toGroup(ctx, t, !query.ExcludeMembers)
+		if err != nil {
+			return nil, err
+		}
+		list.Resources = append(list.Resources, g)
+	}
+	list.ItemsPerPage = len(list.Resources)
+	return list, nil
+}
+
+func validateGroup(g *Group) error {
+	if strings.TrimSpace(g.DisplayName) == "" {
+		return badRequest(errorTypeInvalidValue, "displayName is required")
+	}
+	return nil
+}
+
+func containsID(ids []int64, id int64) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,321 @@
+package scim
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+const (
+	opAdd     = "add"
+	opReplace = "replace"
+	opRemove  = "remove"
+)
+
+// patchPath is a parsed attribute path of a PATCH operation, such as `name.givenName`
+// or `members[value eq "2"]`.
+type patchPath struct {
+	attribute    string
+	filter       *Filter
+	subAttribute string
+}
+
+func parsePatchPath(path string) (patchPath, error) {
+	p := patchPath{}
+	path = stripSchema(strings.TrimSpace(path))
+
+	if start := strings.Index(path, "["); start >= 0 {
+		end := strings.Index(path, "]")
+		if end < start {
+			return p, badRequest(errorTypeInvalidPath, "invalid path %q", path)
+		}
+		filter, err := ParseFilter(path[start+1:end], "value", "type")
+		if err != nil || filter == nil {
+			return p, badRequest(errorTypeInvalidPath, "invalid value filter in path %q", path)
+		}
+		p.filter = filter
+		p.subAttribute = strings.ToLower(strings.TrimPrefix(path[end+1:], "."))
+		path = path[:start]
+	} else if dot := strings.Index(path, "."); dot >= 0 {
+		p.subAttribute = strings.ToLower(path[dot+1:])
+		path = path[:dot]
+	}
+
+	p.attribute = strings.ToLower(path)
+	if p.attribute == "" {
+		return p, badRequest(errorTypeInvalidPath, "invalid path %q", path)
+	}
+	return p, nil
+}
+
+// applyUserPatch applies PATCH operations to the SCIM representation of a user.
+// Attributes Grafana does not store are ignored.
+func applyUserPatch(u *User, operations []PatchOperation) error {
+	return applyPatch(operations, func(op string, p patchPath, value json.RawMessage) error {
+		return applyUserAttribute(u, op, p, value)
+	})
+}
+
+// applyGroupPatch applies PATCH operations to the SCIM representation of a group.
+// Attributes Grafana does not store are ignored.
+func applyGroupPatch(g *Group, operations []PatchOperation) error {
+	return applyPatch(operations, func(op string, p patchPath, value json.RawMessage) error {
+		return applyGroupAttribute(g, op, p, value)
+	})
+}
+
+func applyPatch(operations []PatchOperation, apply func(op string, p patchPath, value json.RawMessage) error) error {
+	if len(operations) == 0 {
+		return badRequest(errorTypeInvalidSyntax, "no patch operations")
+	}
+
+	for _, operation := range operations {
+		op := strings.ToLower(operation.Op)
+		if op != opAdd && op != opReplace && op != opRemove {
+			return badRequest(errorTypeInvalidSyntax, "unsupported patch operation %q", operation.Op)
+		}
+
+		if operation.Path != "" {
+			p, err := parsePatchPath(operation.Path)
+			if err != nil {
+				return err
+			}
+			if err := apply(op, p, operation.Value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// without a path, the value holds the attributes to add or replace
+		if op == opRemove {
+			return badRequest(errorTypeNoTarget, "remove operations require a path")
+		}
+		attributes := map[string]json.RawMessage{}
+		if err := json.Unmarshal(operation.Value, &attributes); err != nil {
+			return badRequest(errorTypeInvalidValue, "patch operations without a path require an object value")
+		}
+		for key, value := range attributes {
+			p, err := parsePatchPath(key)
+			if err != nil {
+				return err
+			}
+			if err := apply(op, p, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func applyUserAttribute(u *User, op string, p patchPath, value json.RawMessage) error {
+	switch p.attribute {
+	case "username":
+		if op == opRemove {
+			return badRequest(errorTypeMutability, "userName cannot be removed")
+		}
+		return unmarshalString(value, &u.UserName)
+	case "active":
+		if op == opRemove {
+			return badRequest(errorTypeMutability, "active cannot be removed")
+		}
+		active, err := unmarshalBool(value)
+		if err != nil {
+			return err
+		}
+		u.Active = &active
+	case "displayname":
+		if op == opRemove {
+			u.DisplayName = ""
+			return nil
+		}
+		return unmarshalString(value, &u.DisplayName)
+	case "externalid":
+		if op == opRemove {
+			u.ExternalID = ""
+			return nil
+		}
+		return unmarshalString(value, &u.ExternalID)
+	case "name":
+		return applyName(u, op, p, value)
+	case "emails":
+		return applyEmails(u, op, value)
+	}
+	return nil
+}
+
+// applyName updates the name of the user. The name takes precedence over the display
+// name from then on, since Grafana only stores a single name.
+func applyName(u *User, op string, p patchPath, value json.RawMessage) error {
+	u.DisplayName = ""
+	if u.Name == nil {
+		u.Name = &Name{}
+	}
+
+	if p.subAttribute == "" {
+		if op == opRemove {
+			u.Name = nil
+			return nil
+		}
+		name := Name{}
+		if err := json.Unmarshal(value, &name); err != nil {
+			return badRequest(errorTypeInvalidValue, "invalid name: %s", err)
+		}
+		u.Name = &name
+		return nil
+	}
+
+	var target *string
+	switch p.subAttribute {
+	case "formatted":
+		target = &u.Name.Formatted
+	case "givenname":
+		target = &u.Name.GivenName
+		u.Name.Formatted = ""
+	case "familyname":
+		target = &u.Name.FamilyName
+		u.Name.Formatted = ""
+	default:
+		return nil
+	}
+
+	if op == opRemove {
+		*target = ""
+		return nil
+	}
+	return unmarshalString(value, target)
+}
+
+// applyEmails updates the emails of the user. A single value, such as the one of
+// `emails[type eq "work"].value`, replaces the primary email.
+func applyEmails(u *User, op string, value json.RawMessage) error {
+	if op == opRemove {
+		u.Emails = nil
+		return nil
+	}
+
+	var email string
+	if err := json.Unmarshal(value, &email); err == nil {
+		u.Emails = []Email{{Value: email, Primary: true}}
+		return nil
+	}
+
+	var emails []Email
+	if err := json.Unmarshal(value, &emails); err != nil {
+		single := Email{}
+		if err := json.Unmarshal(value, &single); err != nil {
+			return badRequest(errorTypeInvalidValue, "invalid emails: %s", err)
+		}
+		emails = []Email{single}
+	}
+	if op == opAdd {
+		emails = append(emails, u.Emails...)
+	}
+	u.Emails = emails
+	return nil
+}
+
+func applyGroupAttribute(g *Group, op string, p patchPath, value json.RawMessage) error {
+	switch p.attribute {
+	case "displayname":
+		if op == opRemove {
+			return badRequest(errorTypeMutability, "displayName cannot be removed")
+		}
+		return unmarshalString(value, &g.DisplayName)
+	case "members":
+		return applyMembers(g, op, p, value)
+	}
+	return nil
+}
+
+func applyMembers(g *Group, op string, p patchPath, value json.RawMessage) error {
+	if p.filter != nil {
+		if op != opRemove || p.filter.Attribute != "value" {
+			return badRequest(errorTypeInvalidPath, "only members can be removed by value")
+		}
+		g.Members = removeMembers(g.Members, []Reference{{Value: p.filter.Value}})
+		return nil
+	}
+
+	var members []Reference
+	if len(value) > 0 && string(value) != "null" {
+		if err := json.Unmarshal(value, &members); err != nil {
+			member := Reference{}
+			if err := json.Unmarshal(value, &member); err != nil {
+				return badRequest(errorTypeInvalidValue, "invalid members: %s", err)
+			}
+			members = []Reference{member}
+		}
+	}
+
+	switch op {
+	case opAdd:
+		for _, member := range members {
+			if !hasMember(g.Members, member.Value) {
+				g.Members = append(g.Members, member)
+			}
+		}
+	case opReplace:
+		g.Members = members
+	case opRemove:
+		if len(members) == 0 {
+			g.Members = nil
+		} else {
+			g.Members = removeMembers(g.Members, members)
+		}
+	}
+	return nil
+}
+
+func removeMembers(members []Reference, removed []Reference) []Reference {
+	result := make([]Reference, 0, len(members))
+	for _, member := range members {
+		if !hasMember(removed, member.Value) {
+			result = append(result, member)
+		}
+	}
+	return result
+}
+
+func hasMember(members []Reference, value string) bool {
+	for _, member := range members {
+		if member.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func unmarshalString(value json.RawMessage, target *string) error {
+	if err := json.Unmarshal(value, target); err != nil {
+		return badRequest(errorTypeInvalidValue, "expected a string value: %s", err)
+	}
+	return nil
+}
+
+// unmarshalBool also accepts booleans sent as strings, such as "False", which some
+// identity providers use in PATCH requests.
+func unmarshalBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b, nil
+		}
+	}
+	return false, badRequest(errorTypeInvalidValue, "expected a boolean value, got %s", string(value))
+}
+
+// stripSchema removes the schema prefix of fully qualified attribute paths
+func stripSchema(path string) string {
+	lower := strings.ToLower(path)
+	for _, schema := range []string{SchemaUser, SchemaGroup} {
+		prefix := strings.ToLower(schema) + ":"
+		if strings.HasPrefix(lower, prefix) {
+			return path[len(prefix):]
+		}
+	}
+	return path
+}
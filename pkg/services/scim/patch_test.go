@@ -0,0 +1,126 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyUserPatch(t *testing.T) {
+	active := true
+	newUser := func() *User {
+		return &User{
+			UserName:    "jdoe",
+			Name:        &Name{Formatted: "John Doe"},
+			DisplayName: "John Doe",
+			Emails:      []Email{{Value: "jdoe@example.org", Primary: true}},
+			Active:      &active,
+		}
+	}
+
+	t.Run("should disable the user with a string boolean", func(t *testing.T) {
+		u := newUser()
+		err := applyUserPatch(u, operations(t, `[{"op": "Replace", "path": "active", "value": "False"}]`))
+		require.NoError(t, err)
+		require.NotNil(t, u.Active)
+		assert.False(t, *u.Active)
+	})
+
+	t.Run("should replace attributes without a path", func(t *testing.T) {
+		u := newUser()
+		err := applyUserPatch(u, operations(t, `[{"op": "replace", "value": {"active": false, "userName": "john", "externalId": "abc"}}]`))
+		require.NoError(t, err)
+		assert.False(t, *u.Active)
+		assert.Equal(t, "john", u.UserName)
+		assert.Equal(t, "abc", u.ExternalID)
+	})
+
+	t.Run("should use the patched name parts", func(t *testing.T) {
+		u := newUser()
+		err := applyUserPatch(u, operations(t, `[
+			{"op": "replace", "path": "name.givenName", "value": "Jane"},
+			{"op": "replace", "path": "name.familyName", "value": "Smith"}
+		]`))
+		require.NoError(t, err)
+		assert.Equal(t, "Jane Smith", userDisplayName(u))
+	})
+
+	t.Run("should replace the primary email with a value filter path", func(t *testing.T) {
+		u := newUser()
+		err := applyUserPatch(u, operations(t, `[{"op": "replace", "path": "emails[type eq \"work\"].value", "value": "jane@example.org"}]`))
+		require.NoError(t, err)
+		assert.Equal(t, "jane@example.org", primaryEmail(u))
+	})
+
+	t.Run("should ignore attributes Grafana does not store", func(t *testing.T) {
+		u := newUser()
+		err := applyUserPatch(u, operations(t, `[{"op": "add", "path": "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department", "value": "R&D"}]`))
+		require.NoError(t, err)
+		assert.Equal(t, newUser(), u)
+	})
+
+	t.Run("should not remove the userName", func(t *testing.T) {
+		err := applyUserPatch(newUser(), operations(t, `[{"op": "remove", "path": "userName"}]`))
+		var reqErr requestError
+		require.ErrorAs(t, err, &reqErr)
+		assert.Equal(t, errorTypeMutability, reqErr.scimType)
+	})
+
+	t.Run("should reject unknown operations", func(t *testing.T) {
+		err := applyUserPatch(newUser(), operations(t, `[{"op": "move", "path": "userName", "value": "x"}]`))
+		var reqErr requestError
+		require.ErrorAs(t, err, &reqErr)
+		assert.Equal(t, errorTypeInvalidSyntax, reqErr.scimType)
+	})
+}
+
+func TestApplyGroupPatch(t *testing.T) {
+	newGroup := func() *Group {
+		return &Group{DisplayName: "Developers", Members: []Reference{{Value: "1"}, {Value: "2"}}}
+	}
+
+	t.Run("should add members", func(t *testing.T) {
+		g := newGroup()
+		err := applyGroupPatch(g, operations(t, `[{"op": "Add", "path": "members", "value": [{"value": "2"}, {"value": "3"}]}]`))
+		require.NoError(t, err)
+		assert.Equal(t, []Reference{{Value: "1"}, {Value: "2"}, {Value: "3"}}, g.Members)
+	})
+
+	t.Run("should remove a member with a value filter", func(t *testing.T) {
+		g := newGroup()
+		err := applyGroupPatch(g, operations(t, `[{"op": "remove", "path": "members[value eq \"1\"]"}]`))
+		require.NoError(t, err)
+		assert.Equal(t, []Reference{{Value: "2"}}, g.Members)
+	})
+
+	t.Run("should remove members listed in the value", func(t *testing.T) {
+		g := newGroup()
+		err := applyGroupPatch(g, operations(t, `[{"op": "Remove", "path": "members", "value": [{"value": "2"}]}]`))
+		require.NoError(t, err)
+		assert.Equal(t, []Reference{{Value: "1"}}, g.Members)
+	})
+
+	t.Run("should remove all members", func(t *testing.T) {
+		g := newGroup()
+		err := applyGroupPatch(g, operations(t, `[{"op": "remove", "path": "members"}]`))
+		require.NoError(t, err)
+		assert.Empty(t, g.Members)
+	})
+
+	t.Run("should replace the display name and members without a path", func(t *testing.T) {
+		g := newGroup()
+		err := applyGroupPatch(g, operations(t, `[{"op": "replace", "value": {"id": "5", "displayName": "Platform", "members": [{"value": "3"}]}}]`))
+		require.NoError(t, err)
+		assert.Equal(t, "Platform", g.DisplayName)
+		assert.Equal(t, []Reference{{Value: "3"}}, g.Members)
+	})
+}
+
+func operations(t *testing.T, raw string) []PatchOperation {
+	t.Helper()
+	var ops []PatchOperation
+	require.NoError(t, json.Unmarshal([]byte(raw), &ops))
+	return ops
+}
@@ -0,0 +1,34 @@
+package scim
+
+import (
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+const (
+	ActionUsersRead   = "scim.users:read"
+	ActionUsersWrite  = "scim.users:write"
+	ActionGroupsRead  = "scim.groups:read"
+	ActionGroupsWrite = "scim.groups:write"
+)
+
+var provisionerRole = ac.RoleDTO{
+	Name:        "fixed:scim:provisioner",
+	DisplayName: "SCIM provisioner",
+	Description: "Provision users and teams of the organization through the SCIM API",
+	Group:       "SCIM",
+	Permissions: []ac.Permission{
+		{Action: ActionUsersRead},
+		{Action: ActionUsersWrite},
+		{Action: ActionGroupsRead},
+		{Action: ActionGroupsWrite},
+	},
+}
+
+// declareFixedRoles only grants the SCIM permissions to Grafana server admins, since
+// provisioning creates and disables users. Service accounts get them through a custom role.
+func declareFixedRoles(service ac.Service) error {
+	return service.DeclareFixedRoles(ac.RoleRegistration{
+		Role:   provisionerRole,
+		Grants: []string{ac.RoleGrafanaAdmin},
+	})
+}
@@ -0,0 +1,90 @@
+package scim
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Service is a SCIM 2.0 server (RFC 7643 and RFC 7644) letting an identity provider
+// provision the users and teams of the organization of the service account calling it.
+// SCIM users map to Grafana users, SCIM groups map to teams.
+type Service struct {
+	cfg                    *setting.Cfg
+	log                    log.Logger
+	accessControl          ac.AccessControl
+	accesscontrolService   ac.Service
+	userService            user.Service
+	orgService             org.Service
+	teamService            team.Service
+	teamPermissionsService ac.TeamPermissionsService
+	authInfoService        login.AuthInfoService
+	authTokenService       auth.UserTokenService
+}
+
+func ProvideService(cfg *setting.Cfg, routeRegister routing.RouteRegister, accessControl ac.AccessControl,
+	accesscontrolService ac.Service, userService user.Service, orgService org.Service, teamService team.Service,
+	teamPermissionsService ac.TeamPermissionsService, authInfoService login.AuthInfoService,
+	authTokenService auth.UserTokenService) (*Service, error) {
+	s := &Service{
+		cfg:                    cfg,
+		log:                    log.New("scim"),
+		accessControl:          accessControl,
+		accesscontrolService:   accesscontrolService,
+		userService:            userService,
+		orgService:             orgService,
+		teamService:            teamService,
+		teamPermissionsService: teamPermissionsService,
+		authInfoService:        authInfoService,
+		authTokenService:       authTokenService,
+	}
+
+	if !cfg.SCIMEnabled {
+		return s, nil
+	}
+
+	if !org.RoleType(cfg.SCIMDefaultOrgRole).IsValid() {
+		return nil, fmt.Errorf("invalid SCIM default organization role %q", cfg.SCIMDefaultOrgRole)
+	}
+
+	if !accessControl.IsDisabled() {
+		if err := declareFixedRoles(accesscontrolService); err != nil {
+			return nil, err
+		}
+	}
+
+	s.registerAPIEndpoints(routeRegister)
+
+	return s, nil
+}
+
+// queryUser is used to look up teams and team members of the organization, the
+// access of the caller being checked by the SCIM permissions instead.
+func queryUser(orgID int64) *user.SignedInUser {
+	return &user.SignedInUser{
+		OrgID: orgID,
+		Permissions: map[int64]map[string][]string{orgID: {
+			ac.ActionTeamsRead:    {ac.ScopeTeamsAll},
+			ac.ActionOrgUsersRead: {ac.ScopeUsersAll},
+		}},
+	}
+}
+
+// paginate converts the 1-based SCIM start index and count to a Grafana page and limit.
+// When the start index is not aligned on a page, the results start on the first page
+// and the first skip results are left out.
+func paginate(startIndex, count int) (page, limit, skip int) {
+	offset := startIndex - 1
+	if offset%count == 0 {
+		return offset/count + 1, count, 0
+	}
+	return 1, offset + count, offset
+}
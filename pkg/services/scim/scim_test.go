@@ -0,0 +1,240 @@
+package scim
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/auth/authtest"
+	"github.com/grafana/grafana/pkg/services/login/logintest"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		startIndex, count           int
+		expectedPage, expectedLimit int
+		expectedSkip                int
+	}{
+		{startIndex: 1, count: 100, expectedPage: 1, expectedLimit: 100, expectedSkip: 0},
+		{startIndex: 101, count: 100, expectedPage: 2, expectedLimit: 100, expectedSkip: 0},
+		{startIndex: 11, count: 5, expectedPage: 3, expectedLimit: 5, expectedSkip: 0},
+		{startIndex: 3, count: 5, expectedPage: 1, expectedLimit: 7, expectedSkip: 2},
+	}
+
+	for _, tt := range tests {
+		page, limit, skip := paginate(tt.startIndex, tt.count)
+		assert.Equal(t, tt.expectedPage, page)
+		assert.Equal(t, tt.expectedLimit, limit)
+		assert.Equal(t, tt.expectedSkip, skip)
+	}
+}
+
+func TestService_PatchUser(t *testing.T) {
+	t.Run("should disable the user and revoke their sessions", func(t *testing.T) {
+		s, userService, tokenService := setupTestService(t)
+		var disabled *user.DisableUserCommand
+		userService.DisableFn = func(ctx context.Context, cmd *user.DisableUserCommand) error {
+			disabled = cmd
+			return nil
+		}
+		var revokedUserID int64
+		tokenService.RevokeAllUserTokensProvider = func(ctx context.Context, userID int64) error {
+			revokedUserID = userID
+			return nil
+		}
+
+		_, err := s.PatchUser(context.Background(), 1, "2", operations(t, `[{"op": "replace", "path": "active", "value": false}]`))
+		require.NoError(t, err)
+
+		require.NotNil(t, disabled)
+		assert.Equal(t, int64(2), disabled.UserID)
+		assert.True(t, disabled.IsDisabled)
+		assert.Equal(t, int64(2), revokedUserID)
+	})
+
+	t.Run("should not update unchanged users", func(t *testing.T) {
+		s, userService, tokenService := setupTestService(t)
+		userService.DisableFn = func(ctx context.Context, cmd *user.DisableUserCommand) error {
+			t.Fatal("user should not be disabled")
+			return nil
+		}
+		tokenService.RevokeAllUserTokensProvider = func(ctx context.Context, userID int64) error {
+			t.Fatal("sessions should not be revoked")
+			return nil
+		}
+
+		u, err := s.PatchUser(context.Background(), 1, "2", operations(t, `[{"op": "replace", "path": "active", "value": "True"}]`))
+		require.NoError(t, err)
+		assert.Equal(t, "jdoe", u.UserName)
+	})
+
+	t.Run("should refuse to update server admins", func(t *testing.T) {
+		s, userService, _ := setupTestService(t)
+		userService.ExpectedUser = &user.User{ID: 2, Login: "jdoe", IsAdmin: true}
+		userService.DisableFn = func(ctx context.Context, cmd *user.DisableUserCommand) error {
+			t.Fatal("user should not be disabled")
+			return nil
+		}
+
+		_, err := s.PatchUser(context.Background(), 1, "2", operations(t, `[{"op": "replace", "path": "active", "value": false}]`))
+		var reqErr requestError
+		require.ErrorAs(t, err, &reqErr)
+		assert.Equal(t, http.StatusForbidden, reqErr.status)
+
+		_, err = s.ReplaceUser(context.Background(), 1, "2", &User{UserName: "admin", Emails: []Email{{Value: "admin@example.org", Primary: true}}})
+		require.ErrorAs(t, err, &reqErr)
+		assert.Equal(t, http.StatusForbidden, reqErr.status)
+	})
+
+	t.Run("should refuse to update members of other organizations", func(t *testing.T) {
+		s, userService, _ := setupTestService(t)
+		s.orgService.(*orgtest.FakeOrgService).ExpectedUserOrgDTO = []*org.UserOrgDTO{{OrgID: 1}, {OrgID: 2}}
+		userService.DisableFn = func(ctx context.Context, cmd *user.DisableUserCommand) error {
+			t.Fatal("user should not be disabled")
+			return nil
+		}
+
+		_, err := s.PatchUser(context.Background(), 1, "2", operations(t, `[{"op": "replace", "path": "active", "value": false}]`))
+		var reqErr requestError
+		require.ErrorAs(t, err, &reqErr)
+		assert.Equal(t, http.StatusForbidden, reqErr.status)
+
+		_, err = s.PatchUser(context.Background(), 1, "2", operations(t, `[{"op": "replace", "path": "userName", "value": "jsmith"}]`))
+		require.ErrorAs(t, err, &reqErr)
+		assert.Equal(t, http.StatusForbidden, reqErr.status)
+	})
+
+	t.Run("should return not found for users outside of the organization", func(t *testing.T) {
+		s, _, _ := setupTestService(t)
+		s.orgService = &orgtest.FakeOrgService{ExpectedSearchOrgUsersResult: &org.SearchOrgUsersQueryResult{}}
+
+		_, err := s.PatchUser(context.Background(), 1, "3", operations(t, `[{"op": "replace", "path": "active", "value": false}]`))
+		var reqErr requestError
+		require.ErrorAs(t, err, &reqErr)
+		assert.Equal(t, http.StatusNotFound, reqErr.status)
+	})
+}
+
+func TestService_DeleteUser(t *testing.T) {
+	t.Run("should delete users of the organization", func(t *testing.T) {
+		s, _, _ := setupTestService(t)
+		s.accesscontrolService = actest.FakeService{}
+		orgService := s.orgService.(*orgtest.FakeOrgService)
+		orgService.ExpectedUserOrgDTO = []*org.UserOrgDTO{{OrgID: 1}}
+		orgService.ExpectedOrgListResponse = orgtest.OrgListResponse{{OrgID: 1}}
+
+		require.NoError(t, s.DeleteUser(context.Background(), 1, "2"))
+		assert.Empty(t, orgService.ExpectedOrgListResponse)
+	})
+
+	t.Run("should refuse to delete server admins", func(t *testing.T) {
+		s, userService, _ := setupTestService(t)
+		userService.ExpectedUser = &user.User{ID: 2, Login: "jdoe", IsAdmin: true}
+		orgService := s.orgService.(*orgtest.FakeOrgService)
+		orgService.ExpectedOrgListResponse = orgtest.OrgListResponse{{OrgID: 1}}
+
+		err := s.DeleteUser(context.Background(), 1, "2")
+		var reqErr requestError
+		require.ErrorAs(t, err, &reqErr)
+		assert.Equal(t, http.StatusForbidden, reqErr.status)
+		assert.Len(t, orgService.ExpectedOrgListResponse, 1)
+	})
+
+	t.Run("should refuse to delete members of other organizations", func(t *testing.T) {
+		s, _, _ := setupTestService(t)
+		orgService := s.orgService.(*orgtest.FakeOrgService)
+		orgService.ExpectedUserOrgDTO = []*org.UserOrgDTO{{OrgID: 1}, {OrgID: 2}}
+		orgService.ExpectedOrgListResponse = orgtest.OrgListResponse{{OrgID: 1}}
+
+		err := s.DeleteUser(context.Background(), 1, "2")
+		var reqErr requestError
+		require.ErrorAs(t, err, &reqErr)
+		assert.Equal(t, http.StatusForbidden, reqErr.status)
+		assert.Len(t, orgService.ExpectedOrgListResponse, 1)
+	})
+}
+
+func TestService_CreateUser(t *testing.T) {
+	t.Run("should return a uniqueness error for existing users", func(t *testing.T) {
+		s, userService, _ := setupTestService(t)
+		userService.CreateFn = func(ctx context.Context, cmd *user.CreateUserCommand) (*user.User, error) {
+			return nil, user.ErrUserAlreadyExists
+		}
+
+		_, err := s.CreateUser(context.Background(), 1, &User{UserName: "jdoe"})
+		var reqErr requestError
+		require.ErrorAs(t, err, &reqErr)
+		assert.Equal(t, http.StatusConflict, reqErr.status)
+		assert.Equal(t, errorTypeUniqueness, reqErr.scimType)
+	})
+
+	t.Run("should require a userName", func(t *testing.T) {
+		s, _, _ := setupTestService(t)
+
+		_, err := s.CreateUser(context.Background(), 1, &User{DisplayName: "John Doe"})
+		var reqErr requestError
+		require.ErrorAs(t, err, &reqErr)
+		assert.Equal(t, errorTypeInvalidValue, reqErr.scimType)
+	})
+}
+
+func TestService_GetUser(t *testing.T) {
+	s, _, _ := setupTestService(t)
+	s.teamService = &teamtest.FakeService{ExpectedTeamsByUser: []*team.TeamDTO{{ID: 4, OrgID: 1, Name: "Developers"}}}
+
+	u, err := s.GetUser(context.Background(), 1, "2")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{SchemaUser}, u.Schemas)
+	assert.Equal(t, "2", u.ID)
+	assert.Equal(t, "jdoe", u.UserName)
+	assert.Equal(t, "John Doe", u.DisplayName)
+	assert.Equal(t, "jdoe@example.org", primaryEmail(u))
+	assert.True(t, *u.Active)
+	assert.Equal(t, []Reference{{Value: "4", Ref: "http://localhost:3000/api/scim/v2/Groups/4", Display: "Developers"}}, u.Groups)
+	assert.Equal(t, "http://localhost:3000/api/scim/v2/Users/2", u.Meta.Location)
+}
+
+func setupTestService(t *testing.T) (*Service, *usertest.FakeUserService, *authtest.FakeUserAuthTokenService) {
+	t.Helper()
+
+	cfg := setting.NewCfg()
+	cfg.AppURL = "http://localhost:3000/"
+	cfg.SCIMDefaultOrgRole = string(org.RoleViewer)
+
+	userService := usertest.NewUserServiceFake()
+	userService.ExpectedUser = &user.User{ID: 2, Login: "jdoe", Email: "jdoe@example.org"}
+	tokenService := authtest.NewFakeUserAuthTokenService()
+
+	s := &Service{
+		cfg:         cfg,
+		log:         log.NewNopLogger(),
+		userService: userService,
+		orgService: &orgtest.FakeOrgService{ExpectedSearchOrgUsersResult: &org.SearchOrgUsersQueryResult{
+			TotalCount: 1,
+			OrgUsers: []*org.OrgUserDTO{{
+				OrgID:  1,
+				UserID: 2,
+				Login:  "jdoe",
+				Email:  "jdoe@example.org",
+				Name:   "John Doe",
+			}},
+		}},
+		teamService:      teamtest.NewFakeService(),
+		authInfoService:  &logintest.AuthInfoServiceFake{ExpectedError: user.ErrUserNotFound},
+		authTokenService: tokenService,
+	}
+	return s, userService, tokenService
+}
@@ -0,0 +1,495 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func (s *Service) GetUser(ctx context.Context, orgID int64, id string) (*User, error) {
+	orgUser, err := s.getOrgUser(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toUser(ctx, orgUser)
+}
+
+func (s *Service) ListUsers(ctx context.Context, query ListQuery) (*ListResponse, error) {
+	if query.Filter != nil {
+		orgUser, err := s.findOrgUser(ctx, query.OrgID, query.Filter)
+		if err != nil {
+			return nil, err
+		}
+		var users []*org.OrgUserDTO
+		if orgUser != nil {
+			users = append(users, orgUser)
+		}
+		return s.userList(ctx, query, int64(len(users)), filterPage(users, query))
+	}
+
+	count := query.Count
+	if count == 0 {
+		count = 1
+	}
+	page, limit, skip := paginate(query.StartIndex, count)
+	result, err := s.orgService.SearchOrgUsers(ctx, &org.SearchOrgUsersQuery{
+		OrgID:                    query.OrgID,
+		Page:                     page,
+		Limit:                    limit,
+		DontEnforceAccessControl: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	users := result.OrgUsers
+	if skip < len(users) {
+		users = users[skip:]
+	} else {
+		users = nil
+	}
+	if query.Count == 0 {
+		users = nil
+	}
+	return s.userList(ctx, query, result.TotalCount, users)
+}
+
+func (s *Service) CreateUser(ctx context.Context, orgID int64, u *User) (*User, error) {
+	if err := validateUser(u); err != nil {
+		return nil, err
+	}
+
+	usr, err := s.userService.Create(ctx, &user.CreateUserCommand{
+		Login:         u.UserName,
+		Email:         primaryEmail(u),
+		Name:          userDisplayName(u),
+		IsDisabled:    u.Active != nil && !*u.Active,
+		EmailVerified: true,
+		SkipOrgSetup:  true,
+	})
+	if err != nil {
+		if errors.Is(err, user.ErrUserAlreadyExists) {
+			return nil, conflict("a user with the userName %q or the same email already exists", u.UserName)
+		}
+		return nil, err
+	}
+
+	if err := s.orgService.AddOrgUser(ctx, &org.AddOrgUserCommand{
+		OrgID:  orgID,
+		UserID: usr.ID,
+		Role:   org.RoleType(s.cfg.SCIMDefaultOrgRole),
+	}); err != nil {
+		if err := s.userService.Delete(ctx, &user.DeleteUserCommand{UserID: usr.ID}); err != nil {
+			s.log.Warn("Failed to delete user not added to the organization", "userID", usr.ID, "error", err)
+		}
+		return nil, err
+	}
+
+	if u.ExternalID != "" {
+		if err := s.authInfoService.SetAuthInfo(ctx, &login.SetAuthInfoCommand{
+			AuthModule: login.SCIMAuthModule,
+			AuthId:     u.ExternalID,
+			UserId:     usr.ID,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	s.log.Info("Provisioned user", "orgID", orgID, "userID", usr.ID, "login", usr.Login)
+	return s.GetUser(ctx, orgID, strconv.FormatInt(usr.ID, 10))
+}
+
+// ReplaceUser updates the user to match the given representation. Group memberships
+// are read-only on users and are ignored.
+func (s *Service) ReplaceUser(ctx context.Context, orgID int64, id string, u *User) (*User, error) {
+	orgUser, err := s.getOrgUser(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.toUser(ctx, orgUser)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.updateUser(ctx, orgID, orgUser.UserID, current, u); err != nil {
+		return nil, err
+	}
+	return s.GetUser(ctx, orgID, id)
+}
+
+func (s *Service) PatchUser(ctx context.Context, orgID int64, id string, operations []PatchOperation) (*User, error) {
+	orgUser, err := s.getOrgUser(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.toUser(ctx, orgUser)
+	if err != nil {
+		return nil, err
+	}
+
+	patched := *current
+	if current.Name != nil {
+		name := *current.Name
+		patched.Name = &name
+	}
+	if err := applyUserPatch(&patched, operations); err != nil {
+		return nil, err
+	}
+
+	if err := s.updateUser(ctx, orgID, orgUser.UserID, current, &patched); err != nil {
+		return nil, err
+	}
+	return s.GetUser(ctx, orgID, id)
+}
+
+// DeleteUser removes the user from the organization. Like removing a user from an
+// organization in Grafana, users that are not member of any other organization are deleted.
+// Server admins and members of other organizations can't be removed through SCIM.
+func (s *Service) DeleteUser(ctx context.Context, orgID int64, id string) error {
+	orgUser, err := s.getOrgUser(ctx, orgID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkManagedByOrg(ctx, orgID, orgUser.UserID); err != nil {
+		return err
+	}
+
+	cmd := &org.RemoveOrgUserCommand{
+		UserID:                   orgUser.UserID,
+		OrgID:                    orgID,
+		ShouldDeleteOrphanedUser: true,
+	}
+	if err := s.orgService.RemoveOrgUser(ctx, cmd); err != nil {
+		if errors.Is(err, org.ErrLastOrgAdmin) {
+			return badRequest(errorTypeMutability, "cannot remove the last organization admin")
+		}
+		return err
+	}
+
+	permissionsOrgID := orgID
+	if cmd.UserWasDeleted {
+		permissionsOrgID = ac.GlobalOrgID
+	}
+	if err := s.accesscontrolService.DeleteUserPermissions(ctx, permissionsOrgID, orgUser.UserID); err != nil {
+		s.log.Warn("Failed to delete permissions for user", "userID", orgUser.UserID, "orgID", permissionsOrgID, "error", err)
+	}
+
+	s.log.Info("Deprovisioned user", "orgID", orgID, "userID", orgUser.UserID, "deleted", cmd.UserWasDeleted)
+	return nil
+}
+
+func (s *Service) updateUser(ctx context.Context, orgID, userID int64, current, u *User) error {
+	if err := validateUser(u); err != nil {
+		return err
+	}
+
+	userLogin, email, name := u.UserName, primaryEmail(u), userDisplayName(u)
+	if email == "" {
+		email = primaryEmail(current)
+	}
+	profileChanged := userLogin != current.UserName || email != primaryEmail(current) || name != userDisplayName(current)
+	activeChanged := u.Active != nil && *u.Active != *current.Active
+	externalIDChanged := u.ExternalID != "" && u.ExternalID != current.ExternalID
+	if profileChanged || activeChanged || externalIDChanged {
+		if err := s.checkManagedByOrg(ctx, orgID, userID); err != nil {
+			return err
+		}
+	}
+
+	if profileChanged {
+		if err := s.checkUniqueness(ctx, userID, userLogin, email); err != nil {
+			return err
+		}
+		if err := s.userService.Update(ctx, &user.UpdateUserCommand{
+			UserID: userID,
+			Login:  userLogin,
+			Email:  email,
+			Name:   name,
+		}); err != nil {
+			if errors.Is(err, user.ErrCaseInsensitive) || errors.Is(err, user.ErrUserAlreadyExists) {
+				return conflict("a user with the userName %q or the email %q already exists", userLogin, email)
+			}
+			return err
+		}
+	}
+
+	if activeChanged {
+		if err := s.userService.Disable(ctx, &user.DisableUserCommand{UserID: userID, IsDisabled: !*u.Active}); err != nil {
+			return err
+		}
+		if !*u.Active {
+			if err := s.authTokenService.RevokeAllUserTokens(ctx, userID); err != nil {
+				return err
+			}
+		}
+		s.log.Info("Updated user status", "userID", userID, "active", *u.Active)
+	}
+
+	if externalIDChanged {
+		if current.ExternalID == "" {
+			return s.authInfoService.SetAuthInfo(ctx, &login.SetAuthInfoCommand{
+				AuthModule: login.SCIMAuthModule,
+				AuthId:     u.ExternalID,
+				UserId:     userID,
+			})
+		}
+		return s.authInfoService.UpdateAuthInfo(ctx, &login.UpdateAuthInfoCommand{
+			AuthModule: login.SCIMAuthModule,
+			AuthId:     u.ExternalID,
+			UserId:     userID,
+		})
+	}
+	return nil
+}
+
+// checkManagedByOrg refuses changes to the attributes shared by all organizations of a user, and its
+// deletion, unless the organization is the only one the user belongs to. Server admins can't be changed through SCIM.
+func (s *Service) checkManagedByOrg(ctx context.Context, orgID, userID int64) error {
+	usr, err := s.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: userID})
+	if err != nil {
+		return err
+	}
+	if usr.IsAdmin {
+		return forbidden("user %d is a server admin and can't be changed through SCIM", userID)
+	}
+
+	orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: userID})
+	if err != nil {
+		return err
+	}
+	for _, o := range orgs {
+		if o.OrgID != orgID {
+			return forbidden("user %d is a member of other organizations and can't be changed through SCIM", userID)
+		}
+	}
+	return nil
+}
+
+// checkUniqueness checks that no other user uses the login or the email
+func (s *Service) checkUniqueness(ctx context.Context, userID int64, userLogin, email string) error {
+	for _, loginOrEmail := range []string{userLogin, email} {
+		usr, err := s.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: loginOrEmail})
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				continue
+			}
+			return err
+		}
+		if usr.ID != userID {
+			return conflict("a user with the userName or email %q already exists", loginOrEmail)
+		}
+	}
+	return nil
+}
+
+// getOrgUser returns the user with the SCIM id if they are member of the organization
+func (s *Service) getOrgUser(ctx context.Context, orgID int64, id string) (*org.OrgUserDTO, error) {
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, notFound("user %s not found", id)
+	}
+
+	result, err := s.orgService.SearchOrgUsers(ctx, &org.SearchOrgUsersQuery{
+		OrgID:                    orgID,
+		UserID:                   userID,
+		Page:                     1,
+		Limit:                    1,
+		DontEnforceAccessControl: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.OrgUsers) == 0 {
+		return nil, notFound("user %s not found", id)
+	}
+	return result.OrgUsers[0], nil
+}
+
+// findOrgUser returns the member of the organization matching the filter, if any
+func (s *Service) findOrgUser(ctx context.Context, orgID int64, filter *Filter) (*org.OrgUserDTO, error) {
+	var userID int64
+	switch filter.Attribute {
+	case attrID:
+		orgUser, err := s.getOrgUser(ctx, orgID, filter.Value)
+		var reqErr requestError
+		if errors.As(err, &reqErr) {
+			return nil, nil
+		}
+		return orgUser, err
+	case attrUserName:
+		usr, err := s.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: filter.Value})
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if !strings.EqualFold(usr.Login, filter.Value) {
+			return nil, nil
+		}
+		userID = usr.ID
+	case attrEmail:
+		usr, err := s.userService.GetByEmail(ctx, &user.GetUserByEmailQuery{Email: filter.Value})
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		userID = usr.ID
+	case attrExternalID:
+		authInfo, err := s.authInfoService.GetAuthInfo(ctx, &login.GetAuthInfoQuery{
+			AuthModule: login.SCIMAuthModule,
+			AuthId:     filter.Value,
+		})
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		userID = authInfo.UserId
+	default:
+		return nil, badRequest(errorTypeInvalidFilter, "filtering users on %q is not supported", filter.Attribute)
+	}
+
+	orgUser, err := s.getOrgUser(ctx, orgID, strconv.FormatInt(userID, 10))
+	var reqErr requestError
+	if errors.As(err, &reqErr) {
+		return nil, nil
+	}
+	return orgUser, err
+}
+
+func (s *Service) toUser(ctx context.Context, orgUser *org.OrgUserDTO) (*User, error) {
+	id := strconv.FormatInt(orgUser.UserID, 10)
+	active := !orgUser.IsDisabled
+	u := &User{
+		Schemas:     []string{SchemaUser},
+		ID:          id,
+		UserName:    orgUser.Login,
+		Name:        &Name{Formatted: orgUser.Name},
+		DisplayName: orgUser.Name,
+		Active:      &active,
+		Meta: &Meta{
+			ResourceType: "User",
+			Created:      &orgUser.Created,
+			LastModified: &orgUser.Updated,
+			Location:     s.location("Users", id),
+		},
+	}
+	if orgUser.Email != "" {
+		u.Emails = []Email{{Value: orgUser.Email, Type: "work", Primary: true}}
+	}
+
+	authInfo, err := s.authInfoService.GetAuthInfo(ctx, &login.GetAuthInfoQuery{
+		UserId:     orgUser.UserID,
+		AuthModule: login.SCIMAuthModule,
+	})
+	if err != nil && !errors.Is(err, user.ErrUserNotFound) {
+		return nil, err
+	}
+	if authInfo != nil {
+		u.ExternalID = authInfo.AuthId
+	}
+
+	teams, err := s.teamService.GetTeamsByUser(ctx, &team.GetTeamsByUserQuery{
+		OrgID:        orgUser.OrgID,
+		UserID:       orgUser.UserID,
+		SignedInUser: queryUser(orgUser.OrgID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range teams {
+		teamID := strconv.FormatInt(t.ID, 10)
+		u.Groups = append(u.Groups, Reference{Value: teamID, Ref: s.location("Groups", teamID), Display: t.Name})
+	}
+
+	return u, nil
+}
+
+func (s *Service) userList(ctx context.Context, query ListQuery, total int64, orgUsers []*org.OrgUserDTO) (*ListResponse, error) {
+	list := newListResponse(query, total)
+	for _, orgUser := range orgUsers {
+		u, err := s.toUser(ctx, orgUser)
+		if err != nil {
+			return nil, err
+		}
+		list.Resources = append(list.Resources, u)
+	}
+	list.ItemsPerPage = len(list.Resources)
+	return list, nil
+}
+
+func (s *Service) location(resource, id string) string {
+	location := strings.TrimSuffix(s.cfg.AppURL, "/") + rootURL + "/" + resource
+	if id != "" {
+		location += "/" + id
+	}
+	return location
+}
+
+func validateUser(u *User) error {
+	if strings.TrimSpace(u.UserName) == "" {
+		return badRequest(errorTypeInvalidValue, "userName is required")
+	}
+	return nil
+}
+
+// primaryEmail returns the primary email of the user, or their first email
+func primaryEmail(u *User) string {
+	for _, email := range u.Emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
+
+// userDisplayName returns the single name Grafana stores for the user
+func userDisplayName(u *User) string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	if u.Name != nil {
+		if u.Name.Formatted != "" {
+			return u.Name.Formatted
+		}
+		if name := strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName); name != "" {
+			return name
+		}
+	}
+	return u.UserName
+}
+
+func newListResponse(query ListQuery, total int64) *ListResponse {
+	return &ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   query.StartIndex,
+		Resources:    []interface{}{},
+	}
+}
+
+// filterPage pages the results of a filtered query, which are already all loaded
+func filterPage[T any](results []T, query ListQuery) []T {
+	start := query.StartIndex - 1
+	if start >= len(results) || query.Count == 0 {
+		return nil
+	}
+	end := start + query.Count
+	if end > len(results) {
+		end = len(results)
+	}
+	return results[start:end]
+}
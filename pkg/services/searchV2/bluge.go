@@ -28,17 +28,21 @@ const (
 	documentFieldName        = "name"
 	documentFieldName_sort   = "name_sort"
 	documentFieldName_ngram  = "name_ngram"
+	documentFieldDescription = "description"
 	documentFieldLocation    = "location" // parent path
+	documentFieldFolder      = "folder"   // folder UID (for dashboards and panels)
 	documentFieldPanelType   = "panel_type"
 	documentFieldTransformer = "transformer"
+	documentFieldQuery       = "query" // panel query text
+	documentFieldLibPanel    = "library_panel"
 	documentFieldDSUID       = "ds_uid"
 	documentFieldDSType      = "ds_type"
 	DocumentFieldCreatedAt   = "created_at"
 	DocumentFieldUpdatedAt   = "updated_at"
 )
 
-func initOrgIndex(dashboards []dashboard, logger log.Logger, extendDoc ExtendDashboardFunc) (*orgIndex, error) {
-	dashboardWriter, err := bluge.OpenWriter(bluge.InMemoryOnlyConfig())
+func initOrgIndex(dashboards []dashboard, logger log.Logger, extendDoc ExtendDashboardFunc, config bluge.Config) (*orgIndex, error) {
+	dashboardWriter, err := bluge.OpenWriter(config)
 	if err != nil {
 		return nil, fmt.Errorf("error opening writer: %v", err)
 	}
@@ -110,7 +114,7 @@ func initOrgIndex(dashboards []dashboard, logger log.Logger, extendDoc ExtendDas
 			location += "/"
 		}
 		location += dash.uid
-		docs := getDashboardPanelDocs(dash, location)
+		docs := getDashboardPanelDocs(dash, folderUID, location)
 
 		for _, panelDoc := range docs {
 			batch.Insert(panelDoc)
@@ -157,6 +161,7 @@ func getNonFolderDashboardDoc(dash dashboard, location string) *bluge.Document {
 	doc := newSearchDocument(dash.uid, dash.summary.Name, dash.summary.Description, url).
 		AddField(bluge.NewKeywordField(documentFieldKind, string(entityKindDashboard)).Aggregatable().StoreValue()).
 		AddField(bluge.NewKeywordField(documentFieldLocation, location).Aggregatable().StoreValue()).
+		AddField(bluge.NewKeywordField(documentFieldFolder, location).Aggregatable()).
 		AddField(bluge.NewDateTimeField(DocumentFieldCreatedAt, dash.created).Sortable().StoreValue()).
 		AddField(bluge.NewDateTimeField(DocumentFieldUpdatedAt, dash.updated).Sortable().StoreValue())
 
@@ -188,7 +193,7 @@ func getNonFolderDashboardDoc(dash dashboard, location string) *bluge.Document {
 	return doc
 }
 
-func getDashboardPanelDocs(dash dashboard, location string) []*bluge.Document {
+func getDashboardPanelDocs(dash dashboard, folderUID string, location string) []*bluge.Document {
 	dashURL := fmt.Sprintf("/d/%s/%s", dash.uid, slugify.Slugify(dash.summary.Name))
 
 	var docs []*bluge.Document
@@ -205,11 +210,16 @@ func getDashboardPanelDocs(dash dashboard, location string) []*bluge.Document {
 		url := fmt.Sprintf("%s?viewPanel=%d", dashURL, panelId)
 		doc := newSearchDocument(panel.UID, panel.Name, panel.Description, url).
 			AddField(bluge.NewKeywordField(documentFieldLocation, location).Aggregatable().StoreValue()).
+			AddField(bluge.NewKeywordField(documentFieldFolder, folderUID).Aggregatable()).
 			AddField(bluge.NewKeywordField(documentFieldKind, string(entityKindPanel)).Aggregatable().StoreValue()) // likely want independent index for this
 
+		for _, query := range getSummaryFieldStrings(panel, "queries") {
+			doc.AddField(bluge.NewTextField(documentFieldQuery, query))
+		}
+
 		for _, ref := range panel.References {
 			switch ref.Family {
-			case entity.StandardKindDashboard:
+			case entity.StandardKindDataSource:
 				if ref.Type != "" {
 					doc.AddField(bluge.NewKeywordField(documentFieldDSType, ref.Type).
 						StoreValue().
@@ -230,6 +240,10 @@ func getDashboardPanelDocs(dash dashboard, location string) []*bluge.Document {
 				if ref.Type == entity.ExternalEntityReferenceRuntime_Transformer && ref.Identifier != "" {
 					doc.AddField(bluge.NewKeywordField(documentFieldTransformer, ref.Identifier).Aggregatable())
 				}
+			case entity.StandardKindLibraryPanel:
+				if ref.Identifier != "" {
+					doc.AddField(bluge.NewKeywordField(documentFieldLibPanel, ref.Identifier).Aggregatable())
+				}
 			}
 		}
 
@@ -252,12 +266,32 @@ func newSearchDocument(uid string, name string, descr string, url string) *bluge
 			doc.AddField(bluge.NewKeywordField(documentFieldName_sort, sortStr).Sortable())
 		}
 	}
+	if descr != "" {
+		doc.AddField(bluge.NewTextField(documentFieldDescription, descr))
+	}
 	if url != "" {
 		doc.AddField(bluge.NewKeywordField(documentFieldURL, url).StoreValue())
 	}
 	return doc
 }
 
+// Summary fields are either set directly or loaded from JSON
+func getSummaryFieldStrings(summary *entity.EntitySummary, key string) []string {
+	switch v := summary.Fields[key].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		res := make([]string, 0, len(v))
+		for _, item := range v {
+			if str, ok := item.(string); ok && str != "" {
+				res = append(res, str)
+			}
+		}
+		return res
+	}
+	return nil
+}
+
 func getDashboardPanelIDs(index *orgIndex, panelLocation string) ([]string, error) {
 	var panelIDs []string
 
@@ -435,12 +469,36 @@ func doSearchQuery(
 		hasConstraints = true
 	}
 
-	// Folder
+	// Datasource type
+	if q.DatasourceType != "" {
+		fullQuery.AddMust(bluge.NewTermQuery(q.DatasourceType).SetField(documentFieldDSType))
+		hasConstraints = true
+	}
+
+	// Transformer
+	if q.Transformer != "" {
+		fullQuery.AddMust(bluge.NewTermQuery(q.Transformer).SetField(documentFieldTransformer))
+		hasConstraints = true
+	}
+
+	// Library panel
+	if q.LibraryPanel != "" {
+		fullQuery.AddMust(bluge.NewTermQuery(q.LibraryPanel).SetField(documentFieldLibPanel))
+		hasConstraints = true
+	}
+
+	// Parent
 	if q.Location != "" {
 		fullQuery.AddMust(bluge.NewTermQuery(q.Location).SetField(documentFieldLocation))
 		hasConstraints = true
 	}
 
+	// Folder (matches both dashboards and their panels)
+	if q.Folder != "" {
+		fullQuery.AddMust(bluge.NewTermQuery(q.Folder).SetField(documentFieldFolder))
+		hasConstraints = true
+	}
+
 	isMatchAllQuery := q.Query == "*" || q.Query == ""
 	if isMatchAllQuery {
 		if !hasConstraints {
@@ -460,6 +518,20 @@ func doSearchQuery(
 				SetAnalyzer(ngramQueryAnalyzer).SetBoost(1))
 		}
 
+		// Also match descriptions, panel queries, transformations and library panel references
+		if q.FullText {
+			bq.AddShould(bluge.NewMatchQuery(q.Query).
+				SetField(documentFieldDescription).
+				SetOperator(bluge.MatchQueryOperatorAnd).
+				SetBoost(2))
+			bq.AddShould(bluge.NewMatchQuery(q.Query).
+				SetField(documentFieldQuery).
+				SetOperator(bluge.MatchQueryOperatorAnd).
+				SetBoost(2))
+			bq.AddShould(bluge.NewTermQuery(q.Query).SetField(documentFieldTransformer))
+			bq.AddShould(bluge.NewTermQuery(q.Query).SetField(documentFieldLibPanel))
+		}
+
 		fullQuery.AddMust(bq)
 	}
 
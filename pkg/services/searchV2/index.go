@@ -67,6 +67,11 @@ type buildSignal struct {
 
 type orgIndex struct {
 	writers map[indexType]*bluge.Writer
+
+	// Only set when the index is persisted to disk
+	path    string
+	eventID int64     // entity events up to this ID are included in the index
+	updated time.Time // when eventID was read from the entity events
 }
 
 type indexType string
@@ -89,6 +94,7 @@ func (i *orgIndex) readerForIndex(idxType indexType) (*bluge.Reader, func(), err
 
 type searchIndex struct {
 	mu                      sync.RWMutex
+	checkpointMu            sync.Mutex // serializes checkpoint file writes, which happen outside mu
 	loader                  dashboardLoader
 	perOrgIndex             map[int64]*orgIndex
 	initializedOrgs         map[int64]bool
@@ -209,6 +215,9 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 		return err
 	}
 
+	// Indexes loaded from disk need the events since they were saved
+	lastEventID = i.getOldestCheckpoint(lastEventID)
+
 	// This semaphore channel allows limiting concurrent async re-indexing routines to 1.
 	asyncReIndexSemaphore := make(chan struct{}, 1)
 
@@ -261,8 +270,16 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 				// We need semaphore here since asynchronous re-indexing may be in progress already.
 				asyncReIndexSemaphore <- struct{}{}
 				defer func() { <-asyncReIndexSemaphore }()
-				_, err = i.buildOrgIndex(buildSignalCtx, signal.orgID)
-				signal.done <- err
+				if index := i.loadOrgIndex(buildSignalCtx, signal.orgID); index != nil {
+					i.setOrgIndex(signal.orgID, index)
+					if index.eventID < lastIndexedEventID {
+						lastIndexedEventID = index.eventID
+					}
+					signal.done <- nil
+				} else {
+					_, err := i.buildOrgIndex(buildSignalCtx, signal.orgID)
+					signal.done <- err
+				}
 				reIndexDoneCh <- lastIndexedEventID
 			}()
 		case <-fullReIndexTimer.C:
@@ -301,6 +318,7 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 			}
 			fullReIndexTimer.Reset(reIndexInterval)
 		case <-ctx.Done():
+			i.closeIndexes()
 			return ctx.Err()
 		}
 	}
@@ -325,6 +343,12 @@ func (i *searchIndex) buildInitialIndex(ctx context.Context, orgID int64) error
 		go i.debugResourceUsage(debugCtx, 200*time.Millisecond)
 	}
 
+	if index := i.loadOrgIndex(ctx, orgID); index != nil {
+		debugCtxCancel()
+		i.setOrgIndex(orgID, index)
+		return nil
+	}
+
 	started := time.Now()
 	numDashboards, err := i.buildOrgIndex(ctx, orgID)
 	if err != nil {
@@ -478,6 +502,17 @@ func (i *searchIndex) buildOrgIndex(ctx context.Context, orgID int64) (int, erro
 		cancel()
 	}()
 
+	// Persisted indexes keep track of the last event included in the loaded dashboards
+	var lastEventID int64
+	var err error
+	lastEventTime := time.Now()
+	if i.settings.IndexPath != "" {
+		lastEventID, err = i.getLastEventID(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("error getting last entity event: %w", err)
+		}
+	}
+
 	i.logger.Info("Start building org index", "orgId", orgID)
 	dashboards, err := i.loader.LoadDashboards(ctx, orgID, "")
 	orgSearchIndexLoadTime := time.Since(started)
@@ -493,13 +528,17 @@ func (i *searchIndex) buildOrgIndex(ctx context.Context, orgID int64) (int, erro
 	initOrgIndexSpan.SetAttributes("org_id", orgID, attribute.Key("org_id").Int64(orgID))
 	initOrgIndexSpan.SetAttributes("dashboardCount", len(dashboards), attribute.Key("dashboardCount").Int(len(dashboards)))
 
-	index, err := initOrgIndex(dashboards, i.logger, dashboardExtender)
+	config, path := i.newIndexConfig(orgID)
+	index, err := initOrgIndex(dashboards, i.logger, dashboardExtender, config)
 
 	initOrgIndexSpan.End()
 
 	if err != nil {
 		return 0, fmt.Errorf("error initializing index: %w", err)
 	}
+	index.path = path
+	index.eventID = lastEventID
+	index.updated = lastEventTime
 	orgSearchIndexTotalTime := time.Since(started)
 	orgSearchIndexBuildTime := orgSearchIndexTotalTime - orgSearchIndexLoadTime
 
@@ -510,6 +549,22 @@ func (i *searchIndex) buildOrgIndex(ctx context.Context, orgID int64) (int, erro
 			"orgSearchIndexTotalTime", orgSearchIndexTotalTime,
 			"orgSearchDashboardCount", len(dashboards))...)
 
+	i.setOrgIndex(orgID, index)
+
+	if orgID == 1 {
+		go func() {
+			if reader, cancel, err := index.readerForIndex(indexTypeDashboard); err == nil {
+				defer cancel()
+				updateUsageStats(context.Background(), reader, i.logger, i.tracer)
+			}
+		}()
+	}
+	return len(dashboards), nil
+}
+
+// setOrgIndex replaces the org index, persisted indexes are saved as the current index for the org
+func (i *searchIndex) setOrgIndex(orgID int64, index *orgIndex) {
+	i.checkpointMu.Lock()
 	i.mu.Lock()
 	if oldIndex, ok := i.perOrgIndex[orgID]; ok {
 		for _, w := range oldIndex.writers {
@@ -517,21 +572,23 @@ func (i *searchIndex) buildOrgIndex(ctx context.Context, orgID int64) (int, erro
 		}
 	}
 	i.perOrgIndex[orgID] = index
+	var checkpoint *indexCheckpoint
+	if index.path != "" {
+		checkpoint = newIndexCheckpoint(index)
+	}
 	i.mu.Unlock()
 
+	if checkpoint != nil {
+		if err := writeIndexCheckpoint(i.getOrgIndexRoot(orgID), checkpoint); err != nil {
+			i.logger.Warn("Error saving search index checkpoint", "orgId", orgID, "error", err)
+		}
+		i.removeStaleIndexes(orgID, index.path)
+	}
+	i.checkpointMu.Unlock()
+
 	i.initializationMutex.Lock()
 	i.initializedOrgs[orgID] = true
 	i.initializationMutex.Unlock()
-
-	if orgID == 1 {
-		go func() {
-			if reader, cancel, err := index.readerForIndex(indexTypeDashboard); err == nil {
-				defer cancel()
-				updateUsageStats(context.Background(), reader, i.logger, i.tracer)
-			}
-		}()
-	}
-	return len(dashboards), nil
 }
 
 func (i *searchIndex) getOrgIndex(orgID int64) (*orgIndex, bool) {
@@ -600,23 +657,28 @@ func (i *searchIndex) withCtxData(ctx context.Context, params ...interface{}) []
 
 func (i *searchIndex) applyIndexUpdates(ctx context.Context, lastEventID int64) int64 {
 	ctx = log.InitCounter(ctx)
+	loaded := time.Now()
 	events, err := i.eventStore.GetAllEventsAfter(ctx, lastEventID)
 	if err != nil {
 		i.logger.Error("can't load events", "error", err)
 		return lastEventID
 	}
 	if len(events) == 0 {
+		i.updateCheckpoints(lastEventID, lastEventID, loaded)
 		return lastEventID
 	}
+	firstEventID := lastEventID
 	started := time.Now()
 	for _, e := range events {
 		err := i.applyEventOnIndex(ctx, e)
 		if err != nil {
 			i.logger.Error("can't apply event", "error", err)
+			i.updateCheckpoints(firstEventID, lastEventID, loaded)
 			return lastEventID
 		}
 		lastEventID = e.Id
 	}
+	i.updateCheckpoints(firstEventID, lastEventID, loaded)
 	i.logger.Info("Index updates applied", i.withCtxData(ctx, "indexEventsAppliedElapsed", time.Since(started), "numEvents", len(events))...)
 	return lastEventID
 }
@@ -781,7 +843,7 @@ func (i *searchIndex) updateDashboard(ctx context.Context, orgID int64, index *o
 		location += "/"
 	}
 	location += dash.uid
-	panelDocs := getDashboardPanelDocs(dash, location)
+	panelDocs := getDashboardPanelDocs(dash, folderUID, location)
 	actualPanelIDs := make([]string, 0, len(panelDocs))
 	for _, panelDoc := range panelDocs {
 		actualPanelIDs = append(actualPanelIDs, string(panelDoc.ID().Term()))
@@ -0,0 +1,228 @@
+package searchV2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/blugelabs/bluge"
+
+	"github.com/grafana/grafana/pkg/services/store"
+)
+
+// Increase when the indexed documents change so that indexes saved by older versions are rebuilt
+const persistedIndexVersion = 1
+
+const checkpointFileName = "checkpoint.json"
+
+// Checkpoints of indexes without new events are refreshed at this interval so they do not
+// fall behind the entity events retention on quiet instances
+const checkpointRefreshInterval = time.Hour
+
+// indexCheckpoint is saved next to the index directories of an org, it points to
+// the current index and the last entity event included in it
+type indexCheckpoint struct {
+	Version int       `json:"version"`
+	Dir     string    `json:"dir"`
+	EventID int64     `json:"eventId"`
+	Updated time.Time `json:"updated"`
+}
+
+func (i *searchIndex) getOrgIndexRoot(orgID int64) string {
+	return filepath.Join(i.settings.IndexPath, strconv.FormatInt(orgID, 10))
+}
+
+// newIndexConfig returns the bluge config for a new org index and the directory it will be saved in (empty when in memory)
+func (i *searchIndex) newIndexConfig(orgID int64) (bluge.Config, string) {
+	if i.settings.IndexPath == "" {
+		return bluge.InMemoryOnlyConfig(), ""
+	}
+	path := filepath.Join(i.getOrgIndexRoot(orgID), strconv.FormatInt(time.Now().UnixNano(), 10))
+	return bluge.DefaultConfig(path), path
+}
+
+func (i *searchIndex) getLastEventID(ctx context.Context) (int64, error) {
+	lastEvent, err := i.eventStore.GetLastEvent(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if lastEvent == nil {
+		return 0, nil
+	}
+	return lastEvent.Id, nil
+}
+
+func readIndexCheckpoint(root string) (*indexCheckpoint, error) {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path is built from configuration
+	body, err := os.ReadFile(filepath.Join(root, checkpointFileName))
+	if err != nil {
+		return nil, err
+	}
+	checkpoint := &indexCheckpoint{}
+	err = json.Unmarshal(body, checkpoint)
+	return checkpoint, err
+}
+
+// The checkpoint is written to a temp file first so a crash never leaves a partial file
+func writeIndexCheckpoint(root string, checkpoint *indexCheckpoint) error {
+	body, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(root, checkpointFileName+".tmp")
+	if err = os.WriteFile(tmp, body, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(root, checkpointFileName))
+}
+
+func newIndexCheckpoint(index *orgIndex) *indexCheckpoint {
+	return &indexCheckpoint{
+		Version: persistedIndexVersion,
+		Dir:     filepath.Base(index.path),
+		EventID: index.eventID,
+		Updated: index.updated,
+	}
+}
+
+// loadOrgIndex opens the index saved by a previous run. It returns nil when there is no
+// saved index, or when the entity events since it was saved are no longer available.
+func (i *searchIndex) loadOrgIndex(ctx context.Context, orgID int64) *orgIndex {
+	if i.settings.IndexPath == "" {
+		return nil
+	}
+
+	root := i.getOrgIndexRoot(orgID)
+	checkpoint, err := readIndexCheckpoint(root)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			i.logger.Warn("Error reading search index checkpoint", "orgId", orgID, "error", err)
+		}
+		return nil
+	}
+
+	if err = i.validateCheckpoint(ctx, checkpoint); err != nil {
+		i.logger.Info("Search index on disk will be rebuilt", "orgId", orgID, "reason", err)
+		return nil
+	}
+
+	path := filepath.Join(root, checkpoint.Dir)
+	writer, err := bluge.OpenWriter(bluge.DefaultConfig(path))
+	if err != nil {
+		i.logger.Warn("Error opening search index", "orgId", orgID, "path", path, "error", err)
+		return nil
+	}
+
+	i.logger.Info("Loaded search index from disk", "orgId", orgID, "eventId", checkpoint.EventID, "updated", checkpoint.Updated)
+	return &orgIndex{
+		writers: map[indexType]*bluge.Writer{
+			indexTypeDashboard: writer,
+		},
+		path:    path,
+		eventID: checkpoint.EventID,
+		updated: checkpoint.Updated,
+	}
+}
+
+func (i *searchIndex) validateCheckpoint(ctx context.Context, checkpoint *indexCheckpoint) error {
+	if checkpoint.Version != persistedIndexVersion {
+		return fmt.Errorf("index version %d does not match %d", checkpoint.Version, persistedIndexVersion)
+	}
+	if checkpoint.Dir == "" {
+		return fmt.Errorf("missing index directory")
+	}
+	// Old events are deleted, so the ones after the checkpoint may be gone
+	if time.Since(checkpoint.Updated) > store.EntityEventsRetention {
+		return fmt.Errorf("checkpoint is older than the entity events retention")
+	}
+	lastEventID, err := i.getLastEventID(ctx)
+	if err != nil {
+		return err
+	}
+	if lastEventID < checkpoint.EventID {
+		return fmt.Errorf("checkpoint event %d is ahead of the last event %d", checkpoint.EventID, lastEventID)
+	}
+	return nil
+}
+
+// updateCheckpoints moves the checkpoints of persisted indexes after events (from, to] have been applied.
+// Indexes that are missing events before `from` (for example a rebuilt index that has not caught up yet) are skipped.
+// When there were no new events the checkpoint time is still refreshed, at most once per checkpointRefreshInterval.
+func (i *searchIndex) updateCheckpoints(from int64, to int64, updated time.Time) {
+	if i.settings.IndexPath == "" || to < from {
+		return
+	}
+
+	i.checkpointMu.Lock()
+	defer i.checkpointMu.Unlock()
+
+	checkpoints := map[int64]*indexCheckpoint{}
+	i.mu.Lock()
+	for orgID, index := range i.perOrgIndex {
+		if index.path == "" || index.eventID < from || index.eventID > to {
+			continue
+		}
+		if index.eventID == to && updated.Sub(index.updated) < checkpointRefreshInterval {
+			continue
+		}
+		index.eventID = to
+		index.updated = updated
+		checkpoints[orgID] = newIndexCheckpoint(index)
+	}
+	i.mu.Unlock()
+
+	for orgID, checkpoint := range checkpoints {
+		if err := writeIndexCheckpoint(i.getOrgIndexRoot(orgID), checkpoint); err != nil {
+			i.logger.Warn("Error saving search index checkpoint", "orgId", orgID, "error", err)
+		}
+	}
+}
+
+// getOldestCheckpoint returns the first event that is not included in all persisted indexes
+func (i *searchIndex) getOldestCheckpoint(eventID int64) int64 {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	for _, index := range i.perOrgIndex {
+		if index.path != "" && index.eventID < eventID {
+			eventID = index.eventID
+		}
+	}
+	return eventID
+}
+
+// removeStaleIndexes deletes the index directories of an org except the current one
+func (i *searchIndex) removeStaleIndexes(orgID int64, current string) {
+	root := i.getOrgIndexRoot(orgID)
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		i.logger.Warn("Error listing search index directories", "orgId", orgID, "error", err)
+		return
+	}
+	for _, entry := range entries {
+		path := filepath.Join(root, entry.Name())
+		if !entry.IsDir() || path == current {
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			i.logger.Warn("Error removing search index", "orgId", orgID, "path", path, "error", err)
+		}
+	}
+}
+
+func (i *searchIndex) closeIndexes() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, index := range i.perOrgIndex {
+		for _, w := range index.writers {
+			_ = w.Close()
+		}
+	}
+}
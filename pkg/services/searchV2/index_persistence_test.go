@@ -0,0 +1,103 @@
+package searchV2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func newPersistedTestIndex(t *testing.T, path string, lastEventID int64) *searchIndex {
+	t.Helper()
+	events := &store.MockEntityEventsService{}
+	events.On("GetLastEvent", mock.Anything).Return(&store.EntityEvent{Id: lastEventID}, nil)
+	loader := &testDashboardLoader{dashboards: dashboardsWithFolders}
+	return newSearchIndex(loader, events, &NoopDocumentExtender{}, func(ctx context.Context, folderId int64) (string, error) { return "x", nil },
+		tracing.InitializeTracerForTest(), featuremgmt.WithFeatures(), setting.SearchSettings{IndexPath: path})
+}
+
+func TestPersistedIndex(t *testing.T) {
+	ctx := context.Background()
+	path := t.TempDir()
+
+	index := newPersistedTestIndex(t, path, 5)
+	_, err := index.buildOrgIndex(ctx, testOrgID)
+	require.NoError(t, err)
+
+	checkpoint, err := readIndexCheckpoint(index.getOrgIndexRoot(testOrgID))
+	require.NoError(t, err)
+	require.Equal(t, int64(5), checkpoint.EventID)
+
+	// Rebuilding replaces the saved index
+	_, err = index.buildOrgIndex(ctx, testOrgID)
+	require.NoError(t, err)
+	rebuilt, err := readIndexCheckpoint(index.getOrgIndexRoot(testOrgID))
+	require.NoError(t, err)
+	require.NotEqual(t, checkpoint.Dir, rebuilt.Dir)
+
+	// Checkpoints only move when the index includes all previous events
+	index.updateCheckpoints(5, 8, time.Now())
+	index.updateCheckpoints(10, 12, time.Now())
+	checkpoint, err = readIndexCheckpoint(index.getOrgIndexRoot(testOrgID))
+	require.NoError(t, err)
+	require.Equal(t, int64(8), checkpoint.EventID)
+	require.Equal(t, int64(3), index.getOldestCheckpoint(3))
+	require.Equal(t, int64(8), index.getOldestCheckpoint(20))
+
+	// Without new events the checkpoint time is refreshed once the refresh interval has passed
+	index.updateCheckpoints(8, 8, checkpoint.Updated.Add(time.Minute))
+	refreshed, err := readIndexCheckpoint(index.getOrgIndexRoot(testOrgID))
+	require.NoError(t, err)
+	require.True(t, refreshed.Updated.Equal(checkpoint.Updated))
+	later := checkpoint.Updated.Add(checkpointRefreshInterval + time.Minute)
+	index.updateCheckpoints(8, 8, later)
+	refreshed, err = readIndexCheckpoint(index.getOrgIndexRoot(testOrgID))
+	require.NoError(t, err)
+	require.Equal(t, int64(8), refreshed.EventID)
+	require.True(t, refreshed.Updated.Equal(later))
+	index.closeIndexes()
+
+	t.Run("load saved index", func(t *testing.T) {
+		restarted := newPersistedTestIndex(t, path, 10)
+		orgIndex := restarted.loadOrgIndex(ctx, testOrgID)
+		require.NotNil(t, orgIndex)
+		require.Equal(t, int64(8), orgIndex.eventID)
+		restarted.setOrgIndex(testOrgID, orgIndex)
+		require.True(t, restarted.initializedOrgs[testOrgID])
+
+		resp := doSearchQuery(ctx, testLogger, orgIndex, testAllowAllFilter,
+			DashboardQuery{Query: "Panel", Kind: []string{string(entityKindPanel)}},
+			&NoopQueryExtender{}, "")
+		require.NoError(t, resp.Error)
+		custom, ok := resp.Frames[0].Meta.Custom.(*customMeta)
+		require.True(t, ok)
+		require.Equal(t, uint64(4), custom.Count)
+		restarted.closeIndexes()
+	})
+
+	t.Run("checkpoint ahead of the events log", func(t *testing.T) {
+		restarted := newPersistedTestIndex(t, path, 2)
+		require.Nil(t, restarted.loadOrgIndex(ctx, testOrgID))
+	})
+
+	t.Run("checkpoint older than events retention", func(t *testing.T) {
+		checkpoint.Updated = time.Now().Add(-store.EntityEventsRetention - time.Minute)
+		err := writeIndexCheckpoint(index.getOrgIndexRoot(testOrgID), checkpoint)
+		require.NoError(t, err)
+
+		restarted := newPersistedTestIndex(t, path, 10)
+		require.Nil(t, restarted.loadOrgIndex(ctx, testOrgID))
+	})
+
+	t.Run("in memory index", func(t *testing.T) {
+		restarted := newPersistedTestIndex(t, "", 10)
+		require.Nil(t, restarted.loadOrgIndex(ctx, testOrgID))
+	})
+}
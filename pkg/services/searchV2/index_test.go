@@ -548,6 +548,150 @@ func TestDashboardIndex_Panels(t *testing.T) {
 	})
 }
 
+var dashboardsWithPanelDetails = []dashboard{
+	{
+		id:       1,
+		uid:      "folder",
+		isFolder: true,
+		summary: &entity.EntitySummary{
+			Name: "Services",
+		},
+	},
+	{
+		id:       2,
+		uid:      "api",
+		folderID: 1,
+		summary: &entity.EntitySummary{
+			Name:   "API",
+			Labels: map[string]string{"backend": ""},
+			Nested: []*entity.EntitySummary{
+				{
+					Kind:        "panel",
+					UID:         "api#1",
+					Name:        "Requests",
+					Description: "Requests served by the load balancer",
+					Fields: map[string]interface{}{
+						"type":    "timeseries",
+						"queries": []interface{}{`sum(rate(http_requests_total{job="api"}[5m]))`},
+					},
+					References: []*entity.EntityExternalReference{
+						{Family: entity.StandardKindDataSource, Type: "prometheus", Identifier: "prom-1"},
+						{Family: entity.ExternalEntityReferencePlugin, Type: entity.StandardKindPanel, Identifier: "timeseries"},
+						{Family: entity.ExternalEntityReferenceRuntime, Type: entity.ExternalEntityReferenceRuntime_Transformer, Identifier: "reduce"},
+					},
+				},
+				{
+					Kind: "panel",
+					UID:  "api#2",
+					Name: "Errors",
+					Fields: map[string]interface{}{
+						"type": "table",
+					},
+					References: []*entity.EntityExternalReference{
+						{Family: entity.StandardKindDataSource, Type: "loki", Identifier: "loki-1"},
+						{Family: entity.ExternalEntityReferencePlugin, Type: entity.StandardKindPanel, Identifier: "table"},
+						{Family: entity.StandardKindLibraryPanel, Identifier: "lib-errors"},
+					},
+				},
+			},
+		},
+	},
+	{
+		id:  3,
+		uid: "3",
+		summary: &entity.EntitySummary{
+			Name: "Other",
+			Nested: []*entity.EntitySummary{
+				newNestedPanel(1, 3, "Requests"),
+			},
+		},
+	},
+}
+
+func getSearchResultUIDs(t *testing.T, resp *backend.DataResponse) []string {
+	t.Helper()
+	require.NoError(t, resp.Error)
+	field, idx := resp.Frames[0].FieldByName("uid")
+	require.NotEqual(t, -1, idx)
+	uids := make([]string, 0, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		uids = append(uids, field.At(i).(string))
+	}
+	return uids
+}
+
+func getFacetCounts(t *testing.T, resp *backend.DataResponse, facet string) map[string]uint64 {
+	t.Helper()
+	counts := make(map[string]uint64)
+	for _, frame := range resp.Frames {
+		if frame.Name != "Facet: "+facet {
+			continue
+		}
+		for i := 0; i < frame.Rows(); i++ {
+			counts[frame.Fields[0].At(i).(string)] = frame.Fields[1].At(i).(uint64)
+		}
+		return counts
+	}
+	t.Fatalf("missing facet: %s", facet)
+	return nil
+}
+
+func TestDashboardIndex_PanelFullText(t *testing.T) {
+	index := initTestOrgIndexFromDashes(t, dashboardsWithPanelDetails)
+	search := func(q DashboardQuery) *backend.DataResponse {
+		return doSearchQuery(context.Background(), testLogger, index, testAllowAllFilter, q, &NoopQueryExtender{}, "")
+	}
+
+	t.Run("query text only matched with full text", func(t *testing.T) {
+		resp := search(DashboardQuery{Query: "http_requests_total", Kind: []string{string(entityKindPanel)}})
+		require.Empty(t, getSearchResultUIDs(t, resp))
+
+		resp = search(DashboardQuery{Query: "http_requests_total", FullText: true, Kind: []string{string(entityKindPanel)}})
+		require.Equal(t, []string{"api#1"}, getSearchResultUIDs(t, resp))
+	})
+
+	t.Run("description", func(t *testing.T) {
+		resp := search(DashboardQuery{Query: "load balancer", FullText: true})
+		require.Equal(t, []string{"api#1"}, getSearchResultUIDs(t, resp))
+	})
+
+	t.Run("transformer and library panel", func(t *testing.T) {
+		resp := search(DashboardQuery{Query: "reduce", FullText: true})
+		require.Equal(t, []string{"api#1"}, getSearchResultUIDs(t, resp))
+
+		resp = search(DashboardQuery{LibraryPanel: "lib-errors"})
+		require.Equal(t, []string{"api#2"}, getSearchResultUIDs(t, resp))
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		resp := search(DashboardQuery{DatasourceType: "loki", Kind: []string{string(entityKindPanel)}})
+		require.Equal(t, []string{"api#2"}, getSearchResultUIDs(t, resp))
+
+		resp = search(DashboardQuery{Query: "Requests", Folder: "folder"})
+		require.Equal(t, []string{"api#1"}, getSearchResultUIDs(t, resp))
+	})
+
+	t.Run("facets", func(t *testing.T) {
+		resp := search(DashboardQuery{
+			Kind: []string{string(entityKindPanel)},
+			Facet: []FacetField{
+				{Field: documentFieldDSType},
+				{Field: documentFieldPanelType},
+				{Field: documentFieldFolder},
+			},
+		})
+		require.Equal(t, map[string]uint64{"prometheus": 1, "loki": 1}, getFacetCounts(t, resp, documentFieldDSType))
+		require.Equal(t, map[string]uint64{"timeseries": 1, "table": 1}, getFacetCounts(t, resp, documentFieldPanelType))
+		require.Equal(t, map[string]uint64{"folder": 2, "general": 1}, getFacetCounts(t, resp, documentFieldFolder))
+
+		resp = search(DashboardQuery{
+			Kind:  []string{string(entityKindDashboard)},
+			Facet: []FacetField{{Field: documentFieldTag}},
+		})
+		require.Equal(t, map[string]uint64{"backend": 1}, getFacetCounts(t, resp, documentFieldTag))
+	})
+}
+
 var punctuationSplitNgramDashboards = []dashboard{
 	{
 		id:  1,
@@ -17,13 +17,17 @@ type FacetField struct {
 type DashboardQuery struct {
 	Query              string       `json:"query"`
 	Location           string       `json:"location,omitempty"` // parent folder ID
+	Folder             string       `json:"folder,omitempty"`   // folder UID, matches dashboards and their panels
 	Sort               string       `json:"sort,omitempty"`     // field ASC/DESC
 	Datasource         string       `json:"ds_uid,omitempty"`   // "datasource" collides with the JSON value at the same leel :()
 	DatasourceType     string       `json:"ds_type,omitempty"`
 	Tags               []string     `json:"tags,omitempty"`
 	Kind               []string     `json:"kind,omitempty"`
 	PanelType          string       `json:"panel_type,omitempty"`
+	Transformer        string       `json:"transformer,omitempty"`
+	LibraryPanel       string       `json:"library_panel,omitempty"`
 	UIDs               []string     `json:"uid,omitempty"`
+	FullText           bool         `json:"fullText,omitempty"`           // also match descriptions, panel queries, transformations and library panels
 	Explain            bool         `json:"explain,omitempty"`            // adds details on why document matched
 	WithAllowedActions bool         `json:"withAllowedActions,omitempty"` // adds allowed actions per entity
 	Facet              []FacetField `json:"facet,omitempty"`
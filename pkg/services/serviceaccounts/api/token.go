@@ -8,6 +8,8 @@ import (
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/components/satokengen"
+	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/audit"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/web"
@@ -28,6 +30,8 @@ type TokenDTO struct {
 	Created *time.Time `json:"created"`
 	// example: 2022-03-23T10:31:02Z
 	LastUsedAt *time.Time `json:"lastUsedAt"`
+	// example: 10.0.0.1
+	LastUsedIP *string `json:"lastUsedIp"`
 	// example: 2022-03-23T10:31:02Z
 	Expiration *time.Time `json:"expiration"`
 	// example: 0
@@ -36,6 +40,10 @@ type TokenDTO struct {
 	HasExpired bool `json:"hasExpired"`
 	// example: false
 	IsRevoked *bool `json:"isRevoked"`
+	// Permissions the token is restricted to, empty when it has all the service account permissions
+	Permissions apikey.Permissions `json:"permissions"`
+	// example: ["10.0.0.0/8"]
+	AllowedIPs apikey.AllowedIPs `json:"allowedIps"`
 }
 
 func hasExpired(expiration *int64) bool {
@@ -102,7 +110,10 @@ func (api *ServiceAccountsAPI) ListTokens(ctx *contextmodel.ReqContext) response
 			SecondsUntilExpiration: &secondsUntilExpiration,
 			HasExpired:             isExpired,
 			LastUsedAt:             token.LastUsedAt,
+			LastUsedIP:             token.LastUsedIP,
 			IsRevoked:              token.IsRevoked,
+			Permissions:            token.Permissions,
+			AllowedIPs:             token.AllowedIPs,
 		}
 	}
 
@@ -172,6 +183,15 @@ func (api *ServiceAccountsAPI) CreateToken(c *contextmodel.ReqContext) response.
 		return response.ErrOrFallback(http.StatusInternalServerError, "failed to add service account token", err)
 	}
 
+	api.auditService.Log(c.Req.Context(), audit.Event{
+		Action:   audit.ActionServiceAccountTokenCreate,
+		Resource: audit.Resource{Kind: audit.ResourceServiceAccountToken, ID: strconv.FormatInt(apiKey.ID, 10), Name: apiKey.Name},
+		Details: map[string]any{
+			"serviceAccountId": saID, "secondsToLive": cmd.SecondsToLive,
+			"permissions": cmd.Permissions, "allowedIps": cmd.AllowedIPs,
+		},
+	})
+
 	result := &dtos.NewApiKeyResult{
 		ID:   apiKey.ID,
 		Name: apiKey.Name,
@@ -217,6 +237,12 @@ func (api *ServiceAccountsAPI) DeleteToken(c *contextmodel.ReqContext) response.
 		return response.ErrOrFallback(http.StatusInternalServerError, failedToDeleteMsg, err)
 	}
 
+	api.auditService.Log(c.Req.Context(), audit.Event{
+		Action:   audit.ActionServiceAccountTokenDelete,
+		Resource: audit.Resource{Kind: audit.ResourceServiceAccountToken, ID: strconv.FormatInt(tokenID, 10)},
+		Details:  map[string]any{"serviceAccountId": saID},
+	})
+
 	return response.Success("Service account token deleted")
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/apikey"
@@ -56,6 +57,8 @@ func (s *ServiceAccountsStoreImpl) AddServiceAccountToken(ctx context.Context, s
 			Key:              cmd.Key,
 			SecondsToLive:    cmd.SecondsToLive,
 			ServiceAccountID: &serviceAccountId,
+			Permissions:      cmd.Permissions,
+			AllowedIPs:       cmd.AllowedIPs,
 		}
 
 		key, err := s.apiKeyService.AddAPIKey(ctx, addKeyCmd)
@@ -65,6 +68,10 @@ func (s *ServiceAccountsStoreImpl) AddServiceAccountToken(ctx context.Context, s
 				return serviceaccounts.ErrDuplicateToken.Errorf("service account token with name %s already exists in the organization", cmd.Name)
 			case errors.Is(err, apikey.ErrInvalidExpiration):
 				return serviceaccounts.ErrInvalidTokenExpiration.Errorf("invalid service account token expiration value %d", cmd.SecondsToLive)
+			case errors.Is(err, apikey.ErrInvalidPermissions):
+				return serviceaccounts.ErrInvalidTokenPermissions.Errorf("%w", err)
+			case errors.Is(err, apikey.ErrInvalidAllowedIPs):
+				return serviceaccounts.ErrInvalidTokenAllowedIPs.Errorf("%w", err)
 			}
 
 			return err
@@ -109,6 +116,25 @@ func (s *ServiceAccountsStoreImpl) RevokeServiceAccountToken(ctx context.Context
 	})
 }
 
+// RevokeUnusedServiceAccountTokens revokes the tokens not used since the given time,
+// the tokens that were never used are revoked when they were created before it
+func (s *ServiceAccountsStoreImpl) RevokeUnusedServiceAccountTokens(ctx context.Context, unusedSince time.Time) (int64, error) {
+	rawSQL := "UPDATE api_key SET is_revoked = ? WHERE service_account_id IS NOT NULL AND (is_revoked IS NULL OR is_revoked = ?) " +
+		"AND (last_used_at < ? OR (last_used_at IS NULL AND created < ?))"
+
+	var affected int64
+	err := s.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		dialect := s.sqlStore.GetDialect()
+		result, err := sess.Exec(rawSQL, dialect.BooleanStr(true), dialect.BooleanStr(false), unusedSince, unusedSince)
+		if err != nil {
+			return err
+		}
+		affected, err = result.RowsAffected()
+		return err
+	})
+	return affected, err
+}
+
 // assignApiKeyToServiceAccount sets the API key service account ID
 func (s *ServiceAccountsStoreImpl) assignApiKeyToServiceAccount(sess *db.Session, apiKeyId int64, serviceAccountId int64) error {
 	key := apikey.APIKey{ID: apiKeyId}
@@ -3,12 +3,15 @@ package database
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/components/apikeygen"
+	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts/tests"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
 )
 
 func TestStore_AddServiceAccountToken(t *testing.T) {
@@ -93,6 +96,90 @@ func TestStore_AddServiceAccountToken_WrongServiceAccount(t *testing.T) {
 	require.Error(t, err, "It should not be possible to add token to non-existing service account")
 }
 
+func TestStore_AddServiceAccountToken_Restricted(t *testing.T) {
+	saToCreate := tests.TestUser{Login: "servicetestwithTeam@admin", IsServiceAccount: true}
+	db, store := setupTestDatabase(t)
+	sa := tests.SetupUserServiceAccount(t, db, saToCreate)
+
+	addToken := func(name string, permissions apikey.Permissions, allowedIPs apikey.AllowedIPs) error {
+		key, err := apikeygen.New(sa.OrgID, name)
+		require.NoError(t, err)
+		_, err = store.AddServiceAccountToken(context.Background(), sa.ID, &serviceaccounts.AddServiceAccountTokenCommand{
+			Name:        name,
+			OrgId:       sa.OrgID,
+			Key:         key.HashedKey,
+			Permissions: permissions,
+			AllowedIPs:  allowedIPs,
+		})
+		return err
+	}
+
+	permissions := apikey.Permissions{{Action: "dashboards:read", Scope: "folders:uid:ops"}}
+	allowedIPs := apikey.AllowedIPs{"10.0.0.0/8", "192.168.1.10"}
+	require.NoError(t, addToken("restricted", permissions, allowedIPs))
+
+	err := addToken("invalid-permissions", apikey.Permissions{{Scope: "folders:uid:ops"}}, nil)
+	require.ErrorIs(t, err, serviceaccounts.ErrInvalidTokenPermissions)
+
+	err = addToken("invalid-ips", nil, apikey.AllowedIPs{"10.0.0.0/33"})
+	require.ErrorIs(t, err, serviceaccounts.ErrInvalidTokenAllowedIPs)
+
+	keys, err := store.ListTokens(context.Background(), &serviceaccounts.GetSATokensQuery{
+		OrgID:            &sa.OrgID,
+		ServiceAccountID: &sa.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, permissions, keys[0].Permissions)
+	require.Equal(t, allowedIPs, keys[0].AllowedIPs)
+}
+
+func TestStore_RevokeUnusedServiceAccountTokens(t *testing.T) {
+	saToCreate := tests.TestUser{Login: "servicetestwithTeam@admin", IsServiceAccount: true}
+	db, store := setupTestDatabase(t)
+	sa := tests.SetupUserServiceAccount(t, db, saToCreate)
+
+	addToken := func(name string) *apikey.APIKey {
+		key, err := apikeygen.New(sa.OrgID, name)
+		require.NoError(t, err)
+		token, err := store.AddServiceAccountToken(context.Background(), sa.ID, &serviceaccounts.AddServiceAccountTokenCommand{
+			Name:  name,
+			OrgId: sa.OrgID,
+			Key:   key.HashedKey,
+		})
+		require.NoError(t, err)
+		return token
+	}
+
+	addToken("never-used")
+	used := addToken("used")
+
+	now := time.Now()
+	err := db.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+		_, err := sess.Exec("UPDATE api_key SET last_used_at = ? WHERE id = ?", now.Add(2*time.Hour), used.ID)
+		return err
+	})
+	require.NoError(t, err)
+
+	revoked, err := store.RevokeUnusedServiceAccountTokens(context.Background(), now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, revoked)
+
+	revoked, err = store.RevokeUnusedServiceAccountTokens(context.Background(), now.Add(time.Hour))
+	require.NoError(t, err)
+	require.EqualValues(t, 1, revoked)
+
+	keys, err := store.ListTokens(context.Background(), &serviceaccounts.GetSATokensQuery{
+		OrgID:            &sa.OrgID,
+		ServiceAccountID: &sa.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	for _, k := range keys {
+		require.Equal(t, k.Name == "never-used", *k.IsRevoked, k.Name)
+	}
+}
+
 func TestStore_RevokeServiceAccountToken(t *testing.T) {
 	userToCreate := tests.TestUser{Login: "servicetestwithTeam@admin", IsServiceAccount: true}
 	db, store := setupTestDatabase(t)
@@ -9,9 +9,11 @@ import (
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/infra/usagestats"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/audit"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts/api"
@@ -25,6 +27,7 @@ import (
 const (
 	metricsCollectionInterval = time.Minute * 30
 	defaultSecretScanInterval = time.Minute * 5
+	unusedTokensCheckInterval = time.Hour
 )
 
 type ServiceAccountsService struct {
@@ -35,6 +38,9 @@ type ServiceAccountsService struct {
 
 	secretScanEnabled  bool
 	secretScanInterval time.Duration
+	// unusedTokenDayLimit is the number of days after which unused tokens are revoked
+	unusedTokenDayLimit int
+	serverLock          *serverlock.ServerLockService
 }
 
 func ProvideServiceAccountsService(
@@ -49,6 +55,8 @@ func ProvideServiceAccountsService(
 	orgService org.Service,
 	permissionService accesscontrol.ServiceAccountPermissionsService,
 	accesscontrolService accesscontrol.Service,
+	auditService audit.Service,
+	serverLock *serverlock.ServerLockService,
 ) (*ServiceAccountsService, error) {
 	serviceAccountsStore := database.ProvideServiceAccountsStore(
 		cfg,
@@ -60,9 +68,11 @@ func ProvideServiceAccountsService(
 	)
 	log := log.New("serviceaccounts")
 	s := &ServiceAccountsService{
-		store:         serviceAccountsStore,
-		log:           log,
-		backgroundLog: log.New("serviceaccounts.background"),
+		store:               serviceAccountsStore,
+		log:                 log,
+		backgroundLog:       log.New("serviceaccounts.background"),
+		unusedTokenDayLimit: cfg.SATokenUnusedDayLimit,
+		serverLock:          serverLock,
 	}
 
 	if err := RegisterRoles(accesscontrolService); err != nil {
@@ -71,7 +81,7 @@ func ProvideServiceAccountsService(
 
 	usageStats.RegisterMetricsFunc(s.getUsageMetrics)
 
-	serviceaccountsAPI := api.NewServiceAccountsAPI(cfg, s, ac, accesscontrolService, routeRegister, permissionService)
+	serviceaccountsAPI := api.NewServiceAccountsAPI(cfg, s, ac, accesscontrolService, routeRegister, permissionService, auditService)
 	serviceaccountsAPI.RegisterAPIEndpoints()
 
 	s.secretScanEnabled = cfg.SectionWithEnvOverrides("secretscan").Key("enabled").MustBool(false)
@@ -121,6 +131,14 @@ func (sa *ServiceAccountsService) Run(ctx context.Context) error {
 		defer tokenCheckTicker.Stop()
 	}
 
+	unusedTokensTicker := time.NewTicker(unusedTokensCheckInterval)
+	if sa.unusedTokenDayLimit <= 0 {
+		unusedTokensTicker.Stop()
+	} else {
+		sa.revokeUnusedTokens(ctx)
+		defer unusedTokensTicker.Stop()
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -143,7 +161,30 @@ func (sa *ServiceAccountsService) Run(ctx context.Context) error {
 			if err := sa.secretScanService.CheckTokens(ctx); err != nil {
 				sa.backgroundLog.Warn("Failed to check for leaked tokens", "error", err.Error())
 			}
+		case <-unusedTokensTicker.C:
+			sa.revokeUnusedTokens(ctx)
+		}
+	}
+}
+
+// revokeUnusedTokens revokes the tokens that have not been used for more than the configured number of days.
+// The server lock makes sure only one instance runs it per interval.
+func (sa *ServiceAccountsService) revokeUnusedTokens(ctx context.Context) {
+	err := sa.serverLock.LockAndExecute(ctx, "revoke unused service account tokens", unusedTokensCheckInterval, func(ctx context.Context) {
+		sa.backgroundLog.Debug("revoking unused tokens")
+
+		unusedSince := time.Now().AddDate(0, 0, -sa.unusedTokenDayLimit)
+		revoked, err := sa.store.RevokeUnusedServiceAccountTokens(ctx, unusedSince)
+		if err != nil {
+			sa.backgroundLog.Warn("Failed to revoke unused tokens", "error", err.Error())
+			return
+		}
+		if revoked > 0 {
+			sa.backgroundLog.Info("Revoked unused tokens", "count", revoked, "unusedSince", unusedSince)
 		}
+	})
+	if err != nil {
+		sa.backgroundLog.Warn("Failed to lock the unused token revocation", "error", err.Error())
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util/errutil"
@@ -35,6 +36,8 @@ var (
 	ErrServiceAccountTokenNotFound       = errutil.NewBase(errutil.StatusNotFound, "serviceaccounts.ErrTokenNotFound", errutil.WithPublicMessage("service account token not found"))
 	ErrInvalidTokenExpiration            = errutil.NewBase(errutil.StatusValidationFailed, "serviceaccounts.ErrInvalidInput", errutil.WithPublicMessage("invalid SecondsToLive value"))
 	ErrDuplicateToken                    = errutil.NewBase(errutil.StatusBadRequest, "serviceaccounts.ErrTokenAlreadyExists", errutil.WithPublicMessage("service account token with given name already exists in the organization"))
+	ErrInvalidTokenPermissions           = errutil.NewBase(errutil.StatusValidationFailed, "serviceaccounts.ErrInvalidTokenPermissions", errutil.WithPublicMessage("invalid service account token permissions"))
+	ErrInvalidTokenAllowedIPs            = errutil.NewBase(errutil.StatusValidationFailed, "serviceaccounts.ErrInvalidTokenAllowedIPs", errutil.WithPublicMessage("invalid service account token allowed IPs"))
 )
 
 type ServiceAccount struct {
@@ -90,6 +93,11 @@ type AddServiceAccountTokenCommand struct {
 	OrgId         int64  `json:"-"`
 	Key           string `json:"-"`
 	SecondsToLive int64  `json:"secondsToLive"`
+	// Permissions restricts the token to a subset of the service account permissions
+	Permissions apikey.Permissions `json:"permissions"`
+	// AllowedIPs restricts the IP addresses and CIDR ranges the token can be used from
+	// example: ["10.0.0.0/8"]
+	AllowedIPs apikey.AllowedIPs `json:"allowedIps"`
 }
 
 type SearchOrgServiceAccountsQuery struct {
@@ -100,4 +100,17 @@ func addApiKeyMigrations(mg *Migrator) {
 	mg.AddMigration("Add is_revoked column to api_key table", NewAddColumnMigration(apiKeyV2, &Column{
 		Name: "is_revoked", Type: DB_Bool, Nullable: true, Default: "0",
 	}))
+
+	// permissions and allowed_ips restrict service account tokens, they are stored as JSON lists
+	mg.AddMigration("Add permissions column to api_key table", NewAddColumnMigration(apiKeyV2, &Column{
+		Name: "permissions", Type: DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("Add allowed_ips column to api_key table", NewAddColumnMigration(apiKeyV2, &Column{
+		Name: "allowed_ips", Type: DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("Add last_used_ip column to api_key table", NewAddColumnMigration(apiKeyV2, &Column{
+		Name: "last_used_ip", Type: DB_NVarchar, Length: 64, Nullable: true,
+	}))
 }
@@ -0,0 +1,34 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addAuditLogMigrations(mg *Migrator) {
+	auditLogV1 := Table{
+		Name: "audit_log",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "actor_kind", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "actor_id", Type: DB_BigInt, Nullable: false},
+			{Name: "actor_login", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "ip_address", Type: DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "action", Type: DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "resource_kind", Type: DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "resource_id", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "resource_name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "result", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "details", Type: DB_Text, Nullable: true},
+		},
+		Indices: []*Index{
+			{Cols: []string{"created"}},
+			{Cols: []string{"org_id", "created"}},
+			{Cols: []string{"actor_id"}},
+		},
+	}
+
+	mg.AddMigration("create audit_log table", NewAddTableMigration(auditLogV1))
+	mg.AddMigration("add index audit_log.created", NewAddIndexMigration(auditLogV1, auditLogV1.Indices[0]))
+	mg.AddMigration("add index audit_log.org_id_created", NewAddIndexMigration(auditLogV1, auditLogV1.Indices[1]))
+	mg.AddMigration("add index audit_log.actor_id", NewAddIndexMigration(auditLogV1, auditLogV1.Indices[2]))
+}
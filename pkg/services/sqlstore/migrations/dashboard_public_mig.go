@@ -96,4 +96,64 @@ func addPublicDashboardMigration(mg *Migrator) {
 	mg.AddMigration("backfill empty share column fields with default of public", NewRawSQLMigration(
 		"UPDATE dashboard_public SET share='public' WHERE share=''",
 	))
+
+	mg.AddMigration("add max_time_range column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "max_time_range",
+		Type:     DB_NVarchar,
+		Length:   64,
+		Nullable: false,
+		Default:  "''",
+	}))
+
+	mg.AddMigration("add variable_settings column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "variable_settings",
+		Type:     DB_Text,
+		Nullable: true,
+	}))
+
+	var dashboardPublicTokenV1 = Table{
+		Name: "dashboard_public_token",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "public_dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "access_token", Type: DB_NVarchar, Length: 32, Nullable: false},
+			{Name: "expires_at", Type: DB_DateTime, Nullable: true},
+			{Name: "revoked", Type: DB_Bool, Nullable: false, Default: "0"},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created_at", Type: DB_DateTime, Nullable: false},
+			{Name: "updated_at", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"uid"}, Type: UniqueIndex},
+			{Cols: []string{"access_token"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "public_dashboard_uid"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard public token table v1", NewAddTableMigration(dashboardPublicTokenV1))
+	addTableIndicesMigrations(mg, "v1", dashboardPublicTokenV1)
+
+	var dashboardPublicAccessLogV1 = Table{
+		Name: "dashboard_public_access_log",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "public_dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "token_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "action", Type: DB_NVarchar, Length: 32, Nullable: false},
+			{Name: "panel_id", Type: DB_BigInt, Nullable: false},
+			{Name: "ip", Type: DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "public_dashboard_uid", "created"}},
+			{Cols: []string{"created"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard public access log table v1", NewAddTableMigration(dashboardPublicAccessLogV1))
+	addTableIndicesMigrations(mg, "v1", dashboardPublicAccessLogV1)
 }
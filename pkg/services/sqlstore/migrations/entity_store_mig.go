@@ -182,6 +182,36 @@ func addEntityStoreMigrations(mg *migrator.Migrator) {
 		},
 	})
 
+	// Ordered change log used to serve the watch stream.  The auto-increment id is the
+	// position a watcher can resume from
+	tables = append(tables, migrator.Table{
+		Name: "entity_change",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "grn", Type: migrator.DB_NVarchar, Length: grnLength, Nullable: false},
+
+			// The entity identifier
+			{Name: "tenant_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "kind", Type: migrator.DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "folder", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "version", Type: migrator.DB_NVarchar, Length: 128, Nullable: false},
+
+			// What happened (see EntityWatchResponse_Action)
+			{Name: "action", Type: migrator.DB_Int, Nullable: false},
+			{Name: "labels", Type: migrator.DB_Text, Nullable: true}, // JSON object (at the time of the change)
+
+			// Who changed what when
+			{Name: "updated_at", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "updated_by", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"tenant_id", "id"}},
+			{Cols: []string{"grn"}},
+			{Cols: []string{"updated_at"}}, // used to delete the changes older than the retention
+		},
+	})
+
 	// !!! This should not run in production!
 	// The object store SQL schema is still in active development and this
 	// will only be called when the feature toggle is enabled
@@ -193,7 +223,7 @@ func addEntityStoreMigrations(mg *migrator.Migrator) {
 	// Migration cleanups: given that this is a complex setup
 	// that requires a lot of testing before we are ready to push out of dev
 	// this script lets us easy wipe previous changes and initialize clean tables
-	suffix := " (v010)" // change this when we want to wipe and reset the object tables
+	suffix := " (v012)" // change this when we want to wipe and reset the object tables
 	mg.AddMigration("EntityStore init: cleanup"+suffix, migrator.NewRawSQLMigration(strings.TrimSpace(`
 		DELETE FROM migration_log WHERE migration_id LIKE 'EntityStore init%';
 	`)))
@@ -40,3 +40,20 @@ func addLoginAttemptMigrations(mg *Migrator) {
 		"ip_address": "ip_address",
 	})
 }
+
+func addLoginLockoutMigrations(mg *Migrator) {
+	loginLockoutV1 := Table{
+		Name: "login_lockout",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "username", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "locked_at", Type: DB_Int, Default: "0", Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"username"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create login_lockout table", NewAddTableMigration(loginLockoutV1))
+	mg.AddMigration("add unique index login_lockout.username", NewAddIndexMigration(loginLockoutV1, loginLockoutV1.Indices[0]))
+}
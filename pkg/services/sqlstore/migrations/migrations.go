@@ -100,6 +100,13 @@ func (*OSSMigrations) AddMigration(mg *Migrator) {
 	AddExternalAlertmanagerToDatasourceMigration(mg)
 
 	addFolderMigrations(mg)
+
+	addTwoFactorMigrations(mg)
+
+	addAuditLogMigrations(mg)
+
+	addPasswordPolicyMigrations(mg)
+	addLoginLockoutMigrations(mg)
 }
 
 func addMigrationLogMigrations(mg *Migrator) {
@@ -0,0 +1,21 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addPasswordPolicyMigrations(mg *Migrator) {
+	passwordHistoryV1 := Table{
+		Name: "user_password_history",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "password", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"user_id"}},
+		},
+	}
+
+	mg.AddMigration("create user_password_history table", NewAddTableMigration(passwordHistoryV1))
+	mg.AddMigration("add index user_password_history.user_id", NewAddIndexMigration(passwordHistoryV1, passwordHistoryV1.Indices[0]))
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addTwoFactorMigrations(mg *Migrator) {
+	userTwoFactorV1 := Table{
+		Name: "user_two_factor",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "secret", Type: DB_Text, Nullable: false},
+			{Name: "enabled", Type: DB_Bool, Nullable: false},
+			{Name: "last_used_step", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"user_id"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create user_two_factor table", NewAddTableMigration(userTwoFactorV1))
+	mg.AddMigration("add unique index user_two_factor.user_id", NewAddIndexMigration(userTwoFactorV1, userTwoFactorV1.Indices[0]))
+
+	recoveryCodeV1 := Table{
+		Name: "user_two_factor_recovery_code",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "code_hash", Type: DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"user_id", "code_hash"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create user_two_factor_recovery_code table", NewAddTableMigration(recoveryCodeV1))
+	mg.AddMigration("add unique index user_two_factor_recovery_code.user_id_code_hash", NewAddIndexMigration(recoveryCodeV1, recoveryCodeV1.Indices[0]))
+}
@@ -79,11 +79,12 @@ type RootStorageConfig struct {
 	Disabled         bool   `json:"disabled,omitempty"`
 
 	// Depending on type, these will be configured
-	Disk *StorageLocalDiskConfig `json:"disk,omitempty"`
-	Git  *StorageGitConfig       `json:"git,omitempty"`
-	SQL  *StorageSQLConfig       `json:"sql,omitempty"`
-	S3   *StorageS3Config        `json:"s3,omitempty"`
-	GCS  *StorageGCSConfig       `json:"gcs,omitempty"`
+	Disk   *StorageLocalDiskConfig `json:"disk,omitempty"`
+	Git    *StorageGitConfig       `json:"git,omitempty"`
+	SQL    *StorageSQLConfig       `json:"sql,omitempty"`
+	S3     *StorageS3Config        `json:"s3,omitempty"`
+	GCS    *StorageGCSConfig       `json:"gcs,omitempty"`
+	Object *StorageObjectConfig    `json:"object,omitempty"`
 }
 
 type StorageLocalDiskConfig struct {
@@ -126,12 +127,22 @@ type StorageGCSConfig struct {
 	CredentialsFile string `json:"credentialsFile"`
 }
 
+type StorageObjectConfig struct {
+	// Go CDK bucket URL, for example s3://bucket?region=us-east-1, gs://bucket or azblob://container
+	BucketURL string `json:"bucketURL"`
+	Folder    string `json:"folder,omitempty"` // subfolder within the bucket
+
+	// Credentials are read from the environment by the Go CDK drivers
+}
+
 func newStorage(cfg RootStorageConfig, localWorkCache string) (storageRuntime, error) {
 	switch cfg.Type {
 	case rootStorageTypeDisk:
 		return newDiskStorage(RootStorageMeta{}, cfg), nil
 	case rootStorageTypeGit:
 		return newGitStorage(RootStorageMeta{}, cfg, localWorkCache), nil
+	case rootStorageTypeObject:
+		return newObjectStorage(RootStorageMeta{}, cfg), nil
 	}
 
 	return nil, fmt.Errorf("unsupported store: " + cfg.Type)
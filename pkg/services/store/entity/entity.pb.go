@@ -1648,7 +1648,7 @@ type EntityWatchRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Timestamp of last changes. Empty will default to
+	// Change log position (timestamp) of the last event seen.  Empty will start with new changes
 	Since int64 `protobuf:"varint,1,opt,name=since,proto3" json:"since,omitempty"`
 	// Watch sppecific entities
 	GRN []*GRN `protobuf:"bytes,2,rep,name=GRN,proto3" json:"GRN,omitempty"`
@@ -1759,7 +1759,7 @@ type EntityWatchResponse struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Timestamp the event was sent
+	// Change log position of this event, pass it as `since` to resume watching
 	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 	// List of entities with the same action
 	Entity []*Entity `protobuf:"bytes,2,rep,name=entity,proto3" json:"entity,omitempty"`
@@ -23,6 +23,9 @@ const (
 	// Standalone panel is not an object kind yet -- library panel, or nested in dashboard
 	StandardKindPanel = "panel"
 
+	// StandardKindLibraryPanel: not a real kind yet, but used to define references from dashboard panels
+	StandardKindLibraryPanel = "librarypanel"
+
 	// entity.StandardKindSVG SVG file support
 	StandardKindSVG = "svg"
 
@@ -0,0 +1,271 @@
+package sqlstash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/services/sqlstore/session"
+	"github.com/grafana/grafana/pkg/services/store/entity"
+)
+
+const (
+	// how often a watcher polls the change log for new events
+	defaultWatchInterval = time.Second
+
+	// max number of changes read from the log in one query
+	watchBatchSize = 100
+
+	// how long changes are kept in the log; watchers can not resume from older positions
+	changeLogRetention = 7 * 24 * time.Hour
+
+	// how often old changes are deleted from the log
+	changeLogCleanupInterval = time.Hour
+)
+
+// entityChange is a row in the `entity_change` table
+type entityChange struct {
+	id        int64
+	grn       *entity.GRN
+	folder    string
+	version   string
+	action    entity.EntityWatchResponse_Action
+	labels    *string
+	updatedAt int64
+	updatedBy string
+}
+
+// Append an event to the change log.  This must be called in the same transaction as the change
+func recordChange(ctx context.Context, tx *session.SessionTx, c *entityChange) error {
+	_, err := tx.Exec(ctx, "INSERT INTO entity_change ("+
+		"grn, tenant_id, kind, uid, folder, version, "+
+		"action, labels, updated_at, updated_by) "+
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		c.grn.ToGRNString(), c.grn.TenantId, c.grn.Kind, c.grn.UID, c.folder, c.version,
+		int64(c.action), c.labels, c.updatedAt, c.updatedBy,
+	)
+	return err
+}
+
+// Watch streams changes from the entity change log.  The request `since` value is a position
+// in the log (the timestamp of a previously received event); when empty, the stream begins
+// with changes made after the watch was started.  Changes are kept for changeLogRetention, so
+// older positions miss the changes that were deleted.
+func (s *sqlEntityServer) Watch(r *entity.EntityWatchRequest, w entity.EntityStore_WatchServer) error {
+	ctx := w.Context()
+	user, err := appcontext.User(ctx)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return fmt.Errorf("missing user in context")
+	}
+
+	for _, grn := range r.GRN {
+		if grn.TenantId == 0 {
+			grn.TenantId = user.OrgID
+		} else if grn.TenantId != user.OrgID {
+			return fmt.Errorf("tenant ID does not match userID")
+		}
+	}
+
+	since := r.Since
+	if since < 1 {
+		since, err = s.getChangeLogHead(ctx, user.OrgID)
+		if err != nil {
+			return err
+		}
+	}
+
+	interval := s.watchInterval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Keep reading while full batches are returned
+		for {
+			changes, err := s.readChanges(ctx, user.OrgID, r, since)
+			if err != nil {
+				return err
+			}
+			for _, c := range changes {
+				since = c.id
+				if !changeMatches(r, c) {
+					continue
+				}
+				rsp, err := s.toWatchResponse(ctx, r, c)
+				if err != nil {
+					return err
+				}
+				if err = w.Send(rsp); err != nil {
+					return err
+				}
+			}
+			if len(changes) < watchBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Delete the changes older than changeLogRetention.  This is called after changes are recorded
+// and runs at most once per changeLogCleanupInterval in each instance
+func (s *sqlEntityServer) cleanupChangeLog(ctx context.Context) {
+	now := time.Now()
+	last := s.lastChangeLogCleanup.Load()
+	if now.Sub(time.UnixMilli(last)) < changeLogCleanupInterval || !s.lastChangeLogCleanup.CompareAndSwap(last, now.UnixMilli()) {
+		return
+	}
+
+	res, err := s.sess.Exec(ctx, "DELETE FROM entity_change WHERE updated_at < ?", now.Add(-changeLogRetention).UnixMilli())
+	if err != nil {
+		s.log.Warn("failed to delete old entity changes", "error", err)
+		return
+	}
+	if count, err := res.RowsAffected(); err == nil && count > 0 {
+		s.log.Debug("deleted old entity changes", "count", count)
+	}
+}
+
+func (s *sqlEntityServer) getChangeLogHead(ctx context.Context, tenantID int64) (int64, error) {
+	head := int64(0)
+	rows, err := s.sess.Query(ctx, "SELECT MAX(id) FROM entity_change WHERE tenant_id=?", tenantID)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	if rows.Next() {
+		var v *int64
+		if err = rows.Scan(&v); err != nil {
+			return 0, err
+		}
+		if v != nil {
+			head = *v
+		}
+	}
+	return head, rows.Err()
+}
+
+func getWatchQuery(tenantID int64, r *entity.EntityWatchRequest, since int64) (string, []interface{}) {
+	entityQuery := selectQuery{
+		fields: []string{
+			"id", "tenant_id", "kind", "uid", "folder", "version",
+			"action", "labels", "updated_at", "updated_by",
+		},
+		from:    "entity_change",
+		orderBy: "id ASC",
+		args:    []interface{}{},
+		limit:   watchBatchSize,
+	}
+	entityQuery.addWhere("tenant_id", tenantID)
+	entityQuery.addWhereGreater("id", since)
+
+	if len(r.Kind) > 0 {
+		entityQuery.addWhereIn("kind", r.Kind)
+	}
+	if r.Folder != "" {
+		entityQuery.addWhere("folder", r.Folder)
+	}
+	if len(r.GRN) > 0 {
+		grns := make([]string, 0, len(r.GRN))
+		for _, grn := range r.GRN {
+			grns = append(grns, grn.ToGRNString())
+		}
+		entityQuery.addWhereIn("grn", grns)
+	}
+
+	return entityQuery.toQuery()
+}
+
+func (s *sqlEntityServer) readChanges(ctx context.Context, tenantID int64, r *entity.EntityWatchRequest, since int64) ([]*entityChange, error) {
+	query, args := getWatchQuery(tenantID, r, since)
+	rows, err := s.sess.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	changes := make([]*entityChange, 0)
+	for rows.Next() {
+		c := &entityChange{
+			grn: &entity.GRN{},
+		}
+		action := int64(0)
+		err = rows.Scan(&c.id, &c.grn.TenantId, &c.grn.Kind, &c.grn.UID, &c.folder, &c.version,
+			&action, &c.labels, &c.updatedAt, &c.updatedBy)
+		if err != nil {
+			return nil, err
+		}
+		c.action = entity.EntityWatchResponse_Action(action)
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// Labels are saved as JSON, so they are checked after reading from the log
+func changeMatches(r *entity.EntityWatchRequest, c *entityChange) bool {
+	if len(r.Labels) == 0 {
+		return true
+	}
+	if c.labels == nil {
+		return false
+	}
+	labels := make(map[string]string)
+	if err := json.Unmarshal([]byte(*c.labels), &labels); err != nil {
+		return false
+	}
+	for k, v := range r.Labels {
+		found, ok := labels[k]
+		if !ok || found != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *sqlEntityServer) toWatchResponse(ctx context.Context, r *entity.EntityWatchRequest, c *entityChange) (*entity.EntityWatchResponse, error) {
+	e := &entity.Entity{
+		GRN:       c.grn,
+		Version:   c.version,
+		Folder:    c.folder,
+		UpdatedAt: c.updatedAt,
+		UpdatedBy: c.updatedBy,
+	}
+
+	// Load the body from history (deleted entities do not have one)
+	if c.action == entity.EntityWatchResponse_UPDATED && (r.WithBody || r.WithLabels || r.WithFields) {
+		found, err := s.Read(ctx, &entity.ReadEntityRequest{
+			GRN:         c.grn,
+			Version:     c.version,
+			WithBody:    r.WithBody,
+			WithSummary: r.WithLabels || r.WithFields,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if found.GRN != nil {
+			e.Body = found.Body
+			e.ETag = found.ETag
+			e.Size = found.Size
+			e.SummaryJson = found.SummaryJson
+		}
+	}
+
+	return &entity.EntityWatchResponse{
+		Timestamp: c.id,
+		Entity:    []*entity.Entity{e},
+		Action:    c.action,
+	}, nil
+}
@@ -0,0 +1,31 @@
+package sqlstash
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/store/entity"
+)
+
+func TestWatchQuery(t *testing.T) {
+	query, args := getWatchQuery(1, &entity.EntityWatchRequest{
+		Kind:   []string{"dashboard", "folder"},
+		Folder: "abc",
+	}, 25)
+	require.Equal(t, "SELECT id,tenant_id,kind,uid,folder,version,action,labels,updated_at,updated_by "+
+		"FROM entity_change WHERE tenant_id=? AND id>? AND kind IN (?,?)  AND folder=? "+
+		"ORDER BY id ASC LIMIT ?", query)
+	require.Equal(t, []interface{}{int64(1), int64(25), "dashboard", "folder", "abc", int64(watchBatchSize)}, args)
+}
+
+func TestChangeMatches(t *testing.T) {
+	labels := `{"env":"prod","team":"a"}`
+	change := &entityChange{labels: &labels}
+
+	require.True(t, changeMatches(&entity.EntityWatchRequest{}, change))
+	require.True(t, changeMatches(&entity.EntityWatchRequest{Labels: map[string]string{"env": "prod"}}, change))
+	require.False(t, changeMatches(&entity.EntityWatchRequest{Labels: map[string]string{"env": "dev"}}, change))
+	require.False(t, changeMatches(&entity.EntityWatchRequest{Labels: map[string]string{"other": ""}}, change))
+	require.False(t, changeMatches(&entity.EntityWatchRequest{Labels: map[string]string{"env": "prod"}}, &entityChange{}))
+}
@@ -5,6 +5,7 @@ import "strings"
 type selectQuery struct {
 	fields   []string // SELECT xyz
 	from     string   // FROM object
+	orderBy  string   // ORDER BY xyz
 	limit    int64
 	oneExtra bool
 
@@ -17,6 +18,11 @@ func (q *selectQuery) addWhere(f string, val interface{}) {
 	q.where = append(q.where, f+"=?")
 }
 
+func (q *selectQuery) addWhereGreater(f string, val interface{}) {
+	q.args = append(q.args, val)
+	q.where = append(q.where, f+">?")
+}
+
 func (q *selectQuery) addWhereInSubquery(f string, subquery string, subqueryArgs []interface{}) {
 	q.args = append(q.args, subqueryArgs...)
 	q.where = append(q.where, f+" IN ("+subquery+")")
@@ -62,6 +68,11 @@ func (q *selectQuery) toQuery() (string, []interface{}) {
 		}
 	}
 
+	if q.orderBy != "" {
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(q.orderBy)
+	}
+
 	if q.limit > 0 || q.oneExtra {
 		limit := q.limit
 		if limit < 1 {
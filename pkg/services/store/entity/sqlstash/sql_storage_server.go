@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/appcontext"
@@ -32,6 +33,8 @@ func ProvideSQLEntityServer(db db.DB, cfg *setting.Cfg, grpcServerProvider grpcs
 		log:      log.New("sql-entity-server"),
 		kinds:    kinds,
 		resolver: resolver,
+
+		watchInterval: defaultWatchInterval,
 	}
 	entity.RegisterEntityStoreServer(grpcServerProvider.GetServer(), entityServer)
 	return entityServer
@@ -42,6 +45,12 @@ type sqlEntityServer struct {
 	sess     *session.SessionDB
 	kinds    kind.KindRegistry
 	resolver resolver.EntityReferenceResolver
+
+	// how often watchers check the change log
+	watchInterval time.Duration
+
+	// when old changes were last deleted from the change log (unix milliseconds)
+	lastChangeLogCleanup atomic.Int64
 }
 
 func getReadSelect(r *entity.ReadEntityRequest) string {
@@ -449,6 +458,17 @@ func (s *sqlEntityServer) AdminWrite(ctx context.Context, r *entity.AdminWriteEn
 		if err == nil && entity.StandardKindFolder == r.GRN.Kind {
 			err = updateFolderTree(ctx, tx, grn.TenantId)
 		}
+		if err == nil {
+			err = recordChange(ctx, tx, &entityChange{
+				grn:       grn,
+				folder:    r.Folder,
+				version:   versionInfo.Version,
+				action:    entity.EntityWatchResponse_UPDATED,
+				labels:    summary.labels,
+				updatedAt: updatedAt,
+				updatedBy: versionInfo.UpdatedBy,
+			})
+		}
 		if err == nil {
 			summary.folder = r.Folder
 			summary.parent_grn = grn
@@ -459,8 +479,10 @@ func (s *sqlEntityServer) AdminWrite(ctx context.Context, r *entity.AdminWriteEn
 	rsp.SummaryJson = summary.marshaled
 	if err != nil {
 		rsp.Status = entity.WriteEntityResponse_ERROR
+		return rsp, err
 	}
-	return rsp, err
+	s.cleanupChangeLog(ctx)
+	return rsp, nil
 }
 
 func (s *sqlEntityServer) fillCreationInfo(ctx context.Context, tx *session.SessionTx, grn string, createdAt *int64, createdBy *string) error {
@@ -632,14 +654,50 @@ func (s *sqlEntityServer) Delete(ctx context.Context, r *entity.DeleteEntityRequ
 		return nil, err
 	}
 
+	modifier, err := appcontext.User(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	rsp := &entity.DeleteEntityResponse{}
 	err = s.sess.WithTransaction(ctx, func(tx *session.SessionTx) error {
+		change, err := s.selectForDelete(ctx, tx, grn)
+		if err != nil {
+			return err
+		}
 		rsp.OK, err = doDelete(ctx, tx, grn)
-		return err
+		if err != nil || !rsp.OK {
+			return err
+		}
+		change.action = entity.EntityWatchResponse_DELETED
+		change.updatedAt = time.Now().UnixMilli()
+		change.updatedBy = store.GetUserIDString(modifier)
+		return recordChange(ctx, tx, change)
 	})
+	if err == nil && rsp.OK {
+		s.cleanupChangeLog(ctx)
+	}
 	return rsp, err
 }
 
+// Read the values that should be saved in the change log before an entity is removed
+func (s *sqlEntityServer) selectForDelete(ctx context.Context, tx *session.SessionTx, grn *entity.GRN) (*entityChange, error) {
+	rows, err := tx.Query(ctx, "SELECT folder,version,labels FROM entity WHERE grn=?", grn.ToGRNString())
+	if err != nil {
+		return nil, err
+	}
+	change := &entityChange{grn: grn}
+	if rows.Next() {
+		err = rows.Scan(&change.folder, &change.version, &change.labels)
+	}
+
+	errClose := rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	return change, errClose
+}
+
 func doDelete(ctx context.Context, tx *session.SessionTx, grn *entity.GRN) (bool, error) {
 	str := grn.ToGRNString()
 	results, err := tx.Exec(ctx, "DELETE FROM entity WHERE grn=?", str)
@@ -846,7 +904,3 @@ func (s *sqlEntityServer) Search(ctx context.Context, r *entity.EntitySearchRequ
 
 	return rsp, err
 }
-
-func (s *sqlEntityServer) Watch(*entity.EntityWatchRequest, entity.EntityStore_WatchServer) error {
-	return fmt.Errorf("unimplemented")
-}
@@ -1,6 +1,7 @@
 package entity_server_tests
 
 import (
+	"context"
 	_ "embed"
 	"encoding/json"
 	"fmt"
@@ -476,4 +477,79 @@ func TestIntegrationEntityServer(t *testing.T) {
 		require.NotNil(t, search)
 		require.Len(t, search.Results, 0)
 	})
+
+	t.Run("should be able to watch changes and resume from a position", func(t *testing.T) {
+		watchGRN := &entity.GRN{
+			Kind: kind,
+			UID:  "watched-entity",
+		}
+		watchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		w1, err := testCtx.client.Write(ctx, &entity.WriteEntityRequest{
+			GRN:  watchGRN,
+			Body: []byte(`{"hello": "world"}`),
+		})
+		require.NoError(t, err)
+
+		// start from the beginning of the log, only the changes of the watched entity are sent
+		stream, err := testCtx.client.Watch(watchCtx, &entity.EntityWatchRequest{
+			Since:    1,
+			GRN:      []*entity.GRN{watchGRN},
+			WithBody: true,
+		})
+		require.NoError(t, err)
+
+		created, err := stream.Recv()
+		require.NoError(t, err)
+		require.Equal(t, entity.EntityWatchResponse_UPDATED, created.Action)
+		require.Len(t, created.Entity, 1)
+		require.Equal(t, watchGRN.UID, created.Entity[0].GRN.UID)
+		require.Equal(t, w1.Entity.Version, created.Entity[0].Version)
+		require.JSONEq(t, `{"hello": "world"}`, string(created.Entity[0].Body))
+
+		w2, err := testCtx.client.Write(ctx, &entity.WriteEntityRequest{
+			GRN:             watchGRN,
+			Body:            []byte(`{"hello": "again"}`),
+			PreviousVersion: w1.Entity.Version,
+		})
+		require.NoError(t, err)
+
+		updated, err := stream.Recv()
+		require.NoError(t, err)
+		require.Equal(t, entity.EntityWatchResponse_UPDATED, updated.Action)
+		require.Equal(t, w2.Entity.Version, updated.Entity[0].Version)
+		require.Greater(t, updated.Timestamp, created.Timestamp)
+
+		_, err = testCtx.client.Delete(ctx, &entity.DeleteEntityRequest{
+			GRN:             watchGRN,
+			PreviousVersion: w2.Entity.Version,
+		})
+		require.NoError(t, err)
+
+		deleted, err := stream.Recv()
+		require.NoError(t, err)
+		require.Equal(t, entity.EntityWatchResponse_DELETED, deleted.Action)
+		require.Nil(t, deleted.Entity[0].Body)
+		cancel()
+
+		// resuming after the first change sends the following changes only
+		resumeCtx, cancelResume := context.WithCancel(ctx)
+		defer cancelResume()
+		resumed, err := testCtx.client.Watch(resumeCtx, &entity.EntityWatchRequest{
+			Since: created.Timestamp,
+			GRN:   []*entity.GRN{watchGRN},
+		})
+		require.NoError(t, err)
+
+		rsp, err := resumed.Recv()
+		require.NoError(t, err)
+		require.Equal(t, updated.Timestamp, rsp.Timestamp)
+		require.Equal(t, entity.EntityWatchResponse_UPDATED, rsp.Action)
+
+		rsp, err = resumed.Recv()
+		require.NoError(t, err)
+		require.Equal(t, deleted.Timestamp, rsp.Timestamp)
+		require.Equal(t, entity.EntityWatchResponse_DELETED, rsp.Action)
+	})
 }
@@ -56,6 +56,9 @@ type SaveEventCmd struct {
 
 type EventHandler func(ctx context.Context, e *EntityEvent) error
 
+// EntityEventsRetention is how long events are kept before they are deleted
+const EntityEventsRetention = 24 * time.Hour
+
 // EntityEventsService is a temporary solution to support change notifications in an HA setup
 // With this service each system can query for any events that have happened since a fixed time
 //
@@ -132,7 +135,7 @@ func (e *entityEventService) Run(ctx context.Context) error {
 		select {
 		case <-clean.C:
 			go func() {
-				err := e.deleteEventsOlderThan(context.Background(), 24*time.Hour)
+				err := e.deleteEventsOlderThan(context.Background(), EntityEventsRetention)
 				if err != nil {
 					e.log.Info("failed to delete old entity events", "error", err)
 				}
@@ -423,6 +423,15 @@ func readpanelInfo(iter *jsoniter.Iterator, lookup DatasourceLookup) panelInfo {
 				}
 			}
 
+		case "libraryPanel":
+			for sub := iter.ReadObject(); sub != ""; sub = iter.ReadObject() {
+				if sub == "uid" {
+					panel.LibraryPanel = iter.ReadString()
+				} else {
+					iter.Skip()
+				}
+			}
+
 		// Rows have nested panels
 		case "panels":
 			for iter.ReadArray() {
@@ -445,6 +454,7 @@ func readpanelInfo(iter *jsoniter.Iterator, lookup DatasourceLookup) panelInfo {
 	}
 
 	panel.Datasource = targets.GetDatasourceInfo()
+	panel.Queries = targets.queries
 
 	return panel
 }
@@ -70,6 +70,7 @@ func TestReadDashboard(t *testing.T) {
 		"mixed-datasource-with-variable",
 		"special-datasource-types",
 		"panels-without-datasources",
+		"panels-with-queries",
 	}
 
 	devdash := "../../../../../devenv/dev-dashboards/"
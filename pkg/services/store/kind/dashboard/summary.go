@@ -76,6 +76,9 @@ func NewStaticDashboardSummaryBuilder(lookup DatasourceLookup, sanitize bool) en
 			p.Description = panel.Description
 			p.Fields = make(map[string]interface{}, 0)
 			p.Fields["type"] = panel.Type
+			if len(panel.Queries) > 0 {
+				p.Fields["queries"] = panel.Queries
+			}
 
 			if panel.Type != "row" {
 				panelRefs.Add(entity.ExternalEntityReferencePlugin, string(plugins.Panel), panel.Type)
@@ -92,6 +95,10 @@ func NewStaticDashboardSummaryBuilder(lookup DatasourceLookup, sanitize bool) en
 				panelRefs.Add(entity.ExternalEntityReferenceRuntime, entity.ExternalEntityReferenceRuntime_Transformer, v)
 				dashboardRefs.Add(entity.ExternalEntityReferenceRuntime, entity.ExternalEntityReferenceRuntime_Transformer, v)
 			}
+			if panel.LibraryPanel != "" {
+				panelRefs.Add(entity.StandardKindLibraryPanel, "", panel.LibraryPanel)
+				dashboardRefs.Add(entity.StandardKindLibraryPanel, "", panel.LibraryPanel)
+			}
 			p.References = panelRefs.Get()
 			summary.Nested = append(summary.Nested, p)
 		}
@@ -1,12 +1,15 @@
 package dashboard
 
 import (
+	"strings"
+
 	jsoniter "github.com/json-iterator/go"
 )
 
 type targetInfo struct {
-	lookup DatasourceLookup
-	uids   map[string]*DataSourceRef
+	lookup  DatasourceLookup
+	uids    map[string]*DataSourceRef
+	queries []string
 }
 
 func newTargetInfo(lookup DatasourceLookup) targetInfo {
@@ -75,6 +78,16 @@ func (s *targetInfo) addTarget(iter *jsoniter.Iterator) {
 		case "refId":
 			iter.Skip()
 
+		// The query text for common datasources (prometheus, loki, sql, graphite, ...)
+		case "expr", "query", "rawSql", "target", "queryText", "expression":
+			if iter.WhatIsNext() != jsoniter.StringValue {
+				iter.Skip()
+				continue
+			}
+			if q := strings.TrimSpace(iter.ReadString()); q != "" {
+				s.queries = append(s.queries, q)
+			}
+
 		default:
 			v := iter.Read()
 			logf("[Panel.TARGET] %s=%v\n", l1Field, v)
@@ -89,6 +89,9 @@ type standardStorageService struct {
 	authService  storageAuthService
 	quotaService quota.Service
 	systemUsers  SystemUsersFilterProvider
+
+	// object storage included in the file quota
+	objectFiles *objectFileCounter
 }
 
 func ProvideService(
@@ -162,6 +165,27 @@ func ProvideService(
 		}
 	}
 
+	// Object storage roots defined in grafana.ini
+	for _, root := range cfg.Storage.ObjectRoots {
+		if root.Prefix == "" {
+			grafanaStorageLogger.Warn("Invalid object storage root configuration", "cfg", root)
+			continue
+		}
+
+		globalRoots = append(globalRoots, newObjectStorage(RootStorageMeta{
+			ReadOnly: root.ReadOnly,
+		}, RootStorageConfig{
+			Prefix:           root.Prefix,
+			UnderContentRoot: true,
+			Name:             root.Name,
+			Description:      root.Description,
+			Object: &StorageObjectConfig{
+				BucketURL: root.BucketURL,
+				Folder:    root.Folder,
+			},
+		}))
+	}
+
 	initializeOrgStorages := func(orgId int64) []storageRuntime {
 		storages := make([]storageRuntime, 0)
 
@@ -171,10 +195,25 @@ func ProvideService(
 			}, RootContent, "Content", "Content root", &StorageSQLConfig{}, sql, orgId, false))
 
 		// Custom upload files
-		storages = append(storages,
-			newSQLStorage(RootStorageMeta{
-				Builtin: true,
-			}, RootResources, "Resources", "Upload custom resource files", &StorageSQLConfig{}, sql, orgId, false))
+		if cfg.Storage.ResourcesBucketURL != "" {
+			storages = append(storages,
+				newObjectStorage(RootStorageMeta{
+					Builtin: true,
+				}, RootStorageConfig{
+					Prefix:      RootResources,
+					Name:        "Resources",
+					Description: "Upload custom resource files",
+					Object: &StorageObjectConfig{
+						BucketURL: cfg.Storage.ResourcesBucketURL,
+						Folder:    getDbStoragePathPrefix(orgId, RootResources),
+					},
+				}))
+		} else {
+			storages = append(storages,
+				newSQLStorage(RootStorageMeta{
+					Builtin: true,
+				}, RootResources, "Resources", "Upload custom resource files", &StorageSQLConfig{}, sql, orgId, false))
+		}
 
 		// System settings
 		storages = append(storages,
@@ -254,6 +293,7 @@ func ProvideService(
 	s := newStandardStorageService(sql, globalRoots, initializeOrgStorages, authService, cfg, systemUsersService)
 	s.quotaService = quotaService
 	s.cfg = settings
+	s.objectFiles = newObjectFileCounter(globalRoots, cfg.Storage.ResourcesBucketURL)
 
 	defaultLimits, err := readQuotaConfig(cfg)
 	if err != nil {
@@ -351,6 +391,16 @@ func (s *standardStorageService) Read(ctx context.Context, user *user.SignedInUs
 func (s *standardStorageService) Usage(ctx context.Context, ScopeParameters *quota.ScopeParameters) (*quota.Map, error) {
 	u := &quota.Map{}
 
+	// Files saved in object storage are not in the `file` table
+	objectCount := int64(0)
+	if s.objectFiles != nil {
+		count, err := s.objectFiles.Count(ctx)
+		if err != nil {
+			return u, err
+		}
+		objectCount = count
+	}
+
 	err := s.sql.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
 		type result struct {
 			Count int64
@@ -366,7 +416,7 @@ func (s *standardStorageService) Usage(ctx context.Context, ScopeParameters *quo
 		if err != nil {
 			return err
 		}
-		u.Set(tag, r.Count)
+		u.Set(tag, r.Count+objectCount)
 
 		return nil
 	})
@@ -439,7 +489,7 @@ func (s *standardStorageService) Upload(ctx context.Context, user *user.SignedIn
 }
 
 func (s *standardStorageService) checkFileQuota(ctx context.Context, path string) error {
-	// assumes we are only uploading to the SQL database - TODO: refactor once we introduce object stores
+	// the file quota includes the SQL database and object storage
 	quotaReached, err := s.quotaService.CheckQuotaReached(ctx, QuotaTargetSrv, nil)
 	if err != nil {
 		grafanaStorageLogger.Error("failed while checking upload quota", "path", path, "error", err)
@@ -0,0 +1,243 @@
+package store
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"gocloud.dev/blob"
+	_ "gocloud.dev/blob/azureblob"
+	_ "gocloud.dev/blob/gcsblob"
+	_ "gocloud.dev/blob/s3blob"
+
+	"github.com/grafana/grafana/pkg/infra/filestorage"
+)
+
+const rootStorageTypeObject = "object"
+
+var _ storageRuntime = &rootStorageObject{}
+
+// rootStorageObject keeps files in a bucket that can be opened by the Go CDK (s3, gcs, azblob, mem, file)
+type rootStorageObject struct {
+	settings *StorageObjectConfig
+	meta     RootStorageMeta
+	store    filestorage.FileStorage
+}
+
+func newObjectStorage(meta RootStorageMeta, scfg RootStorageConfig) *rootStorageObject {
+	cfg := scfg.Object
+	if cfg == nil {
+		cfg = &StorageObjectConfig{}
+		scfg.Object = cfg
+	}
+	scfg.Type = rootStorageTypeObject
+	meta.Config = scfg
+	if scfg.Prefix == "" {
+		meta.Notice = append(meta.Notice, data.Notice{
+			Severity: data.NoticeSeverityError,
+			Text:     "Missing prefix",
+		})
+	}
+	if cfg.BucketURL == "" {
+		meta.Notice = append(meta.Notice, data.Notice{
+			Severity: data.NoticeSeverityError,
+			Text:     "Missing bucket URL configuration",
+		})
+	}
+
+	s := &rootStorageObject{
+		settings: cfg,
+	}
+
+	if meta.Notice == nil {
+		bucket, err := blob.OpenBucket(context.Background(), cfg.BucketURL)
+		if err != nil {
+			grafanaStorageLogger.Warn("error loading storage", "prefix", scfg.Prefix, "err", err)
+			meta.Notice = append(meta.Notice, data.Notice{
+				Severity: data.NoticeSeverityError,
+				Text:     "Failed to initialize storage",
+			})
+		} else {
+			s.store = filestorage.NewCdkBlobStorage(grafanaStorageLogger,
+				bucket, getObjectStorageRootFolder(cfg.Folder),
+				filestorage.NewAllowAllPathFilter())
+
+			meta.Ready = true
+		}
+	}
+
+	s.meta = meta
+	return s
+}
+
+// getObjectStorageRootFolder converts a bucket subfolder into the root folder key prefix.
+// example:
+//
+//	folder: "/grafana/files"
+//	  => root folder: "grafana/files/"
+func getObjectStorageRootFolder(folder string) string {
+	folder = strings.Trim(folder, filestorage.Delimiter)
+	if folder == "" {
+		return ""
+	}
+	return folder + filestorage.Delimiter
+}
+
+func (s *rootStorageObject) Meta() RootStorageMeta {
+	return s.meta
+}
+
+func (s *rootStorageObject) Store() filestorage.FileStorage {
+	return s.store
+}
+
+func (s *rootStorageObject) Sync() error {
+	return nil // already in sync
+}
+
+// like local disk, object storage does not keep user metadata or messages
+func (s *rootStorageObject) Write(ctx context.Context, cmd *WriteValueRequest) (*WriteValueResponse, error) {
+	byteAray := []byte(cmd.Body)
+
+	path := cmd.Path
+	if !strings.HasPrefix(path, filestorage.Delimiter) {
+		path = filestorage.Delimiter + path
+	}
+	err := s.store.Upsert(ctx, &filestorage.UpsertFileCommand{
+		Path:     path,
+		Contents: byteAray,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &WriteValueResponse{Code: 200}, nil
+}
+
+// objectFileCountTTL is how long the number of files in object storage is kept before the
+// buckets are listed again
+const objectFileCountTTL = time.Minute
+
+// resourcesKeyRegex matches the keys of the files of the per-org resources roots in a bucket
+var resourcesKeyRegex = regexp.MustCompile(`^\d+/` + RootResources + `/`)
+
+// objectFileCounter counts the files saved in object storage for the file quota.  Every bucket
+// is listed once, and each file is counted once even when it is under several roots.  The count
+// is kept for objectFileCountTTL so quota checks do not list the buckets on every upload.
+type objectFileCounter struct {
+	buckets []*objectQuotaBucket
+
+	mu      sync.Mutex
+	count   int64
+	updated time.Time
+}
+
+type objectQuotaBucket struct {
+	url   string
+	store filestorage.FileStorage
+	// the keys counted in the bucket
+	matchers []func(key string) bool
+}
+
+func newObjectFileCounter(globalRoots []storageRuntime, resourcesBucketURL string) *objectFileCounter {
+	c := &objectFileCounter{}
+	for _, root := range globalRoots {
+		meta := root.Meta()
+		if meta.Config.Type == rootStorageTypeObject && meta.Ready && !meta.ReadOnly {
+			folder := getObjectStorageRootFolder(meta.Config.Object.Folder)
+			c.addBucket(meta.Config.Object.BucketURL, func(key string) bool {
+				return strings.HasPrefix(key, folder)
+			})
+		}
+	}
+
+	// The resources of every org share one bucket, under the org prefix
+	if resourcesBucketURL != "" {
+		c.addBucket(resourcesBucketURL, resourcesKeyRegex.MatchString)
+	}
+	return c
+}
+
+func (c *objectFileCounter) addBucket(url string, matcher func(key string) bool) {
+	for _, b := range c.buckets {
+		if b.url == url {
+			b.matchers = append(b.matchers, matcher)
+			return
+		}
+	}
+
+	bucket := newObjectStorage(RootStorageMeta{}, RootStorageConfig{
+		Prefix: "quota",
+		Object: &StorageObjectConfig{
+			BucketURL: url,
+		},
+	})
+	if !bucket.Meta().Ready {
+		return
+	}
+	c.buckets = append(c.buckets, &objectQuotaBucket{
+		url:      url,
+		store:    bucket.Store(),
+		matchers: []func(key string) bool{matcher},
+	})
+}
+
+// Count returns the number of files in object storage
+func (c *objectFileCounter) Count(ctx context.Context) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.updated.IsZero() && time.Since(c.updated) < objectFileCountTTL {
+		return c.count, nil
+	}
+
+	count := int64(0)
+	for _, b := range c.buckets {
+		n, err := countFiles(ctx, b.store, b.matches)
+		if err != nil {
+			return 0, err
+		}
+		count += n
+	}
+
+	c.count = count
+	c.updated = time.Now()
+	return count, nil
+}
+
+func (b *objectQuotaBucket) matches(path string) bool {
+	key := strings.TrimPrefix(path, filestorage.Delimiter)
+	for _, matcher := range b.matchers {
+		if matcher(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// countFiles returns the number of files saved in the store (folder markers are not included).
+// When match is set, only the files with a matching path are counted.
+func countFiles(ctx context.Context, store filestorage.FileStorage, match func(path string) bool) (int64, error) {
+	count := int64(0)
+	paging := &filestorage.Paging{First: 1000}
+	for {
+		rsp, err := store.List(ctx, filestorage.Delimiter, paging, &filestorage.ListOptions{
+			Recursive: true,
+			WithFiles: true,
+		})
+		if err != nil {
+			return count, err
+		}
+		for _, file := range rsp.Files {
+			if match == nil || match(file.FullPath) {
+				count++
+			}
+		}
+		if !rsp.HasMore || rsp.LastPath == "" {
+			return count, nil
+		}
+		paging.After = rsp.LastPath
+	}
+}
@@ -0,0 +1,127 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/filestorage"
+)
+
+func TestGetObjectStorageRootFolder(t *testing.T) {
+	require.Equal(t, "", getObjectStorageRootFolder(""))
+	require.Equal(t, "", getObjectStorageRootFolder("/"))
+	require.Equal(t, "grafana/", getObjectStorageRootFolder("grafana"))
+	require.Equal(t, "1/resources/", getObjectStorageRootFolder(getDbStoragePathPrefix(1, RootResources)))
+}
+
+func TestObjectStorage(t *testing.T) {
+	t.Run("should report missing configuration", func(t *testing.T) {
+		s := newObjectStorage(RootStorageMeta{}, RootStorageConfig{Prefix: "bucket"})
+		require.False(t, s.Meta().Ready)
+		require.Len(t, s.Meta().Notice, 1)
+	})
+
+	t.Run("should read and write files", func(t *testing.T) {
+		ctx := context.Background()
+		s := newObjectStorage(RootStorageMeta{}, RootStorageConfig{
+			Prefix: "bucket",
+			Object: &StorageObjectConfig{
+				BucketURL: "mem://",
+				Folder:    "grafana",
+			},
+		})
+		require.True(t, s.Meta().Ready)
+		require.Equal(t, rootStorageTypeObject, s.Meta().Config.Type)
+
+		rsp, err := s.Write(ctx, &WriteValueRequest{
+			Path: "folder/dash.json",
+			Body: []byte(`{"title":"hello"}`),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 200, rsp.Code)
+
+		err = s.Store().Upsert(ctx, &filestorage.UpsertFileCommand{
+			Path:     "/image.png",
+			Contents: []byte("png"),
+		})
+		require.NoError(t, err)
+
+		file, found, err := s.Store().Get(ctx, "/folder/dash.json", &filestorage.GetFileOptions{WithContents: true})
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, `{"title":"hello"}`, string(file.Contents))
+
+		count, err := countFiles(ctx, s.Store(), nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), count)
+
+		err = s.Store().Delete(ctx, "/image.png")
+		require.NoError(t, err)
+
+		count, err = countFiles(ctx, s.Store(), nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+	})
+}
+
+func TestObjectFileCounter(t *testing.T) {
+	ctx := context.Background()
+	bucketURL := "file://" + filepath.ToSlash(t.TempDir())
+
+	bucket := newObjectStorage(RootStorageMeta{}, RootStorageConfig{
+		Prefix: "bucket",
+		Object: &StorageObjectConfig{BucketURL: bucketURL},
+	})
+	require.True(t, bucket.Meta().Ready)
+	for _, path := range []string{"/grafana/dash.json", "/1/resources/image.png", "/2/resources/image.png"} {
+		err := bucket.Store().Upsert(ctx, &filestorage.UpsertFileCommand{Path: path, Contents: []byte("x")})
+		require.NoError(t, err)
+	}
+
+	grafanaRoot := newObjectStorage(RootStorageMeta{}, RootStorageConfig{
+		Prefix: "grafana",
+		Object: &StorageObjectConfig{BucketURL: bucketURL, Folder: "grafana"},
+	})
+
+	t.Run("should count the files of the org resources and of the roots once", func(t *testing.T) {
+		counter := newObjectFileCounter([]storageRuntime{grafanaRoot}, bucketURL)
+		require.Len(t, counter.buckets, 1)
+		count, err := counter.Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), count)
+	})
+
+	t.Run("should only count the files under the roots and the org resources", func(t *testing.T) {
+		counter := newObjectFileCounter(nil, bucketURL)
+		count, err := counter.Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), count)
+
+		counter = newObjectFileCounter([]storageRuntime{grafanaRoot}, "")
+		count, err = counter.Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), count)
+	})
+
+	t.Run("should keep the count until it expires", func(t *testing.T) {
+		counter := newObjectFileCounter([]storageRuntime{grafanaRoot}, bucketURL)
+		count, err := counter.Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), count)
+
+		err = grafanaRoot.Store().Upsert(ctx, &filestorage.UpsertFileCommand{Path: "/other.json", Contents: []byte("x")})
+		require.NoError(t, err)
+		count, err = counter.Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), count)
+
+		counter.updated = time.Now().Add(-objectFileCountTTL)
+		count, err = counter.Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(4), count)
+	})
+}
@@ -0,0 +1,48 @@
+package teamsync
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrTeamNotFound      = errutil.NewBase(errutil.StatusNotFound, "teamsync.team-not-found", errutil.WithPublicMessage("Team not found"))
+	ErrGroupNotFound     = errutil.NewBase(errutil.StatusNotFound, "teamsync.group-not-found", errutil.WithPublicMessage("Group not found"))
+	ErrGroupAlreadyAdded = errutil.NewBase(errutil.StatusBadRequest, "teamsync.group-already-added", errutil.WithPublicMessage("Group is already added to this team"))
+	ErrGroupIDRequired   = errutil.NewBase(errutil.StatusBadRequest, "teamsync.group-id-required", errutil.WithPublicMessage("Group ID is required"))
+)
+
+// TeamGroup maps an external group, as reported by an OAuth provider or LDAP, to a team
+type TeamGroup struct {
+	ID      int64  `json:"-" xorm:"pk autoincr 'id'"`
+	OrgID   int64  `json:"orgId" xorm:"org_id"`
+	TeamID  int64  `json:"teamId" xorm:"team_id"`
+	GroupID string `json:"groupId" xorm:"group_id"`
+
+	Created time.Time `json:"-"`
+	Updated time.Time `json:"-"`
+}
+
+type GetTeamGroupsQuery struct {
+	OrgID  int64
+	TeamID int64
+}
+
+type AddTeamGroupCommand struct {
+	OrgID   int64  `json:"-"`
+	TeamID  int64  `json:"-"`
+	GroupID string `json:"groupId"`
+}
+
+type RemoveTeamGroupCommand struct {
+	OrgID   int64
+	TeamID  int64
+	GroupID string
+}
+
+type SetTeamGroupsCommand struct {
+	OrgID    int64
+	TeamID   int64
+	GroupIDs []string
+}
@@ -0,0 +1,20 @@
+package teamsync
+
+import (
+	"context"
+)
+
+type Service interface {
+	// GetGroups returns the external groups mapped to a team.
+	GetGroups(ctx context.Context, query *GetTeamGroupsQuery) ([]*TeamGroup, error)
+	// AddGroup maps an external group to a team, members of the group are added to the team when they log in.
+	AddGroup(ctx context.Context, cmd *AddTeamGroupCommand) error
+	// RemoveGroup removes the mapping of an external group to a team.
+	RemoveGroup(ctx context.Context, cmd *RemoveTeamGroupCommand) error
+	// SetGroups replaces the external groups mapped to a team.
+	SetGroups(ctx context.Context, cmd *SetTeamGroupsCommand) error
+	// SyncUserTeams adds the user to the teams mapped to their external groups in the organizations they are a member of,
+	// and removes them from the teams they were added to by a previous sync and are no longer mapped to their groups.
+	// Memberships added through the API are left untouched.
+	SyncUserTeams(ctx context.Context, userID int64, groups []string) error
+}
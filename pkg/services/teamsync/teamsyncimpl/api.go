@@ -0,0 +1,78 @@
+package teamsyncimpl
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(router routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	router.Group("/api/teams/:teamId/groups", func(groupsRoute routing.RouteRegister) {
+		groupsRoute.Get("/", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(ac.ActionTeamsPermissionsRead, ac.ScopeTeamsID)), routing.Wrap(s.getGroupsHandler))
+		groupsRoute.Post("/", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(ac.ActionTeamsPermissionsWrite, ac.ScopeTeamsID)), routing.Wrap(s.addGroupHandler))
+		// group IDs such as URLs or nested group paths can contain slashes, they can be passed in the groupId query parameter instead
+		groupsRoute.Delete("/", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(ac.ActionTeamsPermissionsWrite, ac.ScopeTeamsID)), routing.Wrap(s.removeGroupHandler))
+		groupsRoute.Delete("/:groupId", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(ac.ActionTeamsPermissionsWrite, ac.ScopeTeamsID)), routing.Wrap(s.removeGroupHandler))
+	}, middleware.ReqSignedIn)
+}
+
+func (s *Service) getGroupsHandler(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+
+	groups, err := s.GetGroups(c.Req.Context(), &teamsync.GetTeamGroupsQuery{OrgID: c.OrgID, TeamID: teamID})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get team groups", err)
+	}
+	return response.JSON(http.StatusOK, groups)
+}
+
+func (s *Service) addGroupHandler(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+
+	cmd := teamsync.AddTeamGroupCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.OrgID
+	cmd.TeamID = teamID
+
+	if err := s.AddGroup(c.Req.Context(), &cmd); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to add group to team", err)
+	}
+	return response.Success("Group added to Team")
+}
+
+func (s *Service) removeGroupHandler(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+
+	groupID := web.Params(c.Req)[":groupId"]
+	if groupID == "" {
+		groupID = c.Query("groupId")
+	}
+	if groupID == "" {
+		return response.Error(http.StatusBadRequest, "groupId is required", nil)
+	}
+
+	if err := s.RemoveGroup(c.Req.Context(), &teamsync.RemoveTeamGroupCommand{OrgID: c.OrgID, TeamID: teamID, GroupID: groupID}); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to remove group from team", err)
+	}
+	return response.Success("Team Group removed")
+}
@@ -0,0 +1,156 @@
+package teamsyncimpl
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/authn/authnimpl/sync"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const (
+	// teamMemberPermission is the team permission given to the users added by the sync
+	teamMemberPermission = "Member"
+	// syncTeamsHookPriority runs the sync after the users and their organizations are synced
+	syncTeamsHookPriority = 50
+)
+
+var _ teamsync.Service = new(Service)
+
+func ProvideService(
+	db db.DB, routeRegister routing.RouteRegister, accessControl ac.AccessControl, teamService team.Service,
+	orgService org.Service, teamPermissionsService ac.TeamPermissionsService, loginService login.Service, authnService authn.Service,
+) *Service {
+	s := &Service{
+		store:                  &xormStore{db: db, now: time.Now},
+		accessControl:          accessControl,
+		teamService:            teamService,
+		orgService:             orgService,
+		teamPermissionsService: teamPermissionsService,
+		log:                    log.New("teamsync"),
+	}
+
+	// logins handled by the authn service sync teams through the post auth hook,
+	// the other ones through the login service
+	authnService.RegisterPostAuthHook(sync.ProvideTeamSync(s).SyncTeamsHook, syncTeamsHookPriority)
+	loginService.SetTeamSyncFunc(s.syncExternalUserTeams)
+
+	s.registerAPIEndpoints(routeRegister)
+
+	return s
+}
+
+// Service maps external groups to teams and keeps the team memberships of users in sync with their groups
+type Service struct {
+	store                  store
+	accessControl          ac.AccessControl
+	teamService            team.Service
+	orgService             org.Service
+	teamPermissionsService ac.TeamPermissionsService
+	log                    log.Logger
+}
+
+func (s *Service) GetGroups(ctx context.Context, query *teamsync.GetTeamGroupsQuery) ([]*teamsync.TeamGroup, error) {
+	return s.store.Get(ctx, query)
+}
+
+func (s *Service) AddGroup(ctx context.Context, cmd *teamsync.AddTeamGroupCommand) error {
+	if cmd.GroupID == "" {
+		return teamsync.ErrGroupIDRequired.Errorf("group id is required")
+	}
+	return s.store.Add(ctx, cmd)
+}
+
+func (s *Service) RemoveGroup(ctx context.Context, cmd *teamsync.RemoveTeamGroupCommand) error {
+	return s.store.Remove(ctx, cmd)
+}
+
+func (s *Service) SetGroups(ctx context.Context, cmd *teamsync.SetTeamGroupsCommand) error {
+	for _, groupID := range cmd.GroupIDs {
+		if groupID == "" {
+			return teamsync.ErrGroupIDRequired.Errorf("group id is required")
+		}
+	}
+	return s.store.Set(ctx, cmd)
+}
+
+type teamKey struct {
+	orgID  int64
+	teamID int64
+}
+
+func (s *Service) SyncUserTeams(ctx context.Context, userID int64, groups []string) error {
+	mappings, err := s.store.GetByGroups(ctx, groups)
+	if err != nil {
+		return err
+	}
+
+	orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: userID})
+	if err != nil {
+		return err
+	}
+	isOrgMember := make(map[int64]bool, len(orgs))
+	for _, o := range orgs {
+		isOrgMember[o.OrgID] = true
+	}
+
+	// teams are only synced in the organizations the user is a member of
+	wanted := make(map[teamKey]bool, len(mappings))
+	for _, m := range mappings {
+		if isOrgMember[m.OrgID] {
+			wanted[teamKey{m.OrgID, m.TeamID}] = true
+		}
+	}
+
+	memberships, err := s.teamService.GetUserTeamMemberships(ctx, 0, userID, false)
+	if err != nil {
+		return err
+	}
+	current := make(map[teamKey]*team.TeamMemberDTO, len(memberships))
+	for _, m := range memberships {
+		current[teamKey{m.OrgID, m.TeamID}] = m
+	}
+
+	for key := range wanted {
+		if _, ok := current[key]; ok {
+			continue
+		}
+		s.log.FromContext(ctx).Debug("Adding user to team", "userID", userID, "orgID", key.orgID, "teamID", key.teamID)
+		if err := s.setMembership(ctx, userID, key, teamMemberPermission); err != nil {
+			return err
+		}
+	}
+
+	for key, m := range current {
+		// only the memberships added by a sync are removed
+		if !m.External || wanted[key] {
+			continue
+		}
+		s.log.FromContext(ctx).Debug("Removing user from team", "userID", userID, "orgID", key.orgID, "teamID", key.teamID)
+		if err := s.setMembership(ctx, userID, key, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) setMembership(ctx context.Context, userID int64, key teamKey, permission string) error {
+	_, err := s.teamPermissionsService.SetUserPermission(ctx, key.orgID, ac.User{ID: userID, IsExternal: true}, strconv.FormatInt(key.teamID, 10), permission)
+	return err
+}
+
+// syncExternalUserTeams syncs the teams of users logging in through the login service
+func (s *Service) syncExternalUserTeams(usr *user.User, extUser *login.ExternalUserInfo) error {
+	return s.SyncUserTeams(context.Background(), usr.ID, extUser.Groups)
+}
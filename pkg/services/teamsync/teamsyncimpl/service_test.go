@@ -0,0 +1,132 @@
+package teamsyncimpl
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/authn/authntest"
+	"github.com/grafana/grafana/pkg/services/login/logintest"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamimpl"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationTeamSync(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	teamService := teamimpl.ProvideService(sqlStore, cfg)
+
+	createTeam := func(name string, orgID int64) int64 {
+		created, err := teamService.CreateTeam(name, "", orgID)
+		require.NoError(t, err)
+		return created.ID
+	}
+	developers := createTeam("developers", 1)
+	operators := createTeam("operators", 1)
+	otherOrgDevelopers := createTeam("developers", 2)
+	sre := createTeam("sre", 1)
+
+	members := &teamtest.FakeService{}
+	orgs := &orgtest.FakeOrgService{}
+	permissions := &fakeTeamPermissionsService{}
+	s := ProvideService(sqlStore, routing.NewRouteRegister(), acimpl.ProvideAccessControl(cfg), members, orgs, permissions,
+		&logintest.LoginServiceFake{}, &authntest.FakeService{})
+
+	t.Run("should map groups to teams", func(t *testing.T) {
+		require.NoError(t, s.AddGroup(ctx, &teamsync.AddTeamGroupCommand{OrgID: 1, TeamID: developers, GroupID: "devs"}))
+		require.NoError(t, s.AddGroup(ctx, &teamsync.AddTeamGroupCommand{OrgID: 1, TeamID: operators, GroupID: "ops"}))
+		require.NoError(t, s.AddGroup(ctx, &teamsync.AddTeamGroupCommand{OrgID: 2, TeamID: otherOrgDevelopers, GroupID: "devs"}))
+
+		groups, err := s.GetGroups(ctx, &teamsync.GetTeamGroupsQuery{OrgID: 1, TeamID: developers})
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		assert.Equal(t, "devs", groups[0].GroupID)
+	})
+
+	t.Run("should not add a group twice", func(t *testing.T) {
+		err := s.AddGroup(ctx, &teamsync.AddTeamGroupCommand{OrgID: 1, TeamID: developers, GroupID: "devs"})
+		assert.ErrorIs(t, err, teamsync.ErrGroupAlreadyAdded)
+	})
+
+	t.Run("should not add groups to teams of other organizations", func(t *testing.T) {
+		err := s.AddGroup(ctx, &teamsync.AddTeamGroupCommand{OrgID: 1, TeamID: otherOrgDevelopers, GroupID: "devs"})
+		assert.ErrorIs(t, err, teamsync.ErrTeamNotFound)
+	})
+
+	t.Run("should require a group id", func(t *testing.T) {
+		err := s.AddGroup(ctx, &teamsync.AddTeamGroupCommand{OrgID: 1, TeamID: developers})
+		assert.ErrorIs(t, err, teamsync.ErrGroupIDRequired)
+	})
+
+	t.Run("should replace the groups of a team", func(t *testing.T) {
+		require.NoError(t, s.SetGroups(ctx, &teamsync.SetTeamGroupsCommand{OrgID: 1, TeamID: sre, GroupIDs: []string{"ops", "oncall"}}))
+		require.NoError(t, s.SetGroups(ctx, &teamsync.SetTeamGroupsCommand{OrgID: 1, TeamID: sre, GroupIDs: []string{"sre", "sre", "ops"}}))
+
+		groups, err := s.GetGroups(ctx, &teamsync.GetTeamGroupsQuery{OrgID: 1, TeamID: sre})
+		require.NoError(t, err)
+		require.Len(t, groups, 2)
+		assert.Equal(t, "ops", groups[0].GroupID)
+		assert.Equal(t, "sre", groups[1].GroupID)
+	})
+
+	t.Run("should remove groups", func(t *testing.T) {
+		require.NoError(t, s.RemoveGroup(ctx, &teamsync.RemoveTeamGroupCommand{OrgID: 1, TeamID: sre, GroupID: "ops"}))
+		err := s.RemoveGroup(ctx, &teamsync.RemoveTeamGroupCommand{OrgID: 1, TeamID: sre, GroupID: "ops"})
+		assert.ErrorIs(t, err, teamsync.ErrGroupNotFound)
+	})
+
+	t.Run("should sync the teams of the user organizations", func(t *testing.T) {
+		orgs.ExpectedUserOrgDTO = []*org.UserOrgDTO{{OrgID: 1}}
+		members.ExpectedMembers = []*team.TeamMemberDTO{
+			// added by a previous sync and no longer in the sre group
+			{OrgID: 1, TeamID: sre, UserID: 5, External: true},
+			// added through the API
+			{OrgID: 1, TeamID: operators, UserID: 5},
+		}
+
+		require.NoError(t, s.SyncUserTeams(ctx, 5, []string{"devs"}))
+		assert.Equal(t, map[string]string{
+			strconv.FormatInt(developers, 10): "Member",
+			strconv.FormatInt(sre, 10):        "",
+		}, permissions.set)
+		assert.True(t, permissions.external)
+	})
+}
+
+type fakeTeamPermissionsService struct {
+	set      map[string]string
+	external bool
+}
+
+var _ accesscontrol.TeamPermissionsService = new(fakeTeamPermissionsService)
+
+func (f *fakeTeamPermissionsService) GetPermissions(ctx context.Context, user *user.SignedInUser, resourceID string) ([]accesscontrol.ResourcePermission, error) {
+	return nil, nil
+}
+
+func (f *fakeTeamPermissionsService) SetUserPermission(ctx context.Context, orgID int64, user accesscontrol.User, resourceID, permission string) (*accesscontrol.ResourcePermission, error) {
+	if f.set == nil {
+		f.set = map[string]string{}
+	}
+	f.set[resourceID] = permission
+	f.external = user.IsExternal
+	return &accesscontrol.ResourcePermission{}, nil
+}
@@ -0,0 +1,132 @@
+package teamsyncimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/teamsync"
+)
+
+type store interface {
+	Get(ctx context.Context, query *teamsync.GetTeamGroupsQuery) ([]*teamsync.TeamGroup, error)
+	// GetByGroups returns the mappings of the groups in every organization
+	GetByGroups(ctx context.Context, groupIDs []string) ([]*teamsync.TeamGroup, error)
+	Add(ctx context.Context, cmd *teamsync.AddTeamGroupCommand) error
+	Remove(ctx context.Context, cmd *teamsync.RemoveTeamGroupCommand) error
+	Set(ctx context.Context, cmd *teamsync.SetTeamGroupsCommand) error
+}
+
+type xormStore struct {
+	db  db.DB
+	now func() time.Time
+}
+
+func (s *xormStore) Get(ctx context.Context, query *teamsync.GetTeamGroupsQuery) ([]*teamsync.TeamGroup, error) {
+	result := make([]*teamsync.TeamGroup, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		if err := teamExists(sess, query.OrgID, query.TeamID); err != nil {
+			return err
+		}
+		return sess.Where("org_id = ? AND team_id = ?", query.OrgID, query.TeamID).Asc("group_id").Find(&result)
+	})
+	return result, err
+}
+
+func (s *xormStore) GetByGroups(ctx context.Context, groupIDs []string) ([]*teamsync.TeamGroup, error) {
+	result := make([]*teamsync.TeamGroup, 0)
+	if len(groupIDs) == 0 {
+		return result, nil
+	}
+
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		args := make([]interface{}, 0, len(groupIDs))
+		for _, id := range groupIDs {
+			args = append(args, id)
+		}
+		return sess.In("group_id", args...).Find(&result)
+	})
+	return result, err
+}
+
+func (s *xormStore) Add(ctx context.Context, cmd *teamsync.AddTeamGroupCommand) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if err := teamExists(sess, cmd.OrgID, cmd.TeamID); err != nil {
+			return err
+		}
+
+		exists, err := sess.Table("team_group").Where("org_id = ? AND team_id = ? AND group_id = ?", cmd.OrgID, cmd.TeamID, cmd.GroupID).Exist()
+		if err != nil {
+			return err
+		}
+		if exists {
+			return teamsync.ErrGroupAlreadyAdded.Errorf("group %s is already added to team %d", cmd.GroupID, cmd.TeamID)
+		}
+
+		return s.insert(sess, cmd.OrgID, cmd.TeamID, cmd.GroupID)
+	})
+}
+
+func (s *xormStore) Remove(ctx context.Context, cmd *teamsync.RemoveTeamGroupCommand) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if err := teamExists(sess, cmd.OrgID, cmd.TeamID); err != nil {
+			return err
+		}
+
+		affected, err := sess.Where("org_id = ? AND team_id = ? AND group_id = ?", cmd.OrgID, cmd.TeamID, cmd.GroupID).Delete(&teamsync.TeamGroup{})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return teamsync.ErrGroupNotFound.Errorf("group %s is not added to team %d", cmd.GroupID, cmd.TeamID)
+		}
+		return nil
+	})
+}
+
+func (s *xormStore) Set(ctx context.Context, cmd *teamsync.SetTeamGroupsCommand) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if err := teamExists(sess, cmd.OrgID, cmd.TeamID); err != nil {
+			return err
+		}
+
+		if _, err := sess.Where("org_id = ? AND team_id = ?", cmd.OrgID, cmd.TeamID).Delete(&teamsync.TeamGroup{}); err != nil {
+			return err
+		}
+
+		seen := make(map[string]bool, len(cmd.GroupIDs))
+		for _, groupID := range cmd.GroupIDs {
+			if seen[groupID] {
+				continue
+			}
+			seen[groupID] = true
+			if err := s.insert(sess, cmd.OrgID, cmd.TeamID, groupID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *xormStore) insert(sess *db.Session, orgID, teamID int64, groupID string) error {
+	now := s.now()
+	_, err := sess.Insert(&teamsync.TeamGroup{
+		OrgID:   orgID,
+		TeamID:  teamID,
+		GroupID: groupID,
+		Created: now,
+		Updated: now,
+	})
+	return err
+}
+
+func teamExists(sess *db.Session, orgID, teamID int64) error {
+	exists, err := sess.Table("team").Where("org_id = ? AND id = ?", orgID, teamID).Exist()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return teamsync.ErrTeamNotFound.Errorf("team %d not found in organization %d", teamID, orgID)
+	}
+	return nil
+}
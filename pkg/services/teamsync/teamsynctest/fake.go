@@ -0,0 +1,51 @@
+package teamsynctest
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/teamsync"
+)
+
+var _ teamsync.Service = new(FakeService)
+
+type FakeService struct {
+	ExpectedGroups []*teamsync.TeamGroup
+	ExpectedErr    error
+
+	// SyncedGroups records the groups of the last SyncUserTeams call by user ID
+	SyncedGroups map[int64][]string
+	// SetGroupsByTeam records the groups of the SetGroups calls by team ID
+	SetGroupsByTeam map[int64][]string
+}
+
+func NewFakeService() *FakeService {
+	return &FakeService{SyncedGroups: map[int64][]string{}, SetGroupsByTeam: map[int64][]string{}}
+}
+
+func (f *FakeService) GetGroups(ctx context.Context, query *teamsync.GetTeamGroupsQuery) ([]*teamsync.TeamGroup, error) {
+	return f.ExpectedGroups, f.ExpectedErr
+}
+
+func (f *FakeService) AddGroup(ctx context.Context, cmd *teamsync.AddTeamGroupCommand) error {
+	return f.ExpectedErr
+}
+
+func (f *FakeService) RemoveGroup(ctx context.Context, cmd *teamsync.RemoveTeamGroupCommand) error {
+	return f.ExpectedErr
+}
+
+func (f *FakeService) SetGroups(ctx context.Context, cmd *teamsync.SetTeamGroupsCommand) error {
+	if f.ExpectedErr != nil {
+		return f.ExpectedErr
+	}
+	f.SetGroupsByTeam[cmd.TeamID] = cmd.GroupIDs
+	return nil
+}
+
+func (f *FakeService) SyncUserTeams(ctx context.Context, userID int64, groups []string) error {
+	if f.ExpectedErr != nil {
+		return f.ExpectedErr
+	}
+	f.SyncedGroups[userID] = groups
+	return nil
+}
@@ -0,0 +1,66 @@
+package twofactor
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/middleware/cookies"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	// ChallengeCookieName is the cookie holding the login challenge between the password and the code steps
+	ChallengeCookieName = "grafana_two_factor"
+	// ChallengeTTL is how long users have to enter their code after entering their password
+	ChallengeTTL = 5 * time.Minute
+)
+
+var (
+	ErrDisabled          = errutil.NewBase(errutil.StatusNotFound, "twofactor.disabled", errutil.WithPublicMessage("Two-factor authentication is disabled"))
+	ErrNotEnrolled       = errutil.NewBase(errutil.StatusBadRequest, "twofactor.not-enrolled", errutil.WithPublicMessage("Two-factor authentication is not enabled for the user"))
+	ErrAlreadyEnabled    = errutil.NewBase(errutil.StatusBadRequest, "twofactor.already-enabled", errutil.WithPublicMessage("Two-factor authentication is already enabled for the user"))
+	ErrExternalUser      = errutil.NewBase(errutil.StatusBadRequest, "twofactor.external-user", errutil.WithPublicMessage("Two-factor authentication is only available for users managed by Grafana"))
+	ErrInvalidCode       = errutil.NewBase(errutil.StatusUnauthorized, "twofactor.invalid-code", errutil.WithPublicMessage("Invalid two-factor authentication code"))
+	ErrChallengeNotFound = errutil.NewBase(errutil.StatusUnauthorized, "twofactor.challenge-not-found", errutil.WithPublicMessage("Two-factor authentication expired, log in again"))
+	ErrRequired          = errutil.NewBase(errutil.StatusForbidden, "twofactor.required", errutil.WithPublicMessage("Two-factor authentication is required"))
+
+	// ErrCodeRequired is returned by password logins that need a code to complete
+	ErrCodeRequired = errutil.NewBase(errutil.StatusUnauthorized, "twofactor.code-required", errutil.WithPublicMessage("Two-factor authentication required"))
+	// ErrEnrollmentRequired is returned by password logins of users that need to enroll before they can log in
+	ErrEnrollmentRequired = errutil.NewBase(errutil.StatusUnauthorized, "twofactor.enrollment-required", errutil.WithPublicMessage("Two-factor authentication enrollment required"))
+)
+
+// Status is the two-factor authentication status of a user
+type Status struct {
+	// Enabled is true when the user confirmed an enrollment
+	Enabled bool `json:"enabled"`
+	// Required is true when the user is not allowed to log in with a password only
+	Required bool `json:"required"`
+	// RecoveryCodesRemaining is the number of recovery codes the user has not used yet
+	RecoveryCodesRemaining int64 `json:"recoveryCodesRemaining"`
+}
+
+// Enrollment holds what a user needs to set up an authenticator app.
+// It is only returned once, the secret and the recovery codes are not readable afterwards.
+type Enrollment struct {
+	Secret        string   `json:"secret"`
+	URL           string   `json:"url"`
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// LoginChallenge is the pending second step of a password login
+type LoginChallenge struct {
+	Token string `json:"-"`
+	// Enroll is true when the user is required to use two-factor authentication but is not enrolled yet
+	Enroll bool `json:"enroll"`
+}
+
+// WriteChallengeCookie stores the login challenge token until the user enters their code
+func WriteChallengeCookie(w http.ResponseWriter, token string) {
+	cookies.WriteCookie(w, ChallengeCookieName, token, int(ChallengeTTL.Seconds()), nil)
+}
+
+// DeleteChallengeCookie removes the login challenge token once the login completed
+func DeleteChallengeCookie(w http.ResponseWriter) {
+	cookies.DeleteCookie(w, ChallengeCookieName, nil)
+}
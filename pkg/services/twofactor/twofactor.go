@@ -0,0 +1,28 @@
+package twofactor
+
+import (
+	"context"
+)
+
+type Service interface {
+	// GetStatus returns whether the user has two-factor authentication enabled and whether it is required for them.
+	GetStatus(ctx context.Context, userID int64) (*Status, error)
+	// Enroll generates a new secret and new recovery codes for the user.
+	// They are only used once the enrollment is confirmed with a code from the authenticator app.
+	Enroll(ctx context.Context, userID int64) (*Enrollment, error)
+	// ConfirmEnrollment enables two-factor authentication for the user when the code matches the enrolled secret.
+	ConfirmEnrollment(ctx context.Context, userID int64, code string) error
+	// Disable removes the secret and the recovery codes of the user.
+	Disable(ctx context.Context, userID int64) error
+	// RegenerateRecoveryCodes replaces the recovery codes of a user with two-factor authentication enabled.
+	RegenerateRecoveryCodes(ctx context.Context, userID int64) ([]string, error)
+	// Verify checks a code from the authenticator app or consumes a recovery code.
+	Verify(ctx context.Context, userID int64, code string) error
+	// StartLogin returns a login challenge when a user that passed password authentication
+	// needs a second factor to log in, or nil when the password is enough.
+	StartLogin(ctx context.Context, userID int64) (*LoginChallenge, error)
+	// EnrollLogin enrolls the user of a login challenge that requires an enrollment.
+	EnrollLogin(ctx context.Context, token string) (*Enrollment, error)
+	// CompleteLogin verifies the code of a login challenge and returns the ID of the user to create a session for.
+	CompleteLogin(ctx context.Context, token, code, ipAddress string) (int64, error)
+}
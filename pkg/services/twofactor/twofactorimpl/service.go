@@ -0,0 +1,423 @@
+package twofactorimpl
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/twofactor"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	challengeKeyPrefix = "twofactor-login-"
+	// maxChallengeAttempts is the number of invalid codes and enrollments after which users have to enter their password again
+	maxChallengeAttempts = 5
+)
+
+var _ twofactor.Service = new(Service)
+
+func ProvideService(
+	db db.DB, cfg *setting.Cfg, secretsService secrets.Service, cache remotecache.CacheStorage,
+	userService user.Service, orgService org.Service, loginAttempts loginattempt.Service,
+) *Service {
+	return &Service{
+		store:          &xormStore{db: db, now: time.Now},
+		cfg:            cfg,
+		secretsService: secretsService,
+		cache:          cache,
+		userService:    userService,
+		orgService:     orgService,
+		loginAttempts:  loginAttempts,
+		log:            log.New("twofactor"),
+		now:            time.Now,
+	}
+}
+
+type Service struct {
+	store          store
+	cfg            *setting.Cfg
+	secretsService secrets.Service
+	cache          remotecache.CacheStorage
+	userService    user.Service
+	orgService     org.Service
+	loginAttempts  loginattempt.Service
+	log            log.Logger
+	now            func() time.Time
+}
+
+// loginChallenge is the state of a login challenge kept in the remote cache
+type loginChallenge struct {
+	UserID   int64     `json:"userId"`
+	Enroll   bool      `json:"enroll"`
+	Attempts int       `json:"attempts"`
+	Expires  time.Time `json:"expires"`
+}
+
+func (s *Service) GetStatus(ctx context.Context, userID int64) (*twofactor.Status, error) {
+	status := &twofactor.Status{}
+	if !s.cfg.TwoFactorEnabled {
+		return status, nil
+	}
+
+	tf, err := s.store.Get(ctx, userID)
+	if err != nil && !errors.Is(err, errNotFound) {
+		return nil, err
+	}
+	status.Enabled = tf != nil && tf.Enabled
+
+	if status.Required, err = s.isRequired(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if status.Enabled {
+		if status.RecoveryCodesRemaining, err = s.store.CountRecoveryCodes(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+func (s *Service) Enroll(ctx context.Context, userID int64) (*twofactor.Enrollment, error) {
+	if !s.cfg.TwoFactorEnabled {
+		return nil, twofactor.ErrDisabled.Errorf("two-factor authentication is disabled")
+	}
+
+	tf, err := s.store.Get(ctx, userID)
+	if err != nil && !errors.Is(err, errNotFound) {
+		return nil, err
+	}
+	if tf != nil && tf.Enabled {
+		return nil, twofactor.ErrAlreadyEnabled.Errorf("user %d already enabled two-factor authentication", userID)
+	}
+
+	usr, err := s.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := s.secretsService.Encrypt(ctx, []byte(secret), secrets.WithoutScope())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Save(ctx, userID, base64.StdEncoding.EncodeToString(encrypted), hashRecoveryCodes(codes)); err != nil {
+		return nil, err
+	}
+
+	return &twofactor.Enrollment{
+		Secret:        secret,
+		URL:           keyURI(s.cfg.TwoFactorIssuer, usr.Login, secret),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+func (s *Service) ConfirmEnrollment(ctx context.Context, userID int64, code string) error {
+	tf, err := s.get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if tf.Enabled {
+		return twofactor.ErrAlreadyEnabled.Errorf("user %d already enabled two-factor authentication", userID)
+	}
+
+	if err := s.verifyTOTP(ctx, tf, code); err != nil {
+		return err
+	}
+	return s.store.Enable(ctx, userID)
+}
+
+func (s *Service) Disable(ctx context.Context, userID int64) error {
+	return s.store.Delete(ctx, userID)
+}
+
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, userID int64) ([]string, error) {
+	if _, err := s.getEnabled(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.ReplaceRecoveryCodes(ctx, userID, hashRecoveryCodes(codes)); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *Service) Verify(ctx context.Context, userID int64, code string) error {
+	tf, err := s.getEnabled(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if looksLikeTOTPCode(code) {
+		return s.verifyTOTP(ctx, tf, code)
+	}
+
+	used, err := s.store.UseRecoveryCode(ctx, userID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return twofactor.ErrInvalidCode.Errorf("invalid recovery code for user %d", userID)
+	}
+	s.log.Info("Recovery code used", "userId", userID)
+	return nil
+}
+
+func (s *Service) StartLogin(ctx context.Context, userID int64) (*twofactor.LoginChallenge, error) {
+	if !s.cfg.TwoFactorEnabled {
+		return nil, nil
+	}
+
+	status, err := s.GetStatus(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !status.Enabled && !status.Required {
+		return nil, nil
+	}
+
+	token, err := generateChallengeToken()
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := &loginChallenge{
+		UserID:  userID,
+		Enroll:  !status.Enabled,
+		Expires: s.now().Add(twofactor.ChallengeTTL),
+	}
+	if err := s.saveChallenge(ctx, token, challenge); err != nil {
+		return nil, err
+	}
+
+	return &twofactor.LoginChallenge{Token: token, Enroll: challenge.Enroll}, nil
+}
+
+func (s *Service) EnrollLogin(ctx context.Context, token string) (*twofactor.Enrollment, error) {
+	challenge, err := s.getChallenge(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !challenge.Enroll {
+		return nil, twofactor.ErrAlreadyEnabled.Errorf("user %d already enabled two-factor authentication", challenge.UserID)
+	}
+
+	// every enrollment replaces the secret, so it is limited like the codes
+	if !s.countChallengeAttempt(ctx, token, challenge) {
+		return nil, twofactor.ErrChallengeNotFound.Errorf("too many attempts for login challenge")
+	}
+	return s.Enroll(ctx, challenge.UserID)
+}
+
+func (s *Service) CompleteLogin(ctx context.Context, token, code, ipAddress string) (int64, error) {
+	challenge, err := s.getChallenge(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+
+	if challenge.Enroll {
+		err = s.ConfirmEnrollment(ctx, challenge.UserID, code)
+	} else {
+		err = s.Verify(ctx, challenge.UserID, code)
+	}
+
+	if err != nil {
+		if errors.Is(err, twofactor.ErrInvalidCode) {
+			s.failChallenge(ctx, token, challenge, ipAddress)
+		}
+		return 0, err
+	}
+
+	if err := s.cache.Delete(ctx, challengeKey(token)); err != nil {
+		s.log.FromContext(ctx).Warn("Failed to delete login challenge", "error", err)
+	}
+	return challenge.UserID, nil
+}
+
+// failChallenge counts an invalid code towards both the challenge attempts and the brute force login protection
+func (s *Service) failChallenge(ctx context.Context, token string, challenge *loginChallenge, ipAddress string) {
+	logger := s.log.FromContext(ctx)
+
+	usr, err := s.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: challenge.UserID})
+	if err == nil {
+		if err := s.loginAttempts.Add(ctx, usr.Login, ipAddress); err != nil {
+			logger.Error("Failed to save invalid login attempt", "error", err)
+		}
+	}
+
+	s.countChallengeAttempt(ctx, token, challenge)
+}
+
+// countChallengeAttempt adds an attempt to the challenge and discards it once there were too many.
+// It returns false when the challenge was discarded.
+func (s *Service) countChallengeAttempt(ctx context.Context, token string, challenge *loginChallenge) bool {
+	logger := s.log.FromContext(ctx)
+
+	challenge.Attempts++
+	if challenge.Attempts >= maxChallengeAttempts {
+		if err := s.cache.Delete(ctx, challengeKey(token)); err != nil {
+			logger.Warn("Failed to delete login challenge", "error", err)
+		}
+		return false
+	}
+
+	if err := s.saveChallenge(ctx, token, challenge); err != nil {
+		logger.Warn("Failed to update login challenge", "error", err)
+	}
+	return true
+}
+
+func (s *Service) get(ctx context.Context, userID int64) (*userTwoFactor, error) {
+	if !s.cfg.TwoFactorEnabled {
+		return nil, twofactor.ErrDisabled.Errorf("two-factor authentication is disabled")
+	}
+
+	tf, err := s.store.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, errNotFound) {
+			return nil, twofactor.ErrNotEnrolled.Errorf("user %d is not enrolled", userID)
+		}
+		return nil, err
+	}
+	return tf, nil
+}
+
+func (s *Service) getEnabled(ctx context.Context, userID int64) (*userTwoFactor, error) {
+	tf, err := s.get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !tf.Enabled {
+		return nil, twofactor.ErrNotEnrolled.Errorf("user %d did not confirm the enrollment", userID)
+	}
+	return tf, nil
+}
+
+func (s *Service) verifyTOTP(ctx context.Context, tf *userTwoFactor, code string) error {
+	encrypted, err := base64.StdEncoding.DecodeString(tf.Secret)
+	if err != nil {
+		return err
+	}
+	secret, err := s.secretsService.Decrypt(ctx, encrypted)
+	if err != nil {
+		return err
+	}
+
+	step, ok := validateCode(string(secret), code, s.now(), tf.LastUsedStep)
+	if !ok {
+		return twofactor.ErrInvalidCode.Errorf("invalid code for user %d", tf.UserID)
+	}
+
+	// the step is only recorded when no concurrent request used it first, so a code can be used only once
+	used, err := s.store.UseStep(ctx, tf.UserID, step)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return twofactor.ErrInvalidCode.Errorf("code already used for user %d", tf.UserID)
+	}
+	return nil
+}
+
+func (s *Service) isRequired(ctx context.Context, userID int64) (bool, error) {
+	if !s.cfg.TwoFactorEnforceForAdmins {
+		return false, nil
+	}
+
+	usr, err := s.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: userID})
+	if err != nil {
+		return false, err
+	}
+	if usr.IsAdmin {
+		return true, nil
+	}
+
+	orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: userID})
+	if err != nil {
+		return false, err
+	}
+	for _, o := range orgs {
+		if o.Role == org.RoleAdmin {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Service) getChallenge(ctx context.Context, token string) (*loginChallenge, error) {
+	if token == "" {
+		return nil, twofactor.ErrChallengeNotFound.Errorf("missing login challenge")
+	}
+
+	data, err := s.cache.Get(ctx, challengeKey(token))
+	if err != nil {
+		if errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			return nil, twofactor.ErrChallengeNotFound.Errorf("login challenge not found")
+		}
+		return nil, err
+	}
+
+	var challenge loginChallenge
+	if err := json.Unmarshal(data, &challenge); err != nil {
+		return nil, err
+	}
+	if !s.now().Before(challenge.Expires) {
+		return nil, twofactor.ErrChallengeNotFound.Errorf("login challenge expired")
+	}
+	return &challenge, nil
+}
+
+func (s *Service) saveChallenge(ctx context.Context, token string, challenge *loginChallenge) error {
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+	return s.cache.Set(ctx, challengeKey(token), data, challenge.Expires.Sub(s.now()))
+}
+
+// challengeKey hashes the token so that the cache does not hold anything that can be used to log in
+func challengeKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return challengeKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+func generateChallengeToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+func hashRecoveryCodes(codes []string) []string {
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return hashes
+}
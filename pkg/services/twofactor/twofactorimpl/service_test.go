@@ -0,0 +1,246 @@
+package twofactorimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/services/twofactor"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationTwoFactor(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	const userID int64 = 1
+
+	t.Run("should enroll, confirm and verify codes", func(t *testing.T) {
+		s, now := setupTestService(t, func(cfg *setting.Cfg) {})
+
+		status, err := s.GetStatus(ctx, userID)
+		require.NoError(t, err)
+		assert.False(t, status.Enabled)
+
+		enrollment, err := s.Enroll(ctx, userID)
+		require.NoError(t, err)
+		assert.Contains(t, enrollment.URL, "otpauth://totp/Grafana:admin?")
+		assert.Len(t, enrollment.RecoveryCodes, recoveryCodeCount)
+
+		err = s.Verify(ctx, userID, codeAt(t, enrollment.Secret, *now))
+		assert.ErrorIs(t, err, twofactor.ErrNotEnrolled)
+
+		err = s.ConfirmEnrollment(ctx, userID, "000000")
+		assert.ErrorIs(t, err, twofactor.ErrInvalidCode)
+
+		require.NoError(t, s.ConfirmEnrollment(ctx, userID, codeAt(t, enrollment.Secret, *now)))
+
+		status, err = s.GetStatus(ctx, userID)
+		require.NoError(t, err)
+		assert.True(t, status.Enabled)
+		assert.Equal(t, int64(recoveryCodeCount), status.RecoveryCodesRemaining)
+
+		_, err = s.Enroll(ctx, userID)
+		assert.ErrorIs(t, err, twofactor.ErrAlreadyEnabled)
+
+		// the code used to confirm the enrollment can not be replayed
+		err = s.Verify(ctx, userID, codeAt(t, enrollment.Secret, *now))
+		assert.ErrorIs(t, err, twofactor.ErrInvalidCode)
+
+		*now = now.Add(totpPeriod)
+		require.NoError(t, s.Verify(ctx, userID, codeAt(t, enrollment.Secret, *now)))
+
+		require.NoError(t, s.Verify(ctx, userID, enrollment.RecoveryCodes[0]))
+		err = s.Verify(ctx, userID, enrollment.RecoveryCodes[0])
+		assert.ErrorIs(t, err, twofactor.ErrInvalidCode)
+
+		status, err = s.GetStatus(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(recoveryCodeCount-1), status.RecoveryCodesRemaining)
+
+		codes, err := s.RegenerateRecoveryCodes(ctx, userID)
+		require.NoError(t, err)
+		assert.ErrorIs(t, s.Verify(ctx, userID, enrollment.RecoveryCodes[1]), twofactor.ErrInvalidCode)
+		require.NoError(t, s.Verify(ctx, userID, codes[0]))
+
+		require.NoError(t, s.Disable(ctx, userID))
+		status, err = s.GetStatus(ctx, userID)
+		require.NoError(t, err)
+		assert.False(t, status.Enabled)
+	})
+
+	t.Run("should not challenge users without two-factor authentication", func(t *testing.T) {
+		s, _ := setupTestService(t, func(cfg *setting.Cfg) {})
+
+		challenge, err := s.StartLogin(ctx, userID)
+		require.NoError(t, err)
+		assert.Nil(t, challenge)
+	})
+
+	t.Run("should complete login with a valid code", func(t *testing.T) {
+		s, now := setupTestService(t, func(cfg *setting.Cfg) {})
+		enrollment := enroll(t, s, userID, *now)
+		*now = now.Add(totpPeriod)
+
+		challenge, err := s.StartLogin(ctx, userID)
+		require.NoError(t, err)
+		require.NotNil(t, challenge)
+		assert.False(t, challenge.Enroll)
+
+		_, err = s.CompleteLogin(ctx, challenge.Token, "000000", "127.0.0.1")
+		assert.ErrorIs(t, err, twofactor.ErrInvalidCode)
+
+		id, err := s.CompleteLogin(ctx, challenge.Token, codeAt(t, enrollment.Secret, *now), "127.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, userID, id)
+
+		// challenges are single use
+		_, err = s.CompleteLogin(ctx, challenge.Token, enrollment.RecoveryCodes[0], "127.0.0.1")
+		assert.ErrorIs(t, err, twofactor.ErrChallengeNotFound)
+	})
+
+	t.Run("should discard challenges after too many invalid codes", func(t *testing.T) {
+		s, now := setupTestService(t, func(cfg *setting.Cfg) {})
+		enrollment := enroll(t, s, userID, *now)
+
+		challenge, err := s.StartLogin(ctx, userID)
+		require.NoError(t, err)
+
+		for i := 0; i < maxChallengeAttempts; i++ {
+			_, err = s.CompleteLogin(ctx, challenge.Token, "000000", "127.0.0.1")
+			assert.ErrorIs(t, err, twofactor.ErrInvalidCode)
+		}
+
+		_, err = s.CompleteLogin(ctx, challenge.Token, enrollment.RecoveryCodes[0], "127.0.0.1")
+		assert.ErrorIs(t, err, twofactor.ErrChallengeNotFound)
+	})
+
+	t.Run("should discard challenges after too many enrollments", func(t *testing.T) {
+		s, _ := setupTestService(t, func(cfg *setting.Cfg) { cfg.TwoFactorEnforceForAdmins = true })
+		s.orgService = &orgtest.FakeOrgService{ExpectedUserOrgDTO: []*org.UserOrgDTO{{OrgID: 1, Role: org.RoleAdmin}}}
+
+		challenge, err := s.StartLogin(ctx, userID)
+		require.NoError(t, err)
+
+		for i := 0; i < maxChallengeAttempts-1; i++ {
+			_, err = s.EnrollLogin(ctx, challenge.Token)
+			require.NoError(t, err)
+		}
+
+		_, err = s.EnrollLogin(ctx, challenge.Token)
+		assert.ErrorIs(t, err, twofactor.ErrChallengeNotFound)
+		_, err = s.EnrollLogin(ctx, challenge.Token)
+		assert.ErrorIs(t, err, twofactor.ErrChallengeNotFound)
+	})
+
+	t.Run("should discard expired challenges", func(t *testing.T) {
+		s, now := setupTestService(t, func(cfg *setting.Cfg) {})
+		enrollment := enroll(t, s, userID, *now)
+
+		challenge, err := s.StartLogin(ctx, userID)
+		require.NoError(t, err)
+
+		*now = now.Add(twofactor.ChallengeTTL)
+		_, err = s.CompleteLogin(ctx, challenge.Token, enrollment.RecoveryCodes[0], "127.0.0.1")
+		assert.ErrorIs(t, err, twofactor.ErrChallengeNotFound)
+	})
+
+	t.Run("should require enrollment of org admins when enforced", func(t *testing.T) {
+		s, now := setupTestService(t, func(cfg *setting.Cfg) { cfg.TwoFactorEnforceForAdmins = true })
+		s.orgService = &orgtest.FakeOrgService{ExpectedUserOrgDTO: []*org.UserOrgDTO{{OrgID: 1, Role: org.RoleAdmin}}}
+
+		status, err := s.GetStatus(ctx, userID)
+		require.NoError(t, err)
+		assert.True(t, status.Required)
+
+		challenge, err := s.StartLogin(ctx, userID)
+		require.NoError(t, err)
+		require.NotNil(t, challenge)
+		assert.True(t, challenge.Enroll)
+
+		enrollment, err := s.EnrollLogin(ctx, challenge.Token)
+		require.NoError(t, err)
+
+		id, err := s.CompleteLogin(ctx, challenge.Token, codeAt(t, enrollment.Secret, *now), "127.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, userID, id)
+
+		status, err = s.GetStatus(ctx, userID)
+		require.NoError(t, err)
+		assert.True(t, status.Enabled)
+	})
+
+	t.Run("should not require enrollment of viewers when enforced", func(t *testing.T) {
+		s, _ := setupTestService(t, func(cfg *setting.Cfg) { cfg.TwoFactorEnforceForAdmins = true })
+		s.orgService = &orgtest.FakeOrgService{ExpectedUserOrgDTO: []*org.UserOrgDTO{{OrgID: 1, Role: org.RoleViewer}}}
+
+		challenge, err := s.StartLogin(ctx, userID)
+		require.NoError(t, err)
+		assert.Nil(t, challenge)
+	})
+
+	t.Run("should do nothing when disabled", func(t *testing.T) {
+		s, _ := setupTestService(t, func(cfg *setting.Cfg) {
+			cfg.TwoFactorEnabled = false
+			cfg.TwoFactorEnforceForAdmins = true
+		})
+
+		_, err := s.Enroll(ctx, userID)
+		assert.ErrorIs(t, err, twofactor.ErrDisabled)
+
+		challenge, err := s.StartLogin(ctx, userID)
+		require.NoError(t, err)
+		assert.Nil(t, challenge)
+	})
+}
+
+func setupTestService(t *testing.T, configure func(cfg *setting.Cfg)) (*Service, *time.Time) {
+	t.Helper()
+
+	cfg := setting.NewCfg()
+	cfg.TwoFactorEnabled = true
+	cfg.TwoFactorIssuer = "Grafana"
+	configure(cfg)
+
+	now := time.Unix(1700000000, 0)
+	clock := func() time.Time { return now }
+
+	s := ProvideService(
+		db.InitTestDB(t), cfg, fakes.NewFakeSecretsService(), remotecache.NewFakeStore(t),
+		&usertest.FakeUserService{ExpectedUser: &user.User{ID: 1, Login: "admin"}},
+		orgtest.NewOrgServiceFake(), loginattempttest.FakeLoginAttemptService{},
+	)
+	s.now = clock
+	s.store.(*xormStore).now = clock
+	return s, &now
+}
+
+func enroll(t *testing.T, s *Service, userID int64, now time.Time) *twofactor.Enrollment {
+	t.Helper()
+
+	enrollment, err := s.Enroll(context.Background(), userID)
+	require.NoError(t, err)
+	require.NoError(t, s.ConfirmEnrollment(context.Background(), userID, codeAt(t, enrollment.Secret, now)))
+	return enrollment
+}
+
+func codeAt(t *testing.T, secret string, now time.Time) string {
+	t.Helper()
+
+	key, err := base32NoPadding.DecodeString(secret)
+	require.NoError(t, err)
+	return totpCode(key, timeStep(now))
+}
@@ -0,0 +1,173 @@
+package twofactorimpl
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+var errNotFound = errors.New("two-factor authentication not found")
+
+type userTwoFactor struct {
+	ID     int64 `xorm:"pk autoincr 'id'"`
+	UserID int64 `xorm:"user_id"`
+	// Secret is the encrypted and base64 encoded TOTP secret
+	Secret       string `xorm:"secret"`
+	Enabled      bool   `xorm:"enabled"`
+	LastUsedStep int64  `xorm:"last_used_step"`
+	Created      time.Time
+	Updated      time.Time
+}
+
+func (userTwoFactor) TableName() string { return "user_two_factor" }
+
+type recoveryCode struct {
+	ID       int64  `xorm:"pk autoincr 'id'"`
+	UserID   int64  `xorm:"user_id"`
+	CodeHash string `xorm:"code_hash"`
+	Created  time.Time
+}
+
+func (recoveryCode) TableName() string { return "user_two_factor_recovery_code" }
+
+type store interface {
+	Get(ctx context.Context, userID int64) (*userTwoFactor, error)
+	// Save replaces the secret and the recovery codes of a user with a new, disabled, enrollment
+	Save(ctx context.Context, userID int64, secret string, codeHashes []string) error
+	Enable(ctx context.Context, userID int64) error
+	Delete(ctx context.Context, userID int64) error
+	// UseStep records the time step of a valid code, it returns false when the step or a later one was already used
+	UseStep(ctx context.Context, userID, step int64) (bool, error)
+	// UseRecoveryCode deletes a recovery code, it returns false when the code does not exist
+	UseRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID int64, codeHashes []string) error
+	CountRecoveryCodes(ctx context.Context, userID int64) (int64, error)
+}
+
+type xormStore struct {
+	db  db.DB
+	now func() time.Time
+}
+
+func (s *xormStore) Get(ctx context.Context, userID int64) (*userTwoFactor, error) {
+	var result userTwoFactor
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("user_id = ?", userID).Get(&result)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return errNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (s *xormStore) Save(ctx context.Context, userID int64, secret string, codeHashes []string) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM user_two_factor WHERE user_id = ?", userID); err != nil {
+			return err
+		}
+
+		now := s.now()
+		if _, err := sess.Insert(&userTwoFactor{
+			UserID:  userID,
+			Secret:  secret,
+			Created: now,
+			Updated: now,
+		}); err != nil {
+			return err
+		}
+
+		return replaceRecoveryCodes(sess, userID, codeHashes, now)
+	})
+}
+
+func (s *xormStore) Enable(ctx context.Context, userID int64) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("user_id = ?", userID).Cols("enabled", "updated").Update(&userTwoFactor{Enabled: true, Updated: s.now()})
+		return err
+	})
+}
+
+func (s *xormStore) Delete(ctx context.Context, userID int64) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM user_two_factor WHERE user_id = ?", userID); err != nil {
+			return err
+		}
+		_, err := sess.Exec("DELETE FROM user_two_factor_recovery_code WHERE user_id = ?", userID)
+		return err
+	})
+}
+
+func (s *xormStore) UseStep(ctx context.Context, userID, step int64) (bool, error) {
+	var used bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE user_two_factor SET last_used_step = ?, updated = ? WHERE user_id = ? AND last_used_step < ?", step, s.now(), userID, step)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		used = affected == 1
+		return nil
+	})
+	return used, err
+}
+
+func (s *xormStore) UseRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error) {
+	var used bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM user_two_factor_recovery_code WHERE user_id = ? AND code_hash = ?", userID, codeHash)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		used = affected == 1
+		return nil
+	})
+	return used, err
+}
+
+func (s *xormStore) ReplaceRecoveryCodes(ctx context.Context, userID int64, codeHashes []string) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		return replaceRecoveryCodes(sess, userID, codeHashes, s.now())
+	})
+}
+
+func (s *xormStore) CountRecoveryCodes(ctx context.Context, userID int64) (int64, error) {
+	var count int64
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		count, err = sess.Where("user_id = ?", userID).Count(&recoveryCode{})
+		return err
+	})
+	return count, err
+}
+
+func replaceRecoveryCodes(sess *db.Session, userID int64, codeHashes []string, now time.Time) error {
+	if _, err := sess.Exec("DELETE FROM user_two_factor_recovery_code WHERE user_id = ?", userID); err != nil {
+		return err
+	}
+
+	codes := make([]*recoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, &recoveryCode{UserID: userID, CodeHash: hash, Created: now})
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	_, err := sess.InsertMulti(codes)
+	return err
+}
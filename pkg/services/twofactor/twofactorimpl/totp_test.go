@@ -0,0 +1,75 @@
+package twofactorimpl
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTPCode(t *testing.T) {
+	// test vectors from RFC 6238 appendix B, truncated to 6 digits
+	secret := []byte("12345678901234567890")
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1111111111, code: "050471"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.code, totpCode(secret, timeStep(time.Unix(tt.unix, 0))))
+	}
+}
+
+func TestValidateCode(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1111111111, 0)
+	current := timeStep(now)
+
+	t.Run("should accept codes of the current and adjacent steps", func(t *testing.T) {
+		step, ok := validateCode(secret, "050471", now, 0)
+		require.True(t, ok)
+		require.Equal(t, current, step)
+
+		_, ok = validateCode(secret, "050471", now.Add(totpPeriod), 0)
+		require.True(t, ok)
+
+		_, ok = validateCode(secret, "050471", now.Add(3*totpPeriod), 0)
+		require.False(t, ok)
+	})
+
+	t.Run("should reject used steps", func(t *testing.T) {
+		_, ok := validateCode(secret, "050471", now, current)
+		require.False(t, ok)
+	})
+
+	t.Run("should reject malformed codes", func(t *testing.T) {
+		_, ok := validateCode(secret, "05047", now, 0)
+		require.False(t, ok)
+		_, ok = validateCode(secret, "", now, 0)
+		require.False(t, ok)
+	})
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := generateRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, recoveryCodeCount)
+	require.Len(t, codes[0], recoveryCodeSize+1)
+	require.NotEqual(t, codes[0], codes[1])
+
+	require.Equal(t, hashRecoveryCode("abcde-fghij"), hashRecoveryCode(" ABCDEFGHIJ"))
+	require.False(t, looksLikeTOTPCode(codes[0]))
+	require.True(t, looksLikeTOTPCode("123456"))
+}
+
+func TestKeyURI(t *testing.T) {
+	require.Equal(t,
+		"otpauth://totp/Grafana:admin@localhost?algorithm=SHA1&digits=6&issuer=Grafana&period=30&secret=ABC",
+		keyURI("Grafana", "admin@localhost", "ABC"))
+}
@@ -0,0 +1,57 @@
+package twofactortest
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/twofactor"
+)
+
+var _ twofactor.Service = new(FakeService)
+
+type FakeService struct {
+	ExpectedStatus        *twofactor.Status
+	ExpectedEnrollment    *twofactor.Enrollment
+	ExpectedRecoveryCodes []string
+	ExpectedChallenge     *twofactor.LoginChallenge
+	ExpectedUserID        int64
+	ExpectedErr           error
+}
+
+func (f *FakeService) GetStatus(ctx context.Context, userID int64) (*twofactor.Status, error) {
+	if f.ExpectedStatus == nil {
+		return &twofactor.Status{}, f.ExpectedErr
+	}
+	return f.ExpectedStatus, f.ExpectedErr
+}
+
+func (f *FakeService) Enroll(ctx context.Context, userID int64) (*twofactor.Enrollment, error) {
+	return f.ExpectedEnrollment, f.ExpectedErr
+}
+
+func (f *FakeService) ConfirmEnrollment(ctx context.Context, userID int64, code string) error {
+	return f.ExpectedErr
+}
+
+func (f *FakeService) Disable(ctx context.Context, userID int64) error {
+	return f.ExpectedErr
+}
+
+func (f *FakeService) RegenerateRecoveryCodes(ctx context.Context, userID int64) ([]string, error) {
+	return f.ExpectedRecoveryCodes, f.ExpectedErr
+}
+
+func (f *FakeService) Verify(ctx context.Context, userID int64, code string) error {
+	return f.ExpectedErr
+}
+
+func (f *FakeService) StartLogin(ctx context.Context, userID int64) (*twofactor.LoginChallenge, error) {
+	return f.ExpectedChallenge, f.ExpectedErr
+}
+
+func (f *FakeService) EnrollLogin(ctx context.Context, token string) (*twofactor.Enrollment, error) {
+	return f.ExpectedEnrollment, f.ExpectedErr
+}
+
+func (f *FakeService) CompleteLogin(ctx context.Context, token, code, ipAddress string) (int64, error) {
+	return f.ExpectedUserID, f.ExpectedErr
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/models/roletype"
+	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 type HelpFlags1 uint64
@@ -29,6 +30,21 @@ var (
 	ErrNoUniqueID        = errors.New("identifying id not found")
 )
 
+// Password policy errors
+var (
+	ErrPasswordTooShort = errutil.NewBase(errutil.StatusBadRequest, "user.password.tooShort").MustTemplate(
+		"password is shorter than {{ .Public.minLength }} characters",
+		errutil.WithPublic("Password must be at least {{ .Public.minLength }} characters long"),
+	)
+	ErrPasswordMissingCharacters = errutil.NewBase(errutil.StatusBadRequest, "user.password.missingCharacters").MustTemplate(
+		"password is missing {{ .Public.classes }}",
+		errutil.WithPublic("Password must contain {{ .Public.classes }}"),
+	)
+	ErrPasswordBanned  = errutil.NewBase(errutil.StatusBadRequest, "user.password.banned", errutil.WithPublicMessage("Password is too common, choose a different one"))
+	ErrPasswordReused  = errutil.NewBase(errutil.StatusBadRequest, "user.password.reused", errutil.WithPublicMessage("Password has been used recently, choose a different one"))
+	ErrPasswordExpired = errutil.NewBase(errutil.StatusUnauthorized, "user.password.expired", errutil.WithPublicMessage("Password has expired, reset it or contact your administrator"))
+)
+
 type User struct {
 	ID            int64 `xorm:"pk autoincr 'id'"`
 	Version       int
@@ -220,6 +236,12 @@ type SignedInUser struct {
 	Analytics          AnalyticsSettings
 	// Permissions grouped by orgID and actions
 	Permissions map[int64]map[string][]string `json:"-"`
+	// TokenPermissions restricts the permissions of a service account authenticated
+	// with a scoped token, grouped by action. Nil when the token is not restricted
+	TokenPermissions map[string][]string `json:"-"`
+	// TokenOrgRole is the role of a service account authenticated with a scoped token,
+	// which the permissions restricted by the token are computed from
+	TokenOrgRole roletype.RoleType `json:"-"`
 }
 
 func (u *User) NameOrFallback() string {
@@ -284,6 +306,19 @@ func (u *SignedInUser) HasRole(role roletype.RoleType) bool {
 	return u.OrgRole.Includes(role)
 }
 
+// RestrictToToken restricts a service account authenticated with a scoped token to the
+// permissions of the token. The role is lowered to Viewer so checks of the role don't grant
+// more than the token does, and kept in TokenOrgRole to compute the service account permissions.
+// Tokens without permissions are not restricted.
+func (u *SignedInUser) RestrictToToken(permissions map[string][]string) {
+	if permissions == nil {
+		return
+	}
+	u.TokenPermissions = permissions
+	u.TokenOrgRole = u.OrgRole
+	u.OrgRole = roletype.RoleViewer
+}
+
 // IsRealUser returns true if the user is a real user and not a service account
 func (u *SignedInUser) IsRealUser() bool {
 	// backwards compatibility
@@ -374,9 +409,3 @@ type AdminCreateUserResponse struct {
 	ID      int64  `json:"id"`
 	Message string `json:"message"`
 }
-
-type Password string
-
-func (p Password) IsWeak() bool {
-	return len(p) <= 4
-}
@@ -12,7 +12,13 @@ type Service interface {
 	GetByLogin(context.Context, *GetUserByLoginQuery) (*User, error)
 	GetByEmail(context.Context, *GetUserByEmailQuery) (*User, error)
 	Update(context.Context, *UpdateUserCommand) error
+	// ValidatePassword checks a plaintext password chosen by a user against the password policy.
+	// Create doesn't apply the policy, so callers creating users with such a password must call it first.
+	ValidatePassword(password string) error
+	// ChangePassword validates the plaintext NewPassword against the password policy and stores its hash.
 	ChangePassword(context.Context, *ChangeUserPasswordCommand) error
+	// IsPasswordExpired returns true if the password of the user is older than the configured maximum age.
+	IsPasswordExpired(context.Context, *User) (bool, error)
 	UpdateLastSeenAt(context.Context, *UpdateUserLastSeenAtCommand) error
 	SetUsingOrg(context.Context, *SetUsingOrgCommand) error
 	GetSignedInUserWithCacheCtx(context.Context, *GetSignedInUserQuery) (*SignedInUser, error)
@@ -0,0 +1,193 @@
+package userimpl
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// passwordHistory is a previously used password hash of a user.
+type passwordHistory struct {
+	ID       int64 `xorm:"pk autoincr 'id'"`
+	UserID   int64 `xorm:"user_id"`
+	Password string
+	Created  time.Time
+}
+
+func (passwordHistory) TableName() string {
+	return "user_password_history"
+}
+
+// passwordPolicy is the password policy configured in [auth.password_policy].
+// The zero value accepts any password.
+type passwordPolicy struct {
+	minLength        int
+	requireUppercase bool
+	requireLowercase bool
+	requireDigit     bool
+	requireSymbol    bool
+	banned           map[string]struct{}
+	historyCount     int
+	maxAge           time.Duration
+}
+
+func newPasswordPolicy(cfg *setting.Cfg) (passwordPolicy, error) {
+	p := passwordPolicy{
+		minLength:        cfg.PasswordPolicyMinLength,
+		requireUppercase: cfg.PasswordPolicyRequireUppercase,
+		requireLowercase: cfg.PasswordPolicyRequireLowercase,
+		requireDigit:     cfg.PasswordPolicyRequireDigit,
+		requireSymbol:    cfg.PasswordPolicyRequireSymbol,
+		historyCount:     cfg.PasswordPolicyHistoryCount,
+		maxAge:           cfg.PasswordPolicyMaxAge,
+	}
+
+	if cfg.PasswordPolicyBannedPasswordsFile != "" {
+		banned, err := readBannedPasswords(cfg.PasswordPolicyBannedPasswordsFile)
+		if err != nil {
+			return p, err
+		}
+		p.banned = banned
+	}
+
+	return p, nil
+}
+
+// readBannedPasswords reads a file with one banned password per line.
+// Empty lines and lines starting with # are ignored.
+func readBannedPasswords(path string) (map[string]struct{}, error) {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path comes from the configuration file.
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read banned passwords file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	banned := map[string]struct{}{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		banned[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read banned passwords file: %w", err)
+	}
+
+	return banned, nil
+}
+
+// validate checks a plaintext password against the length, character class
+// and banned password rules of the policy.
+func (p passwordPolicy) validate(password string) error {
+	if utf8.RuneCountInString(password) < p.minLength {
+		return user.ErrPasswordTooShort.Build(errutil.TemplateData{
+			Public: map[string]any{"minLength": p.minLength},
+		})
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	var missing []string
+	if p.requireUppercase && !hasUpper {
+		missing = append(missing, "an uppercase letter")
+	}
+	if p.requireLowercase && !hasLower {
+		missing = append(missing, "a lowercase letter")
+	}
+	if p.requireDigit && !hasDigit {
+		missing = append(missing, "a digit")
+	}
+	if p.requireSymbol && !hasSymbol {
+		missing = append(missing, "a symbol")
+	}
+	if len(missing) > 0 {
+		return user.ErrPasswordMissingCharacters.Build(errutil.TemplateData{
+			Public: map[string]any{"classes": strings.Join(missing, ", ")},
+		})
+	}
+
+	if _, ok := p.banned[strings.ToLower(password)]; ok {
+		return user.ErrPasswordBanned.Errorf("password is in the banned password list")
+	}
+
+	return nil
+}
+
+// historySize is the number of password hashes kept per user. The latest
+// password is always kept since it is used to compute the password age.
+func (p passwordPolicy) historySize() int {
+	if p.historyCount > 1 {
+		return p.historyCount
+	}
+	return 1
+}
+
+// checkPasswordHistory returns ErrPasswordReused if the hashed password is the
+// current password of the user or one of the last history_count passwords.
+func (s *Service) checkPasswordHistory(ctx context.Context, usr *user.User, hashed string) error {
+	if s.passwordPolicy.historyCount <= 0 {
+		return nil
+	}
+
+	if usr.Password == hashed {
+		return user.ErrPasswordReused.Errorf("password is the current password")
+	}
+
+	history, err := s.store.GetPasswordHistory(ctx, usr.ID, s.passwordPolicy.historyCount)
+	if err != nil {
+		return err
+	}
+	for _, h := range history {
+		if h.Password == hashed {
+			return user.ErrPasswordReused.Errorf("password is one of the last %d passwords", s.passwordPolicy.historyCount)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) ValidatePassword(password string) error {
+	return s.passwordPolicy.validate(password)
+}
+
+func (s *Service) IsPasswordExpired(ctx context.Context, usr *user.User) (bool, error) {
+	if s.passwordPolicy.maxAge <= 0 || usr.Password == "" {
+		return false, nil
+	}
+
+	changed := usr.Created
+	history, err := s.store.GetPasswordHistory(ctx, usr.ID, 1)
+	if err != nil {
+		return false, err
+	}
+	if len(history) > 0 {
+		changed = history[0].Created
+	}
+
+	return timeNow().After(changed.Add(s.passwordPolicy.maxAge)), nil
+}
@@ -0,0 +1,140 @@
+package userimpl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	bannedFile := filepath.Join(t.TempDir(), "banned.txt")
+	require.NoError(t, os.WriteFile(bannedFile, []byte("# common passwords\nPassword1!\n\nletmein\n"), 0600))
+
+	cfg := setting.NewCfg()
+	cfg.PasswordPolicyMinLength = 8
+	cfg.PasswordPolicyRequireUppercase = true
+	cfg.PasswordPolicyRequireDigit = true
+	cfg.PasswordPolicyRequireSymbol = true
+	cfg.PasswordPolicyBannedPasswordsFile = bannedFile
+	policy, err := newPasswordPolicy(cfg)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		password    string
+		expectedErr error
+	}{
+		{name: "valid password", password: "Corr3ct-horse"},
+		{name: "too short", password: "Ab1!", expectedErr: user.ErrPasswordTooShort},
+		{name: "length counts characters, not bytes", password: "Ünï1!çø", expectedErr: user.ErrPasswordTooShort},
+		{name: "missing uppercase", password: "corr3ct-horse", expectedErr: user.ErrPasswordMissingCharacters},
+		{name: "missing digit", password: "Correct-horse", expectedErr: user.ErrPasswordMissingCharacters},
+		{name: "missing symbol", password: "Corr3cthorse", expectedErr: user.ErrPasswordMissingCharacters},
+		{name: "banned password is matched case insensitively", password: "PASSWORD1!", expectedErr: user.ErrPasswordBanned},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.validate(tt.password)
+			if tt.expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+
+	t.Run("zero value policy accepts any password", func(t *testing.T) {
+		require.NoError(t, passwordPolicy{}.validate(""))
+	})
+
+	t.Run("missing banned passwords file fails", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.PasswordPolicyBannedPasswordsFile = filepath.Join(t.TempDir(), "missing.txt")
+		_, err := newPasswordPolicy(cfg)
+		require.Error(t, err)
+	})
+}
+
+func TestService_ValidatePassword(t *testing.T) {
+	userService := Service{passwordPolicy: passwordPolicy{minLength: 5}}
+
+	assert.ErrorIs(t, userService.ValidatePassword("new"), user.ErrPasswordTooShort)
+	assert.NoError(t, userService.ValidatePassword("brand-new"))
+}
+
+func TestService_ChangePassword(t *testing.T) {
+	userStore := newUserStoreFake()
+	userService := Service{
+		store:          userStore,
+		cfg:            setting.NewCfg(),
+		passwordPolicy: passwordPolicy{minLength: 5, historyCount: 2},
+	}
+
+	current, err := util.EncodePassword("current", "salt")
+	require.NoError(t, err)
+	previous, err := util.EncodePassword("previous", "salt")
+	require.NoError(t, err)
+	userStore.ExpectedUser = &user.User{ID: 1, Salt: "salt", Password: current}
+	userStore.ExpectedPasswordHistory = []*passwordHistory{{UserID: 1, Password: current}, {UserID: 1, Password: previous}}
+
+	t.Run("should reject a password violating the policy", func(t *testing.T) {
+		err := userService.ChangePassword(context.Background(), &user.ChangeUserPasswordCommand{UserID: 1, NewPassword: "new"})
+		assert.ErrorIs(t, err, user.ErrPasswordTooShort)
+	})
+
+	t.Run("should reject the current password", func(t *testing.T) {
+		err := userService.ChangePassword(context.Background(), &user.ChangeUserPasswordCommand{UserID: 1, NewPassword: "current"})
+		assert.ErrorIs(t, err, user.ErrPasswordReused)
+	})
+
+	t.Run("should reject a recently used password", func(t *testing.T) {
+		err := userService.ChangePassword(context.Background(), &user.ChangeUserPasswordCommand{UserID: 1, NewPassword: "previous"})
+		assert.ErrorIs(t, err, user.ErrPasswordReused)
+	})
+
+	t.Run("should accept a new password", func(t *testing.T) {
+		err := userService.ChangePassword(context.Background(), &user.ChangeUserPasswordCommand{UserID: 1, NewPassword: "brand-new"})
+		require.NoError(t, err)
+	})
+}
+
+func TestService_IsPasswordExpired(t *testing.T) {
+	userStore := newUserStoreFake()
+	userService := Service{
+		store:          userStore,
+		cfg:            setting.NewCfg(),
+		passwordPolicy: passwordPolicy{maxAge: 24 * time.Hour},
+	}
+	usr := &user.User{ID: 1, Password: "hashed", Created: time.Now().Add(-48 * time.Hour)}
+
+	t.Run("should fall back to the creation date without history", func(t *testing.T) {
+		expired, err := userService.IsPasswordExpired(context.Background(), usr)
+		require.NoError(t, err)
+		assert.True(t, expired)
+	})
+
+	t.Run("should use the latest password change", func(t *testing.T) {
+		userStore.ExpectedPasswordHistory = []*passwordHistory{{UserID: 1, Created: time.Now().Add(-time.Hour)}}
+		t.Cleanup(func() { userStore.ExpectedPasswordHistory = nil })
+
+		expired, err := userService.IsPasswordExpired(context.Background(), usr)
+		require.NoError(t, err)
+		assert.False(t, expired)
+	})
+
+	t.Run("should never expire users without a password", func(t *testing.T) {
+		expired, err := userService.IsPasswordExpired(context.Background(), &user.User{ID: 2, Created: usr.Created})
+		require.NoError(t, err)
+		assert.False(t, expired)
+	})
+}
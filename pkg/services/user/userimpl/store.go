@@ -38,6 +38,8 @@ type store interface {
 	BatchDisableUsers(context.Context, *user.BatchDisableUsersCommand) error
 	Disable(context.Context, *user.DisableUserCommand) error
 	Search(context.Context, *user.SearchUsersQuery) (*user.SearchUserQueryResult, error)
+	AddPasswordHistory(ctx context.Context, userID int64, hashed string, keep int) error
+	GetPasswordHistory(ctx context.Context, userID int64, limit int) ([]*passwordHistory, error)
 
 	Count(ctx context.Context) (int64, error)
 }
@@ -358,6 +360,37 @@ func (ss *sqlStore) ChangePassword(ctx context.Context, cmd *user.ChangeUserPass
 	})
 }
 
+// AddPasswordHistory records a password hash of the user and removes all but
+// the latest keep entries.
+func (ss *sqlStore) AddPasswordHistory(ctx context.Context, userID int64, hashed string, keep int) error {
+	return ss.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(&passwordHistory{UserID: userID, Password: hashed, Created: timeNow()}); err != nil {
+			return err
+		}
+
+		var ids []int64
+		if err := sess.Table("user_password_history").Cols("id").Where("user_id = ?", userID).
+			Desc("created").Desc("id").Find(&ids); err != nil {
+			return err
+		}
+		if len(ids) <= keep {
+			return nil
+		}
+
+		_, err := sess.In("id", ids[keep:]).Delete(&passwordHistory{})
+		return err
+	})
+}
+
+// GetPasswordHistory returns the latest password hashes of the user, newest first.
+func (ss *sqlStore) GetPasswordHistory(ctx context.Context, userID int64, limit int) ([]*passwordHistory, error) {
+	history := make([]*passwordHistory, 0)
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("user_id = ?", userID).Desc("created").Desc("id").Limit(limit).Find(&history)
+	})
+	return history, err
+}
+
 func (ss *sqlStore) UpdateLastSeenAt(ctx context.Context, cmd *user.UpdateUserLastSeenAtCommand) error {
 	return ss.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
 		user := user.User{
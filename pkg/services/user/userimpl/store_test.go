@@ -260,6 +260,19 @@ func TestIntegrationUserDataAccess(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("password history keeps the latest entries", func(t *testing.T) {
+		for _, hashed := range []string{"first", "second", "third"} {
+			err := userStore.AddPasswordHistory(context.Background(), 42, hashed, 2)
+			require.NoError(t, err)
+		}
+
+		history, err := userStore.GetPasswordHistory(context.Background(), 42, 10)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, "third", history[0].Password)
+		assert.Equal(t, "second", history[1].Password)
+	})
+
 	t.Run("update last seen at", func(t *testing.T) {
 		err := userStore.UpdateLastSeenAt(context.Background(), &user.UpdateUserLastSeenAtCommand{})
 		require.NoError(t, err)
@@ -30,6 +30,8 @@ type Service struct {
 	teamService  team.Service
 	cacheService *localcache.CacheService
 	cfg          *setting.Cfg
+
+	passwordPolicy passwordPolicy
 }
 
 func ProvideService(
@@ -50,6 +52,12 @@ func ProvideService(
 		cacheService: cacheService,
 	}
 
+	policy, err := newPasswordPolicy(cfg)
+	if err != nil {
+		return s, err
+	}
+	s.passwordPolicy = policy
+
 	defaultLimits, err := readQuotaConfig(cfg)
 	if err != nil {
 		return s, err
@@ -142,6 +150,12 @@ func (s *Service) Create(ctx context.Context, cmd *user.CreateUserCommand) (*use
 		return nil, err
 	}
 
+	if usr.Password != "" {
+		if err := s.store.AddPasswordHistory(ctx, usr.ID, usr.Password, s.passwordPolicy.historySize()); err != nil {
+			return nil, err
+		}
+	}
+
 	// create org user link
 	if !cmd.SkipOrgSetup {
 		orgUser := org.OrgUser{
@@ -207,7 +221,29 @@ func (s *Service) Update(ctx context.Context, cmd *user.UpdateUserCommand) error
 }
 
 func (s *Service) ChangePassword(ctx context.Context, cmd *user.ChangeUserPasswordCommand) error {
-	return s.store.ChangePassword(ctx, cmd)
+	usr, err := s.store.GetNotServiceAccount(ctx, cmd.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.passwordPolicy.validate(cmd.NewPassword); err != nil {
+		return err
+	}
+
+	hashed, err := util.EncodePassword(cmd.NewPassword, usr.Salt)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkPasswordHistory(ctx, usr, hashed); err != nil {
+		return err
+	}
+
+	if err := s.store.ChangePassword(ctx, &user.ChangeUserPasswordCommand{UserID: usr.ID, NewPassword: hashed}); err != nil {
+		return err
+	}
+
+	return s.store.AddPasswordHistory(ctx, usr.ID, hashed, s.passwordPolicy.historySize())
 }
 
 func (s *Service) UpdateLastSeenAt(ctx context.Context, cmd *user.UpdateUserLastSeenAtCommand) error {
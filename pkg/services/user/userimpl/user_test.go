@@ -199,6 +199,7 @@ type FakeUserStore struct {
 	ExpectedSearchUserQueryResult *user.SearchUserQueryResult
 	ExpectedError                 error
 	ExpectedDeleteUserError       error
+	ExpectedPasswordHistory       []*passwordHistory
 }
 
 func newUserStoreFake() *FakeUserStore {
@@ -285,6 +286,14 @@ func (f *FakeUserStore) Search(ctx context.Context, query *user.SearchUsersQuery
 	return f.ExpectedSearchUserQueryResult, f.ExpectedError
 }
 
+func (f *FakeUserStore) AddPasswordHistory(ctx context.Context, userID int64, hashed string, keep int) error {
+	return f.ExpectedError
+}
+
+func (f *FakeUserStore) GetPasswordHistory(ctx context.Context, userID int64, limit int) ([]*passwordHistory, error) {
+	return f.ExpectedPasswordHistory, f.ExpectedError
+}
+
 func (f *FakeUserStore) Count(ctx context.Context) (int64, error) {
 	return 0, nil
 }
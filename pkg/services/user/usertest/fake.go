@@ -14,6 +14,8 @@ type FakeUserService struct {
 	ExpectedSearchUsers      user.SearchUserQueryResult
 	ExpectedUserProfileDTO   *user.UserProfileDTO
 	ExpectedUserProfileDTOs  []*user.UserProfileDTO
+	ExpectedPasswordExpired  bool
+	ExpectedPasswordError    error
 
 	GetSignedInUserFn func(ctx context.Context, query *user.GetSignedInUserQuery) (*user.SignedInUser, error)
 	CreateFn          func(ctx context.Context, cmd *user.CreateUserCommand) (*user.User, error)
@@ -62,6 +64,14 @@ func (f *FakeUserService) ChangePassword(ctx context.Context, cmd *user.ChangeUs
 	return f.ExpectedError
 }
 
+func (f *FakeUserService) ValidatePassword(password string) error {
+	return f.ExpectedPasswordError
+}
+
+func (f *FakeUserService) IsPasswordExpired(ctx context.Context, usr *user.User) (bool, error) {
+	return f.ExpectedPasswordExpired, f.ExpectedError
+}
+
 func (f *FakeUserService) UpdateLastSeenAt(ctx context.Context, cmd *user.UpdateUserLastSeenAtCommand) error {
 	return f.ExpectedError
 }
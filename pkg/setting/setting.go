@@ -274,6 +274,37 @@ type Cfg struct {
 	IDResponseHeaderPrefix       string
 	IDResponseHeaderNamespaces   map[string]struct{}
 
+	// Session policies
+	// LoginMaxInactiveLifetimeByRole holds per org role overrides of LoginMaxInactiveLifetime
+	LoginMaxInactiveLifetimeByRole map[string]time.Duration
+	// LoginMaxConcurrentSessions is the number of sessions a user can have at once, 0 means unlimited
+	LoginMaxConcurrentSessions int
+
+	// Two-factor authentication
+	TwoFactorEnabled          bool
+	TwoFactorEnforceForAdmins bool
+	TwoFactorIssuer           string
+
+	// Password policy
+	PasswordPolicyMinLength           int
+	PasswordPolicyRequireUppercase    bool
+	PasswordPolicyRequireLowercase    bool
+	PasswordPolicyRequireDigit        bool
+	PasswordPolicyRequireSymbol       bool
+	PasswordPolicyBannedPasswordsFile string
+	PasswordPolicyHistoryCount        int
+	PasswordPolicyMaxAge              time.Duration
+
+	// Account lockout
+	// LoginLockoutThreshold is the number of failed logins after which the account is locked, 0 disables the lockout
+	LoginLockoutThreshold int
+	// LoginLockoutDuration is how long accounts stay locked, 0 means until an admin unlocks them
+	LoginLockoutDuration time.Duration
+
+	// SCIM provisioning
+	SCIMEnabled        bool
+	SCIMDefaultOrgRole string
+
 	// AWS Plugin Auth
 	AWSAllowedAuthProviders []string
 	AWSAssumeRoleEnabled    bool
@@ -314,6 +345,10 @@ type Cfg struct {
 	JWTAuthRoleAttributeStrict     bool
 	JWTAuthAllowAssignGrafanaAdmin bool
 	JWTAuthSkipOrgRoleSync         bool
+	JWTAuthOrgAttributePath        string
+	JWTAuthOrgMapping              []string
+	JWTAuthTokenExchangeEnabled    bool
+	JWTAuthIssuers                 []JWTIssuerSettings
 
 	// Dataproxy
 	SendUserHeader                 bool
@@ -356,6 +391,10 @@ type Cfg struct {
 
 	// Service Accounts
 	SATokenExpirationDayLimit int
+	// SATokenUnusedDayLimit is the number of days after which unused tokens are revoked, 0 disables it
+	SATokenUnusedDayLimit int
+	// SATokenTrustedProxies are the proxies whose forwarded headers are used to check the IP allow list of tokens
+	SATokenTrustedProxies []string
 
 	// Annotations
 	AnnotationCleanupJobBatchSize      int64
@@ -372,11 +411,18 @@ type Cfg struct {
 
 	// Data sources
 	DataSourceLimit int
+	// DataSourcePermissionsEnforced separates the query, explore and alerting
+	// permissions of data sources instead of granting all of them to viewers.
+	DataSourcePermissionsEnforced bool
 
 	// SQL Data sources
 	SqlDatasourceMaxOpenConnsDefault    int
 	SqlDatasourceMaxIdleConnsDefault    int
 	SqlDatasourceMaxConnLifetimeDefault int
+	// SqliteDatasourceAllowedPaths are the directories SQLite data sources can read database files from
+	SqliteDatasourceAllowedPaths []string
+	// GenericSqlDatasourceAllowedDrivers are the database/sql drivers generic SQL data sources can use
+	GenericSqlDatasourceAllowedDrivers []string
 
 	// Snapshots
 	SnapshotEnabled       bool
@@ -499,6 +545,14 @@ type Cfg struct {
 
 	Search SearchSettings
 
+	QueryCaching QueryCachingSettings
+
+	QueryConcurrency QueryConcurrencySettings
+
+	PublicDashboards PublicDashboardsSettings
+
+	Audit AuditSettings
+
 	SecureSocksDSProxy SecureSocksDSProxySettings
 
 	// SAML Auth
@@ -1128,7 +1182,17 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 
 	cfg.DashboardPreviews = readDashboardPreviewsSettings(iniFile)
 	cfg.Storage = readStorageSettings(iniFile)
-	cfg.Search = readSearchSettings(iniFile)
+	cfg.Search = readSearchSettings(iniFile, cfg.DataPath)
+	cfg.QueryCaching = readQueryCachingSettings(iniFile)
+	cfg.QueryConcurrency = readQueryConcurrencySettings(iniFile)
+
+	if cfg.PublicDashboards, err = readPublicDashboardsSettings(iniFile); err != nil {
+		return err
+	}
+
+	if cfg.Audit, err = readAuditSettings(iniFile); err != nil {
+		return err
+	}
 
 	cfg.SecureSocksDSProxy, err = readSecureSocksDSProxySettings(iniFile)
 	if err != nil {
@@ -1200,6 +1264,47 @@ func valueAsString(section *ini.Section, keyName string, defaultValue string) st
 	return section.Key(keyName).MustString(defaultValue)
 }
 
+// JWTIssuerSettings is a trusted JWT issuer configured in an [auth.jwt.issuer.<name>] section.
+type JWTIssuerSettings struct {
+	Name              string
+	Issuer            string
+	KeyFile           string
+	JWKSetFile        string
+	JWKSetURL         string
+	CacheTTL          time.Duration
+	ExpectClaims      string
+	RoleAttributePath string
+	OrgAttributePath  string
+	OrgMapping        []string
+}
+
+func readJWTIssuers(iniFile *ini.File) []JWTIssuerSettings {
+	const prefix = "auth.jwt.issuer."
+
+	var issuers []JWTIssuerSettings
+	for _, section := range iniFile.Sections() {
+		name := strings.TrimPrefix(section.Name(), prefix)
+		if name == section.Name() || name == "" {
+			continue
+		}
+
+		issuers = append(issuers, JWTIssuerSettings{
+			Name:              name,
+			Issuer:            valueAsString(section, "issuer", ""),
+			KeyFile:           valueAsString(section, "key_file", ""),
+			JWKSetFile:        valueAsString(section, "jwk_set_file", ""),
+			JWKSetURL:         valueAsString(section, "jwk_set_url", ""),
+			CacheTTL:          section.Key("cache_ttl").MustDuration(time.Minute * 60),
+			ExpectClaims:      valueAsString(section, "expect_claims", "{}"),
+			RoleAttributePath: valueAsString(section, "role_attribute_path", ""),
+			OrgAttributePath:  valueAsString(section, "org_attribute_path", ""),
+			OrgMapping:        util.SplitString(valueAsString(section, "org_mapping", "")),
+		})
+	}
+
+	return issuers
+}
+
 type RemoteCacheOptions struct {
 	Name       string
 	ConnStr    string
@@ -1332,6 +1437,14 @@ func readSecuritySettings(iniFile *ini.File, cfg *Cfg) error {
 	cfg.SecretKey = SecretKey
 	DisableGravatar = security.Key("disable_gravatar").MustBool(true)
 	cfg.DisableBruteForceLoginProtection = security.Key("disable_brute_force_login_protection").MustBool(false)
+	cfg.LoginLockoutThreshold = security.Key("login_lockout_threshold").MustInt(0)
+	if val := valueAsString(security, "login_lockout_duration", ""); val != "" {
+		lockoutDuration, err := gtime.ParseDuration(val)
+		if err != nil {
+			return err
+		}
+		cfg.LoginLockoutDuration = lockoutDuration
+	}
 
 	CookieSecure = security.Key("cookie_secure").MustBool(false)
 	cfg.CookieSecure = CookieSecure
@@ -1464,6 +1577,27 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 		cfg.TokenRotationIntervalMinutes = 2
 	}
 
+	cfg.LoginMaxInactiveLifetimeByRole = map[string]time.Duration{}
+	for _, role := range []string{"Viewer", "Editor", "Admin"} {
+		key := "login_maximum_inactive_lifetime_duration_" + strings.ToLower(role)
+		val := valueAsString(auth, key, "")
+		if val == "" {
+			continue
+		}
+		d, err := gtime.ParseDuration(val)
+		if err != nil {
+			return err
+		}
+		// tokens are only refreshed when rotated so a shorter idle timeout would sign out active users
+		if minIdle := time.Duration(cfg.TokenRotationIntervalMinutes) * time.Minute; d < minIdle {
+			cfg.Logger.Warn("Idle timeout is shorter than the token rotation interval, using the rotation interval instead", "setting", key, "value", d, "rotationInterval", minIdle)
+			d = minIdle
+		}
+		cfg.LoginMaxInactiveLifetimeByRole[role] = d
+	}
+
+	cfg.LoginMaxConcurrentSessions = auth.Key("login_max_concurrent_sessions").MustInt(0)
+
 	// Debug setting unlocking frontend auth sync lock. Users will still be reset on their next login.
 	cfg.DisableSyncLock = auth.Key("disable_sync_lock").MustBool(false)
 
@@ -1535,6 +1669,32 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 	authBasic := iniFile.Section("auth.basic")
 	cfg.BasicAuthEnabled = authBasic.Key("enabled").MustBool(true)
 
+	// two-factor authentication
+	authTwoFactor := iniFile.Section("auth.two_factor")
+	cfg.TwoFactorEnabled = authTwoFactor.Key("enabled").MustBool(true)
+	cfg.TwoFactorEnforceForAdmins = authTwoFactor.Key("enforce_for_admins").MustBool(false)
+	cfg.TwoFactorIssuer = valueAsString(authTwoFactor, "issuer", "Grafana")
+
+	// password policy
+	passwordPolicy := iniFile.Section("auth.password_policy")
+	cfg.PasswordPolicyMinLength = passwordPolicy.Key("min_length").MustInt(5)
+	cfg.PasswordPolicyRequireUppercase = passwordPolicy.Key("require_uppercase").MustBool(false)
+	cfg.PasswordPolicyRequireLowercase = passwordPolicy.Key("require_lowercase").MustBool(false)
+	cfg.PasswordPolicyRequireDigit = passwordPolicy.Key("require_digit").MustBool(false)
+	cfg.PasswordPolicyRequireSymbol = passwordPolicy.Key("require_symbol").MustBool(false)
+	cfg.PasswordPolicyBannedPasswordsFile = valueAsString(passwordPolicy, "banned_passwords_file", "")
+	cfg.PasswordPolicyHistoryCount = passwordPolicy.Key("history_count").MustInt(0)
+	if val := valueAsString(passwordPolicy, "max_age", ""); val != "" {
+		if cfg.PasswordPolicyMaxAge, err = gtime.ParseDuration(val); err != nil {
+			return err
+		}
+	}
+
+	// SCIM provisioning
+	authSCIM := iniFile.Section("auth.scim")
+	cfg.SCIMEnabled = authSCIM.Key("enabled").MustBool(false)
+	cfg.SCIMDefaultOrgRole = valueAsString(authSCIM, "default_org_role", "Viewer")
+
 	// JWT auth
 	authJWT := iniFile.Section("auth.jwt")
 	cfg.JWTAuthEnabled = authJWT.Key("enabled").MustBool(false)
@@ -1552,6 +1712,10 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 	cfg.JWTAuthRoleAttributeStrict = authJWT.Key("role_attribute_strict").MustBool(false)
 	cfg.JWTAuthAllowAssignGrafanaAdmin = authJWT.Key("allow_assign_grafana_admin").MustBool(false)
 	cfg.JWTAuthSkipOrgRoleSync = authJWT.Key("skip_org_role_sync").MustBool(false)
+	cfg.JWTAuthOrgAttributePath = valueAsString(authJWT, "org_attribute_path", "")
+	cfg.JWTAuthOrgMapping = util.SplitString(valueAsString(authJWT, "org_mapping", ""))
+	cfg.JWTAuthTokenExchangeEnabled = authJWT.Key("token_exchange_enabled").MustBool(false)
+	cfg.JWTAuthIssuers = readJWTIssuers(iniFile)
 
 	authProxy := iniFile.Section("auth.proxy")
 	cfg.AuthProxyEnabled = authProxy.Key("enabled").MustBool(false)
@@ -1637,6 +1801,8 @@ func readUserSettings(iniFile *ini.File, cfg *Cfg) error {
 func readServiceAccountSettings(iniFile *ini.File, cfg *Cfg) error {
 	serviceAccount := iniFile.Section("service_accounts")
 	cfg.SATokenExpirationDayLimit = serviceAccount.Key("token_expiration_day_limit").MustInt(-1)
+	cfg.SATokenUnusedDayLimit = serviceAccount.Key("token_unused_day_limit").MustInt(0)
+	cfg.SATokenTrustedProxies = util.SplitString(serviceAccount.Key("token_trusted_proxies").MustString(""))
 	return nil
 }
 
@@ -1859,6 +2025,7 @@ func (cfg *Cfg) GetContentDeliveryURL(prefix string) string {
 func (cfg *Cfg) readDataSourcesSettings() {
 	datasources := cfg.Raw.Section("datasources")
 	cfg.DataSourceLimit = datasources.Key("datasource_limit").MustInt(5000)
+	cfg.DataSourcePermissionsEnforced = datasources.Key("enforce_permissions").MustBool(false)
 }
 
 func (cfg *Cfg) readSqlDataSourceSettings() {
@@ -1866,6 +2033,8 @@ func (cfg *Cfg) readSqlDataSourceSettings() {
 	cfg.SqlDatasourceMaxOpenConnsDefault = sqlDatasources.Key("max_open_conns_default").MustInt(100)
 	cfg.SqlDatasourceMaxIdleConnsDefault = sqlDatasources.Key("max_idle_conns_default").MustInt(100)
 	cfg.SqlDatasourceMaxConnLifetimeDefault = sqlDatasources.Key("max_conn_lifetime_default").MustInt(14400)
+	cfg.SqliteDatasourceAllowedPaths = util.SplitString(sqlDatasources.Key("sqlite_allowed_paths").MustString(""))
+	cfg.GenericSqlDatasourceAllowedDrivers = util.SplitString(sqlDatasources.Key("generic_sql_allowed_drivers").MustString(""))
 }
 
 func GetAllowedOriginGlobs(originPatterns []string) ([]glob.Glob, error) {
@@ -0,0 +1,48 @@
+package setting
+
+import (
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type AuditSettings struct {
+	// Enabled records logins and administrative and permission-changing actions in the audit log
+	Enabled bool
+	// Sinks are the destinations of the audit events: database, file and syslog
+	Sinks []string
+	// MaxAge is how long events are kept in the database before the cleanup service deletes them
+	MaxAge time.Duration
+
+	// FilePath is the file the file sink appends events to, one JSON object per line
+	FilePath string
+
+	SyslogNetwork  string
+	SyslogAddress  string
+	SyslogFacility string
+	SyslogTag      string
+}
+
+func readAuditSettings(iniFile *ini.File) (AuditSettings, error) {
+	s := AuditSettings{}
+
+	section := iniFile.Section("audit")
+	s.Enabled = section.Key("enabled").MustBool(false)
+	s.Sinks = util.SplitString(valueAsString(section, "sinks", "database"))
+
+	maxAge, err := gtime.ParseDuration(valueAsString(section, "max_age", "90d"))
+	if err != nil {
+		return s, err
+	}
+	s.MaxAge = maxAge
+
+	s.FilePath = valueAsString(section, "file_path", "")
+	s.SyslogNetwork = valueAsString(section, "syslog_network", "")
+	s.SyslogAddress = valueAsString(section, "syslog_address", "")
+	s.SyslogFacility = valueAsString(section, "syslog_facility", "local7")
+	s.SyslogTag = valueAsString(section, "syslog_tag", "grafana-audit")
+	return s, nil
+}
@@ -0,0 +1,29 @@
+package setting
+
+import (
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"gopkg.in/ini.v1"
+)
+
+type PublicDashboardsSettings struct {
+	// AccessLogEnabled records who viewed and queried public dashboards
+	AccessLogEnabled bool
+	// AccessLogMaxAge is how long access log entries are kept before the cleanup service deletes them
+	AccessLogMaxAge time.Duration
+}
+
+func readPublicDashboardsSettings(iniFile *ini.File) (PublicDashboardsSettings, error) {
+	s := PublicDashboardsSettings{}
+
+	section := iniFile.Section("public_dashboards")
+	s.AccessLogEnabled = section.Key("access_log_enabled").MustBool(false)
+
+	maxAge, err := gtime.ParseDuration(valueAsString(section, "access_log_max_age", "30d"))
+	if err != nil {
+		return s, err
+	}
+	s.AccessLogMaxAge = maxAge
+	return s, nil
+}
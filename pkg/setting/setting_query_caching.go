@@ -0,0 +1,25 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+type QueryCachingSettings struct {
+	Enabled bool
+	// TTL is the default time to live of cached query results, it can be overridden per data source
+	TTL time.Duration
+	// MaxValueSize is the maximum size in bytes of a cached response, larger responses are not cached
+	MaxValueSize int
+}
+
+func readQueryCachingSettings(iniFile *ini.File) QueryCachingSettings {
+	s := QueryCachingSettings{}
+
+	section := iniFile.Section("query_caching")
+	s.Enabled = section.Key("enabled").MustBool(false)
+	s.TTL = section.Key("ttl").MustDuration(time.Minute)
+	s.MaxValueSize = section.Key("max_value_mb").MustInt(1) * 1024 * 1024
+	return s
+}
@@ -0,0 +1,30 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+type QueryConcurrencySettings struct {
+	// CoalesceRequests makes identical concurrent queries to a data source share a single request
+	CoalesceRequests bool
+	// MaxConcurrentQueriesPerDatasource limits the concurrent requests to a data source, 0 is unlimited.
+	// It can be overridden per data source.
+	MaxConcurrentQueriesPerDatasource int
+	// MaxConcurrentQueriesPerUser limits the concurrent data source requests of a user, 0 is unlimited
+	MaxConcurrentQueriesPerUser int
+	// QueueTimeout is how long queries over a limit wait for a slot before failing, 0 fails immediately
+	QueueTimeout time.Duration
+}
+
+func readQueryConcurrencySettings(iniFile *ini.File) QueryConcurrencySettings {
+	s := QueryConcurrencySettings{}
+
+	section := iniFile.Section("query_concurrency")
+	s.CoalesceRequests = section.Key("coalesce_requests").MustBool(false)
+	s.MaxConcurrentQueriesPerDatasource = section.Key("max_concurrent_queries_per_datasource").MustInt(0)
+	s.MaxConcurrentQueriesPerUser = section.Key("max_concurrent_queries_per_user").MustInt(0)
+	s.QueueTimeout = section.Key("queue_timeout").MustDuration(10 * time.Second)
+	return s
+}
@@ -10,14 +10,22 @@ type SearchSettings struct {
 	FullReindexInterval       time.Duration
 	IndexUpdateInterval       time.Duration
 	DashboardLoadingBatchSize int
+
+	// IndexPath is the directory where search indexes are persisted (empty will keep them in memory)
+	IndexPath string
 }
 
-func readSearchSettings(iniFile *ini.File) SearchSettings {
+func readSearchSettings(iniFile *ini.File, dataPath string) SearchSettings {
 	s := SearchSettings{}
 
 	searchSection := iniFile.Section("search")
 	s.DashboardLoadingBatchSize = searchSection.Key("dashboard_loading_batch_size").MustInt(200)
 	s.FullReindexInterval = searchSection.Key("full_reindex_interval").MustDuration(5 * time.Minute)
 	s.IndexUpdateInterval = searchSection.Key("index_update_interval").MustDuration(10 * time.Second)
+
+	s.IndexPath = valueAsString(searchSection, "index_path", "")
+	if s.IndexPath != "" {
+		s.IndexPath = makeAbsolute(s.IndexPath, dataPath)
+	}
 	return s
 }
@@ -1,16 +1,57 @@
 package setting
 
 import (
+	"strings"
+
 	"gopkg.in/ini.v1"
 )
 
 type StorageSettings struct {
 	AllowUnsanitizedSvgUpload bool
+
+	// Bucket URL used for the per-org "resources" root instead of the SQL database
+	ResourcesBucketURL string
+
+	// Object storage roots mounted under the content root
+	ObjectRoots []StorageObjectRootSettings
+}
+
+// StorageObjectRootSettings are read from [storage.object.<prefix>] sections
+type StorageObjectRootSettings struct {
+	Prefix      string
+	Name        string
+	Description string
+	BucketURL   string
+	Folder      string
+	ReadOnly    bool
 }
 
 func readStorageSettings(iniFile *ini.File) StorageSettings {
 	s := StorageSettings{}
 	storageSection := iniFile.Section("storage")
 	s.AllowUnsanitizedSvgUpload = storageSection.Key("allow_unsanitized_svg_upload").MustBool(false)
+	s.ResourcesBucketURL = storageSection.Key("resources_bucket_url").MustString("")
+	s.ObjectRoots = extractStorageObjectRoots(iniFile.Sections())
 	return s
 }
+
+func extractStorageObjectRoots(sections []*ini.Section) []StorageObjectRootSettings {
+	roots := make([]StorageObjectRootSettings, 0)
+	for _, section := range sections {
+		sectionName := section.Name()
+		if !strings.HasPrefix(sectionName, "storage.object.") {
+			continue
+		}
+
+		prefix := strings.Replace(sectionName, "storage.object.", "", 1)
+		roots = append(roots, StorageObjectRootSettings{
+			Prefix:      prefix,
+			Name:        section.Key("name").MustString(prefix),
+			Description: section.Key("description").MustString(""),
+			BucketURL:   section.Key("bucket_url").MustString(""),
+			Folder:      section.Key("folder").MustString(""),
+			ReadOnly:    section.Key("read_only").MustBool(false),
+		})
+	}
+	return roots
+}
@@ -0,0 +1,50 @@
+package setting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+func TestStorageObjectRootSettings(t *testing.T) {
+	f, err := ini.Load([]byte(`
+[storage]
+resources_bucket_url = s3://resources
+
+[storage.object.assets]
+name = Assets
+description = Shared assets
+bucket_url = gs://assets
+folder = grafana/assets
+read_only = true
+
+[storage.object.files]
+bucket_url = file:///var/lib/files
+
+[storage.other]
+bucket_url = gs://ignored
+`))
+	require.NoError(t, err)
+
+	roots := extractStorageObjectRoots(f.Sections())
+	require.Equal(t, []StorageObjectRootSettings{
+		{
+			Prefix:      "assets",
+			Name:        "Assets",
+			Description: "Shared assets",
+			BucketURL:   "gs://assets",
+			Folder:      "grafana/assets",
+			ReadOnly:    true,
+		},
+		{
+			Prefix:    "files",
+			Name:      "files",
+			BucketURL: "file:///var/lib/files",
+		},
+	}, roots)
+
+	s := readStorageSettings(f)
+	require.Equal(t, "s3://resources", s.ResourcesBucketURL)
+	require.Equal(t, roots, s.ObjectRoots)
+}
@@ -21,6 +21,15 @@ const (
 	windows = "windows"
 )
 
+func TestMain(m *testing.M) {
+	// the tests load the configuration with the repository as home path, log to
+	// the console so they don't write log files to its data directory
+	if err := os.Setenv("GF_LOG_MODE", "console"); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
 func TestLoadingSettings(t *testing.T) {
 	skipStaticRootValidation = true
 
@@ -767,3 +776,28 @@ func TestAlertingEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestReadJWTIssuers(t *testing.T) {
+	f, err := ini.Load([]byte(`
+[auth.jwt]
+enabled = true
+
+[auth.jwt.issuer.portal]
+issuer = https://portal.example.com
+jwk_set_url = https://portal.example.com/.well-known/jwks.json
+cache_ttl = 10m
+org_attribute_path = tenants
+org_mapping = acme:2:Editor, *:1:Viewer
+`))
+	require.NoError(t, err)
+
+	require.Equal(t, []JWTIssuerSettings{{
+		Name:             "portal",
+		Issuer:           "https://portal.example.com",
+		JWKSetURL:        "https://portal.example.com/.well-known/jwks.json",
+		CacheTTL:         10 * time.Minute,
+		ExpectClaims:     "{}",
+		OrgAttributePath: "tenants",
+		OrgMapping:       []string{"acme:2:Editor", "*:1:Viewer"},
+	}}, readJWTIssuers(f))
+}
@@ -11,13 +11,22 @@ const (
 	HighlightFragmentSize   = 2147483647
 )
 
+// SortOrder is the order of a sort of a search request
+type SortOrder string
+
+const (
+	SortOrderAsc  SortOrder = "asc"
+	SortOrderDesc SortOrder = "desc"
+)
+
 // SearchRequestBuilder represents a builder which can build a search request
 type SearchRequestBuilder struct {
 	interval time.Duration
 	index    string
 	size     int
-	// Currently sort is map, but based in examples it should be an array https://www.elastic.co/guide/en/elasticsearch/reference/current/sort-search-results.html
-	sort         map[string]interface{}
+	// The sort is an array as the order of the sorts matters, and the sort values of the hits, which are used
+	// to paginate with search_after, are in the same order https://www.elastic.co/guide/en/elasticsearch/reference/current/sort-search-results.html
+	sort         []map[string]interface{}
 	queryBuilder *QueryBuilder
 	aggBuilders  []AggBuilder
 	customProps  map[string]interface{}
@@ -27,7 +36,7 @@ type SearchRequestBuilder struct {
 func NewSearchRequestBuilder(interval time.Duration) *SearchRequestBuilder {
 	builder := &SearchRequestBuilder{
 		interval:    interval,
-		sort:        make(map[string]interface{}),
+		sort:        make([]map[string]interface{}, 0),
 		customProps: make(map[string]interface{}),
 		aggBuilders: make([]AggBuilder, 0),
 	}
@@ -73,17 +82,31 @@ func (b *SearchRequestBuilder) Size(size int) *SearchRequestBuilder {
 	return b
 }
 
-// SortDesc adds a sort to the search request
+// SortDesc adds a descending sort to the search request
 func (b *SearchRequestBuilder) SortDesc(field, unmappedType string) *SearchRequestBuilder {
+	return b.Sort(SortOrderDesc, field, unmappedType)
+}
+
+// Sort adds a sort to the search request, after the sorts already added
+func (b *SearchRequestBuilder) Sort(order SortOrder, field, unmappedType string) *SearchRequestBuilder {
 	props := map[string]string{
-		"order": "desc",
+		"order": string(order),
 	}
 
 	if unmappedType != "" {
 		props["unmapped_type"] = unmappedType
 	}
 
-	b.sort[field] = props
+	b.sort = append(b.sort, map[string]interface{}{field: props})
+
+	return b
+}
+
+// SearchAfter sets the sort values of the last hit of the previous page to get the next page of hits
+func (b *SearchRequestBuilder) SearchAfter(values []interface{}) *SearchRequestBuilder {
+	if len(values) > 0 {
+		b.customProps["search_after"] = values
+	}
 
 	return b
 }
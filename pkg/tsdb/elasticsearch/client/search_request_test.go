@@ -59,7 +59,7 @@ func TestSearchRequest(t *testing.T) {
 			})
 
 			t.Run("Should have correct sorting", func(t *testing.T) {
-				sort, ok := sr.Sort[timeField].(map[string]string)
+				sort, ok := sr.Sort[0][timeField].(map[string]string)
 				require.True(t, ok)
 				require.Equal(t, "desc", sort["order"])
 				require.Equal(t, "boolean", sort["unmapped_type"])
@@ -87,7 +87,7 @@ func TestSearchRequest(t *testing.T) {
 				require.Nil(t, err)
 				require.Equal(t, 200, json.Get("size").MustInt(0))
 
-				sort := json.GetPath("sort", timeField)
+				sort := json.Get("sort").GetIndex(0).Get(timeField)
 				require.Equal(t, "desc", sort.Get("order").MustString())
 				require.Equal(t, "boolean", sort.Get("unmapped_type").MustString())
 
@@ -145,6 +145,38 @@ func TestSearchRequest(t *testing.T) {
 		})
 	})
 
+	t.Run("When adding sorts and search after", func(t *testing.T) {
+		b := setup()
+		b.Sort(SortOrderAsc, timeField, "boolean")
+		b.Sort(SortOrderAsc, "_doc", "")
+		b.SearchAfter([]interface{}{1668422437218, 10})
+
+		t.Run("When marshal to JSON should keep the order of the sorts", func(t *testing.T) {
+			sr, err := b.Build()
+			require.Nil(t, err)
+			body, err := json.Marshal(sr)
+			require.Nil(t, err)
+			json, err := simplejson.NewJson(body)
+			require.Nil(t, err)
+
+			sorts := json.Get("sort").MustArray()
+			require.Len(t, sorts, 2)
+			require.Equal(t, "asc", json.Get("sort").GetIndex(0).GetPath(timeField, "order").MustString())
+			require.Equal(t, "boolean", json.Get("sort").GetIndex(0).GetPath(timeField, "unmapped_type").MustString())
+			require.Equal(t, "asc", json.Get("sort").GetIndex(1).GetPath("_doc", "order").MustString())
+
+			searchAfter := json.Get("search_after")
+			require.Equal(t, int64(1668422437218), searchAfter.GetIndex(0).MustInt64())
+			require.Equal(t, int64(10), searchAfter.GetIndex(1).MustInt64())
+		})
+
+		t.Run("Should not set search after without values", func(t *testing.T) {
+			b := setup()
+			b.SearchAfter(nil)
+			require.Nil(t, b.customProps["search_after"])
+		})
+	})
+
 	t.Run("and adding multiple top level aggs", func(t *testing.T) {
 		b := setup()
 		aggBuilder := b.Agg()
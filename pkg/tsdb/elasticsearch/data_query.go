@@ -14,6 +14,8 @@ import (
 
 const (
 	defaultSize = 500
+	// defaultFieldStatsSize is the number of top values returned per field by field stats queries
+	defaultFieldStatsSize = 10
 )
 
 type elasticsearchDataQuery struct {
@@ -74,6 +76,8 @@ func (e *elasticsearchDataQuery) processQuery(q *Query, ms *es.MultiSearchReques
 		processLogsQuery(q, b, from, to, defaultTimeField)
 	} else if isDocumentQuery(q) {
 		processDocumentQuery(q, b, from, to, defaultTimeField)
+	} else if isFieldStatsQuery(q) {
+		processFieldStatsQuery(q, b)
 	} else {
 		// Otherwise, it is a time series query and we process it
 		processTimeSeriesQuery(q, b, from, to, defaultTimeField)
@@ -291,11 +295,22 @@ func getPipelineAggField(m *MetricAgg) string {
 
 func isQueryWithError(query *Query) error {
 	if len(query.BucketAggs) == 0 {
-		// If no aggregations, only document and logs queries are valid
-		if len(query.Metrics) == 0 || !(isLogsQuery(query) || isDocumentQuery(query)) {
+		// If no aggregations, only document, logs and field stats queries are valid
+		if len(query.Metrics) == 0 || !(isLogsQuery(query) || isDocumentQuery(query) || isFieldStatsQuery(query)) {
 			return fmt.Errorf("invalid query, missing metrics and aggregations")
 		}
 	}
+
+	if isLogsQuery(query) || isDocumentQuery(query) {
+		sortDirection := query.Metrics[0].Settings.Get("sortDirection").MustString()
+		if sortDirection != "" && sortDirection != string(es.SortOrderAsc) && sortDirection != string(es.SortOrderDesc) {
+			return fmt.Errorf("invalid query, invalid sort direction %q", sortDirection)
+		}
+	}
+
+	if isFieldStatsQuery(query) && len(getFieldStatsFields(query.Metrics[0])) == 0 {
+		return fmt.Errorf("invalid query, missing fields of field stats")
+	}
 	return nil
 }
 
@@ -315,12 +330,42 @@ func isRawDocumentQuery(query *Query) bool {
 	return query.Metrics[0].Type == rawDocumentType
 }
 
+func isFieldStatsQuery(query *Query) bool {
+	return query.Metrics[0].Type == fieldStatsType
+}
+
+// getDocumentQuerySize returns the number of hits requested by a logs or document query,
+// which is set as a number by API clients and as a string by the query editor.
+func getDocumentQuerySize(metric *MetricAgg) int {
+	setting := "size"
+	if metric.Type == logsType {
+		setting = "limit"
+	}
+
+	if size, err := metric.Settings.Get(setting).Int(); err == nil && size > 0 {
+		return size
+	}
+	return stringToIntWithDefaultValue(metric.Settings.Get(setting).MustString(), defaultSize)
+}
+
+// addDocumentSort sorts the hits of logs and document queries by time, and by _doc to break ties.
+// The next page of hits is fetched by setting searchAfter to the sort values of the last hit of a page.
+func addDocumentSort(b *es.SearchRequestBuilder, metric *MetricAgg, defaultTimeField string) {
+	order := es.SortOrderDesc
+	if metric.Settings.Get("sortDirection").MustString() == string(es.SortOrderAsc) {
+		order = es.SortOrderAsc
+	}
+
+	b.Sort(order, defaultTimeField, "boolean")
+	b.Sort(order, "_doc", "")
+	b.SearchAfter(metric.Settings.Get("searchAfter").MustArray())
+}
+
 func processLogsQuery(q *Query, b *es.SearchRequestBuilder, from, to int64, defaultTimeField string) {
 	metric := q.Metrics[0]
-	b.SortDesc(defaultTimeField, "boolean")
-	b.SortDesc("_doc", "")
+	addDocumentSort(b, metric, defaultTimeField)
 	b.AddDocValueField(defaultTimeField)
-	b.Size(stringToIntWithDefaultValue(metric.Settings.Get("limit").MustString(), defaultSize))
+	b.Size(getDocumentQuerySize(metric))
 	b.AddHighlight()
 
 	// For log query, we add a date histogram aggregation
@@ -342,10 +387,48 @@ func processLogsQuery(q *Query, b *es.SearchRequestBuilder, from, to int64, defa
 
 func processDocumentQuery(q *Query, b *es.SearchRequestBuilder, from, to int64, defaultTimeField string) {
 	metric := q.Metrics[0]
-	b.SortDesc(defaultTimeField, "boolean")
-	b.SortDesc("_doc", "")
+	addDocumentSort(b, metric, defaultTimeField)
 	b.AddDocValueField(defaultTimeField)
-	b.Size(stringToIntWithDefaultValue(metric.Settings.Get("size").MustString(), defaultSize))
+	b.Size(getDocumentQuerySize(metric))
+
+	if metric.Settings.Get("highlight").MustBool(false) {
+		b.AddHighlight()
+	}
+}
+
+// getFieldStatsFields returns the fields of a field stats query, set in the fields setting or, for a single
+// field, as the field of the metric.
+func getFieldStatsFields(metric *MetricAgg) []string {
+	fields := make([]string, 0)
+	for _, field := range metric.Settings.Get("fields").MustStringArray() {
+		if field != "" {
+			fields = append(fields, field)
+		}
+	}
+
+	if len(fields) == 0 && metric.Field != "" {
+		fields = append(fields, metric.Field)
+	}
+	return fields
+}
+
+// processFieldStatsQuery adds a terms aggregation with the most frequent values of each field of a field
+// stats query. The aggregations are keyed by the index of their field, as field names can contain characters
+// that are not allowed in aggregation names.
+func processFieldStatsQuery(q *Query, b *es.SearchRequestBuilder) {
+	metric := q.Metrics[0]
+	size, err := metric.Settings.Get("size").Int()
+	if err != nil || size <= 0 {
+		size = stringToIntWithDefaultValue(metric.Settings.Get("size").MustString(), defaultFieldStatsSize)
+	}
+
+	aggBuilder := b.Agg()
+	for i, field := range getFieldStatsFields(metric) {
+		aggBuilder.Terms(strconv.Itoa(i), field, func(a *es.TermsAggregation, _ es.AggBuilder) {
+			a.Size = size
+			a.Order["_count"] = "desc"
+		})
+	}
 }
 
 func processTimeSeriesQuery(q *Query, b *es.SearchRequestBuilder, from, to int64, defaultTimeField string) {
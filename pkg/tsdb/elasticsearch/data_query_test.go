@@ -2,6 +2,7 @@ package elasticsearch
 
 import (
 	"encoding/json"
+	"strconv"
 	"testing"
 	"time"
 
@@ -424,8 +425,8 @@ func TestExecuteElasticsearchDataQuery(t *testing.T) {
 			require.Equal(t, rangeFilter.Format, es.DateFormatEpochMS)
 
 			require.Equal(t, sr.Size, defaultSize)
-			require.Equal(t, sr.Sort["@timestamp"], map[string]string{"order": "desc", "unmapped_type": "boolean"})
-			require.Equal(t, sr.Sort["_doc"], map[string]string{"order": "desc"})
+			require.Equal(t, sr.Sort[0]["@timestamp"], map[string]string{"order": "desc", "unmapped_type": "boolean"})
+			require.Equal(t, sr.Sort[1]["_doc"], map[string]string{"order": "desc"})
 			require.Equal(t, sr.CustomProps["script_fields"], map[string]interface{}{})
 		})
 
@@ -445,8 +446,8 @@ func TestExecuteElasticsearchDataQuery(t *testing.T) {
 			require.Equal(t, rangeFilter.Format, es.DateFormatEpochMS)
 
 			require.Equal(t, sr.Size, defaultSize)
-			require.Equal(t, sr.Sort["@timestamp"], map[string]string{"order": "desc", "unmapped_type": "boolean"})
-			require.Equal(t, sr.Sort["_doc"], map[string]string{"order": "desc"})
+			require.Equal(t, sr.Sort[0]["@timestamp"], map[string]string{"order": "desc", "unmapped_type": "boolean"})
+			require.Equal(t, sr.Sort[1]["_doc"], map[string]string{"order": "desc"})
 			require.Equal(t, sr.CustomProps["script_fields"], map[string]interface{}{})
 		})
 
@@ -1310,8 +1311,8 @@ func TestExecuteElasticsearchDataQuery(t *testing.T) {
 			require.Equal(t, rangeFilter.Gte, fromMs)
 			require.Equal(t, rangeFilter.Format, es.DateFormatEpochMS)
 
-			require.Equal(t, sr.Sort["@timestamp"], map[string]string{"order": "desc", "unmapped_type": "boolean"})
-			require.Equal(t, sr.Sort["_doc"], map[string]string{"order": "desc"})
+			require.Equal(t, sr.Sort[0]["@timestamp"], map[string]string{"order": "desc", "unmapped_type": "boolean"})
+			require.Equal(t, sr.Sort[1]["_doc"], map[string]string{"order": "desc"})
 			require.Equal(t, sr.CustomProps["script_fields"], map[string]interface{}{})
 
 			firstLevel := sr.Aggs[0]
@@ -1354,6 +1355,83 @@ func TestExecuteElasticsearchDataQuery(t *testing.T) {
 			})
 		})
 
+		t.Run("With log query with sort direction and search after should return next page query", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"metrics": [{ "type": "logs", "id": "1", "settings": { "limit": 100, "sortDirection": "asc", "searchAfter": [1526406600000, 42] }}]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+			require.Equal(t, sr.Size, 100)
+			require.Equal(t, sr.Sort[0]["@timestamp"], map[string]string{"order": "asc", "unmapped_type": "boolean"})
+			require.Equal(t, sr.Sort[1]["_doc"], map[string]string{"order": "asc"})
+
+			searchAfter, err := json.Marshal(sr.CustomProps["search_after"])
+			require.NoError(t, err)
+			require.Equal(t, `[1526406600000,42]`, string(searchAfter))
+		})
+
+		t.Run("With log query with invalid sort direction should return error", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"metrics": [{ "type": "logs", "id": "1", "settings": { "sortDirection": "up" }}]
+			}`, from, to)
+			require.EqualError(t, err, `invalid query, invalid sort direction "up"`)
+		})
+
+		t.Run("With raw data query with highlight should return highlight properties", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"metrics": [{ "type": "raw_data", "id": "1", "settings": { "highlight": true }}]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+			require.NotNil(t, sr.CustomProps["highlight"])
+			require.Nil(t, sr.CustomProps["search_after"])
+		})
+
+		t.Run("With field stats query should return terms aggregation per field", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"metrics": [{ "type": "field_stats", "id": "1", "settings": { "fields": ["host", "level"], "size": "5" }}]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+			require.Equal(t, sr.Size, 0)
+			require.Len(t, sr.Sort, 0)
+			require.Len(t, sr.Aggs, 2)
+
+			for i, field := range []string{"host", "level"} {
+				require.Equal(t, sr.Aggs[i].Key, strconv.Itoa(i))
+				require.Equal(t, sr.Aggs[i].Aggregation.Type, "terms")
+				termsAgg := sr.Aggs[i].Aggregation.Aggregation.(*es.TermsAggregation)
+				require.Equal(t, termsAgg.Field, field)
+				require.Equal(t, termsAgg.Size, 5)
+				require.Equal(t, termsAgg.Order["_count"], "desc")
+			}
+		})
+
+		t.Run("With field stats query with metric field should use default size", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"metrics": [{ "type": "field_stats", "id": "1", "field": "host" }]
+			}`, from, to)
+			require.NoError(t, err)
+			sr := c.multisearchRequests[0].Requests[0]
+			require.Len(t, sr.Aggs, 1)
+			termsAgg := sr.Aggs[0].Aggregation.Aggregation.(*es.TermsAggregation)
+			require.Equal(t, termsAgg.Field, "host")
+			require.Equal(t, termsAgg.Size, defaultFieldStatsSize)
+		})
+
+		t.Run("With field stats query without fields should return error", func(t *testing.T) {
+			c := newFakeClient()
+			_, err := executeElasticsearchDataQuery(c, `{
+				"metrics": [{ "type": "field_stats", "id": "1" }]
+			}`, from, to)
+			require.EqualError(t, err, "invalid query, missing fields of field stats")
+		})
+
 		t.Run("With invalid query should return error", (func(t *testing.T) {
 			c := newFakeClient()
 			_, err := executeElasticsearchDataQuery(c, `{
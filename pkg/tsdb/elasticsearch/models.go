@@ -58,6 +58,7 @@ var metricAggType = map[string]string{
 	"raw_data":       "Raw Data",
 	"rate":           "Rate",
 	"logs":           "Logs",
+	"field_stats":    "Field Stats",
 }
 
 var extendedStats = map[string]string{
@@ -35,6 +35,8 @@ const (
 	rawDataType     = "raw_data"
 	// Logs type
 	logsType = "logs"
+	// Field stats type
+	fieldStatsType = "field_stats"
 )
 
 var searchWordsRegex = regexp.MustCompile(regexp.QuoteMeta(es.HighlightPreTagsString) + `(.*?)` + regexp.QuoteMeta(es.HighlightPostTagsString))
@@ -78,6 +80,9 @@ func parseResponse(responses []*es.SearchResponse, targets []*Query, configuredF
 				return &backend.QueryDataResponse{}, err
 			}
 			result.Responses[target.RefID] = queryRes
+		} else if isFieldStatsQuery(target) {
+			processFieldStatsResponse(res, target, &queryRes)
+			result.Responses[target.RefID] = queryRes
 		} else {
 			// Process as metric query result
 			props := make(map[string]string)
@@ -126,21 +131,7 @@ func processLogsResponse(res *es.SearchResponse, target *Query, configuredFields
 			propNames[key] = true
 		}
 
-		// Process highlight to searchWords
-		if highlights, ok := doc["highlight"].(map[string]interface{}); ok {
-			for _, highlight := range highlights {
-				if highlightList, ok := highlight.([]interface{}); ok {
-					for _, highlightValue := range highlightList {
-						str := fmt.Sprintf("%v", highlightValue)
-						matches := searchWordsRegex.FindAllStringSubmatch(str, -1)
-
-						for _, v := range matches {
-							searchWords[v[1]] = true
-						}
-					}
-				}
-			}
-		}
+		addSearchWords(searchWords, doc["highlight"])
 
 		docs[hitIdx] = doc
 	}
@@ -152,6 +143,7 @@ func processLogsResponse(res *es.SearchResponse, target *Query, configuredFields
 	frame := data.NewFrame("", fields...)
 	setPreferredVisType(frame, data.VisTypeLogs)
 	setSearchWords(frame, searchWords)
+	setSearchAfter(frame, res.Hits.Hits, target)
 	frames = append(frames, frame)
 
 	queryRes.Frames = frames
@@ -161,6 +153,7 @@ func processLogsResponse(res *es.SearchResponse, target *Query, configuredFields
 func processRawDataResponse(res *es.SearchResponse, target *Query, configuredFields es.ConfiguredFields, queryRes *backend.DataResponse) error {
 	propNames := make(map[string]bool)
 	docs := make([]map[string]interface{}, len(res.Hits.Hits))
+	searchWords := make(map[string]bool)
 
 	for hitIdx, hit := range res.Hits.Hits {
 		var flattened map[string]interface{}
@@ -184,6 +177,8 @@ func processRawDataResponse(res *es.SearchResponse, target *Query, configuredFie
 			propNames[key] = true
 		}
 
+		addSearchWords(searchWords, doc["highlight"])
+
 		docs[hitIdx] = doc
 	}
 
@@ -192,6 +187,10 @@ func processRawDataResponse(res *es.SearchResponse, target *Query, configuredFie
 
 	frames := data.Frames{}
 	frame := data.NewFrame("", fields...)
+	if len(searchWords) > 0 {
+		setSearchWords(frame, searchWords)
+	}
+	setSearchAfter(frame, res.Hits.Hits, target)
 	frames = append(frames, frame)
 
 	queryRes.Frames = frames
@@ -200,6 +199,7 @@ func processRawDataResponse(res *es.SearchResponse, target *Query, configuredFie
 
 func processRawDocumentResponse(res *es.SearchResponse, target *Query, queryRes *backend.DataResponse) error {
 	docs := make([]map[string]interface{}, len(res.Hits.Hits))
+	searchWords := make(map[string]bool)
 	for hitIdx, hit := range res.Hits.Hits {
 		doc := map[string]interface{}{
 			"_id":       hit["_id"],
@@ -227,6 +227,8 @@ func processRawDocumentResponse(res *es.SearchResponse, target *Query, queryRes
 			}
 		}
 
+		addSearchWords(searchWords, hit["highlight"])
+
 		docs[hitIdx] = doc
 	}
 
@@ -248,12 +250,57 @@ func processRawDocumentResponse(res *es.SearchResponse, target *Query, queryRes
 
 	frames := data.Frames{}
 	frame := data.NewFrame(target.RefID, field)
+	if len(searchWords) > 0 {
+		setSearchWords(frame, searchWords)
+	}
+	setSearchAfter(frame, res.Hits.Hits, target)
 	frames = append(frames, frame)
 
 	queryRes.Frames = frames
 	return nil
 }
 
+// processFieldStatsResponse returns a table per field of a field stats query, with the most frequent values
+// of the field and their number of documents, the most frequent first.
+func processFieldStatsResponse(res *es.SearchResponse, target *Query, queryRes *backend.DataResponse) {
+	frames := data.Frames{}
+	for i, fieldName := range getFieldStatsFields(target.Metrics[0]) {
+		values := make([]string, 0)
+		counts := make([]int64, 0)
+		for _, b := range simplejson.NewFromAny(res.Aggregations[strconv.Itoa(i)]).Get("buckets").MustArray() {
+			bucket := simplejson.NewFromAny(b)
+			values = append(values, getBucketKeyAsString(bucket))
+			counts = append(counts, bucket.Get("doc_count").MustInt64())
+		}
+
+		frame := data.NewFrame(fieldName,
+			data.NewField("value", nil, values).SetConfig(&data.FieldConfig{DisplayNameFromDS: fieldName}),
+			data.NewField("count", nil, counts).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Count"}),
+		)
+		frame.RefID = target.RefID
+		setPreferredVisType(frame, data.VisTypeTable)
+		frames = append(frames, frame)
+	}
+
+	queryRes.Frames = frames
+}
+
+// getBucketKeyAsString returns the key of a terms bucket, formatted by Elasticsearch for dates and booleans
+func getBucketKeyAsString(bucket *simplejson.Json) string {
+	if key, err := bucket.Get("key_as_string").String(); err == nil {
+		return key
+	}
+
+	switch key := bucket.Get("key").Interface().(type) {
+	case string:
+		return key
+	case float64:
+		return strconv.FormatFloat(key, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", key)
+	}
+}
+
 func processDocsToDataFrameFields(docs []map[string]interface{}, propNames []string, configuredFields es.ConfiguredFields) []*data.Field {
 	size := len(docs)
 	isFilterable := true
@@ -1122,17 +1169,54 @@ func setSearchWords(frame *data.Frame, searchWords map[string]bool) {
 	}
 	sort.Strings(searchWordsList)
 
+	setCustomMeta(frame, "searchWords", searchWordsList)
+}
+
+// addSearchWords adds the words highlighted by Elasticsearch in the highlight of a hit to searchWords
+func addSearchWords(searchWords map[string]bool, highlight interface{}) {
+	highlights, ok := highlight.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for _, highlight := range highlights {
+		if highlightList, ok := highlight.([]interface{}); ok {
+			for _, highlightValue := range highlightList {
+				str := fmt.Sprintf("%v", highlightValue)
+				matches := searchWordsRegex.FindAllStringSubmatch(str, -1)
+
+				for _, v := range matches {
+					searchWords[v[1]] = true
+				}
+			}
+		}
+	}
+}
+
+// setSearchAfter sets the sort values of the last hit of a full page of hits as the searchAfter of the frame
+// meta. They are set as the searchAfter setting of the query to get the next page.
+func setSearchAfter(frame *data.Frame, hits []map[string]interface{}, target *Query) {
+	if len(hits) == 0 || len(hits) < getDocumentQuerySize(target.Metrics[0]) {
+		return
+	}
+
+	if sortValues, ok := hits[len(hits)-1]["sort"].([]interface{}); ok {
+		setCustomMeta(frame, "searchAfter", sortValues)
+	}
+}
+
+func setCustomMeta(frame *data.Frame, key string, value interface{}) {
 	if frame.Meta == nil {
 		frame.Meta = &data.FrameMeta{}
 	}
 
-	if frame.Meta.Custom == nil {
-		frame.Meta.Custom = map[string]interface{}{}
+	custom, ok := frame.Meta.Custom.(map[string]interface{})
+	if !ok {
+		custom = map[string]interface{}{}
+		frame.Meta.Custom = custom
 	}
 
-	frame.Meta.Custom = map[string]interface{}{
-		"searchWords": searchWordsList,
-	}
+	custom[key] = value
 }
 
 func createFields(frames data.Frames, propKeys []string) []*data.Field {
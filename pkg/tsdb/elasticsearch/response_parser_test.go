@@ -1235,6 +1235,129 @@ func TestResponseParser(t *testing.T) {
 			}, customMeta)
 		})
 
+		t.Run("Log query with full page should return search after", func(t *testing.T) {
+			targets := map[string]string{
+				"A": `{
+					"metrics": [{ "type": "logs", "settings": { "limit": "2" } }]
+				}`,
+				"B": `{
+					"metrics": [{ "type": "logs", "settings": { "limit": "3" } }]
+				}`,
+			}
+
+			hits := `{
+				"hits":{
+					"hits":[
+						{
+							"_id":"1",
+							"sort":[1675869055830, 10],
+							"_source":{ "@timestamp":"2023-02-08T15:10:55.830Z", "line":"first" }
+						},
+						{
+							"_id":"2",
+							"sort":[1675869055830, 5],
+							"_source":{ "@timestamp":"2023-02-08T15:10:55.830Z", "line":"second" }
+						}
+					]
+				}
+			}`
+			response := `{ "responses":[` + hits + `,` + hits + `] }`
+
+			result, err := parseTestResponse(targets, response)
+			require.NoError(t, err)
+			require.Len(t, result.Responses, 2)
+
+			for refID, frame := range map[string]*data.Frame{"A": result.Responses["A"].Frames[0], "B": result.Responses["B"].Frames[0]} {
+				customMeta, ok := frame.Meta.Custom.(map[string]interface{})
+				require.True(t, ok)
+				if refID == "A" {
+					require.Equal(t, []interface{}{float64(1675869055830), float64(5)}, customMeta["searchAfter"])
+				} else {
+					require.NotContains(t, customMeta, "searchAfter")
+				}
+			}
+		})
+
+		t.Run("Raw data query with highlight", func(t *testing.T) {
+			targets := map[string]string{
+				"A": `{
+					"metrics": [{ "type": "raw_data", "settings": { "highlight": true } }]
+				}`,
+			}
+
+			response := `{
+				"responses":[
+					{
+						"hits":{
+							"hits":[
+								{
+									"_id":"1",
+									"highlight": { "line": ["@HIGHLIGHT@error@/HIGHLIGHT@ in request"] },
+									"_source":{ "@timestamp":"2023-02-08T15:10:55.830Z", "line":"error in request" }
+								}
+							]
+						}
+					}
+				]
+			}`
+
+			result, err := parseTestResponse(targets, response)
+			require.NoError(t, err)
+			frame := result.Responses["A"].Frames[0]
+			require.Equal(t, map[string]interface{}{
+				"searchWords": []string{"error"},
+			}, frame.Meta.Custom)
+		})
+
+		t.Run("Field stats query", func(t *testing.T) {
+			targets := map[string]string{
+				"A": `{
+					"metrics": [{ "type": "field_stats", "settings": { "fields": ["host", "status"] } }]
+				}`,
+			}
+
+			response := `{
+				"responses":[
+					{
+						"hits":{ "hits":[] },
+						"aggregations":{
+							"0":{
+								"buckets":[
+									{ "key":"server-1", "doc_count":30 },
+									{ "key":"server-2", "doc_count":12 }
+								]
+							},
+							"1":{
+								"buckets":[
+									{ "key":200, "doc_count":40 },
+									{ "key":500, "doc_count":2 }
+								]
+							}
+						}
+					}
+				]
+			}`
+
+			result, err := parseTestResponse(targets, response)
+			require.NoError(t, err)
+			frames := result.Responses["A"].Frames
+			require.Len(t, frames, 2)
+
+			require.Equal(t, "host", frames[0].Name)
+			require.Equal(t, "A", frames[0].RefID)
+			require.Equal(t, data.VisType(data.VisTypeTable), frames[0].Meta.PreferredVisualization)
+			require.Equal(t, "server-1", frames[0].Fields[0].At(0))
+			require.Equal(t, "server-2", frames[0].Fields[0].At(1))
+			require.Equal(t, int64(30), frames[0].Fields[1].At(0))
+			require.Equal(t, int64(12), frames[0].Fields[1].At(1))
+
+			require.Equal(t, "status", frames[1].Name)
+			require.Equal(t, "200", frames[1].Fields[0].At(0))
+			require.Equal(t, "500", frames[1].Fields[0].At(1))
+			require.Equal(t, int64(40), frames[1].Fields[1].At(0))
+			require.Equal(t, int64(2), frames[1].Fields[1].At(1))
+		})
+
 		t.Run("Raw document query", func(t *testing.T) {
 			targets := map[string]string{
 				"A": `{
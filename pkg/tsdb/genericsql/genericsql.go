@@ -0,0 +1,162 @@
+package genericsql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
+)
+
+var logger = log.New("tsdb.genericsql")
+
+var (
+	errDriverRequired   = errors.New("the driver name is required")
+	errDriverNotAllowed = errors.New("the driver is not allowed by the generic_sql_allowed_drivers setting")
+	errDSNRequired      = errors.New("the data source name (DSN) is required")
+)
+
+type Service struct {
+	im instancemgmt.InstanceManager
+}
+
+func ProvideService(cfg *setting.Cfg) *Service {
+	return &Service{
+		im: datasource.NewInstanceManager(newInstanceSettings(cfg)),
+	}
+}
+
+// jsonData contains the settings of the data source in addition to the ones
+// shared by the SQL data sources.
+type jsonData struct {
+	Driver       string `json:"driver"`
+	MacroDialect string `json:"macroDialect"`
+}
+
+func newInstanceSettings(cfg *setting.Cfg) datasource.InstanceFactoryFunc {
+	return func(settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+		sqlJSONData, err := sqleng.NewJsonData(cfg, settings)
+		if err != nil {
+			return nil, err
+		}
+		var genericJSONData jsonData
+		if err := json.Unmarshal(settings.JSONData, &genericJSONData); err != nil {
+			return nil, fmt.Errorf("error reading settings: %w", err)
+		}
+
+		if err := checkDriver(genericJSONData.Driver, cfg.GenericSqlDatasourceAllowedDrivers); err != nil {
+			return &sqleng.DataSourceInstance{Err: err}, nil
+		}
+		dsn := settings.DecryptedSecureJSONData["dsn"]
+		if dsn == "" {
+			return &sqleng.DataSourceInstance{Err: errDSNRequired}, nil
+		}
+
+		dialectName := genericJSONData.MacroDialect
+		if dialectName == "" {
+			dialectName = defaultDialect(genericJSONData.Driver)
+		}
+		dialect, ok := macroDialects[dialectName]
+		if !ok {
+			return &sqleng.DataSourceInstance{Err: fmt.Errorf("unknown macro dialect %q", dialectName)}, nil
+		}
+
+		registerXormDriver(genericJSONData.Driver)
+
+		dsInfo := sqleng.DataSourceInfo{
+			JsonData:                sqlJSONData,
+			Database:                sqlJSONData.Database,
+			ID:                      settings.ID,
+			Updated:                 settings.Updated,
+			UID:                     settings.UID,
+			DecryptedSecureJSONData: settings.DecryptedSecureJSONData,
+		}
+
+		config := sqleng.DataPluginConfiguration{
+			DriverName:        genericJSONData.Driver,
+			ConnectionString:  dsn,
+			DSInfo:            dsInfo,
+			TimeColumnNames:   []string{"time", "time_sec"},
+			MetricColumnTypes: []string{"TEXT", "VARCHAR", "CHAR", "NVARCHAR", "NCHAR", "STRING", "String", "LowCardinality(String)"},
+			RowLimit:          cfg.DataProxyRowLimit,
+		}
+
+		handler, err := sqleng.NewQueryDataHandler(config, &genericQueryResultTransformer{}, newGenericMacroEngine(dialect), logger)
+		if err != nil {
+			logger.Error("Failed connecting to the database", "driver", genericJSONData.Driver, "error", err)
+			return nil, err
+		}
+		return &sqleng.DataSourceInstance{Handler: handler}, nil
+	}
+}
+
+// checkDriver checks that the driver is allowed and registered with
+// database/sql. Drivers are registered by the packages linked into the Grafana
+// binary.
+func checkDriver(driverName string, allowedDrivers []string) error {
+	if driverName == "" {
+		return errDriverRequired
+	}
+
+	allowed := false
+	for _, d := range allowedDrivers {
+		if d == driverName {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return errDriverNotAllowed
+	}
+
+	for _, d := range sql.Drivers() {
+		if d == driverName {
+			return nil
+		}
+	}
+	return fmt.Errorf("the driver %q is not registered", driverName)
+}
+
+// CheckHealth pings the database
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	i, err := s.im.Get(req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+	inst := i.(*sqleng.DataSourceInstance)
+	if inst.Err != nil {
+		return &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: inst.Err.Error()}, nil
+	}
+
+	dsHandler := inst.Handler
+	if err := dsHandler.Ping(); err != nil {
+		return &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: dsHandler.TransformQueryError(logger, err).Error()}, nil
+	}
+	return &backend.CheckHealthResult{Status: backend.HealthStatusOk, Message: "Database Connection OK"}, nil
+}
+
+func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	dsHandler, err := sqleng.GetDataSourceHandler(s.im, req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+	return dsHandler.QueryData(ctx, req)
+}
+
+// genericQueryResultTransformer detects the field types from the values, as
+// the column types reported by the drivers differ.
+type genericQueryResultTransformer struct {
+	sqleng.DynamicConverters
+}
+
+func (t *genericQueryResultTransformer) TransformQueryError(_ log.Logger, err error) error {
+	return err
+}
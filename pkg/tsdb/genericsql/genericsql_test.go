@@ -0,0 +1,127 @@
+package genericsql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+	"xorm.io/core"
+	"xorm.io/xorm"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// testDriver is a driver xorm does not know
+const testDriver = "genericsql-sqlite3"
+
+func init() {
+	sql.Register(testDriver, &sqlite3.SQLiteDriver{})
+}
+
+func TestGenericSQL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.db")
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+		CREATE TABLE metrics (time INTEGER, host TEXT, value REAL);
+		INSERT INTO metrics VALUES
+			(1600000000, 'a', 1.5),
+			(1600000030, 'b', 2),
+			(1600000600, 'a', 4);
+	`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	cfg := setting.NewCfg()
+	cfg.DataProxyRowLimit = 1000000
+	cfg.GenericSqlDatasourceAllowedDrivers = []string{testDriver, "not-registered"}
+	s := ProvideService(cfg)
+
+	pluginContext := func(id int64, driver string, dsn string) backend.PluginContext {
+		return backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+			ID:                      id,
+			JSONData:                mustJSON(t, map[string]interface{}{"driver": driver, "macroDialect": "ansi"}),
+			DecryptedSecureJSONData: map[string]string{"dsn": dsn},
+		}}
+	}
+	query := func(t *testing.T, rawSQL string, format string) backend.DataResponse {
+		t.Helper()
+		resp, err := s.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: pluginContext(1, testDriver, path),
+			Queries: []backend.DataQuery{{
+				RefID:         "A",
+				TimeRange:     backend.TimeRange{From: time.Unix(1600000000, 0), To: time.Unix(1600000300, 0)},
+				MaxDataPoints: 100,
+				Interval:      time.Second,
+				JSON:          mustJSON(t, map[string]interface{}{"rawSql": rawSQL, "format": format}),
+			}},
+		})
+		require.NoError(t, err)
+		return resp.Responses["A"]
+	}
+
+	t.Run("returns tables", func(t *testing.T) {
+		resp := query(t, "SELECT host, value FROM metrics ORDER BY time", "table")
+		require.NoError(t, resp.Error)
+		frame := resp.Frames[0]
+		require.Equal(t, 3, frame.Rows())
+		require.Equal(t, "a", *frame.Fields[0].At(0).(*string))
+		require.Equal(t, 2.0, *frame.Fields[1].At(1).(*float64))
+	})
+
+	t.Run("returns time series", func(t *testing.T) {
+		resp := query(t, "SELECT time, value, host AS metric FROM metrics WHERE $__unixEpochFilter(time) ORDER BY time", "time_series")
+		require.NoError(t, resp.Error)
+		frame := resp.Frames[0]
+		require.Equal(t, 2, frame.Rows())
+		require.Len(t, frame.Fields, 3)
+	})
+
+	t.Run("checks health", func(t *testing.T) {
+		res, err := s.CheckHealth(context.Background(), &backend.CheckHealthRequest{PluginContext: pluginContext(1, testDriver, path)})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+
+		for id, tc := range map[int64]struct {
+			driver  string
+			dsn     string
+			message string
+		}{
+			2: {driver: "sqlite3", dsn: path, message: errDriverNotAllowed.Error()},
+			3: {driver: testDriver, message: errDSNRequired.Error()},
+			4: {driver: "not-registered", dsn: path, message: `the driver "not-registered" is not registered`},
+			5: {message: errDriverRequired.Error()},
+		} {
+			res, err := s.CheckHealth(context.Background(), &backend.CheckHealthRequest{PluginContext: pluginContext(id, tc.driver, tc.dsn)})
+			require.NoError(t, err)
+			require.Equal(t, backend.HealthStatusError, res.Status)
+			require.Equal(t, tc.message, res.Message)
+		}
+	})
+}
+
+func TestRegisterXormDriver(t *testing.T) {
+	registerXormDriver(testDriver)
+
+	uri, err := core.QueryDriver(testDriver).Parse(testDriver, "metrics.db")
+	require.NoError(t, err)
+	require.Equal(t, xormDbType, uri.DbType)
+
+	engine, err := xorm.NewEngine(testDriver, filepath.Join(t.TempDir(), "metrics.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = engine.Close() })
+	require.Equal(t, xormDbType, engine.Dialect().DBType())
+}
+
+func mustJSON(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return b
+}
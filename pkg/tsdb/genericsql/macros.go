@@ -0,0 +1,202 @@
+package genericsql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
+)
+
+const rsIdentifier = `([_a-zA-Z0-9]+)`
+const sExpr = `\$` + rsIdentifier + `\(([^\)]*)\)`
+
+const (
+	dialectANSI       = "ansi"
+	dialectPostgres   = "postgres"
+	dialectMySQL      = "mysql"
+	dialectClickHouse = "clickhouse"
+)
+
+// macroDialect contains the SQL expressions the macros are built from, which
+// differ between databases.
+type macroDialect struct {
+	// timeAlias is the alias of the time column
+	timeAlias string
+	// timeLiteral returns a time value comparable to a time column
+	timeLiteral func(t time.Time) string
+	// epoch returns an expression converting a time column to a unix timestamp in seconds
+	epoch func(column string) string
+	// group returns an expression rounding a unix timestamp down to a multiple of seconds
+	group func(expr string, seconds float64) string
+}
+
+var macroDialects = map[string]macroDialect{
+	dialectANSI: {
+		timeAlias: `"time"`,
+		timeLiteral: func(t time.Time) string {
+			return fmt.Sprintf("TIMESTAMP '%s'", t.UTC().Format("2006-01-02 15:04:05.999999"))
+		},
+		epoch: func(column string) string {
+			return fmt.Sprintf("EXTRACT(EPOCH FROM %s)", column)
+		},
+		group: func(expr string, seconds float64) string {
+			return fmt.Sprintf("FLOOR(%s / %v) * %v", expr, seconds, seconds)
+		},
+	},
+	dialectPostgres: {
+		timeAlias: `"time"`,
+		timeLiteral: func(t time.Time) string {
+			return fmt.Sprintf("'%s'", t.UTC().Format(time.RFC3339Nano))
+		},
+		epoch: func(column string) string {
+			return fmt.Sprintf("extract(epoch from %s)", column)
+		},
+		group: func(expr string, seconds float64) string {
+			return fmt.Sprintf("floor(%s/%v)*%v", expr, seconds, seconds)
+		},
+	},
+	dialectMySQL: {
+		timeAlias: "time",
+		timeLiteral: func(t time.Time) string {
+			return fmt.Sprintf("FROM_UNIXTIME(%d)", t.UTC().Unix())
+		},
+		epoch: func(column string) string {
+			return fmt.Sprintf("UNIX_TIMESTAMP(%s)", column)
+		},
+		group: func(expr string, seconds float64) string {
+			return fmt.Sprintf("%s DIV %v * %v", expr, seconds, seconds)
+		},
+	},
+	dialectClickHouse: {
+		timeAlias: "time",
+		timeLiteral: func(t time.Time) string {
+			return fmt.Sprintf("toDateTime(%d)", t.UTC().Unix())
+		},
+		epoch: func(column string) string {
+			return fmt.Sprintf("toUnixTimestamp(%s)", column)
+		},
+		group: func(expr string, seconds float64) string {
+			return fmt.Sprintf("intDiv(%s, %v) * %v", expr, seconds, seconds)
+		},
+	},
+}
+
+// defaultDialect returns the macro dialect of the databases usually queried
+// with the driver.
+func defaultDialect(driverName string) string {
+	switch {
+	case driverName == "postgres" || driverName == "pgx" || strings.HasPrefix(driverName, "postgres"):
+		return dialectPostgres
+	case driverName == "mysql":
+		return dialectMySQL
+	case strings.HasPrefix(driverName, "clickhouse"):
+		return dialectClickHouse
+	default:
+		return dialectANSI
+	}
+}
+
+type genericMacroEngine struct {
+	*sqleng.SQLMacroEngineBase
+	dialect macroDialect
+}
+
+func newGenericMacroEngine(dialect macroDialect) sqleng.SQLMacroEngine {
+	return &genericMacroEngine{
+		SQLMacroEngineBase: sqleng.NewSQLMacroEngineBase(),
+		dialect:            dialect,
+	}
+}
+
+func (m *genericMacroEngine) Interpolate(query *backend.DataQuery, timeRange backend.TimeRange, sql string) (string, error) {
+	rExp := regexp.MustCompile(sExpr)
+	var macroError error
+
+	sql = m.ReplaceAllStringSubmatchFunc(rExp, sql, func(groups []string) string {
+		args := strings.Split(groups[2], ",")
+		for i, arg := range args {
+			args[i] = strings.Trim(arg, " ")
+		}
+		res, err := m.evaluateMacro(timeRange, query, groups[1], args)
+		if err != nil && macroError == nil {
+			macroError = err
+			return "macro_error()"
+		}
+		return res
+	})
+
+	if macroError != nil {
+		return "", macroError
+	}
+
+	return sql, nil
+}
+
+func (m *genericMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *backend.DataQuery, name string, args []string) (string, error) {
+	switch name {
+	case "__time":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s AS %s", args[0], m.dialect.timeAlias), nil
+	case "__timeEpoch":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s AS %s", m.dialect.epoch(args[0]), m.dialect.timeAlias), nil
+	case "__timeFilter":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s BETWEEN %s AND %s", args[0], m.dialect.timeLiteral(timeRange.From), m.dialect.timeLiteral(timeRange.To)), nil
+	case "__timeFrom":
+		return m.dialect.timeLiteral(timeRange.From), nil
+	case "__timeTo":
+		return m.dialect.timeLiteral(timeRange.To), nil
+	case "__timeGroup", "__unixEpochGroup":
+		if len(args) < 2 {
+			return "", fmt.Errorf("macro %v needs time column and interval and optional fill value", name)
+		}
+		interval, err := gtime.ParseInterval(strings.Trim(args[1], `'"`))
+		if err != nil {
+			return "", fmt.Errorf("error parsing interval %v", args[1])
+		}
+		if len(args) == 3 {
+			err := sqleng.SetupFillmode(query, interval, args[2])
+			if err != nil {
+				return "", err
+			}
+		}
+		if name == "__unixEpochGroup" {
+			return m.dialect.group(args[0], interval.Seconds()), nil
+		}
+		return m.dialect.group(m.dialect.epoch(args[0]), interval.Seconds()), nil
+	case "__timeGroupAlias", "__unixEpochGroupAlias":
+		tg, err := m.evaluateMacro(timeRange, query, strings.TrimSuffix(name, "Alias"), args)
+		if err == nil {
+			return tg + " AS " + m.dialect.timeAlias, nil
+		}
+		return "", err
+	case "__unixEpochFilter":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s >= %d AND %s <= %d", args[0], timeRange.From.UTC().Unix(), args[0], timeRange.To.UTC().Unix()), nil
+	case "__unixEpochNanoFilter":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s >= %d AND %s <= %d", args[0], timeRange.From.UTC().UnixNano(), args[0], timeRange.To.UTC().UnixNano()), nil
+	case "__unixEpochNanoFrom":
+		return fmt.Sprintf("%d", timeRange.From.UTC().UnixNano()), nil
+	case "__unixEpochNanoTo":
+		return fmt.Sprintf("%d", timeRange.To.UTC().UnixNano()), nil
+	default:
+		return "", fmt.Errorf("unknown macro %q", name)
+	}
+}
@@ -0,0 +1,92 @@
+package genericsql
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMacroEngine(t *testing.T) {
+	query := &backend.DataQuery{}
+	from := time.Date(2018, 4, 12, 18, 0, 0, 0, time.UTC)
+	to := from.Add(5 * time.Minute)
+	timeRange := backend.TimeRange{From: from, To: to}
+
+	tests := []struct {
+		dialect    string
+		timeFilter string
+		timeGroup  string
+		epoch      string
+	}{
+		{
+			dialect:    dialectANSI,
+			timeFilter: "created BETWEEN TIMESTAMP '2018-04-12 18:00:00' AND TIMESTAMP '2018-04-12 18:05:00'",
+			timeGroup:  `FLOOR(EXTRACT(EPOCH FROM created) / 300) * 300 AS "time"`,
+			epoch:      `EXTRACT(EPOCH FROM created) AS "time"`,
+		},
+		{
+			dialect:    dialectPostgres,
+			timeFilter: "created BETWEEN '2018-04-12T18:00:00Z' AND '2018-04-12T18:05:00Z'",
+			timeGroup:  `floor(extract(epoch from created)/300)*300 AS "time"`,
+			epoch:      `extract(epoch from created) AS "time"`,
+		},
+		{
+			dialect:    dialectMySQL,
+			timeFilter: fmt.Sprintf("created BETWEEN FROM_UNIXTIME(%d) AND FROM_UNIXTIME(%d)", from.Unix(), to.Unix()),
+			timeGroup:  "UNIX_TIMESTAMP(created) DIV 300 * 300 AS time",
+			epoch:      "UNIX_TIMESTAMP(created) AS time",
+		},
+		{
+			dialect:    dialectClickHouse,
+			timeFilter: fmt.Sprintf("created BETWEEN toDateTime(%d) AND toDateTime(%d)", from.Unix(), to.Unix()),
+			timeGroup:  "intDiv(toUnixTimestamp(created), 300) * 300 AS time",
+			epoch:      "toUnixTimestamp(created) AS time",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.dialect, func(t *testing.T) {
+			engine := newGenericMacroEngine(macroDialects[tc.dialect])
+
+			sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeFilter(created)")
+			require.NoError(t, err)
+			require.Equal(t, "WHERE "+tc.timeFilter, sql)
+
+			sql, err = engine.Interpolate(query, timeRange, "SELECT $__timeGroupAlias(created, '5m')")
+			require.NoError(t, err)
+			require.Equal(t, "SELECT "+tc.timeGroup, sql)
+
+			sql, err = engine.Interpolate(query, timeRange, "SELECT $__timeEpoch(created)")
+			require.NoError(t, err)
+			require.Equal(t, "SELECT "+tc.epoch, sql)
+		})
+	}
+
+	t.Run("interpolates unix epoch macros the same way in all dialects", func(t *testing.T) {
+		for name, dialect := range macroDialects {
+			engine := newGenericMacroEngine(dialect)
+			sql, err := engine.Interpolate(query, timeRange, "WHERE $__unixEpochFilter(time) AND $__unixEpochNanoFilter(time_ns)")
+			require.NoError(t, err, name)
+			require.Equal(t, fmt.Sprintf("WHERE time >= %d AND time <= %d AND time_ns >= %d AND time_ns <= %d", from.Unix(), to.Unix(), from.UnixNano(), to.UnixNano()), sql, name)
+		}
+	})
+
+	t.Run("fails on unknown macros and missing arguments", func(t *testing.T) {
+		engine := newGenericMacroEngine(macroDialects[dialectANSI])
+		_, err := engine.Interpolate(query, timeRange, "SELECT $__unknown(time)")
+		require.Error(t, err)
+		_, err = engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time)")
+		require.Error(t, err)
+	})
+}
+
+func TestDefaultDialect(t *testing.T) {
+	require.Equal(t, dialectPostgres, defaultDialect("postgres"))
+	require.Equal(t, dialectPostgres, defaultDialect("pgx"))
+	require.Equal(t, dialectMySQL, defaultDialect("mysql"))
+	require.Equal(t, dialectClickHouse, defaultDialect("clickhouse"))
+	require.Equal(t, dialectANSI, defaultDialect("duckdb"))
+}
@@ -0,0 +1,105 @@
+package genericsql
+
+import (
+	"errors"
+
+	"xorm.io/core"
+
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
+)
+
+// xormDbType is the xorm database type of the drivers xorm does not know.
+const xormDbType core.DbType = "genericsql"
+
+var errXormNotSupported = errors.New("not supported by generic SQL data sources")
+
+// registerXormDriver registers the drivers xorm does not know with xorm, which
+// needs them to create the engine sqleng queries through.
+func registerXormDriver(driverName string) {
+	sqleng.XormDriverMu.Lock()
+	defer sqleng.XormDriverMu.Unlock()
+
+	if core.QueryDialect(xormDbType) == nil {
+		core.RegisterDialect(xormDbType, func() core.Dialect { return &xormDialect{} })
+	}
+	if core.QueryDriver(driverName) == nil {
+		core.RegisterDriver(driverName, &xormDriver{})
+	}
+}
+
+// xormDriver parses the connection strings of the drivers xorm does not know.
+// The connection string is passed to the driver as is, so it is not parsed.
+type xormDriver struct{}
+
+func (d *xormDriver) Parse(driverName, dataSourceName string) (*core.Uri, error) {
+	return &core.Uri{DbType: xormDbType}, nil
+}
+
+// xormDialect is the xorm dialect of the drivers xorm does not know. Queries
+// are run on the database/sql connection of the engine, so it only implements
+// what is needed to create the engine and supports none of the xorm features
+// depending on the database.
+type xormDialect struct {
+	core.Base
+}
+
+func (d *xormDialect) Init(db *core.DB, uri *core.Uri, driverName, dataSourceName string) error {
+	return d.Base.Init(db, d, uri, driverName, dataSourceName)
+}
+
+func (d *xormDialect) SqlType(c *core.Column) string {
+	return c.SQLType.Name
+}
+
+func (d *xormDialect) IsReserved(string) bool {
+	return false
+}
+
+// Quote quotes identifiers like ANSI SQL.
+func (d *xormDialect) Quote(name string) string {
+	return `"` + name + `"`
+}
+
+func (d *xormDialect) AutoIncrStr() string {
+	return ""
+}
+
+func (d *xormDialect) SupportInsertMany() bool {
+	return false
+}
+
+func (d *xormDialect) SupportEngine() bool {
+	return false
+}
+
+func (d *xormDialect) SupportCharset() bool {
+	return false
+}
+
+func (d *xormDialect) IndexOnTable() bool {
+	return false
+}
+
+func (d *xormDialect) IndexCheckSql(tableName, idxName string) (string, []interface{}) {
+	return "", nil
+}
+
+func (d *xormDialect) TableCheckSql(tableName string) (string, []interface{}) {
+	return "", nil
+}
+
+func (d *xormDialect) GetColumns(tableName string) ([]string, map[string]*core.Column, error) {
+	return nil, nil, errXormNotSupported
+}
+
+func (d *xormDialect) GetTables() ([]*core.Table, error) {
+	return nil, errXormNotSupported
+}
+
+func (d *xormDialect) GetIndexes(tableName string) (map[string]*core.Index, error) {
+	return nil, errXormNotSupported
+}
+
+func (d *xormDialect) Filters() []core.Filter {
+	return nil
+}
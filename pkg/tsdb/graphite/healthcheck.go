@@ -0,0 +1,54 @@
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// CheckHealth finds the metrics at the root of the Graphite metric tree
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	logger := logger.FromContext(ctx)
+
+	dsInfo, err := s.getDSInfo(req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+
+	graphiteReq, err := s.createResourceRequest(ctx, dsInfo, "metrics/find", &backend.CallResourceRequest{
+		Method: http.MethodGet,
+		URL:    "metrics/find?query=*",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := dsInfo.HTTPClient.Do(graphiteReq)
+	if err != nil {
+		return healthCheckError(logger, err), nil
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if res.StatusCode/100 != 2 {
+		return healthCheckError(logger, fmt.Errorf("request failed, status: %s", res.Status)), nil
+	}
+	var metrics []interface{}
+	if err := json.NewDecoder(res.Body).Decode(&metrics); err != nil {
+		return healthCheckError(logger, fmt.Errorf("invalid response from the Graphite metrics API: %w", err)), nil
+	}
+	return &backend.CheckHealthResult{Status: backend.HealthStatusOk, Message: "Data source is working"}, nil
+}
+
+func healthCheckError(logger log.Logger, err error) *backend.CheckHealthResult {
+	logger.Warn("Graphite health check failed", "error", err)
+	return &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: err.Error()}
+}
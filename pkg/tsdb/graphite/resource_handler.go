@@ -0,0 +1,135 @@
+package graphite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// resourcePaths are the paths of the Graphite API that can be called as
+// resources, with the methods they allow. Tag values are fetched with the
+// tags/<tag> path.
+var resourcePaths = map[string][]string{
+	"metrics/find":             {http.MethodGet, http.MethodPost},
+	"metrics/expand":           {http.MethodGet, http.MethodPost},
+	"tags":                     {http.MethodGet},
+	"tags/autoComplete/tags":   {http.MethodGet},
+	"tags/autoComplete/values": {http.MethodGet},
+	"functions":                {http.MethodGet},
+	"version":                  {http.MethodGet},
+}
+
+// maxResourceBodySize is the maximum size of the form data of a resource request
+const maxResourceBodySize = 1 << 20
+
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	logger := logger.FromContext(ctx)
+
+	resourcePath := strings.Trim(req.Path, "/")
+	if !isAllowedResource(resourcePath, req.Method) {
+		return sender.Send(&backend.CallResourceResponse{
+			Status: http.StatusNotFound,
+			Body:   []byte(fmt.Sprintf(`{"message": "invalid resource %s %s"}`, req.Method, resourcePath)),
+		})
+	}
+
+	dsInfo, err := s.getDSInfo(req.PluginContext)
+	if err != nil {
+		return err
+	}
+
+	graphiteReq, err := s.createResourceRequest(ctx, dsInfo, resourcePath, req)
+	if err != nil {
+		return err
+	}
+
+	ctx, span := s.tracer.Start(ctx, "graphite resource")
+	defer span.End()
+	span.SetAttributes("path", resourcePath, attribute.Key("path").String(resourcePath))
+	span.SetAttributes("datasource_id", dsInfo.Id, attribute.Key("datasource_id").Int64(dsInfo.Id))
+	s.tracer.Inject(ctx, graphiteReq.Header, span)
+
+	res, err := dsInfo.HTTPClient.Do(graphiteReq)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	headers := map[string][]string{}
+	if contentType := res.Header.Get("Content-Type"); contentType != "" {
+		headers["Content-Type"] = []string{contentType}
+	}
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  res.StatusCode,
+		Headers: headers,
+		Body:    body,
+	})
+}
+
+func isAllowedResource(resourcePath string, method string) bool {
+	methods, ok := resourcePaths[resourcePath]
+	if !ok {
+		// the values of a tag
+		tag := strings.TrimPrefix(resourcePath, "tags/")
+		if tag == resourcePath || tag == "" || tag == "." || tag == ".." || strings.Contains(tag, "/") {
+			return false
+		}
+		methods = []string{http.MethodGet}
+	}
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) createResourceRequest(ctx context.Context, dsInfo *datasourceInfo, resourcePath string, req *backend.CallResourceRequest) (*http.Request, error) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, resourcePath)
+
+	reqURL, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resource URL: %w", err)
+	}
+	u.RawQuery = reqURL.RawQuery
+
+	var body io.Reader
+	if req.Method == http.MethodPost {
+		if len(req.Body) > maxResourceBodySize {
+			return nil, fmt.Errorf("resource request body exceeds %d bytes", maxResourceBodySize)
+		}
+		body = bytes.NewReader(req.Body)
+	}
+
+	graphiteReq, err := http.NewRequestWithContext(ctx, req.Method, u.String(), body)
+	if err != nil {
+		logger.Info("Failed to create request", "error", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if req.Method == http.MethodPost {
+		graphiteReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	return graphiteReq, nil
+}
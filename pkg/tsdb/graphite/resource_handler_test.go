@@ -0,0 +1,125 @@
+package graphite
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestCallResource(t *testing.T) {
+	var requests []*http.Request
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r)
+		bodies = append(bodies, string(body))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"text": "cpu"}]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	s := ProvideService(httpclient.NewProvider(), tracing.InitializeTracerForTest())
+	pluginContext := backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1, URL: srv.URL + "/graphite"}}
+
+	call := func(t *testing.T, method, path, url string, body string) *backend.CallResourceResponse {
+		t.Helper()
+		sender := &fakeSender{}
+		err := s.CallResource(context.Background(), &backend.CallResourceRequest{
+			PluginContext: pluginContext,
+			Method:        method,
+			Path:          path,
+			URL:           url,
+			Body:          []byte(body),
+		}, sender)
+		require.NoError(t, err)
+		return sender.resp
+	}
+
+	t.Run("forwards metrics/find requests", func(t *testing.T) {
+		requests, bodies = nil, nil
+		resp := call(t, http.MethodPost, "metrics/find", "metrics/find?from=1&until=2", "query=servers.*")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `[{"text": "cpu"}]`, string(resp.Body))
+		require.Equal(t, []string{"application/json"}, resp.Headers["Content-Type"])
+
+		require.Len(t, requests, 1)
+		require.Equal(t, http.MethodPost, requests[0].Method)
+		require.Equal(t, "/graphite/metrics/find", requests[0].URL.Path)
+		require.Equal(t, "from=1&until=2", requests[0].URL.RawQuery)
+		require.Equal(t, "application/x-www-form-urlencoded", requests[0].Header.Get("Content-Type"))
+		require.Equal(t, "query=servers.*", bodies[0])
+	})
+
+	t.Run("forwards tag requests", func(t *testing.T) {
+		requests, bodies = nil, nil
+		call(t, http.MethodGet, "tags/autoComplete/values", "tags/autoComplete/values?expr=name%3Dcpu&tag=host", "")
+		call(t, http.MethodGet, "tags/host", "tags/host", "")
+		require.Len(t, requests, 2)
+		require.Equal(t, "/graphite/tags/autoComplete/values", requests[0].URL.Path)
+		require.Equal(t, "name=cpu", requests[0].URL.Query().Get("expr"))
+		require.Equal(t, "/graphite/tags/host", requests[1].URL.Path)
+	})
+
+	t.Run("rejects other paths and methods", func(t *testing.T) {
+		requests, bodies = nil, nil
+		for _, tc := range []struct{ method, path string }{
+			{http.MethodGet, "render"},
+			{http.MethodPost, "functions"},
+			{http.MethodDelete, "tags/host"},
+			{http.MethodGet, "tags/.."},
+			{http.MethodGet, "tags/host/values"},
+			{http.MethodGet, "events/get_data"},
+		} {
+			resp := call(t, tc.method, tc.path, tc.path, "")
+			require.Equal(t, http.StatusNotFound, resp.Status, tc.path)
+		}
+		require.Empty(t, requests)
+	})
+}
+
+func TestCheckHealth(t *testing.T) {
+	status := http.StatusOK
+	body := `[{"text": "servers"}]`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/metrics/find", r.URL.Path)
+		require.Equal(t, "*", r.URL.Query().Get("query"))
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	s := ProvideService(httpclient.NewProvider(), tracing.InitializeTracerForTest())
+	req := &backend.CheckHealthRequest{PluginContext: backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1, URL: srv.URL}}}
+
+	res, err := s.CheckHealth(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, backend.HealthStatusOk, res.Status)
+
+	body = "<html></html>"
+	res, err = s.CheckHealth(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, backend.HealthStatusError, res.Status)
+
+	status = http.StatusUnauthorized
+	res, err = s.CheckHealth(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, backend.HealthStatusError, res.Status)
+	require.Equal(t, "request failed, status: 401 Unauthorized", res.Message)
+}
+
+type fakeSender struct {
+	resp *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(resp *backend.CallResourceResponse) error {
+	s.resp = resp
+	return nil
+}
@@ -2,15 +2,19 @@ package loki
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"go.opentelemetry.io/otel/attribute"
@@ -52,6 +56,11 @@ type datasourceInfo struct {
 	HTTPClient *http.Client
 	URL        string
 
+	// the dialer and headers of the tail websocket connections, which carry
+	// the TLS settings, basic auth and custom headers of the data source
+	tailDialer  *websocket.Dialer
+	tailHeaders http.Header
+
 	// open streams
 	streams   map[string]data.FrameJSONCache
 	streamsMu sync.RWMutex
@@ -81,10 +90,29 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			return nil, err
 		}
 
+		tlsConfig, err := sdkhttpclient.GetTLSConfig(opts)
+		if err != nil {
+			return nil, err
+		}
+		tailHeaders := http.Header{}
+		for name, value := range opts.Headers {
+			tailHeaders.Set(name, value)
+		}
+		if opts.BasicAuth != nil {
+			auth := base64.StdEncoding.EncodeToString([]byte(opts.BasicAuth.User + ":" + opts.BasicAuth.Password))
+			tailHeaders.Set("Authorization", "Basic "+auth)
+		}
+
 		model := &datasourceInfo{
 			HTTPClient: client,
 			URL:        settings.URL,
-			streams:    make(map[string]data.FrameJSONCache),
+			tailDialer: &websocket.Dialer{
+				Proxy:            http.ProxyFromEnvironment,
+				HandshakeTimeout: 45 * time.Second,
+				TLSClientConfig:  tlsConfig,
+			},
+			tailHeaders: tailHeaders,
+			streams:     make(map[string]data.FrameJSONCache),
 		}
 		return model, nil
 	}
@@ -105,6 +133,13 @@ func callResource(ctx context.Context, req *backend.CallResourceRequest, sender
 	if req.Method != "GET" {
 		return fmt.Errorf("invalid resource method: %s", req.Method)
 	}
+	if resourcePath := strings.Trim(req.Path, "/"); isStructuredResource(resourcePath) {
+		ctx, span := tracer.Start(ctx, "datasource.loki.CallResource")
+		span.SetAttributes("path", resourcePath, attribute.Key("path").String(resourcePath))
+		defer span.End()
+
+		return callStructuredResource(ctx, req, sender, newLokiAPI(dsInfo.HTTPClient, dsInfo.URL, plog), resourcePath)
+	}
 	if (!strings.HasPrefix(url, "labels?")) &&
 		(!strings.HasPrefix(url, "label/")) && // the `/label/$label_name/values` form
 		(!strings.HasPrefix(url, "series?")) &&
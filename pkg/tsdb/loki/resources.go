@@ -0,0 +1,453 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logfmt/logfmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// The structured resources return the data of the Loki label and series
+// APIs without the Loki response envelope, and the fields parsers would
+// extract from log lines. Unlike the other resources, their paths are not
+// Loki API paths.
+const (
+	labelNamesResource     = "label-names"
+	labelValuesResource    = "label-values"
+	seriesLabelsResource   = "series-labels"
+	detectedFieldsResource = "detected-fields"
+)
+
+const (
+	// defaultDetectedFieldsLineLimit is the number of log lines the fields are
+	// detected in when the request has no limit
+	defaultDetectedFieldsLineLimit = 100
+	maxDetectedFieldsLineLimit     = 1000
+)
+
+var labelNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// errBadResourceRequest is returned for invalid resource parameters, which are
+// reported to the client with a 400 response.
+var errBadResourceRequest = errors.New("bad request")
+
+type lokiDataResponse struct {
+	Status string          `json:"status"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// detectedField is a field extracted from log lines by the json or logfmt
+// parsers.
+type detectedField struct {
+	Label       string   `json:"label"`
+	Type        string   `json:"type"`
+	Cardinality int      `json:"cardinality"`
+	Parsers     []string `json:"parsers"`
+
+	values map[string]bool
+}
+
+type detectedFieldsResponse struct {
+	Fields []*detectedField `json:"fields"`
+	Limit  int              `json:"limit"`
+}
+
+func isStructuredResource(resourcePath string) bool {
+	switch resourcePath {
+	case labelNamesResource, labelValuesResource, seriesLabelsResource, detectedFieldsResource:
+		return true
+	}
+	return false
+}
+
+func callStructuredResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender, api *LokiAPI, resourcePath string) error {
+	reqURL, err := url.Parse(req.URL)
+	if err != nil {
+		return fmt.Errorf("invalid resource URL: %w", err)
+	}
+	params := reqURL.Query()
+
+	var result interface{}
+	switch resourcePath {
+	case labelNamesResource:
+		result, err = labelNames(ctx, api, params)
+	case labelValuesResource:
+		result, err = labelValues(ctx, api, params)
+	case seriesLabelsResource:
+		result, err = seriesLabels(ctx, api, params)
+	case detectedFieldsResource:
+		result, err = detectedFields(ctx, api, params)
+	}
+
+	var lokiErr *lokiResourceError
+	switch {
+	case errors.Is(err, errBadResourceRequest):
+		return sendResourceError(sender, http.StatusBadRequest, err)
+	case errors.As(err, &lokiErr):
+		return sender.Send(&backend.CallResourceResponse{
+			Status:  lokiErr.status,
+			Headers: map[string][]string{"content-type": {"application/json"}},
+			Body:    lokiErr.body,
+		})
+	case err != nil:
+		return err
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  http.StatusOK,
+		Headers: map[string][]string{"content-type": {"application/json"}},
+		Body:    body,
+	})
+}
+
+func sendResourceError(sender backend.CallResourceResponseSender, status int, err error) error {
+	body, marshalErr := json.Marshal(lokiResponseError{Message: err.Error()})
+	if marshalErr != nil {
+		return marshalErr
+	}
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  status,
+		Headers: map[string][]string{"content-type": {"application/json"}},
+		Body:    body,
+	})
+}
+
+// lokiResourceError is a client error returned by Loki, which is passed on to
+// the client.
+type lokiResourceError struct {
+	status int
+	body   []byte
+}
+
+func (e *lokiResourceError) Error() string {
+	return string(e.body)
+}
+
+// timeRangeParams returns the start and end parameters of a request, which
+// are unix timestamps in nanoseconds.
+func timeRangeParams(params url.Values) (url.Values, error) {
+	qs := url.Values{}
+	for _, name := range []string{"start", "end"} {
+		value := params.Get(name)
+		if value == "" {
+			continue
+		}
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid %s %q", errBadResourceRequest, name, value)
+		}
+		qs.Set(name, value)
+	}
+	return qs, nil
+}
+
+// queryLokiData calls a Loki API path and decodes the data of its response.
+func queryLokiData(ctx context.Context, api *LokiAPI, apiPath string, qs url.Values, v interface{}) error {
+	rawResponse, err := api.RawQuery(ctx, apiPath+"?"+qs.Encode())
+	if err != nil {
+		return err
+	}
+	if rawResponse.Status/100 != 2 {
+		return &lokiResourceError{status: rawResponse.Status, body: rawResponse.Body}
+	}
+
+	var res lokiDataResponse
+	if err := json.Unmarshal(rawResponse.Body, &res); err != nil {
+		return fmt.Errorf("failed to parse Loki response: %w", err)
+	}
+	if len(res.Data) == 0 || string(res.Data) == "null" {
+		return nil
+	}
+	return json.Unmarshal(res.Data, v)
+}
+
+// labelNames returns the sorted names of the labels of the streams in a time
+// range, or of the streams matching the query parameter.
+func labelNames(ctx context.Context, api *LokiAPI, params url.Values) ([]string, error) {
+	qs, err := timeRangeParams(params)
+	if err != nil {
+		return nil, err
+	}
+	if query := params.Get("query"); query != "" {
+		qs.Set("query", query)
+	}
+
+	names := []string{}
+	if err := queryLokiData(ctx, api, "/loki/api/v1/labels", qs, &names); err != nil {
+		return nil, err
+	}
+	return sortedUnique(names), nil
+}
+
+// labelValues returns the sorted values of the label parameter in a time
+// range, or in the streams matching the query parameter.
+func labelValues(ctx context.Context, api *LokiAPI, params url.Values) ([]string, error) {
+	label := params.Get("label")
+	if !labelNameRegex.MatchString(label) {
+		return nil, fmt.Errorf("%w: invalid label name %q", errBadResourceRequest, label)
+	}
+	qs, err := timeRangeParams(params)
+	if err != nil {
+		return nil, err
+	}
+	if query := params.Get("query"); query != "" {
+		qs.Set("query", query)
+	}
+
+	values := []string{}
+	if err := queryLokiData(ctx, api, "/loki/api/v1/label/"+label+"/values", qs, &values); err != nil {
+		return nil, err
+	}
+	return sortedUnique(values), nil
+}
+
+// seriesLabels returns the sorted values of each label of the streams matching
+// the match[] parameters.
+func seriesLabels(ctx context.Context, api *LokiAPI, params url.Values) (map[string][]string, error) {
+	matchers := params["match[]"]
+	if len(matchers) == 0 {
+		return nil, fmt.Errorf("%w: the match[] parameter is required", errBadResourceRequest)
+	}
+	qs, err := timeRangeParams(params)
+	if err != nil {
+		return nil, err
+	}
+	qs["match[]"] = matchers
+
+	series := []map[string]string{}
+	if err := queryLokiData(ctx, api, "/loki/api/v1/series", qs, &series); err != nil {
+		return nil, err
+	}
+
+	labels := map[string][]string{}
+	for _, s := range series {
+		for name, value := range s {
+			labels[name] = append(labels[name], value)
+		}
+	}
+	for name, values := range labels {
+		labels[name] = sortedUnique(values)
+	}
+	return labels, nil
+}
+
+// detectedFields returns the fields the json and logfmt parsers extract from
+// the most recent lines of a log query, with the type of their values.
+func detectedFields(ctx context.Context, api *LokiAPI, params url.Values) (*detectedFieldsResponse, error) {
+	expr := params.Get("query")
+	if expr == "" {
+		return nil, fmt.Errorf("%w: the query parameter is required", errBadResourceRequest)
+	}
+
+	limit := defaultDetectedFieldsLineLimit
+	if value := params.Get("limit"); value != "" {
+		l, err := strconv.Atoi(value)
+		if err != nil || l <= 0 || l > maxDetectedFieldsLineLimit {
+			return nil, fmt.Errorf("%w: invalid limit %q, it must be between 1 and %d", errBadResourceRequest, value, maxDetectedFieldsLineLimit)
+		}
+		limit = l
+	}
+
+	end := time.Now()
+	start := end.Add(-time.Hour)
+	for _, p := range []struct {
+		name string
+		t    *time.Time
+	}{{"start", &start}, {"end", &end}} {
+		value := params.Get(p.name)
+		if value == "" {
+			continue
+		}
+		ns, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid %s %q", errBadResourceRequest, p.name, value)
+		}
+		*p.t = time.Unix(0, ns)
+	}
+
+	frames, err := api.DataQuery(ctx, lokiQuery{
+		Expr:                expr,
+		QueryType:           QueryTypeRange,
+		Direction:           DirectionBackward,
+		MaxLines:            limit,
+		Start:               start,
+		End:                 end,
+		Step:                time.Second,
+		SupportingQueryType: SupportingQueryNone,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errBadResourceRequest, err)
+	}
+
+	var lines []string
+	for _, frame := range frames {
+		lineField, _ := frame.FieldByName("Line")
+		if lineField == nil || lineField.Type() != data.FieldTypeString {
+			continue
+		}
+		for i := 0; i < lineField.Len(); i++ {
+			lines = append(lines, lineField.At(i).(string))
+		}
+	}
+
+	return &detectedFieldsResponse{Fields: detectFields(lines), Limit: limit}, nil
+}
+
+// detectFields extracts the fields of log lines, the way the Loki json and
+// logfmt parsers name them.
+func detectFields(lines []string) []*detectedField {
+	fields := map[string]*detectedField{}
+	add := func(parser string, key string, value string) {
+		f, ok := fields[key]
+		if !ok {
+			f = &detectedField{Label: key, values: map[string]bool{}}
+			fields[key] = f
+		}
+		f.values[value] = true
+		for _, p := range f.Parsers {
+			if p == parser {
+				return
+			}
+		}
+		f.Parsers = append(f.Parsers, parser)
+	}
+
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "{") {
+			var object map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &object); err == nil {
+				flattenJSON("", object, func(key, value string) { add("json", key, value) })
+				continue
+			}
+		}
+
+		keyvals := map[string]string{}
+		dec := logfmt.NewDecoder(strings.NewReader(line))
+		for dec.ScanRecord() {
+			for dec.ScanKeyval() {
+				// bare words are not fields
+				if dec.Value() != nil {
+					keyvals[sanitizeLabelKey(string(dec.Key()), true)] = string(dec.Value())
+				}
+			}
+		}
+		if dec.Err() != nil {
+			continue
+		}
+		for key, value := range keyvals {
+			add("logfmt", key, value)
+		}
+	}
+
+	result := make([]*detectedField, 0, len(fields))
+	for _, f := range fields {
+		f.Cardinality = len(f.values)
+		f.Type = detectFieldType(f.values)
+		sort.Strings(f.Parsers)
+		result = append(result, f)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Label < result[j].Label
+	})
+	return result
+}
+
+// flattenJSON calls fn with the values of an object, nested keys are joined
+// with underscores. Arrays are skipped, like by the json parser.
+func flattenJSON(prefix string, object map[string]interface{}, fn func(key, value string)) {
+	for k, v := range object {
+		key := sanitizeLabelKey(k, prefix == "")
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		switch value := v.(type) {
+		case map[string]interface{}:
+			flattenJSON(key, value, fn)
+		case []interface{}:
+		case nil:
+			fn(key, "")
+		case string:
+			fn(key, value)
+		default:
+			fn(key, fmt.Sprint(value))
+		}
+	}
+}
+
+// sanitizeLabelKey replaces the characters that are not valid in label names
+// with underscores.
+func sanitizeLabelKey(key string, isPrefix bool) string {
+	if len(key) == 0 {
+		return key
+	}
+	key = strings.TrimSpace(key)
+	if len(key) == 0 {
+		return key
+	}
+	if isPrefix && key[0] >= '0' && key[0] <= '9' {
+		key = "_" + key
+	}
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_' || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+var bytesRegex = regexp.MustCompile(`^\d+(\.\d+)?\s?[KMGTPE]?i?B$`)
+
+// detectFieldType returns the type all the values of a field have, in the
+// order int, float, boolean, duration and bytes, or string.
+func detectFieldType(values map[string]bool) string {
+	checks := []struct {
+		name  string
+		check func(string) bool
+	}{
+		{"int", func(v string) bool { _, err := strconv.ParseInt(v, 10, 64); return err == nil }},
+		{"float", func(v string) bool { _, err := strconv.ParseFloat(v, 64); return err == nil }},
+		{"boolean", func(v string) bool { return v == "true" || v == "false" }},
+		{"duration", func(v string) bool { _, err := time.ParseDuration(v); return err == nil }},
+		{"bytes", bytesRegex.MatchString},
+	}
+	for _, c := range checks {
+		matches := len(values) > 0
+		for v := range values {
+			if !c.check(v) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return c.name
+		}
+	}
+	return "string"
+}
+
+func sortedUnique(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,164 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+)
+
+func TestStructuredResources(t *testing.T) {
+	var requests []*http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/loki/api/v1/labels":
+			_, _ = w.Write([]byte(`{"status": "success", "data": ["job", "app", "job"]}`))
+		case "/loki/api/v1/label/app/values":
+			_, _ = w.Write([]byte(`{"status": "success", "data": ["cart", "checkout"]}`))
+		case "/loki/api/v1/label/missing/values":
+			_, _ = w.Write([]byte(`{"status": "success"}`))
+		case "/loki/api/v1/series":
+			_, _ = w.Write([]byte(`{"status": "success", "data": [
+				{"app": "cart", "level": "info"},
+				{"app": "checkout", "level": "info"},
+				{"app": "cart", "level": "error"}
+			]}`))
+		case "/loki/api/v1/query_range":
+			_, _ = w.Write([]byte(`{"status": "success", "data": {"resultType": "streams", "result": [
+				{"stream": {"app": "cart"}, "values": [
+					["1600000002000000000", "level=info msg=\"added item\" duration=12ms size=1.5KB"],
+					["1600000001000000000", "level=error msg=failed duration=3s"],
+					["1600000000000000000", "{\"user\": {\"id\": 12, \"name\": \"a\"}, \"tags\": [\"a\"], \"level\": \"info\"}"],
+					["1599999999000000000", "plain text line"]
+				]}
+			]}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message": "invalid request"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	s := ProvideService(httpclient.NewProvider(), featuremgmt.WithFeatures(), tracing.InitializeTracerForTest())
+	call := func(t *testing.T, url string, path string) *backend.CallResourceResponse {
+		t.Helper()
+		sender := &fakeSender{}
+		err := s.CallResource(context.Background(), &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1, URL: srv.URL}},
+			Method:        http.MethodGet,
+			Path:          path,
+			URL:           url,
+		}, sender)
+		require.NoError(t, err)
+		return sender.resp
+	}
+
+	t.Run("returns label names", func(t *testing.T) {
+		requests = nil
+		resp := call(t, "label-names?start=1600000000000000000&end=1600000300000000000", "label-names")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `["app", "job"]`, string(resp.Body))
+		require.Equal(t, "1600000000000000000", requests[0].URL.Query().Get("start"))
+		require.Equal(t, "1600000300000000000", requests[0].URL.Query().Get("end"))
+	})
+
+	t.Run("returns label values", func(t *testing.T) {
+		requests = nil
+		resp := call(t, `label-values?label=app&query=%7Bjob%3D%22a%22%7D`, "label-values")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `["cart", "checkout"]`, string(resp.Body))
+		require.Equal(t, `{job="a"}`, requests[0].URL.Query().Get("query"))
+
+		resp = call(t, "label-values?label=missing", "label-values")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `[]`, string(resp.Body))
+	})
+
+	t.Run("returns the values of the labels of series", func(t *testing.T) {
+		requests = nil
+		resp := call(t, `series-labels?match[]=%7Bapp%3D~%22.%2B%22%7D`, "series-labels")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `{"app": ["cart", "checkout"], "level": ["error", "info"]}`, string(resp.Body))
+		require.Equal(t, []string{`{app=~".+"}`}, requests[0].URL.Query()["match[]"])
+	})
+
+	t.Run("returns detected fields", func(t *testing.T) {
+		requests = nil
+		resp := call(t, `detected-fields?query=%7Bapp%3D%22cart%22%7D&limit=10&start=1599999000000000000&end=1600000300000000000`, "detected-fields")
+		require.Equal(t, http.StatusOK, resp.Status)
+
+		var res struct {
+			Fields []struct {
+				Label       string   `json:"label"`
+				Type        string   `json:"type"`
+				Cardinality int      `json:"cardinality"`
+				Parsers     []string `json:"parsers"`
+			} `json:"fields"`
+			Limit int `json:"limit"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body, &res))
+		require.Equal(t, 10, res.Limit)
+
+		fields := map[string]string{}
+		for _, f := range res.Fields {
+			fields[f.Label] = f.Type
+		}
+		require.Equal(t, map[string]string{
+			"level":     "string",
+			"msg":       "string",
+			"duration":  "duration",
+			"size":      "bytes",
+			"user_id":   "int",
+			"user_name": "string",
+		}, fields)
+		require.Equal(t, "duration", res.Fields[0].Label)
+		require.Equal(t, 2, res.Fields[0].Cardinality)
+		require.Equal(t, "level", res.Fields[1].Label)
+		require.Equal(t, []string{"json", "logfmt"}, res.Fields[1].Parsers)
+
+		params := requests[0].URL.Query()
+		require.Equal(t, `{app="cart"}`, params.Get("query"))
+		require.Equal(t, "10", params.Get("limit"))
+		require.Equal(t, "backward", params.Get("direction"))
+		require.Equal(t, "1599999000000000000", params.Get("start"))
+	})
+
+	t.Run("rejects invalid parameters", func(t *testing.T) {
+		requests = nil
+		for _, url := range []string{
+			"label-values?label=a/../b",
+			"label-values",
+			"label-names?start=yesterday",
+			"series-labels",
+			"detected-fields",
+			"detected-fields?query=%7Bapp%3D%22cart%22%7D&limit=5000",
+		} {
+			resp := call(t, url, strings.SplitN(url, "?", 2)[0])
+			require.Equal(t, http.StatusBadRequest, resp.Status, url)
+			var res map[string]string
+			require.NoError(t, json.Unmarshal(resp.Body, &res), url)
+			require.NotEmpty(t, res["message"], url)
+		}
+		require.Empty(t, requests)
+	})
+}
+
+type fakeSender struct {
+	resp *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(resp *backend.CallResourceResponse) error {
+	s.resp = resp
+	return nil
+}
@@ -2,11 +2,12 @@ package loki
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/url"
-	"os"
-	"os/signal"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,6 +18,49 @@ import (
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 )
 
+// tailPingInterval is how often the tail websocket is pinged to keep it open
+// through proxies closing idle connections.
+const tailPingInterval = 30 * time.Second
+
+// liveStreamKey returns the key of the channel of a tail query, which is the
+// hex encoded first 8 bytes of the SHA-1 of the expression and the line limit
+// of the query, like in the frontend. The channels are shared by all the
+// viewers of a query, which all receive the lines of a single Loki tail
+// connection, so the key covers every parameter of the connection.
+func liveStreamKey(expr string, maxLines *int64) (string, error) {
+	var buf strings.Builder
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(struct {
+		Expr     string `json:"expr"`
+		MaxLines *int64 `json:"maxLines,omitempty"`
+	}{expr, maxLines}); err != nil {
+		return "", err
+	}
+	sum := sha1.Sum([]byte(strings.TrimSuffix(buf.String(), "\n")))
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// checkTailPath checks that a channel path is the tail channel of the query,
+// so that viewers of different queries never share a channel.
+func checkTailPath(path string, query *QueryJSONModel) error {
+	// Expect tail/${key}
+	if !strings.HasPrefix(path, "tail/") {
+		return fmt.Errorf("expected tail in channel path")
+	}
+	if query.Expr == "" {
+		return fmt.Errorf("missing expr in channel")
+	}
+	key, err := liveStreamKey(query.Expr, query.MaxLines)
+	if err != nil {
+		return err
+	}
+	if strings.TrimPrefix(path, "tail/") != key {
+		return fmt.Errorf("the channel path does not match the query")
+	}
+	return nil
+}
+
 func (s *Service) SubscribeStream(_ context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
 	dsInfo, err := s.getDSInfo(req.PluginContext)
 	if err != nil {
@@ -25,21 +69,14 @@ func (s *Service) SubscribeStream(_ context.Context, req *backend.SubscribeStrea
 		}, err
 	}
 
-	// Expect tail/${key}
-	if !strings.HasPrefix(req.Path, "tail/") {
-		return &backend.SubscribeStreamResponse{
-			Status: backend.SubscribeStreamStatusNotFound,
-		}, fmt.Errorf("expected tail in channel path")
-	}
-
 	query, err := parseQueryModel(req.Data)
 	if err != nil {
 		return nil, err
 	}
-	if query.Expr == "" {
+	if err := checkTailPath(req.Path, query); err != nil {
 		return &backend.SubscribeStreamResponse{
 			Status: backend.SubscribeStreamStatusNotFound,
-		}, fmt.Errorf("missing expr in channel (subscribe)")
+		}, err
 	}
 
 	dsInfo.streamsMu.RLock()
@@ -71,22 +108,24 @@ func (s *Service) RunStream(ctx context.Context, req *backend.RunStreamRequest,
 	if err != nil {
 		return err
 	}
-	if query.Expr == "" {
-		return fmt.Errorf("missing expr in cuannel")
+	if err := checkTailPath(req.Path, query); err != nil {
+		return err
 	}
 
 	logger := logger.FromContext(ctx)
-	count := int64(0)
-
-	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, os.Interrupt)
 
 	params := url.Values{}
 	params.Add("query", query.Expr)
+	if query.MaxLines != nil && *query.MaxLines > 0 {
+		params.Add("limit", strconv.FormatInt(*query.MaxLines, 10))
+	}
 
 	lokiDataframeApi := s.features.IsEnabled(featuremgmt.FlagLokiDataframeApi)
 
-	wsurl, _ := url.Parse(dsInfo.URL)
+	wsurl, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return err
+	}
 
 	if lokiDataframeApi {
 		wsurl.Path = "/loki/api/v2alpha/tail"
@@ -102,7 +141,7 @@ func (s *Service) RunStream(ctx context.Context, req *backend.RunStreamRequest,
 	wsurl.RawQuery = params.Encode()
 
 	logger.Info("connecting to websocket", "url", wsurl)
-	c, r, err := websocket.DefaultDialer.Dial(wsurl.String(), nil)
+	c, r, err := dsInfo.tailDialer.DialContext(ctx, wsurl.String(), dsInfo.tailHeaders)
 	if err != nil {
 		logger.Error("error connecting to websocket", "err", err)
 		return fmt.Errorf("error connecting to websocket")
@@ -116,7 +155,7 @@ func (s *Service) RunStream(ctx context.Context, req *backend.RunStreamRequest,
 			_ = r.Body.Close()
 		}
 		err = c.Close()
-		logger.Error("closing loki websocket", "err", err)
+		logger.Debug("closed loki websocket", "err", err)
 	}()
 
 	prev := data.FrameJSONCache{}
@@ -161,7 +200,7 @@ func (s *Service) RunStream(ctx context.Context, req *backend.RunStreamRequest,
 		}
 	}()
 
-	ticker := time.NewTicker(time.Second * 60) //.Step)
+	ticker := time.NewTicker(tailPingInterval)
 	defer ticker.Stop()
 
 	for {
@@ -172,9 +211,10 @@ func (s *Service) RunStream(ctx context.Context, req *backend.RunStreamRequest,
 		case <-ctx.Done():
 			logger.Info("stop streaming (context canceled)")
 			return nil
-		case t := <-ticker.C:
-			count++
-			logger.Error("loki websocket ping?", "time", t, "count", count)
+		case <-ticker.C:
+			if err := c.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second*10)); err != nil {
+				logger.Warn("failed to ping loki websocket", "err", err)
+			}
 		}
 	}
 }
@@ -0,0 +1,136 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+)
+
+func TestLiveStreamKey(t *testing.T) {
+	maxLines := int64(50)
+	// the keys computed by the frontend
+	for _, tc := range []struct {
+		expr     string
+		maxLines *int64
+		key      string
+	}{
+		{expr: `{job="varlogs"}`, key: "ffd9ddda5d6c160a"},
+		{expr: `{app="a"} |= "<b>&"`, key: "e43d2642232964d7"},
+		{expr: `{job="varlogs"}`, maxLines: &maxLines, key: "136633dbb0538a86"},
+	} {
+		k, err := liveStreamKey(tc.expr, tc.maxLines)
+		require.NoError(t, err)
+		require.Equal(t, tc.key, k, tc.expr)
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	var requests []*http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		c, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer func() { _ = c.Close() }()
+		err = c.WriteMessage(websocket.TextMessage, []byte(`{"streams":[
+			{"stream": {"job":"varlogs"}, "values":[["1642091525267322910","line1"], ["1642091525267322911","line2"]]}
+		]}`))
+		require.NoError(t, err)
+		// wait for the client to close the connection
+		_, _, _ = c.ReadMessage()
+	}))
+	t.Cleanup(srv.Close)
+
+	s := ProvideService(httpclient.NewProvider(), featuremgmt.WithFeatures(), tracing.InitializeTracerForTest())
+	pluginContext := backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+		ID:               1,
+		URL:              srv.URL,
+		BasicAuthEnabled: true,
+		BasicAuthUser:    "user",
+		JSONData:         []byte(`{"httpHeaderName1": "X-Scope-OrgID"}`),
+		DecryptedSecureJSONData: map[string]string{
+			"basicAuthPassword": "password",
+			"httpHeaderValue1":  "tenant",
+		},
+	}}
+	query := []byte(`{"expr": "{job=\"varlogs\"}", "maxLines": 50}`)
+
+	t.Run("subscribes to the channel of the query", func(t *testing.T) {
+		res, err := s.SubscribeStream(context.Background(), &backend.SubscribeStreamRequest{
+			PluginContext: pluginContext,
+			Path:          "tail/136633dbb0538a86",
+			Data:          query,
+		})
+		require.NoError(t, err)
+		require.Equal(t, backend.SubscribeStreamStatusOK, res.Status)
+
+		for _, path := range []string{"tail/0000000000000000", "tail/ffd9ddda5d6c160a", "metrics/136633dbb0538a86"} {
+			res, err = s.SubscribeStream(context.Background(), &backend.SubscribeStreamRequest{
+				PluginContext: pluginContext,
+				Path:          path,
+				Data:          query,
+			})
+			require.Error(t, err, path)
+			require.Equal(t, backend.SubscribeStreamStatusNotFound, res.Status, path)
+		}
+	})
+
+	t.Run("streams the lines of a tail connection", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sender := &fakePacketSender{packets: make(chan *backend.StreamPacket, 1)}
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- s.RunStream(ctx, &backend.RunStreamRequest{
+				PluginContext: pluginContext,
+				Path:          "tail/136633dbb0538a86",
+				Data:          query,
+			}, backend.NewStreamSender(sender))
+		}()
+
+		select {
+		case packet := <-sender.packets:
+			var frame struct {
+				Data struct {
+					Values []json.RawMessage `json:"values"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(packet.Data, &frame))
+			require.JSONEq(t, `["line1", "line2"]`, string(frame.Data.Values[2]))
+		case <-time.After(5 * time.Second):
+			t.Fatal("no lines were streamed")
+		}
+		cancel()
+		require.NoError(t, <-errCh)
+
+		require.Len(t, requests, 1)
+		require.Equal(t, "/loki/api/v1/tail", requests[0].URL.Path)
+		require.Equal(t, `{job="varlogs"}`, requests[0].URL.Query().Get("query"))
+		require.Equal(t, "50", requests[0].URL.Query().Get("limit"))
+		user, password, ok := requests[0].BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "user", user)
+		require.Equal(t, "password", password)
+		require.Equal(t, "tenant", requests[0].Header.Get("X-Scope-OrgID"))
+	})
+}
+
+type fakePacketSender struct {
+	packets chan *backend.StreamPacket
+}
+
+func (s *fakePacketSender) Send(packet *backend.StreamPacket) error {
+	s.packets <- packet
+	return nil
+}
@@ -0,0 +1,50 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// CheckHealth lists the aggregators of OpenTSDB
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	logger := logger.FromContext(ctx)
+
+	dsInfo, err := s.getDSInfo(req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := s.createResourceRequest(ctx, dsInfo, "api/aggregators", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		return healthCheckError(logger, err), nil
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if res.StatusCode/100 != 2 {
+		return healthCheckError(logger, fmt.Errorf("request failed, status: %s", res.Status)), nil
+	}
+	var aggregators []string
+	if err := json.NewDecoder(res.Body).Decode(&aggregators); err != nil {
+		return healthCheckError(logger, fmt.Errorf("invalid response from the OpenTSDB aggregators API: %w", err)), nil
+	}
+	return &backend.CheckHealthResult{Status: backend.HealthStatusOk, Message: "Data source is working"}, nil
+}
+
+func healthCheckError(logger log.Logger, err error) *backend.CheckHealthResult {
+	logger.Warn("OpenTSDB health check failed", "error", err)
+	return &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: err.Error()}
+}
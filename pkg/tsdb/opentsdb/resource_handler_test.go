@@ -0,0 +1,108 @@
+package opentsdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+)
+
+func TestCallResource(t *testing.T) {
+	var requests []*http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`["cpu.idle", "cpu.user"]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	s := ProvideService(httpclient.NewProvider())
+	call := func(t *testing.T, method, path, url string) *backend.CallResourceResponse {
+		t.Helper()
+		sender := &fakeSender{}
+		err := s.CallResource(context.Background(), &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1, URL: srv.URL}},
+			Method:        method,
+			Path:          path,
+			URL:           url,
+		}, sender)
+		require.NoError(t, err)
+		return sender.resp
+	}
+
+	t.Run("forwards suggest requests", func(t *testing.T) {
+		requests = nil
+		resp := call(t, http.MethodGet, "api/suggest", "api/suggest?type=metrics&q=cpu&max=10")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `["cpu.idle", "cpu.user"]`, string(resp.Body))
+
+		require.Len(t, requests, 1)
+		require.Equal(t, "/api/suggest", requests[0].URL.Path)
+		require.Equal(t, "metrics", requests[0].URL.Query().Get("type"))
+		require.Equal(t, "cpu", requests[0].URL.Query().Get("q"))
+	})
+
+	t.Run("forwards aggregators requests", func(t *testing.T) {
+		requests = nil
+		resp := call(t, http.MethodGet, "api/aggregators", "api/aggregators")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.Len(t, requests, 1)
+		require.Equal(t, "/api/aggregators", requests[0].URL.Path)
+	})
+
+	t.Run("rejects other paths and methods", func(t *testing.T) {
+		requests = nil
+		for _, tc := range []struct{ method, path string }{
+			{http.MethodGet, "api/query"},
+			{http.MethodPost, "api/suggest"},
+			{http.MethodGet, "api/put"},
+		} {
+			resp := call(t, tc.method, tc.path, tc.path)
+			require.Equal(t, http.StatusNotFound, resp.Status, tc.path)
+		}
+		require.Empty(t, requests)
+	})
+}
+
+func TestCheckHealth(t *testing.T) {
+	status := http.StatusOK
+	body := `["sum", "avg"]`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/aggregators", r.URL.Path)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	s := ProvideService(httpclient.NewProvider())
+	req := &backend.CheckHealthRequest{PluginContext: backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1, URL: srv.URL}}}
+
+	res, err := s.CheckHealth(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, backend.HealthStatusOk, res.Status)
+
+	body = "<html></html>"
+	res, err = s.CheckHealth(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, backend.HealthStatusError, res.Status)
+
+	status = http.StatusBadGateway
+	res, err = s.CheckHealth(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, backend.HealthStatusError, res.Status)
+	require.Equal(t, "request failed, status: 502 Bad Gateway", res.Message)
+}
+
+type fakeSender struct {
+	resp *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(resp *backend.CallResourceResponse) error {
+	s.resp = resp
+	return nil
+}
@@ -0,0 +1,221 @@
+package sqleng
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrQueryNotReadOnly is returned by ValidateReadOnly for queries that may modify data.
+var ErrQueryNotReadOnly = errors.New("query is not read-only")
+
+// readOnlyStatements are the keywords a read-only query may start with.
+var readOnlyStatements = map[string]bool{
+	"SELECT":   true,
+	"WITH":     true,
+	"SHOW":     true,
+	"EXPLAIN":  true,
+	"DESCRIBE": true,
+	"DESC":     true,
+	"VALUES":   true,
+}
+
+// forbiddenKeywords are rejected anywhere in a read-only query. Some dialects,
+// like T-SQL, do not require a separator between statements, and some
+// modifying statements can be nested in a read-only one, e.g. data-modifying
+// CTEs or SELECT INTO.
+var forbiddenKeywords = map[string]bool{
+	"INSERT":      true,
+	"UPDATE":      true,
+	"DELETE":      true,
+	"MERGE":       true,
+	"UPSERT":      true,
+	"DROP":        true,
+	"ALTER":       true,
+	"CREATE":      true,
+	"TRUNCATE":    true,
+	"RENAME":      true,
+	"GRANT":       true,
+	"REVOKE":      true,
+	"DENY":        true,
+	"CALL":        true,
+	"EXEC":        true,
+	"EXECUTE":     true,
+	"COPY":        true,
+	"INTO":        true,
+	"LOCK":        true,
+	"SET":         true,
+	"VACUUM":      true,
+	"ATTACH":      true,
+	"DETACH":      true,
+	"PRAGMA":      true,
+	"LOAD":        true,
+	"BULK":        true,
+	"BACKUP":      true,
+	"RESTORE":     true,
+	"DBCC":        true,
+	"KILL":        true,
+	"SHUTDOWN":    true,
+	"RECONFIGURE": true,
+}
+
+// ValidateReadOnly returns an error wrapping ErrQueryNotReadOnly if the SQL
+// query is not a single statement that only reads data.
+//
+// The validation is conservative and dialect agnostic: comments, string
+// literals and quoted identifiers are ignored, everything else is checked
+// against a list of keywords that may modify data. It does not protect against
+// functions with side effects, so a read-only database user should still be
+// used for data sources that must not modify data.
+func ValidateReadOnly(rawSQL string) error {
+	// Whether a backslash escapes a quote and whether $ starts a quoted string
+	// depends on the database and its settings, so the query must be read-only
+	// when parsed in all of these ways.
+	for _, d := range []sqlDialect{{}, {backslashEscapes: true}, {dollarQuotes: true}, {backslashEscapes: true, dollarQuotes: true}} {
+		if err := validateReadOnly(rawSQL, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type sqlDialect struct {
+	backslashEscapes bool
+	dollarQuotes     bool
+}
+
+func validateReadOnly(rawSQL string, d sqlDialect) error {
+	tokens, err := sqlTokens(rawSQL, d)
+	if err != nil {
+		return err
+	}
+
+	terminated := false
+	for i, token := range tokens {
+		if token == ";" {
+			terminated = true
+			continue
+		}
+		if terminated {
+			return fmt.Errorf("%w: multiple statements are not allowed", ErrQueryNotReadOnly)
+		}
+		if i == 0 && !readOnlyStatements[token] {
+			return fmt.Errorf("%w: %s statements are not allowed", ErrQueryNotReadOnly, token)
+		}
+		if forbiddenKeywords[token] {
+			return fmt.Errorf("%w: %s is not allowed", ErrQueryNotReadOnly, token)
+		}
+	}
+	return nil
+}
+
+// sqlTokens returns the upper cased words and statement separators of the
+// query, skipping comments, string literals and quoted identifiers.
+func sqlTokens(rawSQL string, d sqlDialect) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(rawSQL); {
+		c := rawSQL[i]
+		switch {
+		case c == '-' && strings.HasPrefix(rawSQL[i:], "--") && (i+2 == len(rawSQL) || isSpace(rawSQL[i+2])):
+			// MySQL only starts a comment if the dashes are followed by whitespace.
+			end := strings.IndexByte(rawSQL[i:], '\n')
+			if end < 0 {
+				return tokens, nil
+			}
+			i += end + 1
+		case c == '/' && strings.HasPrefix(rawSQL[i:], "/*"):
+			// MySQL executes the content of /*! ... */ comments.
+			if strings.HasPrefix(rawSQL[i:], "/*!") {
+				i += 3
+				continue
+			}
+			// Nested comments are not supported by all databases, so the
+			// comment ends at the first terminator.
+			end := strings.Index(rawSQL[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated comment", ErrQueryNotReadOnly)
+			}
+			i += end + 4
+		case c == '\'' || c == '"' || c == '`':
+			end, err := quotedEnd(rawSQL, i, c, d.backslashEscapes && c != '`')
+			if err != nil {
+				return nil, err
+			}
+			i = end
+		case c == '$' && d.dollarQuotes && (i == 0 || !isWordChar(rawSQL[i-1])):
+			end, err := dollarQuotedEnd(rawSQL, i)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+		case c == ';':
+			tokens = append(tokens, ";")
+			i++
+		case isWordChar(c):
+			start := i
+			for i < len(rawSQL) && isWordChar(rawSQL[i]) {
+				i++
+			}
+			tokens = append(tokens, strings.ToUpper(rawSQL[start:i]))
+		default:
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// quotedEnd returns the index after the quoted section starting at start. A
+// doubled closing quote is an escaped quote.
+func quotedEnd(rawSQL string, start int, closing byte, backslashEscapes bool) (int, error) {
+	for i := start + 1; i < len(rawSQL); i++ {
+		switch rawSQL[i] {
+		case '\\':
+			if backslashEscapes {
+				i++
+			}
+		case closing:
+			if i+1 < len(rawSQL) && rawSQL[i+1] == closing {
+				i++
+				continue
+			}
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unterminated quoted string", ErrQueryNotReadOnly)
+}
+
+// dollarQuotedEnd returns the index after the PostgreSQL dollar quoted string
+// starting at start, or the index after the $ if it does not start one.
+func dollarQuotedEnd(rawSQL string, start int) (int, error) {
+	i := start + 1
+	for i < len(rawSQL) && rawSQL[i] != '$' {
+		c := rawSQL[i]
+		if !isTagChar(c) || (i == start+1 && c >= '0' && c <= '9') {
+			return start + 1, nil
+		}
+		i++
+	}
+	if i >= len(rawSQL) {
+		return start + 1, nil
+	}
+
+	tag := rawSQL[start : i+1]
+	end := strings.Index(rawSQL[i+1:], tag)
+	if end < 0 {
+		return 0, fmt.Errorf("%w: unterminated quoted string", ErrQueryNotReadOnly)
+	}
+	return i + 1 + end + len(tag), nil
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c == '$' || c == '@' || c == '#' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c >= 0x80
+}
+
+func isTagChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c >= 0x80
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
@@ -0,0 +1,61 @@
+package sqleng
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateReadOnly(t *testing.T) {
+	t.Run("accepts read-only queries", func(t *testing.T) {
+		queries := []string{
+			"",
+			"SELECT 1",
+			"select time, value from metrics where $__timeFilter(time) order by 1;",
+			"WITH t AS (SELECT 1 AS a) SELECT a FROM t",
+			"(SELECT 1) UNION (SELECT 2)",
+			"SHOW TABLES",
+			"EXPLAIN SELECT * FROM metrics",
+			"SELECT 'DROP TABLE metrics; DELETE' AS text",
+			"SELECT \"update\", `delete` FROM metrics",
+			"SELECT 1 -- ; DROP TABLE metrics",
+			"SELECT 1 /* INSERT */ FROM metrics",
+			"SELECT 'it''s' AS text",
+			"SELECT $tag$ text $tag$",
+			"SELECT value FROM metrics WHERE id = $1",
+			"SELECT replace(name, 'a', 'b') FROM metrics",
+			"SELECT 1;\n-- comment\n",
+		}
+		for _, q := range queries {
+			require.NoError(t, ValidateReadOnly(q), q)
+		}
+	})
+
+	t.Run("rejects queries that may modify data", func(t *testing.T) {
+		queries := []string{
+			"DELETE FROM metrics",
+			"insert into metrics values (1)",
+			"UPDATE metrics SET value = 1",
+			"DROP TABLE metrics",
+			"TRUNCATE metrics",
+			"SELECT 1; DROP TABLE metrics",
+			"SELECT 1; SELECT 2",
+			"SELECT 1 DROP TABLE metrics",
+			"SELECT * INTO backup FROM metrics",
+			"SELECT * FROM metrics FOR UPDATE",
+			"WITH d AS (DELETE FROM metrics RETURNING *) SELECT * FROM d",
+			"EXEC sp_configure",
+			"SELECT 1 /*!50000 ; DROP TABLE metrics */",
+			"SELECT 1--1; DROP TABLE metrics",
+			"SELECT 'a\\'' ; DROP TABLE metrics; -- '",
+			"SELECT 'a\\' ; DROP TABLE metrics; -- '",
+			"SELECT 1 AS $$; DROP TABLE metrics; SELECT 1 AS $$",
+			"SELECT $$'$$; DROP TABLE metrics; --'",
+			"SELECT 'unterminated",
+			"SELECT 1 /* unterminated",
+		}
+		for _, q := range queries {
+			require.ErrorIs(t, ValidateReadOnly(q), ErrQueryNotReadOnly, q)
+		}
+	})
+}
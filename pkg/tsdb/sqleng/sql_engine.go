@@ -14,12 +14,14 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
 	"xorm.io/core"
 	"xorm.io/xorm"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
 )
 
@@ -44,6 +46,64 @@ type SqlQueryResultTransformer interface {
 	GetConverterList() []sqlutil.StringConverter
 }
 
+// SqlQueryResultConverterProvider can be implemented by a SqlQueryResultTransformer
+// that needs converters which can't be expressed as string converters, e.g. dynamic
+// converters for databases without fixed column types. They replace the string converters.
+type SqlQueryResultConverterProvider interface {
+	GetConverters() []sqlutil.Converter
+}
+
+// DynamicConverters can be embedded in the SqlQueryResultTransformer of databases whose
+// columns have no fixed type, or whose drivers report different column types: the field
+// types are detected from the values.
+type DynamicConverters struct{}
+
+func (DynamicConverters) GetConverterList() []sqlutil.StringConverter {
+	return nil
+}
+
+func (DynamicConverters) GetConverters() []sqlutil.Converter {
+	return []sqlutil.Converter{{Dynamic: true}}
+}
+
+// DataSourceInstance is the data source handler of a data source, or the error
+// preventing it from being created when its settings are invalid.
+type DataSourceInstance struct {
+	Handler *DataSourceHandler
+	Err     error
+}
+
+func (i *DataSourceInstance) Dispose() {
+	if i.Handler != nil {
+		i.Handler.Dispose()
+	}
+}
+
+// GetDataSourceHandler returns the handler of the DataSourceInstance of the plugin context,
+// or the error preventing it from being created.
+func GetDataSourceHandler(im instancemgmt.InstanceManager, pluginCtx backend.PluginContext) (*DataSourceHandler, error) {
+	i, err := im.Get(pluginCtx)
+	if err != nil {
+		return nil, err
+	}
+	inst := i.(*DataSourceInstance)
+	return inst.Handler, inst.Err
+}
+
+// NewJsonData reads the settings of a data source, with the connection pool defaults of
+// the configuration.
+func NewJsonData(cfg *setting.Cfg, settings backend.DataSourceInstanceSettings) (JsonData, error) {
+	jsonData := JsonData{
+		MaxOpenConns:    cfg.SqlDatasourceMaxOpenConnsDefault,
+		MaxIdleConns:    cfg.SqlDatasourceMaxIdleConnsDefault,
+		ConnMaxLifetime: cfg.SqlDatasourceMaxConnLifetimeDefault,
+	}
+	if err := json.Unmarshal(settings.JSONData, &jsonData); err != nil {
+		return JsonData{}, fmt.Errorf("error reading settings: %w", err)
+	}
+	return jsonData, nil
+}
+
 var sqlIntervalCalculator = intervalv2.NewCalculator()
 
 // NewXormEngine is an xorm.Engine factory, that can be stubbed by tests.
@@ -71,6 +131,7 @@ type JsonData struct {
 	TimeInterval        string `json:"timeInterval"`
 	Database            string `json:"database"`
 	SecureDSProxy       bool   `json:"enableSecureSocksProxy"`
+	ReadOnlySQL         bool   `json:"readOnlySql"`
 }
 
 type DataSourceInfo struct {
@@ -277,6 +338,13 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 		return
 	}
 
+	if e.dsInfo.JsonData.ReadOnlySQL {
+		if err := ValidateReadOnly(interpolatedQuery); err != nil {
+			errAppendDebug("query validation failed", err, interpolatedQuery)
+			return
+		}
+	}
+
 	session := e.engine.NewSession()
 	defer session.Close()
 	db := session.DB()
@@ -299,8 +367,11 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 	}
 
 	// Convert row.Rows to dataframe
-	stringConverters := e.queryResultTransformer.GetConverterList()
-	frame, err := sqlutil.FrameFromRows(rows.Rows, e.rowLimit, sqlutil.ToConverters(stringConverters...)...)
+	converters := sqlutil.ToConverters(e.queryResultTransformer.GetConverterList()...)
+	if p, ok := e.queryResultTransformer.(SqlQueryResultConverterProvider); ok {
+		converters = p.GetConverters()
+	}
+	frame, err := sqlutil.FrameFromRows(rows.Rows, e.rowLimit, converters...)
 	if err != nil {
 		errAppendDebug("convert frame from rows error", err, interpolatedQuery)
 		return
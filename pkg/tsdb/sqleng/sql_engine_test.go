@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -426,3 +427,17 @@ func (t *testQueryResultTransformer) TransformQueryError(_ log.Logger, err error
 func (t *testQueryResultTransformer) GetConverterList() []sqlutil.StringConverter {
 	return nil
 }
+
+func TestNewJsonData(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.SqlDatasourceMaxOpenConnsDefault = 10
+	cfg.SqlDatasourceMaxIdleConnsDefault = 5
+	cfg.SqlDatasourceMaxConnLifetimeDefault = 60
+
+	jsonData, err := NewJsonData(cfg, backend.DataSourceInstanceSettings{JSONData: []byte(`{"maxIdleConns": 2, "database": "db"}`)})
+	require.NoError(t, err)
+	assert.Equal(t, JsonData{MaxOpenConns: 10, MaxIdleConns: 2, ConnMaxLifetime: 60, Database: "db"}, jsonData)
+
+	_, err = NewJsonData(cfg, backend.DataSourceInstanceSettings{JSONData: []byte(`{"maxIdleConns": "2"}`)})
+	require.Error(t, err)
+}
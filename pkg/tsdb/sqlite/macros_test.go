@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMacroEngine(t *testing.T) {
+	engine := newSqliteMacroEngine()
+	query := &backend.DataQuery{}
+
+	from := time.Date(2018, 4, 12, 18, 0, 0, 0, time.UTC)
+	to := from.Add(5 * time.Minute)
+	timeRange := backend.TimeRange{From: from, To: to}
+	epochColumn := "(CASE WHEN typeof(time_column) IN ('integer', 'real') THEN time_column ELSE CAST(strftime('%s', time_column) AS INTEGER) END)"
+
+	t.Run("interpolate __time function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "select $__time(time_column)")
+		require.NoError(t, err)
+		require.Equal(t, "select "+epochColumn+" AS time", sql)
+	})
+
+	t.Run("interpolate __timeFilter function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeFilter(time_column)")
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("WHERE %s BETWEEN %d AND %d", epochColumn, from.Unix(), to.Unix()), sql)
+	})
+
+	t.Run("interpolate __timeFrom and __timeTo functions", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "WHERE created BETWEEN $__timeFrom() AND $__timeTo()")
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("WHERE created BETWEEN datetime(%d, 'unixepoch') AND datetime(%d, 'unixepoch')", from.Unix(), to.Unix()), sql)
+	})
+
+	t.Run("interpolate __timeGroup function", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column , '5m')")
+		require.NoError(t, err)
+		sql2, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupAlias(time_column,'5m')")
+		require.NoError(t, err)
+
+		require.Equal(t, "GROUP BY CAST("+epochColumn+" / 300 AS INTEGER) * 300", sql)
+		require.Equal(t, sql+" AS \"time\"", sql2)
+	})
+
+	t.Run("interpolate __timeGroup function with fill", func(t *testing.T) {
+		q := &backend.DataQuery{JSON: []byte(`{}`)}
+		_, err := engine.Interpolate(q, timeRange, "GROUP BY $__timeGroup(time_column,'5m', NULL)")
+		require.NoError(t, err)
+		require.Contains(t, string(q.JSON), `"fillMode":"null"`)
+	})
+
+	t.Run("interpolate __unixEpoch functions", func(t *testing.T) {
+		sql, err := engine.Interpolate(query, timeRange, "WHERE $__unixEpochFilter(time) GROUP BY $__unixEpochGroup(time, '1m')")
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("WHERE time >= %d AND time <= %d GROUP BY CAST(time / 60 AS INTEGER) * 60", from.Unix(), to.Unix()), sql)
+
+		sql, err = engine.Interpolate(query, timeRange, "WHERE $__unixEpochNanoFilter(time)")
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("WHERE time >= %d AND time <= %d", from.UnixNano(), to.UnixNano()), sql)
+	})
+
+	t.Run("fails on unknown macros and missing arguments", func(t *testing.T) {
+		_, err := engine.Interpolate(query, timeRange, "SELECT $__unknown(time)")
+		require.Error(t, err)
+		_, err = engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time)")
+		require.Error(t, err)
+	})
+}
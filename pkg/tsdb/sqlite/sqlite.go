@@ -0,0 +1,184 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
+)
+
+var logger = log.New("tsdb.sqlite")
+
+var (
+	errPathRequired   = errors.New("the path of the database file is required")
+	errPathNotAllowed = errors.New("the database file is not in a directory allowed by the sqlite_allowed_paths setting")
+)
+
+type Service struct {
+	im instancemgmt.InstanceManager
+}
+
+func ProvideService(cfg *setting.Cfg) *Service {
+	return &Service{
+		im: datasource.NewInstanceManager(newInstanceSettings(cfg)),
+	}
+}
+
+func newInstanceSettings(cfg *setting.Cfg) datasource.InstanceFactoryFunc {
+	return func(settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+		jsonData, err := sqleng.NewJsonData(cfg, settings)
+		if err != nil {
+			return nil, err
+		}
+
+		path := jsonData.Database
+		if path == "" {
+			path = settings.Database
+		}
+		path, err = resolvePath(path, cfg.SqliteDatasourceAllowedPaths, cfg.DataPath)
+		if err != nil {
+			return &sqleng.DataSourceInstance{Err: err}, nil
+		}
+
+		// the database file is opened read-only, but queries could still
+		// attach other database files or change the connection settings
+		jsonData.ReadOnlySQL = true
+
+		dsInfo := sqleng.DataSourceInfo{
+			JsonData: jsonData,
+			Database: path,
+			ID:       settings.ID,
+			Updated:  settings.Updated,
+			UID:      settings.UID,
+		}
+
+		cnnstr := connectionString(path)
+		if cfg.Env == setting.Dev {
+			logger.Debug("GetEngine", "connection", cnnstr)
+		}
+
+		config := sqleng.DataPluginConfiguration{
+			DriverName:        "sqlite3",
+			ConnectionString:  cnnstr,
+			DSInfo:            dsInfo,
+			TimeColumnNames:   []string{"time", "time_sec"},
+			MetricColumnTypes: []string{"TEXT", "VARCHAR", "CHAR"},
+			RowLimit:          cfg.DataProxyRowLimit,
+		}
+
+		handler, err := sqleng.NewQueryDataHandler(config, &sqliteQueryResultTransformer{}, newSqliteMacroEngine(), logger)
+		if err != nil {
+			return nil, err
+		}
+		return &sqleng.DataSourceInstance{Handler: handler}, nil
+	}
+}
+
+// connectionString returns the connection string opening the database file
+// read-only.
+func connectionString(path string) string {
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+	return u.String() + "?mode=ro&_query_only=true&_busy_timeout=5000"
+}
+
+// resolvePath returns the absolute path of the database file without symbolic
+// links. The file must be in one of the allowed directories and not in the
+// Grafana data directory, which contains the Grafana database.
+func resolvePath(path string, allowedPaths []string, dataPath string) (string, error) {
+	if path == "" {
+		return "", errPathRequired
+	}
+	if !filepath.IsAbs(path) {
+		return "", errors.New("the path of the database file must be absolute")
+	}
+
+	resolved, err := filepath.EvalSymlinks(filepath.Clean(path))
+	if err != nil {
+		logger.Debug("Failed to resolve database file path", "path", path, "error", err)
+		return "", errPathNotAllowed
+	}
+
+	if dataPath != "" {
+		if dir, err := filepath.EvalSymlinks(dataPath); err == nil && isWithin(resolved, dir) {
+			return "", errPathNotAllowed
+		}
+	}
+
+	for _, allowed := range allowedPaths {
+		dir, err := filepath.EvalSymlinks(filepath.Clean(allowed))
+		if err != nil {
+			logger.Warn("Failed to resolve allowed path", "path", allowed, "error", err)
+			continue
+		}
+		if isWithin(resolved, dir) {
+			return resolved, nil
+		}
+	}
+	return "", errPathNotAllowed
+}
+
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// CheckHealth opens the database file and checks that it is a SQLite database
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	dsHandler, err := sqleng.GetDataSourceHandler(s.im, req.PluginContext)
+	if err != nil {
+		if errors.Is(err, errPathRequired) || errors.Is(err, errPathNotAllowed) {
+			return &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: err.Error()}, nil
+		}
+		return nil, err
+	}
+
+	// opening a connection does not read the file, query the schema to check it
+	resp, err := dsHandler.QueryData(ctx, &backend.QueryDataRequest{
+		Queries: []backend.DataQuery{{RefID: "A", JSON: []byte(`{"rawSql": "SELECT count(*) FROM sqlite_master", "format": "table"}`)}},
+	})
+	if err == nil {
+		err = resp.Responses["A"].Error
+	}
+	if err != nil {
+		return &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: dsHandler.TransformQueryError(logger, err).Error()}, nil
+	}
+	return &backend.CheckHealthResult{Status: backend.HealthStatusOk, Message: "Database Connection OK"}, nil
+}
+
+func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	dsHandler, err := sqleng.GetDataSourceHandler(s.im, req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+	return dsHandler.QueryData(ctx, req)
+}
+
+// sqliteQueryResultTransformer detects the field types from the values, as
+// SQLite columns have no fixed type.
+type sqliteQueryResultTransformer struct {
+	sqleng.DynamicConverters
+}
+
+func (t *sqliteQueryResultTransformer) TransformQueryError(logger log.Logger, err error) error {
+	var driverErr sqlite3.Error
+	if errors.As(err, &driverErr) && driverErr.Code != sqlite3.ErrError {
+		logger.Error("Query error", "error", err)
+		return errQueryFailed
+	}
+	return err
+}
+
+var errQueryFailed = errors.New("query failed - please inspect Grafana server log for details")
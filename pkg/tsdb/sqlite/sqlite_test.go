@@ -0,0 +1,167 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestSQLite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "metrics.db")
+
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+		CREATE TABLE metrics (time INTEGER, created TEXT, host TEXT, value REAL);
+		INSERT INTO metrics VALUES
+			(1600000000, '2020-09-13 12:26:40', 'a', 1.5),
+			(1600000030, '2020-09-13 12:27:10', 'b', 2),
+			(1600000060, '2020-09-13 12:27:40', 'a', 3.5),
+			(1600000600, '2020-09-13 12:36:40', 'a', 4);
+	`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	cfg := setting.NewCfg()
+	cfg.DataProxyRowLimit = 1000000
+	cfg.SqliteDatasourceAllowedPaths = []string{dir}
+	s := ProvideService(cfg)
+
+	pluginContext := func(id int64, path string) backend.PluginContext {
+		return backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+			ID:       id,
+			JSONData: []byte(`{"database": ` + string(mustJSON(t, path)) + `}`),
+		}}
+	}
+	timeRange := backend.TimeRange{From: time.Unix(1600000000, 0), To: time.Unix(1600000300, 0)}
+	query := func(t *testing.T, rawSQL string, format string) backend.DataResponse {
+		t.Helper()
+		resp, err := s.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: pluginContext(1, path),
+			Queries: []backend.DataQuery{{
+				RefID:         "A",
+				TimeRange:     timeRange,
+				MaxDataPoints: 100,
+				Interval:      time.Second,
+				JSON:          mustJSON(t, map[string]interface{}{"rawSql": rawSQL, "format": format}),
+			}},
+		})
+		require.NoError(t, err)
+		return resp.Responses["A"]
+	}
+
+	t.Run("returns tables", func(t *testing.T) {
+		resp := query(t, "SELECT host, value FROM metrics ORDER BY time", "table")
+		require.NoError(t, resp.Error)
+		require.Len(t, resp.Frames, 1)
+		frame := resp.Frames[0]
+		require.Equal(t, 4, frame.Rows())
+		require.Equal(t, "a", *frame.Fields[0].At(0).(*string))
+		require.Equal(t, 2.0, *frame.Fields[1].At(1).(*float64))
+	})
+
+	t.Run("filters and groups time series with integer times", func(t *testing.T) {
+		resp := query(t, "SELECT $__timeGroupAlias(time, '1m'), sum(value) AS value FROM metrics WHERE $__timeFilter(time) GROUP BY 1 ORDER BY 1", "time_series")
+		require.NoError(t, resp.Error)
+		frame := resp.Frames[0]
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, data.TimeSeriesTimeFieldName, frame.Fields[0].Name)
+		require.Equal(t, time.Unix(1599999960, 0).UTC(), (*frame.Fields[0].At(0).(*time.Time)).UTC())
+		require.Equal(t, 1.5, *frame.Fields[1].At(0).(*float64))
+		require.Equal(t, 5.5, *frame.Fields[1].At(1).(*float64))
+	})
+
+	t.Run("filters time series with text times", func(t *testing.T) {
+		resp := query(t, "SELECT $__time(created), value, host AS metric FROM metrics WHERE $__timeFilter(created) ORDER BY 1", "time_series")
+		require.NoError(t, resp.Error)
+		frame := resp.Frames[0]
+		require.Equal(t, 3, frame.Rows())
+		require.Len(t, frame.Fields, 3)
+	})
+
+	t.Run("lists tables and columns", func(t *testing.T) {
+		resp := query(t, `SELECT name AS "table" FROM sqlite_master WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%'`, "table")
+		require.NoError(t, resp.Error)
+		require.Equal(t, "metrics", *resp.Frames[0].Fields[0].At(0).(*string))
+
+		resp = query(t, `SELECT name AS "column", lower(type) AS "type" FROM pragma_table_info('metrics')`, "table")
+		require.NoError(t, resp.Error)
+		require.Equal(t, 4, resp.Frames[0].Rows())
+		require.Equal(t, "real", *resp.Frames[0].Fields[1].At(3).(*string))
+	})
+
+	t.Run("only allows read-only queries", func(t *testing.T) {
+		for _, q := range []string{
+			"DELETE FROM metrics",
+			"ATTACH DATABASE '/tmp/other.db' AS other",
+			"PRAGMA writable_schema = 1",
+		} {
+			resp := query(t, q, "table")
+			require.Error(t, resp.Error, q)
+		}
+	})
+
+	t.Run("checks health", func(t *testing.T) {
+		res, err := s.CheckHealth(context.Background(), &backend.CheckHealthRequest{PluginContext: pluginContext(1, path)})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+
+		other := filepath.Join(t.TempDir(), "other.db")
+		require.NoError(t, os.WriteFile(other, nil, 0600))
+		res, err = s.CheckHealth(context.Background(), &backend.CheckHealthRequest{PluginContext: pluginContext(2, other)})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Equal(t, errPathNotAllowed.Error(), res.Message)
+	})
+}
+
+func TestResolvePath(t *testing.T) {
+	dir := t.TempDir()
+	allowed := filepath.Join(dir, "allowed")
+	dataPath := filepath.Join(allowed, "grafana")
+	require.NoError(t, os.MkdirAll(dataPath, 0750))
+	for _, f := range []string{filepath.Join(allowed, "metrics.db"), filepath.Join(dir, "secret.db"), filepath.Join(dataPath, "grafana.db")} {
+		require.NoError(t, os.WriteFile(f, nil, 0600))
+	}
+	require.NoError(t, os.Symlink(filepath.Join(dir, "secret.db"), filepath.Join(allowed, "link.db")))
+
+	resolved, err := resolvePath(filepath.Join(allowed, "metrics.db"), []string{allowed}, dataPath)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(allowed, "metrics.db"), resolved)
+
+	for _, path := range []string{
+		filepath.Join(dir, "secret.db"),
+		filepath.Join(allowed, "..", "secret.db"),
+		filepath.Join(allowed, "link.db"),
+		filepath.Join(dataPath, "grafana.db"),
+		filepath.Join(allowed, "missing.db"),
+	} {
+		_, err := resolvePath(path, []string{allowed}, dataPath)
+		require.ErrorIs(t, err, errPathNotAllowed, path)
+	}
+
+	_, err = resolvePath("", []string{allowed}, dataPath)
+	require.ErrorIs(t, err, errPathRequired)
+	_, err = resolvePath("metrics.db", []string{allowed}, dataPath)
+	require.Error(t, err)
+	_, err = resolvePath(filepath.Join(allowed, "metrics.db"), nil, dataPath)
+	require.ErrorIs(t, err, errPathNotAllowed)
+}
+
+func mustJSON(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return b
+}
@@ -0,0 +1,104 @@
+package tempo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// resourcePaths are the paths of the Tempo API listing tags that can be called
+// as resources, they only allow GET requests. Tag values are fetched with the
+// api/search/tag/<tag>/values and api/v2/search/tag/<tag>/values paths.
+var resourcePaths = map[string]bool{
+	"api/search/tags":    true,
+	"api/v2/search/tags": true,
+}
+
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	logger := s.tlog.FromContext(ctx)
+
+	resourcePath := strings.Trim(req.Path, "/")
+	if !isAllowedResource(resourcePath) || req.Method != http.MethodGet {
+		return sender.Send(&backend.CallResourceResponse{
+			Status: http.StatusNotFound,
+			Body:   []byte(fmt.Sprintf(`{"message": "invalid resource %s %s"}`, req.Method, resourcePath)),
+		})
+	}
+
+	dsInfo, err := s.getDSInfo(req.PluginContext)
+	if err != nil {
+		return err
+	}
+
+	reqURL, err := url.Parse(req.URL)
+	if err != nil {
+		return fmt.Errorf("invalid resource URL: %w", err)
+	}
+	request, err := s.createResourceRequest(ctx, dsInfo, resourcePath, reqURL.Query())
+	if err != nil {
+		return err
+	}
+
+	res, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	headers := map[string][]string{}
+	if contentType := res.Header.Get("Content-Type"); contentType != "" {
+		headers["Content-Type"] = []string{contentType}
+	}
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  res.StatusCode,
+		Headers: headers,
+		Body:    body,
+	})
+}
+
+func isAllowedResource(resourcePath string) bool {
+	if resourcePaths[resourcePath] {
+		return true
+	}
+
+	// the values of a tag
+	for _, prefix := range []string{"api/search/tag/", "api/v2/search/tag/"} {
+		if !strings.HasPrefix(resourcePath, prefix) || !strings.HasSuffix(resourcePath, "/values") {
+			continue
+		}
+		tag := strings.TrimSuffix(strings.TrimPrefix(resourcePath, prefix), "/values")
+		return tag != "" && tag != "." && tag != ".." && !strings.Contains(tag, "/")
+	}
+	return false
+}
+
+func (s *Service) createResourceRequest(ctx context.Context, dsInfo *datasourceInfo, resourcePath string, params url.Values) (*http.Request, error) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, resourcePath)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		s.tlog.FromContext(ctx).Info("Failed to create request", "error", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	return req, nil
+}
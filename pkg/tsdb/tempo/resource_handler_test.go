@@ -0,0 +1,87 @@
+package tempo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+)
+
+func TestCallResource(t *testing.T) {
+	var requests []*http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"tagValues": ["checkout", "cart"]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	s := ProvideService(httpclient.NewProvider(), nil, nil, nil)
+	call := func(t *testing.T, method, path, url string) *backend.CallResourceResponse {
+		t.Helper()
+		sender := &fakeSender{}
+		err := s.CallResource(context.Background(), &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1, URL: srv.URL}},
+			Method:        method,
+			Path:          path,
+			URL:           url,
+		}, sender)
+		require.NoError(t, err)
+		return sender.resp
+	}
+
+	t.Run("forwards tags requests", func(t *testing.T) {
+		requests = nil
+		resp := call(t, http.MethodGet, "api/v2/search/tags", "api/v2/search/tags?scope=span")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.Len(t, requests, 1)
+		require.Equal(t, "/api/v2/search/tags", requests[0].URL.Path)
+		require.Equal(t, "span", requests[0].URL.Query().Get("scope"))
+	})
+
+	t.Run("forwards tag values requests", func(t *testing.T) {
+		requests = nil
+		resp := call(t, http.MethodGet, "api/search/tag/service.name/values", "api/search/tag/service.name/values")
+		require.Equal(t, http.StatusOK, resp.Status)
+		require.JSONEq(t, `{"tagValues": ["checkout", "cart"]}`, string(resp.Body))
+		require.Equal(t, []string{"application/json"}, resp.Headers["Content-Type"])
+
+		resp = call(t, http.MethodGet, "api/v2/search/tag/resource.service.name/values", "api/v2/search/tag/resource.service.name/values?q=%7B%7D")
+		require.Equal(t, http.StatusOK, resp.Status)
+
+		require.Len(t, requests, 2)
+		require.Equal(t, "/api/search/tag/service.name/values", requests[0].URL.Path)
+		require.Equal(t, "/api/v2/search/tag/resource.service.name/values", requests[1].URL.Path)
+		require.Equal(t, "{}", requests[1].URL.Query().Get("q"))
+	})
+
+	t.Run("rejects other paths and methods", func(t *testing.T) {
+		requests = nil
+		for _, tc := range []struct{ method, path string }{
+			{http.MethodGet, "api/search"},
+			{http.MethodGet, "api/traces/abc"},
+			{http.MethodPost, "api/search/tags"},
+			{http.MethodGet, "api/search/tag/../values"},
+			{http.MethodGet, "api/search/tag/a/b/values"},
+			{http.MethodGet, "api/search/tag//values"},
+		} {
+			resp := call(t, tc.method, tc.path, tc.path)
+			require.Equal(t, http.StatusNotFound, resp.Status, tc.path)
+		}
+		require.Empty(t, requests)
+	})
+}
+
+type fakeSender struct {
+	resp *backend.CallResourceResponse
+}
+
+func (s *fakeSender) Send(resp *backend.CallResourceResponse) error {
+	s.resp = resp
+	return nil
+}
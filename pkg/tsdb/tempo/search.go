@@ -0,0 +1,239 @@
+package tempo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/tsdb/tempo/kinds/dataquery"
+)
+
+// defaultSearchLimit is the maximum number of traces returned by searches
+// without a limit, the same as in the query editor.
+const defaultSearchLimit = 20
+
+// intrinsics are the TraceQL fields that are not attributes, and have no scope.
+var intrinsics = map[string]bool{
+	"duration": true,
+	"name":     true,
+	"status":   true,
+}
+
+type searchResponse struct {
+	Traces []traceSearchMetadata `json:"traces"`
+}
+
+type traceSearchMetadata struct {
+	TraceID           string `json:"traceID"`
+	RootServiceName   string `json:"rootServiceName"`
+	RootTraceName     string `json:"rootTraceName"`
+	StartTimeUnixNano string `json:"startTimeUnixNano"`
+	DurationMs        int64  `json:"durationMs"`
+}
+
+// search searches traces with the search API, which takes either a TraceQL
+// query or tags, and returns them in a table.
+func (s *Service) search(ctx context.Context, dsInfo *datasourceInfo, q backend.DataQuery, params url.Values, limit *int64) backend.DataResponse {
+	queryRes := backend.DataResponse{}
+
+	if limit == nil || *limit == 0 {
+		params.Set("limit", strconv.Itoa(defaultSearchLimit))
+	} else if *limit < 0 {
+		queryRes.Error = fmt.Errorf("invalid limit %d", *limit)
+		return queryRes
+	} else {
+		params.Set("limit", strconv.FormatInt(*limit, 10))
+	}
+	params.Set("start", strconv.FormatInt(q.TimeRange.From.Unix(), 10))
+	params.Set("end", strconv.FormatInt(q.TimeRange.To.Unix(), 10))
+
+	request, err := s.createSearchRequest(ctx, dsInfo, params)
+	if err != nil {
+		queryRes.Error = err
+		return queryRes
+	}
+
+	resp, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		queryRes.Error = fmt.Errorf("failed get to tempo: %w", err)
+		return queryRes
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			s.tlog.FromContext(ctx).Warn("failed to close response body", "err", err)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		queryRes.Error = err
+		return queryRes
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		queryRes.Error = fmt.Errorf("failed to search traces: Status: %s Body: %s", resp.Status, string(body))
+		return queryRes
+	}
+
+	var res searchResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		queryRes.Error = fmt.Errorf("failed to parse tempo search response: %w", err)
+		return queryRes
+	}
+
+	queryRes.Frames = data.Frames{searchResultToFrame(res.Traces, dsInfo)}
+	return queryRes
+}
+
+func (s *Service) createSearchRequest(ctx context.Context, dsInfo *datasourceInfo, params url.Values) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, dsInfo.URL+"/api/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	s.tlog.FromContext(ctx).Debug("Tempo search request", "url", req.URL.String())
+	return req, nil
+}
+
+// searchResultToFrame converts the traces found by a search to a table with
+// the most recent traces first. The trace IDs link to the traces.
+func searchResultToFrame(traces []traceSearchMetadata, dsInfo *datasourceInfo) *data.Frame {
+	sort.SliceStable(traces, func(i, j int) bool {
+		return traceStartTime(traces[i]).After(traceStartTime(traces[j]))
+	})
+
+	traceIDs := make([]string, 0, len(traces))
+	startTimes := make([]time.Time, 0, len(traces))
+	names := make([]string, 0, len(traces))
+	durations := make([]int64, 0, len(traces))
+	for _, trace := range traces {
+		traceIDs = append(traceIDs, trace.TraceID)
+		startTimes = append(startTimes, traceStartTime(trace))
+		names = append(names, strings.TrimSpace(trace.RootServiceName+" "+trace.RootTraceName))
+		durations = append(durations, trace.DurationMs)
+	}
+
+	frame := data.NewFrame("Traces",
+		data.NewField("traceID", nil, traceIDs).SetConfig(&data.FieldConfig{
+			DisplayNameFromDS: "Trace ID",
+			Links: []data.DataLink{{
+				Title: "Trace: ${__value.raw}",
+				Internal: &data.InternalDataLink{
+					DatasourceUID:  dsInfo.UID,
+					DatasourceName: dsInfo.Name,
+					Query: map[string]string{
+						"query":     "${__value.raw}",
+						"queryType": string(dataquery.TempoQueryTypeTraceql),
+					},
+				},
+			}},
+		}),
+		data.NewField("startTime", nil, startTimes).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Start time"}),
+		data.NewField("traceName", nil, names).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Name"}),
+		data.NewField("traceDuration", nil, durations).SetConfig(&data.FieldConfig{DisplayNameFromDS: "Duration", Unit: "ms"}),
+	)
+	frame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	return frame
+}
+
+func traceStartTime(trace traceSearchMetadata) time.Time {
+	nanos, err := strconv.ParseInt(trace.StartTimeUnixNano, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos).UTC()
+}
+
+// traceQLFromFilters builds the TraceQL query of the filters of a TraceQL
+// search query, the same way as the query editor.
+func traceQLFromFilters(model *dataquery.TempoQuery) string {
+	conditions := make([]string, 0, len(model.Filters))
+	for _, f := range model.Filters {
+		if f.Tag == nil || *f.Tag == "" || f.Operator == nil || *f.Operator == "" || f.Value == nil {
+			continue
+		}
+
+		quote := f.ValueType != nil && *f.ValueType == "string"
+		var value string
+		switch v := (*f.Value).(type) {
+		case string:
+			value = v
+		case []interface{}:
+			values := make([]string, 0, len(v))
+			for _, item := range v {
+				values = append(values, fmt.Sprint(item))
+			}
+			value = strings.Join(values, "|")
+			quote = quote || len(values) > 1
+		default:
+			value = fmt.Sprint(v)
+		}
+		if value == "" {
+			continue
+		}
+		if quote {
+			value = `"` + value + `"`
+		}
+
+		scope := ""
+		if !intrinsics[*f.Tag] {
+			if f.Scope != nil && (*f.Scope == dataquery.TempoQueryFiltersScopeResource || *f.Scope == dataquery.TempoQueryFiltersScopeSpan) {
+				scope = string(*f.Scope)
+			}
+			scope += "."
+		}
+		conditions = append(conditions, scope+*f.Tag+*f.Operator+value)
+	}
+	return "{" + strings.Join(conditions, " && ") + "}"
+}
+
+// nativeSearchParams returns the search parameters of a tag-based search
+// query, which are validated the same way as in the query editor.
+func nativeSearchParams(model *dataquery.TempoQuery) (url.Values, error) {
+	tags := ""
+	if model.Search != nil {
+		tags = *model.Search
+	}
+	if model.ServiceName != nil && *model.ServiceName != "" {
+		tags += fmt.Sprintf(` service.name="%s"`, *model.ServiceName)
+	}
+	if model.SpanName != nil && *model.SpanName != "" {
+		tags += fmt.Sprintf(` name="%s"`, *model.SpanName)
+	}
+
+	params := url.Values{}
+	params.Set("tags", strings.TrimSpace(tags))
+
+	durations := []struct {
+		name  string
+		value *string
+	}{
+		{"minDuration", model.MinDuration},
+		{"maxDuration", model.MaxDuration},
+	}
+	for _, d := range durations {
+		if d.value == nil || *d.value == "" {
+			continue
+		}
+		duration := strings.Join(strings.Fields(*d.value), "")
+		if _, err := time.ParseDuration(duration); err != nil {
+			return nil, fmt.Errorf("invalid %s %q", d.name, *d.value)
+		}
+		params.Set(d.name, duration)
+	}
+
+	return params, nil
+}
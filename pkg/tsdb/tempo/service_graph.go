@@ -0,0 +1,316 @@
+package tempo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/contexthandler/ctxkey"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/adapters"
+	"github.com/grafana/grafana/pkg/tsdb/tempo/kinds/dataquery"
+)
+
+// The service graph metrics generated by Tempo in Prometheus. The number of
+// requests between two services and their duration are counted per client
+// and server.
+const (
+	secondsMetric = "traces_service_graph_request_server_seconds_sum"
+	totalsMetric  = "traces_service_graph_request_total"
+	failedMetric  = "traces_service_graph_request_failed_total"
+)
+
+var serviceGraphMetrics = []string{secondsMetric, totalsMetric, failedMetric}
+
+var (
+	errServiceMapNotConfigured = errors.New("the service graph requires a linked Prometheus data source")
+	errServiceMapAccessDenied  = errors.New("the service graph requires permission to query the linked Prometheus data source")
+)
+
+// serviceGraphStats are the numbers of requests and the time spent handling
+// them over the time range of a query.
+type serviceGraphStats struct {
+	total   float64
+	seconds float64
+	failed  float64
+}
+
+func (st *serviceGraphStats) add(metric string, value float64) {
+	switch metric {
+	case totalsMetric:
+		st.total += value
+	case secondsMetric:
+		st.seconds += value
+	case failedMetric:
+		st.failed += value
+	}
+}
+
+type serviceGraphEdge struct {
+	serviceGraphStats
+	source string
+	target string
+}
+
+// queryServiceMap queries the service graph metrics from the linked Prometheus
+// data source and converts them to the nodes and edges of a node graph.
+func (s *Service) queryServiceMap(ctx context.Context, pluginCtx backend.PluginContext, dsInfo *datasourceInfo, q backend.DataQuery, model *dataquery.TempoQuery) backend.DataResponse {
+	queryRes := backend.DataResponse{}
+	if dsInfo.ServiceMapDatasourceUID == "" {
+		queryRes.Error = errServiceMapNotConfigured
+		return queryRes
+	}
+
+	if err := s.canQueryServiceMapDatasource(ctx, dsInfo.ServiceMapDatasourceUID); err != nil {
+		queryRes.Error = err
+		return queryRes
+	}
+
+	promSettings, err := s.getServiceMapDatasource(ctx, pluginCtx.OrgID, dsInfo.ServiceMapDatasourceUID)
+	if err != nil {
+		queryRes.Error = err
+		return queryRes
+	}
+
+	filter := ""
+	if model.ServiceMapQuery != nil {
+		filter, err = serviceMapFilter(*model.ServiceMapQuery)
+		if err != nil {
+			queryRes.Error = err
+			return queryRes
+		}
+	}
+	queries := make([]backend.DataQuery, 0, len(serviceGraphMetrics))
+	for _, metric := range serviceGraphMetrics {
+		promQuery, err := json.Marshal(map[string]interface{}{
+			"refId":   metric,
+			"expr":    fmt.Sprintf("sum by (client, server) (increase(%s%s[$__range]))", metric, filter),
+			"instant": true,
+			"range":   false,
+		})
+		if err != nil {
+			queryRes.Error = err
+			return queryRes
+		}
+		queries = append(queries, backend.DataQuery{
+			RefID:         metric,
+			MaxDataPoints: q.MaxDataPoints,
+			Interval:      q.Interval,
+			TimeRange:     q.TimeRange,
+			JSON:          promQuery,
+		})
+	}
+
+	promRes, err := s.prometheus.QueryData(ctx, &backend.QueryDataRequest{
+		PluginContext: backend.PluginContext{
+			OrgID:                      pluginCtx.OrgID,
+			PluginID:                   promSettings.Type,
+			User:                       pluginCtx.User,
+			DataSourceInstanceSettings: promSettings,
+		},
+		Queries: queries,
+	})
+	if err != nil {
+		queryRes.Error = fmt.Errorf("failed to query the service graph metrics: %w", err)
+		return queryRes
+	}
+
+	nodes := map[string]*serviceGraphStats{}
+	edges := map[string]*serviceGraphEdge{}
+	for _, metric := range serviceGraphMetrics {
+		res := promRes.Responses[metric]
+		if res.Error != nil {
+			queryRes.Error = fmt.Errorf("failed to query the service graph metrics: %w", res.Error)
+			return queryRes
+		}
+		collectMetricData(metric, res.Frames, nodes, edges)
+	}
+
+	rangeSeconds := q.TimeRange.To.Sub(q.TimeRange.From).Seconds()
+	queryRes.Frames = serviceGraphToFrames(nodes, edges, rangeSeconds)
+	return queryRes
+}
+
+// canQueryServiceMapDatasource checks that the user running the query is
+// allowed to query the linked Prometheus data source, as the query does not go
+// through the query API checking it. Queries without a signed in user, such as
+// the ones of alert rules, are denied.
+func (s *Service) canQueryServiceMapDatasource(ctx context.Context, uid string) error {
+	reqCtx, ok := ctx.Value(ctxkey.Key{}).(*contextmodel.ReqContext)
+	if !ok || reqCtx == nil || reqCtx.SignedInUser == nil || s.accessControl == nil {
+		return errServiceMapAccessDenied
+	}
+
+	evaluator := accesscontrol.EvalPermission(datasources.ActionQuery, datasources.ScopeProvider.GetResourceScopeUID(uid))
+	allowed, err := s.accessControl.Evaluate(ctx, reqCtx.SignedInUser, evaluator)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errServiceMapAccessDenied
+	}
+	return nil
+}
+
+// serviceMapFilter parses the label matchers filtering the service graph
+// metrics, such as {client="app"}, and returns them formatted to be appended
+// to the metric names. Anything else than label matchers is rejected so the
+// filter cannot change the queries.
+func serviceMapFilter(query string) (string, error) {
+	if strings.TrimSpace(query) == "" {
+		return "", nil
+	}
+	matchers, err := parser.ParseMetricSelector(query)
+	if err != nil {
+		return "", fmt.Errorf("invalid service graph filter %q: %w", query, err)
+	}
+
+	filter := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		if m.Name == labels.MetricName {
+			return "", fmt.Errorf("invalid service graph filter %q: the metric name cannot be filtered", query)
+		}
+		filter = append(filter, m.String())
+	}
+	return "{" + strings.Join(filter, ",") + "}", nil
+}
+
+// getServiceMapDatasource returns the settings of the Prometheus data source
+// linked to the Tempo data source.
+func (s *Service) getServiceMapDatasource(ctx context.Context, orgID int64, uid string) (*backend.DataSourceInstanceSettings, error) {
+	ds, err := s.dataSourceService.GetDataSource(ctx, &datasources.GetDataSourceQuery{UID: uid, OrgID: orgID})
+	if err != nil {
+		if errors.Is(err, datasources.ErrDataSourceNotFound) {
+			return nil, fmt.Errorf("the service graph data source %q was not found", uid)
+		}
+		return nil, err
+	}
+	if ds.Type != datasources.DS_PROMETHEUS {
+		return nil, fmt.Errorf("the service graph data source %q is not a Prometheus data source", ds.Name)
+	}
+
+	return adapters.ModelToInstanceSettings(ds, func(ds *datasources.DataSource) (map[string]string, error) {
+		return s.dataSourceService.DecryptedValues(ctx, ds)
+	})
+}
+
+// collectMetricData adds the values of a metric to the edges between clients
+// and servers and to the server nodes. The client nodes are created without
+// stats as they show the requests they handled, not the ones they made.
+func collectMetricData(metric string, frames data.Frames, nodes map[string]*serviceGraphStats, edges map[string]*serviceGraphEdge) {
+	for _, frame := range frames {
+		for _, field := range frame.Fields {
+			if field.Type() != data.FieldTypeFloat64 && field.Type() != data.FieldTypeNullableFloat64 {
+				continue
+			}
+			client, server := field.Labels["client"], field.Labels["server"]
+			if server == "" || field.Len() == 0 {
+				continue
+			}
+			value, ok := field.ConcreteAt(field.Len() - 1)
+			if !ok || math.IsNaN(value.(float64)) {
+				continue
+			}
+
+			edgeID := client + "_" + server
+			if edges[edgeID] == nil {
+				edges[edgeID] = &serviceGraphEdge{source: client, target: server}
+			}
+			edges[edgeID].add(metric, value.(float64))
+
+			if nodes[server] == nil {
+				nodes[server] = &serviceGraphStats{}
+			}
+			nodes[server].add(metric, value.(float64))
+			if nodes[client] == nil {
+				nodes[client] = &serviceGraphStats{}
+			}
+		}
+	}
+}
+
+// serviceGraphToFrames returns the nodes and edges frames of a node graph.
+// The nodes show the average response time and the requests per second of the
+// requests they handled, and the ratio of failed requests.
+func serviceGraphToFrames(nodes map[string]*serviceGraphStats, edges map[string]*serviceGraphEdge, rangeSeconds float64) data.Frames {
+	nodeIDs := make([]string, 0, len(nodes))
+	for id := range nodes {
+		nodeIDs = append(nodeIDs, id)
+	}
+	sort.Strings(nodeIDs)
+
+	nodesFrame := data.NewFrame("Nodes",
+		data.NewField("id", nil, []string{}),
+		data.NewField("title", nil, []string{}).SetConfig(&data.FieldConfig{DisplayName: "Service name"}),
+		data.NewField("mainstat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Average response time", Unit: "ms/r"}),
+		data.NewField("secondarystat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Requests per second", Unit: "r/sec"}),
+		data.NewField("arc__success", nil, []float64{}).SetConfig(&data.FieldConfig{
+			DisplayName: "Success",
+			Color:       map[string]interface{}{"mode": "fixed", "fixedColor": "green"},
+		}),
+		data.NewField("arc__failed", nil, []float64{}).SetConfig(&data.FieldConfig{
+			DisplayName: "Failed",
+			Color:       map[string]interface{}{"mode": "fixed", "fixedColor": "red"},
+		}),
+	)
+	for _, id := range nodeIDs {
+		node := nodes[id]
+		success, failed := 1.0, 0.0
+		if node.total > 0 {
+			failed = math.Min(node.failed, node.total) / node.total
+			success = 1 - failed
+		}
+		nodesFrame.AppendRow(id, id, averageResponseTime(*node), requestRate(*node, rangeSeconds), success, failed)
+	}
+	nodesFrame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeNodeGraph}
+
+	edgeIDs := make([]string, 0, len(edges))
+	for id := range edges {
+		edgeIDs = append(edgeIDs, id)
+	}
+	sort.Strings(edgeIDs)
+
+	edgesFrame := data.NewFrame("Edges",
+		data.NewField("id", nil, []string{}),
+		data.NewField("source", nil, []string{}),
+		data.NewField("target", nil, []string{}),
+		data.NewField("mainstat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Average response time", Unit: "ms/r"}),
+		data.NewField("secondarystat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Requests per second", Unit: "r/sec"}),
+	)
+	for _, id := range edgeIDs {
+		edge := edges[id]
+		edgesFrame.AppendRow(id, edge.source, edge.target, averageResponseTime(edge.serviceGraphStats), requestRate(edge.serviceGraphStats, rangeSeconds))
+	}
+	edgesFrame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeNodeGraph}
+
+	return data.Frames{nodesFrame, edgesFrame}
+}
+
+// averageResponseTime returns the average response time in milliseconds, or
+// NaN, which the node graph does not show, for nodes that handled no requests.
+func averageResponseTime(st serviceGraphStats) float64 {
+	if st.total == 0 {
+		return math.NaN()
+	}
+	return st.seconds / st.total * 1000
+}
+
+// requestRate returns the requests per second rounded to 2 decimals, or NaN
+// for nodes that handled no requests.
+func requestRate(st serviceGraphStats, rangeSeconds float64) float64 {
+	if st.total == 0 || rangeSeconds <= 0 {
+		return math.NaN()
+	}
+	return math.Round(st.total/rangeSeconds*100) / 100
+}
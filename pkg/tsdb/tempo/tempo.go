@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/tsdb/tempo/kinds/dataquery"
 
@@ -17,23 +20,48 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/httpclient"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/datasources"
 )
 
+// PrometheusQuerier queries the Prometheus data source the service graph
+// metrics are stored in.
+type PrometheusQuerier interface {
+	QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error)
+}
+
 type Service struct {
-	im   instancemgmt.InstanceManager
-	tlog log.Logger
+	im                instancemgmt.InstanceManager
+	tlog              log.Logger
+	dataSourceService datasources.DataSourceService
+	accessControl     accesscontrol.AccessControl
+	prometheus        PrometheusQuerier
 }
 
-func ProvideService(httpClientProvider httpclient.Provider) *Service {
+func ProvideService(httpClientProvider httpclient.Provider, dataSourceService datasources.DataSourceService, accessControl accesscontrol.AccessControl, prometheus PrometheusQuerier) *Service {
 	return &Service{
-		tlog: log.New("tsdb.tempo"),
-		im:   datasource.NewInstanceManager(newInstanceSettings(httpClientProvider)),
+		tlog:              log.New("tsdb.tempo"),
+		im:                datasource.NewInstanceManager(newInstanceSettings(httpClientProvider)),
+		dataSourceService: dataSourceService,
+		accessControl:     accessControl,
+		prometheus:        prometheus,
 	}
 }
 
 type datasourceInfo struct {
 	HTTPClient *http.Client
 	URL        string
+	UID        string
+	Name       string
+	// ServiceMapDatasourceUID is the UID of the Prometheus data source the
+	// service graph metrics are queried from
+	ServiceMapDatasourceUID string
+}
+
+type jsonData struct {
+	ServiceMap struct {
+		DatasourceUID string `json:"datasourceUid"`
+	} `json:"serviceMap"`
 }
 
 func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.InstanceFactoryFunc {
@@ -48,9 +76,19 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			return nil, err
 		}
 
+		var jd jsonData
+		if len(settings.JSONData) > 0 {
+			if err := json.Unmarshal(settings.JSONData, &jd); err != nil {
+				return nil, fmt.Errorf("error reading settings: %w", err)
+			}
+		}
+
 		model := &datasourceInfo{
-			HTTPClient: client,
-			URL:        settings.URL,
+			HTTPClient:              client,
+			URL:                     settings.URL,
+			UID:                     settings.UID,
+			Name:                    settings.Name,
+			ServiceMapDatasourceUID: jd.ServiceMap.DatasourceUID,
 		}
 		return model, nil
 	}
@@ -58,28 +96,79 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 
 func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	result := backend.NewQueryDataResponse()
-	queryRes := backend.DataResponse{}
-	refID := req.Queries[0].RefID
-
-	model := &dataquery.TempoQuery{}
-	err := json.Unmarshal(req.Queries[0].JSON, model)
-	if err != nil {
-		return result, err
-	}
 
 	dsInfo, err := s.getDSInfo(req.PluginContext)
 	if err != nil {
 		return nil, err
 	}
 
-	request, err := s.createRequest(ctx, dsInfo, model.Query, req.Queries[0].TimeRange.From.Unix(), req.Queries[0].TimeRange.To.Unix())
+	for _, q := range req.Queries {
+		model := &dataquery.TempoQuery{}
+		if err := json.Unmarshal(q.JSON, model); err != nil {
+			result.Responses[q.RefID] = backend.DataResponse{Error: fmt.Errorf("failed to unmarshal query: %w", err)}
+			continue
+		}
+
+		queryRes := s.query(ctx, req.PluginContext, dsInfo, q, model)
+		for _, frame := range queryRes.Frames {
+			frame.RefID = q.RefID
+		}
+		result.Responses[q.RefID] = queryRes
+	}
+	return result, nil
+}
+
+// query runs a query according to its type. Queries without a type are trace
+// ID lookups, which were the only queries run by the backend before.
+func (s *Service) query(ctx context.Context, pluginCtx backend.PluginContext, dsInfo *datasourceInfo, q backend.DataQuery, model *dataquery.TempoQuery) backend.DataResponse {
+	queryType := ""
+	if model.QueryType != nil {
+		queryType = *model.QueryType
+	}
+
+	switch dataquery.TempoQueryType(queryType) {
+	case dataquery.TempoQueryTypeTraceql:
+		// Queries made of hex characters only are trace IDs
+		if traceIDRegex.MatchString(strings.TrimSpace(model.Query)) {
+			return s.queryTrace(ctx, dsInfo, q, strings.TrimSpace(model.Query))
+		}
+		return s.search(ctx, dsInfo, q, url.Values{"q": []string{model.Query}}, model.Limit)
+	case dataquery.TempoQueryTypeTraceqlSearch:
+		return s.search(ctx, dsInfo, q, url.Values{"q": []string{traceQLFromFilters(model)}}, model.Limit)
+	case dataquery.TempoQueryTypeNativeSearch:
+		params, err := nativeSearchParams(model)
+		if err != nil {
+			return backend.DataResponse{Error: err}
+		}
+		return s.search(ctx, dsInfo, q, params, model.Limit)
+	case dataquery.TempoQueryTypeServiceMap:
+		return s.queryServiceMap(ctx, pluginCtx, dsInfo, q, model)
+	case "":
+		return s.queryTrace(ctx, dsInfo, q, strings.TrimSpace(model.Query))
+	default:
+		return backend.DataResponse{Error: fmt.Errorf("query type %q is not supported by the backend", queryType)}
+	}
+}
+
+var traceIDRegex = regexp.MustCompile(`^[0-9A-Fa-f]*$`)
+
+// queryTrace looks up a trace by its ID.
+func (s *Service) queryTrace(ctx context.Context, dsInfo *datasourceInfo, q backend.DataQuery, traceID string) backend.DataResponse {
+	queryRes := backend.DataResponse{}
+	if traceID == "" {
+		return queryRes
+	}
+
+	request, err := s.createRequest(ctx, dsInfo, traceID, q.TimeRange.From.Unix(), q.TimeRange.To.Unix())
 	if err != nil {
-		return result, err
+		queryRes.Error = err
+		return queryRes
 	}
 
 	resp, err := dsInfo.HTTPClient.Do(request)
 	if err != nil {
-		return result, fmt.Errorf("failed get to tempo: %w", err)
+		queryRes.Error = fmt.Errorf("failed get to tempo: %w", err)
+		return queryRes
 	}
 
 	defer func() {
@@ -90,30 +179,30 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return &backend.QueryDataResponse{}, err
+		queryRes.Error = err
+		return queryRes
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		queryRes.Error = fmt.Errorf("failed to get trace with id: %s Status: %s Body: %s", model.Query, resp.Status, string(body))
-		result.Responses[refID] = queryRes
-		return result, nil
+		queryRes.Error = fmt.Errorf("failed to get trace with id: %s Status: %s Body: %s", traceID, resp.Status, string(body))
+		return queryRes
 	}
 
 	otTrace, err := otlp.NewProtobufTracesUnmarshaler().UnmarshalTraces(body)
-
 	if err != nil {
-		return &backend.QueryDataResponse{}, fmt.Errorf("failed to convert tempo response to Otlp: %w", err)
+		queryRes.Error = fmt.Errorf("failed to convert tempo response to Otlp: %w", err)
+		return queryRes
 	}
 
 	frame, err := TraceToFrame(otTrace)
 	if err != nil {
-		return &backend.QueryDataResponse{}, fmt.Errorf("failed to transform trace %v to data frame: %w", model.Query, err)
+		queryRes.Error = fmt.Errorf("failed to transform trace %v to data frame: %w", traceID, err)
+		return queryRes
 	}
-	frame.RefID = refID
-	frames := []*data.Frame{frame}
-	queryRes.Frames = frames
-	result.Responses[refID] = queryRes
-	return result, nil
+	if frame != nil {
+		queryRes.Frames = data.Frames{frame}
+	}
+	return queryRes
 }
 
 func (s *Service) createRequest(ctx context.Context, dsInfo *datasourceInfo, traceID string, start int64, end int64) (*http.Request, error) {
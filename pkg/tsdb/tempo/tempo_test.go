@@ -2,11 +2,28 @@ package tempo
 
 import (
 	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
-	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/contexthandler/ctxkey"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakedatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
 )
 
 func TestTempo(t *testing.T) {
@@ -25,3 +42,264 @@ func TestTempo(t *testing.T) {
 		assert.Equal(t, "/api/traces/traceID?start=1&end=2", req.URL.String())
 	})
 }
+
+func TestSearch(t *testing.T) {
+	var requests []*http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"traces": [
+			{"traceID": "1a", "rootServiceName": "cart", "rootTraceName": "GET /cart", "startTimeUnixNano": "1600000000000000000", "durationMs": 12},
+			{"traceID": "2b", "rootServiceName": "checkout", "startTimeUnixNano": "1600000060000000000"}
+		]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	s := ProvideService(httpclient.NewProvider(), nil, nil, nil)
+	timeRange := backend.TimeRange{From: time.Unix(1600000000, 0), To: time.Unix(1600000300, 0)}
+	query := func(t *testing.T, model map[string]interface{}) backend.DataResponse {
+		t.Helper()
+		resp, err := s.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+				ID: 1, UID: "tempo", Name: "Tempo", URL: srv.URL,
+			}},
+			Queries: []backend.DataQuery{{RefID: "A", TimeRange: timeRange, JSON: mustJSON(t, model)}},
+		})
+		require.NoError(t, err)
+		return resp.Responses["A"]
+	}
+
+	t.Run("runs TraceQL queries", func(t *testing.T) {
+		requests = nil
+		resp := query(t, map[string]interface{}{"queryType": "traceql", "query": `{ .http.status_code = 500 }`, "limit": 5})
+		require.NoError(t, resp.Error)
+
+		require.Len(t, requests, 1)
+		require.Equal(t, "/api/search", requests[0].URL.Path)
+		params := requests[0].URL.Query()
+		require.Equal(t, `{ .http.status_code = 500 }`, params.Get("q"))
+		require.Equal(t, "5", params.Get("limit"))
+		require.Equal(t, "1600000000", params.Get("start"))
+		require.Equal(t, "1600000300", params.Get("end"))
+
+		require.Len(t, resp.Frames, 1)
+		frame := resp.Frames[0]
+		require.Equal(t, "A", frame.RefID)
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, "2b", frame.Fields[0].At(0))
+		require.Equal(t, time.Unix(1600000060, 0).UTC(), frame.Fields[1].At(0))
+		require.Equal(t, "checkout", frame.Fields[2].At(0))
+		require.Equal(t, "cart GET /cart", frame.Fields[2].At(1))
+		require.Equal(t, int64(12), frame.Fields[3].At(1))
+		require.Equal(t, "tempo", frame.Fields[0].Config.Links[0].Internal.DatasourceUID)
+	})
+
+	t.Run("runs TraceQL searches", func(t *testing.T) {
+		requests = nil
+		resp := query(t, map[string]interface{}{
+			"queryType": "traceqlSearch",
+			"filters": []map[string]interface{}{
+				{"id": "service-name", "tag": "service.name", "operator": "=", "scope": "resource", "value": []string{"cart", "checkout"}, "valueType": "string"},
+				{"id": "span-name", "tag": "name", "operator": "=", "value": "GET /cart", "valueType": "string"},
+				{"id": "status", "tag": "http.status_code", "operator": ">=", "scope": "unscoped", "value": "500", "valueType": "int"},
+				{"id": "min-duration", "tag": "duration", "operator": ">"},
+			},
+		})
+		require.NoError(t, resp.Error)
+		require.Len(t, requests, 1)
+		require.Equal(t, `{resource.service.name="cart|checkout" && name="GET /cart" && .http.status_code>=500}`, requests[0].URL.Query().Get("q"))
+		require.Equal(t, "20", requests[0].URL.Query().Get("limit"))
+	})
+
+	t.Run("runs tag searches", func(t *testing.T) {
+		requests = nil
+		resp := query(t, map[string]interface{}{
+			"queryType":   "nativeSearch",
+			"search":      "http.status_code=500",
+			"serviceName": "cart",
+			"minDuration": "1 s",
+		})
+		require.NoError(t, resp.Error)
+		require.Len(t, requests, 1)
+		params := requests[0].URL.Query()
+		require.Equal(t, `http.status_code=500 service.name="cart"`, params.Get("tags"))
+		require.Equal(t, "1s", params.Get("minDuration"))
+		require.Empty(t, params.Get("maxDuration"))
+	})
+
+	t.Run("returns errors of invalid searches", func(t *testing.T) {
+		requests = nil
+		resp := query(t, map[string]interface{}{"queryType": "nativeSearch", "maxDuration": "1 parsec"})
+		require.EqualError(t, resp.Error, `invalid maxDuration "1 parsec"`)
+
+		resp = query(t, map[string]interface{}{"queryType": "traceql", "query": "{}", "limit": -1})
+		require.Error(t, resp.Error)
+
+		resp = query(t, map[string]interface{}{"queryType": "upload"})
+		require.EqualError(t, resp.Error, `query type "upload" is not supported by the backend`)
+		require.Empty(t, requests)
+	})
+
+	t.Run("looks up trace IDs in TraceQL queries", func(t *testing.T) {
+		requests = nil
+		resp := query(t, map[string]interface{}{"queryType": "traceql", "query": "1a2b"})
+		require.Error(t, resp.Error)
+		require.Len(t, requests, 1)
+		require.Equal(t, "/api/traces/1a2b", requests[0].URL.Path)
+	})
+}
+
+func TestServiceMap(t *testing.T) {
+	dsService := &fakedatasources.FakeDataSourceService{DataSources: []*datasources.DataSource{
+		{ID: 2, UID: "prom", Name: "Prometheus", Type: datasources.DS_PROMETHEUS, OrgID: 1, URL: "http://prometheus", JsonData: simplejson.New()},
+		{ID: 3, UID: "loki", Name: "Loki", Type: datasources.DS_LOKI, OrgID: 1, JsonData: simplejson.New()},
+	}}
+	prom := &fakePrometheus{responses: map[string][]float64{
+		totalsMetric:  {300, 600},
+		secondsMetric: {3, 12},
+		failedMetric:  {30, 0},
+	}}
+	s := ProvideService(httpclient.NewProvider(), dsService, fakeAccessControl{}, prom)
+
+	// the signed in user can query all data sources but loki-2
+	signedInUser := &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: {
+		datasources.ActionQuery: {"datasources:uid:prom", "datasources:uid:loki", "datasources:uid:missing"},
+	}}}
+	timeRange := backend.TimeRange{From: time.Unix(1600000000, 0), To: time.Unix(1600000300, 0)}
+	queryAs := func(t *testing.T, signedInUser *user.SignedInUser, id int64, serviceMapUID, filter string) backend.DataResponse {
+		t.Helper()
+		ctx := context.Background()
+		if signedInUser != nil {
+			ctx = context.WithValue(ctx, ctxkey.Key{}, &contextmodel.ReqContext{SignedInUser: signedInUser})
+		}
+		resp, err := s.QueryData(ctx, &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{OrgID: 1, DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+				ID:       id,
+				JSONData: mustJSON(t, map[string]interface{}{"serviceMap": map[string]string{"datasourceUid": serviceMapUID}}),
+			}},
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				TimeRange: timeRange,
+				JSON:      mustJSON(t, map[string]interface{}{"queryType": "serviceMap", "serviceMapQuery": filter}),
+			}},
+		})
+		require.NoError(t, err)
+		return resp.Responses["A"]
+	}
+	query := func(t *testing.T, id int64, serviceMapUID string) backend.DataResponse {
+		t.Helper()
+		return queryAs(t, signedInUser, id, serviceMapUID, `{client="app"}`)
+	}
+
+	t.Run("queries the service graph metrics", func(t *testing.T) {
+		resp := query(t, 1, "prom")
+		require.NoError(t, resp.Error)
+
+		require.Equal(t, "prom", prom.req.PluginContext.DataSourceInstanceSettings.UID)
+		require.Len(t, prom.req.Queries, 3)
+		var model map[string]interface{}
+		require.NoError(t, json.Unmarshal(prom.req.Queries[1].JSON, &model))
+		require.Equal(t, `sum by (client, server) (increase(traces_service_graph_request_total{client="app"}[$__range]))`, model["expr"])
+		require.Equal(t, true, model["instant"])
+
+		require.Len(t, resp.Frames, 2)
+		nodes, edges := resp.Frames[0], resp.Frames[1]
+		require.Equal(t, data.VisTypeNodeGraph, string(nodes.Meta.PreferredVisualization))
+
+		// app calls cart and checkout, which also calls cart
+		require.Equal(t, 3, nodes.Rows())
+		require.Equal(t, "app", nodes.Fields[0].At(0))
+		require.True(t, math.IsNaN(nodes.Fields[2].At(0).(float64)))
+		require.Equal(t, "cart", nodes.Fields[0].At(1))
+		require.Equal(t, 10.0, nodes.Fields[2].At(1))
+		require.Equal(t, 2.0, nodes.Fields[3].At(1))
+		require.Equal(t, 0.9, nodes.Fields[4].At(1))
+		require.Equal(t, 0.1, nodes.Fields[5].At(1))
+		require.Equal(t, "checkout", nodes.Fields[0].At(2))
+		require.Equal(t, 20.0, nodes.Fields[2].At(2))
+
+		require.Equal(t, 3, edges.Rows())
+		require.Equal(t, "app_cart", edges.Fields[0].At(0))
+		require.Equal(t, "app", edges.Fields[1].At(0))
+		require.Equal(t, "cart", edges.Fields[2].At(0))
+		require.Equal(t, 10.0, edges.Fields[3].At(0))
+		require.Equal(t, 1.0, edges.Fields[4].At(0))
+	})
+
+	t.Run("returns errors of invalid service graph data sources", func(t *testing.T) {
+		require.ErrorIs(t, query(t, 2, "").Error, errServiceMapNotConfigured)
+		require.EqualError(t, query(t, 3, "missing").Error, `the service graph data source "missing" was not found`)
+		require.EqualError(t, query(t, 4, "loki").Error, `the service graph data source "Loki" is not a Prometheus data source`)
+	})
+
+	t.Run("denies users who cannot query the service graph data source", func(t *testing.T) {
+		prom.req = nil
+		require.ErrorIs(t, query(t, 5, "loki-2").Error, errServiceMapAccessDenied)
+		require.ErrorIs(t, queryAs(t, nil, 1, "prom", "").Error, errServiceMapAccessDenied)
+		require.Nil(t, prom.req)
+	})
+
+	t.Run("only accepts label matchers as filter", func(t *testing.T) {
+		resp := queryAs(t, signedInUser, 1, "prom", `{ client =~ "app|web", server!="" }`)
+		require.NoError(t, resp.Error)
+		var model map[string]interface{}
+		require.NoError(t, json.Unmarshal(prom.req.Queries[0].JSON, &model))
+		require.Equal(t, `sum by (client, server) (increase(traces_service_graph_request_server_seconds_sum{client=~"app|web",server!=""}[$__range]))`, model["expr"])
+
+		prom.req = nil
+		for _, filter := range []string{`{client="app"}) or vector(1`, `{client="app"}[5m]`, `other_metric{client="app"}`} {
+			require.Error(t, queryAs(t, signedInUser, 1, "prom", filter).Error, filter)
+		}
+		require.Nil(t, prom.req)
+	})
+}
+
+// fakeAccessControl evaluates the permissions of the user in their current
+// organization.
+type fakeAccessControl struct {
+	actest.FakeAccessControl
+}
+
+func (f fakeAccessControl) Evaluate(_ context.Context, user *user.SignedInUser, evaluator accesscontrol.Evaluator) (bool, error) {
+	return evaluator.Evaluate(user.Permissions[user.OrgID]), nil
+}
+
+// fakePrometheus returns the values of the service graph metrics for the
+// edges app -> cart, app -> checkout and checkout -> cart.
+type fakePrometheus struct {
+	responses map[string][]float64
+	req       *backend.QueryDataRequest
+}
+
+func (p *fakePrometheus) QueryData(_ context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	p.req = req
+	edges := []data.Labels{
+		{"client": "app", "server": "cart"},
+		{"client": "app", "server": "checkout"},
+		{"client": "checkout", "server": "cart"},
+	}
+	resp := backend.NewQueryDataResponse()
+	for _, q := range req.Queries {
+		values := p.responses[q.RefID]
+		var frames data.Frames
+		for i, labels := range edges {
+			value := values[0]
+			if i == 1 {
+				value = values[1]
+			}
+			frames = append(frames, data.NewFrame("",
+				data.NewField(data.TimeSeriesTimeFieldName, nil, []time.Time{time.Unix(1600000300, 0)}),
+				data.NewField(data.TimeSeriesValueFieldName, labels, []float64{value}),
+			))
+		}
+		resp.Responses[q.RefID] = backend.DataResponse{Frames: frames}
+	}
+	return resp, nil
+}
+
+func mustJSON(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return b
+}
@@ -15,6 +15,12 @@ export interface FormModel {
   email: string;
 }
 
+export interface TwoFactorEnrollment {
+  secret: string;
+  url: string;
+  recoveryCodes: string[];
+}
+
 interface Props {
   resetCode?: string;
 
@@ -29,22 +35,29 @@ interface Props {
     isOauthEnabled: boolean;
     loginHint: string;
     passwordHint: string;
+    isTwoFactorRequired: boolean;
+    twoFactorEnrollment?: TwoFactorEnrollment;
+    submitTwoFactorCode: (code: string) => void;
   }) => JSX.Element;
 }
 
 interface State {
   isLoggingIn: boolean;
   isChangingPassword: boolean;
+  isTwoFactorRequired: boolean;
+  twoFactorEnrollment?: TwoFactorEnrollment;
 }
 
 export class LoginCtrl extends PureComponent<Props, State> {
   result: any = {};
+  isDefaultPassword = false;
 
   constructor(props: Props) {
     super(props);
     this.state = {
       isLoggingIn: false,
       isChangingPassword: false,
+      isTwoFactorRequired: false,
     };
 
     if (config.loginError) {
@@ -89,13 +102,12 @@ export class LoginCtrl extends PureComponent<Props, State> {
     getBackendSrv()
       .post('/login', formModel)
       .then((result) => {
-        this.result = result;
-        if (formModel.password !== 'admin' || config.ldapEnabled || config.authProxyEnabled) {
-          this.toGrafana();
+        this.isDefaultPassword = formModel.password === 'admin';
+        if (result.twoFactorRequired) {
+          this.startTwoFactor(result.twoFactorEnroll);
           return;
-        } else {
-          this.changeView();
         }
+        this.loggedIn(result);
       })
       .catch(() => {
         this.setState({
@@ -104,6 +116,57 @@ export class LoginCtrl extends PureComponent<Props, State> {
       });
   };
 
+  startTwoFactor = (enroll: boolean) => {
+    this.setState({
+      isLoggingIn: false,
+      isTwoFactorRequired: true,
+    });
+
+    if (enroll) {
+      getBackendSrv()
+        .post('/login/2fa/enroll')
+        .then((enrollment: TwoFactorEnrollment) => {
+          this.setState({
+            twoFactorEnrollment: enrollment,
+          });
+        });
+    }
+  };
+
+  submitTwoFactorCode = (code: string) => {
+    this.setState({
+      isLoggingIn: true,
+    });
+
+    getBackendSrv()
+      .post('/login/2fa', { code })
+      .then((result) => {
+        this.setState({
+          isTwoFactorRequired: false,
+        });
+        this.loggedIn(result);
+      })
+      .catch((err) => {
+        // the challenge expired or too many invalid codes were entered, start over from the password
+        const isExpired = err?.status === 401 && err?.data?.messageId === 'twofactor.challenge-not-found';
+        this.setState({
+          isLoggingIn: false,
+          isTwoFactorRequired: !isExpired,
+          twoFactorEnrollment: isExpired ? undefined : this.state.twoFactorEnrollment,
+        });
+      });
+  };
+
+  loggedIn = (result: any) => {
+    this.result = result;
+    if (!this.isDefaultPassword || config.ldapEnabled || config.authProxyEnabled) {
+      this.toGrafana();
+      return;
+    } else {
+      this.changeView();
+    }
+  };
+
   changeView = () => {
     this.setState({
       isChangingPassword: true,
@@ -125,8 +188,8 @@ export class LoginCtrl extends PureComponent<Props, State> {
 
   render() {
     const { children } = this.props;
-    const { isLoggingIn, isChangingPassword } = this.state;
-    const { login, toGrafana, changePassword } = this;
+    const { isLoggingIn, isChangingPassword, isTwoFactorRequired, twoFactorEnrollment } = this.state;
+    const { login, toGrafana, changePassword, submitTwoFactorCode } = this;
     const { loginHint, passwordHint, disableLoginForm, disableUserSignUp } = config;
 
     return (
@@ -142,6 +205,9 @@ export class LoginCtrl extends PureComponent<Props, State> {
           changePassword,
           skipPasswordChange: toGrafana,
           isChangingPassword,
+          isTwoFactorRequired,
+          twoFactorEnrollment,
+          submitTwoFactorCode,
         })}
       </>
     );
@@ -13,6 +13,7 @@ import LoginCtrl from './LoginCtrl';
 import { LoginForm } from './LoginForm';
 import { LoginLayout, InnerBox } from './LoginLayout';
 import { LoginServiceButtons } from './LoginServiceButtons';
+import { TwoFactorForm } from './TwoFactorForm';
 import { UserSignup } from './UserSignup';
 
 const forgottenPasswordStyles = css`
@@ -35,9 +36,21 @@ export const LoginPage = () => {
           changePassword,
           skipPasswordChange,
           isChangingPassword,
+          isTwoFactorRequired,
+          twoFactorEnrollment,
+          submitTwoFactorCode,
         }) => (
           <>
-            {!isChangingPassword && (
+            {isTwoFactorRequired && (
+              <InnerBox>
+                <TwoFactorForm
+                  onSubmit={submitTwoFactorCode}
+                  isLoggingIn={isLoggingIn}
+                  enrollment={twoFactorEnrollment}
+                />
+              </InnerBox>
+            )}
+            {!isChangingPassword && !isTwoFactorRequired && (
               <InnerBox>
                 {!disableLoginForm && (
                   <LoginForm
@@ -0,0 +1,74 @@
+import { css } from '@emotion/css';
+import React from 'react';
+
+import { Alert, Button, Field, Form, Input } from '@grafana/ui';
+
+import { TwoFactorEnrollment } from './LoginCtrl';
+import { submitButton } from './LoginForm';
+
+interface Props {
+  onSubmit: (code: string) => void;
+  isLoggingIn: boolean;
+  enrollment?: TwoFactorEnrollment;
+}
+
+interface TwoFactorFormModel {
+  code: string;
+}
+
+const wrapperStyles = css`
+  width: 100%;
+  padding-bottom: 16px;
+`;
+
+const codeListStyles = css`
+  columns: 2;
+  font-family: monospace;
+  list-style: none;
+  margin: 8px 0 0;
+`;
+
+export const TwoFactorForm = ({ onSubmit, isLoggingIn, enrollment }: Props) => {
+  return (
+    <div className={wrapperStyles}>
+      {enrollment && (
+        <Alert severity="info" title="Set up two-factor authentication">
+          <p>
+            Two-factor authentication is required for your account. Add this key to your authenticator app, or open
+            the <a href={enrollment.url}>setup link</a> on your phone, then enter the code it shows.
+          </p>
+          <pre>{enrollment.secret}</pre>
+          <p>Store these recovery codes somewhere safe. Each of them can be used once if you lose your device.</p>
+          <ul className={codeListStyles}>
+            {enrollment.recoveryCodes.map((code) => (
+              <li key={code}>{code}</li>
+            ))}
+          </ul>
+        </Alert>
+      )}
+      <Form<TwoFactorFormModel> onSubmit={(data) => onSubmit(data.code)} validateOn="onChange">
+        {({ register, errors }) => (
+          <>
+            <Field
+              label="Authentication code"
+              description={enrollment ? undefined : 'Enter the code from your authenticator app or a recovery code'}
+              invalid={!!errors.code}
+              error={errors.code?.message}
+            >
+              <Input
+                {...register('code', { required: 'Code is required' })}
+                autoFocus
+                autoComplete="one-time-code"
+                autoCapitalize="none"
+                id="two-factor-code"
+              />
+            </Field>
+            <Button type="submit" className={submitButton} disabled={isLoggingIn}>
+              {isLoggingIn ? 'Verifying...' : 'Verify'}
+            </Button>
+          </>
+        )}
+      </Form>
+    </div>
+  );
+};
@@ -8,9 +8,10 @@ const selectors = e2eSelectors.pages.ShareDashboardModal.PublicDashboard;
 export const UnsupportedTemplateVariablesAlert = () => (
   <Alert
     severity="warning"
-    title="Template variables are not supported"
+    title="Template variables are limited"
     data-testid={selectors.TemplateVariablesWarningAlert}
   >
-    This public dashboard may not work since it uses template variables
+    Viewers can only change the template variables and values allowed for this public dashboard. Other variables
+    always use the values saved in the dashboard
   </Alert>
 );
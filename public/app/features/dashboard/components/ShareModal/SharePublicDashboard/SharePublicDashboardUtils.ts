@@ -21,6 +21,8 @@ export interface PublicDashboard extends PublicDashboardSettings {
   uid: string;
   dashboardUid: string;
   timeSettings?: object;
+  maxTimeRange?: string;
+  variableSettings?: { allowed?: Record<string, string[]> };
   share: PublicDashboardShareType;
   recipients?: Array<{ uid: string; recipient: string }>;
 }
@@ -28,6 +28,15 @@ jest.mock('@grafana/runtime', () => ({
       getInstanceSettings: (ref?: DataSourceRef) => ({ type: ref?.type ?? '?', uid: ref?.uid ?? '?' }),
     };
   },
+  getTemplateSrv: () => ({
+    getVariables: () => [
+      { name: 'env', type: 'custom', hide: 0, current: { value: 'prod' } },
+      { name: 'host', type: 'custom', hide: 0, current: { value: ['a', 'b'] } },
+      { name: 'region', type: 'custom', hide: 2, current: { value: 'eu' } },
+      { name: 'interval', type: 'interval', hide: 0, current: { value: '$__auto_interval_interval' } },
+      { name: 'constant', type: 'constant', hide: 2, current: { value: 'c' } },
+    ],
+  }),
 }));
 
 describe('PublicDashboardDatasource', () => {
@@ -132,6 +141,7 @@ describe('PublicDashboardDatasource', () => {
     expect(mock.lastCall[0].url).toEqual(
       `/api/public/dashboards/${publicDashboardAccessToken}/panels/${panelId}/query`
     );
+    expect(mock.lastCall[0].data.variables).toEqual({ env: ['prod'], host: ['a', 'b'] });
   });
 
   test('returns public datasource uid when datasource passed in is null', () => {
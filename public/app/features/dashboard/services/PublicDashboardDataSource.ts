@@ -10,8 +10,9 @@ import {
   DataSourcePluginMeta,
   DataSourceRef,
   toDataFrame,
+  VariableHide,
 } from '@grafana/data';
-import { BackendDataSourceResponse, getBackendSrv, toDataQueryResponse } from '@grafana/runtime';
+import { BackendDataSourceResponse, getBackendSrv, getTemplateSrv, toDataQueryResponse } from '@grafana/runtime';
 
 import { GrafanaQueryType } from '../../../plugins/datasource/grafana/types';
 import { MIXED_DATASOURCE_NAME } from '../../../plugins/datasource/mixed/MixedDataSource';
@@ -113,6 +114,7 @@ export class PublicDashboardDataSource extends DataSourceApi<DataQuery, DataSour
         maxDataPoints,
         queryCachingTTL,
         timeRange: { from: fromRange.valueOf().toString(), to: toRange.valueOf().toString() },
+        variables: this.getVariableValues(),
       };
 
       return getBackendSrv()
@@ -133,6 +135,20 @@ export class PublicDashboardDataSource extends DataSourceApi<DataQuery, DataSour
     }
   }
 
+  // Selected values of the variables viewers can change. The server turns those into visible custom variables,
+  // all other variables always use the values saved in the dashboard and are not sent.
+  getVariableValues(): Record<string, string[]> {
+    const variables: Record<string, string[]> = {};
+    for (const variable of getTemplateSrv().getVariables()) {
+      if (variable.type !== 'custom' || variable.hide === VariableHide.hideVariable || !variable.current) {
+        continue;
+      }
+      const value = variable.current.value;
+      variables[variable.name] = Array.isArray(value) ? value : [value];
+    }
+    return variables;
+  }
+
   async getAnnotations(request: DataQueryRequest<DataQuery>): Promise<DataQueryResponse> {
     const {
       publicDashboardAccessToken: accessToken,
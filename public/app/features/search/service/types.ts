@@ -9,6 +9,7 @@ export interface FacetField {
 export interface SearchQuery {
   query?: string;
   location?: string;
+  folder?: string; // folder UID, matches dashboards and their panels
   sort?: string;
   ds_uid?: string;
   ds_type?: string;
@@ -16,6 +17,9 @@ export interface SearchQuery {
   tags?: string[];
   kind?: string[];
   panel_type?: string;
+  transformer?: string;
+  library_panel?: string;
+  fullText?: boolean; // also match descriptions, panel queries, transformations and library panels
   uid?: string[];
   facet?: FacetField[];
   explain?: boolean;
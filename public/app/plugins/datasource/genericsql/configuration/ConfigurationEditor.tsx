@@ -0,0 +1,118 @@
+import React from 'react';
+
+import {
+  DataSourcePluginOptionsEditorProps,
+  onUpdateDatasourceJsonDataOption,
+  onUpdateDatasourceSecureJsonDataOption,
+  SelectableValue,
+  updateDatasourcePluginJsonDataOption,
+  updateDatasourcePluginResetOption,
+} from '@grafana/data';
+import { Alert, FieldSet, InlineField, Input, Link, SecretInput, Select } from '@grafana/ui';
+import { ConnectionLimits } from 'app/features/plugins/sql/components/configuration/ConnectionLimits';
+
+import { GenericSqlOptions, MacroDialect, SecureJsonData } from '../types';
+
+const macroDialects: Array<SelectableValue<MacroDialect | undefined>> = [
+  { label: 'Driver default', value: undefined, description: 'Chosen from the driver name' },
+  { label: 'ANSI SQL', value: MacroDialect.ansi },
+  { label: 'PostgreSQL', value: MacroDialect.postgres },
+  { label: 'MySQL', value: MacroDialect.mysql },
+  { label: 'ClickHouse', value: MacroDialect.clickhouse },
+];
+
+export const GenericSqlConfigEditor = (
+  props: DataSourcePluginOptionsEditorProps<GenericSqlOptions, SecureJsonData>
+) => {
+  const { options, onOptionsChange } = props;
+  const jsonData = options.jsonData;
+
+  const onResetDSN = () => {
+    updateDatasourcePluginResetOption(props, 'dsn');
+  };
+
+  const onMacroDialectChanged = (value: SelectableValue<MacroDialect | undefined>) => {
+    updateDatasourcePluginJsonDataOption(props, 'macroDialect', value.value);
+  };
+
+  const labelWidthConnection = 20;
+  const labelWidthShort = 20;
+
+  return (
+    <>
+      <FieldSet label="Connection" width={400}>
+        <InlineField
+          labelWidth={labelWidthConnection}
+          label="Driver"
+          tooltip="Name the database/sql driver is registered with, for example postgres"
+        >
+          <Input
+            width={40}
+            name="driver"
+            value={jsonData.driver || ''}
+            placeholder="driver name"
+            onChange={onUpdateDatasourceJsonDataOption(props, 'driver')}
+          ></Input>
+        </InlineField>
+        <InlineField
+          labelWidth={labelWidthConnection}
+          label="Data source name"
+          tooltip="Connection string of the driver, it can contain credentials and is stored encrypted"
+        >
+          <SecretInput
+            width={40}
+            placeholder="DSN"
+            isConfigured={options.secureJsonFields?.dsn}
+            onReset={onResetDSN}
+            onBlur={onUpdateDatasourceSecureJsonDataOption(props, 'dsn')}
+          ></SecretInput>
+        </InlineField>
+        <InlineField
+          labelWidth={labelWidthConnection}
+          label="Macro dialect"
+          tooltip="SQL dialect the time macros like $__timeFilter are expanded in"
+          htmlFor="macroDialect"
+        >
+          <Select
+            width={40}
+            inputId="macroDialect"
+            options={macroDialects}
+            value={jsonData.macroDialect}
+            onChange={onMacroDialectChanged}
+          ></Select>
+        </InlineField>
+      </FieldSet>
+
+      <ConnectionLimits labelWidth={labelWidthShort} options={options} onOptionsChange={onOptionsChange} />
+
+      <FieldSet label="Query details">
+        <InlineField
+          tooltip={
+            <span>
+              A lower limit for the auto group by time interval. Recommended to be set to write frequency, for example
+              <code>1m</code> if your data is written every minute.
+            </span>
+          }
+          labelWidth={labelWidthShort}
+          label="Min time interval"
+        >
+          <Input
+            placeholder="1m"
+            value={jsonData.timeInterval || ''}
+            onChange={onUpdateDatasourceJsonDataOption(props, 'timeInterval')}
+          ></Input>
+        </InlineField>
+      </FieldSet>
+
+      <Alert title="Drivers" severity="info">
+        The driver must be linked into the Grafana server and allowed by the <code>generic_sql_allowed_drivers</code>
+        setting. Grafana does not validate that queries are safe, so the database user should only be granted read
+        permissions on the tables you want to query. Check out the{' '}
+        <Link rel="noreferrer" target="_blank" href="http://docs.grafana.org/features/datasources/genericsql/">
+          Generic SQL Data Source Docs
+        </Link>{' '}
+        for more information.
+      </Alert>
+    </>
+  );
+};
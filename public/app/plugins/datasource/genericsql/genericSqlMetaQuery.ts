@@ -0,0 +1,12 @@
+export function showTables() {
+  return `SELECT table_name AS "table" FROM information_schema.tables
+    WHERE lower(table_schema) NOT IN
+      ('information_schema', 'pg_catalog', 'system', 'mysql', 'performance_schema', 'sys')
+    ORDER BY table_name`;
+}
+
+export function getSchema(table?: string) {
+  return `SELECT column_name AS "column", lower(data_type) AS "type"
+    FROM information_schema.columns
+    WHERE table_name = '${(table ?? '').replace(/'/g, "''")}'`;
+}
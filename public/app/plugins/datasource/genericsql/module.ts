@@ -0,0 +1,16 @@
+import { DataSourcePlugin } from '@grafana/data';
+import { SQLQuery } from 'app/features/plugins/sql/types';
+
+import { QueryEditor } from '../sqlite/QueryEditor';
+
+import { CheatSheet } from './CheatSheet';
+import { GenericSqlConfigEditor } from './configuration/ConfigurationEditor';
+import { GenericSqlDatasource } from './datasource';
+import { GenericSqlOptions, SecureJsonData } from './types';
+
+export const plugin = new DataSourcePlugin<GenericSqlDatasource, SQLQuery, GenericSqlOptions, SecureJsonData>(
+  GenericSqlDatasource
+)
+  .setQueryEditor(QueryEditor)
+  .setQueryEditorHelp(CheatSheet)
+  .setConfigEditor(GenericSqlConfigEditor);
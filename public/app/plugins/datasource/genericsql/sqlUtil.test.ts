@@ -0,0 +1,51 @@
+import { SQLQuery } from 'app/features/plugins/sql/types';
+
+import { getFieldConfig, toRawSql } from './sqlUtil';
+import { getMacroDialect, MacroDialect } from './types';
+
+describe('getFieldConfig', () => {
+  test.each([
+    { type: 'boolean', dialect: MacroDialect.ansi, expected: 'boolean' },
+    { type: 'timestamp with time zone', dialect: MacroDialect.postgres, expected: 'datetime' },
+    { type: 'double precision', dialect: MacroDialect.postgres, expected: 'number' },
+    { type: 'money', dialect: MacroDialect.ansi, expected: 'text' },
+    { type: 'money', dialect: MacroDialect.postgres, expected: 'number' },
+    { type: 'datetime', dialect: MacroDialect.mysql, expected: 'datetime' },
+    { type: 'mediumint', dialect: MacroDialect.mysql, expected: 'number' },
+    { type: 'varchar', dialect: MacroDialect.mysql, expected: 'text' },
+    { type: 'Nullable(Float64)', dialect: MacroDialect.clickhouse, expected: 'number' },
+    { type: 'DateTime64(3)', dialect: MacroDialect.clickhouse, expected: 'datetime' },
+    { type: 'LowCardinality(Nullable(String))', dialect: MacroDialect.clickhouse, expected: 'text' },
+    { type: 'Bool', dialect: MacroDialect.clickhouse, expected: 'boolean' },
+  ])('should return $expected for $type in the $dialect dialect', ({ type, dialect, expected }) => {
+    expect(getFieldConfig(type, dialect).raqbFieldType).toBe(expected);
+  });
+});
+
+describe('toRawSql', () => {
+  const query = {
+    refId: 'A',
+    table: 'metrics',
+    sql: { columns: [{ type: 'function', parameters: [{ type: 'functionParameter', name: 'host' }] }], limit: 10 },
+  } as SQLQuery;
+
+  it('should limit the rows with LIMIT', () => {
+    expect(toRawSql(query, MacroDialect.mysql)).toBe('SELECT host FROM metrics LIMIT 10 ');
+  });
+
+  it('should limit the rows with FETCH FIRST in ANSI SQL', () => {
+    expect(toRawSql(query, MacroDialect.ansi)).toBe('SELECT host FROM metrics FETCH FIRST 10 ROWS ONLY ');
+  });
+});
+
+describe('getMacroDialect', () => {
+  test.each([
+    { options: { driver: 'pgx' }, expected: MacroDialect.postgres },
+    { options: { driver: 'mysql' }, expected: MacroDialect.mysql },
+    { options: { driver: 'clickhouse' }, expected: MacroDialect.clickhouse },
+    { options: { driver: 'duckdb' }, expected: MacroDialect.ansi },
+    { options: { driver: 'mysql', macroDialect: MacroDialect.ansi }, expected: MacroDialect.ansi },
+  ])('should return $expected for $options', ({ options, expected }) => {
+    expect(getMacroDialect(options)).toBe(expected);
+  });
+});
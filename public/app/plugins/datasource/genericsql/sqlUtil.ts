@@ -0,0 +1,132 @@
+import { isEmpty } from 'lodash';
+
+import { RAQBFieldTypes, SQLQuery } from 'app/features/plugins/sql/types';
+import { createSelectClause, haveColumns } from 'app/features/plugins/sql/utils/sql.utils';
+
+import { MacroDialect } from './types';
+
+type FieldConfig = { raqbFieldType: RAQBFieldTypes; icon: string };
+
+const booleanField: FieldConfig = { raqbFieldType: 'boolean', icon: 'toggle-off' };
+const datetimeField: FieldConfig = { raqbFieldType: 'datetime', icon: 'clock-nine' };
+const numberField: FieldConfig = { raqbFieldType: 'number', icon: 'calculator-alt' };
+const textField: FieldConfig = { raqbFieldType: 'text', icon: 'text' };
+
+// the data types of information_schema.columns without their parameters
+const ansiNumberTypes = [
+  'smallint',
+  'integer',
+  'int',
+  'bigint',
+  'numeric',
+  'decimal',
+  'real',
+  'float',
+  'double precision',
+];
+const postgresNumberTypes = [...ansiNumberTypes, 'money'];
+const mysqlNumberTypes = ['tinyint', 'smallint', 'mediumint', 'int', 'bigint', 'decimal', 'float', 'double', 'year'];
+const clickHouseWrapperRegex = /^(?:nullable|lowcardinality)\((.*)\)$/;
+
+// getFieldConfig maps the data type of a column to a query builder field type,
+// using the type names of the macro dialect of the data source.
+export function getFieldConfig(type: string, dialect: MacroDialect): FieldConfig {
+  const dataType = type.toLowerCase();
+  switch (dialect) {
+    case MacroDialect.clickhouse:
+      return getClickHouseFieldConfig(dataType);
+    case MacroDialect.mysql:
+      return getMySqlFieldConfig(dataType);
+    default:
+      return getAnsiFieldConfig(dataType, dialect === MacroDialect.postgres ? postgresNumberTypes : ansiNumberTypes);
+  }
+}
+
+function getAnsiFieldConfig(dataType: string, numberTypes: string[]): FieldConfig {
+  const name = dataType.replace(/\(.*\)/, '').trim();
+  if (name === 'boolean') {
+    return booleanField;
+  }
+  // time and timestamp types can have a time zone suffix
+  if (name === 'date' || name.startsWith('time')) {
+    return datetimeField;
+  }
+  if (numberTypes.includes(name)) {
+    return numberField;
+  }
+  return textField;
+}
+
+function getMySqlFieldConfig(dataType: string): FieldConfig {
+  const name = dataType.replace(/\(.*\)/, '').trim();
+  // MySQL has no boolean type, BOOL columns are tinyint(1)
+  if (name === 'bit') {
+    return booleanField;
+  }
+  if (['date', 'datetime', 'timestamp', 'time'].includes(name)) {
+    return datetimeField;
+  }
+  if (mysqlNumberTypes.includes(name.replace(/ unsigned$/, ''))) {
+    return numberField;
+  }
+  return textField;
+}
+
+function getClickHouseFieldConfig(dataType: string): FieldConfig {
+  // the wrapped type decides the field type
+  let name = dataType;
+  let wrapped = name.match(clickHouseWrapperRegex);
+  while (wrapped) {
+    name = wrapped[1];
+    wrapped = name.match(clickHouseWrapperRegex);
+  }
+  if (name === 'bool') {
+    return booleanField;
+  }
+  if (name.startsWith('date')) {
+    return datetimeField;
+  }
+  if (/^(u?int\d+|float\d+|decimal)/.test(name)) {
+    return numberField;
+  }
+  return textField;
+}
+
+export function toRawSql({ sql, table }: SQLQuery, dialect: MacroDialect): string {
+  let rawQuery = '';
+
+  // Return early with empty string if there is no sql column
+  if (!sql || !haveColumns(sql.columns)) {
+    return rawQuery;
+  }
+
+  rawQuery += createSelectClause(sql.columns);
+
+  if (table) {
+    rawQuery += `FROM ${table} `;
+  }
+
+  if (sql.whereString) {
+    rawQuery += `WHERE ${sql.whereString} `;
+  }
+
+  if (sql.groupBy?.[0]?.property.name) {
+    const groupBy = sql.groupBy.map((g) => g.property.name).filter((g) => !isEmpty(g));
+    rawQuery += `GROUP BY ${groupBy.join(', ')} `;
+  }
+
+  if (sql.orderBy?.property.name) {
+    rawQuery += `ORDER BY ${sql.orderBy.property.name} `;
+  }
+
+  if (sql.orderBy?.property.name && sql.orderByDirection) {
+    rawQuery += `${sql.orderByDirection} `;
+  }
+
+  // Altough LIMIT 0 doesn't make sense, it is still possible to have LIMIT 0
+  if (sql.limit !== undefined && sql.limit >= 0) {
+    // LIMIT is not part of ANSI SQL
+    rawQuery += dialect === MacroDialect.ansi ? `FETCH FIRST ${sql.limit} ROWS ONLY ` : `LIMIT ${sql.limit} `;
+  }
+  return rawQuery;
+}
@@ -0,0 +1,38 @@
+import { SQLOptions } from 'app/features/plugins/sql/types';
+
+export enum MacroDialect {
+  ansi = 'ansi',
+  postgres = 'postgres',
+  mysql = 'mysql',
+  clickhouse = 'clickhouse',
+}
+
+export interface GenericSqlOptions extends SQLOptions {
+  driver?: string;
+  macroDialect?: MacroDialect;
+}
+
+// getMacroDialect returns the macro dialect of the data source, chosen from the
+// driver name when none is selected like the backend does.
+export function getMacroDialect({
+  driver = '',
+  macroDialect,
+}: Pick<GenericSqlOptions, 'driver' | 'macroDialect'>): MacroDialect {
+  if (macroDialect) {
+    return macroDialect;
+  }
+  if (driver === 'pgx' || driver.startsWith('postgres')) {
+    return MacroDialect.postgres;
+  }
+  if (driver === 'mysql') {
+    return MacroDialect.mysql;
+  }
+  if (driver.startsWith('clickhouse')) {
+    return MacroDialect.clickhouse;
+  }
+  return MacroDialect.ansi;
+}
+
+export interface SecureJsonData {
+  dsn: string;
+}
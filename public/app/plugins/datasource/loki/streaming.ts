@@ -13,7 +13,8 @@ import { LokiQuery } from './types';
  * possible collisions
  */
 export async function getLiveStreamKey(query: LokiQuery): Promise<string> {
-  const str = JSON.stringify({ expr: query.expr });
+  // the line limit is sent to the Loki tail connection shared by the channel
+  const str = JSON.stringify({ expr: query.expr, maxLines: query.maxLines });
 
   const msgUint8 = new TextEncoder().encode(str); // encode as (utf-8) Uint8Array
   const hashBuffer = await crypto.subtle.digest('SHA-1', msgUint8); // hash the message
@@ -0,0 +1,14 @@
+import React from 'react';
+
+import { QueryEditorProps } from '@grafana/data';
+import { SqlQueryEditor } from 'app/features/plugins/sql/components/QueryEditor';
+import { SqlDatasource } from 'app/features/plugins/sql/datasource/SqlDatasource';
+import { SQLOptions, SQLQuery } from 'app/features/plugins/sql/types';
+
+const queryHeaderProps = { isDatasetSelectorHidden: true };
+
+// QueryEditor is the query editor of the SQL data sources without datasets,
+// the generic SQL data source uses it too.
+export function QueryEditor(props: QueryEditorProps<SqlDatasource, SQLQuery, SQLOptions>) {
+  return <SqlQueryEditor {...props} queryHeaderProps={queryHeaderProps} />;
+}
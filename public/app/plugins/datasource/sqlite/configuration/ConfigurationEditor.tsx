@@ -0,0 +1,66 @@
+import React from 'react';
+
+import { DataSourcePluginOptionsEditorProps, onUpdateDatasourceJsonDataOption } from '@grafana/data';
+import { Alert, FieldSet, InlineField, Input, Link } from '@grafana/ui';
+import { ConnectionLimits } from 'app/features/plugins/sql/components/configuration/ConnectionLimits';
+
+import { SqliteOptions } from '../types';
+
+export const SqliteConfigEditor = (props: DataSourcePluginOptionsEditorProps<SqliteOptions>) => {
+  const { options, onOptionsChange } = props;
+  const jsonData = options.jsonData;
+
+  const labelWidthConnection = 20;
+  const labelWidthShort = 20;
+
+  return (
+    <>
+      <FieldSet label="SQLite Connection" width={400}>
+        <InlineField
+          labelWidth={labelWidthConnection}
+          label="Path"
+          tooltip="Absolute path of the database file on the Grafana server"
+        >
+          <Input
+            width={40}
+            name="database"
+            value={jsonData.database || ''}
+            placeholder="/var/lib/data/metrics.db"
+            onChange={onUpdateDatasourceJsonDataOption(props, 'database')}
+          ></Input>
+        </InlineField>
+      </FieldSet>
+
+      <ConnectionLimits labelWidth={labelWidthShort} options={options} onOptionsChange={onOptionsChange} />
+
+      <FieldSet label="SQLite details">
+        <InlineField
+          tooltip={
+            <span>
+              A lower limit for the auto group by time interval. Recommended to be set to write frequency, for example
+              <code>1m</code> if your data is written every minute.
+            </span>
+          }
+          labelWidth={labelWidthShort}
+          label="Min time interval"
+        >
+          <Input
+            placeholder="1m"
+            value={jsonData.timeInterval || ''}
+            onChange={onUpdateDatasourceJsonDataOption(props, 'timeInterval')}
+          ></Input>
+        </InlineField>
+      </FieldSet>
+
+      <Alert title="Read-only access" severity="info">
+        The database file is opened read-only and only read-only statements can be queried. The file must be in a
+        directory allowed by the <code>sqlite_allowed_paths</code> setting of the Grafana server, and cannot be in the
+        Grafana data directory. Check out the{' '}
+        <Link rel="noreferrer" target="_blank" href="http://docs.grafana.org/features/datasources/sqlite/">
+          SQLite Data Source Docs
+        </Link>{' '}
+        for more information.
+      </Alert>
+    </>
+  );
+};
@@ -0,0 +1,13 @@
+import { DataSourcePlugin } from '@grafana/data';
+import { SQLQuery } from 'app/features/plugins/sql/types';
+
+import { CheatSheet } from './CheatSheet';
+import { QueryEditor } from './QueryEditor';
+import { SqliteConfigEditor } from './configuration/ConfigurationEditor';
+import { SqliteDatasource } from './datasource';
+import { SqliteOptions } from './types';
+
+export const plugin = new DataSourcePlugin<SqliteDatasource, SQLQuery, SqliteOptions>(SqliteDatasource)
+  .setQueryEditor(QueryEditor)
+  .setQueryEditorHelp(CheatSheet)
+  .setConfigEditor(SqliteConfigEditor);
@@ -0,0 +1,68 @@
+import { isEmpty } from 'lodash';
+
+import { RAQBFieldTypes, SQLQuery } from 'app/features/plugins/sql/types';
+import { createSelectClause, haveColumns } from 'app/features/plugins/sql/utils/sql.utils';
+
+// getFieldConfig maps the declared type of a column to a query builder field
+// type, using the type affinity rules of SQLite.
+export function getFieldConfig(type: string): { raqbFieldType: RAQBFieldTypes; icon: string } {
+  const declared = type.toLowerCase();
+  if (declared.includes('bool')) {
+    return { raqbFieldType: 'boolean', icon: 'toggle-off' };
+  }
+  if (declared.includes('date') || declared.includes('time')) {
+    return { raqbFieldType: 'datetime', icon: 'clock-nine' };
+  }
+  if (declared.includes('char') || declared.includes('clob') || declared.includes('text')) {
+    return { raqbFieldType: 'text', icon: 'text' };
+  }
+  if (
+    declared.includes('int') ||
+    declared.includes('real') ||
+    declared.includes('floa') ||
+    declared.includes('doub') ||
+    declared.includes('num') ||
+    declared.includes('dec')
+  ) {
+    return { raqbFieldType: 'number', icon: 'calculator-alt' };
+  }
+  return { raqbFieldType: 'text', icon: 'text' };
+}
+
+export function toRawSql({ sql, table }: SQLQuery): string {
+  let rawQuery = '';
+
+  // Return early with empty string if there is no sql column
+  if (!sql || !haveColumns(sql.columns)) {
+    return rawQuery;
+  }
+
+  rawQuery += createSelectClause(sql.columns);
+
+  if (table) {
+    rawQuery += `FROM ${table} `;
+  }
+
+  if (sql.whereString) {
+    rawQuery += `WHERE ${sql.whereString} `;
+  }
+
+  if (sql.groupBy?.[0]?.property.name) {
+    const groupBy = sql.groupBy.map((g) => g.property.name).filter((g) => !isEmpty(g));
+    rawQuery += `GROUP BY ${groupBy.join(', ')} `;
+  }
+
+  if (sql.orderBy?.property.name) {
+    rawQuery += `ORDER BY ${sql.orderBy.property.name} `;
+  }
+
+  if (sql.orderBy?.property.name && sql.orderByDirection) {
+    rawQuery += `${sql.orderByDirection} `;
+  }
+
+  // Altough LIMIT 0 doesn't make sense, it is still possible to have LIMIT 0
+  if (sql.limit !== undefined && sql.limit >= 0) {
+    rawQuery += `LIMIT ${sql.limit} `;
+  }
+  return rawQuery;
+}
@@ -0,0 +1,10 @@
+export function showTables() {
+  return `SELECT name AS "table" FROM sqlite_master
+    WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%'
+    ORDER BY name`;
+}
+
+export function getSchema(table?: string) {
+  return `SELECT name AS "column", lower(type) AS "type"
+    FROM pragma_table_info('${(table ?? '').replace(/'/g, "''")}')`;
+}
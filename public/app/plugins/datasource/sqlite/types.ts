@@ -0,0 +1,3 @@
+import { SQLOptions } from 'app/features/plugins/sql/types';
+
+export type SqliteOptions = SQLOptions;